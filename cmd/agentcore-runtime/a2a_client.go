@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A2A JSON-RPC values and the bridge event types shared across the HTTP,
+// SSE, and WebSocket bridges.
+const (
+	jsonrpcVersion      = "2.0"
+	methodMessageSend   = "message/send"
+	methodMessageStream = "message/stream"
+	roleUser            = "user"
+	// kindText is the A2A "text" part kind; it doubles as the "text" event
+	// type since the underlying string is identical.
+	kindText = "text"
+
+	// keyStatus and keyError are bridge event types (and the invocation
+	// error status).
+	keyStatus = "status"
+	keyError  = "error"
+)
+
+// JSON-RPC request IDs. The bridge sends one request per connection to the
+// loopback A2A server, so fixed IDs are enough to tell the bridges apart in
+// server logs.
+const (
+	rpcIDHTTP   = "http-bridge-1"
+	rpcIDStream = "http-bridge-stream-1"
+	rpcIDWS     = "ws-bridge-1"
+)
+
+// Message ID prefixes, followed by a nanosecond timestamp.
+const (
+	messageIDPrefixHTTP = "http"
+	messageIDPrefixWS   = "ws"
+)
+
+// defaultFailedMessage is reported for a failed task that carries no text.
+const defaultFailedMessage = "agent task failed"
+
+// a2aPart is a message or artifact part. The bridge only sends and reads
+// text parts; other part kinds decode with a nil Text and are skipped.
+type a2aPart struct {
+	Kind string  `json:"kind,omitempty"`
+	Text *string `json:"text,omitempty"`
+}
+
+// textPart returns a text part holding s.
+func textPart(s string) a2aPart {
+	return a2aPart{Kind: kindText, Text: &s}
+}
+
+// a2aMessage is an A2A message.
+type a2aMessage struct {
+	Role      string         `json:"role,omitempty"`
+	Parts     []a2aPart      `json:"parts"`
+	MessageID string         `json:"messageId,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+// a2aSendConfiguration controls how message/send handles the message.
+type a2aSendConfiguration struct {
+	Blocking bool `json:"blocking"`
+}
+
+// a2aSendParams is the params object of message/send and message/stream.
+type a2aSendParams struct {
+	Message       a2aMessage            `json:"message"`
+	Configuration *a2aSendConfiguration `json:"configuration,omitempty"`
+	ContextID     string                `json:"contextId,omitempty"`
+}
+
+// a2aRequest is a JSON-RPC request to the A2A server.
+type a2aRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      string        `json:"id"`
+	Method  string        `json:"method"`
+	Params  a2aSendParams `json:"params"`
+}
+
+// newA2ARequest returns a request that sends text as a user message.
+// sessionID maps to contextId for multi-turn conversation continuity and
+// metadata is forwarded as message-level metadata.
+func newA2ARequest(id, method, idPrefix, text, sessionID string, metadata map[string]any) *a2aRequest {
+	msg := a2aMessage{
+		Role:      roleUser,
+		Parts:     []a2aPart{textPart(text)},
+		MessageID: fmt.Sprintf("%s-%d", idPrefix, time.Now().UnixNano()),
+	}
+	if len(metadata) > 0 {
+		msg.Metadata = metadata
+	}
+	return &a2aRequest{
+		JSONRPC: jsonrpcVersion,
+		ID:      id,
+		Method:  method,
+		Params:  a2aSendParams{Message: msg, ContextID: sessionID},
+	}
+}
+
+// a2aRPCError is a JSON-RPC error object.
+type a2aRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// text returns the client-facing message for the error.
+func (e *a2aRPCError) text() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("a2a error %d", e.Code)
+}
+
+// a2aTaskStatus is the status of an A2A task.
+type a2aTaskStatus struct {
+	State   string      `json:"state"`
+	Message *a2aMessage `json:"message,omitempty"`
+}
+
+// failureText returns the first text part of the status message, or
+// defaultFailedMessage when there is none.
+func (s *a2aTaskStatus) failureText() string {
+	if s.Message != nil {
+		for _, p := range s.Message.Parts {
+			if p.Text != nil {
+				return *p.Text
+			}
+		}
+	}
+	return defaultFailedMessage
+}
+
+// a2aArtifact is an output produced by a task.
+type a2aArtifact struct {
+	Parts    []a2aPart      `json:"parts"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// text concatenates the artifact's text parts.
+func (a *a2aArtifact) text() string {
+	var sb strings.Builder
+	for _, p := range a.Parts {
+		if p.Text != nil {
+			sb.WriteString(*p.Text)
+		}
+	}
+	return sb.String()
+}
+
+// a2aTask is the result of a blocking message/send. Metadata values are
+// kept raw so an unexpected shape in one key cannot fail the whole decode.
+type a2aTask struct {
+	ID        string                     `json:"id"`
+	ContextID string                     `json:"contextId"`
+	Status    a2aTaskStatus              `json:"status"`
+	Artifacts []a2aArtifact              `json:"artifacts"`
+	Metadata  map[string]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// a2aResponse is the JSON-RPC response to message/send.
+type a2aResponse struct {
+	Result a2aTask      `json:"result"`
+	Error  *a2aRPCError `json:"error"`
+}
+
+// failure returns the client-facing error for a JSON-RPC error or a
+// failed task, and false when the response succeeded.
+func (r *a2aResponse) failure() (string, bool) {
+	if r.Error != nil {
+		return r.Error.text(), true
+	}
+	if r.Result.Status.State == stateFailed {
+		return r.Result.Status.failureText(), true
+	}
+	return "", false
+}
+
+// a2aStreamResponse is one JSON-RPC response carried in a message/stream
+// SSE data line.
+type a2aStreamResponse struct {
+	Result a2aStreamEvent `json:"result"`
+	Error  *a2aRPCError   `json:"error"`
+}
+
+// a2aStreamEvent is a status or artifact update. The event kind is
+// discriminated by which of Status and Artifact is present.
+type a2aStreamEvent struct {
+	TaskID    string         `json:"taskId"`
+	ContextID string         `json:"contextId"`
+	Status    *a2aTaskStatus `json:"status"`
+	Artifact  *a2aArtifact   `json:"artifact"`
+}
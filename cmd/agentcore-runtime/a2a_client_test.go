@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewA2ARequest_WireFormat(t *testing.T) {
+	send, err := buildA2ARequest("hi", "ctx-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := buildWSA2ARequest("hi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		JSONRPC string `json:"jsonrpc"`
+		Params  struct {
+			Message struct {
+				Role      string           `json:"role"`
+				MessageID string           `json:"messageId"`
+				Parts     []map[string]any `json:"parts"`
+			} `json:"message"`
+			Configuration map[string]any `json:"configuration"`
+			ContextID     *string        `json:"contextId"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(send, &got); err != nil {
+		t.Fatal(err)
+	}
+	msg := got.Params.Message
+	if got.JSONRPC != "2.0" || msg.Role != "user" || !strings.HasPrefix(msg.MessageID, "http-") {
+		t.Errorf("send request = %s", send)
+	}
+	if len(msg.Parts) != 1 || msg.Parts[0]["kind"] != "text" || msg.Parts[0]["text"] != "hi" {
+		t.Errorf("parts = %v, want one text part", msg.Parts)
+	}
+	if got.Params.Configuration["blocking"] != true {
+		t.Errorf("configuration = %v, want blocking", got.Params.Configuration)
+	}
+
+	got.Params.Configuration, got.Params.ContextID = nil, nil
+	if err := json.Unmarshal(stream, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Params.Configuration != nil || got.Params.ContextID != nil {
+		t.Errorf("stream request = %s, want no configuration or contextId", stream)
+	}
+	if !strings.HasPrefix(got.Params.Message.MessageID, "ws-") {
+		t.Errorf("messageId = %q, want ws- prefix", got.Params.Message.MessageID)
+	}
+}
+
+func TestA2AResponse_Decode(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFailed bool
+		wantMsg    string
+		wantText   string
+		wantUsage  *usageInfo
+	}{
+		{
+			name:     "completed with artifacts",
+			body:     `{"result":{"id":"t1","status":{"state":"completed"},"artifacts":[{"parts":[{"kind":"text","text":"a"},{"text":"b"}]},{"parts":[{"text":"c"}]}]}}`,
+			wantText: "abc",
+		},
+		{
+			name:     "non-text parts skipped",
+			body:     `{"result":{"status":{"state":"completed"},"artifacts":[{"parts":[{"kind":"data","data":{"x":1}},{"text":"ok"}]}]}}`,
+			wantText: "ok",
+		},
+		{
+			name: "completed without artifacts",
+			body: `{"result":{"status":{"state":"completed"}}}`,
+		},
+		{
+			name:     "unknown state and fields",
+			body:     `{"result":{"status":{"state":"input-required","timestamp":"2026-01-01T00:00:00Z"},"history":[],"kind":"task"}}`,
+			wantText: "",
+		},
+		{
+			name:       "failed with message",
+			body:       `{"result":{"status":{"state":"failed","message":{"role":"agent","parts":[{"text":"quota exceeded"}]}}}}`,
+			wantFailed: true, wantMsg: "quota exceeded",
+		},
+		{
+			name:       "failed with non-text message",
+			body:       `{"result":{"status":{"state":"failed","message":{"parts":[{"kind":"data","data":{}}]}}}}`,
+			wantFailed: true, wantMsg: defaultFailedMessage,
+		},
+		{
+			name:       "failed without message",
+			body:       `{"result":{"status":{"state":"failed"}}}`,
+			wantFailed: true, wantMsg: defaultFailedMessage,
+		},
+		{
+			name:       "rpc error",
+			body:       `{"error":{"code":-32600,"message":"bad request"}}`,
+			wantFailed: true, wantMsg: "bad request",
+		},
+		{
+			name:       "rpc error without message",
+			body:       `{"error":{"code":-32603}}`,
+			wantFailed: true, wantMsg: "a2a error -32603",
+		},
+		{
+			name:       "rpc error wins over result",
+			body:       `{"result":{"status":{"state":"completed"}},"error":{"code":1,"message":"boom"}}`,
+			wantFailed: true, wantMsg: "boom",
+		},
+		{
+			name:      "usage",
+			body:      `{"result":{"status":{"state":"completed"},"metadata":{"usage":{"input_tokens":7,"output_tokens":3}}}}`,
+			wantUsage: &usageInfo{InputTokens: 7, OutputTokens: 3},
+		},
+		{
+			name: "malformed usage ignored",
+			body: `{"result":{"status":{"state":"completed"},"metadata":{"usage":"lots","other":[1]}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp a2aResponse
+			if err := json.Unmarshal([]byte(tt.body), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			msg, failed := resp.failure()
+			if failed != tt.wantFailed || msg != tt.wantMsg {
+				t.Errorf("failure() = %q, %v, want %q, %v", msg, failed, tt.wantMsg, tt.wantFailed)
+			}
+			if got := extractArtifactText(&resp); got != tt.wantText {
+				t.Errorf("text = %q, want %q", got, tt.wantText)
+			}
+			got := extractUsage(&resp)
+			if (got == nil) != (tt.wantUsage == nil) || (got != nil && *got != *tt.wantUsage) {
+				t.Errorf("usage = %+v, want %+v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestParseA2ASSEEvent_Permutations(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *sseEvent
+	}{
+		{
+			name: "working status",
+			data: `{"result":{"taskId":"t","contextId":"c","status":{"state":"working"}}}`,
+			want: &sseEvent{Type: keyStatus, State: "working", TaskID: "t", ContextID: "c"},
+		},
+		{
+			name: "failed status with reason",
+			data: `{"result":{"taskId":"t","status":{"state":"failed","message":{"parts":[{"text":"nope"}]}}}}`,
+			want: &sseEvent{Type: keyStatus, State: stateFailed, Content: "nope", TaskID: "t"},
+		},
+		{
+			name: "failed status without message",
+			data: `{"result":{"taskId":"t","status":{"state":"failed"}}}`,
+			want: &sseEvent{Type: keyStatus, State: stateFailed, TaskID: "t"},
+		},
+		{
+			name: "non-failed status message not surfaced",
+			data: `{"result":{"taskId":"t","status":{"state":"working","message":{"parts":[{"text":"thinking"}]}}}}`,
+			want: &sseEvent{Type: keyStatus, State: "working", TaskID: "t"},
+		},
+		{
+			name: "task-shaped initial result",
+			data: `{"result":{"id":"t","kind":"task","status":{"state":"submitted"}}}`,
+			want: &sseEvent{Type: keyStatus, State: "submitted"},
+		},
+		{
+			name: "artifact text",
+			data: `{"result":{"taskId":"t","contextId":"c","artifact":{"parts":[{"text":"he"},{"text":"llo"}]}}}`,
+			want: &sseEvent{Type: kindText, Content: "hello", TaskID: "t", ContextID: "c"},
+		},
+		{
+			name: "artifact without text",
+			data: `{"result":{"taskId":"t","artifact":{"parts":[{"kind":"data","data":{}}]}}}`,
+		},
+		{
+			name: "empty artifact",
+			data: `{"result":{"taskId":"t","artifact":{"parts":[]}}}`,
+		},
+		{
+			name: "rpc error",
+			data: `{"error":{"code":-32000,"message":"overloaded"}}`,
+			want: &sseEvent{Type: keyError, Content: "overloaded"},
+		},
+		{
+			name: "rpc error without message",
+			data: `{"error":{"code":-32000}}`,
+			want: &sseEvent{Type: keyError, Content: "a2a error -32000"},
+		},
+		{
+			name: "neither status nor artifact",
+			data: `{"result":{"kind":"message","parts":[{"text":"hi"}]}}`,
+		},
+		{name: "invalid json", data: `{"result":`},
+		{name: "non-object result", data: `{"result":"done"}`},
+	}
+	b := &httpBridge{log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.parseA2ASSEEvent(tt.data)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("parseA2ASSEEvent = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Redaction modes for user content (prompts, bodies, responses) in logs.
+const (
+	redactHash     = "hash"     // log a short SHA-256 digest and the length
+	redactTruncate = "truncate" // log a short prefix and the length
+	redactNone     = "none"     // log content verbatim
+)
+
+// redactTruncateLen is the number of runes kept by the truncate mode.
+const redactTruncateLen = 32
+
+// redactHashBytes is the number of digest bytes kept by the hash mode.
+const redactHashBytes = 8
+
+// defaultLogSampleRate logs every request.
+const defaultLogSampleRate = 1.0
+
+// redact renders user content for logging according to the bridge's
+// redaction mode. The zero value hashes, so raw text is never logged unless
+// explicitly configured.
+func (b *httpBridge) redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	switch b.redaction {
+	case redactNone:
+		return s
+	case redactTruncate:
+		if utf8.RuneCountInString(s) <= redactTruncateLen {
+			return s
+		}
+		runes := []rune(s)
+		return fmt.Sprintf("%s… (%d bytes)", string(runes[:redactTruncateLen]), len(s))
+	default:
+		sum := sha256.Sum256([]byte(s))
+		return fmt.Sprintf("sha256:%s (%d bytes)", hex.EncodeToString(sum[:redactHashBytes]), len(s))
+	}
+}
+
+// accessLogEntry collects per-request details that handlers learn while
+// serving (task ID, token usage) for the access log line.
+type accessLogEntry struct {
+	mu           sync.Mutex
+	start        time.Time
+	taskID       string
+	inputTokens  int
+	outputTokens int
+	metadata     map[string]any
+
+	// releaseTurn drops the session turn admitted for the request.
+	releaseTurn func()
+}
+
+// accessLogKey is the context key for the request's accessLogEntry.
+type accessLogKey struct{}
+
+// accessLogFrom returns the request's access log entry, or nil outside the
+// access log middleware. All setters are nil-safe.
+func accessLogFrom(ctx context.Context) *accessLogEntry {
+	e, _ := ctx.Value(accessLogKey{}).(*accessLogEntry)
+	return e
+}
+
+// receivedAt returns when the bridge received the request, or fallback
+// outside the access log middleware.
+func (e *accessLogEntry) receivedAt(fallback time.Time) time.Time {
+	if e == nil || e.start.IsZero() {
+		return fallback
+	}
+	return e.start
+}
+
+// setTask records the A2A task ID served by the request.
+func (e *accessLogEntry) setTask(taskID string) {
+	if e == nil || taskID == "" {
+		return
+	}
+	e.mu.Lock()
+	e.taskID = taskID
+	e.mu.Unlock()
+}
+
+// setUsage records token usage reported by the agent.
+func (e *accessLogEntry) setUsage(u *usageInfo) {
+	if e == nil || u == nil {
+		return
+	}
+	e.mu.Lock()
+	e.inputTokens += u.InputTokens
+	e.outputTokens += u.OutputTokens
+	e.mu.Unlock()
+}
+
+// setMetadata records the invocation metadata for the post-invoke webhook.
+func (e *accessLogEntry) setMetadata(md map[string]any) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.metadata = md
+	e.mu.Unlock()
+}
+
+// holdSessionTurn keeps the session turn reservation release until the
+// request's turn is recorded. Outside the access log middleware, which
+// records turns, it releases the reservation at once.
+func (e *accessLogEntry) holdSessionTurn(release func()) {
+	if e == nil {
+		release()
+		return
+	}
+	e.mu.Lock()
+	e.releaseTurn = release
+	e.mu.Unlock()
+}
+
+// releaseSessionTurn drops the request's session turn reservation, if any.
+func (e *accessLogEntry) releaseSessionTurn() {
+	e.mu.Lock()
+	release := e.releaseTurn
+	e.releaseTurn = nil
+	e.mu.Unlock()
+	if release != nil {
+		release()
+	}
+}
+
+// statusRecorder captures the response status code while passing through
+// the streaming (Flusher) and WebSocket (Hijacker) interfaces.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code.
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Flush implements http.Flusher for SSE responses.
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for WebSocket upgrades.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	s.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// withAccessLog wraps next with structured per-request access logging.
+// Health checks are not logged; server errors are always logged; other
+// requests are logged at the configured sample rate.
+func (b *httpBridge) withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == pingPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		entry := &accessLogEntry{start: start}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, entry)))
+		if r.URL.Path == invocationsPath {
+			ev := entry.webhookEvent(r, rec.status, time.Since(start))
+			b.webhooks.postInvoke(ev)
+			if rec.status < http.StatusBadRequest {
+				b.sessions.complete(ev.SessionID, ev.TaskID,
+					usageInfo{InputTokens: ev.InputTokens, OutputTokens: ev.OutputTokens})
+			}
+			entry.releaseSessionTurn()
+		}
+
+		if !b.sampleAccessLog(rec.status) {
+			return
+		}
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+		b.log.Info("access",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"session_id", r.Header.Get(sessionHeader),
+			"task_id", entry.taskID,
+			"input_tokens", entry.inputTokens,
+			"output_tokens", entry.outputTokens,
+			"sample_rate", b.logSampleRate)
+	})
+}
+
+// sampleAccessLog decides whether a request's access log line is emitted.
+func (b *httpBridge) sampleAccessLog(status int) bool {
+	if status >= http.StatusInternalServerError || b.logSampleRate >= 1 {
+		return true
+	}
+	if b.logSampleRate <= 0 {
+		return false
+	}
+	return rand.Float64() < b.logSampleRate //nolint:gosec // G404: log sampling, not security sensitive.
+}
+
+// webhookEvent builds the post-invoke webhook event for a served request.
+func (e *accessLogEntry) webhookEvent(r *http.Request, status int, latency time.Duration) *webhookEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return &webhookEvent{
+		Timestamp:    time.Now().UTC(),
+		Path:         r.URL.Path,
+		SessionID:    r.Header.Get(sessionHeader),
+		TaskID:       e.taskID,
+		Status:       status,
+		LatencyMS:    latency.Milliseconds(),
+		InputTokens:  e.inputTokens,
+		OutputTokens: e.outputTokens,
+		Metadata:     e.metadata,
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedact_Modes(t *testing.T) {
+	long := strings.Repeat("secret ", 10)
+
+	hashed := (&httpBridge{}).redact(long)
+	if strings.Contains(hashed, "secret") {
+		t.Errorf("hash mode leaked content: %q", hashed)
+	}
+	if !strings.HasPrefix(hashed, "sha256:") {
+		t.Errorf("hash mode = %q, want sha256 prefix", hashed)
+	}
+	if hashed != (&httpBridge{redaction: redactHash}).redact(long) {
+		t.Error("default mode should match explicit hash mode")
+	}
+
+	truncated := (&httpBridge{redaction: redactTruncate}).redact(long)
+	if !strings.HasPrefix(truncated, long[:redactTruncateLen]) {
+		t.Errorf("truncate mode = %q, want %d-rune prefix", truncated, redactTruncateLen)
+	}
+	if strings.Contains(truncated, long) {
+		t.Errorf("truncate mode kept full content: %q", truncated)
+	}
+	if got := (&httpBridge{redaction: redactTruncate}).redact("short"); got != "short" {
+		t.Errorf("truncate(short) = %q, want unchanged", got)
+	}
+
+	if got := (&httpBridge{redaction: redactNone}).redact(long); got != long {
+		t.Errorf("none mode = %q, want verbatim", got)
+	}
+	if got := (&httpBridge{}).redact(""); got != "" {
+		t.Errorf("redact(\"\") = %q, want empty", got)
+	}
+}
+
+func TestSampleAccessLog(t *testing.T) {
+	if !(&httpBridge{logSampleRate: 1}).sampleAccessLog(http.StatusOK) {
+		t.Error("rate 1 should always log")
+	}
+	if (&httpBridge{logSampleRate: 0}).sampleAccessLog(http.StatusOK) {
+		t.Error("rate 0 should not log successes")
+	}
+	if !(&httpBridge{logSampleRate: 0}).sampleAccessLog(http.StatusBadGateway) {
+		t.Error("server errors should always be logged")
+	}
+}
+
+// accessLogLines returns the decoded "access" records written to buf.
+func accessLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if raw == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			t.Fatalf("decode log line %q: %v", raw, err)
+		}
+		if rec["msg"] == "access" {
+			lines = append(lines, rec)
+		}
+	}
+	return lines
+}
+
+func TestWithAccessLog_RecordsTaskAndUsage(t *testing.T) {
+	mock := newMockA2AServer(t)
+	var buf bytes.Buffer
+	b := &httpBridge{
+		a2aPort:       mock.port(t),
+		log:           slog.New(slog.NewJSONHandler(&buf, nil)),
+		logSampleRate: 1,
+	}
+	h := b.withAccessLog(http.HandlerFunc(b.handleInvocation))
+
+	req := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"my secret"}`))
+	req.Header.Set(sessionHeader, "sess-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	lines := accessLogLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 access log line, got %d", len(lines))
+	}
+	line := lines[0]
+	if line["status"] != float64(http.StatusOK) {
+		t.Errorf("status = %v, want 200", line["status"])
+	}
+	if line["session_id"] != "sess-1" {
+		t.Errorf("session_id = %v, want sess-1", line["session_id"])
+	}
+	if line["task_id"] != "task-001" {
+		t.Errorf("task_id = %v, want task-001", line["task_id"])
+	}
+	if line["input_tokens"] != float64(10) || line["output_tokens"] != float64(20) {
+		t.Errorf("tokens = %v/%v, want 10/20", line["input_tokens"], line["output_tokens"])
+	}
+	if strings.Contains(buf.String(), "my secret") {
+		t.Error("raw prompt leaked into logs")
+	}
+}
+
+func TestWithAccessLog_SkipsPingAndSamples(t *testing.T) {
+	var buf bytes.Buffer
+	b := &httpBridge{log: slog.New(slog.NewJSONHandler(&buf, nil)), logSampleRate: 0}
+	h := b.withAccessLog(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, pingPath, nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, invocationsPath, nil))
+
+	if lines := accessLogLines(t, &buf); len(lines) != 0 {
+		t.Errorf("expected no access logs, got %v", lines)
+	}
+}
+
+func TestStatusRecorder_PassThrough(t *testing.T) {
+	inner := httptest.NewRecorder()
+	rec := &statusRecorder{ResponseWriter: inner, status: http.StatusOK}
+
+	rec.WriteHeader(http.StatusTeapot)
+	rec.Flush()
+	if rec.status != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", rec.status, http.StatusTeapot)
+	}
+	if !inner.Flushed {
+		t.Error("Flush not passed through")
+	}
+	if rec.Unwrap() != inner {
+		t.Error("Unwrap should return the inner writer")
+	}
+	if _, _, err := rec.Hijack(); err == nil {
+		t.Error("expected error hijacking a recorder")
+	}
+}
+
+func TestAccessLogEntry_NilSafe(t *testing.T) {
+	var e *accessLogEntry
+	e.setTask("t1")
+	e.setUsage(&usageInfo{InputTokens: 1})
+}
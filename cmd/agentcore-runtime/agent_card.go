@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// Invocation paths advertised on the agent card.
+const (
+	a2aPath       = "/a2a"
+	wsPath        = "/ws"
+	agentCardPath = "/.well-known/agent.json"
+)
+
+// Protocol bindings used in the agent card's supportedInterfaces.
+const (
+	bindingJSONRPC   = "JSONRPC"
+	bindingHTTPJSON  = "HTTP+JSON"
+	bindingSSE       = "SSE"
+	bindingWebSocket = "WEBSOCKET"
+)
+
+// Agent card extension URIs describing runtime-specific capabilities.
+// External orchestrators can match on these to discover how a deployed
+// runtime may be invoked without parsing the deploy config.
+const (
+	extURIPrefix   = "urn:promptarena:agentcore:"
+	extURIProtocol = extURIPrefix + "protocol:"
+	extURIAuth     = extURIPrefix + "auth:"
+	extURIRuntime  = extURIPrefix + "runtime"
+	extURIModel    = extURIPrefix + "model"
+	extURIBridge   = extURIPrefix + "bridge"
+)
+
+// toolSkillPrefix prefixes the ID of skills generated from a prompt's tools
+// so they cannot collide with the agent's own skill ID.
+const toolSkillPrefix = "tool:"
+
+// toolSkillTag tags every skill generated from a tool.
+const toolSkillTag = "tool"
+
+// Protocol names advertised alongside the protocol mode values.
+const (
+	protocolSSE = "sse"
+	protocolWS  = "ws"
+)
+
+// applyRuntimeCapabilities advertises the protocols, invocation paths, auth
+// requirements, and streaming support of this runtime on the agent card.
+// Interface URLs are paths relative to the runtime host: the A2A interface
+// is served on the configured A2A port, bridge interfaces on port 8080.
+func applyRuntimeCapabilities(card *a2a.AgentCard, cfg *runtimeConfig) {
+	var interfaces []a2a.AgentInterface
+	var protocols []string
+
+	if cfg.wantA2AServer() {
+		interfaces = append(interfaces, a2a.AgentInterface{URL: a2aPath, ProtocolBinding: bindingJSONRPC})
+		protocols = append(protocols, protocolA2A)
+	}
+	if cfg.wantHTTPBridge() {
+		interfaces = append(interfaces,
+			a2a.AgentInterface{URL: invocationsPath, ProtocolBinding: bindingHTTPJSON},
+			a2a.AgentInterface{URL: invocationsPath, ProtocolBinding: bindingSSE},
+			a2a.AgentInterface{URL: wsPath, ProtocolBinding: bindingWebSocket},
+		)
+		protocols = append(protocols, protocolHTTP, protocolSSE, protocolWS)
+	}
+
+	card.SupportedInterfaces = interfaces
+	// Every enabled protocol supports incremental output (A2A message/stream,
+	// SSE, or WebSocket), so streaming is always advertised.
+	card.Capabilities.Streaming = len(interfaces) > 0
+
+	for _, p := range protocols {
+		card.Capabilities.Extensions = append(card.Capabilities.Extensions, a2a.AgentExtension{
+			URI:         extURIProtocol + p,
+			Description: "runtime accepts " + p + " invocations",
+		})
+	}
+	if cfg.A2AAuthMode != "" {
+		card.Capabilities.Extensions = append(card.Capabilities.Extensions, a2a.AgentExtension{
+			URI:         extURIAuth + cfg.A2AAuthMode,
+			Description: "callers must authenticate with " + cfg.A2AAuthMode,
+			Required:    true,
+		})
+	}
+	if cfg.wantHTTPBridge() {
+		card.Capabilities.Extensions = append(card.Capabilities.Extensions, a2a.AgentExtension{
+			URI:         extURIBridge,
+			Description: "HTTP bridge endpoints are summarized at " + capabilitiesPath,
+		})
+	}
+	card.Capabilities.Extensions = append(card.Capabilities.Extensions, a2a.AgentExtension{
+		URI:         extURIRuntime,
+		Description: "agentcore-runtime " + version,
+	})
+}
+
+// enrichAgentCard fills the card from pack metadata the generic generator
+// ignores: the pack description, pack tags, variable examples as sample
+// inputs, one skill per allowed tool, and the model provider. Deploy-time
+// overrides are applied last so they always win.
+func enrichAgentCard(card *a2a.AgentCard, pack *prompt.Pack, agentName string, cfg *runtimeConfig) {
+	if card.Description == "" {
+		card.Description = pack.Description
+	}
+
+	skill := agentSkill(card, agentName)
+	// The generator shares the pack's tag slice; clone before appending.
+	skill.Tags = slices.Clone(skill.Tags)
+	if pack.Metadata != nil {
+		for _, tag := range pack.Metadata.Tags {
+			if !slices.Contains(skill.Tags, tag) {
+				skill.Tags = append(skill.Tags, tag)
+			}
+		}
+	}
+	if p := pack.Prompts[agentName]; p != nil {
+		skill.Examples = append(skill.Examples, variableExamples(p.Variables)...)
+		card.Skills = append(card.Skills, toolSkills(pack, allowedTools(p.Tools, cfg.AllowedTools))...)
+	}
+
+	if cfg.ProviderType != "" || cfg.Model != "" {
+		card.Capabilities.Extensions = append(card.Capabilities.Extensions, a2a.AgentExtension{
+			URI:         extURIModel,
+			Description: strings.TrimSpace(cfg.ProviderType + " " + cfg.Model),
+		})
+	}
+
+	applyCardOverrides(card, cfg.AgentCard)
+}
+
+// agentSkill returns the card's skill for agentName, adding one built from
+// the card's own name and description if the generator produced none.
+func agentSkill(card *a2a.AgentCard, agentName string) *a2a.AgentSkill {
+	for i := range card.Skills {
+		if card.Skills[i].ID == agentName {
+			return &card.Skills[i]
+		}
+	}
+	card.Skills = append(card.Skills, a2a.AgentSkill{
+		ID:          agentName,
+		Name:        card.Name,
+		Description: card.Description,
+	})
+	return &card.Skills[len(card.Skills)-1]
+}
+
+// variableExamples renders each prompt variable's example value as a sample
+// input of the form "name: value".
+func variableExamples(vars []prompt.VariableMetadata) []string {
+	var examples []string
+	for _, v := range vars {
+		if v.Example == nil {
+			continue
+		}
+		value, ok := v.Example.(string)
+		if !ok {
+			b, err := json.Marshal(v.Example)
+			if err != nil {
+				continue
+			}
+			value = string(b)
+		}
+		examples = append(examples, fmt.Sprintf("%s: %s", v.Name, value))
+	}
+	return examples
+}
+
+// allowedTools returns the prompt's tools that are in allowed, or all of
+// them when allowed is empty.
+func allowedTools(tools, allowed []string) []string {
+	if len(allowed) == 0 {
+		return tools
+	}
+	var kept []string
+	for _, tool := range tools {
+		if slices.Contains(allowed, tool) {
+			kept = append(kept, tool)
+		}
+	}
+	return kept
+}
+
+// toolSkills returns one skill per tool the prompt may call, described by
+// the pack's tool definition when there is one.
+func toolSkills(pack *prompt.Pack, tools []string) []a2a.AgentSkill {
+	skills := make([]a2a.AgentSkill, 0, len(tools))
+	for _, name := range tools {
+		skill := a2a.AgentSkill{ID: toolSkillPrefix + name, Name: name, Tags: []string{toolSkillTag}}
+		if t := pack.Tools[name]; t != nil {
+			skill.Description = t.Description
+		}
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
+// applyCardOverrides replaces pack-derived card fields with the non-empty
+// values configured for this agent at deploy time.
+func applyCardOverrides(card *a2a.AgentCard, o *agentcore.AgentCardConfig) {
+	if o == nil {
+		return
+	}
+	if o.DisplayName != "" {
+		card.Name = o.DisplayName
+	}
+	if o.Description != "" {
+		card.Description = o.Description
+	}
+	if o.IconURL != "" {
+		card.IconURL = o.IconURL
+	}
+	if o.DocumentationURL != "" {
+		card.DocumentationURL = o.DocumentationURL
+	}
+	if o.ProviderOrganization != "" {
+		card.Provider = &a2a.AgentProvider{Organization: o.ProviderOrganization, URL: o.ProviderURL}
+	}
+}
+
+// handleAgentCard serves the agent card on the HTTP bridge. It is only
+// registered when the A2A server (which normally serves the card) is skipped.
+func (b *httpBridge) handleAgentCard(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(b.card)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+func hasExtension(card *a2a.AgentCard, uri string) bool {
+	for _, ext := range card.Capabilities.Extensions {
+		if ext.URI == uri {
+			return true
+		}
+	}
+	return false
+}
+
+func TestApplyRuntimeCapabilities_Both(t *testing.T) {
+	card := &a2a.AgentCard{Name: "agent"}
+	applyRuntimeCapabilities(card, &runtimeConfig{})
+
+	if !card.Capabilities.Streaming {
+		t.Error("expected streaming capability")
+	}
+	if len(card.SupportedInterfaces) != 4 {
+		t.Fatalf("expected 4 interfaces, got %d", len(card.SupportedInterfaces))
+	}
+	if card.SupportedInterfaces[0].URL != a2aPath {
+		t.Errorf("first interface URL = %q, want %q", card.SupportedInterfaces[0].URL, a2aPath)
+	}
+	for _, p := range []string{protocolA2A, protocolHTTP, protocolSSE, protocolWS} {
+		if !hasExtension(card, extURIProtocol+p) {
+			t.Errorf("missing protocol extension for %q", p)
+		}
+	}
+	if !hasExtension(card, extURIRuntime) {
+		t.Error("missing runtime extension")
+	}
+}
+
+func TestApplyRuntimeCapabilities_HTTPOnly(t *testing.T) {
+	card := &a2a.AgentCard{Name: "agent"}
+	applyRuntimeCapabilities(card, &runtimeConfig{Protocol: protocolHTTP})
+
+	for _, iface := range card.SupportedInterfaces {
+		if iface.ProtocolBinding == bindingJSONRPC {
+			t.Error("a2a interface advertised in http-only mode")
+		}
+	}
+	if hasExtension(card, extURIProtocol+protocolA2A) {
+		t.Error("a2a protocol extension advertised in http-only mode")
+	}
+	if !hasExtension(card, extURIBridge) {
+		t.Error("missing bridge extension in http-only mode")
+	}
+}
+
+func TestApplyRuntimeCapabilities_A2AOnly(t *testing.T) {
+	card := &a2a.AgentCard{Name: "agent"}
+	applyRuntimeCapabilities(card, &runtimeConfig{Protocol: protocolA2A})
+
+	if len(card.SupportedInterfaces) != 1 {
+		t.Fatalf("expected 1 interface, got %d", len(card.SupportedInterfaces))
+	}
+	if card.SupportedInterfaces[0].ProtocolBinding != bindingJSONRPC {
+		t.Errorf("binding = %q, want %q", card.SupportedInterfaces[0].ProtocolBinding, bindingJSONRPC)
+	}
+	if hasExtension(card, extURIBridge) {
+		t.Error("bridge extension advertised in a2a-only mode")
+	}
+}
+
+func TestApplyRuntimeCapabilities_Auth(t *testing.T) {
+	card := &a2a.AgentCard{Name: "agent"}
+	applyRuntimeCapabilities(card, &runtimeConfig{A2AAuthMode: "iam"})
+
+	for _, ext := range card.Capabilities.Extensions {
+		if ext.URI == extURIAuth+"iam" {
+			if !ext.Required {
+				t.Error("auth extension should be required")
+			}
+			return
+		}
+	}
+	t.Error("missing auth extension")
+}
+
+func TestBuildAgentCard_PackVersionOnGeneratedCard(t *testing.T) {
+	pack := &prompt.Pack{
+		Version: "3.1.0",
+		Agents: &prompt.AgentsConfig{
+			Entry:   "myagent",
+			Members: map[string]*prompt.AgentDef{"myagent": {Description: "d"}},
+		},
+		Prompts: map[string]*prompt.PackPrompt{"myagent": {Name: "myagent"}},
+	}
+
+	card := buildAgentCard(pack, "myagent", &runtimeConfig{})
+	if card.Version != "3.1.0" {
+		t.Errorf("card.Version = %q, want %q", card.Version, "3.1.0")
+	}
+	if len(card.SupportedInterfaces) == 0 {
+		t.Error("expected supported interfaces on card")
+	}
+}
+
+func enrichmentTestPack() *prompt.Pack {
+	return &prompt.Pack{
+		Version:     "1.2.0",
+		Description: "Customer support pack",
+		Metadata:    &prompt.Metadata{Tags: []string{"support", "billing"}},
+		Tools: map[string]*prompt.PackTool{
+			"lookup_order": {Name: "lookup_order", Description: "Finds an order by ID"},
+		},
+		Agents: &prompt.AgentsConfig{
+			Entry:   "support",
+			Members: map[string]*prompt.AgentDef{"support": {Tags: []string{"support"}}},
+		},
+		Prompts: map[string]*prompt.PackPrompt{
+			"support": {
+				Name:  "Support",
+				Tools: []string{"lookup_order", "refund"},
+				Variables: []prompt.VariableMetadata{
+					{Name: "question", Example: "Where is my order?"},
+					{Name: "order_id", Example: 1234},
+					{Name: "tone"},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildAgentCard_EnrichedFromPack(t *testing.T) {
+	pack := enrichmentTestPack()
+	card := buildAgentCard(pack, "support", &runtimeConfig{ProviderType: "bedrock", Model: "claude"})
+
+	if card.Description != "Customer support pack" {
+		t.Errorf("Description = %q, want pack description", card.Description)
+	}
+	if len(card.Skills) != 3 {
+		t.Fatalf("skills = %+v, want agent skill plus two tool skills", card.Skills)
+	}
+	agent := card.Skills[0]
+	if strings.Join(agent.Tags, ",") != "support,billing" {
+		t.Errorf("agent skill tags = %v", agent.Tags)
+	}
+	if strings.Join(agent.Examples, "|") != "question: Where is my order?|order_id: 1234" {
+		t.Errorf("agent skill examples = %v", agent.Examples)
+	}
+	if card.Skills[1].ID != "tool:lookup_order" || card.Skills[1].Description != "Finds an order by ID" {
+		t.Errorf("tool skill = %+v", card.Skills[1])
+	}
+	if card.Skills[2].ID != "tool:refund" || card.Skills[2].Description != "" {
+		t.Errorf("undefined tool skill = %+v", card.Skills[2])
+	}
+	if !hasExtension(card, extURIModel) {
+		t.Error("missing model extension")
+	}
+	if len(pack.Agents.Members["support"].Tags) != 1 {
+		t.Errorf("pack agent tags mutated: %v", pack.Agents.Members["support"].Tags)
+	}
+}
+
+func TestBuildAgentCard_AllowedTools(t *testing.T) {
+	card := buildAgentCard(enrichmentTestPack(), "support", &runtimeConfig{AllowedTools: []string{"refund"}})
+	if len(card.Skills) != 2 || card.Skills[1].ID != "tool:refund" {
+		t.Errorf("skills = %+v, want the agent skill and the allowed tool only", card.Skills)
+	}
+}
+
+func TestBuildAgentCard_Overrides(t *testing.T) {
+	cfg := &runtimeConfig{AgentCard: &agentcore.AgentCardConfig{
+		DisplayName:          "Acme Support",
+		Description:          "Public description",
+		IconURL:              "https://acme.example/icon.png",
+		DocumentationURL:     "https://acme.example/docs",
+		ProviderOrganization: "Acme",
+		ProviderURL:          "https://acme.example",
+	}}
+	card := buildAgentCard(enrichmentTestPack(), "support", cfg)
+
+	if card.Name != "Acme Support" || card.Description != "Public description" {
+		t.Errorf("name/description = %q/%q", card.Name, card.Description)
+	}
+	if card.IconURL != "https://acme.example/icon.png" || card.DocumentationURL != "https://acme.example/docs" {
+		t.Errorf("icon/docs = %q/%q", card.IconURL, card.DocumentationURL)
+	}
+	if card.Provider == nil || card.Provider.Organization != "Acme" || card.Provider.URL != "https://acme.example" {
+		t.Errorf("provider = %+v", card.Provider)
+	}
+}
+
+func TestBuildAgentCard_FallbackGetsSkill(t *testing.T) {
+	pack := &prompt.Pack{Prompts: map[string]*prompt.PackPrompt{"chat": {Description: "chats"}}}
+	card := buildAgentCard(pack, "chat", &runtimeConfig{})
+	if len(card.Skills) != 1 || card.Skills[0].ID != "chat" || card.Skills[0].Description != "chats" {
+		t.Errorf("skills = %+v", card.Skills)
+	}
+}
+
+func TestHandleAgentCard(t *testing.T) {
+	card := &a2a.AgentCard{Name: "agent", Version: "1.0.0"}
+	b := &httpBridge{log: slog.Default(), card: card}
+
+	rec := httptest.NewRecorder()
+	b.handleAgentCard(rec, httptest.NewRequest(http.MethodGet, agentCardPath, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got a2a.AgentCard
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Name != "agent" || got.Version != "1.0.0" {
+		t.Errorf("card = %+v, want name=agent version=1.0.0", got)
+	}
+}
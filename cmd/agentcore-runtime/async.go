@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// stateWorking is the A2A task state reported while an async invocation
+// runs.
+const stateWorking = "working"
+
+// Async invocation limits.
+const (
+	// asyncTaskRetention is how long a finished task's result stays
+	// available to GET /invocations/{taskId}.
+	asyncTaskRetention = 15 * time.Minute
+	// maxRunningAsyncTasks caps the async invocations running at once,
+	// also when no invocation concurrency cap is configured. Each task
+	// keeps its invocation's limiter slots until it finishes.
+	maxRunningAsyncTasks = 64
+	// pushNotificationTimeout bounds each push notification call.
+	pushNotificationTimeout = 10 * time.Second
+)
+
+// asyncTaskIDPrefix marks task handles issued by the bridge, which are
+// not A2A task IDs.
+const asyncTaskIDPrefix = "async-"
+
+// pushTokenHeader carries the push notification token, as in the A2A
+// push notification spec.
+const pushTokenHeader = "X-A2A-Notification-Token"
+
+// msgTooManyAsyncTasks rejects an async invocation when the cap is hit.
+const msgTooManyAsyncTasks = "too many running async invocations"
+
+// pushNotificationConfig is the callback an async invocation reports its
+// result to, mirroring A2A's PushNotificationConfig.
+type pushNotificationConfig struct {
+	URL   string `json:"url"`
+	Token string `json:"token,omitempty"`
+}
+
+// errPushAddressBlocked refuses a push notification to an address inside
+// the runtime's own network.
+var errPushAddressBlocked = errors.New("push notifications may not target loopback, link-local or private addresses")
+
+// validate checks that the callback URL is an absolute http(s) URL, and
+// that a host not in allowedHosts is not localhost or an internal IP.
+// Hosts that resolve to internal IPs are refused when the push is sent.
+func (c *pushNotificationConfig) validate(allowedHosts map[string]bool) error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("push_notification.url %q must be an absolute http or https URL", c.URL)
+	}
+	host := strings.ToLower(u.Hostname())
+	if allowedHosts[host] {
+		return nil
+	}
+	if ip := net.ParseIP(host); host == "localhost" || strings.HasSuffix(host, ".localhost") || blockedPushIP(ip) {
+		return fmt.Errorf("push_notification.url %q: %w", c.URL, errPushAddressBlocked)
+	}
+	return nil
+}
+
+// blockedPushIP reports whether ip lies in the runtime's own network:
+// loopback, link-local (which holds the instance metadata endpoint),
+// private, or unspecified.
+func blockedPushIP(ip net.IP) bool {
+	return ip != nil && (ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified())
+}
+
+// newPushClient returns the client push notifications are sent with. It
+// connects directly, not through a proxy, and refuses to connect to a
+// blocked IP unless the URL's host is in allowedHosts, so that neither
+// DNS nor a redirect can point a callback at the metadata endpoint or
+// another internal service.
+func newPushClient(allowedHosts map[string]bool) *http.Client {
+	guarded := &net.Dialer{Control: func(_, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if blockedPushIP(net.ParseIP(host)) {
+			return errPushAddressBlocked
+		}
+		return nil
+	}}
+	direct := &net.Dialer{}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && allowedHosts[strings.ToLower(host)] {
+			return direct.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+	return &http.Client{Timeout: pushNotificationTimeout, Transport: transport}
+}
+
+// asyncTask is one async invocation.
+type asyncTask struct {
+	resp     invocationResponse
+	done     bool
+	finished time.Time
+}
+
+// asyncTaskStore runs async invocations in the background and keeps their
+// results for polling. Finished tasks are dropped after
+// asyncTaskRetention.
+type asyncTaskStore struct {
+	log    *slog.Logger
+	health *healthHandler
+	client *http.Client
+	// allowedHosts are callback hosts exempt from the internal address
+	// check, lowercased.
+	allowedHosts map[string]bool
+	now          func() time.Time // nil uses time.Now
+
+	mu      sync.Mutex
+	tasks   map[string]*asyncTask
+	running int
+	wg      sync.WaitGroup
+}
+
+// newAsyncTaskStore creates an empty store. Running tasks are reported to
+// health so /ping shows the runtime as busy. Push notifications may reach
+// internal addresses only at allowedHosts.
+func newAsyncTaskStore(log *slog.Logger, health *healthHandler, allowedHosts []string) *asyncTaskStore {
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		allowed[strings.ToLower(host)] = true
+	}
+	return &asyncTaskStore{
+		log:          log,
+		health:       health,
+		client:       newPushClient(allowed),
+		allowedHosts: allowed,
+		tasks:        make(map[string]*asyncTask),
+	}
+}
+
+func (s *asyncTaskStore) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+// start runs invoke in the background under a new task ID, and posts the
+// result to push when it is set. It returns false when
+// maxRunningAsyncTasks are already running.
+func (s *asyncTaskStore) start(
+	invoke func() invocationResponse, push *pushNotificationConfig,
+) (string, bool) {
+	id := newAsyncTaskID()
+
+	s.mu.Lock()
+	if s.running >= maxRunningAsyncTasks {
+		s.mu.Unlock()
+		return "", false
+	}
+	s.pruneLocked(s.clock())
+	s.tasks[id] = &asyncTask{resp: invocationResponse{Status: stateWorking, TaskID: id}}
+	s.running++
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	s.health.beginBusy()
+	go func() {
+		defer s.wg.Done()
+		defer s.health.endBusy()
+		resp := invoke()
+		resp.TaskID = id
+		s.finish(id, resp)
+		if push != nil {
+			s.notify(id, push, resp)
+		}
+	}()
+	return id, true
+}
+
+// finish records a task's result.
+func (s *asyncTaskStore) finish(id string, resp invocationResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	s.tasks[id] = &asyncTask{resp: resp, done: true, finished: s.clock()}
+}
+
+// get returns a task's current response, or false when the task is
+// unknown or its result has expired.
+func (s *asyncTaskStore) get(id string) (invocationResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked(s.clock())
+	t, ok := s.tasks[id]
+	if !ok {
+		return invocationResponse{}, false
+	}
+	return t.resp, true
+}
+
+// pruneLocked drops finished tasks past their retention. The caller holds
+// s.mu.
+func (s *asyncTaskStore) pruneLocked(now time.Time) {
+	for id, t := range s.tasks {
+		if t.done && now.Sub(t.finished) > asyncTaskRetention {
+			delete(s.tasks, id)
+		}
+	}
+}
+
+// notify posts the final response to the push notification URL. Failures
+// are logged; the result stays available for polling.
+func (s *asyncTaskStore) notify(id string, push *pushNotificationConfig, resp invocationResponse) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pushNotificationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, push.URL, bytes.NewReader(body))
+	if err != nil {
+		s.log.Warn("push notification failed", "task_id", id, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if push.Token != "" {
+		req.Header.Set(pushTokenHeader, push.Token)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		s.log.Warn("push notification failed", "task_id", id, "error", err)
+		return
+	}
+	_ = res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		s.log.Warn("push notification rejected", "task_id", id, "status", res.StatusCode)
+	}
+}
+
+// wait blocks until running tasks finish and their notifications are sent.
+func (s *asyncTaskStore) wait() {
+	if s != nil {
+		s.wg.Wait()
+	}
+}
+
+// newAsyncTaskID returns a random task handle.
+func newAsyncTaskID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return asyncTaskIDPrefix + hex.EncodeToString(b)
+}
+
+// handleAsyncInvocation starts req in the background and answers 202 with
+// the task handle to poll at GET /invocations/{taskId}. The task keeps the
+// invocation's limiter slots until it finishes, so async invocations count
+// against the concurrency caps, and runs under a context detached from
+// the request's cancellation.
+func (b *httpBridge) handleAsyncInvocation(
+	w http.ResponseWriter, r *http.Request, req *invocationRequest, metadata map[string]any,
+) {
+	if req.PushNotification != nil {
+		if err := req.PushNotification.validate(b.async.allowedHosts); err != nil {
+			writeInvocationStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	a2aBody, err := buildA2ARequest(req.text(), r.Header.Get(sessionHeader), metadata)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	received := accessLogFrom(r.Context()).receivedAt(time.Now())
+	ctx := context.WithoutCancel(r.Context())
+	outputFormat := req.OutputFormat
+	release := keepLimiterSlot(r.Context())
+	id, ok := b.async.start(func() invocationResponse {
+		defer release()
+		forwarded := time.Now()
+		resp := invocationResponse{Response: "agent unavailable", Status: keyError}
+		if respBody, err := b.forwardToA2A(ctx, a2aBody); err == nil {
+			resp = parseA2AInvocation(respBody, outputFormat)
+			if resp.Status != keyError {
+				b.moderation.apply(&resp)
+			}
+		}
+		resp.Timings = b.completedTimings(received, forwarded)
+		return resp
+	}, req.PushNotification)
+	if !ok {
+		release()
+		w.Header().Set(retryAfterHeader, strconv.Itoa(int(concurrencyRetryAfter/time.Second)))
+		writeInvocationStatus(w, http.StatusTooManyRequests, msgTooManyAsyncTasks)
+		return
+	}
+
+	accessLogFrom(r.Context()).setTask(id)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", invocationsPath+"/"+id)
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(invocationResponse{Status: stateWorking, TaskID: id})
+}
+
+// handleAsyncResult serves GET /invocations/{taskId}.
+func (b *httpBridge) handleAsyncResult(w http.ResponseWriter, r *http.Request) {
+	resp, ok := b.async.get(r.PathValue("taskId"))
+	if !ok {
+		writeInvocationStatus(w, http.StatusNotFound, "task not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func asyncBridgeForTest(t *testing.T, a2aPort int) *httpBridge {
+	t.Helper()
+	b := bridgeForTest(t, a2aPort)
+	b.health = newHealthHandler()
+	b.async = newAsyncTaskStore(b.log, b.health, nil)
+	return b
+}
+
+func postAsyncInvocation(t *testing.T, b *httpBridge, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(body))
+	r.Header.Set(sessionHeader, "session-1")
+	w := httptest.NewRecorder()
+	b.handleInvocation(w, r)
+	return w
+}
+
+func getAsyncResult(t *testing.T, b *httpBridge, id string) (int, invocationResponse) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, invocationsPath+"/"+id, nil)
+	r.SetPathValue("taskId", id)
+	w := httptest.NewRecorder()
+	b.handleAsyncResult(w, r)
+	var resp invocationResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	return w.Code, resp
+}
+
+func TestInvocationRequest_AsyncFieldsNotForwarded(t *testing.T) {
+	var req invocationRequest
+	body := `{"prompt":"hi","async":true,"push_notification":{"url":"https://cb.example.com","token":"t"}}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	if !req.Async || req.PushNotification == nil || req.PushNotification.Token != "t" {
+		t.Errorf("req = %+v", req)
+	}
+	if req.Extra != nil {
+		t.Errorf("Extra = %v, want the async fields kept out of metadata", req.Extra)
+	}
+}
+
+func TestAsyncInvocation_PollsResult(t *testing.T) {
+	mock := newMockA2AServer(t)
+	release := make(chan struct{})
+	mock.onSend = func(map[string]any) (int, string) {
+		<-release
+		return http.StatusOK, `{"result":{"id":"task-9","status":{"state":"completed"},` +
+			`"artifacts":[{"parts":[{"text":"done"}]}]}}`
+	}
+	b := asyncBridgeForTest(t, mock.port(t))
+
+	w := postAsyncInvocation(t, b, `{"prompt":"long job","async":true}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", w.Code)
+	}
+	var accepted invocationResponse
+	if err := json.NewDecoder(w.Body).Decode(&accepted); err != nil {
+		t.Fatal(err)
+	}
+	id := accepted.TaskID
+	if accepted.Status != stateWorking || !strings.HasPrefix(id, asyncTaskIDPrefix) {
+		t.Fatalf("accepted = %+v", accepted)
+	}
+	if loc := w.Header().Get("Location"); loc != invocationsPath+"/"+id {
+		t.Errorf("Location = %q", loc)
+	}
+
+	if code, resp := getAsyncResult(t, b, id); code != http.StatusOK || resp.Status != stateWorking {
+		t.Errorf("while running: %d %+v", code, resp)
+	}
+	if _, h := serveHealth(t, b.health); h.Status != statusBusy {
+		t.Errorf("health while running = %q, want %q", h.Status, statusBusy)
+	}
+
+	close(release)
+	b.async.wait()
+	code, resp := getAsyncResult(t, b, id)
+	if code != http.StatusOK || resp.Status != "success" || resp.Response != "done" || resp.TaskID != id {
+		t.Errorf("finished: %d %+v", code, resp)
+	}
+	if _, h := serveHealth(t, b.health); h.Status != statusHealthy {
+		t.Errorf("health after finishing = %q, want %q", h.Status, statusHealthy)
+	}
+}
+
+func TestAsyncInvocation_PushNotification(t *testing.T) {
+	mock := newMockA2AServer(t)
+	type push struct {
+		token string
+		resp  invocationResponse
+	}
+	pushed := make(chan push, 1)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p push
+		p.token = r.Header.Get(pushTokenHeader)
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &p.resp)
+		pushed <- p
+	}))
+	defer callback.Close()
+	b := asyncBridgeForTest(t, mock.port(t))
+	b.async = newAsyncTaskStore(b.log, b.health, []string{"127.0.0.1"})
+
+	w := postAsyncInvocation(t, b,
+		`{"prompt":"hi","async":true,"push_notification":{"url":"`+callback.URL+`","token":"tok"}}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", w.Code)
+	}
+	select {
+	case p := <-pushed:
+		if p.token != "tok" || p.resp.Status != "success" || p.resp.Response != "echo: hi" {
+			t.Errorf("push = %+v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification")
+	}
+	b.async.wait()
+}
+
+func TestAsyncInvocation_HoldsLimiterSlot(t *testing.T) {
+	mock := newMockA2AServer(t)
+	release := make(chan struct{})
+	mock.onSend = func(map[string]any) (int, string) {
+		<-release
+		return http.StatusOK, `{"result":{"id":"task-9","status":{"state":"completed"},` +
+			`"artifacts":[{"parts":[{"text":"done"}]}]}}`
+	}
+	b := asyncBridgeForTest(t, mock.port(t))
+	h := buildInvocationLimiter(&runtimeConfig{MaxConcurrentInvocations: 1}).wrap(http.HandlerFunc(b.handleInvocation))
+	post := func(body string) int {
+		r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(body))
+		r.Header.Set(sessionHeader, "session-1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := post(`{"prompt":"long job","async":true}`); code != http.StatusAccepted {
+		t.Fatalf("async status = %d, want 202", code)
+	}
+	if code := post(`{"prompt":"hi"}`); code != http.StatusTooManyRequests {
+		t.Errorf("status while the task runs = %d, want 429", code)
+	}
+	close(release)
+	b.async.wait()
+	if code := post(`{"prompt":"hi"}`); code != http.StatusOK {
+		t.Errorf("status after the task finished = %d, want 200", code)
+	}
+}
+
+func TestAsyncInvocation_Rejections(t *testing.T) {
+	b := asyncBridgeForTest(t, 1)
+
+	for _, url := range []string{"/relative", "http://169.254.169.254/latest/meta-data", "http://localhost:8080/cb",
+		"https://10.0.0.7/cb", "http://[::1]/cb", "http://0.0.0.0/cb"} {
+		w := postAsyncInvocation(t, b, `{"prompt":"hi","async":true,"push_notification":{"url":"`+url+`"}}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("push url %s: status = %d, want 400", url, w.Code)
+		}
+	}
+
+	b.async.running = maxRunningAsyncTasks
+	w := postAsyncInvocation(t, b, `{"prompt":"hi","async":true}`)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get(retryAfterHeader) == "" {
+		t.Errorf("at cap: status = %d, want 429 with Retry-After", w.Code)
+	}
+
+	if code, _ := getAsyncResult(t, b, "async-unknown"); code != http.StatusNotFound {
+		t.Errorf("unknown task: status = %d, want 404", code)
+	}
+}
+
+func TestPushNotificationConfig_AllowedHosts(t *testing.T) {
+	push := &pushNotificationConfig{URL: "http://LocalHost:8080/cb"}
+	if err := push.validate(nil); !errors.Is(err, errPushAddressBlocked) {
+		t.Errorf("validate without allowlist = %v, want %v", err, errPushAddressBlocked)
+	}
+	if err := push.validate(map[string]bool{"localhost": true}); err != nil {
+		t.Errorf("validate of an allowed host = %v", err)
+	}
+	if err := (&pushNotificationConfig{URL: "https://hooks.example.com/cb"}).validate(nil); err != nil {
+		t.Errorf("validate of a public host = %v", err)
+	}
+}
+
+func TestPushClient_RefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, nil)
+	if _, err := newPushClient(nil).Do(req); !errors.Is(err, errPushAddressBlocked) {
+		t.Errorf("push to %s = %v, want %v", srv.URL, err, errPushAddressBlocked)
+	}
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, nil)
+	res, err := newPushClient(map[string]bool{"127.0.0.1": true}).Do(req)
+	if err != nil {
+		t.Fatalf("push to an allowed host: %v", err)
+	}
+	_ = res.Body.Close()
+}
+
+func TestAsyncTaskStore_ExpiresFinishedTasks(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newAsyncTaskStore(slog.New(slog.NewJSONHandler(io.Discard, nil)), nil, nil)
+	s.now = func() time.Time { return now }
+
+	id, ok := s.start(func() invocationResponse { return invocationResponse{Status: "success"} }, nil)
+	if !ok {
+		t.Fatal("start rejected")
+	}
+	s.wait()
+	if _, ok := s.get(id); !ok {
+		t.Fatal("finished task not kept")
+	}
+	now = now.Add(asyncTaskRetention + time.Second)
+	if _, ok := s.get(id); ok {
+		t.Error("task kept past its retention")
+	}
+}
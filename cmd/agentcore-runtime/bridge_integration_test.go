@@ -807,6 +807,41 @@ func readWSResponse(t *testing.T, conn *websocket.Conn) wsResponse {
 	return resp
 }
 
+// readWSUntilDone reads frames until a done or error frame and returns them
+// all, including the final one.
+func readWSUntilDone(t *testing.T, conn *websocket.Conn) []wsResponse {
+	t.Helper()
+	var frames []wsResponse
+	for {
+		resp := readWSResponse(t, conn)
+		frames = append(frames, resp)
+		if resp.Type == wsTypeDone || resp.Type == keyError {
+			return frames
+		}
+	}
+}
+
+// wsText concatenates the content of all text frames.
+func wsText(frames []wsResponse) string {
+	var sb strings.Builder
+	for _, f := range frames {
+		if f.Type == kindText {
+			sb.WriteString(f.Content)
+		}
+	}
+	return sb.String()
+}
+
+// wsStreamBody renders a default-shaped A2A SSE stream for the given text.
+func wsStreamBody(w http.ResponseWriter, taskID, text string) {
+	w.Header().Set("Content-Type", sseContentType)
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "data: %s\n\n",
+		fmt.Sprintf(`{"jsonrpc":"2.0","id":"1","result":{"taskId":%q,"artifact":{"parts":[{"text":%q}]}}}`, taskID, text))
+	fmt.Fprintf(w, "data: %s\n\n",
+		fmt.Sprintf(`{"jsonrpc":"2.0","id":"1","result":{"taskId":%q,"status":{"state":"completed"}}}`, taskID))
+}
+
 func TestIntegration_WS_BasicRoundTrip(t *testing.T) {
 	mock := newMockA2AServer(t)
 	b := bridgeForTest(t, mock.port(t))
@@ -817,23 +852,33 @@ func TestIntegration_WS_BasicRoundTrip(t *testing.T) {
 		t.Fatalf("write: %v", err)
 	}
 
-	resp := readWSResponse(t, conn)
-	if resp.Type != "text" {
-		t.Errorf("Type = %q, want text", resp.Type)
+	frames := readWSUntilDone(t, conn)
+	// working → 2 text chunks → completed → done
+	if len(frames) != 5 {
+		t.Fatalf("expected 5 frames, got %d: %+v", len(frames), frames)
 	}
-	if resp.Content != "echo: ws hello" {
-		t.Errorf("Content = %q, want %q", resp.Content, "echo: ws hello")
+	if frames[0].Type != keyStatus || frames[0].State != "working" {
+		t.Errorf("frame[0] = %+v, want working status", frames[0])
 	}
-	if resp.TaskID != "task-001" {
-		t.Errorf("TaskID = %q, want task-001", resp.TaskID)
+	if frames[1].Type != kindText || frames[1].Content != "echo: " {
+		t.Errorf("frame[1] = %+v, want first text chunk", frames[1])
 	}
-	if resp.Usage == nil {
-		t.Fatal("expected usage to be non-nil")
+	if got := wsText(frames); got != "echo: ws hello" {
+		t.Errorf("text = %q, want %q", got, "echo: ws hello")
 	}
-
-	done := readWSResponse(t, conn)
-	if done.Type != "done" {
-		t.Errorf("done.Type = %q, want done", done.Type)
+	if frames[1].TaskID != "task-s1" {
+		t.Errorf("TaskID = %q, want task-s1", frames[1].TaskID)
+	}
+	for i, f := range frames[:4] {
+		if f.Seq != i+1 {
+			t.Errorf("frame[%d].Seq = %d, want %d", i, f.Seq, i+1)
+		}
+	}
+	if frames[3].State != "completed" {
+		t.Errorf("frame[3].State = %q, want completed", frames[3].State)
+	}
+	if frames[4].Type != wsTypeDone {
+		t.Errorf("last frame Type = %q, want done", frames[4].Type)
 	}
 }
 
@@ -850,46 +895,41 @@ func TestIntegration_WS_MultipleMessages(t *testing.T) {
 			t.Fatalf("write %d: %v", i, err)
 		}
 
-		resp := readWSResponse(t, conn)
-		if resp.Type != "text" {
-			t.Errorf("msg %d: Type = %q, want text", i, resp.Type)
-		}
+		frames := readWSUntilDone(t, conn)
 		expected := "echo: " + prompt
-		if resp.Content != expected {
-			t.Errorf("msg %d: Content = %q, want %q", i, resp.Content, expected)
+		if got := wsText(frames); got != expected {
+			t.Errorf("msg %d: text = %q, want %q", i, got, expected)
 		}
-
-		done := readWSResponse(t, conn)
-		if done.Type != "done" {
-			t.Errorf("msg %d: done.Type = %q, want done", i, done.Type)
+		if last := frames[len(frames)-1]; last.Type != wsTypeDone {
+			t.Errorf("msg %d: last frame Type = %q, want done", i, last.Type)
+		}
+		// Sequence numbers restart for every request.
+		if frames[0].Seq != 1 {
+			t.Errorf("msg %d: first Seq = %d, want 1", i, frames[0].Seq)
 		}
 	}
 
 	mock.mu.Lock()
 	defer mock.mu.Unlock()
-	if mock.sendCount != 3 {
-		t.Errorf("sendCount = %d, want 3", mock.sendCount)
+	if mock.streamCount != 3 {
+		t.Errorf("streamCount = %d, want 3", mock.streamCount)
 	}
 }
 
 func TestIntegration_WS_ErrorRecovery(t *testing.T) {
 	callCount := int32(0)
 	mock := newMockA2AServer(t)
-	mock.onSend = func(params map[string]any) (int, string) {
+	mock.onStream = func(w http.ResponseWriter, params map[string]any) {
 		n := atomic.AddInt32(&callCount, 1)
 		if n == 1 {
 			// First call returns error.
-			return http.StatusOK, `{"error":{"message":"first call fails"}}`
+			w.Header().Set("Content-Type", sseContentType)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "data: %s\n\n", `{"error":{"message":"first call fails"}}`)
+			return
 		}
 		// Subsequent calls succeed.
-		text := extractTextFromParams(params)
-		return http.StatusOK, fmt.Sprintf(`{
-			"result": {
-				"id": "task-r1",
-				"status": {"state": "completed"},
-				"artifacts": [{"parts": [{"text": %q}]}]
-			}
-		}`, "ok: "+text)
+		wsStreamBody(w, "task-r1", "ok: "+extractTextFromParams(params))
 	}
 	b := bridgeForTest(t, mock.port(t))
 	baseURL := startBridgeServer(t, b)
@@ -908,12 +948,9 @@ func TestIntegration_WS_ErrorRecovery(t *testing.T) {
 	if err := conn.WriteJSON(wsRequest{Prompt: "second"}); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	resp2 := readWSResponse(t, conn)
-	if resp2.Type != "text" {
-		t.Errorf("second: Type = %q, want text", resp2.Type)
-	}
-	if resp2.Content != "ok: second" {
-		t.Errorf("second: Content = %q, want %q", resp2.Content, "ok: second")
+	frames := readWSUntilDone(t, conn)
+	if got := wsText(frames); got != "ok: second" {
+		t.Errorf("second: text = %q, want %q", got, "ok: second")
 	}
 }
 
@@ -999,19 +1036,28 @@ func TestIntegration_WS_ConcurrentConnections(t *testing.T) {
 				return
 			}
 
-			_, data, readErr := conn.ReadMessage()
-			if readErr != nil {
-				errs <- fmt.Errorf("conn %d read: %w", idx, readErr)
-				return
-			}
-			var wsResp wsResponse
-			if unmarshalErr := json.Unmarshal(data, &wsResp); unmarshalErr != nil {
-				errs <- fmt.Errorf("conn %d unmarshal: %w", idx, unmarshalErr)
-				return
+			var sb strings.Builder
+			for {
+				_, data, readErr := conn.ReadMessage()
+				if readErr != nil {
+					errs <- fmt.Errorf("conn %d read: %w", idx, readErr)
+					return
+				}
+				var wsResp wsResponse
+				if unmarshalErr := json.Unmarshal(data, &wsResp); unmarshalErr != nil {
+					errs <- fmt.Errorf("conn %d unmarshal: %w", idx, unmarshalErr)
+					return
+				}
+				if wsResp.Type == wsTypeDone {
+					break
+				}
+				if wsResp.Type == kindText {
+					sb.WriteString(wsResp.Content)
+				}
 			}
 			expected := "echo: " + prompt
-			if wsResp.Content != expected {
-				errs <- fmt.Errorf("conn %d: got %q, want %q", idx, wsResp.Content, expected)
+			if sb.String() != expected {
+				errs <- fmt.Errorf("conn %d: got %q, want %q", idx, sb.String(), expected)
 			}
 		}(i)
 	}
@@ -1042,12 +1088,12 @@ func TestIntegration_Ping_Endpoint(t *testing.T) {
 		t.Errorf("status = %d, want 200", resp.StatusCode)
 	}
 
-	var body map[string]string
+	var body healthResponse
 	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
 		t.Fatalf("decode: %v", err)
 	}
-	if body["status"] != "healthy" {
-		t.Errorf("status = %q, want healthy", body["status"])
+	if body.Status != "healthy" {
+		t.Errorf("status = %q, want healthy", body.Status)
 	}
 }
 
@@ -1127,18 +1173,25 @@ func TestIntegration_MixedEndpoints(t *testing.T) {
 			errs <- fmt.Errorf("ws write: %w", writeErr)
 			return
 		}
-		_, data, readErr := conn.ReadMessage()
-		if readErr != nil {
-			errs <- fmt.Errorf("ws read: %w", readErr)
-			return
-		}
-		var wsResp wsResponse
-		if unmarshalErr := json.Unmarshal(data, &wsResp); unmarshalErr != nil {
-			errs <- fmt.Errorf("ws unmarshal: %w", unmarshalErr)
-			return
+		sawText := false
+		for {
+			_, data, readErr := conn.ReadMessage()
+			if readErr != nil {
+				errs <- fmt.Errorf("ws read: %w", readErr)
+				return
+			}
+			var wsResp wsResponse
+			if unmarshalErr := json.Unmarshal(data, &wsResp); unmarshalErr != nil {
+				errs <- fmt.Errorf("ws unmarshal: %w", unmarshalErr)
+				return
+			}
+			if wsResp.Type == wsTypeDone {
+				break
+			}
+			sawText = sawText || wsResp.Type == kindText
 		}
-		if wsResp.Type != "text" {
-			errs <- fmt.Errorf("ws: Type=%q, want text", wsResp.Type)
+		if !sawText {
+			errs <- fmt.Errorf("ws: no text frame received")
 		}
 	}()
 
@@ -1218,20 +1271,9 @@ func TestIntegration_SSE_NonEventLines(t *testing.T) {
 func TestIntegration_WS_WithMetadata(t *testing.T) {
 	var receivedParams atomic.Value
 	mock := newMockA2AServer(t)
-	original := mock.onSend
-	mock.onSend = func(params map[string]any) (int, string) {
+	mock.onStream = func(w http.ResponseWriter, params map[string]any) {
 		receivedParams.Store(params)
-		if original != nil {
-			return original(params)
-		}
-		text := extractTextFromParams(params)
-		return http.StatusOK, fmt.Sprintf(`{
-			"result": {
-				"id": "task-md",
-				"status": {"state": "completed"},
-				"artifacts": [{"parts": [{"text": %q}]}]
-			}
-		}`, "ok: "+text)
+		wsStreamBody(w, "task-md", "ok: "+extractTextFromParams(params))
 	}
 	b := bridgeForTest(t, mock.port(t))
 	baseURL := startBridgeServer(t, b)
@@ -1245,9 +1287,9 @@ func TestIntegration_WS_WithMetadata(t *testing.T) {
 		t.Fatalf("write: %v", err)
 	}
 
-	resp := readWSResponse(t, conn)
-	if resp.Type != "text" {
-		t.Fatalf("Type = %q, want text", resp.Type)
+	frames := readWSUntilDone(t, conn)
+	if got := wsText(frames); got != "ok: with metadata" {
+		t.Fatalf("text = %q, want %q", got, "ok: with metadata")
 	}
 
 	// Verify metadata was forwarded.
@@ -1272,27 +1314,19 @@ func TestIntegration_WS_InputField(t *testing.T) {
 		t.Fatalf("write: %v", err)
 	}
 
-	resp := readWSResponse(t, conn)
-	if resp.Type != "text" {
-		t.Errorf("Type = %q, want text", resp.Type)
-	}
-	if resp.Content != "echo: using input" {
-		t.Errorf("Content = %q, want %q", resp.Content, "echo: using input")
+	frames := readWSUntilDone(t, conn)
+	if got := wsText(frames); got != "echo: using input" {
+		t.Errorf("text = %q, want %q", got, "echo: using input")
 	}
 }
 
 func TestIntegration_WS_A2ATaskFailed(t *testing.T) {
 	mock := newMockA2AServer(t)
-	mock.onSend = func(_ map[string]any) (int, string) {
-		return http.StatusOK, `{
-			"result": {
-				"id": "task-f1",
-				"status": {
-					"state": "failed",
-					"message": {"parts": [{"text": "task failed"}]}
-				}
-			}
-		}`
+	mock.onStream = func(w http.ResponseWriter, _ map[string]any) {
+		w.Header().Set("Content-Type", sseContentType)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "data: %s\n\n",
+			`{"result":{"taskId":"task-f1","status":{"state":"failed","message":{"parts":[{"text":"task failed"}]}}}}`)
 	}
 	b := bridgeForTest(t, mock.port(t))
 	baseURL := startBridgeServer(t, b)
@@ -1302,11 +1336,14 @@ func TestIntegration_WS_A2ATaskFailed(t *testing.T) {
 		t.Fatalf("write: %v", err)
 	}
 
-	resp := readWSResponse(t, conn)
-	if resp.Type != "error" {
-		t.Errorf("Type = %q, want error", resp.Type)
+	frames := readWSUntilDone(t, conn)
+	if len(frames) != 2 {
+		t.Fatalf("expected failed status + done, got %+v", frames)
+	}
+	if frames[0].Type != keyStatus || frames[0].State != stateFailed {
+		t.Errorf("frame[0] = %+v, want failed status", frames[0])
 	}
-	if resp.Content != "task failed" {
-		t.Errorf("Content = %q, want %q", resp.Content, "task failed")
+	if frames[0].Content != "task failed" {
+		t.Errorf("Content = %q, want %q", frames[0].Content, "task failed")
 	}
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// capabilitiesPath serves the bridge's capability summary.
+const capabilitiesPath = "/capabilities"
+
+// Optional bridge features listed in the capability summary.
+const (
+	featureAsync       = "async"
+	featureSSEResume   = "sse_resume"
+	featureSessions    = "sessions"
+	featureRateLimits  = "rate_limits"
+	featureBudgets     = "session_token_budgets"
+	featureCompression = "compression"
+	featureCORS        = "cors"
+	featureModeration  = "response_moderation"
+	featureTimings     = "response_timings"
+)
+
+// bridgeCapabilities is the GET /capabilities response: the HTTP endpoints
+// and optional features this bridge serves. Discovery tooling reads it
+// alongside the agent card, which it also locates when the A2A server is
+// skipped.
+type bridgeCapabilities struct {
+	Runtime   string           `json:"runtime"`
+	Protocol  string           `json:"protocol"`
+	A2AServer bool             `json:"a2a_server"`
+	Endpoints []bridgeEndpoint `json:"endpoints"`
+	Features  []string         `json:"features"`
+}
+
+// bridgeEndpoint describes one HTTP endpoint of the bridge.
+type bridgeEndpoint struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+// buildCapabilities summarizes the endpoints and features of b as
+// configured by cfg. Call it once the bridge's optional parts are built.
+func (b *httpBridge) buildCapabilities(cfg *runtimeConfig) *bridgeCapabilities {
+	protocol := cfg.Protocol
+	if protocol == "" {
+		protocol = protocolBoth
+	}
+	endpoints := []bridgeEndpoint{
+		{http.MethodPost, invocationsPath, "invoke the agent: blocking JSON, SSE with Accept: text/event-stream, " +
+			"or async with \"async\": true"},
+		{http.MethodGet, invocationsPath + "/{taskId}", "status and result of an async invocation"},
+		{http.MethodGet, wsPath, "WebSocket messaging"},
+		{http.MethodGet, pingPath, "health check"},
+	}
+	if b.sessions != nil {
+		endpoints = append(endpoints,
+			bridgeEndpoint{http.MethodGet, sessionsPath + "{id}", "session metadata"},
+			bridgeEndpoint{http.MethodGet, sessionsPath + "{id}" + sessionUsageSuffix, "token usage of a conversation"})
+	}
+	if b.card != nil {
+		endpoints = append(endpoints, bridgeEndpoint{http.MethodGet, agentCardPath, "agent card"})
+	}
+	endpoints = append(endpoints, bridgeEndpoint{http.MethodGet, capabilitiesPath, "this summary"})
+
+	features := []string{featureAsync, featureSSEResume}
+	for _, f := range []struct {
+		name string
+		on   bool
+	}{
+		{featureSessions, b.sessions != nil},
+		{featureRateLimits, b.limits != nil},
+		{featureBudgets, b.sessions != nil && b.sessions.tokenBudget > 0},
+		{featureCompression, b.compression != nil},
+		{featureCORS, b.cors != nil},
+		{featureModeration, b.moderation != nil},
+		{featureTimings, b.responseTimings},
+	} {
+		if f.on {
+			features = append(features, f.name)
+		}
+	}
+
+	return &bridgeCapabilities{
+		Runtime:   version,
+		Protocol:  protocol,
+		A2AServer: cfg.wantA2AServer(),
+		Endpoints: endpoints,
+		Features:  features,
+	}
+}
+
+// handleCapabilities serves GET /capabilities.
+func (b *httpBridge) handleCapabilities(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(b.capabilities)
+}
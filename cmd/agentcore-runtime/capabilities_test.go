@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+)
+
+func hasEndpoint(c *bridgeCapabilities, method, path string) bool {
+	return slices.ContainsFunc(c.Endpoints, func(e bridgeEndpoint) bool {
+		return e.Method == method && e.Path == path
+	})
+}
+
+func TestBuildCapabilities_HTTPOnly(t *testing.T) {
+	b := &httpBridge{card: &a2a.AgentCard{Name: "agent"}, responseTimings: true}
+	c := b.buildCapabilities(&runtimeConfig{Protocol: protocolHTTP})
+
+	if c.Protocol != protocolHTTP || c.A2AServer {
+		t.Errorf("protocol = %q, a2a_server = %t; want http without the A2A server", c.Protocol, c.A2AServer)
+	}
+	for _, path := range []string{invocationsPath, wsPath, pingPath, agentCardPath, capabilitiesPath} {
+		if !hasEndpoint(c, http.MethodGet, path) && !hasEndpoint(c, http.MethodPost, path) {
+			t.Errorf("endpoint %s missing from %+v", path, c.Endpoints)
+		}
+	}
+	if hasEndpoint(c, http.MethodGet, sessionsPath+"{id}") || hasEndpoint(c, http.MethodGet, sessionsPath+"{id}"+sessionUsageSuffix) {
+		t.Error("session endpoints listed without session tracking")
+	}
+	if !slices.Contains(c.Features, featureTimings) || slices.Contains(c.Features, featureCORS) {
+		t.Errorf("features = %v, want response_timings without cors", c.Features)
+	}
+}
+
+func TestBuildCapabilities_Both(t *testing.T) {
+	b := &httpBridge{sessions: &sessionTracker{}, cors: &corsPolicy{}}
+	c := b.buildCapabilities(&runtimeConfig{})
+
+	if c.Protocol != protocolBoth || !c.A2AServer {
+		t.Errorf("protocol = %q, a2a_server = %t; want both with the A2A server", c.Protocol, c.A2AServer)
+	}
+	if hasEndpoint(c, http.MethodGet, agentCardPath) {
+		t.Error("agent card listed on the bridge while the A2A server serves it")
+	}
+	if !hasEndpoint(c, http.MethodGet, sessionsPath+"{id}") || !hasEndpoint(c, http.MethodGet, sessionsPath+"{id}"+sessionUsageSuffix) {
+		t.Error("session endpoints missing with session tracking")
+	}
+	if !slices.Contains(c.Features, featureSessions) || !slices.Contains(c.Features, featureCORS) {
+		t.Errorf("features = %v, want sessions and cors", c.Features)
+	}
+}
+
+func TestHandleCapabilities(t *testing.T) {
+	b := &httpBridge{log: slog.Default()}
+	b.capabilities = b.buildCapabilities(&runtimeConfig{Protocol: protocolHTTP})
+
+	rec := httptest.NewRecorder()
+	b.handleCapabilities(rec, httptest.NewRequest(http.MethodGet, capabilitiesPath, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got bridgeCapabilities
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Runtime != version || len(got.Endpoints) == 0 {
+		t.Errorf("capabilities = %+v", got)
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Content codings the bridge compresses responses with and decompresses
+// request bodies from. deflate is the zlib format, per RFC 9110.
+const (
+	encodingGzip     = "gzip"
+	encodingDeflate  = "deflate"
+	encodingIdentity = "identity"
+)
+
+// HTTP headers used in content-coding negotiation.
+const (
+	acceptEncodingHeader  = "Accept-Encoding"
+	contentEncodingHeader = "Content-Encoding"
+	varyHeader            = "Vary"
+)
+
+// defaultCompressionMinSize is the smallest response body, in bytes, that is
+// compressed when PROMPTPACK_COMPRESSION_MIN_SIZE is unset. Smaller bodies
+// cost more to frame than compression saves.
+const defaultCompressionMinSize = 1024
+
+// maxDecompressedRequestBytes caps a decompressed request body, so a small
+// compressed body cannot expand without bound.
+const maxDecompressedRequestBytes = 10 << 20
+
+// compressionStream is a gzip or zlib writer.
+type compressionStream interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// responseCompressor compresses invocation responses in the coding the
+// client accepts.
+type responseCompressor struct {
+	level   int  // gzip.DefaultCompression or 1-9
+	minSize int  // bodies shorter than this are sent as is
+	sse     bool // also compress SSE streams
+
+	gzipWriters sync.Pool
+	zlibWriters sync.Pool
+}
+
+// buildResponseCompressor returns the compressor configured in cfg, or nil
+// when response compression is off.
+func buildResponseCompressor(cfg *runtimeConfig, log *slog.Logger) *responseCompressor {
+	if !cfg.Compression {
+		return nil
+	}
+	c := &responseCompressor{
+		level:   gzip.DefaultCompression,
+		minSize: defaultCompressionMinSize,
+		sse:     cfg.CompressionSSE,
+	}
+	if cfg.CompressionLevel > 0 {
+		c.level = cfg.CompressionLevel
+	}
+	if cfg.CompressionMinSize > 0 {
+		c.minSize = cfg.CompressionMinSize
+	}
+	log.Info("response compression enabled", "level", c.level, "min_size", c.minSize, "sse", c.sse)
+	return c
+}
+
+// writer returns a pooled stream for encoding that writes to w.
+func (c *responseCompressor) writer(encoding string, w io.Writer) compressionStream {
+	pool, create := &c.gzipWriters, func() (compressionStream, error) { return gzip.NewWriterLevel(w, c.level) }
+	if encoding == encodingDeflate {
+		pool, create = &c.zlibWriters, func() (compressionStream, error) { return zlib.NewWriterLevel(w, c.level) }
+	}
+	if s, ok := pool.Get().(compressionStream); ok {
+		s.Reset(w)
+		return s
+	}
+	// The level is validated when the config loads, so create cannot fail.
+	s, _ := create()
+	return s
+}
+
+// release returns a closed stream to its pool.
+func (c *responseCompressor) release(encoding string, s compressionStream) {
+	if encoding == encodingDeflate {
+		c.zlibWriters.Put(s)
+		return
+	}
+	c.gzipWriters.Put(s)
+}
+
+// wrap compresses the responses of next in the coding negotiated from the
+// request's Accept-Encoding. A nil compressor returns next unchanged.
+func (c *responseCompressor) wrap(next http.Handler) http.Handler {
+	if c == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add(varyHeader, acceptEncodingHeader)
+		encoding := negotiateEncoding(r.Header.Get(acceptEncodingHeader))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, c: c, encoding: encoding}
+		next.ServeHTTP(cw, r)
+		cw.finish()
+	})
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding value,
+// preferring the higher quality and gzip on a tie, or returns "" when the
+// client accepts neither.
+func negotiateEncoding(accept string) string {
+	q := map[string]float64{}
+	for part := range strings.SplitSeq(accept, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		q[coding] = weight
+	}
+	quality := func(coding string) float64 {
+		if v, ok := q[coding]; ok {
+			return v
+		}
+		return q["*"]
+	}
+	gz, fl := quality(encodingGzip), quality(encodingDeflate)
+	switch {
+	case gz > 0 && gz >= fl:
+		return encodingGzip
+	case fl > 0:
+		return encodingDeflate
+	default:
+		return ""
+	}
+}
+
+// compressWriter buffers a response until it reaches the minimum size, then
+// compresses the rest of it. A flush before then starts the response:
+// compressed for an SSE stream when SSE compression is on, as is otherwise.
+type compressWriter struct {
+	http.ResponseWriter
+	c        *responseCompressor
+	encoding string
+
+	status  int
+	buf     []byte
+	started bool
+	stream  compressionStream // non-nil once compressing
+}
+
+// WriteHeader records the status; it is sent when the response starts.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.started || cw.status != 0 {
+		return
+	}
+	cw.status = code
+}
+
+// Write buffers p until the body reaches the minimum size, then writes it
+// through the compressor.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.started {
+		if cw.stream != nil {
+			return cw.stream.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.c.minSize {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush implements http.Flusher, flushing compressed data through to the
+// client.
+func (cw *compressWriter) Flush() {
+	if !cw.started {
+		_ = cw.start(cw.isSSE())
+	}
+	if cw.stream != nil {
+		_ = cw.stream.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// isSSE reports whether the response is an SSE stream.
+func (cw *compressWriter) isSSE() bool {
+	return strings.HasPrefix(cw.Header().Get("Content-Type"), sseContentType)
+}
+
+// start sends the status and headers, compressed when compress is set and
+// the response allows it, and writes out the buffered body.
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	status := cw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	h := cw.Header()
+	if compress && cw.compressible(status) {
+		h.Del("Content-Length")
+		h.Set(contentEncodingHeader, cw.encoding)
+		cw.stream = cw.c.writer(cw.encoding, cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.stream != nil {
+		_, err := cw.stream.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// compressible reports whether a response with status may be compressed:
+// it has a body, is not already encoded, and is not an SSE stream unless
+// SSE compression is on.
+func (cw *compressWriter) compressible(status int) bool {
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if cw.Header().Get(contentEncodingHeader) != "" {
+		return false
+	}
+	return cw.c.sse || !cw.isSSE()
+}
+
+// finish sends a response that never reached the minimum size as is and
+// closes the compressor.
+func (cw *compressWriter) finish() {
+	if !cw.started && (cw.status != 0 || len(cw.buf) > 0) {
+		_ = cw.start(false)
+	}
+	if cw.stream != nil {
+		_ = cw.stream.Close()
+		cw.c.release(cw.encoding, cw.stream)
+		cw.stream = nil
+	}
+}
+
+// decompressRequest decodes gzip and deflate request bodies before next
+// reads them. Other codings get 415 with the supported ones listed; bodies
+// that do not decode get 400, and bodies that decompress past
+// maxDecompressedRequestBytes get 413.
+func decompressRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		coding := strings.ToLower(strings.TrimSpace(r.Header.Get(contentEncodingHeader)))
+		if coding == "" || coding == encodingIdentity {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var zr io.ReadCloser
+		var err error
+		switch coding {
+		case encodingGzip:
+			zr, err = gzip.NewReader(r.Body)
+		case encodingDeflate:
+			zr, err = zlib.NewReader(r.Body)
+		default:
+			w.Header().Set(acceptEncodingHeader, encodingGzip+", "+encodingDeflate)
+			writeInvocationStatus(w, http.StatusUnsupportedMediaType,
+				fmt.Sprintf("unsupported content encoding %q", coding))
+			return
+		}
+		var body []byte
+		if err == nil {
+			body, err = io.ReadAll(io.LimitReader(zr, maxDecompressedRequestBytes+1))
+			_ = zr.Close()
+		}
+		if err != nil {
+			writeInvocationStatus(w, http.StatusBadRequest, fmt.Sprintf("invalid %s request body", coding))
+			return
+		}
+		if len(body) > maxDecompressedRequestBytes {
+			writeInvocationStatus(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("decompressed request body exceeds %d bytes", maxDecompressedRequestBytes))
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.Header.Del(contentEncodingHeader)
+		next.ServeHTTP(w, r)
+	})
+}
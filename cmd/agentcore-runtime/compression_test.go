@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestCompressor(cfg runtimeConfig) *responseCompressor {
+	cfg.Compression = true
+	return buildResponseCompressor(&cfg, slog.New(slog.DiscardHandler))
+}
+
+func TestLoadConfig_Compression(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(envConfigFile, writeConfigFile(t, "runtime.yaml", `
+pack_file: file.pack.json
+compression: true
+compression_level: 9
+compression_min_size: 256
+`))
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Compression || cfg.CompressionLevel != 9 || cfg.CompressionMinSize != 256 || cfg.CompressionSSE {
+		t.Errorf("compression = %v level %d min %d sse %v", cfg.Compression, cfg.CompressionLevel,
+			cfg.CompressionMinSize, cfg.CompressionSSE)
+	}
+
+	for env, raw := range map[string]string{
+		envCompression:        "yes please",
+		envCompressionLevel:   "10",
+		envCompressionMinSize: "0",
+		envCompressionSSE:     "sometimes",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, raw)
+			if _, err := loadConfig(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("err = %v, want an error naming %s", err, env)
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"gzip":                     encodingGzip,
+		"deflate":                  encodingDeflate,
+		"deflate, gzip":            encodingGzip,
+		"gzip;q=0.5, deflate":      encodingDeflate,
+		"gzip;q=0":                 "",
+		"br, identity":             "",
+		"*":                        encodingGzip,
+		"*;q=0.1, deflate;q=0.2":   encodingDeflate,
+		"GZIP ; q=1.0, deflate;q=": encodingGzip,
+	}
+	for accept, want := range tests {
+		if got := negotiateEncoding(accept); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", accept, got, want)
+		}
+	}
+}
+
+func TestResponseCompressor_Blocking(t *testing.T) {
+	c := newTestCompressor(runtimeConfig{CompressionMinSize: 64})
+	body := `{"response":"` + strings.Repeat("long answer ", 20) + `"}`
+	h := c.wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, body)
+	}))
+
+	serve := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, invocationsPath, nil)
+		req.Header.Set(acceptEncodingHeader, accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("gzip")
+	if rec.Code != http.StatusCreated || rec.Header().Get(contentEncodingHeader) != encodingGzip {
+		t.Fatalf("status = %d, encoding = %q", rec.Code, rec.Header().Get(contentEncodingHeader))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(zr); string(got) != body {
+		t.Errorf("decompressed = %q, want %q", got, body)
+	}
+
+	rec = serve("deflate")
+	zlr, err := zlib.NewReader(rec.Body)
+	if err != nil || rec.Header().Get(contentEncodingHeader) != encodingDeflate {
+		t.Fatalf("deflate: encoding = %q, err = %v", rec.Header().Get(contentEncodingHeader), err)
+	}
+	if got, _ := io.ReadAll(zlr); string(got) != body {
+		t.Errorf("deflate decompressed = %q", got)
+	}
+
+	rec = serve("")
+	if rec.Header().Get(contentEncodingHeader) != "" || rec.Body.String() != body {
+		t.Errorf("no Accept-Encoding: encoding = %q, body = %q", rec.Header().Get(contentEncodingHeader), rec.Body)
+	}
+	if rec.Header().Get(varyHeader) != acceptEncodingHeader {
+		t.Errorf("Vary = %q, want %s", rec.Header().Get(varyHeader), acceptEncodingHeader)
+	}
+
+	small := c.wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, `{"status":"ok"}`)
+	}))
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, invocationsPath, nil)
+	req.Header.Set(acceptEncodingHeader, "gzip")
+	small.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted || rec.Header().Get(contentEncodingHeader) != "" ||
+		rec.Body.String() != `{"status":"ok"}` {
+		t.Errorf("small body: status = %d, encoding = %q, body = %q",
+			rec.Code, rec.Header().Get(contentEncodingHeader), rec.Body)
+	}
+}
+
+func TestResponseCompressor_SSE(t *testing.T) {
+	stream := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", sseContentType)
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		for _, data := range []string{"first", "second"} {
+			_, _ = io.WriteString(w, "data: "+data+"\n\n")
+			flusher.Flush()
+		}
+	})
+	want := "data: first\n\ndata: second\n\n"
+
+	for _, sse := range []bool{false, true} {
+		srv := httptest.NewServer(newTestCompressor(runtimeConfig{CompressionSSE: sse}).wrap(stream))
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+invocationsPath, nil)
+		req.Header.Set(acceptHeader, sseContentType)
+		req.Header.Set(acceptEncodingHeader, "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var body io.Reader = resp.Body
+		encoding := resp.Header.Get(contentEncodingHeader)
+		if sse {
+			if encoding != encodingGzip {
+				t.Fatalf("sse on: encoding = %q, want gzip", encoding)
+			}
+			if body, err = gzip.NewReader(resp.Body); err != nil {
+				t.Fatal(err)
+			}
+		} else if encoding != "" {
+			t.Errorf("sse off: encoding = %q, want none", encoding)
+		}
+		got, err := io.ReadAll(body)
+		_ = resp.Body.Close()
+		srv.Close()
+		if err != nil || string(got) != want {
+			t.Errorf("sse %v: body = %q, %v, want %q", sse, got, err, want)
+		}
+	}
+}
+
+func TestDecompressRequest(t *testing.T) {
+	var received string
+	h := decompressRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		if r.Header.Get(contentEncodingHeader) != "" || r.ContentLength != int64(len(body)) {
+			t.Errorf("Content-Encoding = %q, ContentLength = %d", r.Header.Get(contentEncodingHeader), r.ContentLength)
+		}
+	}))
+	send := func(coding string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, invocationsPath, bytes.NewReader(body))
+		req.Header.Set(contentEncodingHeader, coding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	compress := func(newWriter func(io.Writer) io.WriteCloser, data []byte) []byte {
+		var buf bytes.Buffer
+		zw := newWriter(&buf)
+		_, _ = zw.Write(data)
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+	gzipped := func(data []byte) []byte {
+		return compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }, data)
+	}
+	payload := []byte(`{"prompt":"hello"}`)
+
+	if rec := send("gzip", gzipped(payload)); rec.Code != http.StatusOK || received != string(payload) {
+		t.Errorf("gzip: status = %d, received = %q", rec.Code, received)
+	}
+	deflated := compress(func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }, payload)
+	if rec := send("deflate", deflated); rec.Code != http.StatusOK || received != string(payload) {
+		t.Errorf("deflate: status = %d, received = %q", rec.Code, received)
+	}
+
+	rec := send("br", payload)
+	if rec.Code != http.StatusUnsupportedMediaType || rec.Header().Get(acceptEncodingHeader) != "gzip, deflate" {
+		t.Errorf("br: status = %d, Accept-Encoding = %q", rec.Code, rec.Header().Get(acceptEncodingHeader))
+	}
+	if rec = send("gzip", payload); rec.Code != http.StatusBadRequest {
+		t.Errorf("corrupt gzip: status = %d, want 400", rec.Code)
+	}
+	bomb := gzipped(make([]byte, maxDecompressedRequestBytes+1))
+	if rec = send("gzip", bomb); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized: status = %d, want 413", rec.Code)
+	}
+}
@@ -3,37 +3,99 @@
 package main
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
 )
 
 // Environment variable names.
 const (
-	envPackFile        = "PROMPTPACK_FILE"
-	envPackJSON        = "PROMPTPACK_PACK_JSON"
-	envAgentName       = "PROMPTPACK_AGENT"
-	envPort            = "PROMPTPACK_PORT"
-	envAWSRegion       = "AWS_REGION"
-	envMemoryStore     = "PROMPTPACK_MEMORY_STORE"
-	envMemoryID        = "PROMPTPACK_MEMORY_ID"
-	envA2AAuthMode     = "PROMPTPACK_A2A_AUTH_MODE"
-	envA2AAuthRole     = "PROMPTPACK_A2A_AUTH_ROLE"
-	envPolicyEngineARN = "PROMPTPACK_POLICY_ENGINE_ARN"
-	envMetricsConfig   = "PROMPTPACK_METRICS_CONFIG"
-	envDashboardConfig = "PROMPTPACK_DASHBOARD_CONFIG"
-	envLogGroup        = "PROMPTPACK_LOG_GROUP"
-	envOTLPEndpoint    = "OTEL_EXPORTER_OTLP_ENDPOINT"
-	envTracingEnabled  = "OTEL_TRACING_ENABLED"
-	envAgentEndpoints  = "PROMPTPACK_AGENTS"
-	envProviderType    = "PROMPTPACK_PROVIDER_TYPE"
-	envProviderModel   = "PROMPTPACK_PROVIDER_MODEL"
-	envProtocol        = "PROMPTPACK_PROTOCOL"
+	envPackFile         = "PROMPTPACK_FILE"
+	envPackJSON         = "PROMPTPACK_PACK_JSON"
+	envAgentName        = "PROMPTPACK_AGENT"
+	envPort             = "PROMPTPACK_PORT"
+	envAWSRegion        = "AWS_REGION"
+	envMemoryStore      = "PROMPTPACK_MEMORY_STORE"
+	envMemoryID         = "PROMPTPACK_MEMORY_ID"
+	envMemoryNamespace  = "PROMPTPACK_MEMORY_NAMESPACE"
+	envA2AAuthMode      = "PROMPTPACK_A2A_AUTH_MODE"
+	envA2AAuthRole      = "PROMPTPACK_A2A_AUTH_ROLE"
+	envPolicyEngineARN  = "PROMPTPACK_POLICY_ENGINE_ARN"
+	envMetricsConfig    = "PROMPTPACK_METRICS_CONFIG"
+	envDashboardConfig  = "PROMPTPACK_DASHBOARD_CONFIG"
+	envLogGroup         = "PROMPTPACK_LOG_GROUP"
+	envOTLPEndpoint     = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envTracingEnabled   = "OTEL_TRACING_ENABLED"
+	envServiceName      = "OTEL_SERVICE_NAME"
+	envAgentEndpoints   = "PROMPTPACK_AGENTS"
+	envProviderType     = "PROMPTPACK_PROVIDER_TYPE"
+	envProviderModel    = "PROMPTPACK_PROVIDER_MODEL"
+	envInferenceProfile = "PROMPTPACK_INFERENCE_PROFILE"
+	envGatewaySearch    = "PROMPTPACK_GATEWAY_SEARCH"
+	envAllowedTools     = "PROMPTPACK_ALLOWED_TOOLS"
+	envProtocol         = "PROMPTPACK_PROTOCOL"
+	envWSPingInterval   = "PROMPTPACK_WS_PING_INTERVAL"
+	envWSIdleTimeout    = "PROMPTPACK_WS_IDLE_TIMEOUT"
+	envSSEHeartbeat     = "PROMPTPACK_SSE_HEARTBEAT_INTERVAL"
+	envLogSampleRate    = "PROMPTPACK_LOG_SAMPLE_RATE"
+	envLogRedaction     = "PROMPTPACK_LOG_REDACTION"
+	envResponseTimings  = "PROMPTPACK_RESPONSE_TIMINGS"
+	envAgentCard        = "PROMPTPACK_AGENT_CARD"
+	envToolAudit        = "PROMPTPACK_TOOL_AUDIT"
+	envToolAuditMax     = "PROMPTPACK_TOOL_AUDIT_MAX_EVENTS"
+
+	envPreInvokeWebhook  = "PROMPTPACK_PRE_INVOKE_WEBHOOK_URL"
+	envPostInvokeWebhook = "PROMPTPACK_POST_INVOKE_WEBHOOK_URL"
+	envWebhookSecret     = "PROMPTPACK_WEBHOOK_SECRET"
+	envWebhookTimeout    = "PROMPTPACK_WEBHOOK_TIMEOUT"
+
+	envSessionStore    = "PROMPTPACK_SESSION_STORE"
+	envSessionFile     = "PROMPTPACK_SESSION_FILE"
+	envSessionMaxTurns = "PROMPTPACK_SESSION_MAX_TURNS"
+	envSessionBudget   = "PROMPTPACK_SESSION_DAILY_TOKEN_BUDGET"
+	envA2ATaskStore    = "PROMPTPACK_A2A_TASK_STORE"
+
+	envHistoryMaxTurns  = "PROMPTPACK_HISTORY_MAX_TURNS"
+	envHistoryMaxTokens = "PROMPTPACK_HISTORY_MAX_TOKENS"
+	envHistoryStrategy  = "PROMPTPACK_HISTORY_STRATEGY"
+
+	envRateLimit        = "PROMPTPACK_RATE_LIMIT"
+	envRateBurst        = "PROMPTPACK_RATE_BURST"
+	envSessionRateLimit = "PROMPTPACK_SESSION_RATE_LIMIT"
+	envSessionRateBurst = "PROMPTPACK_SESSION_RATE_BURST"
+	envMaxConcurrent    = "PROMPTPACK_MAX_CONCURRENT_INVOCATIONS"
+	envSessionMaxConc   = "PROMPTPACK_SESSION_MAX_CONCURRENT"
+
+	envFaultInjection = "PROMPTPACK_FAULT_INJECTION"
+
+	envCompression        = "PROMPTPACK_COMPRESSION"
+	envCompressionLevel   = "PROMPTPACK_COMPRESSION_LEVEL"
+	envCompressionMinSize = "PROMPTPACK_COMPRESSION_MIN_SIZE"
+	envCompressionSSE     = "PROMPTPACK_COMPRESSION_SSE"
+
+	envResponseModeration = "PROMPTPACK_RESPONSE_MODERATION"
+
+	envCORSAllowedOrigins = "PROMPTPACK_CORS_ALLOWED_ORIGINS"
+	envCORSAllowedHeaders = "PROMPTPACK_CORS_ALLOWED_HEADERS"
+	envCORSMaxAge         = "PROMPTPACK_CORS_MAX_AGE"
+
+	envPushAllowedHosts = "PROMPTPACK_PUSH_NOTIFICATION_ALLOWED_HOSTS"
 )
 
 const defaultPort = 9000
 
+// bundledPackName is the pack file the code deploy ZIP places next to the
+// runtime binary.
+const bundledPackName = "pack.json"
+
 // runtimeConfig holds all configuration parsed from environment variables.
 type runtimeConfig struct {
 	PackFile        string
@@ -44,6 +106,7 @@ type runtimeConfig struct {
 	AWSRegion       string
 	MemoryStore     string
 	MemoryID        string
+	MemoryNamespace string // actor conversation events are kept under; "" for the default
 	A2AAuthMode     string
 	A2AAuthRole     string
 	PolicyEngineARN string
@@ -52,9 +115,74 @@ type runtimeConfig struct {
 	LogGroup        string
 	OTLPEndpoint    string
 	TracingEnabled  bool
+	ServiceName     string // service.name of the runtime's spans; "" for the default
 	AgentEndpoints  map[string]string
 	ProviderType    string
 	Model           string
+	// InferenceProfile is a Bedrock inference profile ID or ARN invoked
+	// in place of Model when set.
+	InferenceProfile string
+	// GatewaySearch is "semantic" when the pack's tool gateway offers
+	// tool discovery, so agents can search for tools instead of loading
+	// every schema.
+	GatewaySearch string
+	// AllowedTools lists the pack tools the agent's prompt uses, as the
+	// adapter computed them; empty = the prompt's own tool list.
+	AllowedTools   []string
+	WSPingInterval time.Duration // 0 = defaultWSPingInterval
+	WSIdleTimeout  time.Duration // 0 = defaultWSIdleTimeout
+
+	SSEHeartbeatInterval time.Duration // 0 = defaultSSEHeartbeatInterval
+
+	LogSampleRate float64 // fraction of requests access-logged, 0..1
+	LogRedaction  string  // "hash" (default), "truncate", or "none"
+
+	ResponseTimings bool // add a latency breakdown to invocation responses
+
+	AgentCard *agentcore.AgentCardConfig // public agent card overrides
+
+	ToolAudit          bool // record tool calls as memory events
+	ToolAuditMaxEvents int  // per-session audit cap, 0 = agentcore default
+
+	PreInvokeWebhookURL  string        // POSTed before each invocation; a 4xx rejects it
+	PostInvokeWebhookURL string        // POSTed after each invocation
+	WebhookSecret        string        // HMAC key for webhook signatures
+	WebhookTimeout       time.Duration // 0 = defaultWebhookTimeout
+
+	SessionStore    string // "memory" persists session metadata in MemoryID
+	SessionFile     string // local JSON file for session metadata; overrides SessionStore
+	SessionMaxTurns int    // per-session turn limit, 0 = unlimited
+	A2ATaskStore    string // "memory" persists A2A tasks in MemoryID
+
+	// SessionDailyTokenBudget caps the tokens one session may use per UTC
+	// day, 0 = unlimited.
+	SessionDailyTokenBudget int
+
+	HistoryMaxTurns  int    // recent turns sent to the provider, 0 = all
+	HistoryMaxTokens int    // token budget for prompt and history, 0 = unlimited
+	HistoryStrategy  string // "truncate" (default) or "summarize"
+
+	RateLimit                float64 // global invocations per second, 0 = unlimited
+	RateBurst                int     // global bucket size, 0 = rate rounded up
+	SessionRateLimit         float64 // per-session invocations per second, 0 = unlimited
+	SessionRateBurst         int     // per-session bucket size, 0 = rate rounded up
+	MaxConcurrentInvocations int     // in-flight invocation cap, 0 = unlimited
+	SessionMaxConcurrent     int     // per-session in-flight invocation cap, 0 = unlimited
+
+	FaultInjection *faultInjectionConfig // simulated failure profile; nil = off
+
+	Compression        bool // compress responses for clients that accept gzip or deflate
+	CompressionLevel   int  // 1 (fastest) to 9 (smallest), 0 = default level
+	CompressionMinSize int  // smallest body compressed in bytes, 0 = defaultCompressionMinSize
+	CompressionSSE     bool // also compress SSE streams
+
+	ResponseModeration bool // enforce the prompt's banned_words and regex validators on responses
+
+	CORSAllowedOrigins []string      // origins browsers may call the bridge from; empty = CORS off
+	CORSAllowedHeaders []string      // request headers preflights allow; empty = corsDefaultAllowedHeader
+	CORSMaxAge         time.Duration // how long browsers may cache a preflight, 0 = unset
+
+	PushAllowedHosts []string // push notification hosts allowed to resolve to internal addresses
 }
 
 // Protocol mode constants matching adapter-side values.
@@ -74,34 +202,75 @@ func (c *runtimeConfig) wantA2AServer() bool {
 	return c.Protocol == "" || c.Protocol == protocolBoth || c.Protocol == protocolA2A
 }
 
-// loadConfig reads configuration from environment variables.
-// PROMPTPACK_FILE is required; all others have sensible defaults.
+// bundledPackFile returns the path of a pack.json beside the running
+// executable, or "" if there is none. The binary code deploy layout has no
+// wrapper to set PROMPTPACK_FILE, so the runtime looks for it here.
+func bundledPackFile() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(filepath.Dir(exe), bundledPackName)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadConfig reads configuration from environment variables layered over the
+// optional RUNTIME_CONFIG_FILE. PROMPTPACK_FILE is required unless the pack
+// JSON is inlined or bundled beside the binary; all others have sensible
+// defaults.
 func loadConfig() (*runtimeConfig, error) {
+	src, err := newConfigSource()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &runtimeConfig{
-		PackFile:        os.Getenv(envPackFile),
-		PackJSON:        os.Getenv(envPackJSON),
-		AgentName:       os.Getenv(envAgentName),
-		Protocol:        os.Getenv(envProtocol),
-		AWSRegion:       os.Getenv(envAWSRegion),
-		MemoryStore:     os.Getenv(envMemoryStore),
-		MemoryID:        os.Getenv(envMemoryID),
-		A2AAuthMode:     os.Getenv(envA2AAuthMode),
-		A2AAuthRole:     os.Getenv(envA2AAuthRole),
-		PolicyEngineARN: os.Getenv(envPolicyEngineARN),
-		MetricsConfig:   os.Getenv(envMetricsConfig),
-		DashboardConfig: os.Getenv(envDashboardConfig),
-		LogGroup:        os.Getenv(envLogGroup),
-		OTLPEndpoint:    os.Getenv(envOTLPEndpoint),
-		ProviderType:    os.Getenv(envProviderType),
-		Model:           os.Getenv(envProviderModel),
-		Port:            defaultPort,
+		PackFile:         src.get(envPackFile),
+		PackJSON:         src.get(envPackJSON),
+		AgentName:        src.get(envAgentName),
+		Protocol:         src.get(envProtocol),
+		AWSRegion:        src.get(envAWSRegion),
+		MemoryStore:      src.get(envMemoryStore),
+		MemoryID:         src.get(envMemoryID),
+		MemoryNamespace:  src.get(envMemoryNamespace),
+		A2AAuthMode:      src.get(envA2AAuthMode),
+		A2AAuthRole:      src.get(envA2AAuthRole),
+		PolicyEngineARN:  src.get(envPolicyEngineARN),
+		MetricsConfig:    src.get(envMetricsConfig),
+		DashboardConfig:  src.get(envDashboardConfig),
+		LogGroup:         src.get(envLogGroup),
+		OTLPEndpoint:     src.get(envOTLPEndpoint),
+		ServiceName:      src.get(envServiceName),
+		ProviderType:     src.get(envProviderType),
+		Model:            src.get(envProviderModel),
+		InferenceProfile: src.get(envInferenceProfile),
+		GatewaySearch:    src.get(envGatewaySearch),
+		AllowedTools:     splitList(src.get(envAllowedTools)),
+		LogRedaction:     src.get(envLogRedaction),
+
+		PreInvokeWebhookURL:  src.get(envPreInvokeWebhook),
+		PostInvokeWebhookURL: src.get(envPostInvokeWebhook),
+		WebhookSecret:        src.get(envWebhookSecret),
+		SessionStore:         src.get(envSessionStore),
+		SessionFile:          src.get(envSessionFile),
+		A2ATaskStore:         src.get(envA2ATaskStore),
+		HistoryStrategy:      src.get(envHistoryStrategy),
+		Port:                 defaultPort,
+		LogSampleRate:        defaultLogSampleRate,
 	}
 
 	if cfg.PackFile == "" && cfg.PackJSON == "" {
-		return nil, fmt.Errorf("%s or %s is required", envPackFile, envPackJSON)
+		cfg.PackFile = bundledPackFile()
+	}
+	if cfg.PackFile == "" && cfg.PackJSON == "" {
+		return nil, fmt.Errorf("%s or %s is required (or a %s next to the binary)",
+			envPackFile, envPackJSON, bundledPackName)
 	}
 
-	if portStr := os.Getenv(envPort); portStr != "" {
+	if portStr := src.get(envPort); portStr != "" {
 		port, err := strconv.Atoi(portStr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s %q: %w", envPort, portStr, err)
@@ -109,7 +278,7 @@ func loadConfig() (*runtimeConfig, error) {
 		cfg.Port = port
 	}
 
-	if tracingStr := os.Getenv(envTracingEnabled); tracingStr != "" {
+	if tracingStr := src.get(envTracingEnabled); tracingStr != "" {
 		enabled, err := strconv.ParseBool(tracingStr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid %s %q: %w", envTracingEnabled, tracingStr, err)
@@ -117,13 +286,322 @@ func loadConfig() (*runtimeConfig, error) {
 		cfg.TracingEnabled = enabled
 	}
 
-	if agentsJSON := os.Getenv(envAgentEndpoints); agentsJSON != "" {
+	parsers := []func(configSource, *runtimeConfig) error{
+		parseJSONSettings,
+		parseLogSettings,
+		parseToolAuditSettings,
+		parseSessionSettings,
+		parseHistorySettings,
+		parseLimitSettings,
+		parseCompressionSettings,
+		parseModerationSettings,
+		parseCORSSettings,
+	}
+	for _, parse := range parsers {
+		if err := parse(src, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	durations := []struct {
+		env string
+		dst *time.Duration
+	}{
+		{envWSPingInterval, &cfg.WSPingInterval},
+		{envWSIdleTimeout, &cfg.WSIdleTimeout},
+		{envSSEHeartbeat, &cfg.SSEHeartbeatInterval},
+		{envWebhookTimeout, &cfg.WebhookTimeout},
+		{envCORSMaxAge, &cfg.CORSMaxAge},
+	}
+	for _, d := range durations {
+		if err := parseDurationEnv(src, d.env, d.dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseDurationEnv parses the named setting as a positive Go duration into
+// dst. An unset setting leaves dst untouched.
+func parseDurationEnv(src configSource, name string, dst *time.Duration) error {
+	raw := src.get(name)
+	if raw == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid %s %q: must be positive", name, raw)
+	}
+	*dst = d
+	return nil
+}
+
+// parseJSONSettings decodes the settings that carry JSON: the A2A agent
+// endpoint map, the agent card overrides, and the fault injection profile.
+func parseJSONSettings(src configSource, cfg *runtimeConfig) error {
+	if agentsJSON := src.get(envAgentEndpoints); agentsJSON != "" {
 		endpoints := make(map[string]string)
 		if err := json.Unmarshal([]byte(agentsJSON), &endpoints); err != nil {
-			return nil, fmt.Errorf("invalid %s JSON: %w", envAgentEndpoints, err)
+			return fmt.Errorf("invalid %s JSON: %w", envAgentEndpoints, err)
 		}
 		cfg.AgentEndpoints = endpoints
 	}
+	if cardJSON := src.get(envAgentCard); cardJSON != "" {
+		card := &agentcore.AgentCardConfig{}
+		if err := json.Unmarshal([]byte(cardJSON), card); err != nil {
+			return fmt.Errorf("invalid %s JSON: %w", envAgentCard, err)
+		}
+		cfg.AgentCard = card
+	}
+	if faultJSON := src.get(envFaultInjection); faultJSON != "" {
+		faults := &faultInjectionConfig{}
+		if err := json.Unmarshal([]byte(faultJSON), faults); err != nil {
+			return fmt.Errorf("invalid %s JSON: %w", envFaultInjection, err)
+		}
+		if err := faults.validate(); err != nil {
+			return fmt.Errorf("invalid %s: %w", envFaultInjection, err)
+		}
+		cfg.FaultInjection = faults
+	}
+	return nil
+}
 
-	return cfg, nil
+// parseToolAuditSettings reads the tool audit toggle and per-session cap.
+func parseToolAuditSettings(src configSource, cfg *runtimeConfig) error {
+	if raw := src.get(envToolAudit); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", envToolAudit, raw, err)
+		}
+		cfg.ToolAudit = enabled
+	}
+	if raw := src.get(envToolAuditMax); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q: must be a positive integer", envToolAuditMax, raw)
+		}
+		cfg.ToolAuditMaxEvents = n
+	}
+	return nil
+}
+
+// parseSessionSettings validates the session store, turn limit, and daily
+// token budget.
+func parseSessionSettings(src configSource, cfg *runtimeConfig) error {
+	if cfg.SessionStore != "" && cfg.SessionStore != agentcore.SessionStoreMemory {
+		return fmt.Errorf("invalid %s %q: must be %q", envSessionStore, cfg.SessionStore, agentcore.SessionStoreMemory)
+	}
+	if cfg.A2ATaskStore != "" && cfg.A2ATaskStore != agentcore.A2ATaskStoreMemory {
+		return fmt.Errorf("invalid %s %q: must be %q", envA2ATaskStore, cfg.A2ATaskStore, agentcore.A2ATaskStoreMemory)
+	}
+	counts := []struct {
+		env string
+		dst *int
+	}{
+		{envSessionMaxTurns, &cfg.SessionMaxTurns},
+		{envSessionBudget, &cfg.SessionDailyTokenBudget},
+	}
+	for _, c := range counts {
+		raw := src.get(c.env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q: must be a positive integer", c.env, raw)
+		}
+		*c.dst = n
+	}
+	return nil
+}
+
+// parseHistorySettings reads the conversation history limits and checks
+// that the summarize strategy has a turn window to summarize outside of.
+func parseHistorySettings(src configSource, cfg *runtimeConfig) error {
+	counts := []struct {
+		env string
+		dst *int
+	}{
+		{envHistoryMaxTurns, &cfg.HistoryMaxTurns},
+		{envHistoryMaxTokens, &cfg.HistoryMaxTokens},
+	}
+	for _, c := range counts {
+		raw := src.get(c.env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q: must be a positive integer", c.env, raw)
+		}
+		*c.dst = n
+	}
+
+	switch cfg.HistoryStrategy {
+	case "", historyTruncate:
+		return nil
+	case historySummarize:
+		if cfg.HistoryMaxTurns == 0 {
+			return fmt.Errorf("%s %q requires %s", envHistoryStrategy, historySummarize, envHistoryMaxTurns)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid %s %q: must be %q or %q",
+			envHistoryStrategy, cfg.HistoryStrategy, historyTruncate, historySummarize)
+	}
+}
+
+// parseLimitSettings reads the invocation rate limits and concurrency caps.
+func parseLimitSettings(src configSource, cfg *runtimeConfig) error {
+	rates := []struct {
+		env string
+		dst *float64
+	}{
+		{envRateLimit, &cfg.RateLimit},
+		{envSessionRateLimit, &cfg.SessionRateLimit},
+	}
+	for _, r := range rates {
+		raw := src.get(r.env)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || !(v > 0) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid %s %q: must be a positive number", r.env, raw)
+		}
+		*r.dst = v
+	}
+
+	counts := []struct {
+		env string
+		dst *int
+	}{
+		{envRateBurst, &cfg.RateBurst},
+		{envSessionRateBurst, &cfg.SessionRateBurst},
+		{envMaxConcurrent, &cfg.MaxConcurrentInvocations},
+		{envSessionMaxConc, &cfg.SessionMaxConcurrent},
+	}
+	for _, c := range counts {
+		raw := src.get(c.env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q: must be a positive integer", c.env, raw)
+		}
+		*c.dst = n
+	}
+	return nil
+}
+
+// parseLogSettings validates the access log sample rate and redaction mode
+// and reads the response timings toggle.
+func parseLogSettings(src configSource, cfg *runtimeConfig) error {
+	if rateStr := src.get(envLogSampleRate); rateStr != "" {
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return fmt.Errorf("invalid %s %q: must be a number between 0 and 1", envLogSampleRate, rateStr)
+		}
+		cfg.LogSampleRate = rate
+	}
+	if raw := src.get(envResponseTimings); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", envResponseTimings, raw, err)
+		}
+		cfg.ResponseTimings = enabled
+	}
+
+	switch cfg.LogRedaction {
+	case "", redactHash, redactTruncate, redactNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid %s %q: must be %q, %q, or %q",
+			envLogRedaction, cfg.LogRedaction, redactHash, redactTruncate, redactNone)
+	}
+}
+
+// parseModerationSettings reads the response moderation toggle.
+func parseModerationSettings(src configSource, cfg *runtimeConfig) error {
+	raw := src.get(envResponseModeration)
+	if raw == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", envResponseModeration, raw, err)
+	}
+	cfg.ResponseModeration = enabled
+	return nil
+}
+
+// parseCORSSettings reads the comma-separated CORS origin and header
+// lists, and the push notification host allowlist.
+func parseCORSSettings(src configSource, cfg *runtimeConfig) error {
+	cfg.CORSAllowedOrigins = splitList(src.get(envCORSAllowedOrigins))
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if !validCORSOrigin(origin) {
+			return fmt.Errorf("invalid %s %q: must be * or a scheme and host such as https://app.example.com",
+				envCORSAllowedOrigins, origin)
+		}
+	}
+	cfg.CORSAllowedHeaders = splitList(src.get(envCORSAllowedHeaders))
+	cfg.PushAllowedHosts = splitList(src.get(envPushAllowedHosts))
+	return nil
+}
+
+// splitList splits a comma-separated setting into its trimmed, non-empty
+// items.
+func splitList(raw string) []string {
+	var items []string
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCompressionSettings reads the response compression toggles, level,
+// and minimum body size.
+func parseCompressionSettings(src configSource, cfg *runtimeConfig) error {
+	toggles := []struct {
+		env string
+		dst *bool
+	}{
+		{envCompression, &cfg.Compression},
+		{envCompressionSSE, &cfg.CompressionSSE},
+	}
+	for _, t := range toggles {
+		raw := src.get(t.env)
+		if raw == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", t.env, raw, err)
+		}
+		*t.dst = enabled
+	}
+	if raw := src.get(envCompressionLevel); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < gzip.BestSpeed || n > gzip.BestCompression {
+			return fmt.Errorf("invalid %s %q: must be an integer from %d to %d",
+				envCompressionLevel, raw, gzip.BestSpeed, gzip.BestCompression)
+		}
+		cfg.CompressionLevel = n
+	}
+	if raw := src.get(envCompressionMinSize); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q: must be a positive integer", envCompressionMinSize, raw)
+		}
+		cfg.CompressionMinSize = n
+	}
+	return nil
 }
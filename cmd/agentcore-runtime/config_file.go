@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// envConfigFile names an optional JSON or YAML file providing defaults for
+// every runtime setting. Environment variables always take precedence.
+const envConfigFile = "RUNTIME_CONFIG_FILE"
+
+// redactedPlaceholder replaces secret-bearing values in printed config.
+const redactedPlaceholder = "REDACTED"
+
+// runtimeConfigFile is the on-disk form of the runtime configuration. Each
+// field maps to one environment variable; --print-config emits the same shape
+// so its output can be saved and reused as a config file.
+type runtimeConfigFile struct {
+	PackFile         string            `json:"pack_file,omitempty" yaml:"pack_file,omitempty"`
+	PackJSON         string            `json:"pack_json,omitempty" yaml:"pack_json,omitempty"`
+	Agent            string            `json:"agent,omitempty" yaml:"agent,omitempty"`
+	Port             *int              `json:"port,omitempty" yaml:"port,omitempty"`
+	Protocol         string            `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	AWSRegion        string            `json:"aws_region,omitempty" yaml:"aws_region,omitempty"`
+	MemoryStore      string            `json:"memory_store,omitempty" yaml:"memory_store,omitempty"`
+	MemoryID         string            `json:"memory_id,omitempty" yaml:"memory_id,omitempty"`
+	MemoryNamespace  string            `json:"memory_namespace,omitempty" yaml:"memory_namespace,omitempty"`
+	A2AAuthMode      string            `json:"a2a_auth_mode,omitempty" yaml:"a2a_auth_mode,omitempty"`
+	A2AAuthRole      string            `json:"a2a_auth_role,omitempty" yaml:"a2a_auth_role,omitempty"`
+	PolicyEngineARN  string            `json:"policy_engine_arn,omitempty" yaml:"policy_engine_arn,omitempty"`
+	MetricsConfig    string            `json:"metrics_config,omitempty" yaml:"metrics_config,omitempty"`
+	DashboardConfig  string            `json:"dashboard_config,omitempty" yaml:"dashboard_config,omitempty"`
+	LogGroup         string            `json:"log_group,omitempty" yaml:"log_group,omitempty"`
+	OTLPEndpoint     string            `json:"otlp_endpoint,omitempty" yaml:"otlp_endpoint,omitempty"`
+	TracingEnabled   *bool             `json:"tracing_enabled,omitempty" yaml:"tracing_enabled,omitempty"`
+	ServiceName      string            `json:"service_name,omitempty" yaml:"service_name,omitempty"`
+	Agents           map[string]string `json:"agents,omitempty" yaml:"agents,omitempty"`
+	ProviderType     string            `json:"provider_type,omitempty" yaml:"provider_type,omitempty"`
+	ProviderModel    string            `json:"provider_model,omitempty" yaml:"provider_model,omitempty"`
+	InferenceProfile string            `json:"inference_profile,omitempty" yaml:"inference_profile,omitempty"`
+	GatewaySearch    string            `json:"gateway_search,omitempty" yaml:"gateway_search,omitempty"`
+	AllowedTools     []string          `json:"allowed_tools,omitempty" yaml:"allowed_tools,omitempty"`
+	WSPingInterval   string            `json:"ws_ping_interval,omitempty" yaml:"ws_ping_interval,omitempty"`
+	WSIdleTimeout    string            `json:"ws_idle_timeout,omitempty" yaml:"ws_idle_timeout,omitempty"`
+	SSEHeartbeat     string            `json:"sse_heartbeat_interval,omitempty" yaml:"sse_heartbeat_interval,omitempty"`
+	LogSampleRate    *float64          `json:"log_sample_rate,omitempty" yaml:"log_sample_rate,omitempty"`
+	LogRedaction     string            `json:"log_redaction,omitempty" yaml:"log_redaction,omitempty"`
+	ResponseTimings  *bool             `json:"response_timings,omitempty" yaml:"response_timings,omitempty"`
+
+	AgentCard          *agentcore.AgentCardConfig `json:"agent_card,omitempty" yaml:"agent_card,omitempty"`
+	ToolAudit          *bool                      `json:"tool_audit,omitempty" yaml:"tool_audit,omitempty"`
+	ToolAuditMaxEvents *int                       `json:"tool_audit_max_events,omitempty" yaml:"tool_audit_max_events,omitempty"`
+
+	PreInvokeWebhookURL  string `json:"pre_invoke_webhook_url,omitempty" yaml:"pre_invoke_webhook_url,omitempty"`
+	PostInvokeWebhookURL string `json:"post_invoke_webhook_url,omitempty" yaml:"post_invoke_webhook_url,omitempty"`
+	WebhookSecret        string `json:"webhook_secret,omitempty" yaml:"webhook_secret,omitempty"`
+	WebhookTimeout       string `json:"webhook_timeout,omitempty" yaml:"webhook_timeout,omitempty"`
+
+	SessionStore    string `json:"session_store,omitempty" yaml:"session_store,omitempty"`
+	SessionFile     string `json:"session_file,omitempty" yaml:"session_file,omitempty"`
+	SessionMaxTurns *int   `json:"session_max_turns,omitempty" yaml:"session_max_turns,omitempty"`
+	SessionBudget   *int   `json:"session_daily_token_budget,omitempty" yaml:"session_daily_token_budget,omitempty"`
+	A2ATaskStore    string `json:"a2a_task_store,omitempty" yaml:"a2a_task_store,omitempty"`
+
+	HistoryMaxTurns  *int   `json:"history_max_turns,omitempty" yaml:"history_max_turns,omitempty"`
+	HistoryMaxTokens *int   `json:"history_max_tokens,omitempty" yaml:"history_max_tokens,omitempty"`
+	HistoryStrategy  string `json:"history_strategy,omitempty" yaml:"history_strategy,omitempty"`
+
+	RateLimit                *float64 `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+	RateBurst                *int     `json:"rate_burst,omitempty" yaml:"rate_burst,omitempty"`
+	SessionRateLimit         *float64 `json:"session_rate_limit,omitempty" yaml:"session_rate_limit,omitempty"`
+	SessionRateBurst         *int     `json:"session_rate_burst,omitempty" yaml:"session_rate_burst,omitempty"`
+	MaxConcurrentInvocations *int     `json:"max_concurrent_invocations,omitempty" yaml:"max_concurrent_invocations,omitempty"`
+	SessionMaxConcurrent     *int     `json:"session_max_concurrent,omitempty" yaml:"session_max_concurrent,omitempty"`
+
+	FaultInjection *faultInjectionConfig `json:"fault_injection,omitempty" yaml:"fault_injection,omitempty"`
+
+	Compression        *bool `json:"compression,omitempty" yaml:"compression,omitempty"`
+	CompressionLevel   *int  `json:"compression_level,omitempty" yaml:"compression_level,omitempty"`
+	CompressionMinSize *int  `json:"compression_min_size,omitempty" yaml:"compression_min_size,omitempty"`
+	CompressionSSE     *bool `json:"compression_sse,omitempty" yaml:"compression_sse,omitempty"`
+
+	ResponseModeration *bool `json:"response_moderation,omitempty" yaml:"response_moderation,omitempty"`
+
+	CORSAllowedOrigins []string `json:"cors_allowed_origins,omitempty" yaml:"cors_allowed_origins,omitempty"`
+	CORSAllowedHeaders []string `json:"cors_allowed_headers,omitempty" yaml:"cors_allowed_headers,omitempty"`
+	CORSMaxAge         string   `json:"cors_max_age,omitempty" yaml:"cors_max_age,omitempty"`
+
+	PushAllowedHosts []string `json:"push_notification_allowed_hosts,omitempty" yaml:"push_notification_allowed_hosts,omitempty"`
+}
+
+// configSource resolves a setting by environment variable name. A non-empty
+// environment variable wins; otherwise the config file value is used.
+type configSource struct {
+	file map[string]string
+}
+
+// get returns the effective raw value for the named environment variable.
+func (s configSource) get(name string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return s.file[name]
+}
+
+// newConfigSource loads RUNTIME_CONFIG_FILE when set and returns a source
+// that layers environment variables over it.
+func newConfigSource() (configSource, error) {
+	path := os.Getenv(envConfigFile)
+	if path == "" {
+		return configSource{}, nil
+	}
+	f, err := readConfigFile(path)
+	if err != nil {
+		return configSource{}, fmt.Errorf("%s: %w", envConfigFile, err)
+	}
+	vals, err := f.envValues()
+	if err != nil {
+		return configSource{}, fmt.Errorf("%s: %w", envConfigFile, err)
+	}
+	return configSource{file: vals}, nil
+}
+
+// readConfigFile parses a JSON (.json) or YAML (.yaml, .yml) config file.
+// Unknown keys are rejected so typos do not silently fall back to defaults.
+func readConfigFile(path string) (*runtimeConfigFile, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // operator-supplied config path
+	if err != nil {
+		return nil, err
+	}
+
+	var f runtimeConfigFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&f)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&f)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q (want .json, .yaml, or .yml)",
+			filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// envValues maps the file's settings onto their environment variable names
+// so they pass through the same parsing and validation as env values.
+func (f *runtimeConfigFile) envValues() (map[string]string, error) {
+	vals := map[string]string{
+		envPackFile:         f.PackFile,
+		envPackJSON:         f.PackJSON,
+		envAgentName:        f.Agent,
+		envProtocol:         f.Protocol,
+		envAWSRegion:        f.AWSRegion,
+		envMemoryStore:      f.MemoryStore,
+		envMemoryID:         f.MemoryID,
+		envMemoryNamespace:  f.MemoryNamespace,
+		envA2AAuthMode:      f.A2AAuthMode,
+		envA2AAuthRole:      f.A2AAuthRole,
+		envPolicyEngineARN:  f.PolicyEngineARN,
+		envMetricsConfig:    f.MetricsConfig,
+		envDashboardConfig:  f.DashboardConfig,
+		envLogGroup:         f.LogGroup,
+		envOTLPEndpoint:     f.OTLPEndpoint,
+		envServiceName:      f.ServiceName,
+		envProviderType:     f.ProviderType,
+		envProviderModel:    f.ProviderModel,
+		envInferenceProfile: f.InferenceProfile,
+		envGatewaySearch:    f.GatewaySearch,
+		envWSPingInterval:   f.WSPingInterval,
+		envWSIdleTimeout:    f.WSIdleTimeout,
+		envSSEHeartbeat:     f.SSEHeartbeat,
+		envLogRedaction:     f.LogRedaction,
+
+		envPreInvokeWebhook:  f.PreInvokeWebhookURL,
+		envPostInvokeWebhook: f.PostInvokeWebhookURL,
+		envWebhookSecret:     f.WebhookSecret,
+		envWebhookTimeout:    f.WebhookTimeout,
+
+		envSessionStore: f.SessionStore,
+		envSessionFile:  f.SessionFile,
+		envA2ATaskStore: f.A2ATaskStore,
+
+		envHistoryStrategy: f.HistoryStrategy,
+
+		envCORSMaxAge: f.CORSMaxAge,
+	}
+	if f.Port != nil {
+		vals[envPort] = strconv.Itoa(*f.Port)
+	}
+	if f.TracingEnabled != nil {
+		vals[envTracingEnabled] = strconv.FormatBool(*f.TracingEnabled)
+	}
+	if f.LogSampleRate != nil {
+		vals[envLogSampleRate] = strconv.FormatFloat(*f.LogSampleRate, 'g', -1, 64)
+	}
+	if f.ResponseTimings != nil {
+		vals[envResponseTimings] = strconv.FormatBool(*f.ResponseTimings)
+	}
+	if f.ToolAudit != nil {
+		vals[envToolAudit] = strconv.FormatBool(*f.ToolAudit)
+	}
+	if f.ToolAuditMaxEvents != nil {
+		vals[envToolAuditMax] = strconv.Itoa(*f.ToolAuditMaxEvents)
+	}
+	if f.SessionMaxTurns != nil {
+		vals[envSessionMaxTurns] = strconv.Itoa(*f.SessionMaxTurns)
+	}
+	setInt(vals, envSessionBudget, f.SessionBudget)
+	setInt(vals, envHistoryMaxTurns, f.HistoryMaxTurns)
+	setInt(vals, envHistoryMaxTokens, f.HistoryMaxTokens)
+	setFloat(vals, envRateLimit, f.RateLimit)
+	setFloat(vals, envSessionRateLimit, f.SessionRateLimit)
+	setInt(vals, envRateBurst, f.RateBurst)
+	setInt(vals, envSessionRateBurst, f.SessionRateBurst)
+	setInt(vals, envMaxConcurrent, f.MaxConcurrentInvocations)
+	setInt(vals, envSessionMaxConc, f.SessionMaxConcurrent)
+	setBool(vals, envCompression, f.Compression)
+	setInt(vals, envCompressionLevel, f.CompressionLevel)
+	setInt(vals, envCompressionMinSize, f.CompressionMinSize)
+	setBool(vals, envCompressionSSE, f.CompressionSSE)
+	setBool(vals, envResponseModeration, f.ResponseModeration)
+	setList(vals, envCORSAllowedOrigins, f.CORSAllowedOrigins)
+	setList(vals, envCORSAllowedHeaders, f.CORSAllowedHeaders)
+	setList(vals, envPushAllowedHosts, f.PushAllowedHosts)
+	setList(vals, envAllowedTools, f.AllowedTools)
+	if len(f.Agents) > 0 {
+		agents, err := json.Marshal(f.Agents)
+		if err != nil {
+			return nil, fmt.Errorf("encode agents: %w", err)
+		}
+		vals[envAgentEndpoints] = string(agents)
+	}
+	if f.AgentCard != nil {
+		card, err := json.Marshal(f.AgentCard)
+		if err != nil {
+			return nil, fmt.Errorf("encode agent_card: %w", err)
+		}
+		vals[envAgentCard] = string(card)
+	}
+	if f.FaultInjection != nil {
+		faults, err := json.Marshal(f.FaultInjection)
+		if err != nil {
+			return nil, fmt.Errorf("encode fault_injection: %w", err)
+		}
+		vals[envFaultInjection] = string(faults)
+	}
+	return vals, nil
+}
+
+// redactedConfigFile converts the effective config back into file form with
+// secrets removed: the inline pack body is replaced by its size, the
+// webhook secret is masked, and credentials embedded in the OTLP endpoint
+// and webhook URLs are masked.
+func redactedConfigFile(cfg *runtimeConfig) *runtimeConfigFile {
+	port := cfg.Port
+	tracing := cfg.TracingEnabled
+	sampleRate := cfg.LogSampleRate
+	responseTimings := cfg.ResponseTimings
+	toolAudit := cfg.ToolAudit
+	f := &runtimeConfigFile{
+		PackFile:         cfg.PackFile,
+		Agent:            cfg.AgentName,
+		Port:             &port,
+		Protocol:         cfg.Protocol,
+		AWSRegion:        cfg.AWSRegion,
+		MemoryStore:      cfg.MemoryStore,
+		MemoryID:         cfg.MemoryID,
+		MemoryNamespace:  cfg.MemoryNamespace,
+		A2AAuthMode:      cfg.A2AAuthMode,
+		A2AAuthRole:      cfg.A2AAuthRole,
+		PolicyEngineARN:  cfg.PolicyEngineARN,
+		MetricsConfig:    cfg.MetricsConfig,
+		DashboardConfig:  cfg.DashboardConfig,
+		LogGroup:         cfg.LogGroup,
+		OTLPEndpoint:     redactURL(cfg.OTLPEndpoint),
+		TracingEnabled:   &tracing,
+		ServiceName:      cfg.ServiceName,
+		Agents:           cfg.AgentEndpoints,
+		ProviderType:     cfg.ProviderType,
+		ProviderModel:    cfg.Model,
+		InferenceProfile: cfg.InferenceProfile,
+		GatewaySearch:    cfg.GatewaySearch,
+		AllowedTools:     cfg.AllowedTools,
+		LogSampleRate:    &sampleRate,
+		LogRedaction:     cfg.LogRedaction,
+		ResponseTimings:  &responseTimings,
+		AgentCard:        cfg.AgentCard,
+		ToolAudit:        &toolAudit,
+
+		PreInvokeWebhookURL:  redactURL(cfg.PreInvokeWebhookURL),
+		PostInvokeWebhookURL: redactURL(cfg.PostInvokeWebhookURL),
+
+		SessionStore: cfg.SessionStore,
+		SessionFile:  cfg.SessionFile,
+		A2ATaskStore: cfg.A2ATaskStore,
+
+		HistoryMaxTurns:  positiveInt(cfg.HistoryMaxTurns),
+		HistoryMaxTokens: positiveInt(cfg.HistoryMaxTokens),
+		HistoryStrategy:  cfg.HistoryStrategy,
+
+		FaultInjection: cfg.FaultInjection,
+	}
+	if cfg.ToolAuditMaxEvents > 0 {
+		maxEvents := cfg.ToolAuditMaxEvents
+		f.ToolAuditMaxEvents = &maxEvents
+	}
+	if cfg.SessionMaxTurns > 0 {
+		maxTurns := cfg.SessionMaxTurns
+		f.SessionMaxTurns = &maxTurns
+	}
+	f.RateLimit = positiveFloat(cfg.RateLimit)
+	f.RateBurst = positiveInt(cfg.RateBurst)
+	f.SessionRateLimit = positiveFloat(cfg.SessionRateLimit)
+	f.SessionRateBurst = positiveInt(cfg.SessionRateBurst)
+	f.MaxConcurrentInvocations = positiveInt(cfg.MaxConcurrentInvocations)
+	f.SessionMaxConcurrent = positiveInt(cfg.SessionMaxConcurrent)
+	f.SessionBudget = positiveInt(cfg.SessionDailyTokenBudget)
+	if cfg.Compression {
+		compression, compressSSE := cfg.Compression, cfg.CompressionSSE
+		f.Compression = &compression
+		f.CompressionSSE = &compressSSE
+		f.CompressionLevel = positiveInt(cfg.CompressionLevel)
+		f.CompressionMinSize = positiveInt(cfg.CompressionMinSize)
+	}
+	if cfg.ResponseModeration {
+		moderation := cfg.ResponseModeration
+		f.ResponseModeration = &moderation
+	}
+	f.CORSAllowedOrigins = cfg.CORSAllowedOrigins
+	f.CORSAllowedHeaders = cfg.CORSAllowedHeaders
+	f.PushAllowedHosts = cfg.PushAllowedHosts
+	if cfg.CORSMaxAge > 0 {
+		f.CORSMaxAge = cfg.CORSMaxAge.String()
+	}
+	if cfg.PackJSON != "" {
+		f.PackJSON = fmt.Sprintf("%s (%d bytes)", redactedPlaceholder, len(cfg.PackJSON))
+	}
+	if cfg.WSPingInterval > 0 {
+		f.WSPingInterval = cfg.WSPingInterval.String()
+	}
+	if cfg.WSIdleTimeout > 0 {
+		f.WSIdleTimeout = cfg.WSIdleTimeout.String()
+	}
+	if cfg.SSEHeartbeatInterval > 0 {
+		f.SSEHeartbeat = cfg.SSEHeartbeatInterval.String()
+	}
+	if cfg.WebhookSecret != "" {
+		f.WebhookSecret = redactedPlaceholder
+	}
+	if cfg.WebhookTimeout > 0 {
+		f.WebhookTimeout = cfg.WebhookTimeout.String()
+	}
+	return f
+}
+
+// setInt stores v under name in vals when it is set.
+func setInt(vals map[string]string, name string, v *int) {
+	if v != nil {
+		vals[name] = strconv.Itoa(*v)
+	}
+}
+
+// setBool stores v under name in vals when it is set.
+func setBool(vals map[string]string, name string, v *bool) {
+	if v != nil {
+		vals[name] = strconv.FormatBool(*v)
+	}
+}
+
+// setFloat stores v under name in vals when it is set.
+func setFloat(vals map[string]string, name string, v *float64) {
+	if v != nil {
+		vals[name] = strconv.FormatFloat(*v, 'g', -1, 64)
+	}
+}
+
+// setList stores the comma-separated items under name in vals when there
+// are any.
+func setList(vals map[string]string, name string, items []string) {
+	if len(items) > 0 {
+		vals[name] = strings.Join(items, ",")
+	}
+}
+
+// positiveInt returns a pointer to n, or nil when n is unset (zero).
+func positiveInt(n int) *int {
+	if n <= 0 {
+		return nil
+	}
+	return &n
+}
+
+// positiveFloat returns a pointer to v, or nil when v is unset (zero).
+func positiveFloat(v float64) *float64 {
+	if v <= 0 {
+		return nil
+	}
+	return &v
+}
+
+// redactURL masks the password in a URL's userinfo. Values that do not parse
+// as URLs are returned unchanged.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedPlaceholder)
+	}
+	return u.String()
+}
+
+// printConfig writes the effective, redacted configuration as indented JSON.
+func printConfig(w io.Writer, cfg *runtimeConfig) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(redactedConfigFile(cfg))
+}
+
+// runPrintConfig loads the effective configuration and prints it to w.
+// It backs the --print-config flag.
+func runPrintConfig(w io.Writer) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	return printConfig(w, cfg)
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_RequiresPack(t *testing.T) {
@@ -13,6 +16,28 @@ func TestLoadConfig_RequiresPack(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_BundledPack(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	bundled := filepath.Join(filepath.Dir(exe), bundledPackName)
+	if err := os.WriteFile(bundled, []byte(`{}`), 0o600); err != nil {
+		t.Skipf("cannot write next to test binary: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(bundled) })
+	t.Setenv(envPackFile, "")
+	t.Setenv(envPackJSON, "")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PackFile != bundled {
+		t.Errorf("PackFile = %q, want %q", cfg.PackFile, bundled)
+	}
+}
+
 func TestLoadConfig_PackJSONOnly(t *testing.T) {
 	t.Setenv(envPackFile, "")
 	t.Setenv(envPackJSON, `{"id":"test","prompts":{}}`)
@@ -132,6 +157,172 @@ func TestLoadConfig_ValidAgentsJSON(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_AgentCard(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envAgentCard, `{"display_name":"Support Bot","provider_organization":"Acme"}`)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AgentCard == nil || cfg.AgentCard.DisplayName != "Support Bot" ||
+		cfg.AgentCard.ProviderOrganization != "Acme" {
+		t.Errorf("AgentCard = %+v", cfg.AgentCard)
+	}
+
+	t.Setenv(envAgentCard, "not-json")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for invalid agent card JSON")
+	}
+}
+
+func TestLoadConfig_ToolAudit(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envToolAudit, "true")
+	t.Setenv(envToolAuditMax, "25")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ToolAudit || cfg.ToolAuditMaxEvents != 25 {
+		t.Errorf("ToolAudit = %v, ToolAuditMaxEvents = %d", cfg.ToolAudit, cfg.ToolAuditMaxEvents)
+	}
+
+	t.Setenv(envToolAuditMax, "0")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for non-positive tool audit cap")
+	}
+}
+
+func TestLoadConfig_ResponseTimings(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envResponseTimings, "true")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ResponseTimings {
+		t.Error("ResponseTimings = false, want true")
+	}
+
+	t.Setenv(envResponseTimings, "sometimes")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for invalid response timings toggle")
+	}
+}
+
+func TestLoadConfig_Sessions(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envSessionStore, "memory")
+	t.Setenv(envSessionMaxTurns, "10")
+	t.Setenv(envSessionBudget, "50000")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SessionStore != "memory" || cfg.SessionMaxTurns != 10 || cfg.SessionDailyTokenBudget != 50000 {
+		t.Errorf("SessionStore = %q, SessionMaxTurns = %d, SessionDailyTokenBudget = %d",
+			cfg.SessionStore, cfg.SessionMaxTurns, cfg.SessionDailyTokenBudget)
+	}
+
+	t.Setenv(envSessionMaxTurns, "-1")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for non-positive session turn limit")
+	}
+	t.Setenv(envSessionMaxTurns, "")
+	t.Setenv(envSessionBudget, "lots")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for a non-numeric token budget")
+	}
+	t.Setenv(envSessionBudget, "")
+	t.Setenv(envSessionMaxTurns, "")
+	t.Setenv(envSessionStore, "bolt")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for unknown session store")
+	}
+	t.Setenv(envSessionStore, "")
+	t.Setenv(envA2ATaskStore, "memory")
+	cfg, err = loadConfig()
+	if err != nil || cfg.A2ATaskStore != "memory" {
+		t.Fatalf("A2ATaskStore = %q, err = %v", cfg.A2ATaskStore, err)
+	}
+	t.Setenv(envA2ATaskStore, "dynamodb")
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("expected error for unknown a2a task store")
+	}
+}
+
+func TestLoadConfig_History(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envHistoryMaxTurns, "20")
+	t.Setenv(envHistoryMaxTokens, "8000")
+	t.Setenv(envHistoryStrategy, historySummarize)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HistoryMaxTurns != 20 || cfg.HistoryMaxTokens != 8000 || cfg.HistoryStrategy != historySummarize {
+		t.Errorf("history = %d turns, %d tokens, %q", cfg.HistoryMaxTurns, cfg.HistoryMaxTokens, cfg.HistoryStrategy)
+	}
+
+	for _, tt := range []struct{ env, val string }{
+		{envHistoryMaxTurns, "0"},
+		{envHistoryMaxTokens, "many"},
+		{envHistoryStrategy, "forget"},
+	} {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, tt.val)
+			if _, err := loadConfig(); err == nil {
+				t.Errorf("expected error for %s=%q", tt.env, tt.val)
+			}
+		})
+	}
+
+	t.Setenv(envHistoryMaxTurns, "")
+	if _, err := loadConfig(); err == nil {
+		t.Error("expected error for summarize without a turn limit")
+	}
+}
+
+func TestLoadConfig_Limits(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envRateLimit, "2.5")
+	t.Setenv(envSessionRateLimit, "0.5")
+	t.Setenv(envSessionRateBurst, "3")
+	t.Setenv(envMaxConcurrent, "8")
+	t.Setenv(envSessionMaxConc, "2")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RateLimit != 2.5 || cfg.RateBurst != 0 || cfg.SessionRateLimit != 0.5 ||
+		cfg.SessionRateBurst != 3 || cfg.MaxConcurrentInvocations != 8 || cfg.SessionMaxConcurrent != 2 {
+		t.Errorf("limits = %v/%d, session %v/%d, concurrent %d/%d", cfg.RateLimit, cfg.RateBurst,
+			cfg.SessionRateLimit, cfg.SessionRateBurst, cfg.MaxConcurrentInvocations, cfg.SessionMaxConcurrent)
+	}
+
+	for _, tt := range []struct{ env, val string }{
+		{envRateLimit, "0"},
+		{envRateLimit, "NaN"},
+		{envSessionRateLimit, "fast"},
+		{envRateBurst, "-1"},
+		{envMaxConcurrent, "1.5"},
+		{envSessionMaxConc, "0"},
+	} {
+		t.Run(tt.env+"="+tt.val, func(t *testing.T) {
+			t.Setenv(tt.env, tt.val)
+			if _, err := loadConfig(); err == nil {
+				t.Errorf("expected error for %s=%q", tt.env, tt.val)
+			}
+		})
+	}
+}
+
 func TestLoadConfig_CustomPort(t *testing.T) {
 	t.Setenv(envPackFile, "test.pack.json")
 	t.Setenv(envPort, "8080")
@@ -153,6 +344,7 @@ func TestLoadConfig_TracingEnabled(t *testing.T) {
 	t.Setenv(envTracingEnabled, "true")
 	t.Setenv(envAgentEndpoints, "")
 	t.Setenv(envOTLPEndpoint, "http://collector:4318")
+	t.Setenv(envServiceName, "")
 
 	cfg, err := loadConfig()
 	if err != nil {
@@ -164,6 +356,17 @@ func TestLoadConfig_TracingEnabled(t *testing.T) {
 	if cfg.OTLPEndpoint != "http://collector:4318" {
 		t.Errorf("OTLPEndpoint = %q, want %q", cfg.OTLPEndpoint, "http://collector:4318")
 	}
+	if cfg.ServiceName != "" {
+		t.Errorf("ServiceName = %q, want the default", cfg.ServiceName)
+	}
+
+	t.Setenv(envServiceName, "worker.DEFAULT")
+	if cfg, err = loadConfig(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ServiceName != "worker.DEFAULT" {
+		t.Errorf("ServiceName = %q, want %q", cfg.ServiceName, "worker.DEFAULT")
+	}
 }
 
 func TestLoadConfig_AllEnvVars(t *testing.T) {
@@ -174,6 +377,7 @@ func TestLoadConfig_AllEnvVars(t *testing.T) {
 	t.Setenv(envAWSRegion, "us-east-1")
 	t.Setenv(envMemoryStore, "dynamodb")
 	t.Setenv(envMemoryID, "mem-123")
+	t.Setenv(envMemoryNamespace, "support/worker")
 	t.Setenv(envA2AAuthMode, "iam")
 	t.Setenv(envA2AAuthRole, "arn:aws:iam::123:role/test")
 	t.Setenv(envPolicyEngineARN, "arn:aws:cedar:policy")
@@ -204,6 +408,9 @@ func TestLoadConfig_AllEnvVars(t *testing.T) {
 	if cfg.MemoryStore != "dynamodb" {
 		t.Errorf("MemoryStore = %q, want %q", cfg.MemoryStore, "dynamodb")
 	}
+	if cfg.MemoryNamespace != "support/worker" {
+		t.Errorf("MemoryNamespace = %q, want %q", cfg.MemoryNamespace, "support/worker")
+	}
 	if cfg.A2AAuthMode != "iam" {
 		t.Errorf("A2AAuthMode = %q, want %q", cfg.A2AAuthMode, "iam")
 	}
@@ -253,3 +460,92 @@ func TestWantA2AServer(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig_WSKeepalive(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envWSPingInterval, "15s")
+	t.Setenv(envWSIdleTimeout, "5m")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.WSPingInterval != 15*time.Second {
+		t.Errorf("WSPingInterval = %v, want 15s", cfg.WSPingInterval)
+	}
+	if cfg.WSIdleTimeout != 5*time.Minute {
+		t.Errorf("WSIdleTimeout = %v, want 5m", cfg.WSIdleTimeout)
+	}
+}
+
+func TestLoadConfig_InvalidWSDurations(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		val  string
+	}{
+		{"unparseable ping", envWSPingInterval, "soon"},
+		{"negative ping", envWSPingInterval, "-1s"},
+		{"zero idle", envWSIdleTimeout, "0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envPackFile, "test.pack.json")
+			t.Setenv(tt.env, tt.val)
+			if _, err := loadConfig(); err == nil {
+				t.Errorf("expected error for %s=%q", tt.env, tt.val)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_LogSettings(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+	t.Setenv(envLogSampleRate, "0.25")
+	t.Setenv(envLogRedaction, "truncate")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LogSampleRate != 0.25 {
+		t.Errorf("LogSampleRate = %v, want 0.25", cfg.LogSampleRate)
+	}
+	if cfg.LogRedaction != redactTruncate {
+		t.Errorf("LogRedaction = %q, want %q", cfg.LogRedaction, redactTruncate)
+	}
+}
+
+func TestLoadConfig_LogSettingsDefaults(t *testing.T) {
+	t.Setenv(envPackFile, "test.pack.json")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LogSampleRate != defaultLogSampleRate {
+		t.Errorf("LogSampleRate = %v, want %v", cfg.LogSampleRate, defaultLogSampleRate)
+	}
+}
+
+func TestLoadConfig_InvalidLogSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		val  string
+	}{
+		{"rate not a number", envLogSampleRate, "often"},
+		{"rate above one", envLogSampleRate, "1.5"},
+		{"rate negative", envLogSampleRate, "-0.1"},
+		{"unknown redaction", envLogRedaction, "scramble"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envPackFile, "test.pack.json")
+			t.Setenv(tt.env, tt.val)
+			if _, err := loadConfig(); err == nil {
+				t.Errorf("expected error for %s=%q", tt.env, tt.val)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTP headers of the CORS protocol.
+const (
+	originHeader             = "Origin"
+	allowOriginHeader        = "Access-Control-Allow-Origin"
+	allowMethodsHeader       = "Access-Control-Allow-Methods"
+	allowHeadersHeader       = "Access-Control-Allow-Headers"
+	maxAgeHeader             = "Access-Control-Max-Age"
+	requestMethodHeader      = "Access-Control-Request-Method"
+	corsAllowedMethodsValue  = "GET, POST, OPTIONS"
+	corsAnyOrigin            = "*"
+	corsDefaultAllowedHeader = "Content-Type, Authorization, " + sessionHeader
+)
+
+// corsPolicy answers CORS preflights and marks the responses of allowed
+// origins, so browser apps can call the bridge directly.
+type corsPolicy struct {
+	origins []string // allowed origins; "*" allows any
+	headers string   // Access-Control-Allow-Headers value
+	maxAge  time.Duration
+}
+
+// buildCORSPolicy returns the policy configured in cfg, or nil when no
+// origin is allowed and CORS is off.
+func buildCORSPolicy(cfg *runtimeConfig, log *slog.Logger) *corsPolicy {
+	if len(cfg.CORSAllowedOrigins) == 0 {
+		return nil
+	}
+	c := &corsPolicy{origins: cfg.CORSAllowedOrigins, headers: corsDefaultAllowedHeader, maxAge: cfg.CORSMaxAge}
+	if len(cfg.CORSAllowedHeaders) > 0 {
+		c.headers = strings.Join(cfg.CORSAllowedHeaders, ", ")
+	}
+	log.Info("cors enabled", "origins", c.origins, "headers", c.headers, "max_age", c.maxAge)
+	return c
+}
+
+// allows reports whether origin may call the bridge.
+func (c *corsPolicy) allows(origin string) bool {
+	return slices.Contains(c.origins, corsAnyOrigin) || slices.Contains(c.origins, origin)
+}
+
+// checkOrigin is the WebSocket upgrader's origin check: requests without
+// an Origin header, sent by non-browser clients, are always accepted. A nil
+// policy accepts every origin.
+func (c *corsPolicy) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get(originHeader)
+	return c == nil || origin == "" || c.allows(origin)
+}
+
+// wrap answers preflight requests for next's path and adds the allow
+// headers to its responses for allowed origins. A preflight from another
+// origin gets 403. A nil policy returns next unchanged.
+func (c *corsPolicy) wrap(next http.Handler) http.Handler {
+	if c == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get(originHeader)
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add(varyHeader, originHeader)
+		preflight := r.Method == http.MethodOptions && r.Header.Get(requestMethodHeader) != ""
+		if !c.allows(origin) {
+			if preflight {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set(allowOriginHeader, origin)
+		if !preflight {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set(allowMethodsHeader, corsAllowedMethodsValue)
+		w.Header().Set(allowHeadersHeader, c.headers)
+		if c.maxAge > 0 {
+			w.Header().Set(maxAgeHeader, strconv.Itoa(int(c.maxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// validCORSOrigin reports whether origin is "*" or a scheme and host with
+// no path, as browsers send it in the Origin header.
+func validCORSOrigin(origin string) bool {
+	if origin == corsAnyOrigin {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
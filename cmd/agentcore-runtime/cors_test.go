@@ -0,0 +1,171 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testOrigin = "https://app.example.com"
+
+func TestLoadConfig_CORS(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(envConfigFile, writeConfigFile(t, "runtime.yaml", `
+pack_file: file.pack.json
+cors_allowed_origins: [https://app.example.com, "http://localhost:3000"]
+cors_allowed_headers: [Content-Type, X-Trace-Id]
+cors_max_age: 10m
+`))
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(cfg.CORSAllowedOrigins, ",") != "https://app.example.com,http://localhost:3000" ||
+		strings.Join(cfg.CORSAllowedHeaders, ",") != "Content-Type,X-Trace-Id" || cfg.CORSMaxAge != 10*time.Minute {
+		t.Errorf("cors = %v %v %v", cfg.CORSAllowedOrigins, cfg.CORSAllowedHeaders, cfg.CORSMaxAge)
+	}
+	if f := redactedConfigFile(cfg); len(f.CORSAllowedOrigins) != 2 || f.CORSMaxAge != "10m0s" {
+		t.Errorf("printed cors = %v %q", f.CORSAllowedOrigins, f.CORSMaxAge)
+	}
+
+	t.Setenv(envCORSAllowedOrigins, " * , ")
+	if cfg, err = loadConfig(); err != nil || strings.Join(cfg.CORSAllowedOrigins, ",") != corsAnyOrigin {
+		t.Errorf("origins = %v, %v, want the environment's [*]", cfg.CORSAllowedOrigins, err)
+	}
+
+	for env, raw := range map[string]string{
+		envCORSAllowedOrigins: "https://app.example.com/path",
+		envCORSMaxAge:         "soon",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, raw)
+			if _, err := loadConfig(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("err = %v, want an error naming %s", err, env)
+			}
+		})
+	}
+}
+
+func TestValidCORSOrigin(t *testing.T) {
+	tests := map[string]bool{
+		"*":                        true,
+		"https://app.example.com":  true,
+		"http://localhost:3000":    true,
+		"app.example.com":          false,
+		"ftp://app.example.com":    false,
+		"https://app.example.com/": false,
+		"https://u:p@example.com":  false,
+		"":                         false,
+	}
+	for origin, want := range tests {
+		if got := validCORSOrigin(origin); got != want {
+			t.Errorf("validCORSOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+}
+
+func TestBuildCORSPolicy_Off(t *testing.T) {
+	if c := buildCORSPolicy(&runtimeConfig{}, slog.New(slog.DiscardHandler)); c != nil {
+		t.Errorf("policy = %+v, want nil without origins", c)
+	}
+	var c *corsPolicy
+	h := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	if got := c.wrap(h); got == nil {
+		t.Error("nil policy dropped the handler")
+	}
+	if !c.checkOrigin(httptest.NewRequest(http.MethodGet, wsPath, nil)) {
+		t.Error("nil policy rejected a WebSocket origin")
+	}
+}
+
+func TestCORSPolicy_Wrap(t *testing.T) {
+	c := buildCORSPolicy(&runtimeConfig{CORSAllowedOrigins: []string{testOrigin}, CORSMaxAge: 90 * time.Second},
+		slog.New(slog.DiscardHandler))
+	h := c.wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, invocationsPath, nil)
+		if origin != "" {
+			r.Header.Set(originHeader, origin)
+		}
+		if preflight {
+			r.Header.Set(requestMethodHeader, http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := serve(http.MethodOptions, testOrigin, true)
+	if rec.Code != http.StatusNoContent || rec.Header().Get(allowOriginHeader) != testOrigin ||
+		rec.Header().Get(allowMethodsHeader) != corsAllowedMethodsValue ||
+		!strings.Contains(rec.Header().Get(allowHeadersHeader), sessionHeader) ||
+		rec.Header().Get(maxAgeHeader) != "90" || rec.Header().Get(varyHeader) != originHeader {
+		t.Errorf("preflight = %d %v", rec.Code, rec.Header())
+	}
+
+	rec = serve(http.MethodPost, testOrigin, false)
+	if rec.Code != http.StatusOK || rec.Header().Get(allowOriginHeader) != testOrigin ||
+		rec.Header().Get(allowMethodsHeader) != "" {
+		t.Errorf("allowed POST = %d %v", rec.Code, rec.Header())
+	}
+
+	if rec = serve(http.MethodOptions, "https://evil.example.com", true); rec.Code != http.StatusForbidden {
+		t.Errorf("preflight from another origin = %d, want 403", rec.Code)
+	}
+	rec = serve(http.MethodPost, "https://evil.example.com", false)
+	if rec.Code != http.StatusOK || rec.Header().Get(allowOriginHeader) != "" {
+		t.Errorf("POST from another origin = %d %v, want no allow header", rec.Code, rec.Header())
+	}
+	if rec = serve(http.MethodPost, "", false); rec.Header().Get(varyHeader) != "" {
+		t.Errorf("request without Origin got CORS headers: %v", rec.Header())
+	}
+}
+
+func TestCORSPolicy_CustomHeaders(t *testing.T) {
+	c := buildCORSPolicy(&runtimeConfig{
+		CORSAllowedOrigins: []string{corsAnyOrigin}, CORSAllowedHeaders: []string{"Content-Type", "X-Trace-Id"},
+	}, slog.New(slog.DiscardHandler))
+	r := httptest.NewRequest(http.MethodOptions, pingPath, nil)
+	r.Header.Set(originHeader, "https://any.example.com")
+	r.Header.Set(requestMethodHeader, http.MethodGet)
+	rec := httptest.NewRecorder()
+	c.wrap(http.NotFoundHandler()).ServeHTTP(rec, r)
+	if rec.Header().Get(allowOriginHeader) != "https://any.example.com" ||
+		rec.Header().Get(allowHeadersHeader) != "Content-Type, X-Trace-Id" || rec.Header().Get(maxAgeHeader) != "" {
+		t.Errorf("preflight = %v", rec.Header())
+	}
+}
+
+func TestWSBridge_CORSOrigin(t *testing.T) {
+	b := &httpBridge{log: slog.Default(), cors: &corsPolicy{origins: []string{testOrigin}}}
+	mux := http.NewServeMux()
+	mux.HandleFunc(wsPath, b.handleWebSocket)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + wsPath
+
+	for origin, want := range map[string]bool{testOrigin: true, "https://evil.example.com": false, "": true} {
+		header := http.Header{}
+		if origin != "" {
+			header.Set(originHeader, origin)
+		}
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		if conn != nil {
+			_ = conn.Close()
+		}
+		if (err == nil) != want {
+			t.Errorf("dial from %q: err = %v, want accepted %v", origin, err, want)
+		}
+	}
+}
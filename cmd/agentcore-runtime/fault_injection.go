@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// Fault kinds, reported in the faultHeader of an affected response.
+const (
+	faultDelay = "delay" // the invocation is held before it is served
+	faultDrop  = "drop"  // the SSE stream is cut after its first event
+	faultError = "error" // the invocation fails with a 5xx without reaching the agent
+)
+
+// faultHeader marks a response a fault was injected into, so load tests can
+// tell injected failures from real ones.
+const faultHeader = "X-PromptPack-Fault"
+
+// msgInjectedFault is the response of an invocation failed by fault
+// injection.
+const msgInjectedFault = "injected fault"
+
+// defaultFaultStatus is the status of injected errors when none is set.
+const defaultFaultStatus = http.StatusServiceUnavailable
+
+// faultInjectionConfig is the PROMPTPACK_FAULT_INJECTION failure profile.
+// Each probability is the fraction, 0 to 1, of /invocations requests that
+// get the fault; they are rolled independently.
+type faultInjectionConfig struct {
+	DelayProbability float64 `json:"delay_probability,omitempty" yaml:"delay_probability,omitempty"`
+	Delay            string  `json:"delay,omitempty" yaml:"delay,omitempty"`
+	DropProbability  float64 `json:"drop_probability,omitempty" yaml:"drop_probability,omitempty"`
+	ErrorProbability float64 `json:"error_probability,omitempty" yaml:"error_probability,omitempty"`
+	ErrorStatus      int     `json:"error_status,omitempty" yaml:"error_status,omitempty"`
+
+	// delay is Delay parsed by validate.
+	delay time.Duration
+}
+
+// validate checks the probabilities and error status and parses the delay,
+// which is required when delays are injected.
+func (c *faultInjectionConfig) validate() error {
+	probabilities := []struct {
+		name string
+		v    float64
+	}{
+		{"delay_probability", c.DelayProbability},
+		{"drop_probability", c.DropProbability},
+		{"error_probability", c.ErrorProbability},
+	}
+	for _, p := range probabilities {
+		if p.v < 0 || p.v > 1 {
+			return fmt.Errorf("%s %v must be between 0 and 1", p.name, p.v)
+		}
+	}
+	if c.ErrorStatus != 0 && (c.ErrorStatus < http.StatusInternalServerError || c.ErrorStatus > 599) {
+		return fmt.Errorf("error_status %d must be a 5xx status", c.ErrorStatus)
+	}
+	if c.Delay == "" {
+		if c.DelayProbability > 0 {
+			return fmt.Errorf("delay is required with delay_probability")
+		}
+		return nil
+	}
+	d, err := time.ParseDuration(c.Delay)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("delay %q must be a positive duration", c.Delay)
+	}
+	c.delay = d
+	return nil
+}
+
+// faultInjector injects the configured failures into /invocations, for
+// testing clients and load against a deployed agent without touching the
+// model provider.
+type faultInjector struct {
+	cfg faultInjectionConfig
+	log *slog.Logger
+
+	// roll returns a number in [0, 1) for each fault decision; nil uses
+	// math/rand.
+	roll func() float64
+}
+
+// buildFaultInjector returns the injector configured in cfg, or nil when
+// fault injection is off.
+func buildFaultInjector(cfg *runtimeConfig, log *slog.Logger) *faultInjector {
+	fc := cfg.FaultInjection
+	if fc == nil || (fc.DelayProbability == 0 && fc.DropProbability == 0 && fc.ErrorProbability == 0) {
+		return nil
+	}
+	log.Warn("fault injection enabled",
+		"delay_probability", fc.DelayProbability, "delay", fc.delay,
+		"drop_probability", fc.DropProbability,
+		"error_probability", fc.ErrorProbability, "error_status", fc.ErrorStatus)
+	return &faultInjector{cfg: *fc, log: log}
+}
+
+// hit reports whether a fault with probability p applies to this request.
+func (f *faultInjector) hit(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	if f.roll != nil {
+		return f.roll() < p
+	}
+	return rand.Float64() < p //nolint:gosec // G404: fault sampling, not security sensitive.
+}
+
+// errorStatus returns the status of injected errors.
+func (f *faultInjector) errorStatus() int {
+	if f.cfg.ErrorStatus != 0 {
+		return f.cfg.ErrorStatus
+	}
+	return defaultFaultStatus
+}
+
+// wrap applies the failure profile to next. An injected error is returned
+// before the request reaches the agent; a delay holds the request, and any
+// concurrency slot it took, until it elapses or the client goes away; a
+// drop cuts an SSE response after its first event. A nil injector returns
+// next unchanged.
+func (f *faultInjector) wrap(next http.Handler) http.Handler {
+	if f == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.hit(f.cfg.ErrorProbability) {
+			f.log.Info("fault injected", "fault", faultError, "status", f.errorStatus())
+			w.Header().Set(faultHeader, faultError)
+			writeInvocationStatus(w, f.errorStatus(), msgInjectedFault)
+			return
+		}
+		if f.hit(f.cfg.DelayProbability) {
+			f.log.Info("fault injected", "fault", faultDelay, "delay", f.cfg.delay)
+			w.Header().Add(faultHeader, faultDelay)
+			timer := time.NewTimer(f.cfg.delay)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+		if wantsSSE(r) && f.hit(f.cfg.DropProbability) {
+			f.log.Info("fault injected", "fault", faultDrop)
+			w.Header().Add(faultHeader, faultDrop)
+			w = &droppingWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// droppingWriter aborts the connection once an SSE stream's first event
+// has been flushed. The first flush sends the headers.
+type droppingWriter struct {
+	http.ResponseWriter
+	flushes int
+}
+
+// Flush implements http.Flusher. After the first event it panics with
+// http.ErrAbortHandler, which makes the server close the connection without
+// ending the response, as a network failure would.
+func (d *droppingWriter) Flush() {
+	if f, ok := d.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	d.flushes++
+	if d.flushes > 1 {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (d *droppingWriter) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
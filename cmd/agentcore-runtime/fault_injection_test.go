@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestFaultInjector(t *testing.T, fc faultInjectionConfig, roll float64) *faultInjector {
+	t.Helper()
+	if err := fc.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	f := buildFaultInjector(&runtimeConfig{FaultInjection: &fc}, slog.New(slog.DiscardHandler))
+	f.roll = func() float64 { return roll }
+	return f
+}
+
+func TestLoadConfig_FaultInjection(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(envConfigFile, writeConfigFile(t, "runtime.yaml", `
+pack_file: file.pack.json
+fault_injection:
+  delay_probability: 0.1
+  delay: 2s
+  error_probability: 0.05
+  error_status: 502
+`))
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fc := cfg.FaultInjection
+	if fc == nil || fc.DelayProbability != 0.1 || fc.delay != 2*time.Second || fc.ErrorStatus != 502 {
+		t.Fatalf("FaultInjection = %+v, want the file profile", fc)
+	}
+
+	t.Setenv(envFaultInjection, `{"drop_probability": 1}`)
+	if cfg, err = loadConfig(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fc = cfg.FaultInjection; fc.DropProbability != 1 || fc.DelayProbability != 0 {
+		t.Errorf("FaultInjection = %+v, want the env profile", fc)
+	}
+
+	for _, raw := range []string{
+		`{"error_probability": 1.5}`,
+		`{"delay_probability": 0.5}`,
+		`{"delay_probability": 0.5, "delay": "soon"}`,
+		`{"error_probability": 0.5, "error_status": 429}`,
+		`{"error_probability":`,
+	} {
+		t.Run(raw, func(t *testing.T) {
+			t.Setenv(envFaultInjection, raw)
+			if _, err := loadConfig(); err == nil || !strings.Contains(err.Error(), envFaultInjection) {
+				t.Errorf("err = %v, want an error naming %s", err, envFaultInjection)
+			}
+		})
+	}
+}
+
+func TestBuildFaultInjector(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	if f := buildFaultInjector(&runtimeConfig{}, log); f != nil {
+		t.Error("injector built without a profile")
+	}
+	off := &faultInjectionConfig{ErrorStatus: 500}
+	if f := buildFaultInjector(&runtimeConfig{FaultInjection: off}, log); f != nil {
+		t.Error("injector built with every probability zero")
+	}
+}
+
+func TestFaultInjector_Error(t *testing.T) {
+	f := newTestFaultInjector(t, faultInjectionConfig{ErrorProbability: 0.5}, 0.2)
+	reached := false
+	h := f.wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { reached = true }))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, invocationsPath, nil))
+	if reached {
+		t.Error("injected error reached the agent")
+	}
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get(faultHeader) != faultError {
+		t.Errorf("status = %d, fault = %q", rec.Code, rec.Header().Get(faultHeader))
+	}
+	var resp invocationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Response != msgInjectedFault {
+		t.Errorf("body = %s, want the injected fault message", rec.Body.String())
+	}
+
+	f.roll = func() float64 { return 0.7 }
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, invocationsPath, nil))
+	if !reached || rec.Header().Get(faultHeader) != "" {
+		t.Errorf("roll above the probability: reached = %v, fault = %q", reached, rec.Header().Get(faultHeader))
+	}
+}
+
+func TestFaultInjector_Delay(t *testing.T) {
+	f := newTestFaultInjector(t, faultInjectionConfig{DelayProbability: 1, Delay: "50ms"}, 0)
+	h := f.wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, invocationsPath, nil))
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("served after %v, want the 50ms delay", elapsed)
+	}
+	if rec.Code != http.StatusOK || rec.Header().Get(faultHeader) != faultDelay {
+		t.Errorf("status = %d, fault = %q", rec.Code, rec.Header().Get(faultHeader))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.cfg.delay = time.Hour
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, invocationsPath, nil).WithContext(ctx))
+	if rec.Body.Len() != 0 {
+		t.Errorf("canceled request was served: %s", rec.Body.String())
+	}
+}
+
+func TestFaultInjector_DropsSSEStream(t *testing.T) {
+	f := newTestFaultInjector(t, faultInjectionConfig{DropProbability: 1}, 0)
+	srv := httptest.NewServer(f.wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", sseContentType)
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		for _, data := range []string{"first", "second"} {
+			_, _ = io.WriteString(w, "data: "+data+"\n\n")
+			flusher.Flush()
+		}
+	})))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+invocationsPath, nil)
+	req.Header.Set(acceptHeader, sseContentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.Header.Get(faultHeader) != faultDrop {
+		t.Errorf("fault = %q, want %q", resp.Header.Get(faultHeader), faultDrop)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if scanner.Err() == nil {
+		t.Error("stream ended cleanly, want it cut off")
+	}
+	if strings.Join(lines, "\n") != "data: first\n" {
+		t.Errorf("received %q, want only the first event", lines)
+	}
+
+	// Non-streaming invocations are never dropped.
+	plain := httptest.NewRecorder()
+	f.wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(
+		plain, httptest.NewRequest(http.MethodPost, invocationsPath, nil))
+	if plain.Header().Get(faultHeader) != "" {
+		t.Errorf("fault = %q on a non-streaming invocation", plain.Header().Get(faultHeader))
+	}
+}
@@ -3,37 +3,225 @@ package main
 import (
 	"encoding/json"
 	"net/http"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
-// healthHandler serves the /health endpoint with liveness/readiness status.
+// Health component names reported in the /ping detail payload.
+const (
+	componentA2AServer  = "a2a_server"
+	componentHTTPBridge = "http_bridge"
+	componentStateStore = "state_store"
+	componentTracing    = "tracing"
+)
+
+// Component and overall health states.
+const (
+	healthOK       = "ok"
+	healthError    = "error"
+	healthDisabled = "disabled"
+
+	statusHealthy  = "healthy"
+	statusDegraded = "degraded"
+	statusDraining = "draining"
+	// statusBusy is AgentCore's "HealthyBusy" ping status: the runtime is
+	// working on async invocations, so AgentCore keeps the session alive
+	// while it would otherwise count as idle.
+	statusBusy = "HealthyBusy"
+)
+
+// Upstream flap detection: the A2A upstream is considered flapping when
+// upstreamFlapThreshold forwards fail within upstreamFlapWindow.
+const (
+	upstreamFlapThreshold = 3
+	upstreamFlapWindow    = time.Minute
+	upstreamFlapReason    = "a2a upstream flapping"
+)
+
+// stateStoreFallbackReason is reported when AgentCore memory could not be
+// initialised and session state is only kept in process memory.
+const stateStoreFallbackReason = "state store fell back to in-memory"
+
+// componentHealth is the reported state of one runtime dependency.
+type componentHealth struct {
+	Status      string     `json:"status"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// healthResponse is the JSON body served by /health and /ping.
+type healthResponse struct {
+	Status         string                     `json:"status"`
+	DegradedReason string                     `json:"degraded_reason,omitempty"`
+	UptimeSeconds  int64                      `json:"uptime_seconds"`
+	Components     map[string]componentHealth `json:"components,omitempty"`
+}
+
+// healthHandler serves the /health endpoint with liveness/readiness status
+// and per-component detail. All methods are safe on a nil receiver so
+// helpers can report into it without checking whether health is wired up.
 type healthHandler struct {
-	ready atomic.Bool
+	ready   atomic.Bool
+	busy    atomic.Int64 // running async invocations
+	started time.Time
+	now     func() time.Time
+
+	mu               sync.Mutex
+	components       map[string]componentHealth
+	degradedReason   string
+	upstreamFailures []time.Time
 }
 
 // newHealthHandler creates a healthHandler that starts in the ready state.
 func newHealthHandler() *healthHandler {
-	h := &healthHandler{}
+	h := &healthHandler{
+		started:    time.Now(),
+		now:        time.Now,
+		components: make(map[string]componentHealth),
+	}
 	h.ready.Store(true)
 	return h
 }
 
 // setUnhealthy marks the handler as not ready (called during graceful shutdown).
 func (h *healthHandler) setUnhealthy() {
+	if h == nil {
+		return
+	}
 	h.ready.Store(false)
 }
 
-// ServeHTTP returns 200 when ready or 503 when draining.
-func (h *healthHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+// beginBusy and endBusy bracket an async invocation.
+func (h *healthHandler) beginBusy() {
+	if h != nil {
+		h.busy.Add(1)
+	}
+}
+
+func (h *healthHandler) endBusy() {
+	if h != nil {
+		h.busy.Add(-1)
+	}
+}
+
+// setComponent records the status of a named component, clearing any
+// previously reported error.
+func (h *healthHandler) setComponent(name, status string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.components[name] = componentHealth{Status: status}
+}
+
+// reportError marks a component as failed and remembers the error.
+func (h *healthHandler) reportError(name string, err error) {
+	if h == nil || err == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	at := h.now()
+	h.components[name] = componentHealth{Status: healthError, LastError: err.Error(), LastErrorAt: &at}
+}
+
+// setDegraded marks the runtime as degraded. The endpoint keeps returning 200
+// so AgentCore does not recycle a session that can still serve traffic.
+func (h *healthHandler) setDegraded(reason string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.degradedReason = reason
+}
+
+// recordUpstream records the outcome of a forward to the A2A server. Repeated
+// failures within upstreamFlapWindow flip the runtime into the degraded state;
+// it recovers once the window no longer holds enough failures.
+func (h *healthHandler) recordUpstream(err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	if err != nil {
+		h.upstreamFailures = append(h.upstreamFailures, now)
+		h.components[componentA2AServer] = componentHealth{
+			Status: healthError, LastError: err.Error(), LastErrorAt: &now,
+		}
+	} else if c, ok := h.components[componentA2AServer]; ok && c.Status == healthError {
+		c.Status = healthOK
+		h.components[componentA2AServer] = c
+	}
+	h.refreshUpstreamLocked(now)
+}
+
+// refreshUpstreamLocked drops failures outside the flap window and updates
+// the degraded state accordingly. Callers must hold h.mu.
+func (h *healthHandler) refreshUpstreamLocked(now time.Time) {
+	cutoff := now.Add(-upstreamFlapWindow)
+	kept := h.upstreamFailures[:0]
+	for _, t := range h.upstreamFailures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	h.upstreamFailures = kept
+
+	switch {
+	case len(kept) >= upstreamFlapThreshold:
+		h.degradedReason = upstreamFlapReason
+	case h.degradedReason == upstreamFlapReason:
+		h.degradedReason = ""
+	}
+}
+
+// snapshot builds the current health response and HTTP status code.
+func (h *healthHandler) snapshot() (healthResponse, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	h.refreshUpstreamLocked(now)
+
+	resp := healthResponse{
+		Status:        statusHealthy,
+		UptimeSeconds: int64(now.Sub(h.started) / time.Second),
+	}
+	if len(h.components) > 0 {
+		resp.Components = make(map[string]componentHealth, len(h.components))
+		for name, c := range h.components {
+			resp.Components[name] = c
+		}
+	}
 
-	status := "healthy"
-	code := http.StatusOK
 	if !h.ready.Load() {
-		status = "draining"
-		code = http.StatusServiceUnavailable
+		resp.Status = statusDraining
+		return resp, http.StatusServiceUnavailable
 	}
+	if h.degradedReason != "" {
+		resp.Status = statusDegraded
+		resp.DegradedReason = h.degradedReason
+	}
+	// Busy wins over degraded: AgentCore must not end a session that is
+	// still working.
+	if h.busy.Load() > 0 {
+		resp.Status = statusBusy
+	}
+	return resp, http.StatusOK
+}
+
+// ServeHTTP returns 200 when ready (healthy, busy, or degraded) or 503 when
+// draining.
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
+	resp, code := h.snapshot()
 	w.WriteHeader(code)
-	_ = json.NewEncoder(w).Encode(map[string]string{keyStatus: status})
+	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -2,11 +2,29 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
+func decodeHealth(t *testing.T, rec *httptest.ResponseRecorder) healthResponse {
+	t.Helper()
+	var body healthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return body
+}
+
+func serveHealth(t *testing.T, h *healthHandler) (int, healthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	return rec.Code, decodeHealth(t, rec)
+}
+
 func TestHealthHandler_Ready(t *testing.T) {
 	h := newHealthHandler()
 	rec := httptest.NewRecorder()
@@ -18,12 +36,9 @@ func TestHealthHandler_Ready(t *testing.T) {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
 	}
 
-	var body map[string]string
-	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
-		t.Fatalf("decode: %v", err)
-	}
-	if body["status"] != "healthy" {
-		t.Errorf("status = %q, want %q", body["status"], "healthy")
+	body := decodeHealth(t, rec)
+	if body.Status != "healthy" {
+		t.Errorf("status = %q, want %q", body.Status, "healthy")
 	}
 }
 
@@ -40,12 +55,9 @@ func TestHealthHandler_Unhealthy(t *testing.T) {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
 	}
 
-	var body map[string]string
-	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
-		t.Fatalf("decode: %v", err)
-	}
-	if body["status"] != "draining" {
-		t.Errorf("status = %q, want %q", body["status"], "draining")
+	body := decodeHealth(t, rec)
+	if body.Status != "draining" {
+		t.Errorf("status = %q, want %q", body.Status, "draining")
 	}
 }
 
@@ -61,3 +73,118 @@ func TestHealthHandler_ContentType(t *testing.T) {
 		t.Errorf("Content-Type = %q, want %q", ct, "application/json")
 	}
 }
+
+func TestHealthHandler_ComponentsAndUptime(t *testing.T) {
+	h := newHealthHandler()
+	h.started = time.Now().Add(-90 * time.Second)
+	h.setComponent(componentA2AServer, healthOK)
+	h.setComponent(componentTracing, healthDisabled)
+	h.reportError(componentStateStore, errors.New("memory unavailable"))
+
+	code, body := serveHealth(t, h)
+	if code != http.StatusOK {
+		t.Errorf("status = %d, want %d", code, http.StatusOK)
+	}
+	if body.UptimeSeconds < 90 {
+		t.Errorf("uptime_seconds = %d, want >= 90", body.UptimeSeconds)
+	}
+	if got := body.Components[componentA2AServer].Status; got != healthOK {
+		t.Errorf("a2a_server = %q, want %q", got, healthOK)
+	}
+	if got := body.Components[componentTracing].Status; got != healthDisabled {
+		t.Errorf("tracing = %q, want %q", got, healthDisabled)
+	}
+	store := body.Components[componentStateStore]
+	if store.Status != healthError || store.LastError != "memory unavailable" || store.LastErrorAt == nil {
+		t.Errorf("state_store = %+v, want error with last_error and timestamp", store)
+	}
+}
+
+func TestHealthHandler_Degraded(t *testing.T) {
+	h := newHealthHandler()
+	h.setDegraded("memory fallback")
+
+	code, body := serveHealth(t, h)
+	if code != http.StatusOK {
+		t.Errorf("status = %d, want %d", code, http.StatusOK)
+	}
+	if body.Status != statusDegraded || body.DegradedReason != "memory fallback" {
+		t.Errorf("body = %+v, want degraded with reason", body)
+	}
+
+	h.setUnhealthy()
+	code, body = serveHealth(t, h)
+	if code != http.StatusServiceUnavailable || body.Status != statusDraining {
+		t.Errorf("draining should take precedence, got %d %+v", code, body)
+	}
+}
+
+func TestHealthHandler_UpstreamFlapping(t *testing.T) {
+	h := newHealthHandler()
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	upstreamErr := errors.New("connection refused")
+	for range upstreamFlapThreshold - 1 {
+		h.recordUpstream(upstreamErr)
+		h.recordUpstream(nil)
+	}
+	if _, body := serveHealth(t, h); body.Status != statusHealthy {
+		t.Fatalf("status = %q before threshold, want healthy", body.Status)
+	}
+
+	h.recordUpstream(upstreamErr)
+	_, body := serveHealth(t, h)
+	if body.Status != statusDegraded || body.DegradedReason != upstreamFlapReason {
+		t.Fatalf("body = %+v, want degraded by flapping upstream", body)
+	}
+	if got := body.Components[componentA2AServer]; got.LastError != "connection refused" {
+		t.Errorf("a2a_server = %+v, want last error recorded", got)
+	}
+
+	// Failures age out of the window and the runtime recovers.
+	now = now.Add(upstreamFlapWindow + time.Second)
+	h.recordUpstream(nil)
+	_, body = serveHealth(t, h)
+	if body.Status != statusHealthy {
+		t.Errorf("status = %q after window, want healthy", body.Status)
+	}
+	if got := body.Components[componentA2AServer]; got.Status != healthOK || got.LastError == "" {
+		t.Errorf("a2a_server = %+v, want ok with last error retained", got)
+	}
+}
+
+func TestHealthHandler_FlapRecoveryKeepsOtherReason(t *testing.T) {
+	h := newHealthHandler()
+	h.setDegraded(stateStoreFallbackReason)
+	h.recordUpstream(nil)
+
+	if _, body := serveHealth(t, h); body.DegradedReason != stateStoreFallbackReason {
+		t.Errorf("degraded_reason = %q, want %q", body.DegradedReason, stateStoreFallbackReason)
+	}
+}
+
+func TestHealthHandler_NilSafe(t *testing.T) {
+	var h *healthHandler
+	h.setUnhealthy()
+	h.setComponent(componentTracing, healthOK)
+	h.reportError(componentTracing, errors.New("boom"))
+	h.setDegraded("x")
+	h.recordUpstream(errors.New("boom"))
+}
+
+func TestHealthHandler_BusyWinsOverDegraded(t *testing.T) {
+	h := newHealthHandler()
+	h.setDegraded("state store fell back")
+	h.beginBusy()
+
+	code, body := serveHealth(t, h)
+	if code != http.StatusOK || body.Status != statusBusy || body.DegradedReason == "" {
+		t.Errorf("busy: %d %+v, want %q with the degraded reason", code, body, statusBusy)
+	}
+
+	h.endBusy()
+	if _, body := serveHealth(t, h); body.Status != statusDegraded {
+		t.Errorf("after busy: status = %q, want %q", body.Status, statusDegraded)
+	}
+}
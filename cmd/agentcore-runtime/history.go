@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/AltairaLabs/PromptKit/runtime/credentials"
+	"github.com/AltairaLabs/PromptKit/runtime/providers"
+	"github.com/AltairaLabs/PromptKit/sdk"
+)
+
+// History strategies: what happens to the turns that fall outside the
+// history limits.
+const (
+	historyTruncate  = "truncate"  // drop the oldest messages (default)
+	historySummarize = "summarize" // compress the oldest messages into a summary
+)
+
+// sdkTruncationSliding is the SDK truncation strategy that drops the oldest
+// messages first.
+const sdkTruncationSliding = "sliding"
+
+// messagesPerTurn converts a turn limit into the SDK's message window: one
+// user message and one agent answer per turn.
+const messagesPerTurn = 2
+
+// Summarizer generation defaults, matching the SDK's own provider.
+const (
+	summarizerTemperature = 0.7
+	summarizerMaxTokens   = 4096
+)
+
+// historyOptions returns the SDK options that bound the conversation
+// history sent to the provider on each turn. Without a limit every stored
+// message is loaded, which grows without bound over a long session. The
+// summarize strategy falls back to truncation, with a warning, when no
+// summarizer can be built.
+func historyOptions(cfg *runtimeConfig) []sdk.Option {
+	var opts []sdk.Option
+	strategy := sdkTruncationSliding
+	if cfg.HistoryMaxTurns > 0 {
+		window := cfg.HistoryMaxTurns * messagesPerTurn
+		opts = append(opts, sdk.WithContextWindow(window))
+		if cfg.HistoryStrategy == historySummarize {
+			summarizer, err := newSummarizerProvider(cfg)
+			if err != nil {
+				slog.Warn("history summarizer unavailable, truncating instead", "error", err)
+			} else {
+				// Once twice the window is stored, the oldest window's worth
+				// of messages is summarized.
+				opts = append(opts, sdk.WithAutoSummarize(summarizer, 2*window, window))
+				strategy = historySummarize
+			}
+		}
+	}
+	if cfg.HistoryMaxTokens > 0 {
+		opts = append(opts, sdk.WithTokenBudget(cfg.HistoryMaxTokens), sdk.WithTruncation(strategy))
+	}
+	return opts
+}
+
+// newSummarizerProvider builds a Bedrock provider for the agent's own model
+// to summarize old turns.
+func newSummarizerProvider(cfg *runtimeConfig) (providers.Provider, error) {
+	if cfg.AWSRegion == "" || cfg.ProviderType == "" {
+		return nil, fmt.Errorf("%s and %s are required", envAWSRegion, envProviderType)
+	}
+	cred, err := credentials.NewAWSCredential(context.Background(), cfg.AWSRegion)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock credentials: %w", err)
+	}
+	model := bedrockModelID(cfg)
+	if mapped, ok := credentials.BedrockModelMapping[model]; ok {
+		model = mapped
+	}
+	return providers.CreateProviderFromSpec(providers.ProviderSpec{
+		ID:         cfg.ProviderType + "-summarizer",
+		Type:       cfg.ProviderType,
+		Model:      model,
+		BaseURL:    credentials.BedrockEndpoint(cfg.AWSRegion),
+		Credential: cred,
+		Platform:   "bedrock",
+		PlatformConfig: &providers.PlatformConfig{
+			Type:   "bedrock",
+			Region: cfg.AWSRegion,
+		},
+		Defaults: providers.ProviderDefaults{
+			Temperature: summarizerTemperature,
+			TopP:        1.0,
+			MaxTokens:   summarizerMaxTokens,
+		},
+	})
+}
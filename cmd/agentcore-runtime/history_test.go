@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestHistoryOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  runtimeConfig
+		want int
+	}{
+		{"unbounded", runtimeConfig{}, 0},
+		{"turns", runtimeConfig{HistoryMaxTurns: 10}, 1},
+		{"tokens", runtimeConfig{HistoryMaxTokens: 8000}, 2},
+		{"turns and tokens", runtimeConfig{HistoryMaxTurns: 10, HistoryMaxTokens: 8000}, 3},
+		{"summarize", runtimeConfig{
+			HistoryMaxTurns: 10, HistoryMaxTokens: 8000, HistoryStrategy: historySummarize,
+			AWSRegion: "us-west-2", ProviderType: "claude", Model: "claude-3-5-haiku-20241022",
+		}, 4},
+		{"summarize without a provider truncates", runtimeConfig{
+			HistoryMaxTurns: 10, HistoryStrategy: historySummarize,
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(historyOptions(&tt.cfg)); got != tt.want {
+				t.Errorf("historyOptions returned %d options, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSummarizerProvider_RequiresRegionAndProvider(t *testing.T) {
+	if _, err := newSummarizerProvider(&runtimeConfig{AWSRegion: "us-west-2"}); err == nil {
+		t.Error("want an error without a provider type")
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,6 +11,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
 )
 
 // httpBridgePort is the port AgentCore uses for the HTTP protocol contract.
@@ -20,6 +21,9 @@ const httpBridgePort = 8080
 // invocationsPath is the HTTP protocol endpoint for agent invocations.
 const invocationsPath = "/invocations"
 
+// pingPath is the AgentCore health check endpoint.
+const pingPath = "/ping"
+
 // sessionHeader is the AgentCore header that carries the session ID.
 const sessionHeader = "X-Amzn-Bedrock-AgentCore-Runtime-Session-Id"
 
@@ -34,6 +38,15 @@ type invocationRequest struct {
 	Input    string         `json:"input"`
 	Metadata map[string]any `json:"metadata,omitempty"`
 	Extra    map[string]any `json:"-"` // all other top-level fields
+
+	// Async runs the invocation in the background; the response is 202
+	// with a task handle to poll.
+	Async bool `json:"async,omitempty"`
+	// PushNotification receives the result of an async invocation.
+	PushNotification *pushNotificationConfig `json:"push_notification,omitempty"`
+	// OutputFormat "json" asks the agent for structured JSON and parses its
+	// answer into the response's response_json field.
+	OutputFormat string `json:"output_format,omitempty"`
 }
 
 // UnmarshalJSON implements custom unmarshalling to capture extra fields
@@ -55,6 +68,9 @@ func (r *invocationRequest) UnmarshalJSON(data []byte) error {
 	delete(raw, "prompt")
 	delete(raw, "input")
 	delete(raw, "metadata")
+	delete(raw, "async")
+	delete(raw, "push_notification")
+	delete(raw, "output_format")
 	if len(raw) > 0 {
 		r.Extra = raw
 	}
@@ -70,16 +86,20 @@ func (r *invocationRequest) text() string {
 }
 
 // allMetadata merges explicit metadata with extra top-level fields.
-// Extra fields are namespaced under "payload" to avoid collisions.
+// Extra fields are namespaced under "payload" to avoid collisions. The
+// output format, when set, is passed under "output_format".
 func (r *invocationRequest) allMetadata() map[string]any {
-	if len(r.Metadata) == 0 && len(r.Extra) == 0 {
+	if len(r.Metadata) == 0 && len(r.Extra) == 0 && r.OutputFormat == "" {
 		return nil
 	}
-	merged := make(map[string]any, len(r.Metadata)+1)
+	merged := make(map[string]any, len(r.Metadata)+2)
 	maps.Copy(merged, r.Metadata)
 	if len(r.Extra) > 0 {
 		merged["payload"] = r.Extra
 	}
+	if r.OutputFormat != "" {
+		merged[metadataOutputFormat] = r.OutputFormat
+	}
 	return merged
 }
 
@@ -92,6 +112,15 @@ type invocationResponse struct {
 	ContextID string         `json:"context_id,omitempty"`
 	Usage     *usageInfo     `json:"usage,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
+
+	// ResponseJSON is the parsed answer of an output_format json invocation.
+	ResponseJSON json.RawMessage `json:"response_json,omitempty"`
+
+	Timings *invocationTimings `json:"timings,omitempty"`
+
+	// Error explains a rejection whose reason clients act on, such as a
+	// spent token budget.
+	Error *invocationError `json:"error,omitempty"`
 }
 
 // usageInfo holds token usage from the A2A response.
@@ -106,20 +135,99 @@ type httpBridge struct {
 	a2aPort int
 	log     *slog.Logger
 	srv     *http.Server
+	card    *a2a.AgentCard // served on /.well-known/agent.json when non-nil
+	health  *healthHandler // receives A2A upstream outcomes; nil disables reporting
+
+	// WebSocket keepalive settings; zero values fall back to defaults.
+	wsPingInterval time.Duration
+	wsIdleTimeout  time.Duration
+
+	// sseHeartbeatInterval is the SSE heartbeat period; zero uses the default.
+	sseHeartbeatInterval time.Duration
+	// resume buffers SSE streams for Last-Event-ID resume; nil disables it.
+	resume *sseResumeStore
+
+	// redaction controls how user content appears in logs ("" = hash).
+	redaction string
+	// logSampleRate is the fraction of requests that get an access log line.
+	logSampleRate float64
+	// responseTimings adds a latency breakdown to invocation responses and
+	// SSE done events.
+	responseTimings bool
+
+	// webhooks are called around each invocation; nil disables them.
+	webhooks *invokeWebhooks
+	// sessions tracks per-session metadata; nil disables it.
+	sessions *sessionTracker
+	// limits rate-limits and caps concurrent invocations; nil disables it.
+	limits *invocationLimiter
+	// faults injects simulated failures into invocations; nil disables it.
+	faults *faultInjector
+	// compression compresses invocation responses; nil disables it.
+	compression *responseCompressor
+	// moderation applies the pack's validators to responses; nil disables it.
+	moderation *responseModerator
+	// async runs and tracks async invocations.
+	async *asyncTaskStore
+	// cors answers preflights from browser apps; nil disables it.
+	cors *corsPolicy
+	// capabilities is served on /capabilities.
+	capabilities *bridgeCapabilities
 }
 
 // startHTTPBridge starts the HTTP bridge server on port 8080.
 // It forwards /invocations requests to the A2A server's /a2a endpoint.
-func startHTTPBridge(log *slog.Logger, healthH *healthHandler, a2aPort int) (*httpBridge, error) {
+// card is served on the well-known agent card path when non-nil; pass it
+// only when the A2A server, which normally serves the card, is skipped.
+// moderation, which may be nil, moderates the bridge's responses.
+func startHTTPBridge(
+	log *slog.Logger, healthH *healthHandler, cfg *runtimeConfig, card *a2a.AgentCard,
+	moderation *responseModerator,
+) (*httpBridge, error) {
 	b := &httpBridge{
-		a2aPort: a2aPort,
-		log:     log,
-	}
+		a2aPort:              cfg.Port,
+		log:                  log,
+		health:               healthH,
+		card:                 card,
+		wsPingInterval:       cfg.WSPingInterval,
+		wsIdleTimeout:        cfg.WSIdleTimeout,
+		sseHeartbeatInterval: cfg.SSEHeartbeatInterval,
+		resume:               newSSEResumeStore(),
+		redaction:            cfg.LogRedaction,
+		logSampleRate:        cfg.LogSampleRate,
+		responseTimings:      cfg.ResponseTimings,
+		webhooks:             buildInvokeWebhooks(cfg, log),
+		sessions:             buildSessionTracker(cfg, log),
+		limits:               buildInvocationLimiter(cfg),
+		faults:               buildFaultInjector(cfg, log),
+		compression:          buildResponseCompressor(cfg, log),
+		moderation:           moderation,
+		async:                newAsyncTaskStore(log, healthH, cfg.PushAllowedHosts),
+		cors:                 buildCORSPolicy(cfg, log),
+	}
+	b.capabilities = b.buildCapabilities(cfg)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("POST "+invocationsPath, b.handleInvocation)
-	mux.HandleFunc("/ws", b.handleWebSocket)
-	mux.Handle("/ping", healthH)
+	mux.Handle("POST "+invocationsPath, b.cors.wrap(b.limits.wrap(b.faults.wrap(
+		b.compression.wrap(decompressRequest(http.HandlerFunc(b.handleInvocation)))))))
+	mux.Handle("GET "+invocationsPath+"/{taskId}",
+		b.cors.wrap(b.compression.wrap(http.HandlerFunc(b.handleAsyncResult))))
+	mux.HandleFunc(wsPath, b.handleWebSocket)
+	mux.Handle(pingPath, b.cors.wrap(healthH))
+	if b.cors != nil {
+		preflight := b.cors.wrap(http.NotFoundHandler())
+		for _, path := range []string{invocationsPath, invocationsPath + "/{taskId}"} {
+			mux.Handle("OPTIONS "+path, preflight)
+		}
+	}
+	if card != nil {
+		mux.HandleFunc("GET "+agentCardPath, b.handleAgentCard)
+	}
+	mux.HandleFunc("GET "+capabilitiesPath, b.handleCapabilities)
+	if b.sessions != nil {
+		mux.HandleFunc("GET "+sessionsPath+"{id}", b.handleSession)
+		mux.HandleFunc("GET "+sessionsPath+"{id}"+sessionUsageSuffix, b.handleSessionUsage)
+	}
 	mux.HandleFunc("/", b.handleUnknown)
 
 	addr := fmt.Sprintf(":%d", httpBridgePort)
@@ -130,13 +238,14 @@ func startHTTPBridge(log *slog.Logger, healthH *healthHandler, a2aPort int) (*ht
 	}
 
 	b.srv = &http.Server{
-		Handler:           mux,
+		Handler:           b.withAccessLog(withTraceContext(mux)),
 		ReadHeaderTimeout: defaultReadHeaderTmout,
 	}
 
 	go func() {
 		if err := b.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			log.Error("http bridge serve error", "error", err)
+			healthH.reportError(componentHTTPBridge, err)
 		}
 	}()
 
@@ -149,13 +258,22 @@ func (b *httpBridge) shutdown(ctx context.Context) error {
 	if b == nil {
 		return nil
 	}
-	return b.srv.Shutdown(ctx)
+	err := b.srv.Shutdown(ctx)
+	b.async.wait()
+	b.webhooks.wait()
+	b.sessions.wait()
+	return err
 }
 
 // writeInvocationError writes a JSON error response for an invocation.
 func writeInvocationError(w http.ResponseWriter, msg string) {
+	writeInvocationStatus(w, http.StatusInternalServerError, msg)
+}
+
+// writeInvocationStatus writes a JSON error response with the given status.
+func writeInvocationStatus(w http.ResponseWriter, status int, msg string) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(invocationResponse{
 		Response: msg,
 		Status:   keyError,
@@ -168,122 +286,47 @@ func (b *httpBridge) handleUnknown(w http.ResponseWriter, r *http.Request) {
 	b.log.Warn("unmatched request on http bridge",
 		"method", r.Method, "path", r.URL.Path,
 		"content-type", r.Header.Get("Content-Type"),
-		"body_size", len(body), "body", string(body))
+		"body_size", len(body), "body", b.redact(string(body)))
 	http.Error(w, "not found", http.StatusNotFound)
 }
 
-// a2aResponse is the parsed JSON-RPC response from the A2A server.
-type a2aResponse struct {
-	Result struct {
-		ID        string `json:"id"`
-		ContextID string `json:"contextId"`
-		Status    struct {
-			State   string `json:"state"`
-			Message *struct {
-				Parts []struct {
-					Text *string `json:"text"`
-				} `json:"parts"`
-			} `json:"message"`
-		} `json:"status"`
-		Artifacts []struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-			Metadata map[string]any `json:"metadata,omitempty"`
-		} `json:"artifacts"`
-		Metadata map[string]any `json:"metadata,omitempty"`
-	} `json:"result"`
-	Error *struct {
-		Message string `json:"message"`
-	} `json:"error"`
-}
-
 // buildA2ARequest creates a blocking A2A message/send JSON-RPC request.
 // sessionID maps to contextId for multi-turn conversation continuity.
 // metadata is forwarded as A2A message-level metadata.
 func buildA2ARequest(text, sessionID string, metadata map[string]any) ([]byte, error) {
-	message := map[string]any{
-		keyRole: roleUser,
-		keyParts: []map[string]any{
-			{keyKind: kindText, kindText: text},
-		},
-		keyMessageID: fmt.Sprintf("http-%d", time.Now().UnixNano()),
-	}
-	if len(metadata) > 0 {
-		message["metadata"] = metadata
-	}
-
-	params := map[string]any{
-		keyMessage: message,
-		keyConfiguration: map[string]any{
-			keyBlocking: true,
-		},
-	}
-	if sessionID != "" {
-		params["contextId"] = sessionID
-	}
-
-	a2aReq := map[string]any{
-		keyJSONRPC: jsonrpcVersion,
-		"id":       "http-bridge-1",
-		keyMethod:  methodMessageSend,
-		keyParams:  params,
-	}
-	return json.Marshal(a2aReq)
-}
-
-// extractFailedMessage extracts an error message from a failed A2A task status.
-func extractFailedMessage(result *a2aResponse) string {
-	errMsg := "agent task failed"
-	if m := result.Result.Status.Message; m != nil {
-		for _, p := range m.Parts {
-			if p.Text != nil {
-				errMsg = *p.Text
-				break
-			}
-		}
-	}
-	return errMsg
+	req := newA2ARequest(rpcIDHTTP, methodMessageSend, messageIDPrefixHTTP, text, sessionID, metadata)
+	req.Params.Configuration = &a2aSendConfiguration{Blocking: true}
+	return json.Marshal(req)
 }
 
 // extractArtifactText concatenates all text parts from A2A response artifacts.
 func extractArtifactText(result *a2aResponse) string {
 	var sb strings.Builder
-	for _, art := range result.Result.Artifacts {
-		for _, part := range art.Parts {
-			if part.Text != "" {
-				sb.WriteString(part.Text)
-			}
-		}
+	for i := range result.Result.Artifacts {
+		sb.WriteString(result.Result.Artifacts[i].text())
 	}
 	return sb.String()
 }
 
 // extractUsage extracts token usage from A2A response metadata.
 func extractUsage(result *a2aResponse) *usageInfo {
-	md := result.Result.Metadata
-	if md == nil {
-		return nil
-	}
-	usage, ok := md["usage"]
+	return taskUsage(&result.Result)
+}
+
+// taskUsage extracts token usage from A2A task metadata.
+func taskUsage(task *a2aTask) *usageInfo {
+	raw, ok := task.Metadata["usage"]
 	if !ok {
 		return nil
 	}
-	usageMap, ok := usage.(map[string]any)
-	if !ok {
+	var info usageInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
 		return nil
 	}
-	info := &usageInfo{}
-	if v, ok := usageMap["input_tokens"].(float64); ok {
-		info.InputTokens = int(v)
-	}
-	if v, ok := usageMap["output_tokens"].(float64); ok {
-		info.OutputTokens = int(v)
-	}
 	if info.InputTokens == 0 && info.OutputTokens == 0 {
 		return nil
 	}
-	return info
+	return &info
 }
 
 // handleInvocation converts an HTTP /invocations request to an A2A message/send
@@ -299,20 +342,45 @@ func (b *httpBridge) handleInvocation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	b.log.Info("invocation body", "size", len(body), "body", string(body))
+	b.log.Info("invocation body", "size", len(body), "body", b.redact(string(body)))
 
 	var req invocationRequest
 	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
-		b.log.Error("invalid JSON in invocation", "error", unmarshalErr, "body", string(body))
+		b.log.Error("invalid JSON in invocation", "error", unmarshalErr, "body", b.redact(string(body)))
 		http.Error(w, "invalid JSON", http.StatusBadRequest)
 		return
 	}
 
 	if req.text() == "" {
-		b.log.Warn("invocation missing prompt/input field", "body", string(body))
+		b.log.Warn("invocation missing prompt/input field", "body", b.redact(string(body)))
 		http.Error(w, "prompt or input is required", http.StatusBadRequest)
 		return
 	}
+	if err = validateOutputFormat(req.OutputFormat); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metadata := req.allMetadata()
+	accessLogFrom(r.Context()).setMetadata(metadata)
+	if !b.webhooks.preInvoke(r.Context(), &webhookEvent{
+		Timestamp: time.Now().UTC(), Path: r.URL.Path, SessionID: r.Header.Get(sessionHeader),
+		PromptBytes: len(req.text()), Metadata: metadata,
+	}) {
+		writeInvocationStatus(w, http.StatusForbidden, "rejected by pre-invoke webhook")
+		return
+	}
+	release, err := b.sessions.admit(r.Context(), r.Header.Get(sessionHeader))
+	if err != nil {
+		writeAdmissionError(w, err, b.sessions.clock())
+		return
+	}
+	accessLogFrom(r.Context()).holdSessionTurn(release)
+
+	if req.Async {
+		b.handleAsyncInvocation(w, r, &req, metadata)
+		return
+	}
 
 	// Route to SSE streaming if the client accepts event-stream.
 	if wantsSSE(r) {
@@ -321,32 +389,40 @@ func (b *httpBridge) handleInvocation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sessionID := r.Header.Get(sessionHeader)
-	a2aBody, err := buildA2ARequest(req.text(), sessionID, req.allMetadata())
+	a2aBody, err := buildA2ARequest(req.text(), sessionID, metadata)
 	if err != nil {
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
 
-	respBody, err := b.forwardToA2A(a2aBody)
+	forwarded := time.Now()
+	respBody, err := b.forwardToA2A(r.Context(), a2aBody)
 	if err != nil {
 		http.Error(w, "agent unavailable", http.StatusBadGateway)
 		return
 	}
 
-	b.writeA2AResponse(w, respBody)
+	timings := b.completedTimings(accessLogFrom(r.Context()).receivedAt(forwarded), forwarded)
+	if result := b.writeA2AResponse(w, respBody, timings, req.OutputFormat); result != nil {
+		entry := accessLogFrom(r.Context())
+		entry.setTask(result.Result.ID)
+		entry.setUsage(extractUsage(result))
+	}
 }
 
-// forwardToA2A sends a JSON-RPC request to the A2A server and returns the body.
-func (b *httpBridge) forwardToA2A(a2aBody []byte) ([]byte, error) {
-	a2aURL := fmt.Sprintf("http://127.0.0.1:%d/a2a", b.a2aPort)
+// forwardToA2A sends a JSON-RPC request to the A2A server, with the trace
+// context of ctx, and returns the body.
+func (b *httpBridge) forwardToA2A(ctx context.Context, a2aBody []byte) ([]byte, error) {
+	a2aURL := fmt.Sprintf("http://127.0.0.1:%d%s", b.a2aPort, a2aPath)
 	b.log.Info("forwarding to a2a", "url", a2aURL, "body_size", len(a2aBody))
 
-	resp, err := http.Post(a2aURL, "application/json", //nolint:noctx,gosec // internal loopback
-		bytes.NewReader(a2aBody))
+	resp, err := postA2A(ctx, a2aURL, a2aBody)
 	if err != nil {
 		b.log.Error("a2a forward failed", "error", err)
+		b.health.recordUpstream(err)
 		return nil, err
 	}
+	b.health.recordUpstream(nil)
 	defer func() { _ = resp.Body.Close() }()
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -354,35 +430,64 @@ func (b *httpBridge) forwardToA2A(a2aBody []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	b.log.Info("a2a response", "status", resp.StatusCode, "body", string(respBody))
+	b.log.Info("a2a response", "status", resp.StatusCode, "body", b.redact(string(respBody)))
 	return respBody, nil
 }
 
-// writeA2AResponse parses the A2A JSON-RPC response and writes the invocation response.
-func (b *httpBridge) writeA2AResponse(w http.ResponseWriter, respBody []byte) {
+// writeA2AResponse parses the A2A JSON-RPC response and writes the invocation response
+// with timings, which may be nil, in outputFormat. It returns the parsed response, or
+// nil if the body was not valid JSON.
+func (b *httpBridge) writeA2AResponse(
+	w http.ResponseWriter, respBody []byte, timings *invocationTimings, outputFormat string,
+) *a2aResponse {
 	var result a2aResponse
 	if err := json.Unmarshal(respBody, &result); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write(respBody)
-		return
-	}
-
-	if result.Error != nil {
-		writeInvocationError(w, result.Error.Message)
-		return
+		return nil
 	}
 
-	if result.Result.Status.State == stateFailed {
-		writeInvocationError(w, extractFailedMessage(&result))
-		return
+	resp := successResponse(&result)
+	status := http.StatusOK
+	if msg, failed := result.failure(); failed {
+		resp = invocationResponse{Response: msg, Status: keyError}
+		status = http.StatusInternalServerError
+	} else if !applyOutputFormat(&resp, outputFormat) {
+		status = http.StatusBadGateway
+	} else {
+		b.moderation.apply(&resp)
 	}
+	resp.Timings = timings
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(invocationResponse{
-		Response:  extractArtifactText(&result),
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+	return &result
+}
+
+// successResponse builds the invocation response for a completed task.
+func successResponse(result *a2aResponse) invocationResponse {
+	return invocationResponse{
+		Response:  extractArtifactText(result),
 		Status:    "success",
 		TaskID:    result.Result.ID,
 		ContextID: result.Result.ContextID,
-		Usage:     extractUsage(&result),
-	})
+		Usage:     extractUsage(result),
+	}
+}
+
+// parseA2AInvocation converts an A2A JSON-RPC response body to the
+// invocation response in outputFormat, reporting failures and unparseable
+// bodies as errors.
+func parseA2AInvocation(respBody []byte, outputFormat string) invocationResponse {
+	var result a2aResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return invocationResponse{Response: "invalid agent response", Status: keyError}
+	}
+	if msg, failed := result.failure(); failed {
+		return invocationResponse{Response: msg, Status: keyError}
+	}
+	resp := successResponse(&result)
+	applyOutputFormat(&resp, outputFormat)
+	return resp
 }
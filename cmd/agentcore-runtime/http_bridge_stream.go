@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,6 +18,13 @@ const sseContentType = "text/event-stream"
 // acceptHeader is the HTTP header used for content negotiation.
 const acceptHeader = "Accept"
 
+// defaultSSEHeartbeatInterval is how often a comment line is written to an
+// idle SSE stream so intermediate proxies do not time it out.
+const defaultSSEHeartbeatInterval = 15 * time.Second
+
+// sseHeartbeat is the SSE comment written on every heartbeat.
+const sseHeartbeat = ": heartbeat\n\n"
+
 // sseEvent is the format written to the client for each SSE chunk.
 type sseEvent struct {
 	Type      string `json:"type"`
@@ -24,8 +32,16 @@ type sseEvent struct {
 	State     string `json:"state,omitempty"`
 	TaskID    string `json:"task_id,omitempty"`
 	ContextID string `json:"context_id,omitempty"`
+
+	Timings *invocationTimings `json:"timings,omitempty"` // done events only
 }
 
+// sseTypeDone is the type of the terminal SSE event.
+const sseTypeDone = "done"
+
+// stateCompleted is the A2A task state of a task that finished normally.
+const stateCompleted = "completed"
+
 // wantsSSE returns true if the client accepts text/event-stream.
 func wantsSSE(r *http.Request) bool {
 	accept := r.Header.Get(acceptHeader)
@@ -34,38 +50,23 @@ func wantsSSE(r *http.Request) bool {
 
 // buildA2AStreamRequest creates a streaming A2A message/stream JSON-RPC request.
 func buildA2AStreamRequest(text, sessionID string, metadata map[string]any) ([]byte, error) {
-	message := map[string]any{
-		keyRole: roleUser,
-		keyParts: []map[string]any{
-			{keyKind: kindText, kindText: text},
-		},
-		keyMessageID: fmt.Sprintf("http-%d", time.Now().UnixNano()),
-	}
-	if len(metadata) > 0 {
-		message["metadata"] = metadata
-	}
-
-	params := map[string]any{
-		keyMessage: message,
-	}
-	if sessionID != "" {
-		params["contextId"] = sessionID
-	}
-
-	a2aReq := map[string]any{
-		keyJSONRPC: jsonrpcVersion,
-		"id":       "http-bridge-stream-1",
-		keyMethod:  "message/stream",
-		keyParams:  params,
-	}
-	return json.Marshal(a2aReq)
+	return json.Marshal(newA2ARequest(
+		rpcIDStream, methodMessageStream, messageIDPrefixHTTP, text, sessionID, metadata,
+	))
 }
 
 // handleStreamingInvocation sends a message/stream request to the A2A server
-// and relays the SSE events to the HTTP client.
+// and relays the SSE events to the HTTP client. A request carrying a
+// Last-Event-ID for a buffered task resumes that stream instead.
 func (b *httpBridge) handleStreamingInvocation(
 	w http.ResponseWriter, r *http.Request, req *invocationRequest,
 ) {
+	if tb, lastSeq, ok := b.resume.resumeTarget(r); ok {
+		b.log.Info("resuming sse stream", "last_event_id", r.Header.Get(lastEventIDHeader))
+		b.followSSE(w, r, tb, lastSeq)
+		return
+	}
+
 	sessionID := r.Header.Get(sessionHeader)
 	a2aBody, err := buildA2AStreamRequest(req.text(), sessionID, req.allMetadata())
 	if err != nil {
@@ -73,35 +74,55 @@ func (b *httpBridge) handleStreamingInvocation(
 		return
 	}
 
-	a2aURL := fmt.Sprintf("http://127.0.0.1:%d/a2a", b.a2aPort)
-	b.log.Info("forwarding stream to a2a", "url", a2aURL)
-
-	a2aResp, err := http.Post(a2aURL, "application/json", //nolint:noctx,gosec // internal loopback
-		bytes.NewReader(a2aBody))
+	forwarded := time.Now()
+	a2aResp, err := b.openA2AStream(r.Context(), a2aBody)
 	if err != nil {
-		b.log.Error("a2a stream forward failed", "error", err)
 		http.Error(w, "agent unavailable", http.StatusBadGateway)
 		return
 	}
-	defer func() { _ = a2aResp.Body.Close() }()
 
-	b.relaySSEEvents(w, r, a2aResp.Body)
+	// relaySSEEvents takes ownership of the body.
+	b.relaySSEEvents(w, r, a2aResp.Body, forwarded, req.OutputFormat)
 }
 
-// relaySSEEvents reads A2A SSE events and writes simplified SSE events to the client.
-func (b *httpBridge) relaySSEEvents(w http.ResponseWriter, r *http.Request, body io.Reader) {
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "streaming not supported", http.StatusInternalServerError)
-		return
+// openA2AStream posts a message/stream request, with the trace context of
+// ctx, to the A2A server. The
+// returned response body carries the A2A SSE events; callers must close it.
+// A plain JSON-RPC response (e.g. an error raised before streaming began) is
+// rewritten as a single SSE data event so callers handle one format.
+func (b *httpBridge) openA2AStream(ctx context.Context, a2aBody []byte) (*http.Response, error) {
+	a2aURL := fmt.Sprintf("http://127.0.0.1:%d%s", b.a2aPort, a2aPath)
+	b.log.Info("forwarding stream to a2a", "url", a2aURL)
+
+	resp, err := postA2A(ctx, a2aURL, a2aBody)
+	if err != nil {
+		b.log.Error("a2a stream forward failed", "error", err)
+		b.health.recordUpstream(err)
+		return nil, err
+	}
+	b.health.recordUpstream(nil)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), sseContentType) {
+		return resp, nil
 	}
 
-	w.Header().Set("Content-Type", sseContentType)
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.WriteHeader(http.StatusOK)
-	flusher.Flush()
+	defer func() { _ = resp.Body.Close() }()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var compact bytes.Buffer
+	if compactErr := json.Compact(&compact, raw); compactErr != nil {
+		b.log.Warn("unparseable a2a stream response", "error", compactErr)
+		compact.Reset()
+	}
+	resp.Body = io.NopCloser(strings.NewReader("data: " + compact.String() + "\n\n"))
+	return resp, nil
+}
 
+// scanA2AStream reads A2A SSE data lines from body and passes each translated
+// event to emit. Scanning stops when emit returns false, after a terminal
+// status event has been emitted, or when the stream ends.
+func (b *httpBridge) scanA2AStream(body io.Reader, emit func(evt *sseEvent) bool) {
 	scanner := bufio.NewScanner(body)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -109,115 +130,174 @@ func (b *httpBridge) relaySSEEvents(w http.ResponseWriter, r *http.Request, body
 		if !strings.HasPrefix(line, "data: ") {
 			continue
 		}
-		data := strings.TrimPrefix(line, "data: ")
-
-		evt := b.parseA2ASSEEvent(data)
+		evt := b.parseA2ASSEEvent(strings.TrimPrefix(line, "data: "))
 		if evt == nil {
 			continue
 		}
 
-		if err := writeSSEEvent(w, flusher, evt); err != nil {
-			b.log.Error("sse write failed", "error", err)
+		if !emit(evt) {
 			return
 		}
-
-		// Stop on terminal states.
 		if evt.Type == keyStatus && isTerminalState(evt.State) {
-			writeSSEDone(w, flusher)
-			return
-		}
-
-		// Check for client disconnect.
-		if r.Context().Err() != nil {
-			b.log.Info("client disconnected during stream")
 			return
 		}
 	}
+}
+
+// relaySSEEvents reads A2A SSE events and writes simplified SSE events to the client.
+// The upstream is drained into a task buffer by a separate goroutine that
+// outlives a client disconnect, so the client can resume with Last-Event-ID.
+// body is closed once drained if it implements io.Closer. forwarded is when
+// the stream was requested from the A2A server. The completed status is
+// preceded by an error event when response moderation blocks the text or,
+// with a json outputFormat, when the text does not parse.
+func (b *httpBridge) relaySSEEvents(
+	w http.ResponseWriter, r *http.Request, body io.Reader, forwarded time.Time, outputFormat string,
+) {
+	tb := newSSETaskBuffer()
+	tb.clock = b.newStreamClock(accessLogFrom(r.Context()).receivedAt(forwarded), forwarded)
+	go b.pumpA2AStream(body, tb, b.streamChecks(outputFormat))
+	b.followSSE(w, r, tb, 0)
+}
 
-	// Stream ended without a terminal event — send done.
-	writeSSEDone(w, flusher)
+// streamCheck inspects the events of a stream, returning an event to emit
+// ahead of one, or nil.
+type streamCheck interface {
+	observe(evt *sseEvent) *sseEvent
 }
 
-// a2aSSEPayload is a partial parse of the JSON-RPC response wrapping A2A events.
-type a2aSSEPayload struct {
-	Result json.RawMessage `json:"result"`
-	Error  *struct {
-		Message string `json:"message"`
-	} `json:"error"`
+// streamChecks returns the checks a stream in outputFormat goes through.
+func (b *httpBridge) streamChecks(outputFormat string) []streamCheck {
+	var checks []streamCheck
+	if mc := b.moderation.streamCheck(); mc != nil {
+		checks = append(checks, mc)
+	}
+	if outputFormat == outputFormatJSON {
+		checks = append(checks, &jsonStreamCheck{})
+	}
+	return checks
 }
 
-// a2aStreamEvent is a partial parse of the A2A event discriminated by field presence.
-type a2aStreamEvent struct {
-	TaskID    string           `json:"taskId"`
-	ContextID string           `json:"contextId"`
-	Status    *json.RawMessage `json:"status"`
-	Artifact  *json.RawMessage `json:"artifact"`
+// pumpA2AStream drains the A2A stream into tb and registers tb for resume
+// once the task ID is known. Each event first goes through checks.
+func (b *httpBridge) pumpA2AStream(body io.Reader, tb *sseTaskBuffer, checks []streamCheck) {
+	defer tb.finish()
+	if c, ok := body.(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+	b.scanA2AStream(body, func(evt *sseEvent) bool {
+		for _, check := range checks {
+			if extra := check.observe(evt); extra != nil {
+				tb.append(extra)
+			}
+		}
+		if tb.append(evt) {
+			b.resume.register(tb)
+		}
+		return true
+	})
 }
 
-// a2aStatusPayload extracts the state from a status event.
-type a2aStatusPayload struct {
-	State string `json:"state"`
+// followSSE writes the events of tb after lastSeq to the client, then
+// follows the live stream until it ends or the client disconnects. A
+// heartbeat comment is written whenever the stream is idle.
+func (b *httpBridge) followSSE(w http.ResponseWriter, r *http.Request, tb *sseTaskBuffer, lastSeq int) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", sseContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(b.heartbeatInterval())
+	defer heartbeat.Stop()
+	entry := accessLogFrom(r.Context())
+
+	for {
+		events, done, changed := tb.since(lastSeq)
+		for _, be := range events {
+			if err := writeSSEEventWithID(w, flusher, be.id, be.evt); err != nil {
+				b.log.Error("sse write failed", "error", err)
+				return
+			}
+			entry.setTask(be.evt.TaskID)
+			lastSeq = be.seq
+		}
+		if done {
+			writeSSEDone(w, flusher, tb.doneTimings())
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-heartbeat.C:
+			if err := writeSSEHeartbeat(w, flusher); err != nil {
+				b.log.Error("sse heartbeat failed", "error", err)
+				return
+			}
+		case <-r.Context().Done():
+			b.log.Info("client disconnected during stream")
+			return
+		}
+	}
 }
 
-// a2aArtifactPayload extracts text from an artifact event.
-type a2aArtifactPayload struct {
-	Parts []struct {
-		Text *string `json:"text"`
-	} `json:"parts"`
+// heartbeatInterval returns the configured SSE heartbeat interval or the default.
+func (b *httpBridge) heartbeatInterval() time.Duration {
+	if b.sseHeartbeatInterval > 0 {
+		return b.sseHeartbeatInterval
+	}
+	return defaultSSEHeartbeatInterval
 }
 
 // parseA2ASSEEvent converts an A2A SSE data payload to a simplified sseEvent.
+// It returns nil for payloads that are neither an error, a status update,
+// nor an artifact with text.
 func (b *httpBridge) parseA2ASSEEvent(data string) *sseEvent {
-	var payload a2aSSEPayload
+	var payload a2aStreamResponse
 	if err := json.Unmarshal([]byte(data), &payload); err != nil {
-		b.log.Warn("unparseable SSE data", "data", data, "error", err)
+		b.log.Warn("unparseable SSE data", "data", b.redact(data), "error", err)
 		return nil
 	}
 
 	if payload.Error != nil {
-		return &sseEvent{Type: keyError, Content: payload.Error.Message}
-	}
-
-	var evt a2aStreamEvent
-	if err := json.Unmarshal(payload.Result, &evt); err != nil {
-		return nil
+		return &sseEvent{Type: keyError, Content: payload.Error.text()}
 	}
 
-	if evt.Status != nil {
-		return b.parseStatusEvent(&evt)
-	}
-	if evt.Artifact != nil {
-		return b.parseArtifactEvent(&evt)
+	evt := &payload.Result
+	switch {
+	case evt.Status != nil:
+		return statusSSEEvent(evt)
+	case evt.Artifact != nil:
+		return artifactSSEEvent(evt)
 	}
 	return nil
 }
 
-// parseStatusEvent converts an A2A status event to an sseEvent.
-func (b *httpBridge) parseStatusEvent(evt *a2aStreamEvent) *sseEvent {
-	var status a2aStatusPayload
-	if err := json.Unmarshal(*evt.Status, &status); err != nil {
-		return nil
-	}
-	return &sseEvent{
+// statusSSEEvent converts an A2A status event to an sseEvent.
+func statusSSEEvent(evt *a2aStreamEvent) *sseEvent {
+	out := &sseEvent{
 		Type:      keyStatus,
-		State:     status.State,
+		State:     evt.Status.State,
 		TaskID:    evt.TaskID,
 		ContextID: evt.ContextID,
 	}
+	// Surface the failure reason so clients need not fetch the task.
+	if evt.Status.State == stateFailed && evt.Status.Message != nil {
+		out.Content = evt.Status.failureText()
+	}
+	return out
 }
 
-// parseArtifactEvent converts an A2A artifact event to an sseEvent.
-func (b *httpBridge) parseArtifactEvent(evt *a2aStreamEvent) *sseEvent {
-	var artifact a2aArtifactPayload
-	if err := json.Unmarshal(*evt.Artifact, &artifact); err != nil {
-		return nil
-	}
-	var text string
-	for _, p := range artifact.Parts {
-		if p.Text != nil {
-			text += *p.Text
-		}
-	}
+// artifactSSEEvent converts an A2A artifact event to an sseEvent, or nil
+// when the artifact carries no text.
+func artifactSSEEvent(evt *a2aStreamEvent) *sseEvent {
+	text := evt.Artifact.text()
 	if text == "" {
 		return nil
 	}
@@ -231,10 +311,21 @@ func (b *httpBridge) parseArtifactEvent(evt *a2aStreamEvent) *sseEvent {
 
 // writeSSEEvent writes a single SSE event to the response writer.
 func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, evt *sseEvent) error {
+	return writeSSEEventWithID(w, flusher, "", evt)
+}
+
+// writeSSEEventWithID writes a single SSE event preceded by an "id:" field
+// when id is non-empty, so clients can resume with Last-Event-ID.
+func writeSSEEventWithID(w http.ResponseWriter, flusher http.Flusher, id string, evt *sseEvent) error {
 	data, err := json.Marshal(evt)
 	if err != nil {
 		return err
 	}
+	if id != "" {
+		if _, err = fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
 	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
 	if err != nil {
 		return err
@@ -243,16 +334,26 @@ func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, evt *sseEvent) e
 	return nil
 }
 
-// writeSSEDone writes the terminal SSE done event.
-func writeSSEDone(w http.ResponseWriter, flusher http.Flusher) {
-	_, _ = fmt.Fprintf(w, "data: %s\n\n", `{"type":"done"}`)
+// writeSSEHeartbeat writes an SSE comment that clients ignore but that keeps
+// proxies from closing an idle stream.
+func writeSSEHeartbeat(w http.ResponseWriter, flusher http.Flusher) error {
+	if _, err := io.WriteString(w, sseHeartbeat); err != nil {
+		return err
+	}
 	flusher.Flush()
+	return nil
+}
+
+// writeSSEDone writes the terminal SSE done event with the stream's
+// timings, which may be nil.
+func writeSSEDone(w http.ResponseWriter, flusher http.Flusher, timings *invocationTimings) {
+	_ = writeSSEEvent(w, flusher, &sseEvent{Type: sseTypeDone, Timings: timings})
 }
 
 // isTerminalState returns true for A2A task states that indicate completion.
 func isTerminalState(state string) bool {
 	switch state {
-	case "completed", stateFailed, "canceled", "rejected":
+	case stateCompleted, stateFailed, "canceled", "rejected":
 		return true
 	}
 	return false
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestWantsSSE(t *testing.T) {
@@ -186,7 +187,7 @@ func TestWriteSSEEvent(t *testing.T) {
 
 func TestWriteSSEDone(t *testing.T) {
 	w := httptest.NewRecorder()
-	writeSSEDone(w, w)
+	writeSSEDone(w, w, nil)
 	body := w.Body.String()
 	if !strings.Contains(body, `"type":"done"`) {
 		t.Errorf("expected done event, got %q", body)
@@ -211,7 +212,7 @@ func TestRelaySSEEvents(t *testing.T) {
 	r := httptest.NewRequest(http.MethodPost, "/", nil)
 	w := httptest.NewRecorder()
 
-	b.relaySSEEvents(w, r, strings.NewReader(sseData))
+	b.relaySSEEvents(w, r, strings.NewReader(sseData), time.Now(), "")
 
 	resp := w.Result()
 	body, _ := io.ReadAll(resp.Body)
@@ -150,47 +150,27 @@ func TestBuildA2ARequest_WithMetadata(t *testing.T) {
 
 func TestExtractArtifactText(t *testing.T) {
 	resp := &a2aResponse{}
-	resp.Result.Artifacts = []struct {
-		Parts []struct {
-			Text string `json:"text"`
-		} `json:"parts"`
-		Metadata map[string]any `json:"metadata,omitempty"`
-	}{
-		{Parts: []struct {
-			Text string `json:"text"`
-		}{
-			{Text: "hello "},
-			{Text: "world"},
-		}},
+	resp.Result.Artifacts = []a2aArtifact{
+		{Parts: []a2aPart{textPart("hello "), textPart("world")}},
 	}
 	if got := extractArtifactText(resp); got != "hello world" {
 		t.Errorf("extractArtifactText = %q, want %q", got, "hello world")
 	}
 }
 
-func TestExtractFailedMessage_Default(t *testing.T) {
+func TestFailureText_Default(t *testing.T) {
 	resp := &a2aResponse{}
-	if got := extractFailedMessage(resp); got != "agent task failed" {
-		t.Errorf("extractFailedMessage = %q, want %q", got, "agent task failed")
+	if got := resp.Result.Status.failureText(); got != "agent task failed" {
+		t.Errorf("failureText = %q, want %q", got, "agent task failed")
 	}
 }
 
-func TestExtractFailedMessage_WithMessage(t *testing.T) {
+func TestFailureText_WithMessage(t *testing.T) {
 	errText := "something went wrong"
 	resp := &a2aResponse{}
-	resp.Result.Status.Message = &struct {
-		Parts []struct {
-			Text *string `json:"text"`
-		} `json:"parts"`
-	}{
-		Parts: []struct {
-			Text *string `json:"text"`
-		}{
-			{Text: &errText},
-		},
-	}
-	if got := extractFailedMessage(resp); got != errText {
-		t.Errorf("extractFailedMessage = %q, want %q", got, errText)
+	resp.Result.Status.Message = &a2aMessage{Parts: []a2aPart{textPart(errText)}}
+	if got := resp.Result.Status.failureText(); got != errText {
+		t.Errorf("failureText = %q, want %q", got, errText)
 	}
 }
 
@@ -204,11 +184,8 @@ func TestExtractUsage(t *testing.T) {
 
 	t.Run("with usage", func(t *testing.T) {
 		resp := &a2aResponse{}
-		resp.Result.Metadata = map[string]any{
-			"usage": map[string]any{
-				"input_tokens":  float64(100),
-				"output_tokens": float64(50),
-			},
+		resp.Result.Metadata = map[string]json.RawMessage{
+			"usage": json.RawMessage(`{"input_tokens": 100, "output_tokens": 50}`),
 		}
 		got := extractUsage(resp)
 		if got == nil {
@@ -224,8 +201,8 @@ func TestExtractUsage(t *testing.T) {
 
 	t.Run("zero usage", func(t *testing.T) {
 		resp := &a2aResponse{}
-		resp.Result.Metadata = map[string]any{
-			"usage": map[string]any{},
+		resp.Result.Metadata = map[string]json.RawMessage{
+			"usage": json.RawMessage(`{}`),
 		}
 		if got := extractUsage(resp); got != nil {
 			t.Errorf("expected nil for zero usage, got %+v", got)
@@ -247,7 +224,7 @@ func TestWriteA2AResponse_Success(t *testing.T) {
 	}`
 	w := httptest.NewRecorder()
 	b := &httpBridge{log: slog.Default()}
-	b.writeA2AResponse(w, []byte(a2aJSON))
+	b.writeA2AResponse(w, []byte(a2aJSON), nil, "")
 
 	resp := w.Result()
 	body, _ := io.ReadAll(resp.Body)
@@ -282,7 +259,7 @@ func TestWriteA2AResponse_Error(t *testing.T) {
 	a2aJSON := `{"error": {"message": "model error"}}`
 	w := httptest.NewRecorder()
 	b := &httpBridge{log: slog.Default()}
-	b.writeA2AResponse(w, []byte(a2aJSON))
+	b.writeA2AResponse(w, []byte(a2aJSON), nil, "")
 
 	resp := w.Result()
 	body, _ := io.ReadAll(resp.Body)
@@ -304,7 +281,7 @@ func TestWriteA2AResponse_Failed(t *testing.T) {
 	a2aJSON := `{"result": {"status": {"state": "failed"}}}`
 	w := httptest.NewRecorder()
 	b := &httpBridge{log: slog.Default()}
-	b.writeA2AResponse(w, []byte(a2aJSON))
+	b.writeA2AResponse(w, []byte(a2aJSON), nil, "")
 
 	resp := w.Result()
 	body, _ := io.ReadAll(resp.Body)
@@ -328,7 +305,7 @@ func TestWriteA2AResponse_NoMetadata(t *testing.T) {
 	}`
 	w := httptest.NewRecorder()
 	b := &httpBridge{log: slog.Default()}
-	b.writeA2AResponse(w, []byte(a2aJSON))
+	b.writeA2AResponse(w, []byte(a2aJSON), nil, "")
 
 	resp := w.Result()
 	body, _ := io.ReadAll(resp.Body)
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Rejection messages for invocations turned away by the limiter.
+const (
+	msgRateLimited        = "rate limit exceeded"
+	msgSessionRateLimited = "session rate limit exceeded"
+	msgTooManyConcurrent  = "too many concurrent invocations"
+
+	msgSessionTooManyConcurrent = "too many concurrent invocations for session"
+)
+
+// retryAfterHeader tells a rejected client how long to wait.
+const retryAfterHeader = "Retry-After"
+
+// concurrencyRetryAfter is the Retry-After sent when the concurrency cap is
+// hit; a slot frees when any running invocation finishes, which cannot be
+// predicted.
+const concurrencyRetryAfter = time.Second
+
+// maxSessionLimiters caps the per-session limiter map. Limiters idle for
+// longer than sessionLimiterIdle are pruned when the cap is reached.
+const (
+	maxSessionLimiters = 10000
+	sessionLimiterIdle = 10 * time.Minute
+)
+
+// invocationLimiter applies the global and per-session token buckets and
+// the global and per-session concurrent invocation caps to /invocations.
+// The per-session cap keeps one session's SSE streams from taking every
+// global slot.
+type invocationLimiter struct {
+	global *rate.Limiter // nil = no global rate limit
+	slots  chan struct{} // nil = no concurrency cap
+
+	sessionRate  rate.Limit // 0 = no per-session rate limit
+	sessionBurst int
+	sessionSlots int // per-session concurrency cap, 0 = none
+
+	mu       sync.Mutex
+	sessions map[string]*sessionLimiter
+	inFlight map[string]int   // running invocations per session
+	now      func() time.Time // nil uses time.Now
+}
+
+// sessionLimiter is one session's token bucket.
+type sessionLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// buildInvocationLimiter returns the limiter configured in cfg, or nil when
+// no limit is set.
+func buildInvocationLimiter(cfg *runtimeConfig) *invocationLimiter {
+	if cfg.RateLimit == 0 && cfg.SessionRateLimit == 0 && cfg.MaxConcurrentInvocations == 0 &&
+		cfg.SessionMaxConcurrent == 0 {
+		return nil
+	}
+	l := &invocationLimiter{
+		sessionRate:  rate.Limit(cfg.SessionRateLimit),
+		sessionBurst: burstFor(cfg.SessionRateLimit, cfg.SessionRateBurst),
+		sessionSlots: cfg.SessionMaxConcurrent,
+		sessions:     make(map[string]*sessionLimiter),
+		inFlight:     make(map[string]int),
+	}
+	if cfg.RateLimit > 0 {
+		l.global = rate.NewLimiter(rate.Limit(cfg.RateLimit), burstFor(cfg.RateLimit, cfg.RateBurst))
+	}
+	if cfg.MaxConcurrentInvocations > 0 {
+		l.slots = make(chan struct{}, cfg.MaxConcurrentInvocations)
+	}
+	return l
+}
+
+// burstFor returns the configured burst, or the rate rounded up (at least
+// one) when none is set.
+func burstFor(perSecond float64, burst int) int {
+	if burst > 0 {
+		return burst
+	}
+	return max(1, int(math.Ceil(perSecond)))
+}
+
+func (l *invocationLimiter) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+	return l.now()
+}
+
+// acquire admits one invocation. On success it returns a release func that
+// frees the concurrency slots. On rejection it returns the client message
+// and how long the client should wait; no tokens are consumed.
+func (l *invocationLimiter) acquire(sessionID string) (release func(), msg string, retryAfter time.Duration) {
+	now := l.clock()
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+
+	if l.global != nil {
+		r := l.global.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > 0 {
+			cancel()
+			return nil, msgRateLimited, d
+		}
+	}
+	if sl := l.sessionLimiter(sessionID, now); sl != nil {
+		r := sl.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > 0 {
+			cancel()
+			return nil, msgSessionRateLimited, d
+		}
+	}
+	releaseSession, ok := l.enterSession(sessionID)
+	if !ok {
+		cancel()
+		return nil, msgSessionTooManyConcurrent, concurrencyRetryAfter
+	}
+	if l.slots == nil {
+		return releaseSession, "", 0
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots; releaseSession() }, "", 0
+	default:
+		releaseSession()
+		cancel()
+		return nil, msgTooManyConcurrent, concurrencyRetryAfter
+	}
+}
+
+// enterSession takes one of sessionID's concurrency slots. It returns the
+// func that frees it, and false when the session has none free. Requests
+// without a session ID skip the per-session cap.
+func (l *invocationLimiter) enterSession(sessionID string) (release func(), ok bool) {
+	if l.sessionSlots == 0 || sessionID == "" {
+		return func() {}, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[sessionID] >= l.sessionSlots {
+		return nil, false
+	}
+	l.inFlight[sessionID]++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.inFlight[sessionID]--; l.inFlight[sessionID] <= 0 {
+			delete(l.inFlight, sessionID)
+		}
+	}, true
+}
+
+// sessionLimiter returns the token bucket for sessionID, or nil when there
+// is no per-session limit or no session.
+func (l *invocationLimiter) sessionLimiter(sessionID string, now time.Time) *rate.Limiter {
+	if l.sessionRate == 0 || sessionID == "" {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sl, ok := l.sessions[sessionID]
+	if !ok {
+		if len(l.sessions) >= maxSessionLimiters {
+			l.pruneSessions(now)
+		}
+		sl = &sessionLimiter{limiter: rate.NewLimiter(l.sessionRate, l.sessionBurst)}
+		l.sessions[sessionID] = sl
+	}
+	sl.lastSeen = now
+	return sl.limiter
+}
+
+// pruneSessions drops idle session limiters. An idle limiter has refilled,
+// so dropping it does not loosen the limit. The caller holds l.mu.
+func (l *invocationLimiter) pruneSessions(now time.Time) {
+	for id, sl := range l.sessions {
+		if now.Sub(sl.lastSeen) > sessionLimiterIdle {
+			delete(l.sessions, id)
+		}
+	}
+}
+
+// limiterSlotKey is the request context key of the limiterSlot an
+// admitted invocation holds.
+type limiterSlotKey struct{}
+
+// limiterSlot holds the release of the slots an admitted invocation took.
+// wrap frees them when the handler returns, unless the handler kept them
+// for work that outlives the request.
+type limiterSlot struct {
+	release func()
+}
+
+// free releases the slots unless a handler kept them.
+func (s *limiterSlot) free() {
+	if s.release != nil {
+		s.release()
+		s.release = nil
+	}
+}
+
+// keepLimiterSlot takes over the slots the invocation of ctx's request
+// holds, and returns the func that frees them. The caller must call it
+// once its work finishes. Without a limiter it returns a no-op.
+func keepLimiterSlot(ctx context.Context) func() {
+	s, _ := ctx.Value(limiterSlotKey{}).(*limiterSlot)
+	if s == nil || s.release == nil {
+		return func() {}
+	}
+	release := s.release
+	s.release = nil
+	return release
+}
+
+// retryAfterSeconds rounds a refusal's retry delay up to whole seconds, at
+// least one.
+func retryAfterSeconds(retryAfter time.Duration) int {
+	return max(1, int(math.Ceil(retryAfter.Seconds())))
+}
+
+// wrap applies the limiter to next. Rejected requests get 429 with
+// Retry-After in whole seconds. A nil limiter returns next unchanged.
+func (l *invocationLimiter) wrap(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		release, msg, retryAfter := l.acquire(r.Header.Get(sessionHeader))
+		if release == nil {
+			w.Header().Set(retryAfterHeader, strconv.Itoa(retryAfterSeconds(retryAfter)))
+			writeInvocationStatus(w, http.StatusTooManyRequests, msg)
+			return
+		}
+		slot := &limiterSlot{release: release}
+		defer slot.free()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), limiterSlotKey{}, slot)))
+	})
+}
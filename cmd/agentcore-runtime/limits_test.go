@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(cfg *runtimeConfig, now *time.Time) *invocationLimiter {
+	l := buildInvocationLimiter(cfg)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestBuildInvocationLimiter(t *testing.T) {
+	if l := buildInvocationLimiter(&runtimeConfig{}); l != nil {
+		t.Error("limiter built without any limit")
+	}
+	l := buildInvocationLimiter(&runtimeConfig{RateLimit: 2.5, SessionRateLimit: 1, SessionRateBurst: 4})
+	if l.global == nil || l.global.Burst() != 3 {
+		t.Errorf("global burst = %v, want the rate rounded up", l.global)
+	}
+	if l.sessionBurst != 4 || l.slots != nil {
+		t.Errorf("sessionBurst = %d, slots = %v", l.sessionBurst, l.slots)
+	}
+}
+
+func TestInvocationLimiter_GlobalRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestLimiter(&runtimeConfig{RateLimit: 1, RateBurst: 2}, &now)
+
+	for i := range 2 {
+		if release, msg, _ := l.acquire(""); release == nil {
+			t.Fatalf("request %d rejected: %s", i, msg)
+		}
+	}
+	release, msg, retryAfter := l.acquire("")
+	if release != nil || msg != msgRateLimited {
+		t.Fatalf("third request: msg = %q, want %q", msg, msgRateLimited)
+	}
+	if retryAfter != time.Second {
+		t.Errorf("retryAfter = %v, want 1s", retryAfter)
+	}
+
+	now = now.Add(time.Second)
+	if release, msg, _ := l.acquire(""); release == nil {
+		t.Errorf("request after refill rejected: %s", msg)
+	}
+}
+
+func TestInvocationLimiter_SessionRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestLimiter(&runtimeConfig{RateLimit: 10, SessionRateLimit: 0.5}, &now)
+
+	if release, _, _ := l.acquire("s-1"); release == nil {
+		t.Fatal("first request on s-1 rejected")
+	}
+	release, msg, retryAfter := l.acquire("s-1")
+	if release != nil || msg != msgSessionRateLimited || retryAfter != 2*time.Second {
+		t.Fatalf("second request on s-1: msg = %q, retryAfter = %v", msg, retryAfter)
+	}
+	if release, _, _ := l.acquire("s-2"); release == nil {
+		t.Error("request on another session rejected")
+	}
+	if release, _, _ := l.acquire(""); release == nil {
+		t.Error("request without a session rejected by the session limit")
+	}
+
+	// The rejected request must not have spent a global token: 10 burst
+	// minus the three admitted requests leaves seven.
+	for i := range 7 {
+		if release, msg, _ := l.acquire(""); release == nil {
+			t.Fatalf("global request %d rejected: %s", i, msg)
+		}
+	}
+	if release, _, _ := l.acquire(""); release != nil {
+		t.Error("global bucket not exhausted")
+	}
+}
+
+func TestInvocationLimiter_Concurrency(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestLimiter(&runtimeConfig{MaxConcurrentInvocations: 1}, &now)
+
+	release, _, _ := l.acquire("s-1")
+	if release == nil {
+		t.Fatal("first request rejected")
+	}
+	if r, msg, retryAfter := l.acquire("s-2"); r != nil || msg != msgTooManyConcurrent || retryAfter <= 0 {
+		t.Fatalf("second request: msg = %q, retryAfter = %v", msg, retryAfter)
+	}
+	release()
+	if r, _, _ := l.acquire("s-2"); r == nil {
+		t.Error("request after release rejected")
+	}
+}
+
+func TestInvocationLimiter_SessionConcurrency(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestLimiter(&runtimeConfig{MaxConcurrentInvocations: 3, SessionMaxConcurrent: 2}, &now)
+
+	first, _, _ := l.acquire("s-1")
+	second, _, _ := l.acquire("s-1")
+	if first == nil || second == nil {
+		t.Fatal("requests within the session cap rejected")
+	}
+	r, msg, retryAfter := l.acquire("s-1")
+	if r != nil || msg != msgSessionTooManyConcurrent || retryAfter <= 0 {
+		t.Fatalf("third request on s-1: msg = %q, retryAfter = %v", msg, retryAfter)
+	}
+	// The rejected request must not hold a global slot: the one left
+	// admits another session.
+	third, _, _ := l.acquire("s-2")
+	if third == nil {
+		t.Fatal("request on another session rejected")
+	}
+	if r, msg, _ := l.acquire("s-3"); r != nil || msg != msgTooManyConcurrent {
+		t.Fatalf("request past the global cap: msg = %q", msg)
+	}
+	if len(l.inFlight) != 2 {
+		t.Errorf("inFlight = %v, want s-1 and s-2 only", l.inFlight)
+	}
+
+	first()
+	if r, _, _ := l.acquire("s-1"); r == nil {
+		t.Error("request on s-1 after release rejected")
+	}
+	second()
+	third()
+	if _, ok := l.inFlight["s-2"]; ok {
+		t.Error("idle session still counted in flight")
+	}
+}
+
+func TestInvocationLimiter_PrunesIdleSessions(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestLimiter(&runtimeConfig{SessionRateLimit: 1}, &now)
+	l.acquire("old")
+	now = now.Add(sessionLimiterIdle + time.Second)
+	for i := range maxSessionLimiters - 1 {
+		l.sessions[fmt.Sprintf("s-%d", i)] = &sessionLimiter{lastSeen: now}
+	}
+	l.acquire("new")
+	if _, ok := l.sessions["old"]; ok {
+		t.Error("idle session limiter not pruned")
+	}
+	if _, ok := l.sessions["new"]; !ok {
+		t.Error("new session limiter not stored")
+	}
+}
+
+func TestInvocationLimiter_Wrap(t *testing.T) {
+	var calls int
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	})
+	if h := (*invocationLimiter)(nil).wrap(next); h == nil {
+		t.Fatal("nil limiter returned a nil handler")
+	}
+
+	now := time.Unix(1000, 0)
+	h := newTestLimiter(&runtimeConfig{SessionRateLimit: 0.25}, &now).wrap(next)
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, invocationsPath, nil)
+		req.Header.Set(sessionHeader, "s-1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(); rec.Code != http.StatusOK {
+		t.Fatalf("first status = %d, want 200", rec.Code)
+	}
+	rec := serve()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get(retryAfterHeader); got != "4" {
+		t.Errorf("Retry-After = %q, want 4", got)
+	}
+	var body invocationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Response != msgSessionRateLimited {
+		t.Errorf("body = %s, want the session rate limit message", rec.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
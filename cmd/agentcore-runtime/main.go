@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net"
@@ -12,8 +13,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
 	"github.com/AltairaLabs/PromptKit/runtime/prompt"
-	"github.com/AltairaLabs/PromptKit/sdk"
 	a2aserver "github.com/AltairaLabs/PromptKit/server/a2a"
 )
 
@@ -25,7 +26,18 @@ const (
 )
 
 func main() {
+	printCfg := flag.Bool("print-config", false,
+		"print the effective configuration (secrets redacted) and exit")
+	flag.Parse()
+
 	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	if *printCfg {
+		if err := runPrintConfig(os.Stdout); err != nil {
+			log.Error("fatal", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 	if err := run(log); err != nil {
 		log.Error("fatal", "error", err)
 		os.Exit(1)
@@ -58,23 +70,31 @@ func run(log *slog.Logger) error {
 		return err
 	}
 	log.Info("resolved agent", "name", agentName, "pack", cfg.PackFile,
-		"provider_type", cfg.ProviderType, "model", cfg.Model,
-		"aws_region", cfg.AWSRegion, "agent_name_env", cfg.AgentName)
+		"provider_type", cfg.ProviderType, "model", cfg.Model, "inference_profile", cfg.InferenceProfile,
+		"aws_region", cfg.AWSRegion, "agent_name_env", cfg.AgentName, "gateway_search", cfg.GatewaySearch,
+		"allowed_tools", cfg.AllowedTools)
+
+	healthH := newHealthHandler()
 
-	shutdownTracing := setupTracing(cfg, log)
+	shutdownTracing := setupTracing(cfg, log, healthH)
 	defer func() {
 		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer cancel()
 		_ = shutdownTracing(ctx)
 	}()
 
-	sdkOpts := buildSDKOptions(cfg)
-	opener := sdk.A2AOpener(cfg.PackFile, agentName, sdkOpts...)
+	sdkOpts := buildSDKOptions(cfg, healthH)
+	opener := buildOpener(cfg.PackFile, agentName, sdkOpts, buildToolAudit(cfg, log))
 
-	card := buildAgentCard(pack, agentName)
-	a2aSrv := a2aserver.NewServer(opener, a2aserver.WithCard(card))
+	card := buildAgentCard(pack, agentName, cfg)
+	serverOpts := []a2aserver.Option{a2aserver.WithCard(card)}
+	tasks := buildTaskStore(cfg, log)
+	if tasks != nil {
+		serverOpts = append(serverOpts, a2aserver.WithTaskStore(tasks))
+		defer tasks.wait()
+	}
+	a2aSrv := a2aserver.NewServer(opener, serverOpts...)
 
-	healthH := newHealthHandler()
 	mux := buildMux(a2aSrv.Handler(), healthH)
 
 	// Start A2A server if protocol allows it.
@@ -88,19 +108,31 @@ func run(log *slog.Logger) error {
 		}
 		log.Info("a2a server listening", "addr", ln.Addr().String(),
 			"version", version, "protocol", cfg.Protocol)
+		healthH.setComponent(componentA2AServer, healthOK)
 	} else {
 		log.Info("a2a server skipped", "protocol", cfg.Protocol)
+		healthH.setComponent(componentA2AServer, healthDisabled)
 	}
 
 	// Start HTTP bridge if protocol allows it.
 	var bridge *httpBridge
 	if cfg.wantHTTPBridge() {
-		bridge, err = startHTTPBridge(log, healthH, cfg.Port)
+		var bridgeCard *a2a.AgentCard
+		if !cfg.wantA2AServer() {
+			bridgeCard = card
+		}
+		moderation, modErr := buildResponseModerator(cfg, pack, agentName, log)
+		if modErr != nil {
+			return fmt.Errorf("response moderation: %w", modErr)
+		}
+		bridge, err = startHTTPBridge(log, healthH, cfg, bridgeCard, moderation)
 		if err != nil {
 			return fmt.Errorf("http bridge: %w", err)
 		}
+		healthH.setComponent(componentHTTPBridge, healthOK)
 	} else {
 		log.Info("http bridge skipped", "protocol", cfg.Protocol)
+		healthH.setComponent(componentHTTPBridge, healthDisabled)
 	}
 
 	return runWithShutdown(log, ln, mux, healthH, a2aSrv, bridge)
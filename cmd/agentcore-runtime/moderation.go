@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// Validator types the bridge enforces on responses. banned_words and
+// content_excludes forbid words; regex and regex_match require, or with
+// expect_match false forbid, a pattern.
+const (
+	validatorBannedWords     = "banned_words"
+	validatorContentExcludes = "content_excludes"
+	validatorRegex           = "regex"
+	validatorRegexMatch      = "regex_match"
+)
+
+// Word match modes of banned_words and content_excludes validators.
+const (
+	matchModeSubstring    = "substring"
+	matchModeWordBoundary = "word_boundary"
+)
+
+// statusBlocked is the invocation status of a response moderation
+// withheld.
+const statusBlocked = "blocked"
+
+// metadataModeration is the invocation response metadata key carrying
+// moderation violations.
+const metadataModeration = "moderation"
+
+// sseTypeModeration is the type of the SSE event that reports violations
+// of annotating validators in a stream.
+const sseTypeModeration = "moderation"
+
+// moderationRule is one pack validator enforced on responses.
+type moderationRule struct {
+	validator string
+	block     bool   // withhold the response; otherwise annotate it
+	message   string // the response sent in place of a blocked one
+	// check returns what in text violates the rule, or "".
+	check func(text string) string
+}
+
+// moderationViolation reports one rule a response broke.
+type moderationViolation struct {
+	Validator string `json:"validator"`
+	Detail    string `json:"detail"`
+	Blocking  bool   `json:"blocking"`
+}
+
+// responseModerator applies the agent prompt's banned-word and regex
+// validators to outbound text, blocking or annotating responses that
+// violate them according to each validator's fail_on_violation.
+type responseModerator struct {
+	rules []moderationRule
+	log   *slog.Logger
+}
+
+// buildResponseModerator returns the moderator for the agent's prompt
+// validators, or nil when moderation is off or the prompt has none the
+// bridge enforces. Validators of other types are left to the SDK.
+func buildResponseModerator(
+	cfg *runtimeConfig, pack *prompt.Pack, agentName string, log *slog.Logger,
+) (*responseModerator, error) {
+	if !cfg.ResponseModeration {
+		return nil, nil
+	}
+	p := pack.Prompts[agentName]
+	if p == nil {
+		return nil, nil
+	}
+	m := &responseModerator{log: log}
+	for i, v := range p.Validators {
+		if v.Enabled != nil && !*v.Enabled {
+			continue
+		}
+		rule, ok, err := newModerationRule(v)
+		if err != nil {
+			return nil, fmt.Errorf("prompt %q validator %d: %w", agentName, i, err)
+		}
+		if ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	if len(m.rules) == 0 {
+		log.Info("response moderation enabled, but the prompt has no banned_words or regex validators",
+			"agent", agentName)
+		return nil, nil
+	}
+	log.Info("response moderation enabled", "agent", agentName, "validators", len(m.rules))
+	return m, nil
+}
+
+// newModerationRule builds the rule for v. It reports false for validator
+// types the bridge does not enforce.
+func newModerationRule(v prompt.ValidatorConfig) (moderationRule, bool, error) {
+	rule := moderationRule{
+		validator: v.Type,
+		block:     v.FailOnViolation == nil || *v.FailOnViolation,
+		message:   v.Message,
+	}
+	if rule.message == "" {
+		rule.message, _ = v.Params["message"].(string)
+	}
+	if rule.message == "" {
+		rule.message = prompt.DefaultBlockedMessage
+	}
+
+	var err error
+	switch v.Type {
+	case validatorBannedWords:
+		rule.check, err = wordCheck(v.Params, matchModeWordBoundary)
+	case validatorContentExcludes:
+		rule.check, err = wordCheck(v.Params, matchModeSubstring)
+	case validatorRegex, validatorRegexMatch:
+		rule.check, err = regexCheck(v.Params)
+	default:
+		return moderationRule{}, false, nil
+	}
+	if err != nil {
+		return moderationRule{}, false, fmt.Errorf("%s: %w", v.Type, err)
+	}
+	return rule, true, nil
+}
+
+// wordCheck returns a check for the words or patterns param, matched in
+// any case as whole words or, in substring mode, anywhere.
+func wordCheck(params map[string]any, defaultMode string) (func(string) string, error) {
+	words := stringList(params["words"])
+	words = append(words, stringList(params["patterns"])...)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("words is required")
+	}
+	mode, _ := params["match_mode"].(string)
+	if mode == "" {
+		mode = defaultMode
+	}
+	if mode != matchModeSubstring && mode != matchModeWordBoundary {
+		return nil, fmt.Errorf("match_mode must be %q or %q, got %q", matchModeSubstring, matchModeWordBoundary, mode)
+	}
+	res := make([]*regexp.Regexp, len(words))
+	for i, w := range words {
+		expr := `(?i)` + regexp.QuoteMeta(w)
+		if mode == matchModeWordBoundary {
+			expr = `(?i)\b` + regexp.QuoteMeta(w) + `\b`
+		}
+		res[i] = regexp.MustCompile(expr)
+	}
+	return func(text string) string {
+		for i, re := range res {
+			if re.MatchString(text) {
+				return fmt.Sprintf("contains banned word %q", words[i])
+			}
+		}
+		return ""
+	}, nil
+}
+
+// regexCheck returns a check for the pattern param: text must match it,
+// or with expect_match false must not.
+func regexCheck(params map[string]any) (func(string) string, error) {
+	pattern, _ := params["pattern"].(string)
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	expectMatch := true
+	if v, ok := params["expect_match"].(bool); ok {
+		expectMatch = v
+	}
+	return func(text string) string {
+		switch matched := re.MatchString(text); {
+		case matched && !expectMatch:
+			return fmt.Sprintf("matches forbidden pattern %q", pattern)
+		case !matched && expectMatch:
+			return fmt.Sprintf("does not match required pattern %q", pattern)
+		}
+		return ""
+	}, nil
+}
+
+// stringList returns the strings of a string or list param.
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// check returns the violations of text and, when a blocking rule is
+// violated, the message to send in its place.
+func (m *responseModerator) check(text string) (violations []moderationViolation, blocked string) {
+	for _, r := range m.rules {
+		detail := r.check(text)
+		if detail == "" {
+			continue
+		}
+		violations = append(violations, moderationViolation{Validator: r.validator, Detail: detail, Blocking: r.block})
+		if r.block && blocked == "" {
+			blocked = r.message
+		}
+	}
+	if len(violations) > 0 {
+		m.log.Warn("response moderated", "violations", len(violations), "blocked", blocked != "")
+	}
+	return violations, blocked
+}
+
+// apply moderates a successful invocation response: a blocking violation
+// replaces its text with the validator's message and status "blocked",
+// and every violation is listed under the "moderation" metadata key. A nil
+// moderator leaves resp unchanged.
+func (m *responseModerator) apply(resp *invocationResponse) {
+	if m == nil || resp.Status == keyError {
+		return
+	}
+	violations, blocked := m.check(resp.Response)
+	if len(violations) == 0 {
+		return
+	}
+	if blocked != "" {
+		resp.Response = blocked
+		resp.Status = statusBlocked
+		resp.ResponseJSON = nil
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = map[string]any{}
+	}
+	resp.Metadata[metadataModeration] = violations
+}
+
+// streamCheck returns a check of the text of a stream, or nil for a nil
+// moderator.
+func (m *responseModerator) streamCheck() *moderationStreamCheck {
+	if m == nil {
+		return nil
+	}
+	return &moderationStreamCheck{m: m}
+}
+
+// moderationStreamCheck collects the text of a stream so it can be
+// moderated once the task completes. Streamed text has already reached the
+// client by then, so a blocking violation ends the stream with an error
+// event the client must act on.
+type moderationStreamCheck struct {
+	m    *responseModerator
+	text strings.Builder
+}
+
+// observe records evt and returns the event to emit ahead of it when evt
+// completes a task whose text violates a rule: an error carrying the
+// blocked message, or a moderation event listing the violations of
+// annotating rules. It returns nil otherwise.
+func (c *moderationStreamCheck) observe(evt *sseEvent) *sseEvent {
+	switch {
+	case evt.Type == kindText:
+		c.text.WriteString(evt.Content)
+	case evt.Type == keyStatus && evt.State == stateCompleted:
+		violations, blocked := c.m.check(c.text.String())
+		if blocked != "" {
+			return &sseEvent{Type: keyError, Content: blocked, TaskID: evt.TaskID, ContextID: evt.ContextID}
+		}
+		if len(violations) > 0 {
+			details := make([]string, len(violations))
+			for i, v := range violations {
+				details[i] = v.Validator + ": " + v.Detail
+			}
+			return &sseEvent{
+				Type: sseTypeModeration, Content: strings.Join(details, "; "), TaskID: evt.TaskID, ContextID: evt.ContextID,
+			}
+		}
+	}
+	return nil
+}
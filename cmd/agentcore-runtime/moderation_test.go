@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+func newTestModerator(t *testing.T, validators ...prompt.ValidatorConfig) *responseModerator {
+	t.Helper()
+	pack := &prompt.Pack{Prompts: map[string]*prompt.PackPrompt{"chat": {ID: "chat", Validators: validators}}}
+	m, err := buildResponseModerator(&runtimeConfig{ResponseModeration: true}, pack, "chat",
+		slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestBuildResponseModerator(t *testing.T) {
+	off := false
+	pack := &prompt.Pack{Prompts: map[string]*prompt.PackPrompt{"chat": {ID: "chat", Validators: []prompt.ValidatorConfig{
+		{Type: validatorBannedWords, Params: map[string]any{"words": []any{"darn"}}},
+		{Type: "max_length", Params: map[string]any{"max": 10}},
+		{Type: validatorRegex, Params: map[string]any{"pattern": "x"}, Enabled: &off},
+	}}}}
+	log := slog.New(slog.DiscardHandler)
+
+	if m, err := buildResponseModerator(&runtimeConfig{}, pack, "chat", log); m != nil || err != nil {
+		t.Errorf("moderation off: m = %v, err = %v", m, err)
+	}
+	m, err := buildResponseModerator(&runtimeConfig{ResponseModeration: true}, pack, "chat", log)
+	if err != nil || m == nil || len(m.rules) != 1 {
+		t.Fatalf("m = %+v, err = %v, want only the banned_words rule", m, err)
+	}
+
+	pack.Prompts["chat"].Validators = []prompt.ValidatorConfig{
+		{Type: validatorRegexMatch, Params: map[string]any{"pattern": "("}},
+	}
+	if _, err := buildResponseModerator(&runtimeConfig{ResponseModeration: true}, pack, "chat", log); err == nil ||
+		!strings.Contains(err.Error(), "invalid pattern") {
+		t.Errorf("err = %v, want an invalid pattern error", err)
+	}
+}
+
+func TestResponseModerator_Apply(t *testing.T) {
+	annotate := false
+	m := newTestModerator(t,
+		prompt.ValidatorConfig{Type: validatorBannedWords, Params: map[string]any{"words": []any{"secret"}},
+			Message: "That answer is not allowed."},
+		prompt.ValidatorConfig{Type: validatorRegex, Params: map[string]any{"pattern": `\d{3}-\d{4}`, "expect_match": false},
+			FailOnViolation: &annotate},
+	)
+
+	resp := invocationResponse{Response: "the secret is out", Status: "success", ResponseJSON: json.RawMessage(`1`)}
+	m.apply(&resp)
+	if resp.Status != statusBlocked || resp.Response != "That answer is not allowed." || resp.ResponseJSON != nil {
+		t.Errorf("blocked resp = %+v", resp)
+	}
+
+	resp = invocationResponse{Response: "call 555-1234 about secrets", Status: "success"}
+	m.apply(&resp)
+	violations, _ := resp.Metadata[metadataModeration].([]moderationViolation)
+	if resp.Status != "success" || resp.Response != "call 555-1234 about secrets" || len(violations) != 1 ||
+		violations[0].Validator != validatorRegex || violations[0].Blocking {
+		t.Errorf("annotated resp = %+v", resp)
+	}
+
+	resp = invocationResponse{Response: "secret", Status: keyError}
+	m.apply(&resp)
+	if resp.Status != keyError || resp.Metadata != nil {
+		t.Errorf("error resp was moderated: %+v", resp)
+	}
+	(*responseModerator)(nil).apply(&resp)
+}
+
+func TestWriteA2AResponse_Moderation(t *testing.T) {
+	a2aJSON := `{"result":{"id":"task-1","status":{"state":"completed"},"artifacts":[{"parts":[{"text":"darn it"}]}]}}`
+	w := httptest.NewRecorder()
+	b := &httpBridge{log: slog.Default(), moderation: newTestModerator(t,
+		prompt.ValidatorConfig{Type: validatorBannedWords, Params: map[string]any{"words": "darn"}})}
+	b.writeA2AResponse(w, []byte(a2aJSON), nil, "")
+
+	var resp invocationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || resp.Status != statusBlocked || resp.Response != prompt.DefaultBlockedMessage ||
+		resp.Metadata[metadataModeration] == nil {
+		t.Errorf("status %d, resp %+v", w.Code, resp)
+	}
+}
+
+func TestRelaySSEEvents_Moderation(t *testing.T) {
+	annotate := false
+	for _, tt := range []struct {
+		block    bool
+		wantType string
+	}{
+		{true, keyError},
+		{false, sseTypeModeration},
+	} {
+		v := prompt.ValidatorConfig{Type: validatorContentExcludes, Params: map[string]any{"patterns": []any{"darn"}}}
+		if !tt.block {
+			v.FailOnViolation = &annotate
+		}
+		sseData := strings.Join([]string{
+			`data: {"result":{"taskId":"t1","artifact":{"parts":[{"text":"oh dar"}]}}}`,
+			``,
+			`data: {"result":{"taskId":"t1","artifact":{"parts":[{"text":"n it"}]}}}`,
+			``,
+			`data: {"result":{"taskId":"t1","status":{"state":"completed"}}}`,
+			``,
+		}, "\n")
+		w := httptest.NewRecorder()
+		b := &httpBridge{log: slog.Default(), moderation: newTestModerator(t, v)}
+		b.relaySSEEvents(w, httptest.NewRequest(http.MethodPost, "/", nil), strings.NewReader(sseData), time.Now(), "")
+
+		body := w.Body.String()
+		flagged := strings.Index(body, `"type":"`+tt.wantType+`"`)
+		completed := strings.Index(body, `"state":"completed"`)
+		if flagged < 0 || completed < flagged {
+			t.Errorf("block %v: want a %s event before completion, got %s", tt.block, tt.wantType, body)
+		}
+	}
+}
+
+func TestLoadConfig_ResponseModeration(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(envConfigFile, writeConfigFile(t, "runtime.yaml", "pack_file: file.pack.json\nresponse_moderation: true\n"))
+	cfg, err := loadConfig()
+	if err != nil || !cfg.ResponseModeration {
+		t.Fatalf("cfg.ResponseModeration = %v, err = %v", cfg != nil && cfg.ResponseModeration, err)
+	}
+	t.Setenv(envResponseModeration, "maybe")
+	if _, err := loadConfig(); err == nil || !strings.Contains(err.Error(), envResponseModeration) {
+		t.Errorf("err = %v, want an error naming %s", err, envResponseModeration)
+	}
+}
@@ -7,26 +7,39 @@ import (
 	"github.com/AltairaLabs/PromptKit/runtime/telemetry"
 )
 
+// defaultServiceName is the service.name of the runtime's spans when
+// OTEL_SERVICE_NAME is not set.
+const defaultServiceName = "agentcore-runtime"
+
 // tracingShutdown flushes and shuts down the trace exporter.
 type tracingShutdown func(context.Context) error
 
-// setupTracing configures OTLP trace export if enabled.
-// The A2A server already applies telemetry.TraceMiddleware for inbound header
-// extraction, and the SDK propagates trace context on outbound calls.
-func setupTracing(cfg *runtimeConfig, log *slog.Logger) tracingShutdown {
+// setupTracing configures OTLP trace export if enabled and reports the
+// exporter state to health.
+// The bridge forwards the trace headers of each invocation to the A2A
+// server, whose handler extracts them so the runtime's spans join the
+// caller's trace, and the SDK propagates trace context on outbound calls.
+func setupTracing(cfg *runtimeConfig, log *slog.Logger, health *healthHandler) tracingShutdown {
 	if !cfg.TracingEnabled || cfg.OTLPEndpoint == "" {
 		log.Info("tracing disabled")
+		health.setComponent(componentTracing, healthDisabled)
 		return func(context.Context) error { return nil }
 	}
 
-	tp, err := telemetry.NewTracerProvider(context.Background(), cfg.OTLPEndpoint, "agentcore-runtime")
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	tp, err := telemetry.NewTracerProvider(context.Background(), cfg.OTLPEndpoint, serviceName)
 	if err != nil {
 		log.Error("failed to create tracer provider", "error", err)
+		health.reportError(componentTracing, err)
 		return func(context.Context) error { return nil }
 	}
 
 	telemetry.SetupPropagation()
 
-	log.Info("tracing enabled", "endpoint", cfg.OTLPEndpoint)
+	health.setComponent(componentTracing, healthOK)
+	log.Info("tracing enabled", "endpoint", cfg.OTLPEndpoint, "service", serviceName)
 	return tp.Shutdown
 }
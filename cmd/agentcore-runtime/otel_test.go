@@ -11,7 +11,7 @@ func TestSetupTracing_Disabled(t *testing.T) {
 	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
 
 	cfg := &runtimeConfig{TracingEnabled: false}
-	shutdown := setupTracing(cfg, log)
+	shutdown := setupTracing(cfg, log, nil)
 
 	if err := shutdown(context.Background()); err != nil {
 		t.Fatalf("no-op shutdown should not error: %v", err)
@@ -22,7 +22,7 @@ func TestSetupTracing_EnabledNoEndpoint(t *testing.T) {
 	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
 
 	cfg := &runtimeConfig{TracingEnabled: true, OTLPEndpoint: ""}
-	shutdown := setupTracing(cfg, log)
+	shutdown := setupTracing(cfg, log, nil)
 
 	// Should return no-op because endpoint is empty
 	if err := shutdown(context.Background()); err != nil {
@@ -37,10 +37,21 @@ func TestSetupTracing_Enabled(t *testing.T) {
 		TracingEnabled: true,
 		OTLPEndpoint:   "http://localhost:4318",
 	}
-	shutdown := setupTracing(cfg, log)
+	shutdown := setupTracing(cfg, log, nil)
 
 	// shutdown function is from the exporter, calling it is safe even without a real collector
 	if shutdown == nil {
 		t.Fatal("expected non-nil shutdown function")
 	}
 }
+
+func TestSetupTracing_ReportsHealth(t *testing.T) {
+	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	h := newHealthHandler()
+
+	_ = setupTracing(&runtimeConfig{}, log, h)
+
+	if got := h.components[componentTracing].Status; got != healthDisabled {
+		t.Errorf("tracing component = %q, want %q", got, healthDisabled)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// outputFormatJSON is the output_format value that asks the agent for
+// structured JSON and has the bridge parse its answer.
+const outputFormatJSON = "json"
+
+// metadataOutputFormat is the A2A message metadata key carrying the
+// requested output format to the agent.
+const metadataOutputFormat = "output_format"
+
+// codeFence delimits a Markdown code block, which models often wrap JSON
+// answers in despite being asked not to.
+const codeFence = "```"
+
+// validateOutputFormat checks an invocation's output_format.
+func validateOutputFormat(format string) error {
+	if format != "" && format != outputFormatJSON {
+		return fmt.Errorf("output_format must be %q, got %q", outputFormatJSON, format)
+	}
+	return nil
+}
+
+// parseJSONOutput parses an agent's answer as JSON, ignoring surrounding
+// whitespace and a Markdown code fence.
+func parseJSONOutput(text string) (json.RawMessage, error) {
+	s := strings.TrimSpace(text)
+	if strings.HasPrefix(s, codeFence) && strings.HasSuffix(s, codeFence) && len(s) >= 2*len(codeFence) {
+		s = strings.TrimSuffix(s, codeFence)
+		if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+			s = s[nl+1:] // drop the fence line and its language tag
+		} else {
+			s = strings.TrimPrefix(s, codeFence)
+		}
+		s = strings.TrimSpace(s)
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("agent response is not valid JSON: %w", err)
+	}
+	return json.RawMessage(s), nil
+}
+
+// applyOutputFormat parses a successful response's text into ResponseJSON
+// when format is json. A response that does not parse becomes an error
+// response; it reports false in that case.
+func applyOutputFormat(resp *invocationResponse, format string) bool {
+	if format != outputFormatJSON || resp.Status == keyError {
+		return true
+	}
+	parsed, err := parseJSONOutput(resp.Response)
+	if err != nil {
+		*resp = invocationResponse{
+			Response: err.Error(), Status: keyError, TaskID: resp.TaskID, ContextID: resp.ContextID, Usage: resp.Usage,
+		}
+		return false
+	}
+	resp.ResponseJSON = parsed
+	return true
+}
+
+// jsonStreamCheck collects the text of a stream so a json output_format
+// can be checked once the task completes.
+type jsonStreamCheck struct {
+	text strings.Builder
+}
+
+// observe records evt and returns the error event to emit ahead of it when
+// evt completes a task whose text is not valid JSON, or nil.
+func (c *jsonStreamCheck) observe(evt *sseEvent) *sseEvent {
+	switch {
+	case evt.Type == kindText:
+		c.text.WriteString(evt.Content)
+	case evt.Type == keyStatus && evt.State == stateCompleted:
+		if _, err := parseJSONOutput(c.text.String()); err != nil {
+			return &sseEvent{Type: keyError, Content: err.Error(), TaskID: evt.TaskID, ContextID: evt.ContextID}
+		}
+	}
+	return nil
+}
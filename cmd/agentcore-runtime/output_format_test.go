@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseJSONOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"object", ` {"a":1} `, `{"a":1}`, false},
+		{"array", `[1,2]`, `[1,2]`, false},
+		{"fenced", "```json\n{\"a\":1}\n```", `{"a":1}`, false},
+		{"fenced without tag", "```\n[true]\n```", `[true]`, false},
+		{"prose", `Here you go: {"a":1}`, "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSONOutput(tt.text)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+					t.Errorf("err = %v, want a not valid JSON error", err)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("parseJSONOutput = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{"", outputFormatJSON} {
+		if err := validateOutputFormat(format); err != nil {
+			t.Errorf("validateOutputFormat(%q) = %v", format, err)
+		}
+	}
+	if err := validateOutputFormat("xml"); err == nil {
+		t.Error("want an error for output_format xml")
+	}
+}
+
+func TestInvocationRequest_OutputFormatMetadata(t *testing.T) {
+	var req invocationRequest
+	if err := json.Unmarshal([]byte(`{"prompt":"hi","output_format":"json"}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Extra != nil {
+		t.Errorf("Extra = %v, want output_format kept out of the payload", req.Extra)
+	}
+	if got := req.allMetadata()[metadataOutputFormat]; got != outputFormatJSON {
+		t.Errorf("metadata output_format = %v, want json", got)
+	}
+}
+
+func TestWriteA2AResponse_OutputFormatJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantStatus int
+		wantJSON   string
+	}{
+		{"valid", `{\"answer\":42}`, http.StatusOK, `{"answer":42}`},
+		{"invalid", `the answer is 42`, http.StatusBadGateway, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a2aJSON := `{"result":{"id":"task-1","status":{"state":"completed"},` +
+				`"artifacts":[{"parts":[{"text":"` + tt.text + `"}]}]}}`
+			w := httptest.NewRecorder()
+			b := &httpBridge{log: slog.Default()}
+			b.writeA2AResponse(w, []byte(a2aJSON), nil, outputFormatJSON)
+
+			var resp invocationResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.wantStatus || string(resp.ResponseJSON) != tt.wantJSON {
+				t.Errorf("status %d, response_json %s, want %d, %s", w.Code, resp.ResponseJSON, tt.wantStatus, tt.wantJSON)
+			}
+			if tt.wantJSON == "" && (resp.Status != keyError || resp.TaskID != "task-1") {
+				t.Errorf("resp = %+v, want an error naming the task", resp)
+			}
+		})
+	}
+}
+
+func TestParseA2AInvocation_OutputFormatJSON(t *testing.T) {
+	body := []byte(`{"result":{"id":"t","status":{"state":"completed"},"artifacts":[{"parts":[{"text":"[1]"}]}]}}`)
+	if resp := parseA2AInvocation(body, outputFormatJSON); string(resp.ResponseJSON) != "[1]" {
+		t.Errorf("resp = %+v, want response_json [1]", resp)
+	}
+	if resp := parseA2AInvocation(body, ""); resp.ResponseJSON != nil {
+		t.Errorf("resp = %+v, want no response_json without output_format", resp)
+	}
+}
+
+func TestRelaySSEEvents_OutputFormatJSON(t *testing.T) {
+	for _, tt := range []struct {
+		text      string
+		wantError bool
+	}{
+		{`{\"a\":`, true},
+		{`{\"a\":1}`, false},
+	} {
+		sseData := strings.Join([]string{
+			`data: {"result":{"taskId":"t1","artifact":{"parts":[{"text":"` + tt.text + `"}]}}}`,
+			``,
+			`data: {"result":{"taskId":"t1","status":{"state":"completed"}}}`,
+			``,
+		}, "\n")
+		w := httptest.NewRecorder()
+		b := &httpBridge{log: slog.Default()}
+		b.relaySSEEvents(w, httptest.NewRequest(http.MethodPost, "/", nil), strings.NewReader(sseData),
+			time.Now(), outputFormatJSON)
+
+		body := w.Body.String()
+		errAt := strings.Index(body, `"type":"error"`)
+		if tt.wantError != (errAt >= 0) {
+			t.Fatalf("text %s: body = %q, want error event %v", tt.text, body, tt.wantError)
+		}
+		if tt.wantError && errAt > strings.Index(body, `"state":"completed"`) {
+			t.Errorf("error event follows the completed status: %q", body)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 
 	"github.com/AltairaLabs/PromptKit/runtime/a2a"
 	"github.com/AltairaLabs/PromptKit/runtime/prompt"
@@ -39,15 +40,16 @@ func resolveAgentName(cfg *runtimeConfig, pack *prompt.Pack) (string, error) {
 }
 
 // buildSDKOptions creates SDK options from runtime configuration.
-func buildSDKOptions(cfg *runtimeConfig) []sdk.Option {
+func buildSDKOptions(cfg *runtimeConfig, health *healthHandler) []sdk.Option {
 	var opts []sdk.Option
 
 	if cfg.AWSRegion != "" {
 		// Provider type and model come from the arena config via env vars.
-		opts = append(opts, sdk.WithBedrock(cfg.AWSRegion, cfg.ProviderType, cfg.Model))
+		opts = append(opts, sdk.WithBedrock(cfg.AWSRegion, cfg.ProviderType, bedrockModelID(cfg)))
 	}
 
-	opts = append(opts, sdk.WithStateStore(buildStateStore(cfg)))
+	opts = append(opts, sdk.WithStateStore(buildStateStore(cfg, health)))
+	opts = append(opts, historyOptions(cfg)...)
 
 	if len(cfg.AgentEndpoints) > 0 {
 		opts = append(opts, sdk.WithAgentEndpoints(&sdk.MapEndpointResolver{
@@ -58,40 +60,62 @@ func buildSDKOptions(cfg *runtimeConfig) []sdk.Option {
 	return opts
 }
 
+// bedrockModelID returns the model identifier to invoke: the inference
+// profile when one is configured, otherwise the provider model. Bedrock
+// addresses models as /model/{id}/invoke, so the profile is path-escaped
+// to keep the "/" in application inference profile ARNs inside one segment.
+func bedrockModelID(cfg *runtimeConfig) string {
+	if cfg.InferenceProfile != "" {
+		return url.PathEscape(cfg.InferenceProfile)
+	}
+	return cfg.Model
+}
+
 // buildStateStore creates the appropriate state store based on config.
 // If a memory ID and AWS region are configured, it uses the AgentCore
 // data-plane SDK. Otherwise it falls back to a volatile in-memory store.
-func buildStateStore(cfg *runtimeConfig) statestore.Store {
+// A failed memory init is reported to health as a degraded state store.
+func buildStateStore(cfg *runtimeConfig, health *healthHandler) statestore.Store {
 	if cfg.MemoryID != "" && cfg.AWSRegion != "" {
 		dpClient, err := agentcore.NewDataPlaneClient(cfg.AWSRegion)
 		if err != nil {
 			slog.Warn("AgentCore memory init failed, using in-memory store",
 				"error", err)
+			health.reportError(componentStateStore, err)
+			health.setDegraded(stateStoreFallbackReason)
 			return statestore.NewMemoryStore()
 		}
-		return agentcore.NewStateStore(cfg.MemoryID, dpClient)
+		health.setComponent(componentStateStore, healthOK)
+		return agentcore.NewNamespacedStateStore(cfg.MemoryID, cfg.MemoryNamespace, dpClient)
 	}
+	health.setComponent(componentStateStore, healthOK)
 	return statestore.NewMemoryStore()
 }
 
-// buildAgentCard generates an A2A AgentCard for the named agent from the pack.
+// buildAgentCard generates an A2A AgentCard for the named agent from the pack,
+// advertises the runtime's enabled protocols on it, and enriches it with pack
+// metadata and deploy-time overrides.
 // Falls back to a minimal card if the pack has no agents section.
-func buildAgentCard(pack *prompt.Pack, agentName string) *a2a.AgentCard {
+func buildAgentCard(pack *prompt.Pack, agentName string, cfg *runtimeConfig) *a2a.AgentCard {
 	cards := agentcard.GenerateAgentCards(pack)
-	if card, ok := cards[agentName]; ok {
-		return card
-	}
-
-	// Fallback: minimal card from pack metadata.
-	description := ""
-	if p, ok := pack.Prompts[agentName]; ok {
-		description = p.Description
+	card, ok := cards[agentName]
+	if !ok {
+		// Fallback: minimal card from pack metadata.
+		description := ""
+		if p, found := pack.Prompts[agentName]; found {
+			description = p.Description
+		}
+		card = &a2a.AgentCard{
+			Name:        agentName,
+			Description: description,
+		}
 	}
-	return &a2a.AgentCard{
-		Name:        agentName,
-		Description: description,
-		Version:     pack.Version,
+	if card.Version == "" {
+		card.Version = pack.Version
 	}
+	applyRuntimeCapabilities(card, cfg)
+	enrichAgentCard(card, pack, agentName, cfg)
+	return card
 }
 
 // buildMux creates the HTTP mux with A2A and health routes.
@@ -124,7 +124,7 @@ func TestBuildAgentCard_FromPack(t *testing.T) {
 		},
 	}
 
-	card := buildAgentCard(pack, "myagent")
+	card := buildAgentCard(pack, "myagent", &runtimeConfig{})
 	if card.Name != "myagent" {
 		t.Errorf("card.Name = %q, want %q", card.Name, "myagent")
 	}
@@ -141,7 +141,7 @@ func TestBuildAgentCard_Fallback(t *testing.T) {
 		},
 	}
 
-	card := buildAgentCard(pack, "chat")
+	card := buildAgentCard(pack, "chat", &runtimeConfig{})
 	if card.Name != "chat" {
 		t.Errorf("card.Name = %q, want %q", card.Name, "chat")
 	}
@@ -155,7 +155,7 @@ func TestBuildAgentCard_Fallback(t *testing.T) {
 
 func TestBuildStateStore_WithoutMemoryID(t *testing.T) {
 	cfg := &runtimeConfig{}
-	store := buildStateStore(cfg)
+	store := buildStateStore(cfg, nil)
 	if store == nil {
 		t.Fatal("expected non-nil store")
 	}
@@ -167,7 +167,7 @@ func TestBuildStateStore_WithoutMemoryID(t *testing.T) {
 
 func TestBuildStateStore_MemoryIDWithoutRegion(t *testing.T) {
 	cfg := &runtimeConfig{MemoryID: "mem-123"}
-	store := buildStateStore(cfg)
+	store := buildStateStore(cfg, nil)
 	if _, ok := store.(*statestore.MemoryStore); !ok {
 		t.Errorf("expected fallback to MemoryStore without region, got %T", store)
 	}
@@ -178,7 +178,7 @@ func TestBuildStateStore_WithMemoryIDAndRegion(t *testing.T) {
 		MemoryID:  "mem-123",
 		AWSRegion: "us-west-2",
 	}
-	store := buildStateStore(cfg)
+	store := buildStateStore(cfg, nil)
 	if store == nil {
 		t.Fatal("expected non-nil store")
 	}
@@ -196,7 +196,7 @@ func TestBuildSDKOptions_WithRegionAndEndpoints(t *testing.T) {
 		},
 	}
 
-	opts := buildSDKOptions(cfg)
+	opts := buildSDKOptions(cfg, nil)
 	// 3 options: WithBedrock, WithStateStore, WithAgentEndpoints
 	if len(opts) != 3 {
 		t.Errorf("expected 3 options, got %d", len(opts))
@@ -206,9 +206,36 @@ func TestBuildSDKOptions_WithRegionAndEndpoints(t *testing.T) {
 func TestBuildSDKOptions_NoRegion(t *testing.T) {
 	cfg := &runtimeConfig{}
 
-	opts := buildSDKOptions(cfg)
+	opts := buildSDKOptions(cfg, nil)
 	// 1 option: WithStateStore only
 	if len(opts) != 1 {
 		t.Errorf("expected 1 option, got %d", len(opts))
 	}
 }
+
+func TestBedrockModelID(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  runtimeConfig
+		want string
+	}{
+		{"model only", runtimeConfig{Model: "claude-3-5-haiku-20241022"}, "claude-3-5-haiku-20241022"},
+		{
+			"system profile",
+			runtimeConfig{Model: "claude-sonnet-4", InferenceProfile: "us.anthropic.claude-sonnet-4-20250514-v1:0"},
+			"us.anthropic.claude-sonnet-4-20250514-v1:0",
+		},
+		{
+			"application profile ARN",
+			runtimeConfig{InferenceProfile: "arn:aws:bedrock:us-west-2:123456789012:application-inference-profile/abc"},
+			"arn:aws:bedrock:us-west-2:123456789012:application-inference-profile%2Fabc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bedrockModelID(&tt.cfg); got != tt.want {
+				t.Errorf("bedrockModelID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
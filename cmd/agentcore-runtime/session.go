@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// sessionsPath serves session introspection as GET /sessions/{id}.
+const sessionsPath = "/sessions/"
+
+// sessionStoreTimeout bounds each session store read or write.
+const sessionStoreTimeout = 5 * time.Second
+
+// maxTrackedSessions caps the in-process session cache. Past the cap an
+// arbitrary entry is dropped; persisted sessions are reloaded on next use.
+const maxTrackedSessions = 10000
+
+// sessionFilePerm is the mode of the local session file.
+const sessionFilePerm = 0o600
+
+// errSessionTurnLimit rejects an invocation on a session that has used its
+// turns.
+var errSessionTurnLimit = errors.New("session turn limit reached")
+
+// sessionStore persists session metadata. *agentcore.SessionMetaStore and
+// fileSessionStore are the implementations.
+type sessionStore interface {
+	Load(ctx context.Context, sessionID string) (*agentcore.SessionMeta, error)
+	Save(ctx context.Context, meta *agentcore.SessionMeta) error
+}
+
+// sessionTracker keeps per-session metadata (turn count, last task) so the
+// bridge can enforce a turn limit and a daily token budget and answer
+// session introspection. With a store, the metadata survives restarts when
+// AgentCore reuses the session ID.
+type sessionTracker struct {
+	store       sessionStore // nil keeps metadata in process only
+	maxTurns    int          // 0 = unlimited
+	tokenBudget int          // tokens per session per UTC day, 0 = unlimited
+	log         *slog.Logger
+
+	mu       sync.Mutex
+	sessions map[string]*agentcore.SessionMeta
+	reserved map[string]int                    // turns admitted and not yet completed
+	pending  map[string]*agentcore.SessionMeta // sessions being saved, with the snapshot queued next
+	wg       sync.WaitGroup
+	now      func() time.Time // nil uses time.Now
+}
+
+// buildSessionTracker returns the session tracker for cfg, or nil when no
+// session store, turn limit, or token budget is configured.
+func buildSessionTracker(cfg *runtimeConfig, log *slog.Logger) *sessionTracker {
+	store := buildSessionStore(cfg, log)
+	if store == nil && cfg.SessionMaxTurns == 0 && cfg.SessionDailyTokenBudget == 0 {
+		return nil
+	}
+	if store == nil {
+		log.Warn("session limits without a session store; counts reset on restart")
+	}
+	return &sessionTracker{
+		store:       store,
+		maxTurns:    cfg.SessionMaxTurns,
+		tokenBudget: cfg.SessionDailyTokenBudget,
+		log:         log,
+		sessions:    make(map[string]*agentcore.SessionMeta),
+	}
+}
+
+// buildSessionStore returns the configured session store, or nil.
+func buildSessionStore(cfg *runtimeConfig, log *slog.Logger) sessionStore {
+	switch {
+	case cfg.SessionFile != "":
+		return &fileSessionStore{path: cfg.SessionFile}
+	case cfg.SessionStore != agentcore.SessionStoreMemory:
+		return nil
+	case cfg.MemoryID == "" || cfg.AWSRegion == "":
+		log.Warn("session store memory without memory, skipping",
+			"memory_id", cfg.MemoryID, "aws_region", cfg.AWSRegion)
+		return nil
+	}
+	client, err := agentcore.NewDataPlaneClient(cfg.AWSRegion)
+	if err != nil {
+		log.Warn("session store init failed, skipping", "error", err)
+		return nil
+	}
+	return agentcore.NewSessionMetaStore(cfg.MemoryID, client)
+}
+
+func (t *sessionTracker) clock() time.Time {
+	if t.now == nil {
+		return time.Now().UTC()
+	}
+	return t.now()
+}
+
+// get returns a copy of the session's metadata, loading it from the store
+// on first use. It returns nil for an unknown session.
+func (t *sessionTracker) get(ctx context.Context, sessionID string) (*agentcore.SessionMeta, error) {
+	t.mu.Lock()
+	meta, ok := t.sessions[sessionID]
+	t.mu.Unlock()
+	if ok {
+		return copySessionMeta(meta), nil
+	}
+	if t.store == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sessionStoreTimeout)
+	defer cancel()
+	loaded, err := t.store.Load(ctx, sessionID)
+	if err != nil || loaded == nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if meta, ok = t.sessions[sessionID]; ok {
+		return copySessionMeta(meta), nil
+	}
+	t.cache(loaded)
+	return copySessionMeta(loaded), nil
+}
+
+// admit checks the turn limit and the daily token budget before an
+// invocation and reserves a turn, so concurrent invocations cannot all pass
+// a limit only one of them fits under. The caller calls release once the
+// invocation's turn is recorded by complete, or the invocation failed. A
+// store that cannot be read lets the invocation through.
+//
+// Token usage is only known as invocations complete, so concurrent
+// invocations can still overshoot the budget by their own usage.
+func (t *sessionTracker) admit(ctx context.Context, sessionID string) (release func(), err error) {
+	if t == nil || sessionID == "" {
+		return func() {}, nil
+	}
+	loaded, err := t.get(ctx, sessionID)
+	if err != nil {
+		t.log.Warn("session load failed, continuing", "session_id", sessionID, "error", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	meta, ok := t.sessions[sessionID]
+	if !ok {
+		meta = loaded
+	}
+	if err = t.checkLimits(sessionID, meta); err != nil {
+		return nil, err
+	}
+	if t.reserved == nil {
+		t.reserved = make(map[string]int)
+	}
+	t.reserved[sessionID]++
+	var once sync.Once
+	return func() { once.Do(func() { t.unreserve(sessionID) }) }, nil
+}
+
+// checkLimits checks the session's turns, those reserved included, against
+// the turn limit, and its usage against the daily token budget. meta is
+// nil for a session without metadata yet. The caller holds t.mu.
+func (t *sessionTracker) checkLimits(sessionID string, meta *agentcore.SessionMeta) error {
+	turns := t.reserved[sessionID]
+	if meta != nil {
+		turns += meta.Turns
+	}
+	if t.maxTurns > 0 && turns >= t.maxTurns {
+		return errSessionTurnLimit
+	}
+	if t.tokenBudget > 0 && meta != nil {
+		return t.checkBudget(meta)
+	}
+	return nil
+}
+
+// unreserve drops a turn admit reserved.
+func (t *sessionTracker) unreserve(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.reserved[sessionID]--; t.reserved[sessionID] <= 0 {
+		delete(t.reserved, sessionID)
+	}
+}
+
+// complete records a served turn and its token usage, and persists the
+// result in the background. A session that is no longer cached is loaded
+// from the store first, so its counts carry on; when the store cannot be
+// read, the turn is not saved rather than overwriting the stored counts.
+func (t *sessionTracker) complete(sessionID, taskID string, usage usageInfo) {
+	if t == nil || sessionID == "" {
+		return
+	}
+	loaded, err := t.get(context.Background(), sessionID)
+	if err != nil {
+		t.log.Warn("session load failed, turn not recorded", "session_id", sessionID, "error", err)
+		return
+	}
+	now := t.clock()
+	t.mu.Lock()
+	meta, ok := t.sessions[sessionID]
+	if !ok {
+		meta = loaded
+		if meta == nil {
+			meta = &agentcore.SessionMeta{SessionID: sessionID, CreatedAt: now}
+		}
+		t.cache(meta)
+	}
+	meta.Turns++
+	meta.InputTokens += usage.InputTokens
+	meta.OutputTokens += usage.OutputTokens
+	t.chargeBudget(meta, usage, now)
+	meta.UpdatedAt = now
+	if taskID != "" {
+		meta.LastTaskID = taskID
+	}
+	if t.store != nil {
+		t.persist(copySessionMeta(meta))
+	}
+	t.mu.Unlock()
+}
+
+// persist saves snapshot in the background. The saves of one session run
+// one at a time, and a snapshot waiting behind a save is replaced by newer
+// ones, so the store ends up with the latest. The caller holds t.mu.
+func (t *sessionTracker) persist(snapshot *agentcore.SessionMeta) {
+	if t.pending == nil {
+		t.pending = make(map[string]*agentcore.SessionMeta)
+	}
+	_, saving := t.pending[snapshot.SessionID]
+	t.pending[snapshot.SessionID] = snapshot
+	if saving {
+		return
+	}
+	t.wg.Add(1)
+	go t.saveQueued(snapshot.SessionID)
+}
+
+// saveQueued saves the session's queued snapshots until none is left.
+func (t *sessionTracker) saveQueued(sessionID string) {
+	defer t.wg.Done()
+	for {
+		t.mu.Lock()
+		snapshot := t.pending[sessionID]
+		if snapshot == nil {
+			delete(t.pending, sessionID)
+			t.mu.Unlock()
+			return
+		}
+		t.pending[sessionID] = nil
+		t.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+		if err := t.store.Save(ctx, snapshot); err != nil {
+			t.log.Warn("session save failed", "session_id", sessionID, "error", err)
+		}
+		cancel()
+	}
+}
+
+// cache stores meta, evicting an entry when the cache is full. Sessions
+// still being saved are kept, as the store does not hold their counts yet.
+// The caller holds t.mu.
+func (t *sessionTracker) cache(meta *agentcore.SessionMeta) {
+	if len(t.sessions) >= maxTrackedSessions {
+		for id := range t.sessions {
+			if _, saving := t.pending[id]; !saving {
+				delete(t.sessions, id)
+				break
+			}
+		}
+	}
+	t.sessions[meta.SessionID] = meta
+}
+
+// wait blocks until pending session writes finish.
+func (t *sessionTracker) wait() {
+	if t != nil {
+		t.wg.Wait()
+	}
+}
+
+func copySessionMeta(meta *agentcore.SessionMeta) *agentcore.SessionMeta {
+	c := *meta
+	return &c
+}
+
+// sessionView is the session introspection response.
+type sessionView struct {
+	*agentcore.SessionMeta
+	MaxTurns         int `json:"max_turns,omitempty"`
+	DailyTokenBudget int `json:"daily_token_budget,omitempty"`
+}
+
+// handleSession serves GET /sessions/{id}.
+func (b *httpBridge) handleSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	meta, err := b.sessions.get(r.Context(), sessionID)
+	if err != nil {
+		b.log.Error("session load failed", "session_id", sessionID, "error", err)
+		http.Error(w, "session store unavailable", http.StatusBadGateway)
+		return
+	}
+	if meta == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(sessionView{
+		SessionMeta: meta, MaxTurns: b.sessions.maxTurns, DailyTokenBudget: b.sessions.tokenBudget,
+	})
+}
+
+// fileSessionStore keeps session metadata in a local JSON file keyed by
+// session ID. It is meant for local runs, where there is no AgentCore
+// memory.
+type fileSessionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Load implements sessionStore.
+func (s *fileSessionStore) Load(_ context.Context, sessionID string) (*agentcore.SessionMeta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return all[sessionID], nil
+}
+
+// Save implements sessionStore. The file is replaced atomically.
+func (s *fileSessionStore) Save(_ context.Context, meta *agentcore.SessionMeta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+	all[meta.SessionID] = meta
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode session file: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write session file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Chmod(sessionFilePerm)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write session file: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// read returns the file's sessions; a missing file holds none.
+func (s *fileSessionStore) read() (map[string]*agentcore.SessionMeta, error) {
+	all := make(map[string]*agentcore.SessionMeta)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session file: %w", err)
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("decode session file %s: %w", s.path, err)
+	}
+	return all, nil
+}
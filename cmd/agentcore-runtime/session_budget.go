@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// errCodeBudgetExceeded is the error code of an invocation rejected by the
+// session's daily token budget.
+const errCodeBudgetExceeded = "budget_exceeded"
+
+// msgBudgetExceeded is the response text of a budget rejection.
+const msgBudgetExceeded = "session daily token budget exceeded"
+
+// invocationError is the structured error of a rejected invocation, so
+// clients can branch on its code instead of the message.
+type invocationError struct {
+	Code     string `json:"code"`
+	Limit    int    `json:"limit,omitempty"`
+	Used     int    `json:"used,omitempty"`
+	ResetsAt string `json:"resets_at,omitempty"`
+}
+
+// budgetExceededError rejects an invocation on a session that has used its
+// daily token budget.
+type budgetExceededError struct {
+	limit, used int
+	resetsAt    time.Time
+}
+
+func (e *budgetExceededError) Error() string { return msgBudgetExceeded }
+
+// budgetDay returns the UTC date daily token budgets count now under.
+func budgetDay(now time.Time) string {
+	return now.UTC().Format(time.DateOnly)
+}
+
+// nextBudgetReset returns the start of the UTC day after now.
+func nextBudgetReset(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+// dayTokens returns the tokens meta's session has used on the UTC day of
+// now.
+func dayTokens(meta *agentcore.SessionMeta, now time.Time) int {
+	if meta.BudgetDay != budgetDay(now) {
+		return 0
+	}
+	return meta.DayTokens
+}
+
+// checkBudget returns a budgetExceededError when meta's session has used
+// the tracker's daily token budget. The caller checks that there is one.
+func (t *sessionTracker) checkBudget(meta *agentcore.SessionMeta) error {
+	now := t.clock()
+	if used := dayTokens(meta, now); used >= t.tokenBudget {
+		return &budgetExceededError{limit: t.tokenBudget, used: used, resetsAt: nextBudgetReset(now)}
+	}
+	return nil
+}
+
+// chargeBudget adds usage to the day's token count of meta, starting a new
+// count on a new UTC day. The caller holds t.mu.
+func (t *sessionTracker) chargeBudget(meta *agentcore.SessionMeta, usage usageInfo, now time.Time) {
+	if t.tokenBudget == 0 {
+		return
+	}
+	if day := budgetDay(now); meta.BudgetDay != day {
+		meta.BudgetDay = day
+		meta.DayTokens = 0
+	}
+	meta.DayTokens += usage.InputTokens + usage.OutputTokens
+}
+
+// writeAdmissionError rejects an invocation the session tracker did not
+// admit with 429. A budget rejection carries a budget_exceeded error and
+// Retry-After until the budget resets.
+func writeAdmissionError(w http.ResponseWriter, err error, now time.Time) {
+	var budgetErr *budgetExceededError
+	if !errors.As(err, &budgetErr) {
+		writeInvocationStatus(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	secs := int(math.Ceil(budgetErr.resetsAt.Sub(now).Seconds()))
+	w.Header().Set(retryAfterHeader, strconv.Itoa(max(1, secs)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(invocationResponse{
+		Response: budgetErr.Error(),
+		Status:   keyError,
+		Error: &invocationError{
+			Code:     errCodeBudgetExceeded,
+			Limit:    budgetErr.limit,
+			Used:     budgetErr.used,
+			ResetsAt: budgetErr.resetsAt.Format(time.RFC3339),
+		},
+	})
+}
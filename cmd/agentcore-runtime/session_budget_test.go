@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionTracker_DailyTokenBudget(t *testing.T) {
+	tr := buildSessionTracker(&runtimeConfig{
+		SessionFile: filepath.Join(t.TempDir(), "sessions.json"), SessionDailyTokenBudget: 100,
+	}, quietLogger())
+	now := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	tr.complete("s-1", "", usageInfo{InputTokens: 60, OutputTokens: 30})
+	if err := admitTurn(tr, "s-1"); err != nil {
+		t.Fatalf("admit under budget: %v", err)
+	}
+	tr.complete("s-1", "", usageInfo{InputTokens: 15})
+
+	err := admitTurn(tr, "s-1")
+	var budgetErr *budgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("admit over budget = %v, want a budget error", err)
+	}
+	if budgetErr.limit != 100 || budgetErr.used != 105 ||
+		!budgetErr.resetsAt.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("budget error = %+v", budgetErr)
+	}
+	if err = admitTurn(tr, "s-2"); err != nil {
+		t.Errorf("admit on another session = %v", err)
+	}
+
+	now = now.Add(3 * time.Hour)
+	if err = admitTurn(tr, "s-1"); err != nil {
+		t.Fatalf("admit on the next day = %v", err)
+	}
+	tr.complete("s-1", "", usageInfo{OutputTokens: 5})
+	meta, _ := tr.get(ctx, "s-1")
+	if meta.BudgetDay != "2026-03-02" || meta.DayTokens != 5 || meta.InputTokens != 75 {
+		t.Errorf("meta = %+v, want a fresh day count and running totals", meta)
+	}
+	tr.wait()
+}
+
+func TestSessionTracker_NoBudgetKeepsNoDayCount(t *testing.T) {
+	tr := buildSessionTracker(&runtimeConfig{SessionMaxTurns: 5}, quietLogger())
+	tr.complete("s-1", "", usageInfo{InputTokens: 10})
+	meta, _ := tr.get(context.Background(), "s-1")
+	if meta.BudgetDay != "" || meta.DayTokens != 0 {
+		t.Errorf("meta = %+v, want no day count without a budget", meta)
+	}
+}
+
+func TestSessionBridge_BudgetExceeded(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"result":{"id":"task-1","status":{"state":"completed"},`+
+			`"artifacts":[{"parts":[{"text":"hi"}]}],"metadata":{"usage":{"input_tokens":40,"output_tokens":20}}}}`)
+	}))
+	defer upstream.Close()
+	b := &httpBridge{
+		a2aPort:  extractTestPort(t, upstream.URL),
+		log:      quietLogger(),
+		sessions: buildSessionTracker(&runtimeConfig{SessionDailyTokenBudget: 50}, quietLogger()),
+	}
+	b.sessions.now = func() time.Time { return time.Date(2026, 3, 1, 23, 59, 30, 0, time.UTC) }
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+invocationsPath, b.handleInvocation)
+	h := b.withAccessLog(mux)
+
+	invoke := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"hello"}`))
+		r.Header.Set(sessionHeader, "s-1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	if w := invoke(); w.Code != http.StatusOK {
+		t.Fatalf("first invocation = %d", w.Code)
+	}
+	w := invoke()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("invocation over budget = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get(retryAfterHeader); got != "30" {
+		t.Errorf("Retry-After = %q, want the 30s until midnight UTC", got)
+	}
+	var body invocationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	want := invocationError{Code: errCodeBudgetExceeded, Limit: 50, Used: 60, ResetsAt: "2026-03-02T00:00:00Z"}
+	if body.Status != keyError || body.Error == nil || *body.Error != want {
+		t.Errorf("body = %s, want a budget_exceeded error", w.Body.String())
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newFileTracker(t *testing.T, path string, maxTurns int) *sessionTracker {
+	t.Helper()
+	return buildSessionTracker(&runtimeConfig{SessionFile: path, SessionMaxTurns: maxTurns}, quietLogger())
+}
+
+func TestBuildSessionTracker(t *testing.T) {
+	if tr := buildSessionTracker(&runtimeConfig{}, quietLogger()); tr != nil {
+		t.Error("tracker built without a store or turn limit")
+	}
+	if tr := buildSessionTracker(&runtimeConfig{SessionStore: "memory"}, quietLogger()); tr != nil {
+		t.Error("memory store built without a memory ID")
+	}
+	if tr := buildSessionTracker(&runtimeConfig{SessionMaxTurns: 3}, quietLogger()); tr == nil || tr.store != nil {
+		t.Errorf("tracker = %+v, want an in-process tracker", tr)
+	}
+}
+
+func TestSessionTracker_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	first := newFileTracker(t, path, 0)
+	first.complete("s-1", "task-1", usageInfo{InputTokens: 10, OutputTokens: 4})
+	first.complete("s-1", "task-2", usageInfo{InputTokens: 20, OutputTokens: 6})
+	first.complete("s-2", "", usageInfo{})
+	first.wait()
+
+	restarted := newFileTracker(t, path, 0)
+	meta, err := restarted.get(context.Background(), "s-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if meta == nil || meta.Turns != 2 || meta.LastTaskID != "task-2" || meta.CreatedAt.IsZero() {
+		t.Fatalf("meta = %+v, want 2 turns ending in task-2", meta)
+	}
+	if meta.InputTokens != 30 || meta.OutputTokens != 10 {
+		t.Errorf("tokens = %d/%d, want the turns' sums 30/10", meta.InputTokens, meta.OutputTokens)
+	}
+
+	restarted.complete("s-1", "task-3", usageInfo{})
+	restarted.wait()
+	meta, _ = newFileTracker(t, path, 0).get(context.Background(), "s-1")
+	if meta.Turns != 3 {
+		t.Errorf("turns after restart = %d, want 3", meta.Turns)
+	}
+	if unknown, _ := restarted.get(context.Background(), "nope"); unknown != nil {
+		t.Errorf("unknown session = %+v, want nil", unknown)
+	}
+}
+
+func TestSessionTracker_TurnLimit(t *testing.T) {
+	tr := newFileTracker(t, filepath.Join(t.TempDir(), "sessions.json"), 2)
+	for range 2 {
+		if err := admitTurn(tr, "s-1"); err != nil {
+			t.Fatalf("admit: %v", err)
+		}
+		tr.complete("s-1", "", usageInfo{})
+	}
+	if err := admitTurn(tr, "s-1"); !errors.Is(err, errSessionTurnLimit) {
+		t.Errorf("admit after limit = %v, want %v", err, errSessionTurnLimit)
+	}
+	if err := admitTurn(tr, "s-2"); err != nil {
+		t.Errorf("admit on a fresh session = %v", err)
+	}
+	if err := admitTurn(tr, ""); err != nil {
+		t.Errorf("admit without a session = %v", err)
+	}
+	tr.wait()
+}
+
+// failingSessionStore is a sessionStore that cannot be reached.
+type failingSessionStore struct{}
+
+func (failingSessionStore) Load(context.Context, string) (*agentcore.SessionMeta, error) {
+	return nil, errors.New("throttled")
+}
+
+func (failingSessionStore) Save(context.Context, *agentcore.SessionMeta) error {
+	return errors.New("throttled")
+}
+
+func TestSessionTracker_StoreFailureFailsOpen(t *testing.T) {
+	tr := &sessionTracker{
+		store: failingSessionStore{}, maxTurns: 1, log: quietLogger(),
+		sessions: map[string]*agentcore.SessionMeta{},
+	}
+	if err := admitTurn(tr, "s-1"); err != nil {
+		t.Errorf("admit with an unreachable store = %v, want nil", err)
+	}
+	tr.complete("s-1", "t", usageInfo{})
+	tr.wait()
+}
+
+// admitTurn admits an invocation on tr and drops its turn reservation, as
+// a request whose turn is recorded right after would.
+func admitTurn(tr *sessionTracker, sessionID string) error {
+	release, err := tr.admit(context.Background(), sessionID)
+	if err == nil {
+		release()
+	}
+	return err
+}
+
+func TestSessionTracker_ReservesAdmittedTurns(t *testing.T) {
+	tr := newFileTracker(t, filepath.Join(t.TempDir(), "sessions.json"), 2)
+	ctx := context.Background()
+	first, err := tr.admit(ctx, "s-1")
+	if err != nil {
+		t.Fatalf("first admit: %v", err)
+	}
+	second, err := tr.admit(ctx, "s-1")
+	if err != nil {
+		t.Fatalf("second admit: %v", err)
+	}
+	if _, err = tr.admit(ctx, "s-1"); !errors.Is(err, errSessionTurnLimit) {
+		t.Errorf("admit with both turns in flight = %v, want %v", err, errSessionTurnLimit)
+	}
+	tr.complete("s-1", "", usageInfo{})
+	first()
+	first()
+	second()
+	if err = admitTurn(tr, "s-1"); err != nil {
+		t.Errorf("admit after a turn failed = %v, want the reservation dropped", err)
+	}
+	tr.wait()
+}
+
+func TestSessionTracker_CompleteReloadsEvictedSession(t *testing.T) {
+	tr := newFileTracker(t, filepath.Join(t.TempDir(), "sessions.json"), 0)
+	tr.complete("s-1", "", usageInfo{InputTokens: 10})
+	tr.complete("s-1", "", usageInfo{InputTokens: 10})
+	tr.wait()
+
+	tr.mu.Lock()
+	delete(tr.sessions, "s-1")
+	tr.mu.Unlock()
+	tr.complete("s-1", "", usageInfo{InputTokens: 10})
+	tr.wait()
+
+	meta, _ := newFileTracker(t, tr.store.(*fileSessionStore).path, 0).get(context.Background(), "s-1")
+	if meta == nil || meta.Turns != 3 || meta.InputTokens != 30 {
+		t.Errorf("meta = %+v, want the stored counts carried on", meta)
+	}
+}
+
+// unreadableSessionStore is a sessionStore whose reads fail and whose
+// writes are counted.
+type unreadableSessionStore struct{ saves atomic.Int32 }
+
+func (*unreadableSessionStore) Load(context.Context, string) (*agentcore.SessionMeta, error) {
+	return nil, errors.New("throttled")
+}
+
+func (s *unreadableSessionStore) Save(context.Context, *agentcore.SessionMeta) error {
+	s.saves.Add(1)
+	return nil
+}
+
+func TestSessionTracker_CompleteSkipsSaveWhenUnreadable(t *testing.T) {
+	store := &unreadableSessionStore{}
+	tr := &sessionTracker{store: store, log: quietLogger(), sessions: map[string]*agentcore.SessionMeta{}}
+	tr.complete("s-1", "", usageInfo{})
+	tr.wait()
+	if n := store.saves.Load(); n != 0 {
+		t.Errorf("saves = %d, want none over the stored session", n)
+	}
+}
+
+func TestSessionBridge_LimitAndIntrospection(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"result":{"id":"task-9","status":{"state":"completed"},"artifacts":[{"parts":[{"text":"hi"}]}]}}`)
+	}))
+	defer upstream.Close()
+	b := &httpBridge{
+		a2aPort:  extractTestPort(t, upstream.URL),
+		log:      quietLogger(),
+		sessions: newFileTracker(t, filepath.Join(t.TempDir(), "sessions.json"), 1),
+	}
+	b.sessions.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+invocationsPath, b.handleInvocation)
+	mux.HandleFunc("GET "+sessionsPath+"{id}", b.handleSession)
+	h := b.withAccessLog(mux)
+
+	invoke := func() int {
+		r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"hello"}`))
+		r.Header.Set(sessionHeader, "s-1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+	if code := invoke(); code != http.StatusOK {
+		t.Fatalf("first invocation = %d", code)
+	}
+	if code := invoke(); code != http.StatusTooManyRequests {
+		t.Errorf("invocation past the limit = %d, want 429", code)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, sessionsPath+"s-1", nil))
+	var view struct {
+		SessionID  string `json:"session_id"`
+		Turns      int    `json:"turns"`
+		LastTaskID string `json:"last_task_id"`
+		MaxTurns   int    `json:"max_turns"`
+		CreatedAt  string `json:"created_at"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &view); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	if view.SessionID != "s-1" || view.Turns != 1 || view.LastTaskID != "task-9" || view.MaxTurns != 1 ||
+		view.CreatedAt != "2026-01-01T00:00:00Z" {
+		t.Errorf("session view = %+v", view)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, sessionsPath+"unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown session = %d, want 404", w.Code)
+	}
+	b.sessions.wait()
+}
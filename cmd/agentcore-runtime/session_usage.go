@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// sessionUsageSuffix follows the context ID in GET /sessions/{id}/usage.
+const sessionUsageSuffix = "/usage"
+
+// methodTasksList lists the A2A server's tasks of a context.
+const methodTasksList = "tasks/list"
+
+// rpcIDUsage is the JSON-RPC request ID of the usage endpoint's task query.
+const rpcIDUsage = "http-bridge-usage-1"
+
+// usageTaskPageSize is the most tasks of one context the usage endpoint
+// sums when it falls back to the A2A task store.
+const usageTaskPageSize = 1000
+
+// Sources of a session usage report.
+const (
+	usageSourceSession = "session" // the bridge's session tracker
+	usageSourceTasks   = "tasks"   // the A2A server's task store
+)
+
+// sessionUsage is the GET /sessions/{id}/usage response: the token usage
+// and invocation count summed across a conversation's turns.
+type sessionUsage struct {
+	ContextID    string `json:"context_id"`
+	Invocations  int    `json:"invocations"`
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+	TotalTokens  int    `json:"total_tokens"`
+	Source       string `json:"source"`
+}
+
+// add counts one invocation reporting u, which may be nil.
+func (s *sessionUsage) add(u *usageInfo) {
+	s.Invocations++
+	if u != nil {
+		s.InputTokens += u.InputTokens
+		s.OutputTokens += u.OutputTokens
+	}
+	s.TotalTokens = s.InputTokens + s.OutputTokens
+}
+
+// a2aTasksListParams is the params object of tasks/list.
+type a2aTasksListParams struct {
+	ContextID string `json:"contextId"`
+	PageSize  int    `json:"pageSize,omitempty"`
+}
+
+// a2aTasksListResponse is the JSON-RPC response to tasks/list.
+type a2aTasksListResponse struct {
+	Result struct {
+		Tasks []a2aTask `json:"tasks"`
+	} `json:"result"`
+	Error *a2aRPCError `json:"error"`
+}
+
+// handleSessionUsage serves GET /sessions/{id}/usage.
+func (b *httpBridge) handleSessionUsage(w http.ResponseWriter, r *http.Request) {
+	contextID := r.PathValue("id")
+	usage, err := b.sessionUsage(r.Context(), contextID)
+	if err != nil {
+		b.log.Error("session usage failed", "context_id", contextID, "error", err)
+		http.Error(w, "session usage unavailable", http.StatusBadGateway)
+		return
+	}
+	if usage == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(usage)
+}
+
+// sessionUsage returns the usage of contextID from the session tracker,
+// which counts every turn the bridge served, or else, for a context the
+// tracker does not know, sums the tasks the A2A server holds for it. It
+// returns nil for an unknown context.
+func (b *httpBridge) sessionUsage(ctx context.Context, contextID string) (*sessionUsage, error) {
+	if b.sessions != nil {
+		meta, err := b.sessions.get(ctx, contextID)
+		if err != nil {
+			return nil, err
+		}
+		if meta != nil {
+			return &sessionUsage{
+				ContextID:    contextID,
+				Invocations:  meta.Turns,
+				InputTokens:  meta.InputTokens,
+				OutputTokens: meta.OutputTokens,
+				TotalTokens:  meta.InputTokens + meta.OutputTokens,
+				Source:       usageSourceSession,
+			}, nil
+		}
+	}
+	return b.taskStoreUsage(ctx, contextID)
+}
+
+// taskStoreUsage sums the usage of the A2A server's tasks for contextID.
+func (b *httpBridge) taskStoreUsage(ctx context.Context, contextID string) (*sessionUsage, error) {
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      rpcIDUsage,
+		"method":  methodTasksList,
+		"params":  a2aTasksListParams{ContextID: contextID, PageSize: usageTaskPageSize},
+	})
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := b.forwardToA2A(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	var resp a2aTasksListResponse
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("decode %s response: %w", methodTasksList, err)
+	}
+	if resp.Error != nil {
+		return nil, errors.New(resp.Error.text())
+	}
+	if len(resp.Result.Tasks) == 0 {
+		return nil, nil
+	}
+	usage := &sessionUsage{ContextID: contextID, Source: usageSourceTasks}
+	for i := range resp.Result.Tasks {
+		usage.add(taskUsage(&resp.Result.Tasks[i]))
+	}
+	return usage, nil
+}
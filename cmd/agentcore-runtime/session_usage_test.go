@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getSessionUsage(t *testing.T, h http.Handler, contextID string) (int, sessionUsage) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, sessionsPath+contextID+sessionUsageSuffix, nil))
+	var usage sessionUsage
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &usage); err != nil {
+			t.Fatalf("decode %q: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code, usage
+}
+
+func TestSessionUsage_FromSessionTracker(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"result":{"id":"task-1","contextId":"s-1","status":{"state":"completed"},`+
+			`"artifacts":[{"parts":[{"text":"hi"}]}],"metadata":{"usage":{"input_tokens":12,"output_tokens":5}}}}`)
+	}))
+	defer upstream.Close()
+	b := &httpBridge{
+		a2aPort:  extractTestPort(t, upstream.URL),
+		log:      quietLogger(),
+		sessions: newFileTracker(t, filepath.Join(t.TempDir(), "sessions.json"), 0),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+invocationsPath, b.handleInvocation)
+	mux.HandleFunc("GET "+sessionsPath+"{id}"+sessionUsageSuffix, b.handleSessionUsage)
+	h := b.withAccessLog(mux)
+
+	for range 2 {
+		r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"hello"}`))
+		r.Header.Set(sessionHeader, "s-1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("invocation = %d", w.Code)
+		}
+	}
+
+	code, usage := getSessionUsage(t, h, "s-1")
+	want := sessionUsage{
+		ContextID: "s-1", Invocations: 2, InputTokens: 24, OutputTokens: 10, TotalTokens: 34,
+		Source: usageSourceSession,
+	}
+	if code != http.StatusOK || usage != want {
+		t.Errorf("usage = %d %+v, want %+v", code, usage, want)
+	}
+	b.sessions.wait()
+}
+
+func TestSessionUsage_FromTaskStore(t *testing.T) {
+	var params a2aTasksListParams
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string             `json:"method"`
+			Params a2aTasksListParams `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		params = req.Params
+		switch {
+		case req.Method != methodTasksList:
+			_, _ = io.WriteString(w, `{"error":{"code":-32601,"message":"method not found"}}`)
+		case req.Params.ContextID == "ctx-1":
+			_, _ = io.WriteString(w, `{"result":{"tasks":[`+
+				`{"id":"t1","contextId":"ctx-1","metadata":{"usage":{"input_tokens":7,"output_tokens":3}}},`+
+				`{"id":"t2","contextId":"ctx-1","metadata":{"usage":{"input_tokens":8,"output_tokens":2}}},`+
+				`{"id":"t3","contextId":"ctx-1"}]}}`)
+		case req.Params.ContextID == "broken":
+			_, _ = io.WriteString(w, `{"error":{"code":-32000,"message":"List failed"}}`)
+		default:
+			_, _ = io.WriteString(w, `{"result":{"tasks":[]}}`)
+		}
+	}))
+	defer upstream.Close()
+	b := &httpBridge{a2aPort: extractTestPort(t, upstream.URL), log: quietLogger()}
+	h := http.HandlerFunc(b.handleSessionUsage)
+	mux := http.NewServeMux()
+	mux.Handle("GET "+sessionsPath+"{id}"+sessionUsageSuffix, h)
+
+	code, usage := getSessionUsage(t, mux, "ctx-1")
+	want := sessionUsage{
+		ContextID: "ctx-1", Invocations: 3, InputTokens: 15, OutputTokens: 5, TotalTokens: 20,
+		Source: usageSourceTasks,
+	}
+	if code != http.StatusOK || usage != want {
+		t.Errorf("usage = %d %+v, want %+v", code, usage, want)
+	}
+	if params.PageSize != usageTaskPageSize {
+		t.Errorf("tasks/list page size = %d, want %d", params.PageSize, usageTaskPageSize)
+	}
+
+	if code, _ = getSessionUsage(t, mux, "unknown"); code != http.StatusNotFound {
+		t.Errorf("unknown context = %d, want 404", code)
+	}
+	if code, _ = getSessionUsage(t, mux, "broken"); code != http.StatusBadGateway {
+		t.Errorf("failed task list = %d, want 502", code)
+	}
+}
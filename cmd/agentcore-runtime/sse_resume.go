@@ -0,0 +1,192 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSE resume defaults.
+const (
+	// sseResumeMaxEvents bounds the events buffered per task; older events
+	// are dropped and can no longer be replayed.
+	sseResumeMaxEvents = 512
+	// sseResumeRetention is how long a finished task's buffer stays
+	// available for Last-Event-ID resumes.
+	sseResumeRetention = 5 * time.Minute
+)
+
+// lastEventIDHeader is the standard SSE reconnect header.
+const lastEventIDHeader = "Last-Event-ID"
+
+// sseEventIDSep separates the task ID from the sequence number in event IDs.
+const sseEventIDSep = ":"
+
+// bufferedSSEEvent is an SSE event with its position in the task's stream.
+type bufferedSSEEvent struct {
+	seq int
+	id  string // "<taskId>:<seq>", empty until the task ID is known
+	evt *sseEvent
+}
+
+// sseTaskBuffer records the simplified SSE events of one A2A stream so a
+// client that reconnects with Last-Event-ID can replay what it missed and
+// keep following the live stream.
+type sseTaskBuffer struct {
+	mu         sync.Mutex
+	taskID     string
+	events     []bufferedSSEEvent
+	nextSeq    int
+	done       bool
+	finishedAt time.Time
+	changed    chan struct{} // closed and replaced on every append/finish
+
+	clock   *streamClock       // nil when response timings are off
+	timings *invocationTimings // set by finish when clock is set
+}
+
+// newSSETaskBuffer creates an empty, unregistered task buffer.
+func newSSETaskBuffer() *sseTaskBuffer {
+	return &sseTaskBuffer{changed: make(chan struct{})}
+}
+
+// append adds an event and wakes followers. It reports whether this event
+// revealed the task ID, i.e. whether the buffer should now be registered.
+func (tb *sseTaskBuffer) append(evt *sseEvent) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	revealed := false
+	if tb.taskID == "" && evt.TaskID != "" {
+		tb.taskID = evt.TaskID
+		revealed = true
+	}
+
+	if tb.clock != nil && tb.clock.answered.IsZero() {
+		tb.clock.answered = time.Now()
+	}
+	tb.nextSeq++
+	be := bufferedSSEEvent{seq: tb.nextSeq, evt: evt}
+	if tb.taskID != "" {
+		be.id = tb.taskID + sseEventIDSep + strconv.Itoa(tb.nextSeq)
+	}
+	tb.events = append(tb.events, be)
+	if len(tb.events) > sseResumeMaxEvents {
+		tb.events = tb.events[len(tb.events)-sseResumeMaxEvents:]
+	}
+
+	tb.notifyLocked()
+	return revealed
+}
+
+// finish marks the stream as complete and wakes followers.
+func (tb *sseTaskBuffer) finish() {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.done = true
+	tb.finishedAt = time.Now()
+	if tb.clock != nil {
+		tb.timings = newInvocationTimings(tb.clock.received, tb.clock.forwarded, tb.clock.answered, tb.finishedAt)
+	}
+	tb.notifyLocked()
+}
+
+// doneTimings returns the timings of a finished stream, or nil when
+// response timings are off. A resumed stream reports the timings of the
+// invocation that started it.
+func (tb *sseTaskBuffer) doneTimings() *invocationTimings {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.timings
+}
+
+// notifyLocked wakes all followers. Callers must hold tb.mu.
+func (tb *sseTaskBuffer) notifyLocked() {
+	close(tb.changed)
+	tb.changed = make(chan struct{})
+}
+
+// since returns the buffered events after seq, whether the stream is done,
+// and a channel that is closed on the next change.
+func (tb *sseTaskBuffer) since(seq int) ([]bufferedSSEEvent, bool, <-chan struct{}) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	var out []bufferedSSEEvent
+	for _, be := range tb.events {
+		if be.seq > seq {
+			out = append(out, be)
+		}
+	}
+	return out, tb.done, tb.changed
+}
+
+// expired reports whether a finished buffer has outlived the retention window.
+func (tb *sseTaskBuffer) expired(now time.Time) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.done && now.Sub(tb.finishedAt) > sseResumeRetention
+}
+
+// sseResumeStore indexes task buffers by A2A task ID. A nil store disables
+// resume support.
+type sseResumeStore struct {
+	mu    sync.Mutex
+	tasks map[string]*sseTaskBuffer
+}
+
+// newSSEResumeStore creates an empty resume store.
+func newSSEResumeStore() *sseResumeStore {
+	return &sseResumeStore{tasks: make(map[string]*sseTaskBuffer)}
+}
+
+// register indexes tb under its task ID and evicts expired buffers.
+func (s *sseResumeStore) register(tb *sseTaskBuffer) {
+	if s == nil {
+		return
+	}
+	tb.mu.Lock()
+	taskID := tb.taskID
+	tb.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, other := range s.tasks {
+		if other.expired(now) {
+			delete(s.tasks, id)
+		}
+	}
+	s.tasks[taskID] = tb
+}
+
+// lookup returns the buffer for taskID, or nil.
+func (s *sseResumeStore) lookup(taskID string) *sseTaskBuffer {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tasks[taskID]
+}
+
+// resumeTarget resolves the request's Last-Event-ID header to a buffered task
+// and the sequence number of the last event the client received.
+func (s *sseResumeStore) resumeTarget(r *http.Request) (*sseTaskBuffer, int, bool) {
+	raw := r.Header.Get(lastEventIDHeader)
+	i := strings.LastIndex(raw, sseEventIDSep)
+	if i <= 0 {
+		return nil, 0, false
+	}
+	seq, err := strconv.Atoi(raw[i+len(sseEventIDSep):])
+	if err != nil || seq < 0 {
+		return nil, 0, false
+	}
+	tb := s.lookup(raw[:i])
+	if tb == nil {
+		return nil, 0, false
+	}
+	return tb, seq, true
+}
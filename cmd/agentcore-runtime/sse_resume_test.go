@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSETaskBuffer_AppendAssignsIDs(t *testing.T) {
+	tb := newSSETaskBuffer()
+
+	if tb.append(&sseEvent{Type: keyError, Content: "early"}) {
+		t.Error("event without task ID must not reveal the task")
+	}
+	if !tb.append(&sseEvent{Type: keyStatus, State: "working", TaskID: "t1"}) {
+		t.Error("first event with task ID should reveal the task")
+	}
+	if tb.append(&sseEvent{Type: kindText, Content: "hi", TaskID: "t1"}) {
+		t.Error("task ID is only revealed once")
+	}
+
+	events, done, _ := tb.since(0)
+	if done {
+		t.Error("buffer should not be done before finish")
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[0].id != "" {
+		t.Errorf("events[0].id = %q, want empty", events[0].id)
+	}
+	if events[2].id != "t1:3" {
+		t.Errorf("events[2].id = %q, want t1:3", events[2].id)
+	}
+}
+
+func TestSSETaskBuffer_SinceAndFinish(t *testing.T) {
+	tb := newSSETaskBuffer()
+	for range 3 {
+		tb.append(&sseEvent{Type: kindText, TaskID: "t1"})
+	}
+
+	events, _, changed := tb.since(2)
+	if len(events) != 1 || events[0].seq != 3 {
+		t.Fatalf("since(2) = %+v, want only seq 3", events)
+	}
+
+	tb.finish()
+	select {
+	case <-changed:
+	default:
+		t.Error("finish should close the changed channel")
+	}
+	if _, done, _ := tb.since(3); !done {
+		t.Error("expected done after finish")
+	}
+}
+
+func TestSSETaskBuffer_Bounded(t *testing.T) {
+	tb := newSSETaskBuffer()
+	for range sseResumeMaxEvents + 10 {
+		tb.append(&sseEvent{Type: kindText, TaskID: "t1"})
+	}
+	events, _, _ := tb.since(0)
+	if len(events) != sseResumeMaxEvents {
+		t.Fatalf("buffered %d events, want %d", len(events), sseResumeMaxEvents)
+	}
+	if events[0].seq != 11 {
+		t.Errorf("oldest seq = %d, want 11", events[0].seq)
+	}
+}
+
+func TestSSEResumeStore_RegisterEvictsExpired(t *testing.T) {
+	s := newSSEResumeStore()
+
+	old := newSSETaskBuffer()
+	old.append(&sseEvent{TaskID: "old"})
+	old.finish()
+	old.finishedAt = time.Now().Add(-2 * sseResumeRetention)
+	s.register(old)
+
+	fresh := newSSETaskBuffer()
+	fresh.append(&sseEvent{TaskID: "fresh"})
+	s.register(fresh)
+
+	if s.lookup("old") != nil {
+		t.Error("expired buffer should be evicted")
+	}
+	if s.lookup("fresh") != fresh {
+		t.Error("fresh buffer should be registered")
+	}
+}
+
+func TestSSEResumeStore_NilIsDisabled(t *testing.T) {
+	var s *sseResumeStore
+	s.register(newSSETaskBuffer())
+	if s.lookup("t1") != nil {
+		t.Error("nil store lookup should return nil")
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(lastEventIDHeader, "t1:2")
+	if _, _, ok := s.resumeTarget(r); ok {
+		t.Error("nil store should never resume")
+	}
+}
+
+func TestSSEResumeStore_ResumeTarget(t *testing.T) {
+	s := newSSEResumeStore()
+	tb := newSSETaskBuffer()
+	tb.append(&sseEvent{TaskID: "task:with:colons"})
+	s.register(tb)
+
+	tests := []struct {
+		name    string
+		header  string
+		wantOK  bool
+		wantSeq int
+	}{
+		{"valid", "task:with:colons:4", true, 4},
+		{"missing header", "", false, 0},
+		{"no separator", "garbage", false, 0},
+		{"bad seq", "task:with:colons:x", false, 0},
+		{"unknown task", "other:1", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(lastEventIDHeader, tt.header)
+			}
+			got, seq, ok := s.resumeTarget(r)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (got != tb || seq != tt.wantSeq) {
+				t.Errorf("got (%p, %d), want (%p, %d)", got, seq, tb, tt.wantSeq)
+			}
+		})
+	}
+}
+
+func TestFollowSSE_ReplaysAfterLastEventID(t *testing.T) {
+	b := &httpBridge{log: slog.New(slog.NewJSONHandler(io.Discard, nil))}
+	tb := newSSETaskBuffer()
+	tb.append(&sseEvent{Type: keyStatus, State: "working", TaskID: "t1"})
+	tb.append(&sseEvent{Type: kindText, Content: "a", TaskID: "t1"})
+	tb.append(&sseEvent{Type: kindText, Content: "b", TaskID: "t1"})
+	tb.finish()
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	b.followSSE(w, r, tb, 2)
+
+	body := w.Body.String()
+	if strings.Contains(body, `"content":"a"`) {
+		t.Errorf("event before Last-Event-ID replayed: %q", body)
+	}
+	if !strings.Contains(body, "id: t1:3\n") || !strings.Contains(body, `"content":"b"`) {
+		t.Errorf("expected event t1:3 to be replayed, got %q", body)
+	}
+	if !strings.Contains(body, `"type":"done"`) {
+		t.Errorf("expected done event, got %q", body)
+	}
+}
+
+func TestFollowSSE_Heartbeat(t *testing.T) {
+	b := &httpBridge{
+		log:                  slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		sseHeartbeatInterval: 10 * time.Millisecond,
+	}
+	tb := newSSETaskBuffer()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		tb.finish()
+	}()
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	b.followSSE(w, r, tb, 0)
+
+	if !strings.Contains(w.Body.String(), sseHeartbeat) {
+		t.Errorf("expected heartbeat comment, got %q", w.Body.String())
+	}
+}
+
+func TestFollowSSE_ClientDisconnect(t *testing.T) {
+	b := &httpBridge{log: slog.New(slog.NewJSONHandler(io.Discard, nil))}
+	tb := newSSETaskBuffer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	returned := make(chan struct{})
+	go func() {
+		b.followSSE(w, r, tb, 0)
+		close(returned)
+	}()
+	cancel()
+
+	select {
+	case <-returned:
+	case <-time.After(2 * time.Second):
+		t.Fatal("followSSE did not return after client disconnect")
+	}
+}
+
+func TestIntegration_SSE_ResumeWithLastEventID(t *testing.T) {
+	mock := newMockA2AServer(t)
+	b := bridgeForTest(t, mock.port(t))
+	b.resume = newSSEResumeStore()
+	baseURL := startBridgeServer(t, b)
+
+	post := func(lastEventID string) string {
+		req, _ := http.NewRequest(http.MethodPost, baseURL+invocationsPath,
+			strings.NewReader(`{"prompt":"resume me"}`))
+		req.Header.Set(acceptHeader, sseContentType)
+		if lastEventID != "" {
+			req.Header.Set(lastEventIDHeader, lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	first := post("")
+	if !strings.Contains(first, "id: task-s1:4\n") {
+		t.Fatalf("expected event IDs on the first stream, got %q", first)
+	}
+
+	resumed := post("task-s1:2")
+	if strings.Contains(resumed, "id: task-s1:2\n") {
+		t.Errorf("already-seen event replayed: %q", resumed)
+	}
+	if !strings.Contains(resumed, "id: task-s1:3\n") || !strings.Contains(resumed, "id: task-s1:4\n") {
+		t.Errorf("expected events 3 and 4 replayed, got %q", resumed)
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.streamCount != 1 {
+		t.Errorf("streamCount = %d, want 1 (resume must not re-invoke the agent)", mock.streamCount)
+	}
+}
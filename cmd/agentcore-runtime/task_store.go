@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+	a2aserver "github.com/AltairaLabs/PromptKit/server/a2a"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// taskStoreTimeout bounds each task snapshot read or write.
+const taskStoreTimeout = 5 * time.Second
+
+// taskInterruptedText is the status message of a restored task the
+// restart cut off.
+const taskInterruptedText = "task interrupted by runtime restart"
+
+// taskSnapshotStore persists A2A task snapshots.
+// *agentcore.A2ATaskSnapshotStore is the implementation.
+type taskSnapshotStore interface {
+	Load(ctx context.Context, taskID string) (*a2a.Task, error)
+	Save(ctx context.Context, task *a2a.Task) error
+	AddToContext(ctx context.Context, contextID, taskID string) error
+	ContextTaskIDs(ctx context.Context, contextID string) ([]string, error)
+}
+
+// persistentTaskStore is an a2aserver.TaskStore that keeps live tasks in
+// memory and writes a snapshot after every change, so tasks/get and
+// tasks/list still answer for tasks created before a container restart.
+// A task restored in a non-terminal state was cut off by the restart and
+// is reported as failed.
+type persistentTaskStore struct {
+	live  *a2aserver.InMemoryTaskStore
+	store taskSnapshotStore
+	log   *slog.Logger
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[string]*queuedSnapshot // tasks being saved, with the snapshot queued next
+}
+
+// queuedSnapshot is a task snapshot waiting for the task's previous save,
+// with the index writes that go before it.
+type queuedSnapshot struct {
+	task   *a2a.Task
+	extras []func(context.Context) error
+}
+
+// buildTaskStore returns the persistent A2A task store for cfg, or nil to
+// keep the server's default in-memory store.
+func buildTaskStore(cfg *runtimeConfig, log *slog.Logger) *persistentTaskStore {
+	switch {
+	case cfg.A2ATaskStore != agentcore.A2ATaskStoreMemory:
+		return nil
+	case cfg.MemoryID == "" || cfg.AWSRegion == "":
+		log.Warn("a2a task store memory without memory, skipping",
+			"memory_id", cfg.MemoryID, "aws_region", cfg.AWSRegion)
+		return nil
+	}
+	client, err := agentcore.NewDataPlaneClient(cfg.AWSRegion)
+	if err != nil {
+		log.Warn("a2a task store init failed, skipping", "error", err)
+		return nil
+	}
+	return newPersistentTaskStore(agentcore.NewA2ATaskSnapshotStore(cfg.MemoryID, client), log)
+}
+
+func newPersistentTaskStore(store taskSnapshotStore, log *slog.Logger) *persistentTaskStore {
+	return &persistentTaskStore{live: a2aserver.NewInMemoryTaskStore(), store: store, log: log}
+}
+
+// Create creates the task and records it in its context.
+func (s *persistentTaskStore) Create(taskID, contextID string) (*a2a.Task, error) {
+	task, err := s.live.Create(taskID, contextID)
+	if err != nil {
+		return nil, err
+	}
+	s.persist(taskID, func(ctx context.Context) error {
+		if contextID == "" {
+			return nil
+		}
+		return s.store.AddToContext(ctx, contextID, taskID)
+	})
+	return task, nil
+}
+
+// Get returns the live task, falling back to its last snapshot.
+func (s *persistentTaskStore) Get(taskID string) (*a2a.Task, error) {
+	task, err := s.live.Get(taskID)
+	if !errors.Is(err, a2aserver.ErrTaskNotFound) {
+		return task, err
+	}
+	return s.restore(taskID)
+}
+
+// SetState transitions a live task and snapshots the result.
+func (s *persistentTaskStore) SetState(taskID string, state a2a.TaskState, msg *a2a.Message) error {
+	if err := s.live.SetState(taskID, state, msg); err != nil {
+		return err
+	}
+	s.persist(taskID, nil)
+	return nil
+}
+
+// AddArtifacts appends artifacts to a live task and snapshots the result.
+func (s *persistentTaskStore) AddArtifacts(taskID string, artifacts []a2a.Artifact) error {
+	if err := s.live.AddArtifacts(taskID, artifacts); err != nil {
+		return err
+	}
+	s.persist(taskID, nil)
+	return nil
+}
+
+// Cancel cancels a live task. A restored task is terminal and cannot be
+// canceled.
+func (s *persistentTaskStore) Cancel(taskID string) error {
+	err := s.live.Cancel(taskID)
+	if err == nil {
+		s.persist(taskID, nil)
+		return nil
+	}
+	if !errors.Is(err, a2aserver.ErrTaskNotFound) {
+		return err
+	}
+	task, err := s.restore(taskID)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: cannot cancel task in terminal state %q", a2aserver.ErrTaskTerminal, task.Status.State)
+}
+
+// List returns the tasks of contextID, live and restored, sorted by ID.
+// Without a context only live tasks are listed.
+func (s *persistentTaskStore) List(contextID string, limit, offset int) ([]*a2a.Task, error) {
+	tasks, err := s.live.List(contextID, 0, 0)
+	if err != nil || contextID == "" {
+		return paginateTasks(tasks, limit, offset), err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), taskStoreTimeout)
+	defer cancel()
+	ids, err := s.store.ContextTaskIDs(ctx, contextID)
+	if err != nil {
+		s.log.Warn("a2a task context load failed", "context_id", contextID, "error", err)
+		return paginateTasks(tasks, limit, offset), nil
+	}
+	live := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		live[t.ID] = true
+	}
+	for _, id := range ids {
+		if live[id] {
+			continue
+		}
+		if task, restoreErr := s.restore(id); restoreErr == nil {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return paginateTasks(tasks, limit, offset), nil
+}
+
+// EvictTerminal evicts old terminal tasks from memory. Their snapshots
+// remain until the memory's event expiry.
+func (s *persistentTaskStore) EvictTerminal(olderThan time.Time) []string {
+	return s.live.EvictTerminal(olderThan)
+}
+
+// restore loads the last snapshot of taskID, marking a task the restart
+// interrupted as failed.
+func (s *persistentTaskStore) restore(taskID string) (*a2a.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskStoreTimeout)
+	defer cancel()
+	task, err := s.store.Load(ctx, taskID)
+	if err != nil {
+		s.log.Warn("a2a task load failed", "task_id", taskID, "error", err)
+		return nil, a2aserver.ErrTaskNotFound
+	}
+	if task == nil {
+		return nil, a2aserver.ErrTaskNotFound
+	}
+	if !isTerminalTaskState(task.Status.State) {
+		text := taskInterruptedText
+		task.Status = a2a.TaskStatus{
+			State:     a2a.TaskStateFailed,
+			Message:   &a2a.Message{Role: a2a.RoleAgent, Parts: []a2a.Part{{Text: &text}}},
+			Timestamp: task.Status.Timestamp,
+		}
+	}
+	return task, nil
+}
+
+// persist snapshots the live task in the background, after running extra
+// if non-nil. The saves of one task run one at a time, in the order of its
+// changes, and a snapshot waiting behind a save is replaced by newer ones,
+// so the last snapshot written is the latest. Failures are logged; the
+// live task is unaffected.
+func (s *persistentTaskStore) persist(taskID string, extra func(context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.live.Get(taskID)
+	if err != nil {
+		return
+	}
+	if s.pending == nil {
+		s.pending = make(map[string]*queuedSnapshot)
+	}
+	q, saving := s.pending[taskID]
+	if q == nil {
+		q = &queuedSnapshot{}
+		s.pending[taskID] = q
+	}
+	q.task = task
+	if extra != nil {
+		q.extras = append(q.extras, extra)
+	}
+	if saving {
+		return
+	}
+	s.wg.Add(1)
+	go s.saveQueued(taskID)
+}
+
+// saveQueued writes the task's queued snapshots until none is left.
+func (s *persistentTaskStore) saveQueued(taskID string) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		q := s.pending[taskID]
+		if q == nil {
+			delete(s.pending, taskID)
+			s.mu.Unlock()
+			return
+		}
+		s.pending[taskID] = nil
+		s.mu.Unlock()
+		s.save(taskID, q)
+	}
+}
+
+// save runs the index writes of q, then writes its snapshot.
+func (s *persistentTaskStore) save(taskID string, q *queuedSnapshot) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskStoreTimeout)
+	defer cancel()
+	for _, extra := range q.extras {
+		if err := extra(ctx); err != nil {
+			s.log.Warn("a2a task index failed", "task_id", taskID, "error", err)
+		}
+	}
+	if err := s.store.Save(ctx, q.task); err != nil {
+		s.log.Warn("a2a task save failed", "task_id", taskID, "error", err)
+	}
+}
+
+// wait blocks until pending snapshot writes finish.
+func (s *persistentTaskStore) wait() {
+	if s != nil {
+		s.wg.Wait()
+	}
+}
+
+// isTerminalTaskState reports whether no further transitions leave state.
+func isTerminalTaskState(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+// paginateTasks applies offset and limit (0 = all) to tasks.
+func paginateTasks(tasks []*a2a.Task, limit, offset int) []*a2a.Task {
+	if offset >= len(tasks) {
+		return nil
+	}
+	tasks = tasks[offset:]
+	if limit > 0 && limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+	return tasks
+}
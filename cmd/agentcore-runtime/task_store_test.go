@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+	a2aserver "github.com/AltairaLabs/PromptKit/server/a2a"
+)
+
+// memTaskSnapshots is an in-process taskSnapshotStore that outlives the
+// persistentTaskStore instances using it, standing in for AgentCore memory.
+type memTaskSnapshots struct {
+	mu       sync.Mutex
+	tasks    map[string]*a2a.Task
+	contexts map[string][]string
+	err      error
+}
+
+func newMemTaskSnapshots() *memTaskSnapshots {
+	return &memTaskSnapshots{tasks: map[string]*a2a.Task{}, contexts: map[string][]string{}}
+}
+
+func (m *memTaskSnapshots) Load(_ context.Context, taskID string) (*a2a.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	task, ok := m.tasks[taskID]
+	if !ok {
+		return nil, nil
+	}
+	c := *task
+	return &c, nil
+}
+
+// Save keeps the snapshot saved last, as loading from memory does.
+func (m *memTaskSnapshots) Save(_ context.Context, task *a2a.Task) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tasks[task.ID] = task
+	return m.err
+}
+
+func (m *memTaskSnapshots) AddToContext(_ context.Context, contextID, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.contexts[contextID] = append(m.contexts[contextID], taskID)
+	return m.err
+}
+
+func (m *memTaskSnapshots) ContextTaskIDs(_ context.Context, contextID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.contexts[contextID], m.err
+}
+
+func TestBuildTaskStore(t *testing.T) {
+	if s := buildTaskStore(&runtimeConfig{}, quietLogger()); s != nil {
+		t.Error("task store built without PROMPTPACK_A2A_TASK_STORE")
+	}
+	if s := buildTaskStore(&runtimeConfig{A2ATaskStore: "memory"}, quietLogger()); s != nil {
+		t.Error("memory task store built without a memory ID")
+	}
+	var s *persistentTaskStore
+	s.wait() // nil-safe
+}
+
+func TestPersistentTaskStore_SurvivesRestart(t *testing.T) {
+	snapshots := newMemTaskSnapshots()
+	before := newPersistentTaskStore(snapshots, quietLogger())
+	for _, id := range []string{"t-done", "t-running"} {
+		if _, err := before.Create(id, "ctx-1"); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := before.SetState(id, a2a.TaskStateWorking, nil); err != nil {
+			t.Fatalf("SetState: %v", err)
+		}
+	}
+	if err := before.AddArtifacts("t-done", []a2a.Artifact{{ArtifactID: "a1"}}); err != nil {
+		t.Fatalf("AddArtifacts: %v", err)
+	}
+	if err := before.SetState("t-done", a2a.TaskStateCompleted, nil); err != nil {
+		t.Fatalf("SetState: %v", err)
+	}
+	before.wait()
+
+	after := newPersistentTaskStore(snapshots, quietLogger())
+	done, err := after.Get("t-done")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if done.Status.State != a2a.TaskStateCompleted || len(done.Artifacts) != 1 {
+		t.Errorf("restored task = %+v, want completed with one artifact", done)
+	}
+	running, err := after.Get("t-running")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if running.Status.State != a2a.TaskStateFailed {
+		t.Errorf("interrupted task state = %q, want failed", running.Status.State)
+	}
+	if err := after.Cancel("t-running"); !errors.Is(err, a2aserver.ErrTaskTerminal) {
+		t.Errorf("Cancel restored task = %v, want ErrTaskTerminal", err)
+	}
+	if _, err := after.Get("t-unknown"); !errors.Is(err, a2aserver.ErrTaskNotFound) {
+		t.Errorf("Get unknown = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestPersistentTaskStore_ListMergesRestored(t *testing.T) {
+	snapshots := newMemTaskSnapshots()
+	before := newPersistentTaskStore(snapshots, quietLogger())
+	if _, err := before.Create("t-1", "ctx-1"); err != nil {
+		t.Fatal(err)
+	}
+	before.wait()
+
+	after := newPersistentTaskStore(snapshots, quietLogger())
+	if _, err := after.Create("t-2", "ctx-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := after.Create("t-3", "ctx-2"); err != nil {
+		t.Fatal(err)
+	}
+	after.wait()
+
+	tasks, err := after.List("ctx-1", 0, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "t-1" || tasks[1].ID != "t-2" {
+		t.Errorf("List(ctx-1) = %v, want t-1, t-2", taskIDs(tasks))
+	}
+	tasks, _ = after.List("ctx-1", 1, 1)
+	if len(tasks) != 1 || tasks[0].ID != "t-2" {
+		t.Errorf("List(ctx-1, 1, 1) = %v, want t-2", taskIDs(tasks))
+	}
+}
+
+func TestPersistentTaskStore_StoreUnavailable(t *testing.T) {
+	snapshots := newMemTaskSnapshots()
+	snapshots.err = errors.New("throttled")
+	s := newPersistentTaskStore(snapshots, quietLogger())
+	if _, err := s.Create("t-1", "ctx-1"); err != nil {
+		t.Fatalf("Create with failing store: %v", err)
+	}
+	s.wait()
+	if _, err := s.Get("t-1"); err != nil {
+		t.Errorf("Get live task: %v", err)
+	}
+	tasks, err := s.List("ctx-1", 0, 0)
+	if err != nil || len(tasks) != 1 {
+		t.Errorf("List = %v, %v; want the live task", taskIDs(tasks), err)
+	}
+	if _, err := s.Get("t-2"); !errors.Is(err, a2aserver.ErrTaskNotFound) {
+		t.Errorf("Get unknown = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func taskIDs(tasks []*a2a.Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func TestPersistentTaskStore_SavesInOrder(t *testing.T) {
+	snapshots := newMemTaskSnapshots()
+	s := newPersistentTaskStore(snapshots, quietLogger())
+	if _, err := s.Create("task-1", "ctx-1"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	const changes = 50
+	for i := range changes {
+		if err := s.AddArtifacts("task-1", []a2a.Artifact{{ArtifactID: fmt.Sprint(i)}}); err != nil {
+			t.Fatalf("AddArtifacts: %v", err)
+		}
+	}
+	s.wait()
+	task, _ := snapshots.Load(context.Background(), "task-1")
+	if task == nil || len(task.Artifacts) != changes {
+		t.Errorf("stored snapshot = %+v, want all %d artifacts", task, changes)
+	}
+	if ids, _ := snapshots.ContextTaskIDs(context.Background(), "ctx-1"); len(ids) != 1 {
+		t.Errorf("context index = %v, want the task once", ids)
+	}
+}
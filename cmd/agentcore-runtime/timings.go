@@ -0,0 +1,61 @@
+package main
+
+import "time"
+
+// invocationTimings breaks an invocation's latency down so callers can
+// tell whether it was spent in the bridge, waiting on the agent, or
+// streaming the answer. All values are milliseconds.
+type invocationTimings struct {
+	// QueueMS is the time from the bridge receiving the request to
+	// forwarding it to the A2A server, covering rate limits, webhooks, and
+	// session admission.
+	QueueMS int64 `json:"queue_ms"`
+	// UpstreamMS is the time the A2A server took to answer: the whole call
+	// for a blocking invocation, or until its first event for a stream.
+	UpstreamMS int64 `json:"upstream_ms"`
+	// StreamMS is how long a stream ran after its first event.
+	StreamMS int64 `json:"stream_ms,omitempty"`
+	// TotalMS is the time from receiving the request to its completion.
+	TotalMS int64 `json:"total_ms"`
+}
+
+// newInvocationTimings returns the timings of an invocation received,
+// forwarded to the A2A server, answered, and finished at the given times.
+// A zero answered time means the answer was the whole response.
+func newInvocationTimings(received, forwarded, answered, finished time.Time) *invocationTimings {
+	if answered.IsZero() {
+		answered = finished
+	}
+	return &invocationTimings{
+		QueueMS:    forwarded.Sub(received).Milliseconds(),
+		UpstreamMS: answered.Sub(forwarded).Milliseconds(),
+		StreamMS:   finished.Sub(answered).Milliseconds(),
+		TotalMS:    finished.Sub(received).Milliseconds(),
+	}
+}
+
+// streamClock records when a streaming invocation was received, forwarded,
+// and first answered.
+type streamClock struct {
+	received  time.Time
+	forwarded time.Time
+	answered  time.Time
+}
+
+// completedTimings returns the timings of an invocation that has just
+// completed, or nil when response timings are off.
+func (b *httpBridge) completedTimings(received, forwarded time.Time) *invocationTimings {
+	if !b.responseTimings {
+		return nil
+	}
+	return newInvocationTimings(received, forwarded, time.Time{}, time.Now())
+}
+
+// newStreamClock starts timing a stream forwarded at forwarded, or returns
+// nil when response timings are off.
+func (b *httpBridge) newStreamClock(received, forwarded time.Time) *streamClock {
+	if !b.responseTimings {
+		return nil
+	}
+	return &streamClock{received: received, forwarded: forwarded}
+}
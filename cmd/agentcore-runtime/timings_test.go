@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewInvocationTimings(t *testing.T) {
+	received := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	forwarded := received.Add(20 * time.Millisecond)
+	answered := forwarded.Add(300 * time.Millisecond)
+	finished := answered.Add(2 * time.Second)
+
+	got := newInvocationTimings(received, forwarded, answered, finished)
+	want := invocationTimings{QueueMS: 20, UpstreamMS: 300, StreamMS: 2000, TotalMS: 2320}
+	if *got != want {
+		t.Errorf("timings = %+v, want %+v", *got, want)
+	}
+
+	got = newInvocationTimings(received, forwarded, time.Time{}, answered)
+	want = invocationTimings{QueueMS: 20, UpstreamMS: 300, TotalMS: 320}
+	if *got != want {
+		t.Errorf("unanswered timings = %+v, want %+v", *got, want)
+	}
+}
+
+// receivedEarlier returns r as if the access log middleware had received it
+// ago before now.
+func receivedEarlier(r *http.Request, ago time.Duration) *http.Request {
+	entry := &accessLogEntry{start: time.Now().Add(-ago)}
+	return r.WithContext(context.WithValue(r.Context(), accessLogKey{}, entry))
+}
+
+func TestHandleInvocation_ResponseTimings(t *testing.T) {
+	a2aMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":{"id":"task-1","status":{"state":"completed"},` +
+			`"artifacts":[{"parts":[{"text":"ok"}]}]}}`))
+	}))
+	defer a2aMock.Close()
+
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			b := &httpBridge{a2aPort: extractTestPort(t, a2aMock.URL), log: slog.Default(), responseTimings: enabled}
+			r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"hi"}`))
+			w := httptest.NewRecorder()
+
+			b.handleInvocation(w, receivedEarlier(r, 50*time.Millisecond))
+
+			var resp invocationResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unmarshal response: %v", err)
+			}
+			if !enabled {
+				if resp.Timings != nil {
+					t.Errorf("timings = %+v, want none when disabled", resp.Timings)
+				}
+				return
+			}
+			if resp.Timings == nil {
+				t.Fatal("expected timings in response")
+			}
+			if resp.Timings.QueueMS < 50 || resp.Timings.TotalMS < resp.Timings.QueueMS+resp.Timings.UpstreamMS {
+				t.Errorf("timings = %+v", resp.Timings)
+			}
+		})
+	}
+}
+
+func TestRelaySSEEvents_DoneTimings(t *testing.T) {
+	b := &httpBridge{log: slog.Default(), responseTimings: true}
+	sseData := strings.Join([]string{
+		`data: {"jsonrpc":"2.0","id":"1","result":{"taskId":"t1","artifact":{"parts":[{"text":"hi"}]}}}`,
+		``,
+		`data: {"jsonrpc":"2.0","id":"1","result":{"taskId":"t1","status":{"state":"completed"}}}`,
+		``,
+	}, "\n")
+	r := receivedEarlier(httptest.NewRequest(http.MethodPost, "/", nil), 50*time.Millisecond)
+	w := httptest.NewRecorder()
+
+	b.relaySSEEvents(w, r, strings.NewReader(sseData), time.Now(), "")
+
+	var done sseEvent
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		var evt sseEvent
+		if err := json.Unmarshal([]byte(data), &evt); err != nil {
+			t.Fatalf("parse event: %v", err)
+		}
+		if evt.Type != sseTypeDone && evt.Timings != nil {
+			t.Errorf("%s event carries timings", evt.Type)
+		}
+		done = evt
+	}
+	if done.Type != sseTypeDone || done.Timings == nil {
+		t.Fatalf("last event = %+v, want done with timings", done)
+	}
+	if done.Timings.QueueMS < 50 || done.Timings.TotalMS < done.Timings.QueueMS {
+		t.Errorf("timings = %+v", done.Timings)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/hooks"
+	"github.com/AltairaLabs/PromptKit/sdk"
+	a2aserver "github.com/AltairaLabs/PromptKit/server/a2a"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// toolAuditHookName identifies the audit hook among the SDK's tool hooks.
+const toolAuditHookName = "agentcore_tool_audit"
+
+// toolAuditTimeout bounds each audit write so a slow memory API cannot pile
+// up background writes.
+const toolAuditTimeout = 5 * time.Second
+
+// toolAuditRecorder persists tool audit records; *agentcore.ToolAuditor is
+// the production implementation.
+type toolAuditRecorder interface {
+	Record(ctx context.Context, sessionID string, rec agentcore.ToolAuditRecord) (bool, error)
+}
+
+// toolAudit records completed tool calls in the background so auditing
+// never adds latency to the tool loop.
+type toolAudit struct {
+	recorder toolAuditRecorder
+	log      *slog.Logger
+	wg       sync.WaitGroup
+}
+
+// buildToolAudit returns the tool audit for cfg, or nil when auditing is off
+// or the memory it writes to is unavailable.
+func buildToolAudit(cfg *runtimeConfig, log *slog.Logger) *toolAudit {
+	if !cfg.ToolAudit {
+		return nil
+	}
+	if cfg.MemoryID == "" || cfg.AWSRegion == "" {
+		log.Warn("tool audit enabled without memory, skipping",
+			"memory_id", cfg.MemoryID, "aws_region", cfg.AWSRegion)
+		return nil
+	}
+	client, err := agentcore.NewDataPlaneClient(cfg.AWSRegion)
+	if err != nil {
+		log.Warn("tool audit init failed, skipping", "error", err)
+		return nil
+	}
+	return &toolAudit{
+		recorder: agentcore.NewToolAuditor(cfg.MemoryID, client, cfg.ToolAuditMaxEvents),
+		log:      log,
+	}
+}
+
+// hookFor returns a tool hook that records calls into sessionID.
+func (a *toolAudit) hookFor(sessionID string) hooks.ToolHook {
+	return &toolAuditHook{audit: a, sessionID: sessionID}
+}
+
+// record writes rec in the background.
+func (a *toolAudit) record(sessionID string, rec agentcore.ToolAuditRecord) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), toolAuditTimeout)
+		defer cancel()
+		written, err := a.recorder.Record(ctx, sessionID, rec)
+		switch {
+		case err != nil:
+			a.log.Warn("tool audit write failed", "session_id", sessionID, "tool", rec.Tool, "error", err)
+		case !written:
+			a.log.Debug("tool audit cap reached", "session_id", sessionID, "tool", rec.Tool)
+		}
+	}()
+}
+
+// wait blocks until every pending audit write has finished.
+func (a *toolAudit) wait() {
+	a.wg.Wait()
+}
+
+// toolAuditHook is the per-session hooks.ToolHook. It never blocks or
+// denies a call.
+type toolAuditHook struct {
+	audit     *toolAudit
+	sessionID string
+}
+
+// Name implements hooks.ToolHook.
+func (h *toolAuditHook) Name() string { return toolAuditHookName }
+
+// BeforeExecution implements hooks.ToolHook.
+func (h *toolAuditHook) BeforeExecution(context.Context, hooks.ToolRequest) hooks.Decision {
+	return hooks.Allow
+}
+
+// AfterExecution implements hooks.ToolHook by recording the completed call.
+func (h *toolAuditHook) AfterExecution(_ context.Context, req hooks.ToolRequest, resp hooks.ToolResponse) hooks.Decision {
+	h.audit.record(h.sessionID, agentcore.NewToolAuditRecord(
+		req.Name, req.CallID, req.Args, resp.Content, resp.Error, resp.LatencyMs))
+	return hooks.Allow
+}
+
+// buildOpener returns the A2A conversation opener. With tool auditing on,
+// each conversation gets a tool hook bound to its context ID, which the
+// bridges also use as the AgentCore session ID.
+func buildOpener(packFile, agentName string, opts []sdk.Option, audit *toolAudit) sdk.A2AConversationOpener {
+	if audit == nil {
+		return sdk.A2AOpener(packFile, agentName, opts...)
+	}
+	return func(contextID string) (a2aserver.Conversation, error) {
+		sessionOpts := append(slices.Clone(opts), sdk.WithToolHook(audit.hookFor(contextID)))
+		return sdk.A2AOpener(packFile, agentName, sessionOpts...)(contextID)
+	}
+}
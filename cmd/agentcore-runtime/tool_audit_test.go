@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/hooks"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+type fakeAuditRecorder struct {
+	mu       sync.Mutex
+	sessions []string
+	records  []agentcore.ToolAuditRecord
+	err      error
+}
+
+func (f *fakeAuditRecorder) Record(_ context.Context, sessionID string, rec agentcore.ToolAuditRecord) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = append(f.sessions, sessionID)
+	f.records = append(f.records, rec)
+	return f.err == nil, f.err
+}
+
+func TestToolAuditHook_RecordsCompletedCalls(t *testing.T) {
+	rec := &fakeAuditRecorder{}
+	audit := &toolAudit{recorder: rec, log: slog.Default()}
+	hook := audit.hookFor("ctx-1")
+
+	req := hooks.ToolRequest{Name: "lookup", CallID: "call-1", Args: []byte(`{"id":1}`)}
+	if d := hook.BeforeExecution(context.Background(), req); !d.Allow {
+		t.Error("BeforeExecution should allow")
+	}
+	resp := hooks.ToolResponse{Name: "lookup", CallID: "call-1", Error: "boom", LatencyMs: 12}
+	if d := hook.AfterExecution(context.Background(), req, resp); !d.Allow {
+		t.Error("AfterExecution should allow")
+	}
+	audit.wait()
+
+	if len(rec.records) != 1 || rec.sessions[0] != "ctx-1" {
+		t.Fatalf("recorded %d records in sessions %v", len(rec.records), rec.sessions)
+	}
+	got := rec.records[0]
+	if got.Tool != "lookup" || got.CallID != "call-1" || got.Outcome != agentcore.ToolOutcomeError ||
+		got.DurationMs != 12 || got.ArgsBytes != 8 {
+		t.Errorf("record = %+v", got)
+	}
+}
+
+func TestToolAuditHook_WriteFailureDoesNotBlock(t *testing.T) {
+	rec := &fakeAuditRecorder{err: errors.New("throttled")}
+	audit := &toolAudit{recorder: rec, log: slog.Default()}
+	d := audit.hookFor("ctx-1").AfterExecution(context.Background(), hooks.ToolRequest{Name: "t"}, hooks.ToolResponse{})
+	audit.wait()
+	if !d.Allow {
+		t.Error("a failed audit write must not deny the tool call")
+	}
+}
+
+func TestBuildToolAudit_Disabled(t *testing.T) {
+	if a := buildToolAudit(&runtimeConfig{}, slog.Default()); a != nil {
+		t.Error("expected nil audit when disabled")
+	}
+	if a := buildToolAudit(&runtimeConfig{ToolAudit: true}, slog.Default()); a != nil {
+		t.Error("expected nil audit without memory")
+	}
+}
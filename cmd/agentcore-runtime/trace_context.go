@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceIDResponseHeader echoes the trace ID of a traced request as 32
+// hex digits, whichever header format carried it in.
+const traceIDResponseHeader = "X-Trace-Id"
+
+// bridgePropagator reads and writes the trace headers AgentCore and
+// callers send: W3C traceparent, tracestate and baggage, and the X-Ray
+// X-Amzn-Trace-Id. It does not depend on tracing being enabled, so the
+// bridge forwards trace context even when the runtime exports no spans.
+var bridgePropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+	xray.Propagator{},
+)
+
+// echoPropagator writes the trace headers echoed in responses; baggage is
+// not echoed.
+var echoPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	xray.Propagator{},
+)
+
+// withTraceContext extracts the incoming trace context into the request
+// context, where the A2A forwarders pick it up, and echoes the trace in
+// the response headers.
+func withTraceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := bridgePropagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			echoPropagator.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+			w.Header().Set(traceIDResponseHeader, sc.TraceID().String())
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// postA2A posts the JSON-RPC body to the A2A server at a2aURL, carrying
+// the trace context of ctx. Cancelling ctx does not cancel the request, so
+// async invocations outlive the HTTP request that started them.
+func postA2A(ctx context.Context, a2aURL string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, a2aURL,
+		bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	bridgePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return http.DefaultClient.Do(req) //nolint:gosec // internal loopback
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testTraceID     = "5759e988bd862e3fe1be46a994272793"
+	testTraceparent = "00-" + testTraceID + "-53995c3f42cd8ad8-01"
+	testXRayHeader  = "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+)
+
+// traceCapturingBridge returns a bridge whose A2A upstream records the
+// headers of each request, and the handler serving its invocations.
+func traceCapturingBridge(t *testing.T) (http.Handler, *[]http.Header) {
+	t.Helper()
+	var seen []http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Clone())
+		_, _ = io.WriteString(w, `{"result":{"id":"task-1","status":{"state":"completed"},`+
+			`"artifacts":[{"parts":[{"text":"hi"}]}]}}`)
+	}))
+	t.Cleanup(upstream.Close)
+	b := &httpBridge{a2aPort: extractTestPort(t, upstream.URL), log: quietLogger()}
+	return withTraceContext(http.HandlerFunc(b.handleInvocation)), &seen
+}
+
+func invokeWithHeaders(h http.Handler, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"hello"}`))
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestTraceContext_XRayHeaderPropagated(t *testing.T) {
+	h, seen := traceCapturingBridge(t)
+	w := invokeWithHeaders(h, map[string]string{"X-Amzn-Trace-Id": testXRayHeader})
+	if w.Code != http.StatusOK || len(*seen) != 1 {
+		t.Fatalf("invocation = %d with %d upstream calls", w.Code, len(*seen))
+	}
+	up := (*seen)[0]
+	if got := up.Get("traceparent"); got != testTraceparent {
+		t.Errorf("forwarded traceparent = %q, want %q", got, testTraceparent)
+	}
+	if got := up.Get("X-Amzn-Trace-Id"); !strings.HasPrefix(got, "Root=1-5759e988-bd862e3fe1be46a994272793") {
+		t.Errorf("forwarded X-Amzn-Trace-Id = %q", got)
+	}
+	if got := w.Header().Get(traceIDResponseHeader); got != testTraceID {
+		t.Errorf("echoed trace ID = %q, want %q", got, testTraceID)
+	}
+	if got := w.Header().Get("traceparent"); got != testTraceparent {
+		t.Errorf("echoed traceparent = %q", got)
+	}
+}
+
+func TestTraceContext_W3CHeadersPropagated(t *testing.T) {
+	h, seen := traceCapturingBridge(t)
+	w := invokeWithHeaders(h, map[string]string{"traceparent": testTraceparent, "baggage": "tenant=acme"})
+	up := (*seen)[0]
+	if got := up.Get("traceparent"); got != testTraceparent {
+		t.Errorf("forwarded traceparent = %q, want %q", got, testTraceparent)
+	}
+	if got := up.Get("baggage"); got != "tenant=acme" {
+		t.Errorf("forwarded baggage = %q", got)
+	}
+	if got := w.Header().Get("baggage"); got != "" {
+		t.Errorf("baggage echoed in response: %q", got)
+	}
+	if got := w.Header().Get(traceIDResponseHeader); got != testTraceID {
+		t.Errorf("echoed trace ID = %q, want %q", got, testTraceID)
+	}
+}
+
+func TestTraceContext_UntracedRequest(t *testing.T) {
+	h, seen := traceCapturingBridge(t)
+	w := invokeWithHeaders(h, nil)
+	for _, name := range []string{"traceparent", "X-Amzn-Trace-Id"} {
+		if got := (*seen)[0].Get(name); got != "" {
+			t.Errorf("untraced request forwarded %s = %q", name, got)
+		}
+	}
+	if got := w.Header().Get(traceIDResponseHeader); got != "" {
+		t.Errorf("untraced response has trace ID %q", got)
+	}
+}
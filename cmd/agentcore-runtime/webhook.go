@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Webhook event names sent in webhookEvent.Event.
+const (
+	webhookPreInvoke  = "pre_invoke"
+	webhookPostInvoke = "post_invoke"
+)
+
+// Webhook request headers. The signature is an HMAC-SHA256 over
+// "<timestamp>.<body>" keyed with the webhook secret.
+const (
+	webhookSignatureHeader = "X-PromptPack-Signature"
+	webhookTimestampHeader = "X-PromptPack-Timestamp"
+	webhookEventHeader     = "X-PromptPack-Event"
+)
+
+// defaultWebhookTimeout bounds each webhook call when none is configured.
+const defaultWebhookTimeout = 2 * time.Second
+
+// Circuit breaker settings: after webhookBreakerThreshold consecutive
+// failures a webhook is skipped for webhookBreakerCooldown.
+const (
+	webhookBreakerThreshold = 5
+	webhookBreakerCooldown  = 30 * time.Second
+)
+
+// errWebhookCircuitOpen is returned while a webhook's circuit is open.
+var errWebhookCircuitOpen = errors.New("webhook circuit open")
+
+// webhookEvent is the JSON body posted to invoke webhooks. It carries
+// request metadata only, never prompt or response text.
+type webhookEvent struct {
+	Event        string         `json:"event"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Path         string         `json:"path"`
+	SessionID    string         `json:"session_id,omitempty"`
+	TaskID       string         `json:"task_id,omitempty"`
+	Status       int            `json:"status,omitempty"`
+	LatencyMS    int64          `json:"latency_ms,omitempty"`
+	PromptBytes  int            `json:"prompt_bytes,omitempty"`
+	InputTokens  int            `json:"input_tokens,omitempty"`
+	OutputTokens int            `json:"output_tokens,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+}
+
+// webhook posts signed events to one URL.
+type webhook struct {
+	url     string
+	secret  string
+	client  *http.Client
+	breaker circuitBreaker
+}
+
+// newWebhook returns a webhook for url, or nil when url is empty.
+func newWebhook(url, secret string, timeout time.Duration) *webhook {
+	if url == "" {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &webhook{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// send posts ev and returns the response status. Transport errors, 5xx
+// responses, and an open circuit are returned as errors.
+func (h *webhook) send(ctx context.Context, ev *webhookEvent) (int, error) {
+	if !h.breaker.allow() {
+		return 0, errWebhookCircuitOpen
+	}
+	status, err := h.post(ctx, ev)
+	h.breaker.record(err)
+	return status, err
+}
+
+// post performs one signed POST.
+func (h *webhook) post(ctx context.Context, ev *webhookEvent) (int, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := strconv.FormatInt(ev.Timestamp.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, ev.Event)
+	req.Header.Set(webhookTimestampHeader, ts)
+	if h.secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(h.secret, ts, body))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp.StatusCode, fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "<ts>.<body>".
+func signWebhook(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// circuitBreaker stops calling a failing webhook for a cooldown period.
+// After the cooldown it is half-open: one trial call is let through and
+// the others are refused until that call's outcome closes the breaker or
+// reopens it.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool             // a half-open trial call is in flight
+	now       func() time.Time // nil uses time.Now
+}
+
+func (c *circuitBreaker) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// allow reports whether a call may be made. Every call it allows must be
+// followed by record.
+func (c *circuitBreaker) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures < webhookBreakerThreshold {
+		return true
+	}
+	if c.trial || c.clock().Before(c.openUntil) {
+		return false
+	}
+	c.trial = true
+	return true
+}
+
+// record updates the breaker with a call's outcome.
+func (c *circuitBreaker) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trial = false
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= webhookBreakerThreshold {
+		c.openUntil = c.clock().Add(webhookBreakerCooldown)
+	}
+}
+
+// invokeWebhooks holds the optional pre- and post-invoke webhooks.
+type invokeWebhooks struct {
+	pre  *webhook
+	post *webhook
+	log  *slog.Logger
+	wg   sync.WaitGroup
+}
+
+// buildInvokeWebhooks returns the webhooks configured in cfg, or nil when
+// neither URL is set.
+func buildInvokeWebhooks(cfg *runtimeConfig, log *slog.Logger) *invokeWebhooks {
+	pre := newWebhook(cfg.PreInvokeWebhookURL, cfg.WebhookSecret, cfg.WebhookTimeout)
+	post := newWebhook(cfg.PostInvokeWebhookURL, cfg.WebhookSecret, cfg.WebhookTimeout)
+	if pre == nil && post == nil {
+		return nil
+	}
+	if cfg.WebhookSecret == "" {
+		log.Warn("invoke webhooks configured without a secret; requests are unsigned")
+	}
+	return &invokeWebhooks{pre: pre, post: post, log: log}
+}
+
+// preInvoke calls the pre-invoke webhook and reports whether the
+// invocation may proceed. A 4xx response rejects it; an unreachable,
+// failing, or circuit-broken webhook lets it through.
+func (w *invokeWebhooks) preInvoke(ctx context.Context, ev *webhookEvent) bool {
+	if w == nil || w.pre == nil {
+		return true
+	}
+	ev.Event = webhookPreInvoke
+	status, err := w.pre.send(ctx, ev)
+	if err != nil {
+		w.log.Warn("pre-invoke webhook failed, continuing", "error", err)
+		return true
+	}
+	if status >= http.StatusBadRequest {
+		w.log.Info("invocation rejected by pre-invoke webhook", "status", status, "session_id", ev.SessionID)
+		return false
+	}
+	return true
+}
+
+// postInvoke calls the post-invoke webhook in the background so it never
+// delays the response.
+func (w *invokeWebhooks) postInvoke(ev *webhookEvent) {
+	if w == nil || w.post == nil {
+		return
+	}
+	ev.Event = webhookPostInvoke
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		if _, err := w.post.send(context.Background(), ev); err != nil {
+			w.log.Warn("post-invoke webhook failed", "error", err)
+		}
+	}()
+}
+
+// wait blocks until background post-invoke calls finish.
+func (w *invokeWebhooks) wait() {
+	if w != nil {
+		w.wg.Wait()
+	}
+}
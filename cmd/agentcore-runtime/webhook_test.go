@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookRecorder is a webhook endpoint that records events and answers
+// with status.
+type webhookRecorder struct {
+	mu      sync.Mutex
+	status  int
+	events  []webhookEvent
+	headers []http.Header
+	bodies  [][]byte
+}
+
+func newWebhookServer(t *testing.T, status int) (*webhookRecorder, *httptest.Server) {
+	t.Helper()
+	rec := &webhookRecorder{status: status}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var ev webhookEvent
+		_ = json.Unmarshal(body, &ev)
+		rec.mu.Lock()
+		rec.events = append(rec.events, ev)
+		rec.headers = append(rec.headers, r.Header.Clone())
+		rec.bodies = append(rec.bodies, body)
+		rec.mu.Unlock()
+		w.WriteHeader(rec.status)
+	}))
+	t.Cleanup(srv.Close)
+	return rec, srv
+}
+
+func TestWebhook_SignsRequests(t *testing.T) {
+	rec, srv := newWebhookServer(t, http.StatusOK)
+	h := newWebhook(srv.URL, "s3cret", 0)
+
+	status, err := h.send(context.Background(), &webhookEvent{Event: webhookPreInvoke, Timestamp: time.Unix(1700000000, 0)})
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("send = %d, %v", status, err)
+	}
+	hdr := rec.headers[0]
+	if hdr.Get(webhookTimestampHeader) != "1700000000" || hdr.Get(webhookEventHeader) != webhookPreInvoke {
+		t.Errorf("headers = %v", hdr)
+	}
+	want := "sha256=" + signWebhook("s3cret", "1700000000", rec.bodies[0])
+	if got := hdr.Get(webhookSignatureHeader); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+}
+
+func TestCircuitBreaker_OpensAndRecovers(t *testing.T) {
+	now := time.Unix(0, 0)
+	cb := &circuitBreaker{now: func() time.Time { return now }}
+	for range webhookBreakerThreshold {
+		if !cb.allow() {
+			t.Fatal("breaker opened early")
+		}
+		cb.record(io.ErrUnexpectedEOF)
+	}
+	if cb.allow() {
+		t.Fatal("breaker still closed after threshold failures")
+	}
+
+	now = now.Add(webhookBreakerCooldown)
+	if !cb.allow() {
+		t.Fatal("breaker did not allow a trial call after the cooldown")
+	}
+	if cb.allow() {
+		t.Fatal("half-open breaker allowed a second call during the trial")
+	}
+	cb.record(nil)
+	cb.record(io.ErrUnexpectedEOF)
+	if !cb.allow() {
+		t.Error("a single failure after recovery reopened the breaker")
+	}
+}
+
+func TestCircuitBreaker_FailedTrialReopens(t *testing.T) {
+	now := time.Unix(0, 0)
+	cb := &circuitBreaker{now: func() time.Time { return now }}
+	for range webhookBreakerThreshold {
+		cb.record(io.ErrUnexpectedEOF)
+	}
+
+	now = now.Add(webhookBreakerCooldown)
+	if !cb.allow() {
+		t.Fatal("breaker did not allow a trial call after the cooldown")
+	}
+	cb.record(io.ErrUnexpectedEOF)
+	if cb.allow() {
+		t.Fatal("breaker closed after a failed trial call")
+	}
+	now = now.Add(webhookBreakerCooldown)
+	if !cb.allow() {
+		t.Error("breaker did not allow another trial call after the next cooldown")
+	}
+}
+
+func TestHandleInvocation_PreInvokeWebhookRejects(t *testing.T) {
+	rec, srv := newWebhookServer(t, http.StatusForbidden)
+	b := &httpBridge{
+		log:      slog.Default(),
+		webhooks: buildInvokeWebhooks(&runtimeConfig{PreInvokeWebhookURL: srv.URL}, slog.Default()),
+	}
+
+	r := httptest.NewRequest(http.MethodPost, invocationsPath, strings.NewReader(`{"prompt":"hello","tenant":"t1"}`))
+	r.Header.Set(sessionHeader, "s-1")
+	w := httptest.NewRecorder()
+	b.handleInvocation(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+	ev := rec.events[0]
+	if ev.Event != webhookPreInvoke || ev.SessionID != "s-1" || ev.PromptBytes != len("hello") {
+		t.Errorf("event = %+v", ev)
+	}
+	if payload, _ := ev.Metadata["payload"].(map[string]any); payload["tenant"] != "t1" {
+		t.Errorf("metadata = %v, want the invocation metadata", ev.Metadata)
+	}
+}
+
+func TestPreInvoke_FailsOpen(t *testing.T) {
+	w := buildInvokeWebhooks(&runtimeConfig{
+		PreInvokeWebhookURL: "http://127.0.0.1:1", WebhookTimeout: 100 * time.Millisecond,
+	}, slog.Default())
+	if !w.preInvoke(context.Background(), &webhookEvent{}) {
+		t.Error("unreachable pre-invoke webhook blocked the invocation")
+	}
+}
+
+func TestWithAccessLog_PostInvokeWebhook(t *testing.T) {
+	rec, srv := newWebhookServer(t, http.StatusOK)
+	b := &httpBridge{
+		log:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		logSampleRate: 1,
+		webhooks:      buildInvokeWebhooks(&runtimeConfig{PostInvokeWebhookURL: srv.URL}, slog.Default()),
+	}
+	h := b.withAccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := accessLogFrom(r.Context())
+		entry.setTask("task-9")
+		entry.setUsage(&usageInfo{InputTokens: 3, OutputTokens: 4})
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, invocationsPath, nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+	b.webhooks.wait()
+
+	if len(rec.events) != 1 {
+		t.Fatalf("events = %+v, want one post-invoke event", rec.events)
+	}
+	ev := rec.events[0]
+	if ev.Event != webhookPostInvoke || ev.TaskID != "task-9" || ev.Status != http.StatusOK ||
+		ev.InputTokens != 3 || ev.OutputTokens != 4 {
+		t.Errorf("event = %+v", ev)
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,12 +17,30 @@ const wsReadLimit = 1 << 20 // 1 MiB
 // wsBufferSize is the read/write buffer size for WebSocket connections.
 const wsBufferSize = 4096
 
-// upgrader configures the WebSocket upgrade with permissive origin checks
-// (the bridge is only reachable from within the AgentCore VPC).
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  wsBufferSize,
-	WriteBufferSize: wsBufferSize,
-	CheckOrigin:     func(r *http.Request) bool { return true },
+// WebSocket keepalive defaults. AgentCore load balancers drop idle
+// connections silently, so the bridge pings well inside their idle window.
+const (
+	defaultWSPingInterval = 30 * time.Second
+	defaultWSIdleTimeout  = 10 * time.Minute
+	// wsPongWaitFactor is how many ping intervals may pass without a pong
+	// before the peer is considered gone.
+	wsPongWaitFactor = 2
+	// wsWriteWait bounds every write (data and control frames).
+	wsWriteWait = 10 * time.Second
+)
+
+// wsIdleCloseReason is sent in the close frame when the idle timeout fires.
+const wsIdleCloseReason = "idle timeout"
+
+// newUpgrader configures the WebSocket upgrade. Without a CORS policy the
+// origin check is permissive (the bridge is only reachable from within the
+// AgentCore VPC); with one, browsers may only connect from its origins.
+func newUpgrader(cors *corsPolicy) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  wsBufferSize,
+		WriteBufferSize: wsBufferSize,
+		CheckOrigin:     cors.checkOrigin,
+	}
 }
 
 // wsRequest is the WebSocket message payload from the client.
@@ -39,19 +59,30 @@ func (r *wsRequest) text() string {
 }
 
 // wsResponse is the WebSocket message payload sent to the client.
+// Seq numbers the text and status frames of a single request, starting at 1,
+// so clients can detect gaps and order chunks.
 type wsResponse struct {
-	Type      string     `json:"type"`
-	Content   string     `json:"content,omitempty"`
-	State     string     `json:"state,omitempty"`
-	TaskID    string     `json:"task_id,omitempty"`
-	ContextID string     `json:"context_id,omitempty"`
-	Usage     *usageInfo `json:"usage,omitempty"`
+	Type      string `json:"type"`
+	Content   string `json:"content,omitempty"`
+	State     string `json:"state,omitempty"`
+	TaskID    string `json:"task_id,omitempty"`
+	ContextID string `json:"context_id,omitempty"`
+	Seq       int    `json:"seq,omitempty"`
+	// RetryAfter is set on the error frame of a message refused by a rate
+	// limit or concurrency cap: the seconds to wait before sending again.
+	RetryAfter int `json:"retry_after,omitempty"`
 }
 
+// wsTypeDone is the frame type that ends the response to one request.
+const wsTypeDone = "done"
+
 // handleWebSocket upgrades the connection and processes messages.
-// Each message is forwarded to the A2A server as a blocking message/send.
+// Each message is forwarded to the A2A server as a message/stream request
+// and the resulting events are relayed as individual frames.
+// The server pings the client periodically and closes the connection with
+// a close frame once no client message has arrived within the idle timeout.
 func (b *httpBridge) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := newUpgrader(b.cors).Upgrade(w, r, nil)
 	if err != nil {
 		b.log.Error("websocket upgrade failed", "error", err)
 		return
@@ -60,26 +91,170 @@ func (b *httpBridge) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	conn.SetReadLimit(wsReadLimit)
 
+	ka := newWSKeepalive(conn, b.pingInterval(), b.idleTimeout())
+	done := make(chan struct{})
+	defer close(done)
+	go ka.run(done)
+
 	b.log.Info("websocket connection established")
 
 	for {
 		_, msg, readErr := conn.ReadMessage()
 		if readErr != nil {
-			if websocket.IsUnexpectedCloseError(readErr,
+			switch {
+			case ka.idleClosed.Load():
+				b.log.Info("websocket closed after idle timeout")
+			case websocket.IsUnexpectedCloseError(readErr,
 				websocket.CloseNormalClosure,
-				websocket.CloseGoingAway) {
+				websocket.CloseGoingAway):
 				b.log.Error("websocket read error", "error", readErr)
 			}
 			return
 		}
 
-		b.processWSMessage(conn, msg)
+		ka.begin()
+		b.processWSMessage(r.Context(), conn, r.Header.Get(sessionHeader), msg)
+		ka.end()
+	}
+}
+
+// pingInterval returns the configured WebSocket ping interval or the default.
+func (b *httpBridge) pingInterval() time.Duration {
+	if b.wsPingInterval > 0 {
+		return b.wsPingInterval
+	}
+	return defaultWSPingInterval
+}
+
+// idleTimeout returns the configured WebSocket idle timeout or the default.
+func (b *httpBridge) idleTimeout() time.Duration {
+	if b.wsIdleTimeout > 0 {
+		return b.wsIdleTimeout
+	}
+	return defaultWSIdleTimeout
+}
+
+// wsKeepalive drives server-initiated pings, pong-based read deadlines, and
+// the idle timeout for a single WebSocket connection.
+type wsKeepalive struct {
+	conn         *websocket.Conn
+	pingInterval time.Duration
+	idleTimeout  time.Duration
+
+	lastActive atomic.Int64 // unix nanos of the last client message or reply
+	busy       atomic.Bool  // true while a message is being processed
+	idleClosed atomic.Bool  // set once the idle close frame has been sent
+}
+
+// newWSKeepalive installs the pong handler and initial read deadline.
+func newWSKeepalive(conn *websocket.Conn, pingInterval, idleTimeout time.Duration) *wsKeepalive {
+	ka := &wsKeepalive{conn: conn, pingInterval: pingInterval, idleTimeout: idleTimeout}
+	ka.touch()
+	ka.extendReadDeadline()
+	conn.SetPongHandler(func(string) error {
+		ka.extendReadDeadline()
+		return nil
+	})
+	return ka
+}
+
+// pongWait is how long a read may block before the peer is considered gone.
+func (ka *wsKeepalive) pongWait() time.Duration {
+	return ka.pingInterval * wsPongWaitFactor
+}
+
+// extendReadDeadline pushes the read deadline out by one pong wait.
+func (ka *wsKeepalive) extendReadDeadline() {
+	_ = ka.conn.SetReadDeadline(time.Now().Add(ka.pongWait()))
+}
+
+// touch records client activity for the idle timeout.
+func (ka *wsKeepalive) touch() {
+	ka.lastActive.Store(time.Now().UnixNano())
+}
+
+// begin marks the start of message processing. Processing can outlast the
+// pong wait, so the read deadline is lifted until end is called.
+func (ka *wsKeepalive) begin() {
+	ka.busy.Store(true)
+	ka.touch()
+	_ = ka.conn.SetReadDeadline(time.Time{})
+}
+
+// end marks the end of message processing and restores the read deadline.
+func (ka *wsKeepalive) end() {
+	ka.touch()
+	ka.extendReadDeadline()
+	ka.busy.Store(false)
+}
+
+// idle reports whether the connection has been inactive past the idle timeout.
+func (ka *wsKeepalive) idle(now time.Time) bool {
+	if ka.busy.Load() {
+		return false
 	}
+	return now.Sub(time.Unix(0, ka.lastActive.Load())) >= ka.idleTimeout
 }
 
-// processWSMessage handles a single WebSocket message by forwarding it
-// to the A2A server and writing the response back.
-func (b *httpBridge) processWSMessage(conn *websocket.Conn, msg []byte) {
+// run sends pings every interval and closes idle connections until done is
+// closed. A failed ping or the idle close ends the loop; the resulting read
+// error then unwinds handleWebSocket, so no goroutine outlives the connection.
+func (ka *wsKeepalive) run(done <-chan struct{}) {
+	ticker := time.NewTicker(ka.tickInterval())
+	defer ticker.Stop()
+	nextPing := time.Now().Add(ka.pingInterval)
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if ka.idle(now) {
+				ka.closeIdle()
+				return
+			}
+			if now.Before(nextPing) {
+				continue
+			}
+			nextPing = now.Add(ka.pingInterval)
+			if err := ka.conn.WriteControl(websocket.PingMessage, nil, now.Add(wsWriteWait)); err != nil {
+				_ = ka.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// tickInterval is the keepalive loop granularity: the shorter of the ping
+// interval and idle timeout, so neither fires late by more than one tick.
+func (ka *wsKeepalive) tickInterval() time.Duration {
+	return min(ka.pingInterval, ka.idleTimeout)
+}
+
+// closeIdle sends a close frame with the idle reason and closes the socket.
+func (ka *wsKeepalive) closeIdle() {
+	ka.idleClosed.Store(true)
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, wsIdleCloseReason)
+	_ = ka.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+	_ = ka.conn.Close()
+}
+
+// processWSMessage handles a single WebSocket message by streaming it to
+// the A2A server and relaying each text chunk and status update as a frame.
+// ctx carries the trace context of the connection's upgrade request. Each
+// message takes a limiter slot for sessionID, the session of the upgrade
+// request, as an invocation would; a refused message gets an error frame
+// with the seconds to wait.
+func (b *httpBridge) processWSMessage(ctx context.Context, conn *websocket.Conn, sessionID string, msg []byte) {
+	if b.limits != nil {
+		release, refusal, retryAfter := b.limits.acquire(sessionID)
+		if release == nil {
+			b.writeWSJSON(conn, wsResponse{Type: keyError, Content: refusal, RetryAfter: retryAfterSeconds(retryAfter)})
+			return
+		}
+		defer release()
+	}
+
 	var req wsRequest
 	if err := json.Unmarshal(msg, &req); err != nil {
 		b.writeWSError(conn, "invalid JSON")
@@ -97,73 +272,58 @@ func (b *httpBridge) processWSMessage(conn *websocket.Conn, msg []byte) {
 		return
 	}
 
-	respBody, err := b.forwardToA2A(a2aBody)
+	resp, err := b.openA2AStream(ctx, a2aBody)
 	if err != nil {
 		b.writeWSError(conn, "agent unavailable")
 		return
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	b.writeWSA2AResponse(conn, respBody)
+	b.relayWSStream(conn, resp.Body)
 }
 
-// buildWSA2ARequest creates a blocking A2A message/send for WebSocket messages.
+// buildWSA2ARequest creates a streaming A2A message/stream for WebSocket messages.
 func buildWSA2ARequest(text string, metadata map[string]any) ([]byte, error) {
-	message := map[string]any{
-		keyRole: roleUser,
-		keyParts: []map[string]any{
-			{keyKind: kindText, kindText: text},
-		},
-		keyMessageID: fmt.Sprintf("ws-%d", time.Now().UnixNano()),
-	}
-	if len(metadata) > 0 {
-		message["metadata"] = metadata
-	}
-
-	a2aReq := map[string]any{
-		keyJSONRPC: jsonrpcVersion,
-		"id":       "ws-bridge-1",
-		keyMethod:  methodMessageSend,
-		keyParams: map[string]any{
-			keyMessage: message,
-			keyConfiguration: map[string]any{
-				keyBlocking: true,
-			},
-		},
-	}
-	return json.Marshal(a2aReq)
+	return json.Marshal(newA2ARequest(rpcIDWS, methodMessageStream, messageIDPrefixWS, text, "", metadata))
 }
 
-// writeWSA2AResponse parses the A2A JSON-RPC response and writes a
-// wsResponse message to the WebSocket connection.
-func (b *httpBridge) writeWSA2AResponse(conn *websocket.Conn, body []byte) {
-	var result a2aResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		b.writeWSError(conn, "invalid response from agent")
-		return
-	}
-
-	if result.Error != nil {
-		b.writeWSError(conn, result.Error.Message)
-		return
-	}
-
-	if result.Result.Status.State == stateFailed {
-		b.writeWSJSON(conn, wsResponse{
-			Type:    keyError,
-			Content: extractFailedMessage(&result),
-		})
-		return
-	}
-
-	b.writeWSJSON(conn, wsResponse{
-		Type:      kindText,
-		Content:   extractArtifactText(&result),
-		TaskID:    result.Result.ID,
-		ContextID: result.Result.ContextID,
-		Usage:     extractUsage(&result),
+// relayWSStream forwards A2A stream events to the WebSocket as sequenced
+// text and status frames, mirroring the SSE bridge. A JSON-RPC error ends
+// the response with an error frame; otherwise a done frame follows the
+// last event. Response moderation runs as it does for SSE.
+func (b *httpBridge) relayWSStream(conn *websocket.Conn, body io.Reader) {
+	seq := 0
+	failed := false
+	checks := b.streamChecks("")
+	b.scanA2AStream(body, func(evt *sseEvent) bool {
+		events := make([]*sseEvent, 0, 1+len(checks))
+		for _, check := range checks {
+			if extra := check.observe(evt); extra != nil {
+				events = append(events, extra)
+			}
+		}
+		for _, e := range append(events, evt) {
+			if e.Type == keyError {
+				b.writeWSError(conn, e.Content)
+				failed = true
+				return false
+			}
+			seq++
+			b.writeWSJSON(conn, wsResponse{
+				Type:      e.Type,
+				Content:   e.Content,
+				State:     e.State,
+				TaskID:    e.TaskID,
+				ContextID: e.ContextID,
+				Seq:       seq,
+			})
+		}
+		return true
 	})
 
-	b.writeWSJSON(conn, wsResponse{Type: "done"})
+	if !failed {
+		b.writeWSJSON(conn, wsResponse{Type: wsTypeDone})
+	}
 }
 
 // writeWSError writes an error message to the WebSocket connection.
@@ -171,8 +331,10 @@ func (b *httpBridge) writeWSError(conn *websocket.Conn, msg string) {
 	b.writeWSJSON(conn, wsResponse{Type: keyError, Content: msg})
 }
 
-// writeWSJSON writes a JSON message to the WebSocket connection.
+// writeWSJSON writes a JSON message to the WebSocket connection under a
+// write deadline so a stalled client cannot block the handler indefinitely.
 func (b *httpBridge) writeWSJSON(conn *websocket.Conn, v any) {
+	_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
 	if err := conn.WriteJSON(v); err != nil {
 		b.log.Error("websocket write error", "error", err)
 	}
@@ -2,12 +2,14 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -41,8 +43,8 @@ func TestBuildWSA2ARequest(t *testing.T) {
 	if err := json.Unmarshal(data, &req); err != nil {
 		t.Fatalf("unmarshal: %v", err)
 	}
-	if req["method"] != "message/send" {
-		t.Errorf("method = %v, want message/send", req["method"])
+	if req["method"] != "message/stream" {
+		t.Errorf("method = %v, want message/stream", req["method"])
 	}
 	if req["id"] != "ws-bridge-1" {
 		t.Errorf("id = %v, want ws-bridge-1", req["id"])
@@ -70,19 +72,14 @@ func TestBuildWSA2ARequest_WithMetadata(t *testing.T) {
 func TestWSBridge_Integration(t *testing.T) {
 	// Mock A2A server.
 	a2aMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		body, _ := io.ReadAll(r.Body)
-		var req map[string]any
-		_ = json.Unmarshal(body, &req)
+		_, _ = io.ReadAll(r.Body)
 
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{
-			"result": {
-				"id": "task-ws",
-				"contextId": "ctx-ws",
-				"status": {"state": "completed"},
-				"artifacts": [{"parts": [{"text": "ws response"}]}]
-			}
-		}`))
+		w.Header().Set("Content-Type", sseContentType)
+		_, _ = w.Write([]byte(
+			`data: {"result":{"taskId":"task-ws","contextId":"ctx-ws","artifact":{"parts":[{"text":"ws response"}]}}}` +
+				"\n\n" +
+				`data: {"result":{"taskId":"task-ws","contextId":"ctx-ws","status":{"state":"completed"}}}` +
+				"\n\n"))
 	}))
 	defer a2aMock.Close()
 
@@ -136,6 +133,22 @@ func TestWSBridge_Integration(t *testing.T) {
 	if wsResp.ContextID != "ctx-ws" {
 		t.Errorf("ContextID = %q, want ctx-ws", wsResp.ContextID)
 	}
+	if wsResp.Seq != 1 {
+		t.Errorf("Seq = %d, want 1", wsResp.Seq)
+	}
+
+	// Read the completed status.
+	_, data, err = conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ws read status: %v", err)
+	}
+	var statusResp wsResponse
+	if err := json.Unmarshal(data, &statusResp); err != nil {
+		t.Fatalf("unmarshal status: %v", err)
+	}
+	if statusResp.Type != "status" || statusResp.State != "completed" || statusResp.Seq != 2 {
+		t.Errorf("status frame = %+v, want completed status with seq 2", statusResp)
+	}
 
 	// Read the done message.
 	_, data, err = conn.ReadMessage()
@@ -192,6 +205,53 @@ func TestWSBridge_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestWSBridge_RateLimited(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := &httpBridge{a2aPort: 1, log: slog.Default(),
+		limits: newTestLimiter(&runtimeConfig{SessionRateLimit: 0.25}, &now)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", b.handleWebSocket)
+	wsSrv := httptest.NewServer(mux)
+	defer wsSrv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(wsSrv.URL, "http") + "/ws"
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{sessionHeader: {"s-1"}})
+	if err != nil {
+		t.Fatalf("ws dial: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	send := func() wsResponse {
+		t.Helper()
+		if writeErr := conn.WriteMessage(websocket.TextMessage, []byte(`{"prompt":"hi"}`)); writeErr != nil {
+			t.Fatalf("ws write: %v", writeErr)
+		}
+		_, data, readErr := conn.ReadMessage()
+		if readErr != nil {
+			t.Fatalf("ws read: %v", readErr)
+		}
+		var wsResp wsResponse
+		if unmarshalErr := json.Unmarshal(data, &wsResp); unmarshalErr != nil {
+			t.Fatalf("unmarshal: %v", unmarshalErr)
+		}
+		return wsResp
+	}
+
+	if first := send(); first.Content != "agent unavailable" {
+		t.Errorf("first frame = %+v, want the message forwarded", first)
+	}
+	second := send()
+	if second.Type != keyError || second.Content != msgSessionRateLimited || second.RetryAfter != 4 {
+		t.Errorf("second frame = %+v, want a rate limit error retrying after 4s", second)
+	}
+}
+
 func TestWSBridge_MissingPrompt(t *testing.T) {
 	b := &httpBridge{a2aPort: 1, log: slog.Default()}
 
@@ -276,3 +336,109 @@ func TestWSBridge_A2AError(t *testing.T) {
 		t.Errorf("Content = %q, want %q", wsResp.Content, "model failed")
 	}
 }
+
+func TestWSBridge_ServerPing(t *testing.T) {
+	b := &httpBridge{a2aPort: 1, log: slog.Default(), wsPingInterval: 20 * time.Millisecond}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", b.handleWebSocket)
+	wsSrv := httptest.NewServer(mux)
+	defer wsSrv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(wsSrv.URL, "http") + "/ws"
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("ws dial: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	// Control frames are only dispatched while reading.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a server ping")
+	}
+}
+
+func TestWSBridge_IdleTimeoutClose(t *testing.T) {
+	b := &httpBridge{
+		a2aPort:        1,
+		log:            slog.Default(),
+		wsPingInterval: time.Second,
+		wsIdleTimeout:  50 * time.Millisecond,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", b.handleWebSocket)
+	wsSrv := httptest.NewServer(mux)
+	defer wsSrv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(wsSrv.URL, "http") + "/ws"
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("ws dial: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("expected close error, got %v", err)
+	}
+	if closeErr.Code != websocket.CloseGoingAway {
+		t.Errorf("close code = %d, want %d", closeErr.Code, websocket.CloseGoingAway)
+	}
+	if closeErr.Text != wsIdleCloseReason {
+		t.Errorf("close reason = %q, want %q", closeErr.Text, wsIdleCloseReason)
+	}
+}
+
+func TestWSKeepalive_Defaults(t *testing.T) {
+	b := &httpBridge{}
+	if b.pingInterval() != defaultWSPingInterval {
+		t.Errorf("pingInterval() = %v, want %v", b.pingInterval(), defaultWSPingInterval)
+	}
+	if b.idleTimeout() != defaultWSIdleTimeout {
+		t.Errorf("idleTimeout() = %v, want %v", b.idleTimeout(), defaultWSIdleTimeout)
+	}
+}
+
+func TestWSKeepalive_IdleWhileBusy(t *testing.T) {
+	ka := &wsKeepalive{idleTimeout: time.Millisecond}
+	ka.lastActive.Store(time.Now().Add(-time.Hour).UnixNano())
+
+	if !ka.idle(time.Now()) {
+		t.Error("expected idle after timeout")
+	}
+	ka.busy.Store(true)
+	if ka.idle(time.Now()) {
+		t.Error("busy connection must not be considered idle")
+	}
+}
@@ -0,0 +1,257 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+// EnvA2ATaskStore selects where the runtime's A2A server keeps its tasks.
+const EnvA2ATaskStore = "PROMPTPACK_A2A_TASK_STORE"
+
+// A2ATaskStoreMemory persists A2A tasks as memory events.
+const A2ATaskStoreMemory = "memory"
+
+// Constants for A2A task persistence.
+const (
+	// A2ATaskActorID is the memory actor that owns A2A task snapshots,
+	// kept apart from conversation history and session metadata.
+	A2ATaskActorID = "promptkit-a2a-tasks"
+
+	// a2aTaskPrefix marks the JSON text of an A2A task snapshot event.
+	a2aTaskPrefix = "promptkit:a2a_task:"
+
+	// a2aTaskRefPrefix marks a context index event naming one task.
+	a2aTaskRefPrefix = "promptkit:a2a_task_ref:"
+
+	// a2aContextSessionPrefix names the memory session indexing the tasks
+	// of one A2A context.
+	a2aContextSessionPrefix = "context-"
+
+	// a2aTaskKindKey and a2aTaskKind tag A2A task events in their
+	// metadata.
+	a2aTaskKindKey = "kind"
+	a2aTaskKind    = "a2a_task"
+
+	// a2aTaskSeqKey holds, in a snapshot event's metadata, the sequence
+	// number ordering the task's snapshots.
+	a2aTaskSeqKey = "seq"
+)
+
+// A2ATaskSnapshotStore persists A2A task snapshots as memory events, one
+// per change, in a memory session named after the task. Each snapshot
+// carries a sequence number that grows with every save, and the highest
+// wins on load; snapshots written without one are ordered by status
+// timestamp. A second session per context indexes the context's task IDs.
+// Callers save the snapshots of one task in order.
+type A2ATaskSnapshotStore struct {
+	memoryID string
+	client   DataPlaneClient
+
+	// seq is the sequence number of the last snapshot saved.
+	seq atomic.Int64
+}
+
+// a2aTaskSnapshot is a decoded task snapshot and its sequence number, 0
+// for snapshots written without one.
+type a2aTaskSnapshot struct {
+	task *a2a.Task
+	seq  int64
+}
+
+// NewA2ATaskSnapshotStore creates an A2ATaskSnapshotStore writing to
+// memoryID.
+func NewA2ATaskSnapshotStore(memoryID string, client DataPlaneClient) *A2ATaskSnapshotStore {
+	return &A2ATaskSnapshotStore{memoryID: memoryID, client: client}
+}
+
+// Load returns the latest snapshot of taskID, or nil if the task has none.
+func (s *A2ATaskSnapshotStore) Load(ctx context.Context, taskID string) (*a2a.Task, error) {
+	var latest *a2aTaskSnapshot
+	var nextToken *string
+	for {
+		out, err := s.client.ListEvents(ctx, &bedrockagentcore.ListEventsInput{
+			MemoryId:        aws.String(s.memoryID),
+			ActorId:         aws.String(A2ATaskActorID),
+			SessionId:       aws.String(taskID),
+			IncludePayloads: aws.Bool(true),
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListEvents A2A task %q: %w", taskID, err)
+		}
+		for i := range out.Events {
+			if snap := decodeA2ASnapshot(&out.Events[i]); snap != nil && newerA2ATask(snap, latest) {
+				latest = snap
+			}
+		}
+		if out.NextToken == nil {
+			if latest == nil {
+				return nil, nil
+			}
+			return latest.task, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// Save writes task as a new snapshot event.
+func (s *A2ATaskSnapshotStore) Save(ctx context.Context, task *a2a.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("encode A2A task: %w", err)
+	}
+	in := s.eventInput(task.ID, a2aTaskPrefix+string(data))
+	in.Metadata[a2aTaskSeqKey] = &dpTypes.MetadataValueMemberStringValue{
+		Value: strconv.FormatInt(s.nextSeq(), 10),
+	}
+	if ts := task.Status.Timestamp; ts != nil {
+		in.EventTimestamp = aws.Time(*ts)
+	}
+	if _, err := s.client.CreateEvent(ctx, in); err != nil {
+		return fmt.Errorf("CreateEvent A2A task %q: %w", task.ID, err)
+	}
+	return nil
+}
+
+// nextSeq returns the sequence number of the next snapshot: the current
+// time in nanoseconds, so numbers keep growing across restarts, or one
+// past the last when the clock has not moved on.
+func (s *A2ATaskSnapshotStore) nextSeq() int64 {
+	for {
+		last := s.seq.Load()
+		next := max(last+1, time.Now().UnixNano())
+		if s.seq.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// AddToContext records taskID in the index of contextID.
+func (s *A2ATaskSnapshotStore) AddToContext(ctx context.Context, contextID, taskID string) error {
+	in := s.eventInput(a2aContextSessionPrefix+contextID, a2aTaskRefPrefix+taskID)
+	if _, err := s.client.CreateEvent(ctx, in); err != nil {
+		return fmt.Errorf("CreateEvent A2A context %q: %w", contextID, err)
+	}
+	return nil
+}
+
+// ContextTaskIDs returns the IDs of the tasks indexed under contextID, in
+// the order they were recorded.
+func (s *A2ATaskSnapshotStore) ContextTaskIDs(ctx context.Context, contextID string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	var nextToken *string
+	for {
+		out, err := s.client.ListEvents(ctx, &bedrockagentcore.ListEventsInput{
+			MemoryId:        aws.String(s.memoryID),
+			ActorId:         aws.String(A2ATaskActorID),
+			SessionId:       aws.String(a2aContextSessionPrefix + contextID),
+			IncludePayloads: aws.Bool(true),
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListEvents A2A context %q: %w", contextID, err)
+		}
+		for i := range out.Events {
+			if id := eventTextWithPrefix(&out.Events[i], a2aTaskRefPrefix); id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if out.NextToken == nil {
+			return ids, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// eventInput builds a CreateEvent request carrying text in sessionID.
+func (s *A2ATaskSnapshotStore) eventInput(sessionID, text string) *bedrockagentcore.CreateEventInput {
+	return &bedrockagentcore.CreateEventInput{
+		MemoryId:  aws.String(s.memoryID),
+		ActorId:   aws.String(A2ATaskActorID),
+		SessionId: aws.String(sessionID),
+		Payload: []dpTypes.PayloadType{
+			&dpTypes.PayloadTypeMemberConversational{
+				Value: dpTypes.Conversational{
+					Role:    dpTypes.RoleOther,
+					Content: &dpTypes.ContentMemberText{Value: text},
+				},
+			},
+		},
+		Metadata: map[string]dpTypes.MetadataValue{
+			a2aTaskKindKey: &dpTypes.MetadataValueMemberStringValue{Value: a2aTaskKind},
+		},
+	}
+}
+
+// decodeA2ATask returns the task snapshot carried by event, or nil if the
+// event is not an A2A task snapshot.
+func decodeA2ATask(event *dpTypes.Event) *a2a.Task {
+	data := eventTextWithPrefix(event, a2aTaskPrefix)
+	if data == "" {
+		return nil
+	}
+	var task a2a.Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil
+	}
+	return &task
+}
+
+// decodeA2ASnapshot returns the task snapshot carried by event with its
+// sequence number, or nil if the event is not an A2A task snapshot.
+func decodeA2ASnapshot(event *dpTypes.Event) *a2aTaskSnapshot {
+	task := decodeA2ATask(event)
+	if task == nil {
+		return nil
+	}
+	snap := &a2aTaskSnapshot{task: task}
+	if v, ok := event.Metadata[a2aTaskSeqKey].(*dpTypes.MetadataValueMemberStringValue); ok {
+		snap.seq, _ = strconv.ParseInt(v.Value, 10, 64)
+	}
+	return snap
+}
+
+// eventTextWithPrefix returns the text after prefix in the first text
+// payload of event starting with prefix, or "".
+func eventTextWithPrefix(event *dpTypes.Event, prefix string) string {
+	for _, p := range event.Payload {
+		conv, ok := p.(*dpTypes.PayloadTypeMemberConversational)
+		if !ok {
+			continue
+		}
+		if text, ok := conv.Value.Content.(*dpTypes.ContentMemberText); ok && strings.HasPrefix(text.Value, prefix) {
+			return strings.TrimPrefix(text.Value, prefix)
+		}
+	}
+	return ""
+}
+
+// newerA2ATask reports whether snapshot a supersedes b. A snapshot with a
+// sequence number was saved after any without one. Snapshots without
+// either are ordered by status timestamp, and those without a timestamp
+// never supersede one with.
+func newerA2ATask(a, b *a2aTaskSnapshot) bool {
+	if b == nil {
+		return true
+	}
+	if a.seq != 0 || b.seq != 0 {
+		return a.seq > b.seq
+	}
+	at, bt := a.task.Status.Timestamp, b.task.Status.Timestamp
+	if at == nil || bt == nil {
+		return bt == nil
+	}
+	return !at.Before(*bt)
+}
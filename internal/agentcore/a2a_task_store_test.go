@@ -0,0 +1,203 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+func a2aTaskEvent(t *testing.T, task *a2a.Task) dpTypes.Event {
+	t.Helper()
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dpTypes.Event{Payload: []dpTypes.PayloadType{
+		convPayload(dpTypes.RoleOther, a2aTaskPrefix+string(data)),
+	}}
+}
+
+func TestA2ATaskSnapshotStore_Save(t *testing.T) {
+	mock := &mockDataPlaneClient{}
+	store := NewA2ATaskSnapshotStore("mem-1", mock)
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	task := &a2a.Task{
+		ID:        "task-1",
+		ContextID: "ctx-1",
+		Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted, Timestamp: &ts},
+	}
+	if err := store.Save(context.Background(), task); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if len(mock.createCalls) != 1 {
+		t.Fatalf("CreateEvent calls = %d, want 1", len(mock.createCalls))
+	}
+	in := mock.createCalls[0]
+	if aws.ToString(in.ActorId) != A2ATaskActorID || aws.ToString(in.SessionId) != "task-1" {
+		t.Errorf("actor/session = %q/%q", aws.ToString(in.ActorId), aws.ToString(in.SessionId))
+	}
+	if !aws.ToTime(in.EventTimestamp).Equal(ts) {
+		t.Errorf("EventTimestamp = %v, want %v", aws.ToTime(in.EventTimestamp), ts)
+	}
+	got := decodeA2ATask(&dpTypes.Event{Payload: in.Payload})
+	if got == nil || got.ContextID != "ctx-1" || got.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("decoded snapshot = %+v", got)
+	}
+}
+
+func TestA2ATaskSnapshotStore_SaveError(t *testing.T) {
+	mock := &mockDataPlaneClient{
+		createEventFn: func(
+			_ context.Context, _ *bedrockagentcore.CreateEventInput, _ ...func(*bedrockagentcore.Options),
+		) (*bedrockagentcore.CreateEventOutput, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	store := NewA2ATaskSnapshotStore("mem-1", mock)
+	if err := store.Save(context.Background(), &a2a.Task{ID: "task-1"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestA2ATaskSnapshotStore_LoadLatest(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Minute)
+	pages := [][]dpTypes.Event{
+		{
+			a2aTaskEvent(t, &a2a.Task{ID: "task-1", Status: a2a.TaskStatus{
+				State: a2a.TaskStateCompleted, Timestamp: &newer,
+			}}),
+			{Payload: []dpTypes.PayloadType{convPayload(dpTypes.RoleOther, "unrelated")}},
+		},
+		{
+			a2aTaskEvent(t, &a2a.Task{ID: "task-1", Status: a2a.TaskStatus{
+				State: a2a.TaskStateWorking, Timestamp: &older,
+			}}),
+		},
+	}
+	calls := 0
+	mock := &mockDataPlaneClient{
+		listEventsFn: func(
+			_ context.Context, in *bedrockagentcore.ListEventsInput, _ ...func(*bedrockagentcore.Options),
+		) (*bedrockagentcore.ListEventsOutput, error) {
+			if aws.ToString(in.SessionId) != "task-1" || aws.ToString(in.ActorId) != A2ATaskActorID {
+				t.Errorf("unexpected ListEvents input %+v", in)
+			}
+			out := &bedrockagentcore.ListEventsOutput{Events: pages[calls]}
+			calls++
+			if calls < len(pages) {
+				out.NextToken = aws.String("next")
+			}
+			return out, nil
+		},
+	}
+	store := NewA2ATaskSnapshotStore("mem-1", mock)
+	task, err := store.Load(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if calls != len(pages) {
+		t.Errorf("ListEvents calls = %d, want %d", calls, len(pages))
+	}
+	if task == nil || task.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("Load = %+v, want completed snapshot", task)
+	}
+}
+
+func TestA2ATaskSnapshotStore_LoadHighestSeq(t *testing.T) {
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockDataPlaneClient{}
+	store := NewA2ATaskSnapshotStore("mem-1", mock)
+	for _, artifacts := range [][]a2a.Artifact{nil, {{ArtifactID: "a-1"}}} {
+		task := &a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Timestamp: &ts},
+			Artifacts: artifacts}
+		if err := store.Save(context.Background(), task); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	// List the snapshots newest first; equal timestamps must not make the
+	// older one win.
+	var events []dpTypes.Event
+	for i := len(mock.createCalls) - 1; i >= 0; i-- {
+		in := mock.createCalls[i]
+		if kind, ok := in.Metadata[a2aTaskKindKey].(*dpTypes.MetadataValueMemberStringValue); !ok ||
+			kind.Value != a2aTaskKind {
+			t.Errorf("metadata = %+v, want kind %q", in.Metadata, a2aTaskKind)
+		}
+		events = append(events, dpTypes.Event{Payload: in.Payload, Metadata: in.Metadata})
+	}
+	mock.listEventsFn = func(
+		context.Context, *bedrockagentcore.ListEventsInput, ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.ListEventsOutput, error) {
+		return &bedrockagentcore.ListEventsOutput{Events: events}, nil
+	}
+	task, err := store.Load(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if task == nil || len(task.Artifacts) != 1 {
+		t.Errorf("Load = %+v, want the snapshot saved last", task)
+	}
+}
+
+func TestA2ATaskSnapshotStore_LoadMissing(t *testing.T) {
+	store := NewA2ATaskSnapshotStore("mem-1", &mockDataPlaneClient{})
+	task, err := store.Load(context.Background(), "task-1")
+	if err != nil || task != nil {
+		t.Errorf("Load = %+v, %v; want nil, nil", task, err)
+	}
+}
+
+func TestA2ATaskSnapshotStore_LoadError(t *testing.T) {
+	mock := &mockDataPlaneClient{
+		listEventsFn: func(
+			_ context.Context, _ *bedrockagentcore.ListEventsInput, _ ...func(*bedrockagentcore.Options),
+		) (*bedrockagentcore.ListEventsOutput, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	store := NewA2ATaskSnapshotStore("mem-1", mock)
+	if _, err := store.Load(context.Background(), "task-1"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestA2ATaskSnapshotStore_ContextIndex(t *testing.T) {
+	var events []dpTypes.Event
+	mock := &mockDataPlaneClient{}
+	mock.createEventFn = func(
+		_ context.Context, in *bedrockagentcore.CreateEventInput, _ ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.CreateEventOutput, error) {
+		if aws.ToString(in.SessionId) != "context-ctx-1" {
+			t.Errorf("SessionId = %q, want context-ctx-1", aws.ToString(in.SessionId))
+		}
+		events = append(events, dpTypes.Event{Payload: in.Payload})
+		return &bedrockagentcore.CreateEventOutput{}, nil
+	}
+	mock.listEventsFn = func(
+		_ context.Context, _ *bedrockagentcore.ListEventsInput, _ ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.ListEventsOutput, error) {
+		return &bedrockagentcore.ListEventsOutput{Events: events}, nil
+	}
+	store := NewA2ATaskSnapshotStore("mem-1", mock)
+	ctx := context.Background()
+	for _, id := range []string{"task-1", "task-2", "task-1"} {
+		if err := store.AddToContext(ctx, "ctx-1", id); err != nil {
+			t.Fatalf("AddToContext: %v", err)
+		}
+	}
+	ids, err := store.ContextTaskIDs(ctx, "ctx-1")
+	if err != nil {
+		t.Fatalf("ContextTaskIDs: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "task-1" || ids[1] != "task-2" {
+		t.Errorf("ContextTaskIDs = %v, want [task-1 task-2]", ids)
+	}
+}
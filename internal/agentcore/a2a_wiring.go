@@ -0,0 +1,150 @@
+package agentcore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// a2aWiringSuffix ends the name of each member's a2a_endpoint resource.
+const a2aWiringSuffix = "_a2a"
+
+// metaA2AURL is the metadata key of an a2a_endpoint resource's A2A URL. Its
+// runtime's ARN is recorded under metaRuntimeARN.
+const metaA2AURL = "a2a_url"
+
+// agentCardPath is where the runtime's A2A server serves its agent card,
+// relative to its A2A URL.
+const agentCardPath = ".well-known/agent.json"
+
+// runtimeSessionHeader carries the runtime session ID of a data-plane
+// request.
+const runtimeSessionHeader = "X-Amzn-Bedrock-AgentCore-Runtime-Session-Id"
+
+// probeSessionPrefix starts the session IDs of agent card probes, so they
+// are told apart from user sessions in the runtime's logs.
+const probeSessionPrefix = "promptarena-a2a-probe-"
+
+// maxAgentCardBytes caps the agent card body a probe reads.
+const maxAgentCardBytes = 1 << 20
+
+// a2aRuntimeURL returns the data-plane URL the A2A server of the runtime
+// runtimeARN is reached at, under the bedrock_agentcore endpoint override
+// when one is set.
+func (c *Config) a2aRuntimeURL(runtimeARN string) string {
+	base := "https://bedrock-agentcore." + c.Region + "." + partitionByID(regionPartition(c.Region)).dnsSuffix
+	if u := c.Endpoints[EndpointBedrockAgentCore]; u != "" {
+		base = strings.TrimSuffix(u, "/")
+	}
+	return base + "/runtimes/" + url.QueryEscape(runtimeARN) + "/invocations/"
+}
+
+// deployedRuntimeARNs maps the agent names of the runtimes in resources
+// that Apply did not fail on to their ARNs.
+func deployedRuntimeARNs(resources []ResourceState) map[string]string {
+	arns := make(map[string]string)
+	for _, r := range resources {
+		if r.Type == ResTypeAgentRuntime && r.ARN != "" && r.Status != ResStatusFailed {
+			arns[r.Name] = r.ARN
+		}
+	}
+	return arns
+}
+
+// annotateA2AWiring records on each wired member's a2a_endpoint resource
+// the runtime it wires and the A2A URL members reach it at.
+func annotateA2AWiring(resources []ResourceState, cfg *Config) {
+	for i := range resources {
+		r := &resources[i]
+		runtimeARN := cfg.RuntimeARNs[strings.TrimSuffix(r.Name, a2aWiringSuffix)]
+		if r.Type != ResTypeA2AEndpoint || r.Status == ResStatusFailed || runtimeARN == "" {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string)
+		}
+		r.Metadata[metaRuntimeARN] = runtimeARN
+		r.Metadata[metaA2AURL] = cfg.a2aRuntimeURL(runtimeARN)
+	}
+}
+
+// CreateA2AWiring checks that the member runtime the wiring name stands
+// for is ready and starts its A2A server, and returns the runtime's ARN.
+// Its A2A URL is recorded by annotateA2AWiring.
+func (c *realAWSClient) CreateA2AWiring(ctx context.Context, name string, cfg *Config) (string, error) {
+	agent := strings.TrimSuffix(name, a2aWiringSuffix)
+	runtimeARN := cfg.RuntimeARNs[agent]
+	if runtimeARN == "" {
+		return "", fmt.Errorf("CreateA2AWiring %q: agent %q has no deployed runtime", name, agent)
+	}
+	out, err := c.client.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
+		AgentRuntimeId: aws.String(extractResourceID(runtimeARN, "runtime")),
+	})
+	if err != nil {
+		return "", fmt.Errorf("CreateA2AWiring %q: GetAgentRuntime: %w", name, err)
+	}
+	if out.Status != types.AgentRuntimeStatusReady {
+		return "", fmt.Errorf("CreateA2AWiring %q: runtime %s is %s, not READY", name, runtimeARN, out.Status)
+	}
+	if protocol := out.EnvironmentVariables[EnvProtocol]; protocol == ProtocolHTTP {
+		return "", fmt.Errorf("CreateA2AWiring %q: runtime %s does not start its A2A server (protocol %q)",
+			name, runtimeARN, protocol)
+	}
+	logf("agentcore: A2A wiring %q reaches runtime %s at %s", name, runtimeARN, cfg.a2aRuntimeURL(runtimeARN))
+	return runtimeARN, nil
+}
+
+// checkA2AWiring fetches the agent card from the wiring's A2A URL, the way
+// other members discover the agent. A card that cannot be fetched makes
+// the wiring unhealthy, with the reason as detail.
+func (c *realAWSClient) checkA2AWiring(ctx context.Context, res ResourceState) (string, string, error) {
+	a2aURL := res.Metadata[metaA2AURL]
+	if a2aURL == "" {
+		return StatusUnhealthy, "no A2A URL recorded; apply again to record it", nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a2aURL+agentCardPath, http.NoBody)
+	if err != nil {
+		return StatusUnhealthy, "", fmt.Errorf("agent card request for %q: %w", res.Name, err)
+	}
+	req.Header.Set(runtimeSessionHeader, probeSessionID())
+	resp, err := c.dataPlane.do(ctx, req, nil, "bedrock-agentcore", c.cfg.Region)
+	if err != nil {
+		return StatusUnhealthy, fmt.Sprintf("agent card unreachable: %v", err), nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAgentCardBytes))
+	if err != nil {
+		return StatusUnhealthy, fmt.Sprintf("agent card unreadable: %v", err), nil
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return StatusMissing, fmt.Sprintf("agent card not found at %s", a2aURL+agentCardPath), nil
+	case resp.StatusCode != http.StatusOK:
+		return StatusUnhealthy, fmt.Sprintf("agent card fetch returned HTTP %d", resp.StatusCode), nil
+	}
+	var card struct {
+		Name string `json:"name"`
+	}
+	if err = json.Unmarshal(body, &card); err != nil || card.Name == "" {
+		return StatusUnhealthy, "agent card response is not an agent card", nil
+	}
+	return StatusHealthy, "", nil
+}
+
+// probeSessionID returns a fresh runtime session ID for an agent card
+// probe. AgentCore requires at least 33 characters.
+func probeSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return probeSessionPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,126 @@
+package agentcore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const testA2ARuntimeARN = "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/worker-abc"
+
+func TestA2ARuntimeURL(t *testing.T) {
+	cfg := &Config{Region: "us-west-2"}
+	want := "https://bedrock-agentcore.us-west-2.amazonaws.com/runtimes/" +
+		"arn%3Aaws%3Abedrock-agentcore%3Aus-west-2%3A123456789012%3Aruntime%2Fworker-abc/invocations/"
+	if got := cfg.a2aRuntimeURL(testA2ARuntimeARN); got != want {
+		t.Errorf("a2aRuntimeURL = %s, want %s", got, want)
+	}
+	cfg.Endpoints = map[string]string{EndpointBedrockAgentCore: "https://vpce.example.com/"}
+	if got := cfg.a2aRuntimeURL(testA2ARuntimeARN); !strings.HasPrefix(got, "https://vpce.example.com/runtimes/") {
+		t.Errorf("a2aRuntimeURL with endpoint override = %s", got)
+	}
+}
+
+func TestCreateA2AWiring(t *testing.T) {
+	cfg := &Config{Region: "us-west-2", RuntimeARNs: map[string]string{"worker": testA2ARuntimeARN}}
+	for _, tt := range []struct {
+		name, body, wantErr string
+	}{
+		{"a2a", `{"status":"READY","environmentVariables":{"PROMPTPACK_PROTOCOL":"a2a"}}`, ""},
+		{"both", `{"status":"READY","environmentVariables":{"PROMPTPACK_PROTOCOL":"both"}}`, ""},
+		{"http only", `{"status":"READY","environmentVariables":{"PROMPTPACK_PROTOCOL":"http"}}`,
+			"does not start its A2A server"},
+		{"not ready", `{"status":"UPDATING"}`, "not READY"},
+	} {
+		c, _, _ := newStubbedRealClient(1, tt.body)
+		arn, err := c.CreateA2AWiring(context.Background(), "worker_a2a", cfg)
+		if tt.wantErr == "" && (err != nil || arn != testA2ARuntimeARN) {
+			t.Errorf("%s: CreateA2AWiring = %q, %v", tt.name, arn, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: CreateA2AWiring error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	c, _, stub := newStubbedRealClient(1)
+	if _, err := c.CreateA2AWiring(context.Background(), "planner_a2a", cfg); err == nil ||
+		!strings.Contains(err.Error(), "no deployed runtime") {
+		t.Errorf("CreateA2AWiring without a runtime = %v", err)
+	}
+	if stub.calls != 0 {
+		t.Errorf("made %d calls without a runtime, want none", stub.calls)
+	}
+}
+
+func TestAnnotateA2AWiring(t *testing.T) {
+	cfg := &Config{Region: "us-west-2"}
+	cfg.RuntimeARNs = deployedRuntimeARNs([]ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "worker", ARN: testA2ARuntimeARN, Status: ResStatusCreated},
+		{Type: ResTypeAgentRuntime, Name: "broken", ARN: "arn:broken", Status: ResStatusFailed},
+	})
+	wiring := []ResourceState{
+		{Type: ResTypeA2AEndpoint, Name: "worker_a2a", ARN: testA2ARuntimeARN, Status: ResStatusCreated},
+		{Type: ResTypeA2AEndpoint, Name: "broken_a2a", Status: ResStatusFailed},
+	}
+	annotateA2AWiring(wiring, cfg)
+
+	if got := wiring[0].Metadata; got[metaRuntimeARN] != testA2ARuntimeARN ||
+		got[metaA2AURL] != cfg.a2aRuntimeURL(testA2ARuntimeARN) {
+		t.Errorf("worker metadata = %v", got)
+	}
+	if wiring[1].Metadata != nil {
+		t.Errorf("failed wiring metadata = %v, want none", wiring[1].Metadata)
+	}
+}
+
+func TestCheckA2AWiring(t *testing.T) {
+	var sessionID, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID, auth = r.Header.Get(runtimeSessionHeader), r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/runtimes/worker/invocations/.well-known/agent.json":
+			_, _ = w.Write([]byte(`{"name":"worker","url":"http://localhost:9999"}`))
+		case "/runtimes/denied/invocations/.well-known/agent.json":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := &realAWSClient{
+		cfg: &Config{Region: "us-west-2"},
+		dataPlane: newSigV4Client(aws.Config{Credentials: aws.CredentialsProviderFunc(
+			func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+			})}),
+	}
+	wiring := func(runtime string) ResourceState {
+		return ResourceState{Type: ResTypeA2AEndpoint, Name: runtime + "_a2a",
+			Metadata: map[string]string{metaA2AURL: srv.URL + "/runtimes/" + runtime + "/invocations/"}}
+	}
+
+	for _, tt := range []struct {
+		res                    ResourceState
+		wantStatus, wantDetail string
+	}{
+		{wiring("worker"), StatusHealthy, ""},
+		{wiring("denied"), StatusUnhealthy, "HTTP 403"},
+		{wiring("gone"), StatusMissing, "agent card not found"},
+		{ResourceState{Type: ResTypeA2AEndpoint, Name: "old_a2a"}, StatusUnhealthy, "apply again"},
+	} {
+		status, detail, err := c.CheckResourceDetail(context.Background(), tt.res)
+		if err != nil || status != tt.wantStatus || !strings.Contains(detail, tt.wantDetail) {
+			t.Errorf("%s: CheckResourceDetail = %s, %q, %v", tt.res.Name, status, detail, err)
+		}
+	}
+	if len(sessionID) < 33 || !strings.HasPrefix(sessionID, probeSessionPrefix) {
+		t.Errorf("probe session ID = %q, want at least 33 characters", sessionID)
+	}
+	if !strings.Contains(auth, "/us-west-2/bedrock-agentcore/aws4_request") {
+		t.Errorf("Authorization = %q, want a SigV4 signature for bedrock-agentcore", auth)
+	}
+}
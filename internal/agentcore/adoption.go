@@ -0,0 +1,199 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+)
+
+// metaReconciledTags is the metadata key recording the tags Apply added to
+// or changed on an adopted resource, as sorted key=value pairs.
+const metaReconciledTags = "reconciled_tags"
+
+// adoptionTracker is implemented by clients that remember which existing
+// resources Apply adopted instead of creating.
+type adoptionTracker interface {
+	wasAdopted(arn string) bool
+}
+
+// recordAdopted remembers that the resource at arn was adopted.
+func (c *realAWSClient) recordAdopted(arn string) {
+	if c.adopted == nil {
+		c.adopted = make(map[string]bool)
+	}
+	c.adopted[arn] = true
+}
+
+// wasAdopted implements adoptionTracker.
+func (c *realAWSClient) wasAdopted(arn string) bool {
+	return c.adopted[arn]
+}
+
+// tagReconciler is implemented by clients that merge the pack's tags onto
+// the resources they adopt.
+type tagReconciler interface {
+	reconciledTags(arn string) map[string]string
+}
+
+// reconcileAdoptedTags merges the tags Apply would have created the
+// adopted resource res with onto it, given its current tags, and records
+// the tags it added or changed. Tags only the resource has are kept.
+func (c *realAWSClient) reconcileAdoptedTags(ctx context.Context, res ResourceState, current map[string]string) error {
+	if untaggedResourceTypes[res.Type] {
+		return nil
+	}
+	delta := tagDelta(current, adoptionTags(res.Type, res.Name, c.cfg))
+	if len(delta) == 0 {
+		return nil
+	}
+	var err error
+	if res.Type == ResTypeInferenceProfile {
+		_, err = c.bedrockClient.TagResource(ctx, &bedrock.TagResourceInput{
+			ResourceARN: aws.String(res.ARN), Tags: bedrockTags(delta),
+		})
+	} else {
+		_, err = c.client.TagResource(ctx, &bedrockagentcorecontrol.TagResourceInput{
+			ResourceArn: aws.String(res.ARN), Tags: delta,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("reconcile tags of adopted %s %q: %w", res.Type, res.Name, err)
+	}
+	logf("agentcore: tagged adopted %s %q with %s", res.Type, res.Name, formatTagDelta(delta))
+	if c.reconciled == nil {
+		c.reconciled = make(map[string]map[string]string)
+	}
+	c.reconciled[res.ARN] = delta
+	return nil
+}
+
+// reconciledTags implements tagReconciler.
+func (c *realAWSClient) reconciledTags(arn string) map[string]string {
+	return c.reconciled[arn]
+}
+
+// adoptionTags returns the tags Apply creates a resource of resType named
+// name with. Runtimes also carry their agent's name.
+func adoptionTags(resType, name string, cfg *Config) map[string]string {
+	if resType == ResTypeAgentRuntime {
+		return tagsWithAgent(cfg.ResourceTags, name)
+	}
+	return cfg.ResourceTags
+}
+
+// tagDelta returns the tags of want that current lacks or sets to another
+// value.
+func tagDelta(current, want map[string]string) map[string]string {
+	var delta map[string]string
+	for k, v := range want {
+		if got, ok := current[k]; ok && got == v {
+			continue
+		}
+		if delta == nil {
+			delta = make(map[string]string)
+		}
+		delta[k] = v
+	}
+	return delta
+}
+
+// formatTagDelta renders delta as sorted, comma-separated key=value pairs.
+func formatTagDelta(delta map[string]string) string {
+	pairs := make([]string, 0, len(delta))
+	for _, k := range sortedKeys(delta) {
+		pairs = append(pairs, k+"="+delta[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// markOwnership clears Owned on every resource Apply adopted, and records
+// the tags it reconciled on those adopted in this Apply.
+func markOwnership(resources []ResourceState, client awsClient, priorMap map[string]ResourceState) {
+	tracker, _ := client.(adoptionTracker)
+	reconciler, _ := client.(tagReconciler)
+	for i := range resources {
+		if !isAdopted(resources[i], tracker, priorMap) {
+			continue
+		}
+		owned := false
+		resources[i].Owned = &owned
+		if reconciler == nil {
+			continue
+		}
+		if delta := reconciler.reconciledTags(resources[i].ARN); len(delta) > 0 {
+			resources[i].Metadata = withMetadata(resources[i].Metadata, metaReconciledTags, formatTagDelta(delta))
+		}
+	}
+}
+
+// withMetadata returns a copy of metadata with key set to value.
+func withMetadata(metadata map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// isAdopted reports whether r was adopted, either in this Apply or in an
+// earlier one whose state recorded the same ARN. A Cedar policy resource
+// counts as adopted when its policy engine was, since Destroy deletes the
+// engine.
+func isAdopted(r ResourceState, tracker adoptionTracker, priorMap map[string]ResourceState) bool {
+	if r.ARN == "" {
+		return false
+	}
+	if prior, ok := priorMap[resourceKey(r.Type, r.Name)]; ok && !prior.isOwned() && prior.ARN == r.ARN {
+		return true
+	}
+	if tracker == nil {
+		return false
+	}
+	return tracker.wasAdopted(r.ARN) || tracker.wasAdopted(r.Metadata["policy_engine_arn"])
+}
+
+// reportAdopted reports each resource this Apply adopted instead of
+// creating.
+func reportAdopted(reporter *adaptersdk.ProgressReporter, resources []ResourceState, client awsClient) {
+	tracker, _ := client.(adoptionTracker)
+	for _, r := range resources {
+		if !r.isOwned() && isAdopted(r, tracker, nil) {
+			_ = reporter.Progress(fmt.Sprintf("Adopted existing %s %q", r.Type, r.Name), progressNoPercent)
+		}
+	}
+}
+
+// splitAdopted separates resources Destroy may delete from adopted ones it
+// must leave in place. With includeAdopted every resource is deletable.
+func splitAdopted(resources []ResourceState, includeAdopted bool) (deletable, adopted []ResourceState) {
+	for _, r := range resources {
+		if r.isOwned() || includeAdopted {
+			deletable = append(deletable, r)
+		} else {
+			adopted = append(adopted, r)
+		}
+	}
+	return deletable, adopted
+}
+
+// emitAdoptedSkips reports each adopted resource Destroy left in place.
+func emitAdoptedSkips(callback deploy.DestroyCallback, adopted []ResourceState) {
+	for _, res := range adopted {
+		_ = callback(&deploy.DestroyEvent{
+			Type:    ErrCategoryResource,
+			Message: fmt.Sprintf("Skipped adopted %s %q", res.Type, res.Name),
+			Resource: &deploy.ResourceResult{
+				Type: res.Type, Name: res.Name,
+				Action: deploy.ActionNoChange, Status: ResStatusSkipped,
+				Detail: retainReasonAdopted,
+			},
+		})
+	}
+}
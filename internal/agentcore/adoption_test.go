@@ -0,0 +1,179 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// recordingDestroyer records the names of the resources it deletes.
+type recordingDestroyer struct {
+	deleted []string
+}
+
+func (d *recordingDestroyer) DeleteResource(_ context.Context, res ResourceState) error {
+	d.deleted = append(d.deleted, res.Name)
+	return nil
+}
+
+// fakeAdoptionClient is an awsClient that reports a fixed set of adopted ARNs.
+type fakeAdoptionClient struct {
+	awsClient
+	adopted map[string]bool
+}
+
+func (c *fakeAdoptionClient) wasAdopted(arn string) bool { return c.adopted[arn] }
+
+func adoptedState() *AdapterState {
+	notOwned := false
+	return &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeMemory, Name: "mem", ARN: "arn:mem", Owned: &notOwned},
+		{Type: ResTypeAgentRuntime, Name: "rt", ARN: "arn:rt"},
+	}}
+}
+
+func destroyWith(t *testing.T, d resourceDestroyer, deployConfig string) []*deploy.DestroyEvent {
+	t.Helper()
+	p := &Provider{
+		destroyerFunc: func(_ context.Context, _ *Config) (resourceDestroyer, error) { return d, nil },
+	}
+	var events []*deploy.DestroyEvent
+	err := p.Destroy(context.Background(), &deploy.DestroyRequest{
+		DeployConfig: deployConfig,
+		PriorState:   mustJSON(t, adoptedState()),
+	}, func(e *deploy.DestroyEvent) error {
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	return events
+}
+
+func TestDestroy_SkipsAdoptedResources(t *testing.T) {
+	d := &recordingDestroyer{}
+	events := destroyWith(t, d, validDestroyConfig())
+
+	if len(d.deleted) != 1 || d.deleted[0] != "rt" {
+		t.Errorf("deleted = %v, want [rt]", d.deleted)
+	}
+	var skipped *deploy.ResourceResult
+	for _, e := range events {
+		if e.Resource != nil && e.Resource.Status == ResStatusSkipped {
+			skipped = e.Resource
+		}
+	}
+	if skipped == nil || skipped.Name != "mem" || skipped.Action != deploy.ActionNoChange {
+		t.Errorf("skip event = %+v, want mem NO_CHANGE", skipped)
+	}
+}
+
+func TestDestroy_IncludeAdopted(t *testing.T) {
+	d := &recordingDestroyer{}
+	cfg := strings.TrimSuffix(validDestroyConfig(), "}") + `,"include_adopted":true}`
+	destroyWith(t, d, cfg)
+
+	if len(d.deleted) != 2 {
+		t.Errorf("deleted = %v, want both resources", d.deleted)
+	}
+}
+
+func TestMarkOwnership(t *testing.T) {
+	notOwned := false
+	prior := map[string]ResourceState{
+		resourceKey(ResTypeAgentRuntime, "kept"):     {ARN: "arn:kept", Owned: &notOwned},
+		resourceKey(ResTypeAgentRuntime, "replaced"): {ARN: "arn:old", Owned: &notOwned},
+	}
+	resources := []ResourceState{
+		{Type: ResTypeMemory, Name: "mem", ARN: "arn:mem"},
+		{Type: ResTypeCedarPolicy, Name: "main", ARN: "arn:policy",
+			Metadata: map[string]string{"policy_engine_arn": "arn:engine"}},
+		{Type: ResTypeAgentRuntime, Name: "kept", ARN: "arn:kept"},
+		{Type: ResTypeAgentRuntime, Name: "replaced", ARN: "arn:new"},
+		{Type: ResTypeEvaluator, Name: "eval", ARN: "arn:eval"},
+		{Type: ResTypeEvaluator, Name: "failed"},
+	}
+	client := &fakeAdoptionClient{adopted: map[string]bool{"arn:mem": true, "arn:engine": true}}
+
+	markOwnership(resources, client, prior)
+
+	want := map[string]bool{"mem": false, "main": false, "kept": false, "replaced": true, "eval": true, "failed": true}
+	for _, r := range resources {
+		if r.isOwned() != want[r.Name] {
+			t.Errorf("%s owned = %v, want %v", r.Name, r.isOwned(), want[r.Name])
+		}
+	}
+	if resources[4].Owned != nil {
+		t.Errorf("owned resource should omit the flag, got %v", *resources[4].Owned)
+	}
+}
+
+func TestResolveConflict_RecordsAdoption(t *testing.T) {
+	c := &realAWSClient{cfg: &Config{}}
+	res := ResourceState{Type: ResTypeCedarPolicy, Name: "main_policy_engine", ARN: "arn:engine"}
+
+	action, err := c.resolveConflict(context.Background(), res)
+	if err != nil || action != conflictActionAdopt {
+		t.Fatalf("resolveConflict = %v, %v; want adopt", action, err)
+	}
+	if !c.wasAdopted("arn:engine") || c.wasAdopted("arn:other") {
+		t.Errorf("adopted = %v, want only arn:engine", c.adopted)
+	}
+}
+
+// fakeReconcilingClient is a fakeAdoptionClient that also reports the tags
+// it reconciled.
+type fakeReconcilingClient struct {
+	fakeAdoptionClient
+	reconciled map[string]map[string]string
+}
+
+func (c *fakeReconcilingClient) reconciledTags(arn string) map[string]string {
+	return c.reconciled[arn]
+}
+
+func TestMarkOwnership_RecordsReconciledTags(t *testing.T) {
+	resources := []ResourceState{
+		{Type: ResTypeToolGateway, Name: "gw", ARN: "arn:gw", Metadata: map[string]string{"gateway_id": "gw-1"}},
+		{Type: ResTypeAgentRuntime, Name: "rt", ARN: "arn:rt"},
+	}
+	client := &fakeReconcilingClient{
+		fakeAdoptionClient: fakeAdoptionClient{adopted: map[string]bool{"arn:gw": true, "arn:rt": true}},
+		reconciled: map[string]map[string]string{
+			"arn:gw": tagDelta(map[string]string{"b": "old", "c": "3"}, map[string]string{"a": "1", "b": "2", "c": "3"}),
+		},
+	}
+
+	markOwnership(resources, client, nil)
+
+	if got := resources[0].Metadata[metaReconciledTags]; got != "a=1,b=2" {
+		t.Errorf("gateway %s = %q, want a=1,b=2", metaReconciledTags, got)
+	}
+	if resources[0].Metadata["gateway_id"] != "gw-1" {
+		t.Error("gateway metadata lost")
+	}
+	if _, ok := resources[1].Metadata[metaReconciledTags]; ok {
+		t.Error("runtime without a tag delta has reconciled tags")
+	}
+}
+
+func TestResolveConflict_ReconcilesTags(t *testing.T) {
+	c, _, stub := newStubbedRealClient(1,
+		`{"tags":{"promptpack:pack-id":"pack","promptpack:version":"v1","team":"ml"}}`, `{}`)
+	c.cfg.ResourceTags = map[string]string{TagKeyPackID: "pack", TagKeyVersion: "v2"}
+	res := ResourceState{Type: ResTypeToolGateway, Name: "gw", ARN: "arn:gw"}
+
+	action, err := c.resolveConflict(context.Background(), res)
+	if err != nil || action != conflictActionAdopt {
+		t.Fatalf("resolveConflict = %v, %v; want adopt", action, err)
+	}
+	if stub.calls != 2 {
+		t.Errorf("calls = %d, want a tag read and a TagResource", stub.calls)
+	}
+	if got := c.reconciledTags("arn:gw"); len(got) != 1 || got[TagKeyVersion] != "v2" {
+		t.Errorf("reconciled = %v, want only the new version", got)
+	}
+}
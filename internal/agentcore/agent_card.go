@@ -0,0 +1,99 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// EnvAgentCard carries the public agent card overrides for one runtime as
+// JSON-encoded AgentCardConfig.
+const EnvAgentCard = "PROMPTPACK_AGENT_CARD"
+
+// agentCardDefaultKey is the agent_cards key whose values apply to every
+// agent unless that agent's own entry overrides them.
+const agentCardDefaultKey = "default"
+
+// AgentCardConfig overrides what a runtime publishes on its A2A agent card.
+// Empty fields leave the value derived from the pack unchanged.
+type AgentCardConfig struct {
+	DisplayName          string `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	Description          string `json:"description,omitempty" yaml:"description,omitempty"`
+	IconURL              string `json:"icon_url,omitempty" yaml:"icon_url,omitempty"`
+	DocumentationURL     string `json:"documentation_url,omitempty" yaml:"documentation_url,omitempty"`
+	ProviderOrganization string `json:"provider_organization,omitempty" yaml:"provider_organization,omitempty"`
+	ProviderURL          string `json:"provider_url,omitempty" yaml:"provider_url,omitempty"`
+}
+
+// merge returns c with every empty field filled from base.
+func (c AgentCardConfig) merge(base AgentCardConfig) AgentCardConfig {
+	fill := func(v *string, fallback string) {
+		if *v == "" {
+			*v = fallback
+		}
+	}
+	fill(&c.DisplayName, base.DisplayName)
+	fill(&c.Description, base.Description)
+	fill(&c.IconURL, base.IconURL)
+	fill(&c.DocumentationURL, base.DocumentationURL)
+	fill(&c.ProviderOrganization, base.ProviderOrganization)
+	fill(&c.ProviderURL, base.ProviderURL)
+	return c
+}
+
+// agentCardFor returns the card overrides for agentName, layering its own
+// agent_cards entry over the "default" entry. It returns nil when neither
+// sets anything.
+func agentCardFor(cards map[string]*AgentCardConfig, agentName string) *AgentCardConfig {
+	var merged AgentCardConfig
+	if c := cards[agentName]; c != nil {
+		merged = *c
+	}
+	if d := cards[agentCardDefaultKey]; d != nil {
+		merged = merged.merge(*d)
+	}
+	if merged == (AgentCardConfig{}) {
+		return nil
+	}
+	return &merged
+}
+
+// agentCardEnvValue encodes the card overrides for agentName for the
+// PROMPTPACK_AGENT_CARD env var, or returns "" when there are none.
+func agentCardEnvValue(cards map[string]*AgentCardConfig, agentName string) string {
+	card := agentCardFor(cards, agentName)
+	if card == nil {
+		return ""
+	}
+	b, _ := json.Marshal(card)
+	return string(b)
+}
+
+// validateAgentCards checks that every URL in agent_cards is an absolute
+// http(s) URL.
+func validateAgentCards(cards map[string]*AgentCardConfig) []string {
+	var errs []string
+	for name, c := range cards {
+		if c == nil {
+			continue
+		}
+		for field, v := range map[string]string{
+			"icon_url":          c.IconURL,
+			"documentation_url": c.DocumentationURL,
+			"provider_url":      c.ProviderURL,
+		} {
+			if v != "" && !isHTTPURL(v) {
+				errs = append(errs, fmt.Sprintf("agent_cards.%s.%s %q must be an absolute http(s) URL", name, field, v))
+			}
+		}
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// isHTTPURL reports whether s parses as an absolute http or https URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
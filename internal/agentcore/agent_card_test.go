@@ -0,0 +1,64 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAgentCardFor_DefaultAndAgentMerge(t *testing.T) {
+	cards := map[string]*AgentCardConfig{
+		agentCardDefaultKey: {ProviderOrganization: "Acme", IconURL: "https://acme.example/icon.png"},
+		"support":           {DisplayName: "Acme Support", IconURL: "https://acme.example/support.png"},
+	}
+
+	got := agentCardFor(cards, "support")
+	want := AgentCardConfig{
+		DisplayName:          "Acme Support",
+		IconURL:              "https://acme.example/support.png",
+		ProviderOrganization: "Acme",
+	}
+	if got == nil || *got != want {
+		t.Errorf("support card = %+v, want %+v", got, want)
+	}
+
+	if other := agentCardFor(cards, "billing"); other == nil || other.ProviderOrganization != "Acme" {
+		t.Errorf("billing card = %+v, want default entry", other)
+	}
+	if none := agentCardFor(nil, "support"); none != nil {
+		t.Errorf("card without config = %+v, want nil", none)
+	}
+}
+
+func TestRuntimeEnvVarsForAgent_AgentCard(t *testing.T) {
+	cfg := &Config{
+		RuntimeEnvVars: map[string]string{"AWS_REGION": "us-west-2"},
+		AgentCards:     map[string]*AgentCardConfig{"support": {DisplayName: "Acme Support"}},
+	}
+
+	env := runtimeEnvVarsForAgent(cfg, "support")
+	var card AgentCardConfig
+	if err := json.Unmarshal([]byte(env[EnvAgentCard]), &card); err != nil {
+		t.Fatalf("decode %s: %v", EnvAgentCard, err)
+	}
+	if card.DisplayName != "Acme Support" {
+		t.Errorf("card = %+v", card)
+	}
+	if _, ok := runtimeEnvVarsForAgent(cfg, "billing")[EnvAgentCard]; ok {
+		t.Errorf("%s set for an agent without overrides", EnvAgentCard)
+	}
+}
+
+func TestValidateAgentCards(t *testing.T) {
+	errs := validateAgentCards(map[string]*AgentCardConfig{
+		"default": {IconURL: "https://acme.example/icon.png", ProviderURL: "https://acme.example"},
+		"support": {DocumentationURL: "docs/support", IconURL: "ftp://acme.example/icon.png"},
+	})
+	if len(errs) != 2 {
+		t.Fatalf("errs = %v, want 2", errs)
+	}
+	if !strings.Contains(errs[0], "agent_cards.support.documentation_url") ||
+		!strings.Contains(errs[1], "agent_cards.support.icon_url") {
+		t.Errorf("errs = %v", errs)
+	}
+}
@@ -0,0 +1,146 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// AgentsFilterConfig selects the members of a multi-agent pack a deploy
+// config deploys, so one environment can run a subset of a large pack.
+// Include lists the only members deployed; Exclude lists members left out.
+// Only one of them may be set.
+type AgentsFilterConfig struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// validateAgentsFilter checks the agents_filter block. Whether its names
+// are agents of the pack is checked at Plan time by filterAgents.
+func validateAgentsFilter(f *AgentsFilterConfig) []string {
+	if f == nil {
+		return nil
+	}
+	var errs []string
+	if len(f.Include) > 0 && len(f.Exclude) > 0 {
+		errs = append(errs, "agents_filter: set include or exclude, not both")
+	}
+	for field, names := range map[string][]string{"include": f.Include, "exclude": f.Exclude} {
+		for i, name := range names {
+			if name == "" {
+				errs = append(errs, fmt.Sprintf("agents_filter.%s[%d] must not be empty", field, i))
+			}
+		}
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// filterAgents removes the members agents_filter does not deploy from
+// pack, along with their prompts, so every later step sees only the
+// deployed members. It fails when the filter names an agent the pack
+// does not have, drops the entry agent, or drops a member whose workflow
+// state a deployed member runs.
+func filterAgents(pack *prompt.Pack, cfg *Config) error {
+	f := cfg.AgentsFilter
+	if f == nil {
+		return nil
+	}
+	if !adaptersdk.IsMultiAgent(pack) {
+		return fmt.Errorf("agents_filter requires a multi-agent pack")
+	}
+	members := pack.Agents.Members
+	for _, name := range append(slices.Clone(f.Include), f.Exclude...) {
+		if _, ok := members[name]; !ok {
+			return fmt.Errorf("agents_filter: %q is not an agent of the pack", name)
+		}
+	}
+	deployed := func(name string) bool {
+		return (len(f.Include) == 0 || slices.Contains(f.Include, name)) && !slices.Contains(f.Exclude, name)
+	}
+	if !deployed(pack.Agents.Entry) {
+		return fmt.Errorf("agents_filter drops the entry agent %q", pack.Agents.Entry)
+	}
+	for _, name := range sortedKeys(members) {
+		if !deployed(name) {
+			continue
+		}
+		if ref := stateMember(pack, members[name]); ref != "" && !deployed(ref) {
+			return fmt.Errorf("agents_filter drops %q, whose prompt agent %q runs", ref, name)
+		}
+	}
+	for name := range members {
+		if !deployed(name) {
+			delete(members, name)
+			delete(pack.Prompts, name)
+		}
+	}
+	return nil
+}
+
+// stateMember returns the member whose prompt the workflow state backing
+// def runs, or "" when def is not state-backed or the state runs a prompt
+// that is no member.
+func stateMember(pack *prompt.Pack, def *prompt.AgentDef) string {
+	if def == nil || def.State == "" || pack.Workflow == nil {
+		return ""
+	}
+	state := pack.Workflow.States[def.State]
+	if state == nil {
+		return ""
+	}
+	if _, ok := pack.Agents.Members[state.PromptTask]; !ok {
+		return ""
+	}
+	return state.PromptTask
+}
+
+// deployedPackJSON returns the pack JSON the runtime loads: packJSON with
+// the tool_policy_defaults merged and the members agents_filter drops
+// removed.
+func deployedPackJSON(packJSON string, pack *prompt.Pack, cfg *Config) (string, error) {
+	out, err := runtimePackJSON(packJSON, pack)
+	if err != nil {
+		return "", err
+	}
+	return runtimePackAgents(out, pack, cfg)
+}
+
+// runtimePackAgents returns packJSON with its agents members narrowed to
+// those of pack, so the runtime offers no A2A peer agents_filter did not
+// deploy. Packs whose members were not filtered are returned unchanged.
+func runtimePackAgents(packJSON string, pack *prompt.Pack, cfg *Config) (string, error) {
+	if cfg.AgentsFilter == nil || !adaptersdk.IsMultiAgent(pack) {
+		return packJSON, nil
+	}
+	var raw map[string]json.RawMessage
+	err := json.Unmarshal([]byte(packJSON), &raw)
+	if err != nil {
+		return "", err
+	}
+	var agents map[string]json.RawMessage
+	if err = json.Unmarshal(raw["agents"], &agents); err != nil {
+		return "", fmt.Errorf("pack agents: %w", err)
+	}
+	var members map[string]json.RawMessage
+	if err = json.Unmarshal(agents["members"], &members); err != nil {
+		return "", fmt.Errorf("pack agents members: %w", err)
+	}
+	for name := range members {
+		if _, ok := pack.Agents.Members[name]; !ok {
+			delete(members, name)
+		}
+	}
+	if agents["members"], err = json.Marshal(members); err != nil {
+		return "", err
+	}
+	if raw["agents"], err = json.Marshal(agents); err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(raw)
+	return string(out), err
+}
@@ -0,0 +1,163 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/AltairaLabs/PromptKit/runtime/workflow"
+)
+
+func TestValidateAgentsFilter(t *testing.T) {
+	for _, tt := range []struct {
+		filter *AgentsFilterConfig
+		want   string
+	}{
+		{nil, ""},
+		{&AgentsFilterConfig{Include: []string{"router"}}, ""},
+		{&AgentsFilterConfig{Include: []string{"router"}, Exclude: []string{"worker"}}, "not both"},
+		{&AgentsFilterConfig{Exclude: []string{"worker", ""}}, "agents_filter.exclude[1] must not be empty"},
+	} {
+		errs := validateAgentsFilter(tt.filter)
+		got := strings.Join(errs, "; ")
+		if (tt.want == "") != (len(errs) == 0) || !strings.Contains(got, tt.want) {
+			t.Errorf("validateAgentsFilter(%+v) = %v, want %q", tt.filter, errs, tt.want)
+		}
+	}
+}
+
+func TestPlan_AgentsFilter(t *testing.T) {
+	prior, _ := json.Marshal(AdapterState{Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "worker", ARN: "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/w"},
+	}})
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     multiAgentPackWithToolsAndEvalsJSON(),
+		DeployConfig: strings.TrimSuffix(validDeployConfig, "}") + `,"agents_filter":{"exclude":["worker"]}}`,
+		ArenaConfig:  validArenaConfigJSON,
+		PriorState:   string(prior),
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	var router bool
+	for _, c := range resp.Changes {
+		switch {
+		case c.Type == ResTypeAgentRuntime && c.Name == "router":
+			router = c.Action == deploy.ActionCreate
+		case c.Type == ResTypeAgentRuntime && c.Name == "worker":
+			if c.Action != deploy.ActionDelete {
+				t.Errorf("worker runtime action = %s, want delete", c.Action)
+			}
+		case strings.Contains(c.Name, "worker") || c.Type == ResTypeToolGateway:
+			t.Errorf("plan has %s %s of the filtered-out worker", c.Type, c.Name)
+		}
+	}
+	if !router {
+		t.Errorf("plan has no router runtime: %+v", resp.Changes)
+	}
+}
+
+func TestPlan_AgentsFilterErrors(t *testing.T) {
+	for _, tt := range []struct {
+		pack, filter, want string
+	}{
+		{multiAgentPackJSON(), `{"include":["worker","planner"]}`, `"planner" is not an agent of the pack`},
+		{multiAgentPackJSON(), `{"exclude":["router"]}`, `drops the entry agent "router"`},
+		{singleAgentPackJSON(), `{"exclude":["default"]}`, "requires a multi-agent pack"},
+	} {
+		_, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+			PackJSON:     tt.pack,
+			DeployConfig: strings.TrimSuffix(validDeployConfig, "}") + `,"agents_filter":` + tt.filter + `}`,
+			ArenaConfig:  validArenaConfigJSON,
+		})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Plan with agents_filter %s = %v, want %q", tt.filter, err, tt.want)
+		}
+	}
+}
+
+func TestFilterAgents_StateBackedMember(t *testing.T) {
+	newPack := func() *prompt.Pack {
+		return &prompt.Pack{
+			Prompts: map[string]*prompt.PackPrompt{"router": {}, "reviewer": {}, "writer": {}},
+			Agents: &prompt.AgentsConfig{Entry: "router", Members: map[string]*prompt.AgentDef{
+				"router": {}, "reviewer": {State: "review"}, "writer": {},
+			}},
+			Workflow: &workflow.Spec{States: map[string]*workflow.State{"review": {PromptTask: "writer"}}},
+		}
+	}
+	cfg := &Config{AgentsFilter: &AgentsFilterConfig{Exclude: []string{"writer"}}}
+	if err := filterAgents(newPack(), cfg); err == nil ||
+		!strings.Contains(err.Error(), `drops "writer", whose prompt agent "reviewer" runs`) {
+		t.Errorf("filterAgents = %v, want the reviewer's state to keep writer", err)
+	}
+
+	pack := newPack()
+	cfg.AgentsFilter = &AgentsFilterConfig{Exclude: []string{"reviewer"}}
+	if err := filterAgents(pack, cfg); err != nil {
+		t.Fatalf("filterAgents: %v", err)
+	}
+	if len(pack.Agents.Members) != 2 || pack.Agents.Members["reviewer"] != nil || pack.Prompts["reviewer"] != nil {
+		t.Errorf("filtered pack keeps the reviewer: %v, %v", pack.Agents.Members, pack.Prompts)
+	}
+}
+
+func TestDeployedPackJSON_NarrowsMembers(t *testing.T) {
+	raw := multiAgentPackJSON()
+	pack, err := parsePack(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{AgentsFilter: &AgentsFilterConfig{Include: []string{"router"}}}
+	if err = filterAgents(pack, cfg); err != nil {
+		t.Fatalf("filterAgents: %v", err)
+	}
+	got, err := deployedPackJSON(raw, pack, cfg)
+	if err != nil {
+		t.Fatalf("deployedPackJSON: %v", err)
+	}
+	runtimePack, err := parsePack(got)
+	if err != nil {
+		t.Fatalf("parse %s: %v", got, err)
+	}
+	if len(runtimePack.Agents.Members) != 1 || runtimePack.Agents.Members["router"] == nil {
+		t.Errorf("runtime pack members = %v, want only router", runtimePack.Agents.Members)
+	}
+	if runtimePack.Prompts["worker"] == nil {
+		t.Error("runtime pack lost the worker prompt")
+	}
+
+	if got, err = deployedPackJSON(raw, pack, &Config{}); err != nil || got != raw {
+		t.Errorf("deployedPackJSON without a filter = %s, %v, want the pack unchanged", got, err)
+	}
+}
+
+func TestApply_AgentsFilter(t *testing.T) {
+	state, err := newSimulatedProvider().Apply(context.Background(), &deploy.PlanRequest{
+		PackJSON:     multiAgentPack(),
+		DeployConfig: strings.TrimSuffix(validConfig(t), "}") + `,"agents_filter":{"include":["coordinator"]}}`,
+		ArenaConfig:  validArenaConfigJSON,
+	}, func(*deploy.ApplyEvent) error { return nil })
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var st AdapterState
+	if err = json.Unmarshal([]byte(state), &st); err != nil {
+		t.Fatal(err)
+	}
+	var wired []string
+	for _, r := range st.Resources {
+		if strings.HasPrefix(r.Name, "worker") {
+			t.Errorf("state has %s %s of the filtered-out worker", r.Type, r.Name)
+		}
+		if r.Type == ResTypeA2AEndpoint {
+			wired = append(wired, r.Name)
+		}
+	}
+	if strings.Join(wired, ",") != "coordinator_a2a" {
+		t.Errorf("a2a endpoints = %v, want only coordinator_a2a", wired)
+	}
+}
@@ -0,0 +1,193 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Middleware IDs of the apiCallRecorder's two stack steps.
+const (
+	apiCallsCountID   = "AgentCoreAPICallCount"
+	apiCallsAttemptID = "AgentCoreAPICallAttempt"
+)
+
+// apiCallsShown caps how many operations the api_calls summary lists; the
+// rest are folded into a trailing count.
+const apiCallsShown = 5
+
+// throttleCodes classifies an attempt's error as throttling.
+var throttleCodes = retry.ThrottleErrorCode{Codes: retry.DefaultThrottleErrorCodes}
+
+// apiCallStats counts the calls made to one AWS API operation.
+type apiCallStats struct {
+	Service   string
+	Operation string
+
+	// Calls is the number of operation calls; Retries the extra attempts
+	// the SDK made for them, and Throttles the attempts AWS throttled.
+	Calls     int
+	Retries   int
+	Throttles int
+
+	// Duration is the time spent in the calls, retries and backoff
+	// included.
+	Duration time.Duration
+
+	// attempts counts every attempt, first ones included; apiCalls derives
+	// Retries from it.
+	attempts int
+}
+
+// apiCallReporter is implemented by clients that count their AWS API
+// calls.
+type apiCallReporter interface {
+	apiCalls() []apiCallStats
+}
+
+// apiCallRecorder counts the AWS API calls of every client built from an
+// aws.Config it is registered on. It is safe for concurrent use.
+type apiCallRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*apiCallStats
+}
+
+// newAPICallRecorder returns an empty recorder.
+func newAPICallRecorder() *apiCallRecorder {
+	return &apiCallRecorder{stats: make(map[string]*apiCallStats)}
+}
+
+// register installs the recorder's middleware on an operation stack. It is
+// an aws.Config APIOptions entry. The count runs once per call, after the
+// service metadata is known; the attempt runs after the retry middleware,
+// once per attempt.
+func (r *apiCallRecorder) register(stack *middleware.Stack) error {
+	count := middleware.InitializeMiddlewareFunc(apiCallsCountID, r.handleCall)
+	if err := stack.Initialize.Add(count, middleware.After); err != nil {
+		return err
+	}
+	attempt := middleware.FinalizeMiddlewareFunc(apiCallsAttemptID, r.handleAttempt)
+	return stack.Finalize.Add(attempt, middleware.After)
+}
+
+// handleCall records one operation call and its duration.
+func (r *apiCallRecorder) handleCall(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	started := time.Now()
+	out, md, err := next.HandleInitialize(ctx, in)
+	elapsed := time.Since(started)
+	r.update(ctx, func(s *apiCallStats) {
+		s.Calls++
+		s.Duration += elapsed
+	})
+	return out, md, err
+}
+
+// handleAttempt records one attempt of an operation call, and whether AWS
+// throttled it.
+func (r *apiCallRecorder) handleAttempt(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	out, md, err := next.HandleFinalize(ctx, in)
+	throttled := err != nil && throttleCodes.IsErrorThrottle(err) == aws.TrueTernary
+	r.update(ctx, func(s *apiCallStats) {
+		s.attempts++
+		if throttled {
+			s.Throttles++
+		}
+	})
+	return out, md, err
+}
+
+// update applies fn to the stats of the operation ctx is calling.
+func (r *apiCallRecorder) update(ctx context.Context, fn func(*apiCallStats)) {
+	service, op := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+	key := service + "." + op
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.stats[key]
+	if !ok {
+		s = &apiCallStats{Service: service, Operation: op}
+		r.stats[key] = s
+	}
+	fn(s)
+}
+
+// apiCalls returns the recorded operations, the most time-consuming first.
+func (r *apiCallRecorder) apiCalls() []apiCallStats {
+	r.mu.Lock()
+	out := make([]apiCallStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		c := *s
+		c.Retries = max(c.attempts-c.Calls, 0)
+		out = append(out, c)
+	}
+	r.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Duration != out[j].Duration {
+			return out[i].Duration > out[j].Duration
+		}
+		return out[i].Service+out[i].Operation < out[j].Service+out[j].Operation
+	})
+	return out
+}
+
+// apiCallsSummary reports the AWS API calls client made as one line, or ""
+// when the client does not count them or made none.
+func apiCallsSummary(client any) string {
+	reporter, ok := client.(apiCallReporter)
+	if !ok {
+		return ""
+	}
+	stats := reporter.apiCalls()
+	if len(stats) == 0 {
+		return ""
+	}
+	var total apiCallStats
+	for _, s := range stats {
+		total.Calls += s.Calls
+		total.Retries += s.Retries
+		total.Throttles += s.Throttles
+	}
+	parts := make([]string, 0, apiCallsShown+1)
+	for i, s := range stats {
+		if i == apiCallsShown {
+			parts = append(parts, fmt.Sprintf("%d more operations", len(stats)-apiCallsShown))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", s.Service, s.Operation, formatAPICallCounts(s)))
+	}
+	return fmt.Sprintf("AWS API calls: %s; %s", formatAPICallCounts(total), strings.Join(parts, ", "))
+}
+
+// formatAPICallCounts renders a call count with its retries, throttles, and
+// duration, e.g. "12 calls (3 retries, 2 throttled, 41.2s)".
+func formatAPICallCounts(s apiCallStats) string {
+	detail := make([]string, 0, 3)
+	if s.Retries > 0 {
+		detail = append(detail, fmt.Sprintf("%d retries", s.Retries))
+	}
+	if s.Throttles > 0 {
+		detail = append(detail, fmt.Sprintf("%d throttled", s.Throttles))
+	}
+	if s.Duration > 0 {
+		detail = append(detail, s.Duration.Round(time.Millisecond).String())
+	}
+	calls := fmt.Sprintf("%d calls", s.Calls)
+	if s.Calls == 1 {
+		calls = "1 call"
+	}
+	if len(detail) == 0 {
+		return calls
+	}
+	return calls + " (" + strings.Join(detail, ", ") + ")"
+}
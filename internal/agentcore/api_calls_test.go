@@ -0,0 +1,157 @@
+package agentcore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const stsThrottleResponse = `<ErrorResponse><Error><Type>Sender</Type>` +
+	`<Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`
+
+const stsIdentityResponse = `<GetCallerIdentityResponse><GetCallerIdentityResult>` +
+	`<Account>123456789012</Account></GetCallerIdentityResult></GetCallerIdentityResponse>`
+
+// countingDestroyer is a simulated destroyer that reports API calls.
+type countingDestroyer struct {
+	simulatedDestroyer
+	stats []apiCallStats
+}
+
+func (d *countingDestroyer) apiCalls() []apiCallStats {
+	return d.stats
+}
+
+// countingAWSClient is a simulated client that reports API calls.
+type countingAWSClient struct {
+	*simulatedAWSClient
+	stats []apiCallStats
+}
+
+func (c *countingAWSClient) apiCalls() []apiCallStats {
+	return c.stats
+}
+
+func TestAPICallRecorder_CountsRetriesAndThrottles(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(stsThrottleResponse))
+			return
+		}
+		_, _ = w.Write([]byte(stsIdentityResponse))
+	}))
+	defer srv.Close()
+
+	rec := newAPICallRecorder()
+	client := sts.New(sts.Options{
+		Region:       "us-west-2",
+		BaseEndpoint: aws.String(srv.URL),
+		Credentials:  aws.AnonymousCredentials{},
+		APIOptions:   []func(*middleware.Stack) error{rec.register},
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+			o.RateLimiter = ratelimit.None
+		}),
+	})
+	for range 2 {
+		if _, err := client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{}); err != nil {
+			t.Fatalf("GetCallerIdentity: %v", err)
+		}
+	}
+
+	stats := rec.apiCalls()
+	if len(stats) != 1 {
+		t.Fatalf("stats = %+v, want one operation", stats)
+	}
+	s := stats[0]
+	if s.Service != "STS" || s.Operation != "GetCallerIdentity" {
+		t.Errorf("operation = %s %s", s.Service, s.Operation)
+	}
+	if s.Calls != 2 || s.Retries != 2 || s.Throttles != 2 || s.Duration <= 0 {
+		t.Errorf("stats = %+v, want 2 calls, 2 retries, 2 throttles", s)
+	}
+}
+
+func TestAPICallsSummary(t *testing.T) {
+	stats := []apiCallStats{
+		{Service: "Bedrock AgentCore Control", Operation: "GetAgentRuntime", Calls: 40,
+			Retries: 3, Throttles: 2, Duration: 95 * time.Second},
+		{Service: "STS", Operation: "GetCallerIdentity", Calls: 1, Duration: 120 * time.Millisecond},
+	}
+	got := apiCallsSummary(&countingDestroyer{stats: stats})
+	want := "AWS API calls: 41 calls (3 retries, 2 throttled); " +
+		"Bedrock AgentCore Control GetAgentRuntime 40 calls (3 retries, 2 throttled, 1m35s), " +
+		"STS GetCallerIdentity 1 call (120ms)"
+	if got != want {
+		t.Errorf("summary =\n%s\nwant\n%s", got, want)
+	}
+
+	for range apiCallsShown {
+		stats = append(stats, apiCallStats{Service: "S3", Operation: "PutObject", Calls: 1})
+	}
+	if got = apiCallsSummary(&countingDestroyer{stats: stats}); !strings.HasSuffix(got, ", 2 more operations") {
+		t.Errorf("summary = %s, want the extra operations folded", got)
+	}
+
+	if got = apiCallsSummary(&simulatedDestroyer{}); got != "" {
+		t.Errorf("summary = %q, want none for a client that does not count calls", got)
+	}
+}
+
+func TestDestroy_ReportsAPICalls(t *testing.T) {
+	p := newSimulatedProvider()
+	p.destroyerFunc = func(_ context.Context, _ *Config) (resourceDestroyer, error) {
+		return &countingDestroyer{stats: []apiCallStats{
+			{Service: "Bedrock AgentCore Control", Operation: "DeleteAgentRuntime", Calls: 1},
+		}}, nil
+	}
+	var last *deploy.DestroyEvent
+	err := p.Destroy(context.Background(), &deploy.DestroyRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState: mustJSON(t, &AdapterState{Resources: []ResourceState{
+			{Type: ResTypeAgentRuntime, Name: "a", ARN: "arn:runtime:a"},
+		}}),
+	}, func(e *deploy.DestroyEvent) error {
+		last = e
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	want := "Destroy complete; AWS API calls: 1 call; Bedrock AgentCore Control DeleteAgentRuntime 1 call"
+	if last.Type != "complete" || last.Message != want {
+		t.Errorf("last event = %+v, want %q", last, want)
+	}
+}
+
+func TestApply_ReportsAPICalls(t *testing.T) {
+	p := newSimulatedProvider()
+	p.awsClientFunc = func(_ context.Context, cfg *Config) (awsClient, error) {
+		return &countingAWSClient{newSimulatedAWSClient(cfg.Region), []apiCallStats{
+			{Service: "Bedrock AgentCore Control", Operation: "CreateAgentRuntime", Calls: 1},
+		}}, nil
+	}
+	events, _, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: validConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	last := events[len(events)-1]
+	if !strings.HasPrefix(last.Message, "AWS API calls: 1 call; ") {
+		t.Errorf("last event = %+v, want the api_calls summary", last)
+	}
+}
@@ -4,9 +4,12 @@ package agentcore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/AltairaLabs/PromptKit/runtime/deploy"
 	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
@@ -14,26 +17,24 @@ import (
 	"github.com/AltairaLabs/PromptKit/runtime/prompt"
 )
 
-// numApplyPhases is the total number of apply phases for progress tracking.
-const numApplyPhases = 6
-
-// progressStepSize is the fraction of the overall progress bar each of the
-// six apply phases occupies (tools, policies, runtimes, a2a, evaluators,
-// online_eval_config).
-const progressStepSize = 1.0 / numApplyPhases
-
-// Apply phase step indices for progress tracking.
-const (
-	stepTools         = 0
-	stepPolicies      = 1
-	stepRuntimes      = 2
-	stepA2A           = 3
-	stepEvaluators    = 4
-	stepOnlineEvalCfg = 5
-)
+// progressSpan is the stretch of the progress bar one apply phase reports
+// within: start is where it begins and size how much of the bar it takes.
+type progressSpan struct {
+	start, size float64
+}
+
+// phaseSpan returns the span of the phase at index step of applyOrder. The
+// bar is split evenly between the registered resource types.
+func phaseSpan(step, phases int) progressSpan {
+	size := 1.0 / float64(phases)
+	return progressSpan{start: float64(step) * size, size: size}
+}
 
-// progressA2ADiscovery is the progress percentage for the A2A discovery step.
-const progressA2ADiscovery = 0.5
+// at returns the progress after done of a phase's total resources, leaving
+// room at the end of the span for the phase's last resource.
+func (s progressSpan) at(done, total int) float64 {
+	return s.start + float64(done)/float64(total+1)*s.size
+}
 
 // createFunc is the signature shared by all awsClient Create* methods.
 type createFunc func(ctx context.Context, name string, cfg *Config) (string, error)
@@ -42,6 +43,10 @@ type createFunc func(ctx context.Context, name string, cfg *Config) (string, err
 // It takes the prior ARN so the implementation can extract the resource ID.
 type updateFunc func(ctx context.Context, arn string, name string, cfg *Config) (string, error)
 
+// replaceFunc deletes a prior resource that cannot be updated in place, so
+// that applyPhase can create it again.
+type replaceFunc func(ctx context.Context, prior ResourceState, reason string) error
+
 // applyPhaseResult holds the output of a single apply phase.
 type applyPhaseResult struct {
 	resources   []ResourceState
@@ -53,21 +58,41 @@ type applyPhaseResult struct {
 type applyContext struct {
 	pack     *prompt.Pack
 	cfg      *Config
+	outputs  map[string]OutputDecl
 	reporter *adaptersdk.ProgressReporter
 	client   awsClient
 	priorMap map[string]ResourceState
+
+	// carried holds the keys of prior resources kept unchanged because
+	// the phases config skipped their phase.
+	carried map[string]bool
+
+	// permissions records the IAM permissions AWS denied, for the summary
+	// at the end of Apply.
+	permissions *permissionLog
+
+	// listGatewayTools probes the gateway after its targets are created.
+	// It is nil unless gateway.probe_targets is set.
+	listGatewayTools gatewayToolLister
+
+	// newInvoker invokes updated runtimes for rollout health checks. It
+	// is nil when runtimes cannot be invoked.
+	newInvoker runtimeInvokerFactory
+
+	// span is the stretch of the progress bar of the phase being applied.
+	span progressSpan
 }
 
 // prepareApply parses the request and initializes the apply context.
 func (p *Provider) prepareApply(
 	ctx context.Context, req *deploy.PlanRequest, callback deploy.ApplyCallback,
 ) (*applyContext, error) {
-	pack, err := adaptersdk.ParsePack([]byte(req.PackJSON))
+	pack, err := parsePack(req.PackJSON)
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: failed to parse pack: %w", err)
 	}
 
-	cfg, err := parseConfig(req.DeployConfig)
+	cfg, err := p.loadConfig(req.DeployConfig, arenaConfigVars(req.ArenaConfig))
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
 	}
@@ -77,65 +102,156 @@ func (p *Provider) prepareApply(
 		return nil, fmt.Errorf("agentcore: %w", err)
 	}
 	mergeToolTargets(cfg.ArenaConfig, cfg.ToolTargets)
+	if err = filterAgents(pack, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+	outputs, err := loadOutputs(req.PackJSON, pack, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+
+	permissions := &permissionLog{}
+	reporter := adaptersdk.NewProgressReporter(permissions.applyCallback(callback))
+	if err := reporter.Progress(p.Version(ctx).String(), 0); err != nil {
+		return nil, err
+	}
+	if err := reportRuntimeCompatibility(reporter, cfg.RuntimeBinaryPath, pack); err != nil {
+		return nil, err
+	}
+	warnings := checkModelAvailability(ctx, p.modelCatalogFunc, pack, cfg)
+	warnings = append(warnings, checkInterceptorReachability(ctx, p.lambdaCheckFunc, pack, cfg)...)
+	for _, w := range warnings {
+		if err := reporter.Progress("Warning: "+w, 0); err != nil {
+			return nil, err
+		}
+	}
 
 	client, err := p.awsClientFunc(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: failed to create AWS client: %w", err)
 	}
+	if ws, ok := client.(waitProgressSetter); ok {
+		ws.SetWaitProgress(func(msg string) { _ = reporter.Progress(msg, progressNoPercent) })
+	}
 
-	cfg.PackJSON = req.PackJSON
+	if cfg.PackJSON, err = deployedPackJSON(req.PackJSON, pack, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
 	cfg.PackTools = pack.Tools
 	cfg.PromptNames = extractPromptNames(pack)
+	cfg.AgentTools = runtimeTools(pack)
+	cfg.RuntimeNames = agentRuntimeNames(pack)
 	cfg.RuntimeEnvVars = buildRuntimeEnvVars(cfg)
-	cfg.ResourceTags = buildResourceTags(pack.ID, pack.Version, "", cfg.Tags)
+	cfg.ResourceTags = buildResourceTags(pack.ID, pack.Version, cfg.Workspace, "", cfg.Tags)
 	injectMetricsConfig(cfg, pack)
 	injectDashboardConfig(cfg, pack)
 
-	if err := uploadCodePackage(ctx, client, cfg, req.PackJSON); err != nil {
+	if err := uploadCodePackage(ctx, client, cfg, cfg.PackJSON); err != nil {
 		return nil, fmt.Errorf("agentcore: %w", err)
 	}
 
-	return &applyContext{
+	ac := &applyContext{
 		pack:     pack,
 		cfg:      cfg,
-		reporter: adaptersdk.NewProgressReporter(callback),
+		outputs:  outputs,
+		reporter: reporter,
 		client:   client,
-		priorMap: parsePriorState(req.PriorState),
-	}, nil
+		priorMap: applyPriorMap(req.PriorState, pack, cfg),
+		carried:  make(map[string]bool),
+
+		permissions: permissions,
+		newInvoker:  p.invokerFunc,
+	}
+	if cfg.gatewayProbeEnabled() {
+		ac.listGatewayTools = p.gatewayListFunc
+	}
+	return ac, nil
 }
 
 // Apply executes a deployment plan, streaming progress events via the callback.
-// Resources are created in dependency order:
-//  1. Tool Gateway entries (from pack tools)
-//  2. Agent runtimes (one per agent member, or single for non-multi-agent)
-//  3. A2A wiring between agents
-//  4. Evaluators
+// Resources are created type by type in applyOrder, the dependency order of
+// the resource type registry: memory, inference profiles, identity
+// providers, tool gateway entries, Cedar policies, agent runtimes, A2A
+// wiring, runtime endpoints, log groups, evaluators, the online eval config,
+// its alert, and the logs queries. Each type gets an equal share of the
+// progress bar.
 //
 // When DryRun is enabled in config, Apply emits planned resource events
 // without calling any AWS APIs and returns a preview of the deployment.
+// EnhancedDryRun adds read-only AWS checks to the preview.
 func (p *Provider) Apply(
 	ctx context.Context, req *deploy.PlanRequest, callback deploy.ApplyCallback,
 ) (string, error) {
 	// Check for dry-run mode before full preparation (avoids AWS client creation).
-	cfg, err := parseConfig(req.DeployConfig)
+	cfg, err := p.loadConfig(req.DeployConfig, arenaConfigVars(req.ArenaConfig))
 	if err != nil {
 		return "", fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
 	}
-	if cfg.DryRun {
+	redact := newRedactor(cfg)
+	defer redact.maskLogs()()
+	stateJSON, err := p.applyWithConfig(ctx, req, redact.applyCallback(callback), cfg)
+	return stateJSON, redact.redactError(err)
+}
+
+// applyWithConfig runs Apply once the deploy config has loaded. Its events
+// and error are redacted by the caller.
+func (p *Provider) applyWithConfig(
+	ctx context.Context, req *deploy.PlanRequest, callback deploy.ApplyCallback, cfg *Config,
+) (string, error) {
+	// An unreadable prior state is treated as empty, as parsePriorState does.
+	if prior, parseErr := parseAdapterState(req.PriorState); parseErr == nil {
+		if err := checkStateWorkspace(prior, cfg); err != nil {
+			return "", fmt.Errorf("agentcore: %w", err)
+		}
+	}
+	if cfg.isDryRun() {
 		return p.applyDryRun(ctx, req, callback)
 	}
+	if cfg.requiresApproval() {
+		if err := p.awaitApproval(ctx, req, callback, cfg); err != nil {
+			return "", fmt.Errorf("agentcore: %w", err)
+		}
+	}
 
+	started := time.Now()
 	ac, err := p.prepareApply(ctx, req, callback)
 	if err != nil {
 		return "", err
 	}
 
 	resources, applyErr := p.executeApplyPhases(ctx, ac)
+	testErr, cbErr := p.exerciseRuntimes(ctx, ac, resources)
+	if cbErr != nil {
+		return "", cbErr
+	}
+	applyErr = errors.Join(applyErr, testErr)
+	markOwnership(resources, ac.client, ac.priorMap)
+	markRenamed(resources, ac.priorMap)
+	reportAdopted(ac.reporter, resources, ac.client)
+
+	manifest := buildChangeManifest(req, ac, resources, started, time.Now())
+	if uri, uploadErr := uploadChangeManifest(ctx, ac, manifest); uploadErr != nil {
+		applyErr = errors.Join(applyErr, fmt.Errorf("agentcore: upload change manifest: %w", uploadErr))
+	} else if uri != "" {
+		_ = ac.reporter.Progress("Uploaded change manifest to "+uri, progressNoPercent)
+	}
+	if summary := apiCallsSummary(ac.client); summary != "" {
+		_ = ac.reporter.Progress(summary, progressNoPercent)
+	}
+	if summary := ac.permissions.summary(); summary != "" {
+		_ = ac.reporter.Progress(summary, progressNoPercent)
+	}
 
+	outputs := mergeOutputs(buildOutputs(resources), logsQueryOutputs(resources, ac.cfg.Region))
+	outputs = mergeOutputs(outputs, gatewayTargetOutputs(resources))
 	state := AdapterState{
 		Resources: resources,
 		PackID:    ac.pack.ID,
 		Version:   ac.pack.Version,
+		Workspace: ac.cfg.Workspace,
+		Outputs:   mergeOutputs(outputs, resolveOutputs(ac.outputs, ac.pack, resources)),
+		Manifest:  manifest,
+		Variables: ac.cfg.Variables,
 	}
 	stateJSON, err := json.Marshal(state)
 	if err != nil {
@@ -145,18 +261,33 @@ func (p *Provider) Apply(
 	return string(stateJSON), applyErr
 }
 
+// exerciseRuntimes invokes the deployed runtimes once every phase has
+// run: it pre-warms them, then runs the post-deploy tests. It returns the
+// error of failed tests, and separately that of a progress callback that
+// aborted Apply.
+func (p *Provider) exerciseRuntimes(
+	ctx context.Context, ac *applyContext, resources []ResourceState,
+) (testErr, cbErr error) {
+	if cbErr = prewarmRuntimes(ctx, p.invokerFunc, ac.reporter, ac.cfg, resources); cbErr != nil {
+		return nil, cbErr
+	}
+	return runPostDeployTests(ctx, p.invokerFunc, ac, resources)
+}
+
 // applyDryRun generates a deployment preview without calling AWS APIs.
 // It emits resource events with status "planned" for each resource that
-// would be created.
+// would be created. An enhanced dry run then checks the deployment with
+// read-only AWS calls, returning the preview with an error when a check
+// fails.
 func (p *Provider) applyDryRun(
-	_ context.Context, req *deploy.PlanRequest, callback deploy.ApplyCallback,
+	ctx context.Context, req *deploy.PlanRequest, callback deploy.ApplyCallback,
 ) (string, error) {
-	pack, err := adaptersdk.ParsePack([]byte(req.PackJSON))
+	pack, err := parsePack(req.PackJSON)
 	if err != nil {
 		return "", fmt.Errorf("agentcore: failed to parse pack: %w", err)
 	}
 
-	cfg, err := parseConfig(req.DeployConfig)
+	cfg, err := p.loadConfig(req.DeployConfig, arenaConfigVars(req.ArenaConfig))
 	if err != nil {
 		return "", fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
 	}
@@ -166,8 +297,13 @@ func (p *Provider) applyDryRun(
 		return "", fmt.Errorf("agentcore: %w", err)
 	}
 	mergeToolTargets(cfg.ArenaConfig, cfg.ToolTargets)
+	if err = filterAgents(pack, cfg); err != nil {
+		return "", fmt.Errorf("agentcore: %w", err)
+	}
 
-	cfg.PackJSON = req.PackJSON
+	if cfg.PackJSON, err = deployedPackJSON(req.PackJSON, pack, cfg); err != nil {
+		return "", fmt.Errorf("agentcore: %w", err)
+	}
 	cfg.PackTools = pack.Tools
 
 	reporter := adaptersdk.NewProgressReporter(callback)
@@ -177,18 +313,24 @@ func (p *Provider) applyDryRun(
 	if cbErr != nil {
 		return "", cbErr
 	}
+	var checkErr error
+	if cfg.EnhancedDryRun {
+		checkErr = p.runDryRunChecks(ctx, reporter, pack, cfg, desired)
+	}
 
 	state := AdapterState{
 		Resources: resources,
 		PackID:    pack.ID,
 		Version:   pack.Version,
+		Workspace: cfg.Workspace,
+		Variables: cfg.Variables,
 	}
 	stateJSON, err := json.Marshal(state)
 	if err != nil {
 		return "", fmt.Errorf("agentcore: failed to marshal state: %w", err)
 	}
 
-	return string(stateJSON), nil
+	return string(stateJSON), checkErr
 }
 
 // emitDryRunResources iterates over desired resources, emitting progress and
@@ -228,137 +370,175 @@ func emitDryRunResources(
 	return resources, nil
 }
 
-// executeApplyPhases runs all deploy phases in dependency order.
+// executeApplyPhases deploys each registered resource type in apply order,
+// carrying over the prior resources of the types the phases config skips.
 func (p *Provider) executeApplyPhases(
 	ctx context.Context, ac *applyContext,
 ) ([]ResourceState, error) {
 	var resources []ResourceState
 	var applyErr, cbErr error
-
-	// Pre-step — Memory (if configured).
-	resources, applyErr = applyMemoryPreStep(ctx, ac, resources, applyErr)
-
-	// Step 1 — Tool Gateway entries (no update support yet).
-	phase := applyPhase(ctx, ac.reporter, ac.client.CreateGatewayTool, nil, ac.cfg,
-		sortedKeys(ac.pack.Tools), ResTypeToolGateway, stepTools, ac.priorMap)
-	resources, applyErr, cbErr = mergePhase(resources, applyErr, phase)
-	if cbErr != nil {
-		return resources, cbErr
+	for i, t := range applyOrder {
+		ac.span = phaseSpan(i, len(applyOrder))
+		if !ac.cfg.runsPhase(t.name) {
+			resources, cbErr = skipApplyPhase(ac, t, resources)
+		} else {
+			resources, applyErr, cbErr = t.apply(ctx, ac, resources, applyErr)
+		}
+		if cbErr != nil {
+			return resources, cbErr
+		}
 	}
+	return resources, applyErr
+}
 
-	// Capture gateway ARN for Cedar tool policies that need a specific resource.
-	ac.cfg.GatewayARN = findGatewayARN(resources)
-
-	// Step 2 — Cedar Policies (policy engine + policy per prompt with validators/tool_policy).
-	policyRes, policyErr, policyCbErr := applyPoliciesPhase(ctx, ac)
-	resources = append(resources, policyRes...)
-	applyErr = combineErrors(applyErr, policyErr)
-	if policyCbErr != nil {
-		return resources, policyCbErr
+// applyMemory creates a memory resource if configured.
+func applyMemory(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if !ac.cfg.HasMemory() {
+		return resources, applyErr, nil
 	}
+	memRes, memErr := createMemoryResource(ctx, ac.reporter, ac.client, ac.cfg, ac.pack, ac.priorMap)
+	if memErr != nil {
+		applyErr = combineErrors(applyErr, memErr)
+	}
+	if memRes != nil {
+		resources = append(resources, *memRes)
+	}
+	return resources, applyErr, nil
+}
 
-	// Step 3 — Agent runtimes (supports update).
-	phase = applyPhase(ctx, ac.reporter, ac.client.CreateRuntime, ac.client.UpdateRuntime, ac.cfg,
-		agentRuntimeNames(ac.pack), ResTypeAgentRuntime, stepRuntimes, ac.priorMap)
-	resources, applyErr, cbErr = mergePhase(resources, applyErr, phase)
+// applyToolGateways creates the gateway entries for the pack's tools (no
+// update support yet) and records the gateway ARNs that Cedar tool
+// policies are scoped to and runtimes are pointed at.
+func applyToolGateways(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	phase := applyPhase(ctx, ac.reporter, ac.client.CreateGatewayTool, nil, nil, ac.cfg,
+		toolGatewayNames(ac.pack, ac.cfg), ResTypeToolGateway, ac.span, ac.priorMap)
+	resources, applyErr, cbErr := mergePhase(resources, applyErr, phase)
 	if cbErr != nil {
-		return resources, cbErr
+		return resources, applyErr, cbErr
 	}
-
-	// Post-runtime step — A2A endpoint discovery (multi-agent only).
-	// Injects PROMPTPACK_AGENTS env var on the entry agent so it knows
-	// how to reach other members.
-	if adaptersdk.IsMultiAgent(ac.pack) {
-		if discoverErr := injectA2AEndpoints(ctx, ac, resources); discoverErr != nil {
-			applyErr = combineErrors(applyErr, discoverErr)
+	recordGatewayInterceptors(resources, ac.cfg)
+	recordGatewayTargetNames(resources)
+	restoreGatewayARN(ac, resources)
+	for _, w := range checkGatewayTargets(ctx, ac.listGatewayTools, newPoller(ac.cfg), resources) {
+		if cbErr = ac.reporter.Progress("Warning: "+w, progressNoPercent); cbErr != nil {
+			return resources, applyErr, cbErr
 		}
 	}
+	return resources, applyErr, nil
+}
 
-	// Step 4 — A2A wiring (only for multi-agent packs, no update support).
-	if adaptersdk.IsMultiAgent(ac.pack) {
-		resources, applyErr, cbErr = applyA2AWiring(ctx, ac, resources, applyErr)
-		if cbErr != nil {
-			return resources, cbErr
-		}
+// applyAgentRuntimes creates or updates the agent runtimes and records
+// their lifecycle. The members of a multi-agent pack are rolled out one at
+// a time behind health checks unless rollout.strategy is all_at_once.
+func applyAgentRuntimes(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	names := agentRuntimeNames(ac.pack)
+	var phase applyPhaseResult
+	var carried []ResourceState
+	if adaptersdk.IsMultiAgent(ac.pack) && ac.cfg.rollingUpdate() {
+		phase, carried = applyRuntimesRolling(ctx, ac, names)
+	} else {
+		phase = applyPhase(ctx, ac.reporter, ac.client.CreateRuntime, ac.client.UpdateRuntime, ac.replaceWith(),
+			ac.cfg, names, ResTypeAgentRuntime, ac.span, ac.priorMap)
 	}
+	annotateRuntimeLifecycle(phase.resources, ac.cfg)
+	recordRuntimeSettings(phase.resources, ac.cfg)
+	phase.resources = append(phase.resources, carried...)
+	return mergePhase(resources, applyErr, phase)
+}
 
-	// Steps 5–6 — Evaluators and Online Evaluation Config.
-	return applyEvalPhases(ctx, ac, resources, applyErr)
+// applyA2AEndpoints wires the members of a multi-agent pack together. It
+// first injects the PROMPTPACK_AGENTS env var on the entry agent so it
+// knows how to reach the other members, then creates the A2A wiring (no
+// update support).
+func applyA2AEndpoints(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if !adaptersdk.IsMultiAgent(ac.pack) {
+		return resources, applyErr, nil
+	}
+	if discoverErr := injectA2AEndpoints(ctx, ac, resources); discoverErr != nil {
+		applyErr = combineErrors(applyErr, discoverErr)
+	}
+
+	agents := adaptersdk.ExtractAgents(ac.pack)
+	wireNames := make([]string, len(agents))
+	for i, ag := range agents {
+		wireNames[i] = ag.Name + a2aWiringSuffix
+	}
+	ac.cfg.RuntimeARNs = deployedRuntimeARNs(resources)
+	phase := applyPhase(ctx, ac.reporter, ac.client.CreateA2AWiring, nil, nil, ac.cfg,
+		wireNames, ResTypeA2AEndpoint, ac.span, ac.priorMap)
+	annotateA2AWiring(phase.resources, ac.cfg)
+	return mergePhase(resources, applyErr, phase)
 }
 
-// applyEvalPhases deploys evaluators and wires them to traces via an online
-// evaluation config. Extracted from executeApplyPhases to reduce cognitive
-// complexity.
-func applyEvalPhases(
+// applyEvaluators creates an evaluator per llm_as_judge eval (no update
+// support yet).
+func applyEvaluators(
 	ctx context.Context, ac *applyContext,
 	resources []ResourceState, applyErr error,
-) ([]ResourceState, error) {
-	// Step 5 — Evaluators (no update support yet).
+) ([]ResourceState, error, error) {
 	ac.cfg.EvalDefs = buildEvalDefs(ac.pack)
 	evalNames := evalResourceNames(ac.pack)
-	if len(evalNames) > 0 {
-		phase := applyPhase(ctx, ac.reporter, ac.client.CreateEvaluator, nil, ac.cfg,
-			evalNames, ResTypeEvaluator, stepEvaluators, ac.priorMap)
-		var cbErr error
-		resources, applyErr, cbErr = mergePhase(resources, applyErr, phase)
-		if cbErr != nil {
-			return resources, cbErr
-		}
+	if len(evalNames) == 0 {
+		return resources, applyErr, nil
 	}
+	phase := applyPhase(ctx, ac.reporter, ac.client.CreateEvaluator, nil, nil, ac.cfg,
+		evalNames, ResTypeEvaluator, ac.span, ac.priorMap)
+	return mergePhase(resources, applyErr, phase)
+}
 
-	// Step 6 — Online Evaluation Config (wires evaluators to traces).
+// applyOnlineEvalConfig wires the evaluators to runtime traces through an
+// online evaluation config.
+func applyOnlineEvalConfig(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
 	ac.cfg.EvalARNs = collectEvalARNs(resources)
 	ac.cfg.BuiltinEvalIDs = collectBuiltinEvalIDs(ac.pack)
+	ac.cfg.EvalSampling = referencedEvalSampling(
+		collectEvalSampling(ac.pack, ac.cfg), ac.cfg.EvalARNs, ac.cfg.BuiltinEvalIDs)
 	if len(ac.cfg.EvalARNs) > 0 || len(ac.cfg.BuiltinEvalIDs) > 0 {
 		oecName := ac.pack.ID + "_online_eval"
-		phase := applyPhase(ctx, ac.reporter, ac.client.CreateOnlineEvalConfig, nil, ac.cfg,
-			[]string{oecName}, ResTypeOnlineEvalConfig, stepOnlineEvalCfg, ac.priorMap)
+		phase := applyPhase(ctx, ac.reporter, ac.client.CreateOnlineEvalConfig, ac.client.UpdateOnlineEvalConfig, nil,
+			ac.cfg, []string{oecName}, ResTypeOnlineEvalConfig, ac.span, ac.priorMap)
 		var cbErr error
 		resources, applyErr, cbErr = mergePhase(resources, applyErr, phase)
 		if cbErr != nil {
-			return resources, cbErr
+			return resources, applyErr, cbErr
 		}
 	}
 
-	return resources, applyErr
+	annotateEvalSampling(resources, ac.cfg.EvalSampling)
+	return resources, applyErr, nil
 }
 
-// applyMemoryPreStep creates a memory resource if configured.
-func applyMemoryPreStep(
+// applyCedarPolicies creates Cedar policy engines and policies for prompts
+// that have validators or tool_policy defined, and points the runtimes at
+// their engines.
+func applyCedarPolicies(
 	ctx context.Context, ac *applyContext,
 	resources []ResourceState, applyErr error,
-) ([]ResourceState, error) {
-	if !ac.cfg.HasMemory() {
-		return resources, applyErr
-	}
-	memRes, memErr := createMemoryResource(ctx, ac.reporter, ac.client, ac.cfg, ac.pack)
-	if memErr != nil {
-		applyErr = combineErrors(applyErr, memErr)
-	}
-	if memRes != nil {
-		resources = append(resources, *memRes)
-	}
-	return resources, applyErr
-}
-
-// applyPoliciesPhase creates Cedar policy engines and policies for prompts
-// that have validators or tool_policy defined. It returns the created
-// resources, any apply error, and any callback error.
-func applyPoliciesPhase(
-	ctx context.Context, ac *applyContext,
 ) ([]ResourceState, error, error) {
 	names := policyResourceNames(ac.pack)
 	if len(names) == 0 {
-		return nil, nil, nil
+		return resources, applyErr, nil
 	}
 
-	var resources []ResourceState
-	var applyErr error
-	baseProgress := float64(stepPolicies) * progressStepSize
+	var engines policyEngines
 
 	for i, promptName := range names {
-		pct := baseProgress + float64(i)/float64(len(names)+1)*progressStepSize
-		engineName := promptName + "_policy_engine"
+		pct := ac.span.at(i, len(names))
 
 		if err := ac.reporter.Progress(
 			fmt.Sprintf("Creating %s: %s", ResTypeCedarPolicy, promptName), pct,
@@ -366,7 +546,7 @@ func applyPoliciesPhase(
 			return resources, applyErr, err
 		}
 
-		res, err := createPolicyForPrompt(ctx, ac, promptName, engineName)
+		res, err := createPolicyForPrompt(ctx, ac, promptName, &engines)
 		if err != nil {
 			deployErr := newDeployError("create", ResTypeCedarPolicy, promptName, err)
 			_ = ac.reporter.Error(deployErr)
@@ -392,34 +572,86 @@ func applyPoliciesPhase(
 	return resources, applyErr, nil
 }
 
-// createPolicyForPrompt creates a policy engine and one Cedar policy per
-// forbid block for a single prompt. AWS CreatePolicy accepts only a single
-// policy statement, so multiple rules are created as separate policies on
-// the same engine. Returns the resource state on success.
+// policyEngine is a policy engine associated with the gateway.
+type policyEngine struct {
+	arn, id string
+}
+
+// policyEngines hands out the engines Cedar policies are created in. Each
+// prompt of a single-agent pack, and each member of a multi-agent pack
+// with per-agent gateways, gets its own engine on its gateway. A gateway
+// enforces only one engine, so the members of a multi-agent pack sharing a
+// gateway share one, with each member's policies scoped to its runtime.
+type policyEngines struct {
+	shared *policyEngine
+}
+
+// forPrompt returns the engine for promptName's policies, creating it on
+// first use.
+func (e *policyEngines) forPrompt(
+	ctx context.Context, ac *applyContext, promptName string,
+) (policyEngine, error) {
+	if !adaptersdk.IsMultiAgent(ac.pack) || ac.cfg.gatewaysPerAgent(ac.pack) {
+		return createPolicyEngine(ctx, ac, promptName+"_policy_engine", ac.cfg.gatewayARNFor(promptName))
+	}
+	if e.shared == nil {
+		engine, err := createPolicyEngine(ctx, ac, ac.pack.ID+"_policy_engine", ac.cfg.GatewayARN)
+		if err != nil {
+			return policyEngine{}, err
+		}
+		e.shared = &engine
+	}
+	return *e.shared, nil
+}
+
+// createPolicyEngine creates a policy engine and associates it with the
+// gateway at gatewayARN so the Cedar schema includes the gateway's
+// registered tool actions.
+func createPolicyEngine(ctx context.Context, ac *applyContext, name, gatewayARN string) (policyEngine, error) {
+	arn, id, err := ac.client.CreatePolicyEngine(ctx, name, ac.cfg)
+	if err != nil {
+		return policyEngine{}, fmt.Errorf("policy engine: %w", err)
+	}
+	if err := ac.client.AssociatePolicyEngine(ctx, arn, gatewayARN, ac.cfg); err != nil {
+		return policyEngine{}, fmt.Errorf("associate policy engine with gateway: %w", err)
+	}
+	return policyEngine{arn: arn, id: id}, nil
+}
+
+// policyPrincipalPattern returns the principal pattern scoping a prompt's
+// Cedar policies: its runtime's ARN for members of a multi-agent pack,
+// otherwise none.
+func policyPrincipalPattern(ac *applyContext, promptName string) string {
+	if !adaptersdk.IsMultiAgent(ac.pack) || !slices.Contains(agentRuntimeNames(ac.pack), promptName) {
+		return ""
+	}
+	return agentPrincipalPattern(ac.cfg, promptName)
+}
+
+// createPolicyForPrompt creates one Cedar policy per forbid block for a
+// single prompt. AWS CreatePolicy accepts only a single policy statement,
+// so multiple rules are created as separate policies on the same engine.
+// Returns the resource state on success.
 func createPolicyForPrompt(
 	ctx context.Context, ac *applyContext,
-	promptName, engineName string,
+	promptName string, engines *policyEngines,
 ) (*ResourceState, error) {
 	p := ac.pack.Prompts[promptName]
 	registeredTools := make(map[string]bool, len(ac.pack.Tools))
 	for name := range ac.pack.Tools {
 		registeredTools[name] = true
 	}
-	statements := generateCedarStatements(p.Validators, p.ToolPolicy, ac.cfg.GatewayARN, registeredTools)
+	statements := generateCedarStatements(p.Validators, p.ToolPolicy, ac.cfg.gatewayARNFor(promptName), registeredTools,
+		policyPrincipalPattern(ac, promptName))
 	if len(statements) == 0 {
 		return nil, fmt.Errorf("no Cedar rules generated for prompt %s", promptName)
 	}
 
-	engineARN, engineID, err := ac.client.CreatePolicyEngine(ctx, engineName, ac.cfg)
+	engine, err := engines.forPrompt(ctx, ac, promptName)
 	if err != nil {
-		return nil, fmt.Errorf("policy engine: %w", err)
-	}
-
-	// Associate the policy engine with the gateway so the Cedar schema
-	// includes the gateway's registered tool actions.
-	if err := ac.client.AssociatePolicyEngine(ctx, engineARN, ac.cfg); err != nil {
-		return nil, fmt.Errorf("associate policy engine with gateway: %w", err)
+		return nil, err
 	}
+	engineARN, engineID := engine.arn, engine.id
 
 	var lastPolicyARN, lastPolicyID string
 	for i, stmt := range statements {
@@ -454,7 +686,7 @@ func injectPolicyEngineARNs(cfg *Config, resources []ResourceState) {
 	var arns []string
 	for _, r := range resources {
 		if r.Type == ResTypeCedarPolicy && r.Status == ResStatusCreated {
-			if arn, ok := r.Metadata["policy_engine_arn"]; ok {
+			if arn, ok := r.Metadata["policy_engine_arn"]; ok && !slices.Contains(arns, arn) {
 				arns = append(arns, arn)
 			}
 		}
@@ -464,21 +696,6 @@ func injectPolicyEngineARNs(cfg *Config, resources []ResourceState) {
 	}
 }
 
-// applyA2AWiring runs the A2A wiring phase for multi-agent packs.
-func applyA2AWiring(
-	ctx context.Context, ac *applyContext,
-	resources []ResourceState, applyErr error,
-) ([]ResourceState, error, error) {
-	agents := adaptersdk.ExtractAgents(ac.pack)
-	wireNames := make([]string, len(agents))
-	for i, ag := range agents {
-		wireNames[i] = ag.Name + "_a2a"
-	}
-	phase := applyPhase(ctx, ac.reporter, ac.client.CreateA2AWiring, nil, ac.cfg,
-		wireNames, ResTypeA2AEndpoint, stepA2A, ac.priorMap)
-	return mergePhase(resources, applyErr, phase)
-}
-
 // injectA2AEndpoints updates the entry agent runtime with a PROMPTPACK_AGENTS
 // env var containing a JSON map of {memberName: runtimeARN}. This allows the
 // entry agent to discover and route to other members.
@@ -506,7 +723,7 @@ func injectA2AEndpoints(
 	}
 
 	msg := "Injecting A2A endpoint map on entry agent: " + entryName
-	if err := ac.reporter.Progress(msg, progressA2ADiscovery); err != nil {
+	if err := ac.reporter.Progress(msg, ac.span.start); err != nil {
 		return err
 	}
 
@@ -524,32 +741,46 @@ func injectA2AEndpoints(
 }
 
 // applyPhase creates or updates resources of a single type, reporting progress.
-// stepIndex (0–3) determines which quarter of the progress bar is used.
+// Progress advances through span as each resource is started.
 // If update is non-nil and the resource exists in priorMap, the update function
-// is called instead of create.
+// is called instead of create. If replace is non-nil and the prior resource
+// cannot be updated in place, it is replaced: deleted with replace, then
+// created. A prior resource whose delete fails is kept in state.
 func applyPhase(
 	ctx context.Context,
 	reporter *adaptersdk.ProgressReporter,
 	create createFunc,
 	update updateFunc,
+	replace replaceFunc,
 	cfg *Config,
 	names []string,
 	resType string,
-	stepIndex int,
+	span progressSpan,
 	priorMap map[string]ResourceState,
 ) applyPhaseResult {
 	var result applyPhaseResult
-	baseProgress := float64(stepIndex) * progressStepSize
 
 	for i, name := range names {
-		pct := baseProgress + float64(i)/float64(len(names)+1)*progressStepSize
-		op := resolveOp(resType, name, update, priorMap)
+		pct := span.at(i, len(names))
+		op := resolveOp(resType, name, update != nil, priorMap)
+		prior := priorMap[resourceKey(resType, name)]
+		op = resolveReplace(op, replace != nil, prior, cfg)
 
 		if err := reporter.Progress(fmt.Sprintf("%s %s: %s", op.verb, resType, name), pct); err != nil {
 			result.callbackErr = err
 			return result
 		}
 
+		if op.replaceReason != "" {
+			if err := replace(ctx, prior, op.replaceReason); err != nil {
+				deployErr := newDeployError(op.failVerb, resType, name, err)
+				_ = reporter.Error(deployErr)
+				result.resources = append(result.resources, prior)
+				result.err = combineErrors(result.err, deployErr)
+				continue
+			}
+		}
+
 		arn, opErr := execOp(ctx, &op, create, update, name, cfg)
 		if opErr != nil {
 			deployErr := newDeployError(op.failVerb, resType, name, opErr)
@@ -577,22 +808,24 @@ func applyPhase(
 
 // resourceOp holds the resolved operation details for a single resource.
 type resourceOp struct {
-	isUpdate bool
-	priorARN string
-	verb     string // "Creating" or "Updating"
-	failVerb string // "create" or "update"
-	action   deploy.Action
-	status   string
+	isUpdate      bool
+	priorARN      string
+	replaceReason string // why the prior resource is replaced, "" otherwise
+	verb          string // "Creating", "Updating", or "Replacing"
+	failVerb      string // "create", "update", or "replace"
+	action        deploy.Action
+	status        string
 }
 
 // resolveOp determines whether a resource should be created or updated.
+// Resources without update support are always created.
 func resolveOp(
 	resType, name string,
-	update updateFunc,
+	canUpdate bool,
 	priorMap map[string]ResourceState,
 ) resourceOp {
 	prior, hasPrior := priorMap[resourceKey(resType, name)]
-	if hasPrior && update != nil {
+	if hasPrior && canUpdate {
 		return resourceOp{
 			isUpdate: true, priorARN: prior.ARN,
 			verb: "Updating", failVerb: "update",
@@ -605,6 +838,31 @@ func resolveOp(
 	}
 }
 
+// resolveReplace turns the update op of prior into a replacement when the
+// phase can replace and prior cannot be updated in place to match cfg.
+func resolveReplace(op resourceOp, canReplace bool, prior ResourceState, cfg *Config) resourceOp {
+	if !canReplace || !op.isUpdate {
+		return op
+	}
+	reason := replaceReason(prior, cfg)
+	if reason == "" {
+		return op
+	}
+	return resourceOp{
+		replaceReason: reason,
+		verb:          "Replacing", failVerb: "replace",
+		action: ActionReplace, status: ResStatusReplaced,
+	}
+}
+
+// replaceWith returns the replaceFunc that deletes prior resources through
+// ac's client, as memory replacements do.
+func (ac *applyContext) replaceWith() replaceFunc {
+	return func(ctx context.Context, prior ResourceState, reason string) error {
+		return deleteForReplace(ctx, ac.reporter, ac.client, prior, reason, ac.cfg.IncludeAdopted)
+	}
+}
+
 // execOp runs the appropriate create or update function.
 func execOp(
 	ctx context.Context, op *resourceOp,
@@ -696,15 +954,30 @@ func sortedKeys[V any](m map[string]V) []string {
 }
 
 // createMemoryResource creates a memory resource if configured, injecting
-// the resulting ARN into runtime env vars.
+// the resulting ARN into runtime env vars. A prior memory whose settings
+// cannot change in place is deleted first and created again; if that
+// delete fails the prior memory is kept in state.
 func createMemoryResource(
 	ctx context.Context,
 	reporter *adaptersdk.ProgressReporter,
 	client awsClient,
 	cfg *Config,
 	pack *prompt.Pack,
+	priorMap map[string]ResourceState,
 ) (*ResourceState, error) {
 	memName := pack.ID + "_memory"
+	action, status := deploy.ActionCreate, ResStatusCreated
+
+	if prior, ok := priorMap[resourceKey(ResTypeMemory, memName)]; ok {
+		if reason := replaceReason(prior, cfg); reason != "" {
+			if err := deleteForReplace(ctx, reporter, client, prior, reason, cfg.IncludeAdopted); err != nil {
+				deployErr := newDeployError("replace", ResTypeMemory, memName, err)
+				_ = reporter.Error(deployErr)
+				return &prior, deployErr
+			}
+			action, status = ActionReplace, ResStatusReplaced
+		}
+	}
 
 	if err := reporter.Progress("Creating memory: "+memName, 0); err != nil {
 		return nil, err
@@ -724,14 +997,15 @@ func createMemoryResource(
 
 	if err := reporter.Resource(&deploy.ResourceResult{
 		Type: ResTypeMemory, Name: memName,
-		Action: deploy.ActionCreate, Status: ResStatusCreated,
+		Action: action, Status: status,
 		Detail: arn,
 	}); err != nil {
 		return nil, err
 	}
 
 	return &ResourceState{
-		Type: ResTypeMemory, Name: memName, ARN: arn, Status: "created",
+		Type: ResTypeMemory, Name: memName, ARN: arn, Status: status,
+		Metadata: memoryMetadata(cfg),
 	}, nil
 }
 
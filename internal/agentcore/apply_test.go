@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 	"testing"
@@ -113,6 +114,7 @@ func multiAgentPack() string {
 				"name":            "Worker",
 				"system_template": "You work.",
 				"version":         "v1.0.0",
+				"tools":           []string{"lookup"},
 			},
 		},
 		"agents": map[string]any{
@@ -198,6 +200,22 @@ func (c *failingAWSClient) UpdateRuntime(ctx context.Context, arn string, name s
 	return c.simulatedAWSClient.UpdateRuntime(ctx, arn, name, cfg)
 }
 
+func (c *failingAWSClient) DeployRuntimeEndpoint(
+	ctx context.Context, runtimeARN, endpointName string, cfg *Config,
+) (string, string, error) {
+	if c.failOn["runtime_endpoint"] {
+		return "", "", fmt.Errorf("simulated runtime endpoint failure for %s", endpointName)
+	}
+	return c.simulatedAWSClient.DeployRuntimeEndpoint(ctx, runtimeARN, endpointName, cfg)
+}
+
+func (c *failingAWSClient) PutLogGroup(ctx context.Context, name string, cfg *Config) (string, error) {
+	if c.failOn["log_group"] {
+		return "", fmt.Errorf("simulated log group failure for %s", name)
+	}
+	return c.simulatedAWSClient.PutLogGroup(ctx, name, cfg)
+}
+
 func (c *failingAWSClient) CreateGatewayTool(ctx context.Context, name string, cfg *Config) (string, error) {
 	if c.failOn["tool_gateway"] {
 		return "", fmt.Errorf("simulated gateway tool failure for %s", name)
@@ -2049,6 +2067,103 @@ func (c *trackingAWSClient) CreateCedarPolicy(
 	return c.simulatedAWSClient.CreateCedarPolicy(ctx, engineID, name, stmt, cfg)
 }
 
+// policyRecordingClient wraps simulatedAWSClient to record the policy
+// engines and Cedar statements Apply creates.
+type policyRecordingClient struct {
+	simulatedAWSClient
+	engines    []string
+	statements map[string]string
+}
+
+func (c *policyRecordingClient) CreatePolicyEngine(
+	ctx context.Context, name string, cfg *Config,
+) (string, string, error) {
+	c.engines = append(c.engines, name)
+	return c.simulatedAWSClient.CreatePolicyEngine(ctx, name, cfg)
+}
+
+func (c *policyRecordingClient) CreateCedarPolicy(
+	ctx context.Context, engineID string, name string, stmt string, cfg *Config,
+) (string, string, error) {
+	c.statements[name] = stmt
+	return c.simulatedAWSClient.CreateCedarPolicy(ctx, engineID, name, stmt, cfg)
+}
+
+// multiAgentPackWithToolPolicies returns a multi-agent pack where each
+// member blocks a different tool.
+func multiAgentPackWithToolPolicies() string {
+	member := func(id, blocked string) map[string]any {
+		return map[string]any{
+			"id": id, "system_template": id, "tools": []string{"exec", "search"},
+			"tool_policy": map[string]any{"blocklist": []string{blocked}},
+		}
+	}
+	p := map[string]any{
+		"id":      "multipack",
+		"version": "v1.0.0",
+		"tools": map[string]any{
+			"exec":   map[string]any{"name": "exec", "description": "run commands"},
+			"search": map[string]any{"name": "search", "description": "search the web"},
+		},
+		"prompts": map[string]any{
+			"router": member("router", "exec"),
+			"worker": member("worker", "search"),
+		},
+		"agents": map[string]any{
+			"entry": "router",
+			"members": map[string]any{
+				"router": map[string]any{"description": "Routes requests"},
+				"worker": map[string]any{"description": "Does the work"},
+			},
+		},
+	}
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+func TestApply_MultiAgentPoliciesShareScopedEngine(t *testing.T) {
+	client := &policyRecordingClient{statements: map[string]string{}}
+	provider := &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			client.simulatedAWSClient = *newSimulatedAWSClient(cfg.Region)
+			return client, nil
+		},
+	}
+	_, raw, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     multiAgentPackWithToolPolicies(),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if len(client.engines) != 1 || client.engines[0] != "multipack_policy_engine" {
+		t.Errorf("engines = %v, want one shared engine", client.engines)
+	}
+	for name, tool := range map[string]string{"router": "exec", "worker": "search"} {
+		stmt := client.statements[name+"_policy_0"]
+		if !strings.Contains(stmt, `"`+tool+"___"+tool+`"`) ||
+			!strings.Contains(stmt, ":runtime/"+name+"-*") {
+			t.Errorf("%s statement = %q, want %s blocked for its runtime only", name, stmt, tool)
+		}
+	}
+
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	engineIDs := map[string]bool{}
+	for _, r := range state.Resources {
+		if r.Type == ResTypeCedarPolicy {
+			engineIDs[r.Metadata["policy_engine_id"]] = true
+		}
+	}
+	if len(engineIDs) != 1 {
+		t.Errorf("policy engine IDs = %v, want one", engineIDs)
+	}
+}
+
 // --- resource tagging tests ---
 
 // validConfigWithTags returns a deploy config with user-defined tags.
@@ -2371,3 +2486,17 @@ func TestApply_DryRun_MissingArenaConfig(t *testing.T) {
 		t.Errorf("error = %q, want 'arena_config is required'", err.Error())
 	}
 }
+
+func TestPhaseSpan_CoversApplyOrder(t *testing.T) {
+	n := len(applyOrder)
+	first, last := phaseSpan(0, n), phaseSpan(n-1, n)
+	if first.start != 0 {
+		t.Errorf("first phase starts at %v, want 0", first.start)
+	}
+	if end := last.start + last.size; math.Abs(end-1) > 1e-9 {
+		t.Errorf("last phase ends at %v, want 1", end)
+	}
+	if got := last.at(2, 2); got <= last.start || got >= 1 {
+		t.Errorf("last resource progress = %v, want inside the last phase", got)
+	}
+}
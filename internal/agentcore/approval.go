@@ -0,0 +1,254 @@
+package agentcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+)
+
+// JSON-RPC methods for the approval gate between Plan and Apply. They
+// extend the standard adaptersdk method set.
+const (
+	MethodApprove          = "approve"
+	MethodPendingApprovals = "pending_approvals"
+)
+
+// Approval timeout bounds and default.
+const (
+	defaultApprovalTimeout = 15 * time.Minute
+	minApprovalTimeout     = 10 * time.Second
+	maxApprovalTimeout     = 24 * time.Hour
+)
+
+// planIDLen is the number of hex characters kept from the plan hash.
+const planIDLen = 12
+
+// ResStatusPendingApproval marks a planned change that Apply is holding
+// until the plan is approved.
+const ResStatusPendingApproval = "pending_approval"
+
+// ApprovalConfig makes Apply wait for an explicit approval of its plan
+// before it changes anything in AWS.
+type ApprovalConfig struct {
+	Required bool   `json:"required"`
+	Timeout  string `json:"timeout,omitempty"`
+}
+
+// requiresApproval reports whether Apply must wait for approval.
+func (c *Config) requiresApproval() bool {
+	return c.Approval != nil && c.Approval.Required
+}
+
+// approvalTimeout returns the configured approval timeout, falling back to
+// the default for unset or invalid values.
+func (c *Config) approvalTimeout() time.Duration {
+	if c.Approval != nil {
+		if d, err := time.ParseDuration(c.Approval.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultApprovalTimeout
+}
+
+// validateApproval checks the approval timeout against its bounds.
+func validateApproval(a *ApprovalConfig) []string {
+	if a == nil {
+		return nil
+	}
+	if _, err := parseBoundedDuration(
+		"approval.timeout", a.Timeout, minApprovalTimeout, maxApprovalTimeout,
+	); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+// ApproveRequest is the params object of an approve call. Approved false
+// rejects the plan.
+type ApproveRequest struct {
+	PlanID   string `json:"plan_id"`
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ApproveResponse is the result of an approve call.
+type ApproveResponse struct {
+	PlanID   string `json:"plan_id"`
+	Approved bool   `json:"approved"`
+}
+
+// PendingApprovalsRequest is the params object of a pending_approvals call.
+type PendingApprovalsRequest struct{}
+
+// PendingApproval is a plan that an Apply is waiting on.
+type PendingApproval struct {
+	PlanID    string                  `json:"plan_id"`
+	Summary   string                  `json:"summary"`
+	Changes   []deploy.ResourceChange `json:"changes"`
+	ExpiresAt time.Time               `json:"expires_at"`
+}
+
+// PendingApprovalsResponse is the result of a pending_approvals call.
+type PendingApprovalsResponse struct {
+	Plans []PendingApproval `json:"plans"`
+}
+
+// pendingPlan is a plan waiting for a decision.
+type pendingPlan struct {
+	info     PendingApproval
+	decision chan ApproveRequest
+}
+
+// approvalGate holds the plans that Apply calls are waiting on. The zero
+// value is ready to use.
+type approvalGate struct {
+	mu      sync.Mutex
+	pending map[string]*pendingPlan
+}
+
+// wait registers plan and blocks until it is approved, rejected, times out,
+// or ctx is done.
+func (g *approvalGate) wait(ctx context.Context, info PendingApproval, timeout time.Duration) error {
+	pp := &pendingPlan{info: info, decision: make(chan ApproveRequest, 1)}
+	g.mu.Lock()
+	if _, dup := g.pending[info.PlanID]; dup {
+		g.mu.Unlock()
+		return fmt.Errorf("plan %s is already awaiting approval", info.PlanID)
+	}
+	if g.pending == nil {
+		g.pending = map[string]*pendingPlan{}
+	}
+	g.pending[info.PlanID] = pp
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.pending, info.PlanID)
+		g.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case d := <-pp.decision:
+		if d.Approved {
+			return nil
+		}
+		if d.Reason != "" {
+			return fmt.Errorf("plan %s was rejected: %s", info.PlanID, d.Reason)
+		}
+		return fmt.Errorf("plan %s was rejected", info.PlanID)
+	case <-timer.C:
+		return fmt.Errorf("plan %s was not approved within %s", info.PlanID, timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// decide delivers a decision to the waiting plan.
+func (g *approvalGate) decide(req *ApproveRequest) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	pp, ok := g.pending[req.PlanID]
+	if !ok {
+		return fmt.Errorf("no plan %q is awaiting approval", req.PlanID)
+	}
+	select {
+	case pp.decision <- *req:
+		return nil
+	default:
+		return fmt.Errorf("plan %s has already been decided", req.PlanID)
+	}
+}
+
+// list returns the waiting plans ordered by plan ID.
+func (g *approvalGate) list() []PendingApproval {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	plans := make([]PendingApproval, 0, len(g.pending))
+	for _, pp := range g.pending {
+		plans = append(plans, pp.info)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].PlanID < plans[j].PlanID })
+	return plans
+}
+
+// planID identifies a plan by the request it was computed from, so a
+// re-sent request maps to the same ID.
+func planID(req *deploy.PlanRequest) string {
+	h := sha256.New()
+	for _, s := range []string{req.PackJSON, req.DeployConfig, req.ArenaConfig, req.PriorState} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:planIDLen]
+}
+
+// awaitApproval emits the computed plan as pending resource events and
+// waits for an approve call. Nothing in AWS is touched before it returns
+// nil.
+func (p *Provider) awaitApproval(
+	ctx context.Context, req *deploy.PlanRequest, callback deploy.ApplyCallback, cfg *Config,
+) error {
+	plan, err := p.Plan(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	reporter := adaptersdk.NewProgressReporter(callback)
+	for i := range plan.Changes {
+		c := plan.Changes[i]
+		if c.Action == deploy.ActionNoChange {
+			continue
+		}
+		if err := reporter.Resource(&deploy.ResourceResult{
+			Type: c.Type, Name: c.Name, Action: c.Action,
+			Status: ResStatusPendingApproval, Detail: c.Detail,
+		}); err != nil {
+			return err
+		}
+	}
+
+	timeout := cfg.approvalTimeout()
+	info := PendingApproval{
+		PlanID:    planID(req),
+		Summary:   plan.Summary,
+		Changes:   plan.Changes,
+		ExpiresAt: time.Now().Add(timeout).UTC(),
+	}
+	if err := reporter.Progress(
+		fmt.Sprintf("Awaiting approval of plan %s (%s)", info.PlanID, plan.Summary), 0,
+	); err != nil {
+		return err
+	}
+	if err := p.approvals.wait(ctx, info, timeout); err != nil {
+		return err
+	}
+	return reporter.Progress(fmt.Sprintf("Plan %s approved", info.PlanID), 0)
+}
+
+// Approve approves or rejects a plan that an Apply is waiting on.
+func (p *Provider) Approve(_ context.Context, req *ApproveRequest) (*ApproveResponse, error) {
+	if req.PlanID == "" {
+		return nil, errors.New("agentcore: plan_id is required")
+	}
+	if err := p.approvals.decide(req); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+	return &ApproveResponse{PlanID: req.PlanID, Approved: req.Approved}, nil
+}
+
+// PendingApprovals lists the plans that Apply calls are waiting on.
+func (p *Provider) PendingApprovals(
+	_ context.Context, _ *PendingApprovalsRequest,
+) (*PendingApprovalsResponse, error) {
+	return &PendingApprovalsResponse{Plans: p.approvals.list()}, nil
+}
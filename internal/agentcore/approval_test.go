@@ -0,0 +1,193 @@
+package agentcore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+)
+
+func TestValidateApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *ApprovalConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"default timeout", &ApprovalConfig{Required: true}, false},
+		{"valid timeout", &ApprovalConfig{Required: true, Timeout: "30m"}, false},
+		{"too short", &ApprovalConfig{Required: true, Timeout: "1s"}, true},
+		{"invalid", &ApprovalConfig{Required: true, Timeout: "soon"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateApproval(tt.cfg); (len(got) > 0) != tt.wantErr {
+				t.Errorf("errors = %v, wantErr %v", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApprovalGate_Timeout(t *testing.T) {
+	var g approvalGate
+	err := g.wait(context.Background(), PendingApproval{PlanID: "p1"}, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not approved within") {
+		t.Fatalf("err = %v, want a timeout", err)
+	}
+	if len(g.list()) != 0 {
+		t.Error("timed-out plan still pending")
+	}
+}
+
+func TestApprove_UnknownPlan(t *testing.T) {
+	p := newSimulatedProvider()
+	if _, err := p.Approve(context.Background(), &ApproveRequest{PlanID: "nope", Approved: true}); err == nil {
+		t.Error("expected an error for a plan nobody is waiting on")
+	}
+}
+
+// approvalProvider returns a simulated provider that counts AWS client
+// creations, so tests can tell whether Apply touched AWS.
+func approvalProvider() (*Provider, *atomic.Int32) {
+	var clients atomic.Int32
+	p := newSimulatedProvider()
+	p.awsClientFunc = func(_ context.Context, cfg *Config) (awsClient, error) {
+		clients.Add(1)
+		return newSimulatedAWSClient(cfg.Region), nil
+	}
+	return p, &clients
+}
+
+func approvalRequest(t *testing.T) *deploy.PlanRequest {
+	t.Helper()
+	return &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: strings.TrimSuffix(validConfig(t), "}") + `,"approval":{"required":true}}`,
+		ArenaConfig:  validArenaConfigJSON,
+	}
+}
+
+// waitForPending polls until one plan is awaiting approval.
+func waitForPending(t *testing.T, p *Provider) PendingApproval {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, _ := p.PendingApprovals(context.Background(), &PendingApprovalsRequest{})
+		if len(resp.Plans) == 1 {
+			return resp.Plans[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("no plan became pending")
+	return PendingApproval{}
+}
+
+type applyOutcome struct {
+	events []deploy.ApplyEvent
+	state  string
+	err    error
+}
+
+func applyInBackground(t *testing.T, p *Provider, req *deploy.PlanRequest) <-chan applyOutcome {
+	t.Helper()
+	done := make(chan applyOutcome, 1)
+	go func() {
+		events, state, err := collectEvents(t, p, req)
+		done <- applyOutcome{events, state, err}
+	}()
+	return done
+}
+
+func TestApply_Approval_Approved(t *testing.T) {
+	p, clients := approvalProvider()
+	req := approvalRequest(t)
+	done := applyInBackground(t, p, req)
+
+	pending := waitForPending(t, p)
+	if pending.PlanID != planID(req) || !strings.Contains(pending.Summary, "1 to create") {
+		t.Errorf("pending = %+v", pending)
+	}
+	if clients.Load() != 0 {
+		t.Fatal("AWS client created before approval")
+	}
+	if _, err := p.Approve(context.Background(), &ApproveRequest{PlanID: pending.PlanID, Approved: true}); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	out := <-done
+	if out.err != nil {
+		t.Fatalf("Apply: %v", out.err)
+	}
+	var statuses []string
+	for _, ev := range out.events {
+		if ev.Type == "resource" && ev.Resource != nil {
+			statuses = append(statuses, ev.Resource.Status)
+		}
+	}
+	if strings.Join(statuses, ",") != ResStatusPendingApproval+","+ResStatusCreated {
+		t.Errorf("resource statuses = %v, want the pending plan then the created runtime", statuses)
+	}
+}
+
+func TestApply_Approval_Rejected(t *testing.T) {
+	p, clients := approvalProvider()
+	done := applyInBackground(t, p, approvalRequest(t))
+
+	pending := waitForPending(t, p)
+	if _, err := p.Approve(context.Background(), &ApproveRequest{
+		PlanID: pending.PlanID, Reason: "change freeze",
+	}); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	out := <-done
+	if out.err == nil || !strings.Contains(out.err.Error(), "rejected: change freeze") {
+		t.Fatalf("err = %v, want a rejection", out.err)
+	}
+	if clients.Load() != 0 {
+		t.Error("AWS client created for a rejected plan")
+	}
+}
+
+func TestServeIO_ApprovalRunsInBackground(t *testing.T) {
+	p, _ := approvalProvider()
+	req := approvalRequest(t)
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeIO(p, inR, outW)
+		_ = outW.Close()
+	}()
+
+	_, _ = io.WriteString(inW, jsonRPCRequest(adaptersdk.MethodApply, 1, req))
+	pending := waitForPending(t, p)
+	_, _ = io.WriteString(inW, jsonRPCRequest(MethodApprove, 2, ApproveRequest{PlanID: pending.PlanID, Approved: true}))
+	_ = inW.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(outR)
+	for scanner.Scan() {
+		var resp jsonRPCResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("parse response %q: %v", scanner.Text(), err)
+		}
+		if resp.Error != nil {
+			t.Errorf("response %s error: %s", resp.ID, resp.Error.Message)
+		}
+		ids = append(ids, string(resp.ID))
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("ServeIO: %v", err)
+	}
+	if strings.Join(ids, ",") != "2,1" {
+		t.Errorf("response ids = %v, want the approve answered before the apply", ids)
+	}
+}
@@ -12,6 +12,13 @@ type ArenaConfig struct {
 	MCPServers      []ArenaMCPServer          `json:"mcp_servers,omitempty"`
 	LoadedProviders map[string]*ArenaProvider `json:"loaded_providers,omitempty"`
 	ProviderSpecs   map[string]*ArenaProvider `json:"provider_specs,omitempty"`
+
+	// MinAdapterVersion is the oldest adapter release the calling PromptKit
+	// core works with.
+	MinAdapterVersion string `json:"min_adapter_version,omitempty"`
+
+	// Variables supplies the ${var.*} placeholders of the deploy config.
+	Variables map[string]string `json:"variables,omitempty"`
 }
 
 // ArenaProvider describes a provider from the arena config.
@@ -117,6 +124,9 @@ func parseArenaConfig(raw string) (*ArenaConfig, error) {
 	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
 		return nil, fmt.Errorf("invalid arena_config JSON: %w", err)
 	}
+	if err := checkMinAdapterVersion(cfg.MinAdapterVersion); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
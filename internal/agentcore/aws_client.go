@@ -6,10 +6,17 @@ import "context"
 type awsClient interface {
 	CreateRuntime(ctx context.Context, name string, cfg *Config) (arn string, err error)
 	UpdateRuntime(ctx context.Context, arn string, name string, cfg *Config) (string, error)
+	DeployRuntimeEndpoint(ctx context.Context, runtimeARN string, endpointName string, cfg *Config) (
+		arn string, version string, err error,
+	)
+	PinRuntimeEndpoint(ctx context.Context, runtimeARN, endpointName, version string, cfg *Config) (
+		arn string, err error,
+	)
 	CreateGatewayTool(ctx context.Context, name string, cfg *Config) (arn string, err error)
 	CreateA2AWiring(ctx context.Context, name string, cfg *Config) (arn string, err error)
 	CreateEvaluator(ctx context.Context, name string, cfg *Config) (arn string, err error)
 	CreateOnlineEvalConfig(ctx context.Context, name string, cfg *Config) (arn string, err error)
+	UpdateOnlineEvalConfig(ctx context.Context, arn string, name string, cfg *Config) (string, error)
 	CreateMemory(ctx context.Context, name string, cfg *Config) (arn string, err error)
 	CreatePolicyEngine(ctx context.Context, name string, cfg *Config) (
 		arn string, engineID string, err error,
@@ -17,8 +24,14 @@ type awsClient interface {
 	CreateCedarPolicy(ctx context.Context, engineID string, name string,
 		cedarStatement string, cfg *Config) (arn string, policyID string, err error,
 	)
-	AssociatePolicyEngine(ctx context.Context, policyEngineARN string, cfg *Config) error
+	CreateInferenceProfile(ctx context.Context, name, copyFrom string, cfg *Config) (arn string, err error)
+	AssociatePolicyEngine(ctx context.Context, policyEngineARN, gatewayARN string, cfg *Config) error
 	UploadCodePackage(ctx context.Context, zipData []byte, bucket, key string) error
+	PutLogGroup(ctx context.Context, name string, cfg *Config) (arn string, err error)
+	PutEvalAlert(ctx context.Context, name, onlineEvalARN string, cfg *Config) (*evalAlertDeployment, error)
+	PutLogsQuery(ctx context.Context, def logsQueryDefinition, cfg *Config) (arn string, err error)
+	CreateIdentityProvider(ctx context.Context, name string, cfg *Config) (arn string, err error)
+	UpdateIdentityProvider(ctx context.Context, arn string, name string, cfg *Config) (string, error)
 }
 
 // resourceDestroyer abstracts resource deletion so that real AWS calls
@@ -30,9 +43,28 @@ type resourceDestroyer interface {
 	DeleteResource(ctx context.Context, res ResourceState) error
 }
 
+// orphanScanner is implemented by destroyers that can look for the pack's
+// resources that are still in the account after Destroy.
+type orphanScanner interface {
+	// ScanOrphans returns the resources for packID that still exist.
+	// resources is the state that was destroyed. A non-nil error may
+	// accompany partial results.
+	ScanOrphans(ctx context.Context, packID string, resources []ResourceState) ([]orphanResource, error)
+}
+
 // resourceChecker abstracts resource health checks.
 type resourceChecker interface {
 	// CheckResource returns the health status of a single resource.
 	// Returns one of "healthy", "unhealthy", or "missing".
 	CheckResource(ctx context.Context, res ResourceState) (string, error)
 }
+
+// resourceDetailChecker is implemented by checkers that can explain a
+// non-healthy status, such as which gateway target is missing. Status
+// reports the detail alongside the resource's health.
+type resourceDetailChecker interface {
+	// CheckResourceDetail returns the health status of a single resource
+	// plus a human-readable detail, which is empty when there is nothing
+	// to add.
+	CheckResourceDetail(ctx context.Context, res ResourceState) (status, detail string, err error)
+}
@@ -4,27 +4,20 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/AltairaLabs/PromptKit/runtime/evals"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
-// pollInterval is the delay between status checks when waiting for a
-// resource to become ready.
-const pollInterval = 5 * time.Second
-
-// maxPollAttempts limits how long we wait for a resource to become ready.
-const maxPollAttempts = 60
-
 // listPageSize is the MaxResults value used when listing resources via
 // the AgentCore control-plane API.
 const listPageSize = 100
@@ -36,36 +29,71 @@ const adoptedPlaceholder = "adopted"
 // realAWSClient implements awsClient, resourceDestroyer, and resourceChecker
 // using the real AWS Bedrock AgentCore control-plane SDK.
 type realAWSClient struct {
-	client     *bedrockagentcorecontrol.Client
-	logsClient *cloudwatchlogs.Client
-	s3Client   *s3.Client
-	cfg        *Config
+	client        *bedrockagentcorecontrol.Client
+	bedrockClient *bedrock.Client
+	logsClient    *cloudwatchlogs.Client
+	s3Client      *s3.Client
+	lambdaClient  *lambda.Client
+	iam           *iamRoleReader
+	cfg           *Config
+
+	// dataPlane signs the data-plane requests that probe A2A agent cards.
+	dataPlane *sigV4Client
+
+	// gateways caches the gateways CreateGatewayTool lazily creates on the
+	// first tool of each, keyed by the agent owning it or "" for the shared
+	// gateway, so later targets reuse them.
+	gateways map[string]*toolGateway
+
+	// waitProgress receives status lines from waitFor* polling loops.
+	waitProgress waitProgressFunc
+
+	// poll paces the waitFor* polling loops.
+	poll poller
+
+	// adopted holds the ARNs of existing resources adopted during Apply.
+	adopted map[string]bool
 
-	// gatewayID caches the gateway identifier so that CreateGatewayTool can
-	// lazily create the parent gateway on the first tool and reuse it for
-	// subsequent targets.
-	gatewayID   string
-	gatewayARN  string
-	gatewayName string
+	// reconciled holds, by ARN, the tags merged onto adopted resources.
+	reconciled map[string]map[string]string
+
+	// callerARN is the AWS identity the client calls as, from the
+	// pre-flight STS check.
+	callerARN string
+
+	// calls counts the client's AWS API calls, the STS check included.
+	calls *apiCallRecorder
+
+	// detachLocks holds a *sync.Mutex per online eval config ID, held
+	// while an evaluator is detached from the config.
+	detachLocks sync.Map
 }
 
 // newRealAWSClient builds a realAWSClient from the Config.
 func newRealAWSClient(ctx context.Context, cfg *Config) (*realAWSClient, error) {
-	awsCfg, err := awscfg.LoadDefaultConfig(ctx, awscfg.WithRegion(cfg.Region))
+	awsCfg, err := loadAWSConfig(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("load AWS config: %w", err)
+		return nil, err
+	}
+	calls := newAPICallRecorder()
+	awsCfg.APIOptions = append(awsCfg.APIOptions, calls.register)
+	if cfg.EnhancedDryRun {
+		awsCfg.APIOptions = append(awsCfg.APIOptions, registerReadOnlyGuard)
 	}
 
 	// Pre-flight check: verify the caller's AWS account matches the account
 	// in the runtime_role_arn to catch misconfigurations before any Bedrock
 	// API calls are made.
 	arnAccount := extractAccountFromARN(cfg.RuntimeRoleARN)
+	var callerARN string
 	if arnAccount != "" {
-		identity, err := sts.NewFromConfig(awsCfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		stsClient := sts.NewFromConfig(awsCfg, func(o *sts.Options) { o.BaseEndpoint = cfg.baseEndpoint(EndpointSTS) })
+		identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 		if err != nil {
 			return nil, fmt.Errorf("STS GetCallerIdentity: %w", err)
 		}
 		callerAccount := aws.ToString(identity.Account)
+		callerARN = aws.ToString(identity.Arn)
 		if callerAccount != arnAccount {
 			return nil, fmt.Errorf(
 				"AWS caller account %s does not match runtime_role_arn account %s"+
@@ -75,15 +103,32 @@ func newRealAWSClient(ctx context.Context, cfg *Config) (*realAWSClient, error)
 		}
 	}
 
-	client := bedrockagentcorecontrol.NewFromConfig(awsCfg)
-	logsClient := cloudwatchlogs.NewFromConfig(awsCfg)
-	s3Client := s3.NewFromConfig(awsCfg)
+	client := bedrockagentcorecontrol.NewFromConfig(awsCfg, func(o *bedrockagentcorecontrol.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointBedrockAgentCoreControl)
+	})
+	logsClient := cloudwatchlogs.NewFromConfig(awsCfg, func(o *cloudwatchlogs.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointLogs)
+	})
+	s3Client := s3.NewFromConfig(awsCfg, func(o *s3.Options) { o.BaseEndpoint = cfg.baseEndpoint(EndpointS3) })
+	bedrockClient := bedrock.NewFromConfig(awsCfg, func(o *bedrock.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointBedrock)
+	})
+	lambdaClient := lambda.NewFromConfig(awsCfg, func(o *lambda.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointLambda)
+	})
 	return &realAWSClient{
-		client: client, logsClient: logsClient,
-		s3Client: s3Client, cfg: cfg,
+		client: client, bedrockClient: bedrockClient,
+		logsClient: logsClient, s3Client: s3Client, lambdaClient: lambdaClient, cfg: cfg,
+		iam: newIAMRoleReader(awsCfg, cfg), dataPlane: newSigV4Client(awsCfg),
+		poll: newPoller(cfg), callerARN: callerARN, calls: calls,
 	}, nil
 }
 
+// apiCalls implements apiCallReporter.
+func (c *realAWSClient) apiCalls() []apiCallStats {
+	return c.calls.apiCalls()
+}
+
 // newRealAWSClientFactory is the awsClientFactory used by NewProvider.
 func newRealAWSClientFactory(ctx context.Context, cfg *Config) (awsClient, error) {
 	return newRealAWSClient(ctx, cfg)
@@ -101,12 +146,6 @@ func newRealCheckerFactory(ctx context.Context, cfg *Config) (resourceChecker, e
 
 // ---------- awsClient implementation ----------
 
-// isConflictError returns true if the error indicates a 409 Conflict (resource
-// already exists).
-func isConflictError(err error) bool {
-	return err != nil && strings.Contains(err.Error(), "ConflictException")
-}
-
 // findRuntimeByName lists runtimes and returns the ARN of one matching name.
 func (c *realAWSClient) findRuntimeByName(ctx context.Context, name string) (string, error) {
 	out, err := c.client.ListAgentRuntimes(ctx, &bedrockagentcorecontrol.ListAgentRuntimesInput{
@@ -120,7 +159,7 @@ func (c *realAWSClient) findRuntimeByName(ctx context.Context, name string) (str
 			return aws.ToString(rt.AgentRuntimeArn), nil
 		}
 	}
-	return "", fmt.Errorf("runtime %q not found", name)
+	return "", fmt.Errorf("runtime %q %w", name, errNotListed)
 }
 
 // findGatewayByName lists gateways and returns the ID and ARN of one matching name.
@@ -144,7 +183,7 @@ func (c *realAWSClient) findGatewayByName(ctx context.Context, name string) (id,
 			return gwID, aws.ToString(detail.GatewayArn), nil
 		}
 	}
-	return "", "", fmt.Errorf("gateway %q not found", name)
+	return "", "", fmt.Errorf("gateway %q %w", name, errNotListed)
 }
 
 // findEvaluatorByName lists evaluators and returns the ARN of one matching name.
@@ -160,7 +199,7 @@ func (c *realAWSClient) findEvaluatorByName(ctx context.Context, name string) (s
 			return aws.ToString(ev.EvaluatorArn), nil
 		}
 	}
-	return "", fmt.Errorf("evaluator %q not found", name)
+	return "", fmt.Errorf("evaluator %q %w", name, errNotListed)
 }
 
 // findOnlineEvalConfigByName lists online eval configs and returns the ARN of one matching name.
@@ -176,7 +215,7 @@ func (c *realAWSClient) findOnlineEvalConfigByName(ctx context.Context, name str
 			return aws.ToString(cfg.OnlineEvaluationConfigArn), nil
 		}
 	}
-	return "", fmt.Errorf("online eval config %q not found", name)
+	return "", fmt.Errorf("online eval config %q %w", name, errNotListed)
 }
 
 // findPolicyEngineByName lists policy engines and returns the ARN and ID of one matching name.
@@ -192,7 +231,7 @@ func (c *realAWSClient) findPolicyEngineByName(ctx context.Context, name string)
 			return aws.ToString(pe.PolicyEngineArn), aws.ToString(pe.PolicyEngineId), nil
 		}
 	}
-	return "", "", fmt.Errorf("policy engine %q not found", name)
+	return "", "", fmt.Errorf("policy engine %q %w", name, errNotListed)
 }
 
 // buildRuntimeArtifact returns the CodeConfiguration artifact referencing the
@@ -203,6 +242,7 @@ func buildRuntimeArtifact(cfg *Config) types.AgentRuntimeArtifact {
 	packID := cfg.ResourceTags[TagKeyPackID]
 	version := cfg.ResourceTags[TagKeyVersion]
 	key := codeDeployS3Key(packID, version)
+	layout := cfg.codeLayout()
 	return &types.AgentRuntimeArtifactMemberCodeConfiguration{
 		Value: types.CodeConfiguration{
 			Code: &types.CodeMemberS3{
@@ -211,8 +251,8 @@ func buildRuntimeArtifact(cfg *Config) types.AgentRuntimeArtifact {
 					Prefix: aws.String(key),
 				},
 			},
-			EntryPoint: []string{codeDeployEntryPoint},
-			Runtime:    types.AgentManagedRuntimeTypePython313,
+			EntryPoint: []string{layout.entryPoint},
+			Runtime:    layout.runtime,
 		},
 	}
 }
@@ -229,10 +269,29 @@ func (c *realAWSClient) UploadCodePackage(
 	if err != nil {
 		return fmt.Errorf("S3 PutObject %s/%s: %w", bucket, key, err)
 	}
-	log.Printf("agentcore: uploaded code package to s3://%s/%s (%d bytes)", bucket, key, len(zipData))
+	logf("agentcore: uploaded code package to s3://%s/%s (%d bytes)", bucket, key, len(zipData))
+	return nil
+}
+
+// UploadChangeManifest uploads an Apply's change manifest to S3.
+func (c *realAWSClient) UploadChangeManifest(ctx context.Context, data []byte, bucket, key string) error {
+	_, err := c.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("S3 PutObject %s/%s: %w", bucket, key, err)
+	}
 	return nil
 }
 
+// principal returns the ARN of the AWS identity the client calls as.
+func (c *realAWSClient) principal() string {
+	return c.callerARN
+}
+
 // CreateRuntime provisions an AgentCore runtime via the AWS API and polls
 // until it reaches READY status. On conflict (409), adopts the existing runtime.
 func (c *realAWSClient) CreateRuntime(
@@ -240,14 +299,13 @@ func (c *realAWSClient) CreateRuntime(
 ) (string, error) {
 	envVars := runtimeEnvVarsForAgent(cfg, name)
 	artifact := buildRuntimeArtifact(cfg)
+	awsName := cfg.awsName(name)
 
 	input := &bedrockagentcorecontrol.CreateAgentRuntimeInput{
-		AgentRuntimeName:     aws.String(name),
+		AgentRuntimeName:     aws.String(awsName),
 		RoleArn:              aws.String(cfg.RuntimeRoleARN),
 		AgentRuntimeArtifact: artifact,
-		NetworkConfiguration: &types.NetworkConfiguration{
-			NetworkMode: types.NetworkModePublic,
-		},
+		NetworkConfiguration: buildNetworkConfiguration(cfg),
 	}
 	if proto := resolveServerProtocol(cfg); proto != "" {
 		input.ProtocolConfiguration = &types.ProtocolConfiguration{
@@ -260,15 +318,25 @@ func (c *realAWSClient) CreateRuntime(
 	if authCfg := buildAuthorizerConfig(cfg); authCfg != nil {
 		input.AuthorizerConfiguration = authCfg
 	}
+	if lc := buildLifecycleConfiguration(cfg); lc != nil {
+		input.LifecycleConfiguration = lc
+	}
 	if tags := tagsWithAgent(cfg.ResourceTags, name); len(tags) > 0 {
 		input.Tags = tags
 	}
 	out, err := c.client.CreateAgentRuntime(ctx, input)
-	if err != nil {
-		if isConflictError(err) {
-			log.Printf("agentcore: runtime %q already exists, adopting", name)
-			return c.findRuntimeByName(ctx, name)
+	if isConflictError(err) {
+		arn, adopted, conflictErr := c.onCreateConflict(ctx, ResTypeAgentRuntime, name,
+			func() (string, error) { return c.findRuntimeByName(ctx, awsName) },
+			func() error {
+				out, err = c.client.CreateAgentRuntime(ctx, input)
+				return err
+			})
+		if conflictErr != nil || adopted {
+			return arn, conflictErr
 		}
+	}
+	if err != nil {
 		return "", fmt.Errorf("CreateAgentRuntime %q: %w", name, err)
 	}
 
@@ -296,9 +364,7 @@ func (c *realAWSClient) UpdateRuntime(
 		AgentRuntimeId:       aws.String(id),
 		RoleArn:              aws.String(cfg.RuntimeRoleARN),
 		AgentRuntimeArtifact: artifact,
-		NetworkConfiguration: &types.NetworkConfiguration{
-			NetworkMode: types.NetworkModePublic,
-		},
+		NetworkConfiguration: buildNetworkConfiguration(cfg),
 	}
 	if proto := resolveServerProtocol(cfg); proto != "" {
 		input.ProtocolConfiguration = &types.ProtocolConfiguration{
@@ -311,6 +377,9 @@ func (c *realAWSClient) UpdateRuntime(
 	if authCfg := buildAuthorizerConfig(cfg); authCfg != nil {
 		input.AuthorizerConfiguration = authCfg
 	}
+	if lc := buildLifecycleConfiguration(cfg); lc != nil {
+		input.LifecycleConfiguration = lc
+	}
 	_, err := c.client.UpdateAgentRuntime(ctx, input)
 	if err != nil {
 		return arn, fmt.Errorf("UpdateAgentRuntime %q: %w", name, err)
@@ -323,30 +392,43 @@ func (c *realAWSClient) UpdateRuntime(
 	return arn, nil
 }
 
+// toolGateway is a gateway CreateGatewayTool created or adopted.
+type toolGateway struct {
+	id, arn, name string
+}
+
 // CreateGatewayTool provisions a tool gateway target, lazily creating the
-// parent gateway on the first invocation.
+// parent gateway on the first invocation. A name of the form
+// "<agent>/<tool>" puts the target on that agent's own gateway.
 func (c *realAWSClient) CreateGatewayTool(
 	ctx context.Context, name string, cfg *Config,
 ) (string, error) {
-	if c.gatewayID == "" {
-		if err := c.createParentGateway(ctx, name, cfg); err != nil {
-			return c.gatewayARN, err
+	agent, tool := splitGatewayTarget(name)
+	gw := c.gateways[agent]
+	if gw == nil {
+		var err error
+		if gw, err = c.createParentGateway(ctx, agent, tool, cfg); err != nil {
+			return "", err
+		}
+		if c.gateways == nil {
+			c.gateways = make(map[string]*toolGateway)
 		}
+		c.gateways[agent] = gw
 	}
 
 	input := &bedrockagentcorecontrol.CreateGatewayTargetInput{
-		GatewayIdentifier:   aws.String(c.gatewayID),
-		Name:                aws.String(name),
-		TargetConfiguration: buildTargetConfig(name, cfg),
+		GatewayIdentifier:   aws.String(gw.id),
+		Name:                aws.String(gatewayTargetName(tool)),
+		TargetConfiguration: buildTargetConfig(tool, cfg),
 	}
-	if creds := buildCredentialProviderConfigs(name, cfg); len(creds) > 0 {
+	if creds := buildCredentialProviderConfigs(tool, cfg); len(creds) > 0 {
 		input.CredentialProviderConfigurations = creds
 	}
 	targetOut, err := c.client.CreateGatewayTarget(ctx, input)
 	if err != nil {
 		if isConflictError(err) {
-			log.Printf("agentcore: gateway target %q already exists, adopting", name)
-			return c.gatewayARN, nil
+			logf("agentcore: gateway target %q already exists, adopting", name)
+			return gw.arn, nil
 		}
 		return "", fmt.Errorf("CreateGatewayTarget %q: %w", name, err)
 	}
@@ -354,58 +436,85 @@ func (c *realAWSClient) CreateGatewayTool(
 	return aws.ToString(targetOut.GatewayArn), nil
 }
 
-// createParentGateway provisions the shared gateway and waits for it to
-// become ready.
+// createParentGateway provisions the gateway for agent's tools, or the
+// shared gateway when agent is "", and waits for it to become ready. The
+// shared gateway is named after the first tool's target.
 func (c *realAWSClient) createParentGateway(
-	ctx context.Context, name string, cfg *Config,
-) error {
-	gwName := name + "-gw"
+	ctx context.Context, agent, tool string, cfg *Config,
+) (*toolGateway, error) {
+	gwName := cfg.awsName(gatewayTargetName(tool)) + "-gw"
+	if agent != "" {
+		gwName = cfg.awsName(agent) + "-gw"
+	}
 	gwInput := &bedrockagentcorecontrol.CreateGatewayInput{
 		Name:           aws.String(gwName),
 		RoleArn:        aws.String(cfg.RuntimeRoleARN),
 		ProtocolType:   types.GatewayProtocolTypeMcp,
 		AuthorizerType: types.AuthorizerTypeNone,
 	}
+	if pc := buildGatewayProtocolConfig(cfg); pc != nil {
+		gwInput.ProtocolConfiguration = pc
+	}
+	gwInput.InterceptorConfigurations = buildGatewayInterceptors(cfg)
 	if len(cfg.ResourceTags) > 0 {
 		gwInput.Tags = cfg.ResourceTags
 	}
 	gwOut, err := c.client.CreateGateway(ctx, gwInput)
-	if err != nil {
-		if isConflictError(err) {
-			log.Printf("agentcore: gateway %q already exists, adopting", gwName)
-			id, arn, findErr := c.findGatewayByName(ctx, gwName)
-			if findErr != nil {
-				return fmt.Errorf("CreateGateway for tool %q (adopt): %w", name, findErr)
-			}
-			c.gatewayID = id
-			c.gatewayARN = arn
-			c.gatewayName = gwName
-			return nil
+	if isConflictError(err) {
+		var gwID string
+		arn, adopted, conflictErr := c.onCreateConflict(ctx, ResTypeToolGateway, gwName,
+			func() (string, error) {
+				id, gwARN, findErr := c.findGatewayByName(ctx, gwName)
+				gwID = id
+				return gwARN, findErr
+			},
+			func() error {
+				gwOut, err = c.client.CreateGateway(ctx, gwInput)
+				return err
+			})
+		if conflictErr != nil {
+			return nil, fmt.Errorf("CreateGateway for tool %q: %w", tool, conflictErr)
+		}
+		if adopted {
+			return &toolGateway{id: gwID, arn: arn, name: gwName}, nil
 		}
-		return fmt.Errorf("CreateGateway for tool %q: %w", name, err)
 	}
-	c.gatewayID = aws.ToString(gwOut.GatewayId)
-	c.gatewayARN = aws.ToString(gwOut.GatewayArn)
-	c.gatewayName = gwName
+	if err != nil {
+		return nil, fmt.Errorf("CreateGateway for tool %q: %w", tool, err)
+	}
+	gw := &toolGateway{id: aws.ToString(gwOut.GatewayId), arn: aws.ToString(gwOut.GatewayArn), name: gwName}
 
-	if err := c.waitForGatewayReady(ctx, c.gatewayID); err != nil {
-		return fmt.Errorf("gateway for tool %q created but not ready: %w", name, err)
+	if err := c.waitForGatewayReady(ctx, gw.id); err != nil {
+		return nil, fmt.Errorf("gateway for tool %q created but not ready: %w", tool, err)
+	}
+	return gw, nil
+}
+
+// gatewayByARN returns the gateway with the given ARN that this client
+// created or adopted, or nil.
+func (c *realAWSClient) gatewayByARN(arn string) *toolGateway {
+	for _, gw := range c.gateways {
+		if gw.arn == arn {
+			return gw
+		}
 	}
 	return nil
 }
 
-// AssociatePolicyEngine updates the gateway to reference a policy engine.
-// This must be called after both the gateway and policy engine exist so the
-// engine's Cedar schema includes the gateway's registered tools/actions.
+// AssociatePolicyEngine updates the gateway with the given ARN to
+// reference a policy engine. This must be called after both the gateway
+// and policy engine exist so the engine's Cedar schema includes the
+// gateway's registered tools/actions.
 func (c *realAWSClient) AssociatePolicyEngine(
-	ctx context.Context, policyEngineARN string, cfg *Config,
+	ctx context.Context, policyEngineARN, gatewayARN string, cfg *Config,
 ) error {
-	if c.gatewayID == "" {
+	gw := c.gatewayByARN(gatewayARN)
+	if gw == nil {
 		return fmt.Errorf("no gateway to associate policy engine with")
 	}
 	_, err := c.client.UpdateGateway(ctx, &bedrockagentcorecontrol.UpdateGatewayInput{
-		GatewayIdentifier: aws.String(c.gatewayID),
-		Name:              aws.String(c.gatewayName),
+		GatewayIdentifier: aws.String(gw.id),
+		Name:              aws.String(gw.name),
 		RoleArn:           aws.String(cfg.RuntimeRoleARN),
 		ProtocolType:      types.GatewayProtocolTypeMcp,
 		AuthorizerType:    types.AuthorizerTypeNone,
@@ -413,28 +522,22 @@ func (c *realAWSClient) AssociatePolicyEngine(
 			Arn:  aws.String(policyEngineARN),
 			Mode: types.GatewayPolicyEngineModeEnforce,
 		},
+		// UpdateGateway replaces the gateway settings, so the
+		// interceptors are sent again to keep them attached.
+		InterceptorConfigurations: buildGatewayInterceptors(cfg),
 	})
 	if err != nil {
 		return fmt.Errorf("UpdateGateway to associate policy engine: %w", err)
 	}
-	log.Printf("agentcore: associated policy engine with gateway %s", c.gatewayID)
+	logf("agentcore: associated policy engine with gateway %s", gw.id)
 
 	// Wait for gateway to become ready after the update.
-	if err := c.waitForGatewayReady(ctx, c.gatewayID); err != nil {
+	if err := c.waitForGatewayReady(ctx, gw.id); err != nil {
 		return fmt.Errorf("gateway not ready after policy engine association: %w", err)
 	}
 	return nil
 }
 
-// CreateA2AWiring registers a logical A2A endpoint. No separate AWS API
-// call is required; the runtime exposes A2A when configured.
-func (c *realAWSClient) CreateA2AWiring(
-	_ context.Context, name string, _ *Config,
-) (string, error) {
-	log.Printf("agentcore: A2A wiring %q is a logical resource (no separate API call)", name)
-	return fmt.Sprintf("arn:aws:bedrock:%s:a2a-endpoint/%s", c.cfg.Region, name), nil
-}
-
 // defaultEvalModel is the default Bedrock model ID used for LLM-as-a-Judge evaluators.
 const defaultEvalModel = "anthropic.claude-sonnet-4-20250514-v1:0"
 
@@ -452,12 +555,19 @@ func (c *realAWSClient) CreateEvaluator(
 	}
 
 	level := mapTriggerToLevel(evalDef.Trigger)
-	instructions := evalParamString(evalDef.Params, "instructions", "Evaluate the agent response quality.")
+	instructions, err := evalInstructions(evalDef.Params)
+	if err != nil {
+		return "", fmt.Errorf("CreateEvaluator %q: %w", name, err)
+	}
 	instructions = ensureEvalPlaceholders(instructions)
 	modelID := evalParamString(evalDef.Params, "model", defaultEvalModel)
+	if profile := cfg.EvalModelIDs[name]; profile != "" {
+		modelID = profile
+	}
 
+	awsName := cfg.awsName(name)
 	input := &bedrockagentcorecontrol.CreateEvaluatorInput{
-		EvaluatorName: aws.String(name),
+		EvaluatorName: aws.String(awsName),
 		Level:         level,
 		EvaluatorConfig: &types.EvaluatorConfigMemberLlmAsAJudge{
 			Value: types.LlmAsAJudgeEvaluatorConfig{
@@ -479,11 +589,18 @@ func (c *realAWSClient) CreateEvaluator(
 	}
 
 	out, err := c.client.CreateEvaluator(ctx, input)
-	if err != nil {
-		if isConflictError(err) {
-			log.Printf("agentcore: evaluator %q already exists, adopting", name)
-			return c.findEvaluatorByName(ctx, name)
+	if isConflictError(err) {
+		arn, adopted, conflictErr := c.onCreateConflict(ctx, ResTypeEvaluator, name,
+			func() (string, error) { return c.findEvaluatorByName(ctx, awsName) },
+			func() error {
+				out, err = c.client.CreateEvaluator(ctx, input)
+				return err
+			})
+		if conflictErr != nil || adopted {
+			return arn, conflictErr
 		}
+	}
+	if err != nil {
 		return "", fmt.Errorf("CreateEvaluator %q: %w", name, err)
 	}
 
@@ -496,6 +613,8 @@ func (c *realAWSClient) CreateEvaluator(
 }
 
 // mapTriggerToLevel maps a PromptKit eval trigger to an SDK evaluator level.
+// Sampling triggers map to the level they sample; their rate goes into the
+// online eval config's sampling rule (see effectiveSamplingPercentage).
 func mapTriggerToLevel(trigger evals.EvalTrigger) types.EvaluatorLevel {
 	switch trigger {
 	case evals.TriggerOnSessionComplete, evals.TriggerSampleSessions,
@@ -532,12 +651,7 @@ func ensureEvalPlaceholders(instructions string) string {
 
 // buildNumericalRatingScale builds a 1–N numerical rating scale from eval params.
 func buildNumericalRatingScale(params map[string]any) *types.RatingScaleMemberNumerical {
-	size := defaultRatingScaleSize
-	if v, ok := params["rating_scale_size"]; ok {
-		if n, ok := v.(float64); ok && n >= 2 {
-			size = int(n)
-		}
-	}
+	size := ratingScaleSize(params)
 	defs := make([]types.NumericalScaleDefinition, size)
 	for i := range size {
 		val := float64(i + 1)
@@ -550,10 +664,22 @@ func buildNumericalRatingScale(params map[string]any) *types.RatingScaleMemberNu
 	return &types.RatingScaleMemberNumerical{Value: defs}
 }
 
+// ratingScaleSize returns the number of levels in an evaluator's rating
+// scale from the rating_scale_size param.
+func ratingScaleSize(params map[string]any) int {
+	if v, ok := params["rating_scale_size"]; ok {
+		if n, ok := v.(float64); ok && n >= 2 {
+			return int(n)
+		}
+	}
+	return defaultRatingScaleSize
+}
+
 // waitForEvaluatorReady polls GetEvaluator until status is ACTIVE or a
 // terminal failure state.
 func (c *realAWSClient) waitForEvaluatorReady(ctx context.Context, id string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("evaluator", id)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetEvaluator(ctx, &bedrockagentcorecontrol.GetEvaluatorInput{
 			EvaluatorId: aws.String(id),
 		})
@@ -568,13 +694,14 @@ func (c *realAWSClient) waitForEvaluatorReady(ctx context.Context, id string) er
 		case types.EvaluatorStatusCreating, types.EvaluatorStatusUpdating, types.EvaluatorStatusDeleting:
 			// Transitional — keep polling.
 		}
-		time.Sleep(pollInterval)
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
 	}
-	return fmt.Errorf("evaluator %q did not become active after %d attempts", id, maxPollAttempts)
+	return fmt.Errorf("evaluator %q did not become active after %d attempts", id, c.poll.maxAttempts())
 }
 
-// defaultSamplingPercentage is the default sampling percentage for online
-// evaluation configs when not specified in eval params.
+// defaultSamplingPercentage is the sampling percentage for evals that do
+// not sample, and the cap on any requested rate.
 const defaultSamplingPercentage = 100.0
 
 // defaultTraceLogGroup is the CloudWatch log group where AgentCore runtimes
@@ -588,13 +715,13 @@ func (c *realAWSClient) ensureLogGroup(ctx context.Context, logGroupName string)
 		LogGroupName: aws.String(logGroupName),
 	})
 	if err != nil {
-		// Ignore ResourceAlreadyExistsException — the log group already exists.
-		if strings.Contains(err.Error(), "ResourceAlreadyExistsException") {
+		// The log group already exists.
+		if isAlreadyExists(err) {
 			return nil
 		}
 		return fmt.Errorf("create log group %q: %w", logGroupName, err)
 	}
-	log.Printf("[agentcore] created CloudWatch log group %s", logGroupName)
+	logf("[agentcore] created CloudWatch log group %s", logGroupName)
 	return nil
 }
 
@@ -603,55 +730,37 @@ func (c *realAWSClient) ensureLogGroup(ctx context.Context, logGroupName string)
 func (c *realAWSClient) CreateOnlineEvalConfig(
 	ctx context.Context, name string, cfg *Config,
 ) (string, error) {
-	logGroup := resolveLogGroup(cfg)
-
-	// Ensure the log group exists before referencing it. For the default
-	// "aws/spans" group, Transaction Search must be enabled — the group is
-	// AWS-managed and cannot be created manually.
-	if logGroup != defaultTraceLogGroup {
-		if err := c.ensureLogGroup(ctx, logGroup); err != nil {
-			return "", fmt.Errorf("CreateOnlineEvalConfig %q: %w", name, err)
-		}
-	}
-
-	// Service name follows AgentCore convention: <runtime-name>.DEFAULT
-	packID := cfg.ResourceTags[TagKeyPackID]
-	serviceName := packID + ".DEFAULT"
-
-	evalRefs := buildEvaluatorReferences(cfg.EvalARNs, cfg.BuiltinEvalIDs)
-	if len(evalRefs) == 0 {
-		return "", fmt.Errorf("CreateOnlineEvalConfig %q: no evaluator references available", name)
+	spec, err := c.prepareOnlineEvalSpec(ctx, cfg)
+	if err != nil {
+		return "", fmt.Errorf("CreateOnlineEvalConfig %q: %w", name, err)
 	}
 
-	samplingPct := resolveSamplingPercentage(cfg)
-
+	awsName := cfg.awsName(name)
 	input := &bedrockagentcorecontrol.CreateOnlineEvaluationConfigInput{
-		OnlineEvaluationConfigName: aws.String(name),
+		OnlineEvaluationConfigName: aws.String(awsName),
 		EvaluationExecutionRoleArn: aws.String(cfg.RuntimeRoleARN),
 		EnableOnCreate:             aws.Bool(true),
-		DataSourceConfig: &types.DataSourceConfigMemberCloudWatchLogs{
-			Value: types.CloudWatchLogsInputConfig{
-				LogGroupNames: []string{logGroup},
-				ServiceNames:  []string{serviceName},
-			},
-		},
-		Evaluators: evalRefs,
-		Rule: &types.Rule{
-			SamplingConfig: &types.SamplingConfig{
-				SamplingPercentage: aws.Float64(samplingPct),
-			},
-		},
+		DataSourceConfig:           spec.dataSource(),
+		Evaluators:                 spec.evaluators,
+		Rule:                       spec.rule(),
 	}
 	if cfg.ResourceTags != nil {
 		input.Tags = cfg.ResourceTags
 	}
 
 	out, err := c.client.CreateOnlineEvaluationConfig(ctx, input)
-	if err != nil {
-		if isConflictError(err) {
-			log.Printf("agentcore: online eval config %q already exists, adopting", name)
-			return c.findOnlineEvalConfigByName(ctx, name)
+	if isConflictError(err) {
+		arn, adopted, conflictErr := c.onCreateConflict(ctx, ResTypeOnlineEvalConfig, name,
+			func() (string, error) { return c.findOnlineEvalConfigByName(ctx, awsName) },
+			func() error {
+				out, err = c.client.CreateOnlineEvaluationConfig(ctx, input)
+				return err
+			})
+		if conflictErr != nil || adopted {
+			return arn, conflictErr
 		}
+	}
+	if err != nil {
 		return "", fmt.Errorf("CreateOnlineEvaluationConfig %q: %w", name, err)
 	}
 
@@ -702,23 +811,11 @@ func buildEvaluatorReferences(evalARNs map[string]string, builtinIDs []string) [
 	return refs
 }
 
-// resolveSamplingPercentage extracts the sampling percentage from eval params,
-// defaulting to 100%.
-func resolveSamplingPercentage(cfg *Config) float64 {
-	for name := range cfg.EvalDefs {
-		if v, ok := cfg.EvalDefs[name].Params["sample_percentage"]; ok {
-			if pct, ok := v.(float64); ok && pct > 0 {
-				return pct
-			}
-		}
-	}
-	return defaultSamplingPercentage
-}
-
 // waitForOnlineEvalConfigReady polls GetOnlineEvaluationConfig until ACTIVE
 // or a terminal failure state.
 func (c *realAWSClient) waitForOnlineEvalConfigReady(ctx context.Context, id string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("online eval config", id)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetOnlineEvaluationConfig(ctx,
 			&bedrockagentcorecontrol.GetOnlineEvaluationConfigInput{
 				OnlineEvaluationConfigId: aws.String(id),
@@ -741,9 +838,10 @@ func (c *realAWSClient) waitForOnlineEvalConfigReady(ctx context.Context, id str
 			types.OnlineEvaluationConfigStatusDeleting:
 			// Transitional — keep polling.
 		}
-		time.Sleep(pollInterval)
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
 	}
-	return fmt.Errorf("online eval config %q did not become active after %d attempts", id, maxPollAttempts)
+	return fmt.Errorf("online eval config %q did not become active after %d attempts", id, c.poll.maxAttempts())
 }
 
 // buildAuthorizerConfig returns the SDK AuthorizerConfiguration for the
@@ -780,8 +878,9 @@ func (c *realAWSClient) CreateMemory(
 	ctx context.Context, name string, cfg *Config,
 ) (string, error) {
 	expiryDays := resolveExpiryDays(cfg.Memory.EventExpiryDays)
+	awsName := cfg.awsName(name)
 	input := &bedrockagentcorecontrol.CreateMemoryInput{
-		Name:                aws.String(name),
+		Name:                aws.String(awsName),
 		EventExpiryDuration: aws.Int32(expiryDays),
 	}
 
@@ -798,7 +897,7 @@ func (c *realAWSClient) CreateMemory(
 		input.Tags = cfg.ResourceTags
 	}
 
-	return c.createMemoryWithRetry(ctx, name, input)
+	return c.createMemoryWithRetry(ctx, awsName, input)
 }
 
 // createMemoryWithRetry attempts to create a memory, handling the case where
@@ -807,7 +906,7 @@ func (c *realAWSClient) CreateMemory(
 func (c *realAWSClient) createMemoryWithRetry(
 	ctx context.Context, name string, input *bedrockagentcorecontrol.CreateMemoryInput,
 ) (string, error) {
-	for range maxPollAttempts {
+	for range c.poll.maxAttempts() {
 		out, err := c.client.CreateMemory(ctx, input)
 		if err == nil {
 			memoryID := aws.ToString(out.Memory.Id)
@@ -820,15 +919,16 @@ func (c *realAWSClient) createMemoryWithRetry(
 		if !isMemoryAlreadyExists(err) {
 			return "", fmt.Errorf("CreateMemory %q: %w", name, err)
 		}
-		// Memory already exists — try to adopt it.
-		arn, findErr := c.findMemoryByName(ctx, name)
-		if findErr == nil {
-			log.Printf("agentcore: memory %q already exists, adopting", name)
+		arn, adopted, conflictErr := c.resolveExistingMemory(ctx, name)
+		if conflictErr != nil {
+			return "", conflictErr
+		}
+		if adopted {
 			return arn, nil
 		}
-		// Not found means the old memory is still deleting. Wait for it to
-		// finish before retrying, rather than looping on CreateMemory.
-		log.Printf("agentcore: memory %q exists but is deleting, waiting for deletion", name)
+		// The old memory is still deleting (or was just replaced). Wait for
+		// it to finish before retrying, rather than looping on CreateMemory.
+		logf("agentcore: memory %q exists but is deleting, waiting for deletion", name)
 		if waitErr := c.waitForDeletingMemoryGone(ctx, name); waitErr != nil {
 			return "", fmt.Errorf("memory %q: waiting for deletion: %w", name, waitErr)
 		}
@@ -836,10 +936,30 @@ func (c *realAWSClient) createMemoryWithRetry(
 	return "", fmt.Errorf("memory %q: timed out waiting for previous deletion", name)
 }
 
+// resolveExistingMemory applies the on_conflict policy to a memory whose
+// name is already taken. It reports adopted=false when the existing memory is
+// deleting, either already or because the policy replaced it.
+func (c *realAWSClient) resolveExistingMemory(
+	ctx context.Context, name string,
+) (arn string, adopted bool, err error) {
+	arn, findErr := c.findExisting(ResTypeMemory, name, func() (string, error) {
+		return c.findMemoryByName(ctx, name)
+	})
+	if findErr != nil {
+		return "", false, nil //nolint:nilerr // not found means the old memory is deleting
+	}
+	action, err := c.resolveConflict(ctx, ResourceState{Type: ResTypeMemory, Name: name, ARN: arn})
+	if err != nil {
+		return "", false, err
+	}
+	return arn, action == conflictActionAdopt, nil
+}
+
 // waitForDeletingMemoryGone polls ListMemories until no memory with the given
 // name prefix exists (i.e. the DELETING memory has been fully removed).
 func (c *realAWSClient) waitForDeletingMemoryGone(ctx context.Context, name string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("memory", name)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.ListMemories(ctx, &bedrockagentcorecontrol.ListMemoriesInput{
 			MaxResults: aws.Int32(listPageSize),
 		})
@@ -848,24 +968,19 @@ func (c *realAWSClient) waitForDeletingMemoryGone(ctx context.Context, name stri
 		}
 		found := false
 		for _, m := range out.Memories {
-			if strings.HasPrefix(aws.ToString(m.Id), name) {
+			if memoryIDHasName(aws.ToString(m.Id), name) {
 				found = true
-				log.Printf("agentcore: memory %q still %s, waiting", name, m.Status)
+				logf("agentcore: memory %q still %s, waiting", name, m.Status)
 				break
 			}
 		}
 		if !found {
 			return nil
 		}
-		time.Sleep(pollInterval)
+		tracker.waiting(attempt, "DELETING")
+		c.poll.sleep()
 	}
-	return fmt.Errorf("memory %q still exists after %d poll attempts", name, maxPollAttempts)
-}
-
-// isMemoryAlreadyExists checks for the "already exists" validation error
-// returned by CreateMemory (unlike other resources that return ConflictException).
-func isMemoryAlreadyExists(err error) bool {
-	return err != nil && strings.Contains(err.Error(), "already exists")
+	return fmt.Errorf("memory %q still exists after %d poll attempts", name, c.poll.maxAttempts())
 }
 
 // findMemoryByName lists memories and returns the ARN of one matching name,
@@ -877,24 +992,40 @@ func (c *realAWSClient) findMemoryByName(ctx context.Context, name string) (stri
 	if err != nil {
 		return "", err
 	}
+	deleting := false
 	for _, m := range out.Memories {
 		id := aws.ToString(m.Id)
-		// Memory IDs include the name as a prefix (e.g. "myname-AbCdEfGh").
+		if !memoryIDHasName(id, name) {
+			continue
+		}
 		// Skip memories that are being deleted — they can't be adopted.
-		if strings.HasPrefix(id, name) && m.Status != types.MemoryStatusDeleting {
-			if err := c.waitForMemoryActive(ctx, id); err != nil {
-				return aws.ToString(m.Arn), err
-			}
-			return aws.ToString(m.Arn), nil
+		if m.Status == types.MemoryStatusDeleting {
+			deleting = true
+			continue
+		}
+		if err := c.waitForMemoryActive(ctx, id); err != nil {
+			return aws.ToString(m.Arn), err
 		}
+		return aws.ToString(m.Arn), nil
 	}
-	return "", fmt.Errorf("memory %q not found", name)
+	if deleting {
+		return "", fmt.Errorf("memory %q is deleting", name)
+	}
+	return "", fmt.Errorf("memory %q %w", name, errNotListed)
+}
+
+// memoryIDHasName reports whether a memory ID belongs to the memory named
+// name. Memory IDs are the name plus a suffix (e.g. "myname-AbCdEfGh"); the
+// dash keeps "pack_memory" from matching "pack_memory_dev-AbCdEfGh".
+func memoryIDHasName(id, name string) bool {
+	return strings.HasPrefix(id, name+"-")
 }
 
 // waitForMemoryActive polls GetMemory until the status is ACTIVE or a
 // terminal failure state.
 func (c *realAWSClient) waitForMemoryActive(ctx context.Context, id string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("memory", id)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetMemory(ctx, &bedrockagentcorecontrol.GetMemoryInput{
 			MemoryId: aws.String(id),
 		})
@@ -914,9 +1045,10 @@ func (c *realAWSClient) waitForMemoryActive(ctx context.Context, id string) erro
 		case types.MemoryStatusCreating:
 			// Transitional — keep polling.
 		}
-		time.Sleep(pollInterval)
+		tracker.waiting(attempt, string(out.Memory.Status))
+		c.poll.sleep()
 	}
-	return fmt.Errorf("memory %q did not become active after %d attempts", id, maxPollAttempts)
+	return fmt.Errorf("memory %q did not become active after %d attempts", id, c.poll.maxAttempts())
 }
 
 // resolveExpiryDays returns the configured expiry or the default.
@@ -974,24 +1106,25 @@ func buildStrategyInput(strategy string) types.MemoryStrategyInput {
 // CreatePolicyEngine provisions a policy engine and polls until it reaches
 // ACTIVE status.
 func (c *realAWSClient) CreatePolicyEngine(
-	ctx context.Context, name string, _ *Config,
+	ctx context.Context, name string, cfg *Config,
 ) (arn, engineID string, err error) {
-	out, err := c.client.CreatePolicyEngine(ctx, &bedrockagentcorecontrol.CreatePolicyEngineInput{
-		Name: aws.String(name),
-	})
+	awsName := cfg.awsName(name)
+	input := &bedrockagentcorecontrol.CreatePolicyEngineInput{
+		Name: aws.String(awsName),
+	}
+	out, err := c.client.CreatePolicyEngine(ctx, input)
+	if isConflictError(err) {
+		var adopted bool
+		arn, engineID, adopted, err = c.resolveExistingPolicyEngine(ctx, awsName)
+		if err != nil || adopted {
+			return arn, engineID, err
+		}
+		c.retryCreateAfterReplace(func() error {
+			out, err = c.client.CreatePolicyEngine(ctx, input)
+			return err
+		})
+	}
 	if err != nil {
-		if isConflictError(err) {
-			log.Printf("agentcore: policy engine %q already exists, adopting and purging stale policies", name)
-			arn, id, findErr := c.findPolicyEngineByName(ctx, name)
-			if findErr != nil {
-				return "", "", findErr
-			}
-			// Purge stale policies so fresh ones can be created.
-			if purgeErr := c.purgeAllPolicies(ctx, id); purgeErr != nil {
-				return "", "", fmt.Errorf("purge stale policies on engine %q: %w", name, purgeErr)
-			}
-			return arn, id, nil
-		}
 		return "", "", fmt.Errorf("CreatePolicyEngine %q: %w", name, err)
 	}
 
@@ -1004,6 +1137,37 @@ func (c *realAWSClient) CreatePolicyEngine(
 	return aws.ToString(out.PolicyEngineArn), engineID, nil
 }
 
+// resolveExistingPolicyEngine applies the on_conflict policy to a policy
+// engine whose name is already taken. An adopted engine has its stale
+// policies purged so fresh ones can be created.
+func (c *realAWSClient) resolveExistingPolicyEngine(
+	ctx context.Context, name string,
+) (arn, engineID string, adopted bool, err error) {
+	arn, err = c.findExisting(ResTypeCedarPolicy, name, func() (string, error) {
+		var findErr error
+		arn, engineID, findErr = c.findPolicyEngineByName(ctx, name)
+		return arn, findErr
+	})
+	if err != nil {
+		return "", "", false, fmt.Errorf("%s %q already exists but could not be found: %w",
+			ResTypeCedarPolicy, name, err)
+	}
+	action, err := c.resolveConflict(ctx, ResourceState{
+		Type:     ResTypeCedarPolicy,
+		Name:     name,
+		ARN:      arn,
+		Metadata: map[string]string{"policy_engine_id": engineID},
+	})
+	if err != nil || action != conflictActionAdopt {
+		return "", "", false, err
+	}
+	logf("agentcore: purging stale policies on adopted policy engine %q", name)
+	if purgeErr := c.purgeAllPolicies(ctx, engineID); purgeErr != nil {
+		return "", "", false, fmt.Errorf("purge stale policies on engine %q: %w", name, purgeErr)
+	}
+	return arn, engineID, true, nil
+}
+
 // CreateCedarPolicy creates a Cedar policy within a policy engine and
 // polls until the policy reaches ACTIVE or fails.
 func (c *realAWSClient) CreateCedarPolicy(
@@ -1020,7 +1184,7 @@ func (c *realAWSClient) CreateCedarPolicy(
 	})
 	if err != nil {
 		if isConflictError(err) {
-			log.Printf("agentcore: cedar policy %q already exists on engine %q, adopting", name, engineID)
+			logf("agentcore: cedar policy %q already exists on engine %q, adopting", name, engineID)
 			return adoptedPlaceholder, adoptedPlaceholder, nil
 		}
 		return "", "", fmt.Errorf("CreatePolicy %q on engine %q: %w", name, engineID, err)
@@ -1035,7 +1199,8 @@ func (c *realAWSClient) CreateCedarPolicy(
 
 // waitForPolicyActive polls GetPolicy until the policy leaves CREATING state.
 func (c *realAWSClient) waitForPolicyActive(ctx context.Context, engineID, policyID, name string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("policy", name)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetPolicy(ctx, &bedrockagentcorecontrol.GetPolicyInput{
 			PolicyEngineId: aws.String(engineID),
 			PolicyId:       aws.String(policyID),
@@ -1050,18 +1215,20 @@ func (c *realAWSClient) waitForPolicyActive(ctx context.Context, engineID, polic
 			reasons := strings.Join(out.StatusReasons, "; ")
 			return fmt.Errorf("policy %q failed: %s", name, reasons)
 		case types.PolicyStatusCreating, types.PolicyStatusUpdating:
-			log.Printf("agentcore: waiting for policy %q (status: %s)", name, out.Status)
-			time.Sleep(pollInterval)
+			logf("agentcore: waiting for policy %q (status: %s)", name, out.Status)
+			tracker.waiting(attempt, string(out.Status))
+			c.poll.sleep()
 		case types.PolicyStatusDeleting, types.PolicyStatusDeleteFailed:
 			return fmt.Errorf("policy %q unexpected status: %s", name, out.Status)
 		}
 	}
-	return fmt.Errorf("policy %q still CREATING after %d poll attempts", name, maxPollAttempts)
+	return fmt.Errorf("policy %q still CREATING after %d poll attempts", name, c.poll.maxAttempts())
 }
 
 // waitForPolicyEngineActive polls GetPolicyEngine until the status is ACTIVE.
 func (c *realAWSClient) waitForPolicyEngineActive(ctx context.Context, id string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("policy engine", id)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetPolicyEngine(ctx, &bedrockagentcorecontrol.GetPolicyEngineInput{
 			PolicyEngineId: aws.String(id),
 		})
@@ -1072,37 +1239,24 @@ func (c *realAWSClient) waitForPolicyEngineActive(ctx context.Context, id string
 			return nil
 		}
 		if out.Status == types.PolicyEngineStatusCreating {
-			time.Sleep(pollInterval)
+			tracker.waiting(attempt, string(out.Status))
+			c.poll.sleep()
 			continue
 		}
 		return fmt.Errorf("policy engine %q entered status %s", id, out.Status)
 	}
-	return fmt.Errorf("policy engine %q did not become active after %d attempts", id, maxPollAttempts)
+	return fmt.Errorf("policy engine %q did not become active after %d attempts", id, c.poll.maxAttempts())
 }
 
 // ---------- resourceDestroyer implementation ----------
 
 // DeleteResource removes a single resource by type.
 func (c *realAWSClient) DeleteResource(ctx context.Context, res ResourceState) error {
-	switch res.Type {
-	case ResTypeMemory:
-		return c.deleteMemory(ctx, res)
-	case ResTypeAgentRuntime:
-		return c.deleteRuntime(ctx, res)
-	case ResTypeToolGateway:
-		return c.deleteGateway(ctx, res)
-	case ResTypeA2AEndpoint:
-		log.Printf("agentcore: a2a_endpoint %q is logical; skipping delete", res.Name)
-		return nil
-	case ResTypeEvaluator:
-		return c.deleteEvaluator(ctx, res)
-	case ResTypeOnlineEvalConfig:
-		return c.deleteOnlineEvalConfig(ctx, res)
-	case ResTypeCedarPolicy:
-		return c.deleteCedarPolicy(ctx, res)
-	default:
+	t, ok := lookupResourceType(res.Type)
+	if !ok {
 		return fmt.Errorf("unknown resource type %q for deletion", res.Type)
 	}
+	return t.remove(c, ctx, res)
 }
 
 func (c *realAWSClient) deleteRuntime(ctx context.Context, res ResourceState) error {
@@ -1136,7 +1290,7 @@ func (c *realAWSClient) deleteMemory(ctx context.Context, res ResourceState) err
 		return fmt.Errorf("GetMemory %q before delete: %w", res.Name, err)
 	}
 	if out.Memory != nil && out.Memory.Status == types.MemoryStatusDeleting {
-		log.Printf("agentcore: memory %q already deleting, skipping", res.Name)
+		logf("agentcore: memory %q already deleting, skipping", res.Name)
 		return nil
 	}
 
@@ -1210,7 +1364,8 @@ func (c *realAWSClient) purgeAllGatewayTargets(ctx context.Context, gatewayID st
 // waitForGatewayTargetsDrained polls ListGatewayTargets until no targets
 // remain (all DELETING targets have been fully removed).
 func (c *realAWSClient) waitForGatewayTargetsDrained(ctx context.Context, gatewayID string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("targets on gateway", gatewayID)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.ListGatewayTargets(ctx, &bedrockagentcorecontrol.ListGatewayTargetsInput{
 			GatewayIdentifier: aws.String(gatewayID),
 			MaxResults:        aws.Int32(listPageSize),
@@ -1224,10 +1379,11 @@ func (c *realAWSClient) waitForGatewayTargetsDrained(ctx context.Context, gatewa
 		if len(out.Items) == 0 {
 			return nil
 		}
-		log.Printf("agentcore: waiting for %d gateway target(s) to be deleted on %s", len(out.Items), gatewayID)
-		time.Sleep(pollInterval)
+		logf("agentcore: waiting for %d gateway target(s) to be deleted on %s", len(out.Items), gatewayID)
+		tracker.waiting(attempt, "DELETING")
+		c.poll.sleep()
 	}
-	return fmt.Errorf("gateway %q still has targets after %d poll attempts", gatewayID, maxPollAttempts)
+	return fmt.Errorf("gateway %q still has targets after %d poll attempts", gatewayID, c.poll.maxAttempts())
 }
 
 // deleteGatewayTargetBatch waits for targets to leave CREATING state, then
@@ -1241,7 +1397,7 @@ func (c *realAWSClient) deleteGatewayTargetBatch(
 			continue
 		}
 		if err := c.waitForTargetDeletable(ctx, gatewayID, targetID); err != nil {
-			log.Printf("agentcore: target %s not deletable, attempting delete anyway: %v", targetID, err)
+			logf("agentcore: target %s not deletable, attempting delete anyway: %v", targetID, err)
 		}
 		_, err := c.client.DeleteGatewayTarget(ctx, &bedrockagentcorecontrol.DeleteGatewayTargetInput{
 			GatewayIdentifier: aws.String(gatewayID),
@@ -1250,7 +1406,7 @@ func (c *realAWSClient) deleteGatewayTargetBatch(
 		if err != nil && !isNotFound(err) {
 			return fmt.Errorf("DeleteGatewayTarget %q on gateway %q: %w", targetID, gatewayID, err)
 		}
-		log.Printf("agentcore: deleted gateway target %s from gateway %s", targetID, gatewayID)
+		logf("agentcore: deleted gateway target %s from gateway %s", targetID, gatewayID)
 	}
 	return nil
 }
@@ -1260,7 +1416,8 @@ func (c *realAWSClient) deleteGatewayTargetBatch(
 func (c *realAWSClient) waitForTargetDeletable(
 	ctx context.Context, gatewayID, targetID string,
 ) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("gateway target", targetID)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetGatewayTarget(ctx, &bedrockagentcorecontrol.GetGatewayTargetInput{
 			GatewayIdentifier: aws.String(gatewayID),
 			TargetId:          aws.String(targetID),
@@ -1274,10 +1431,11 @@ func (c *realAWSClient) waitForTargetDeletable(
 		if out.Status != types.TargetStatusCreating {
 			return nil
 		}
-		log.Printf("agentcore: target %s still CREATING, waiting", targetID)
-		time.Sleep(pollInterval)
+		logf("agentcore: target %s still CREATING, waiting", targetID)
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
 	}
-	return fmt.Errorf("target %q did not leave CREATING after %d attempts", targetID, maxPollAttempts)
+	return fmt.Errorf("target %q did not leave CREATING after %d attempts", targetID, c.poll.maxAttempts())
 }
 
 func (c *realAWSClient) deleteCedarPolicy(ctx context.Context, res ResourceState) error {
@@ -1316,8 +1474,8 @@ func (c *realAWSClient) deletePolicyEngineWithRetry(
 		if !isConflictError(err) {
 			return fmt.Errorf("DeletePolicyEngine %q: %w", name, err)
 		}
-		log.Printf("agentcore: policy engine %q still has policies being cleaned up, retrying", engineID)
-		time.Sleep(pollInterval)
+		logf("agentcore: policy engine %q still has policies being cleaned up, retrying", engineID)
+		c.poll.sleep()
 	}
 	return fmt.Errorf("DeletePolicyEngine %q: still has policies after %d retries", name, policyEngineDeleteRetries)
 }
@@ -1365,7 +1523,7 @@ func (c *realAWSClient) deletePolicyBatch(
 		if err != nil && !isNotFound(err) {
 			return fmt.Errorf("DeletePolicy %q on engine %q: %w", policyID, engineID, err)
 		}
-		log.Printf("agentcore: deleted policy %s from engine %s", policyID, engineID)
+		logf("agentcore: deleted policy %s from engine %s", policyID, engineID)
 	}
 	return nil
 }
@@ -1390,6 +1548,9 @@ func (c *realAWSClient) deleteEvaluator(ctx context.Context, res ResourceState)
 	if id == "" {
 		id = res.Name
 	}
+	if err := c.releaseEvaluator(ctx, id, res); err != nil {
+		return err
+	}
 	_, err := c.client.DeleteEvaluator(ctx, &bedrockagentcorecontrol.DeleteEvaluatorInput{
 		EvaluatorId: aws.String(id),
 	})
@@ -1403,24 +1564,26 @@ func (c *realAWSClient) deleteEvaluator(ctx context.Context, res ResourceState)
 
 // CheckResource returns the health status of a single resource.
 func (c *realAWSClient) CheckResource(ctx context.Context, res ResourceState) (string, error) {
+	t, ok := lookupResourceType(res.Type)
+	if !ok {
+		return StatusMissing, fmt.Errorf("unknown resource type %q", res.Type)
+	}
+	return t.check(c, ctx, res)
+}
+
+// CheckResourceDetail implements resourceDetailChecker. Tool gateways
+// report which target is missing or not ready, and A2A endpoints why their
+// agent card could not be fetched; every other type defers to
+// CheckResource with no detail.
+func (c *realAWSClient) CheckResourceDetail(ctx context.Context, res ResourceState) (string, string, error) {
 	switch res.Type {
-	case ResTypeMemory:
-		return c.checkMemory(ctx, res)
-	case ResTypeAgentRuntime:
-		return c.checkRuntime(ctx, res)
 	case ResTypeToolGateway:
 		return c.checkGateway(ctx, res)
 	case ResTypeA2AEndpoint:
-		return StatusHealthy, nil
-	case ResTypeEvaluator:
-		return c.checkEvaluator(ctx, res)
-	case ResTypeOnlineEvalConfig:
-		return c.checkOnlineEvalConfig(ctx, res)
-	case ResTypeCedarPolicy:
-		return c.checkCedarPolicy(ctx, res)
-	default:
-		return StatusMissing, fmt.Errorf("unknown resource type %q", res.Type)
+		return c.checkA2AWiring(ctx, res)
 	}
+	status, err := c.CheckResource(ctx, res)
+	return status, "", err
 }
 
 func (c *realAWSClient) checkMemory(ctx context.Context, res ResourceState) (string, error) {
@@ -1463,7 +1626,10 @@ func (c *realAWSClient) checkRuntime(ctx context.Context, res ResourceState) (st
 	return StatusUnhealthy, nil
 }
 
-func (c *realAWSClient) checkGateway(ctx context.Context, res ResourceState) (string, error) {
+// checkGateway checks the parent gateway and then the target that res
+// stands for. All tool_gateway resources share one gateway, so a deleted or
+// failed target only shows up in the target listing.
+func (c *realAWSClient) checkGateway(ctx context.Context, res ResourceState) (string, string, error) {
 	id := extractResourceID(res.ARN, "gateway")
 	if id == "" {
 		id = res.Name
@@ -1473,14 +1639,56 @@ func (c *realAWSClient) checkGateway(ctx context.Context, res ResourceState) (st
 	})
 	if err != nil {
 		if isNotFound(err) {
-			return StatusMissing, nil
+			return StatusMissing, fmt.Sprintf("gateway %s not found", id), nil
 		}
-		return StatusUnhealthy, fmt.Errorf("GetGateway %q: %w", res.Name, err)
+		return StatusUnhealthy, "", fmt.Errorf("GetGateway %q: %w", res.Name, err)
 	}
-	if out.Status == types.GatewayStatusReady {
-		return StatusHealthy, nil
+	if out.Status != types.GatewayStatusReady {
+		return StatusUnhealthy, fmt.Sprintf("gateway %s is %s", id, out.Status), nil
 	}
-	return StatusUnhealthy, nil
+
+	targets, err := c.listGatewayTargets(ctx, id)
+	if err != nil {
+		return StatusUnhealthy, "", fmt.Errorf("ListGatewayTargets %q: %w", res.Name, err)
+	}
+	status, detail := gatewayTargetHealth(resourceTargetName(res), targets)
+	return status, detail, nil
+}
+
+// listGatewayTargets returns every target on a gateway, following pagination.
+func (c *realAWSClient) listGatewayTargets(ctx context.Context, gatewayID string) ([]types.TargetSummary, error) {
+	var targets []types.TargetSummary
+	var nextToken *string
+	for {
+		out, err := c.client.ListGatewayTargets(ctx, &bedrockagentcorecontrol.ListGatewayTargetsInput{
+			GatewayIdentifier: aws.String(gatewayID),
+			MaxResults:        aws.Int32(listPageSize),
+			NextToken:         nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, out.Items...)
+		if out.NextToken == nil {
+			return targets, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// gatewayTargetHealth reports the health of the target called name:
+// healthy when READY, unhealthy in any other state, missing when absent.
+func gatewayTargetHealth(name string, targets []types.TargetSummary) (status, detail string) {
+	for _, t := range targets {
+		if aws.ToString(t.Name) != name {
+			continue
+		}
+		if t.Status == types.TargetStatusReady {
+			return StatusHealthy, ""
+		}
+		return StatusUnhealthy, fmt.Sprintf("target %q is %s", name, t.Status)
+	}
+	return StatusMissing, fmt.Sprintf("target %q not found on gateway", name)
 }
 
 func (c *realAWSClient) checkCedarPolicy(ctx context.Context, res ResourceState) (string, error) {
@@ -1569,7 +1777,8 @@ func (c *realAWSClient) checkEvaluator(ctx context.Context, res ResourceState) (
 // waitForRuntimeReady polls GetAgentRuntime until the status is READY or a
 // terminal failure state.
 func (c *realAWSClient) waitForRuntimeReady(ctx context.Context, id string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("runtime", id)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
 			AgentRuntimeId: aws.String(id),
 		})
@@ -1590,15 +1799,50 @@ func (c *realAWSClient) waitForRuntimeReady(ctx context.Context, id string) erro
 			types.AgentRuntimeStatusDeleting:
 			// Transitional states — keep polling.
 		}
-		time.Sleep(pollInterval)
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
+	}
+	return fmt.Errorf("runtime %q did not become ready after %d attempts", id, c.poll.maxAttempts())
+}
+
+// WaitDeleted waits until a deleted runtime is gone, so that a replacement
+// can be created under its name. Other resource types need no wait:
+// CreateMemory itself waits out a deleting memory.
+func (c *realAWSClient) WaitDeleted(ctx context.Context, res ResourceState) error {
+	if res.Type != ResTypeAgentRuntime {
+		return nil
+	}
+	id := extractResourceID(res.ARN, "runtime")
+	if id == "" {
+		id = res.Name
+	}
+	return c.waitForRuntimeGone(ctx, id)
+}
+
+// waitForRuntimeGone polls GetAgentRuntime until the runtime is not found.
+func (c *realAWSClient) waitForRuntimeGone(ctx context.Context, id string) error {
+	tracker := c.newPollTracker("runtime", id)
+	for attempt := range c.poll.maxAttempts() {
+		out, err := c.client.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
+			AgentRuntimeId: aws.String(id),
+		})
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("polling runtime %q: %w", id, err)
+		}
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
 	}
-	return fmt.Errorf("runtime %q did not become ready after %d attempts", id, maxPollAttempts)
+	return fmt.Errorf("runtime %q still exists after %d attempts", id, c.poll.maxAttempts())
 }
 
 // waitForGatewayReady polls GetGateway until the status is READY or a
 // terminal failure state.
 func (c *realAWSClient) waitForGatewayReady(ctx context.Context, id string) error {
-	for range maxPollAttempts {
+	tracker := c.newPollTracker("gateway", id)
+	for attempt := range c.poll.maxAttempts() {
 		out, err := c.client.GetGateway(ctx, &bedrockagentcorecontrol.GetGatewayInput{
 			GatewayIdentifier: aws.String(id),
 		})
@@ -1616,7 +1860,8 @@ func (c *realAWSClient) waitForGatewayReady(ctx context.Context, id string) erro
 			types.GatewayStatusDeleting:
 			// Transitional states — keep polling.
 		}
-		time.Sleep(pollInterval)
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
 	}
-	return fmt.Errorf("gateway %q did not become ready after %d attempts", id, maxPollAttempts)
+	return fmt.Errorf("gateway %q did not become ready after %d attempts", id, c.poll.maxAttempts())
 }
@@ -0,0 +1,477 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulatedAccountID is the AWS account of every ARN the simulation hands
+// out.
+const SimulatedAccountID = "123456789012"
+
+// resTypePolicyEngine keys the policy engines of a SimulatedCloud. Engines
+// are not a resource type of their own: Cedar policies are created in them.
+const resTypePolicyEngine = "policy_engine"
+
+// simulatedARNKinds gives the service and resource path prefix of the ARN
+// the simulation returns for each resource type it names by ARN.
+var simulatedARNKinds = map[string][2]string{
+	ResTypeAgentRuntime:     {"bedrock-agentcore", "runtime/"},
+	ResTypeToolGateway:      {"bedrock", "gateway-tool/"},
+	ResTypeA2AEndpoint:      {"bedrock", "a2a-wiring/"},
+	ResTypeEvaluator:        {"bedrock", "evaluator/"},
+	ResTypeOnlineEvalConfig: {"bedrock", "online-evaluation-config/"},
+	ResTypeMemory:           {"bedrock", "memory/"},
+	ResTypeInferenceProfile: {"bedrock", "application-inference-profile/"},
+	ResTypeIdentityProvider: {"bedrock-agentcore", "token-vault/default/oauth2credentialprovider/"},
+	ResTypeCedarPolicy:      {"bedrock", "policy/"},
+	resTypePolicyEngine:     {"bedrock", "policy-engine/"},
+}
+
+// SimulatedResource is a resource in a SimulatedCloud.
+type SimulatedResource struct {
+	Type string
+	Name string
+	ARN  string
+	Tags map[string]string
+
+	// Seeded is true for resources added with Seed, which no deployment
+	// created.
+	Seeded bool
+}
+
+// SimulatedCloud is an in-memory model of the AgentCore resources of one
+// AWS account, for running Plan, Apply, Status, and Destroy without AWS.
+// Apply creates resources in it, tagged as the real adapter tags them, and
+// Destroy deletes them. A create that finds a resource of the same type
+// and name follows on_conflict as it does against AWS: adopting checks the
+// promptpack tags, "fail" fails, and "replace" recreates the resource. A
+// resource Apply created for the same pack and workspace is reused, as a
+// repeated Apply reuses it in AWS. It is safe for concurrent use.
+type SimulatedCloud struct {
+	mu        sync.Mutex
+	resources map[string]SimulatedResource // keyed by resourceKey
+}
+
+// NewSimulatedCloud returns an empty SimulatedCloud.
+func NewSimulatedCloud() *SimulatedCloud {
+	return &SimulatedCloud{resources: make(map[string]SimulatedResource)}
+}
+
+// Seed adds a resource no deployment created, as if someone else made it,
+// and returns its ARN. Tags decide whether Apply may adopt it.
+func (s *SimulatedCloud) Seed(region, resType, name string, tags map[string]string) string {
+	arn := simulatedARN(region, resType, name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[resourceKey(resType, name)] = SimulatedResource{
+		Type: resType, Name: name, ARN: arn, Tags: maps.Clone(tags), Seeded: true,
+	}
+	return arn
+}
+
+// Resource returns the resource of the given type and name.
+func (s *SimulatedCloud) Resource(resType, name string) (SimulatedResource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.resources[resourceKey(resType, name)]
+	return r, ok
+}
+
+// Resources returns every resource, sorted by type and name.
+func (s *SimulatedCloud) Resources() []SimulatedResource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]SimulatedResource, 0, len(s.resources))
+	for _, r := range s.resources {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// simulatedPut is the outcome of SimulatedCloud.put.
+type simulatedPut struct {
+	arn     string
+	adopted bool
+
+	// reconciled holds the tags merged onto an adopted resource.
+	reconciled map[string]string
+}
+
+// put creates the resource, or resolves the conflict with an existing one
+// of the same type and name. Adopting a resource merges cfg's tags onto it.
+func (s *SimulatedCloud) put(resType, name, arn string, cfg *Config) (simulatedPut, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	physical := name
+	if prior, renamed := cfg.renamed[name]; renamed {
+		physical = prior
+	}
+	key := resourceKey(resType, physical)
+	existing, ok := s.resources[key]
+	created := SimulatedResource{Type: resType, Name: physical, ARN: arn, Tags: maps.Clone(cfg.ResourceTags)}
+	switch {
+	case !ok:
+	case !conflictResourceTypes[resType]:
+		created.ARN = existing.ARN
+	case !existing.Seeded && checkOwnershipTags(resType, name, existing.Tags, cfg) == nil:
+		// Retag it, as a renamed pack's resources move to the new pack ID.
+		existing.Tags = created.Tags
+		s.resources[key] = existing
+		return simulatedPut{arn: existing.ARN}, nil
+	default:
+		adopt, err := s.resolveConflict(existing, cfg)
+		if err != nil {
+			return simulatedPut{arn: existing.ARN}, err
+		}
+		if adopt {
+			return s.adopt(key, existing, cfg), nil
+		}
+	}
+	s.resources[key] = created
+	return simulatedPut{arn: created.ARN}, nil
+}
+
+// adopt merges the tags Apply would have created existing with onto it.
+// The caller holds s.mu.
+func (s *SimulatedCloud) adopt(key string, existing SimulatedResource, cfg *Config) simulatedPut {
+	out := simulatedPut{arn: existing.ARN, adopted: true}
+	if untaggedResourceTypes[existing.Type] {
+		return out
+	}
+	out.reconciled = tagDelta(existing.Tags, adoptionTags(existing.Type, existing.Name, cfg))
+	if len(out.reconciled) > 0 {
+		existing.Tags = maps.Clone(existing.Tags)
+		if existing.Tags == nil {
+			existing.Tags = make(map[string]string, len(out.reconciled))
+		}
+		maps.Copy(existing.Tags, out.reconciled)
+		s.resources[key] = existing
+	}
+	return out
+}
+
+// resolveConflict applies cfg's on_conflict policy to an existing
+// resource, reporting whether it is adopted. Replacing leaves the caller to
+// overwrite it.
+func (s *SimulatedCloud) resolveConflict(existing SimulatedResource, cfg *Config) (bool, error) {
+	resType, name := existing.Type, existing.Name
+	switch cfg.OnConflict.policyFor(resType) {
+	case ConflictFail:
+		return false, fmt.Errorf("%s %q already exists and on_conflict is %q", resType, name, ConflictFail)
+	case ConflictReplace:
+		return false, nil
+	}
+	if !untaggedResourceTypes[resType] {
+		if err := checkOwnershipTags(resType, name, existing.Tags, cfg); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// remove deletes the resource with the given ARN, if any.
+func (s *SimulatedCloud) remove(arn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, r := range s.resources {
+		if r.ARN == arn {
+			delete(s.resources, key)
+		}
+	}
+}
+
+// has reports whether a resource with the given ARN exists.
+func (s *SimulatedCloud) has(arn string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.resources {
+		if r.ARN == arn {
+			return true
+		}
+	}
+	return false
+}
+
+// simulatedARN returns the ARN the simulation gives a resource.
+func simulatedARN(region, resType, name string) string {
+	kind := simulatedARNKinds[resType]
+	return formatARN(kind[0], region, SimulatedAccountID, kind[1]+name)
+}
+
+// NewSimulatedProvider returns a Provider whose AWS calls act on cloud
+// instead of AWS. No AWS credentials are required. Evaluation results are
+// always empty.
+func NewSimulatedProvider(cloud *SimulatedCloud) *Provider {
+	return &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			c := newSimulatedAWSClient(cfg.Region)
+			c.cloud = cloud
+			return c, nil
+		},
+		destroyerFunc: func(_ context.Context, _ *Config) (resourceDestroyer, error) {
+			return &simulatedDestroyer{cloud: cloud}, nil
+		},
+		checkerFunc: func(_ context.Context, _ *Config) (resourceChecker, error) {
+			return &simulatedChecker{cloud: cloud}, nil
+		},
+		evalResultsFunc: func(_ context.Context, _ *Config) (evalResultsQuerier, error) {
+			return &simulatedEvalResults{}, nil
+		},
+	}
+}
+
+// simulatedAWSClient returns mock ARNs for all operations. With a cloud it
+// records what it creates there; without one it records nothing.
+type simulatedAWSClient struct {
+	region     string
+	accountID  string
+	cloud      *SimulatedCloud
+	adopted    map[string]bool
+	reconciled map[string]map[string]string
+}
+
+func newSimulatedAWSClient(region string) *simulatedAWSClient {
+	return &simulatedAWSClient{
+		region:    region,
+		accountID: SimulatedAccountID,
+	}
+}
+
+// create records a resource of the given type and name in the cloud and
+// returns its ARN.
+func (c *simulatedAWSClient) create(resType, name string, cfg *Config) (string, error) {
+	return c.record(resType, name, simulatedARN(c.region, resType, name), cfg)
+}
+
+// record records the resource at arn in the cloud and returns the ARN of
+// the resource Apply ends up with.
+func (c *simulatedAWSClient) record(resType, name, arn string, cfg *Config) (string, error) {
+	if c.cloud == nil {
+		return arn, nil
+	}
+	put, err := c.cloud.put(resType, name, arn, cfg)
+	if put.adopted {
+		logf("agentcore: simulated %s %q already exists, adopting", resType, name)
+		if c.adopted == nil {
+			c.adopted = make(map[string]bool)
+		}
+		c.adopted[put.arn] = true
+	}
+	if len(put.reconciled) > 0 {
+		if c.reconciled == nil {
+			c.reconciled = make(map[string]map[string]string)
+		}
+		c.reconciled[put.arn] = put.reconciled
+	}
+	return put.arn, err
+}
+
+// wasAdopted implements adoptionTracker.
+func (c *simulatedAWSClient) wasAdopted(arn string) bool {
+	return c.adopted[arn]
+}
+
+// reconciledTags implements tagReconciler.
+func (c *simulatedAWSClient) reconciledTags(arn string) map[string]string {
+	return c.reconciled[arn]
+}
+
+func (c *simulatedAWSClient) CreateRuntime(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeAgentRuntime, name, cfg)
+}
+
+func (c *simulatedAWSClient) UpdateRuntime(_ context.Context, arn string, _ string, _ *Config) (string, error) {
+	return arn, nil
+}
+
+func (c *simulatedAWSClient) DeployRuntimeEndpoint(
+	_ context.Context, runtimeARN, endpointName string, cfg *Config,
+) (string, string, error) {
+	arn, err := c.record(ResTypeRuntimeEndpoint, endpointName, runtimeARN+"/runtime-endpoint/"+endpointName, cfg)
+	return arn, "1", err
+}
+
+func (c *simulatedAWSClient) PinRuntimeEndpoint(
+	_ context.Context, runtimeARN, endpointName, _ string, _ *Config,
+) (string, error) {
+	return runtimeARN + "/runtime-endpoint/" + endpointName, nil
+}
+
+func (c *simulatedAWSClient) CreateGatewayTool(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeToolGateway, name, cfg)
+}
+
+func (c *simulatedAWSClient) CreateA2AWiring(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeA2AEndpoint, name, cfg)
+}
+
+func (c *simulatedAWSClient) CreateEvaluator(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeEvaluator, name, cfg)
+}
+
+func (c *simulatedAWSClient) CreateOnlineEvalConfig(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeOnlineEvalConfig, name, cfg)
+}
+
+func (c *simulatedAWSClient) UpdateOnlineEvalConfig(
+	_ context.Context, arn string, _ string, _ *Config,
+) (string, error) {
+	return arn, nil
+}
+
+func (c *simulatedAWSClient) CreateMemory(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeMemory, name, cfg)
+}
+
+func (c *simulatedAWSClient) CreatePolicyEngine(
+	_ context.Context, name string, cfg *Config,
+) (string, string, error) {
+	arn, err := c.create(resTypePolicyEngine, name, cfg)
+	engineID := "pe-" + name
+	return arn, engineID, err
+}
+
+func (c *simulatedAWSClient) CreateInferenceProfile(
+	_ context.Context, name, _ string, cfg *Config,
+) (string, error) {
+	return c.create(ResTypeInferenceProfile, name, cfg)
+}
+
+func (c *simulatedAWSClient) AssociatePolicyEngine(
+	_ context.Context, _, _ string, _ *Config,
+) error {
+	return nil
+}
+
+func (c *simulatedAWSClient) CreateCedarPolicy(
+	_ context.Context, engineID string, name string, _ string, cfg *Config,
+) (string, string, error) {
+	arn, err := c.create(ResTypeCedarPolicy, engineID+"/"+name, cfg)
+	policyID := "pol-" + name
+	return arn, policyID, err
+}
+
+func (c *simulatedAWSClient) UploadCodePackage(
+	_ context.Context, _ []byte, _, _ string,
+) error {
+	logf("agentcore: simulated S3 upload")
+	return nil
+}
+
+func (c *simulatedAWSClient) PutLogGroup(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.record(ResTypeLogGroup, name, logGroupARN(c.region, c.accountID, name), cfg)
+}
+
+func (c *simulatedAWSClient) PutEvalAlert(
+	_ context.Context, name, onlineEvalARN string, cfg *Config,
+) (*evalAlertDeployment, error) {
+	ruleARN := fmt.Sprintf("arn:aws:events:%s:%s:rule/%s", c.region, c.accountID, cfg.awsName(name))
+	arn, err := c.record(ResTypeEvalAlert, name, ruleARN, cfg)
+	if err != nil {
+		return nil, err
+	}
+	id := extractResourceID(onlineEvalARN, "online-evaluation-config")
+	target := cfg.EvalAlert.TargetARN
+	if cfg.EvalAlert.Endpoint != "" {
+		target = fmt.Sprintf("arn:aws:events:%s:%s:api-destination/%s", c.region, c.accountID, cfg.awsName(name))
+	}
+	return &evalAlertDeployment{ruleARN: arn, logGroup: evalResultsLogGroupPrefix + id, target: target}, nil
+}
+
+func (c *simulatedAWSClient) PutLogsQuery(_ context.Context, def logsQueryDefinition, cfg *Config) (string, error) {
+	id := def.ID
+	if id == "" {
+		id = "query-" + strings.ReplaceAll(def.Name, "/", "-")
+	}
+	return c.record(ResTypeLogsQuery, def.Name, logsQueryARN(c.region, c.accountID, id), cfg)
+}
+
+func (c *simulatedAWSClient) CreateIdentityProvider(_ context.Context, name string, cfg *Config) (string, error) {
+	return c.create(ResTypeIdentityProvider, name, cfg)
+}
+
+func (c *simulatedAWSClient) UpdateIdentityProvider(
+	_ context.Context, arn string, _ string, _ *Config,
+) (string, error) {
+	return arn, nil
+}
+
+// simulatedTrustPolicy is the trust policy of every simulated IAM role.
+const simulatedTrustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+	`"Principal":{"Service":"` + agentCoreServicePrincipal + `"},"Action":"sts:AssumeRole"}]}`
+
+// roleTrustPolicy implements dryRunChecker: every role exists and trusts
+// AgentCore.
+func (c *simulatedAWSClient) roleTrustPolicy(_ context.Context, _ string) (string, error) {
+	return simulatedTrustPolicy, nil
+}
+
+// countResources implements dryRunChecker, counting the cloud's resources.
+func (c *simulatedAWSClient) countResources(_ context.Context, resType string) (int, error) {
+	if c.cloud == nil {
+		return 0, nil
+	}
+	n := 0
+	for _, r := range c.cloud.Resources() {
+		if r.Type == resType {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// bucketExists implements dryRunChecker: every bucket exists.
+func (c *simulatedAWSClient) bucketExists(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+// simulatedDestroyer deletes resources from its cloud, or only logs the
+// deletion without one.
+type simulatedDestroyer struct {
+	cloud *SimulatedCloud
+}
+
+func (s *simulatedDestroyer) DeleteResource(_ context.Context, res ResourceState) error {
+	logf("agentcore: simulated delete %s %q (arn=%s)", res.Type, res.Name, res.ARN)
+	if s.cloud != nil {
+		s.cloud.remove(res.ARN)
+	}
+	return nil
+}
+
+// simulatedChecker reports resources missing from its cloud. Without a
+// cloud it assumes all existing resources are healthy.
+type simulatedChecker struct {
+	cloud *SimulatedCloud
+}
+
+func (s *simulatedChecker) CheckResource(_ context.Context, res ResourceState) (string, error) {
+	logf("agentcore: simulated health check %s %q (arn=%s)", res.Type, res.Name, res.ARN)
+	if s.cloud != nil && !s.cloud.has(res.ARN) {
+		return StatusMissing, nil
+	}
+	return StatusHealthy, nil
+}
+
+// simulatedEvalResults returns canned scores for every window.
+type simulatedEvalResults struct {
+	scores []EvalScore
+}
+
+func (s *simulatedEvalResults) QueryEvalScores(
+	_ context.Context, _ ResourceState, _, _ time.Time,
+) ([]EvalScore, error) {
+	return s.scores, nil
+}
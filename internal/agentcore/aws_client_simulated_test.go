@@ -2,108 +2,158 @@ package agentcore
 
 import (
 	"context"
-	"fmt"
-	"log"
-)
+	"encoding/json"
+	"strings"
+	"testing"
 
-// simulatedAWSClient returns mock ARNs for all operations.
-type simulatedAWSClient struct {
-	region    string
-	accountID string
-}
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
 
-func newSimulatedAWSClient(region string) *simulatedAWSClient {
-	return &simulatedAWSClient{
-		region:    region,
-		accountID: "123456789012",
+// newSimulatedProvider creates an Provider wired with simulated
+// (in-memory) clients for unit testing. No AWS credentials are required,
+// and nothing is recorded between calls.
+func newSimulatedProvider() *Provider {
+	return &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			return newSimulatedAWSClient(cfg.Region), nil
+		},
+		destroyerFunc: func(_ context.Context, _ *Config) (resourceDestroyer, error) {
+			return &simulatedDestroyer{}, nil
+		},
+		checkerFunc: func(_ context.Context, _ *Config) (resourceChecker, error) {
+			return &simulatedChecker{}, nil
+		},
+		evalResultsFunc: func(_ context.Context, _ *Config) (evalResultsQuerier, error) {
+			return &simulatedEvalResults{}, nil
+		},
 	}
 }
 
-func (c *simulatedAWSClient) CreateRuntime(_ context.Context, name string, _ *Config) (string, error) {
-	return fmt.Sprintf("arn:aws:bedrock-agentcore:%s:%s:runtime/%s", c.region, c.accountID, name), nil
-}
-
-func (c *simulatedAWSClient) UpdateRuntime(_ context.Context, arn string, _ string, _ *Config) (string, error) {
-	return arn, nil
-}
-
-func (c *simulatedAWSClient) CreateGatewayTool(_ context.Context, name string, _ *Config) (string, error) {
-	return fmt.Sprintf("arn:aws:bedrock:%s:%s:gateway-tool/%s", c.region, c.accountID, name), nil
-}
-
-func (c *simulatedAWSClient) CreateA2AWiring(_ context.Context, name string, _ *Config) (string, error) {
-	return fmt.Sprintf("arn:aws:bedrock:%s:%s:a2a-wiring/%s", c.region, c.accountID, name), nil
-}
-
-func (c *simulatedAWSClient) CreateEvaluator(_ context.Context, name string, _ *Config) (string, error) {
-	return fmt.Sprintf("arn:aws:bedrock:%s:%s:evaluator/%s", c.region, c.accountID, name), nil
-}
-
-func (c *simulatedAWSClient) CreateOnlineEvalConfig(_ context.Context, name string, _ *Config) (string, error) {
-	return fmt.Sprintf("arn:aws:bedrock:%s:%s:online-evaluation-config/%s", c.region, c.accountID, name), nil
-}
-
-func (c *simulatedAWSClient) CreateMemory(_ context.Context, name string, _ *Config) (string, error) {
-	return fmt.Sprintf("arn:aws:bedrock:%s:%s:memory/%s", c.region, c.accountID, name), nil
+// applyToCloud applies singleAgentPack to cloud with deployConfig and
+// returns the state.
+func applyToCloud(t *testing.T, cloud *SimulatedCloud, deployConfig string) (*AdapterState, error) {
+	t.Helper()
+	_, raw, err := collectEvents(t, NewSimulatedProvider(cloud), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: deployConfig, ArenaConfig: validArenaConfigJSON,
+	})
+	if raw == "" {
+		return nil, err
+	}
+	var state AdapterState
+	if jsonErr := json.Unmarshal([]byte(raw), &state); jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	return &state, err
 }
 
-func (c *simulatedAWSClient) CreatePolicyEngine(
-	_ context.Context, name string, _ *Config,
-) (string, string, error) {
-	arn := fmt.Sprintf("arn:aws:bedrock:%s:%s:policy-engine/%s", c.region, c.accountID, name)
-	engineID := "pe-" + name
-	return arn, engineID, nil
-}
+func TestSimulatedCloud_ApplyStatusDestroy(t *testing.T) {
+	cloud := NewSimulatedCloud()
+	deployConfig := validConfig(t)
+	state, err := applyToCloud(t, cloud, deployConfig)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	runtime, ok := cloud.Resource(ResTypeAgentRuntime, "mypack")
+	if !ok || runtime.Tags[TagKeyPackID] != "mypack" || runtime.Seeded {
+		t.Fatalf("runtime = %+v, %v, want a tagged runtime", runtime, ok)
+	}
 
-func (c *simulatedAWSClient) AssociatePolicyEngine(
-	_ context.Context, _ string, _ *Config,
-) error {
-	return nil
-}
+	again, err := applyToCloud(t, cloud, deployConfig)
+	if err != nil {
+		t.Fatalf("second Apply: %v", err)
+	}
+	if rt, _ := findResourceOfType(again, ResTypeAgentRuntime); !rt.isOwned() || rt.ARN != runtime.ARN {
+		t.Errorf("second Apply runtime = %+v, want the owned %s", rt, runtime.ARN)
+	}
 
-func (c *simulatedAWSClient) CreateCedarPolicy(
-	_ context.Context, engineID string, name string, _ string, _ *Config,
-) (string, string, error) {
-	arn := fmt.Sprintf("arn:aws:bedrock:%s:%s:policy/%s/%s", c.region, c.accountID, engineID, name)
-	policyID := "pol-" + name
-	return arn, policyID, nil
-}
+	raw := mustJSON(t, state)
+	cloud.remove(runtime.ARN)
+	status, err := NewSimulatedProvider(cloud).Status(context.Background(),
+		&deploy.StatusRequest{DeployConfig: deployConfig, PriorState: raw})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, r := range status.Resources {
+		if r.Type == ResTypeAgentRuntime && r.Status != StatusMissing {
+			t.Errorf("deleted runtime status = %q, want %q", r.Status, StatusMissing)
+		}
+	}
 
-func (c *simulatedAWSClient) UploadCodePackage(
-	_ context.Context, _ []byte, _, _ string,
-) error {
-	log.Printf("agentcore: simulated S3 upload")
-	return nil
+	err = NewSimulatedProvider(cloud).Destroy(context.Background(),
+		&deploy.DestroyRequest{DeployConfig: deployConfig, PriorState: raw},
+		func(*deploy.DestroyEvent) error { return nil })
+	if err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if left := cloud.Resources(); len(left) != 0 {
+		t.Errorf("resources left after Destroy: %+v", left)
+	}
 }
 
-// simulatedDestroyer is a placeholder that logs intent without calling AWS.
-type simulatedDestroyer struct{}
-
-func (s *simulatedDestroyer) DeleteResource(_ context.Context, res ResourceState) error {
-	log.Printf("agentcore: simulated delete %s %q (arn=%s)", res.Type, res.Name, res.ARN)
-	return nil
+func TestSimulatedCloud_Conflicts(t *testing.T) {
+	ownTags := map[string]string{TagKeyPackID: "mypack"}
+	otherTags := map[string]string{TagKeyPackID: "otherpack"}
+	tests := []struct {
+		name       string
+		tags       map[string]string
+		onConflict string
+		wantErr    string
+		wantOwned  bool
+	}{
+		{"adopt own", ownTags, "", "", false},
+		{"adopt other pack", otherTags, "", "refusing to adopt", false},
+		{"fail", ownTags, `"on_conflict":"fail",`, `on_conflict is "fail"`, false},
+		{"replace", otherTags, `"on_conflict":"replace","confirm_replace":true,`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloud := NewSimulatedCloud()
+			seeded := cloud.Seed("us-west-2", ResTypeAgentRuntime, "mypack", tt.tags)
+			state, err := applyToCloud(t, cloud, `{`+tt.onConflict+validConfig(t)[1:])
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			rt, _ := findResourceOfType(state, ResTypeAgentRuntime)
+			if rt.ARN != seeded || rt.isOwned() != tt.wantOwned {
+				t.Errorf("runtime = %+v, want ARN %s owned %v", rt, seeded, tt.wantOwned)
+			}
+			if got, _ := cloud.Resource(ResTypeAgentRuntime, "mypack"); got.Seeded == tt.wantOwned {
+				t.Errorf("cloud runtime = %+v, want replaced %v", got, tt.wantOwned)
+			}
+		})
+	}
 }
 
-// simulatedChecker is a placeholder that assumes all existing resources are healthy.
-type simulatedChecker struct{}
-
-func (s *simulatedChecker) CheckResource(_ context.Context, res ResourceState) (string, error) {
-	log.Printf("agentcore: simulated health check %s %q (arn=%s)", res.Type, res.Name, res.ARN)
-	return "healthy", nil
-}
+func TestSimulatedCloud_AdoptionReconcilesTags(t *testing.T) {
+	cloud := NewSimulatedCloud()
+	cloud.Seed("us-west-2", ResTypeAgentRuntime, "mypack",
+		map[string]string{TagKeyPackID: "mypack", TagKeyVersion: "v0.9.0", "team": "ml"})
+	deployConfig := `{"tags":{"cost-center":"42"},` + validConfig(t)[1:]
+	state, err := applyToCloud(t, cloud, deployConfig)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	rt, _ := findResourceOfType(state, ResTypeAgentRuntime)
+	want := "cost-center=42,promptpack:agent=mypack,promptpack:version=v1.0.0"
+	if got := rt.Metadata[metaReconciledTags]; got != want {
+		t.Errorf("%s = %q, want %q", metaReconciledTags, got, want)
+	}
+	got, _ := cloud.Resource(ResTypeAgentRuntime, "mypack")
+	if got.Tags["cost-center"] != "42" || got.Tags[TagKeyVersion] != "v1.0.0" || got.Tags["team"] != "ml" {
+		t.Errorf("adopted runtime tags = %v, want the pack's tags merged over its own", got.Tags)
+	}
 
-// newSimulatedProvider creates an Provider wired with simulated
-// (in-memory) clients for unit testing. No AWS credentials are required.
-func newSimulatedProvider() *Provider {
-	return &Provider{
-		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
-			return newSimulatedAWSClient(cfg.Region), nil
-		},
-		destroyerFunc: func(_ context.Context, _ *Config) (resourceDestroyer, error) {
-			return &simulatedDestroyer{}, nil
-		},
-		checkerFunc: func(_ context.Context, _ *Config) (resourceChecker, error) {
-			return &simulatedChecker{}, nil
-		},
+	again, err := applyToCloud(t, cloud, deployConfig)
+	if err != nil {
+		t.Fatalf("second Apply: %v", err)
+	}
+	if rt, _ := findResourceOfType(again, ResTypeAgentRuntime); rt.Metadata[metaReconciledTags] != "" {
+		t.Errorf("second Apply reconciled %q, want nothing", rt.Metadata[metaReconciledTags])
 	}
 }
@@ -0,0 +1,217 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// Environment variables the region falls back to, in order, when neither
+// the region override nor the config sets one.
+var regionEnvVars = []string{"AWS_REGION", "AWS_DEFAULT_REGION"}
+
+// envVarNameRE matches an environment variable name.
+var envVarNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AWSCredentialsEnv names the environment variables the adapter reads
+// explicit AWS credentials from, so several accounts' keys can sit side by
+// side without overwriting AWS_ACCESS_KEY_ID.
+type AWSCredentialsEnv struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token,omitempty"`
+}
+
+// SetRegionOverride makes every request use region, ahead of the deploy
+// config's region and the AWS_REGION environment variable. An empty
+// region removes the override.
+func (p *Provider) SetRegionOverride(region string) {
+	p.regionOverride = region
+}
+
+// loadConfig resolves the ${var.*} placeholders of a deploy config from
+// the environment and vars, parses it, and resolves its region: the
+// provider's region override wins, then the config's region, then the
+// environment.
+func (p *Provider) loadConfig(raw string, vars map[string]string) (*Config, error) {
+	resolved, used, err := interpolateConfigVars(raw, vars)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseResolvedConfig(resolved, used)
+}
+
+// loadConfigUnresolved is loadConfig for ValidateConfig, which gets no
+// arena config: placeholders naming a variable the environment does not
+// set are left in place, and their names returned, rather than failing.
+func (p *Provider) loadConfigUnresolved(raw string) (*Config, []string, error) {
+	resolved, used, unresolved, err := resolveConfigVars(raw, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := p.parseResolvedConfig(resolved, used)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, unresolved, nil
+}
+
+// parseResolvedConfig parses a deploy config whose placeholders were
+// resolved with used, and resolves its region.
+func (p *Provider) parseResolvedConfig(resolved string, used map[string]string) (*Config, error) {
+	cfg, err := parseConfig(resolved)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Variables = used
+	cfg.Region = resolveRegion(p.regionOverride, cfg.Region)
+	return cfg, nil
+}
+
+// resolveRegion returns the first of override, configured, and the
+// regionEnvVars that is set.
+func resolveRegion(override, configured string) string {
+	if override != "" {
+		return override
+	}
+	if configured != "" {
+		return configured
+	}
+	for _, name := range regionEnvVars {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// validateAWSCredentials checks the profile, shared file, and credentials
+// env settings.
+func validateAWSCredentials(c *Config) []string {
+	var errs []string
+	for _, files := range []struct {
+		field string
+		paths []string
+	}{
+		{"aws_shared_config_files", c.AWSSharedConfigFiles},
+		{"aws_shared_credentials_files", c.AWSSharedCredentialsFiles},
+	} {
+		for i, path := range files.paths {
+			if strings.TrimSpace(path) == "" {
+				errs = append(errs, fmt.Sprintf("%s[%d] must not be empty", files.field, i))
+			}
+		}
+	}
+	env := c.AWSCredentialsEnv
+	if env == nil {
+		return errs
+	}
+	if c.AWSProfile != "" {
+		errs = append(errs, "set only one of aws_profile and aws_credentials_env")
+	}
+	names := map[string]string{"access_key_id": env.AccessKeyID, "secret_access_key": env.SecretAccessKey}
+	if env.SessionToken != "" {
+		names["session_token"] = env.SessionToken
+	}
+	for _, field := range sortedKeys(names) {
+		if !envVarNameRE.MatchString(names[field]) {
+			errs = append(errs, fmt.Sprintf("aws_credentials_env.%s %q must be an environment variable name",
+				field, names[field]))
+		}
+	}
+	return errs
+}
+
+// loadAWSConfig loads the AWS SDK config for cfg's region, credentials,
+// and endpoint variant settings. Without credentials settings, it is the
+// default credential chain.
+func loadAWSConfig(ctx context.Context, cfg *Config) (aws.Config, error) {
+	opts := []func(*awscfg.LoadOptions) error{awscfg.WithRegion(cfg.Region)}
+	if cfg.AWSProfile != "" {
+		opts = append(opts, awscfg.WithSharedConfigProfile(cfg.AWSProfile))
+	}
+	if len(cfg.AWSSharedConfigFiles) > 0 {
+		opts = append(opts, awscfg.WithSharedConfigFiles(cfg.AWSSharedConfigFiles))
+	}
+	if len(cfg.AWSSharedCredentialsFiles) > 0 {
+		opts = append(opts, awscfg.WithSharedCredentialsFiles(cfg.AWSSharedCredentialsFiles))
+	}
+	if env := cfg.AWSCredentialsEnv; env != nil {
+		provider, err := envCredentialsProvider(env)
+		if err != nil {
+			return aws.Config{}, err
+		}
+		opts = append(opts, awscfg.WithCredentialsProvider(provider))
+	}
+	if cfg.FIPSEndpoints {
+		opts = append(opts, awscfg.WithUseFIPSEndpoint(aws.FIPSEndpointStateEnabled))
+	}
+	if cfg.DualStackEndpoints {
+		opts = append(opts, awscfg.WithUseDualStackEndpoint(aws.DualStackEndpointStateEnabled))
+	}
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("load AWS config: %w", err)
+	}
+	return awsCfg, nil
+}
+
+// envCredentialsProvider returns static credentials read from the
+// variables env names.
+func envCredentialsProvider(env *AWSCredentialsEnv) (aws.CredentialsProvider, error) {
+	var missing []string
+	lookup := func(name string) string {
+		v := os.Getenv(name)
+		if v == "" {
+			missing = append(missing, name)
+		}
+		return v
+	}
+	accessKey, secretKey := lookup(env.AccessKeyID), lookup(env.SecretAccessKey)
+	var token string
+	if env.SessionToken != "" {
+		token = lookup(env.SessionToken)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("aws_credentials_env: %s not set", strings.Join(missing, ", "))
+	}
+	return credentials.NewStaticCredentialsProvider(accessKey, secretKey, token), nil
+}
+
+// awsClientKey identifies the AWS clients a config needs: configs with the
+// same key can share them.
+func (c *Config) awsClientKey() string {
+	key := []string{c.Region, c.AWSProfile,
+		strings.Join(c.AWSSharedConfigFiles, ","), strings.Join(c.AWSSharedCredentialsFiles, ",")}
+	if env := c.AWSCredentialsEnv; env != nil {
+		key = append(key, env.AccessKeyID, env.SecretAccessKey, env.SessionToken)
+	}
+	for _, service := range sortedKeys(c.Endpoints) {
+		key = append(key, service+"="+c.Endpoints[service])
+	}
+	key = append(key, fmt.Sprintf("fips=%t,dualstack=%t", c.FIPSEndpoints, c.DualStackEndpoints))
+	return strings.Join(key, "|")
+}
+
+// diagnoseAWSCredentials warns when aws_credentials_env names variables
+// that are not set in the deploy environment.
+func diagnoseAWSCredentials(cfg *Config) []DiagnosticWarning {
+	env := cfg.AWSCredentialsEnv
+	if env == nil {
+		return nil
+	}
+	if _, err := envCredentialsProvider(env); err != nil {
+		return []DiagnosticWarning{{
+			Category: ErrCategoryConfiguration,
+			Message:  err.Error(),
+			Hint:     "export the variables before running the adapter, or remove aws_credentials_env",
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package agentcore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+)
+
+func clearRegionEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range regionEnvVars {
+		t.Setenv(name, "")
+	}
+}
+
+func TestResolveRegion(t *testing.T) {
+	tests := []struct {
+		name, override, configured, awsRegion, awsDefault, want string
+	}{
+		{"override wins", "eu-west-1", "us-west-2", "us-east-1", "", "eu-west-1"},
+		{"config over env", "", "us-west-2", "us-east-1", "", "us-west-2"},
+		{"AWS_REGION", "", "", "us-east-1", "ap-south-1", "us-east-1"},
+		{"AWS_DEFAULT_REGION", "", "", "", "ap-south-1", "ap-south-1"},
+		{"none", "", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", tt.awsRegion)
+			t.Setenv("AWS_DEFAULT_REGION", tt.awsDefault)
+			if got := resolveRegion(tt.override, tt.configured); got != tt.want {
+				t.Errorf("resolveRegion = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_RegionOverride(t *testing.T) {
+	clearRegionEnv(t)
+	p := newSimulatedProvider()
+	req := &deploy.ValidateRequest{Config: `{"runtime_role_arn":"arn:aws:iam::123456789012:role/test"}`}
+
+	resp, err := p.ValidateConfig(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasRegionError(resp.Errors) {
+		t.Fatalf("errors = %v, want a missing region error", resp.Errors)
+	}
+
+	p.SetRegionOverride("us-west-2")
+	resp, err = p.ValidateConfig(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasRegionError(resp.Errors) {
+		t.Errorf("errors = %v, want the override region to satisfy validation", resp.Errors)
+	}
+}
+
+func hasRegionError(errs []string) bool {
+	for _, e := range errs {
+		if strings.Contains(e, "region") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateAWSCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{"none", Config{}, nil},
+		{"profile and files", Config{AWSProfile: "staging", AWSSharedConfigFiles: []string{"/etc/aws/config"}}, nil},
+		{"env", Config{AWSCredentialsEnv: &AWSCredentialsEnv{
+			AccessKeyID: "STAGING_KEY_ID", SecretAccessKey: "STAGING_SECRET", SessionToken: "STAGING_TOKEN",
+		}}, nil},
+		{"empty file", Config{AWSSharedCredentialsFiles: []string{" "}},
+			[]string{"aws_shared_credentials_files[0] must not be empty"}},
+		{"profile and env", Config{AWSProfile: "staging", AWSCredentialsEnv: &AWSCredentialsEnv{
+			AccessKeyID: "A", SecretAccessKey: "B",
+		}}, []string{"set only one of aws_profile and aws_credentials_env"}},
+		{"bad names", Config{AWSCredentialsEnv: &AWSCredentialsEnv{AccessKeyID: "1KEY", SessionToken: "TOKEN-X"}},
+			[]string{`access_key_id "1KEY"`, `secret_access_key ""`, `session_token "TOKEN-X"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateAWSCredentials(&tt.cfg)
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("errors[%d] = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadAWSConfig_CredentialsEnv(t *testing.T) {
+	t.Setenv("STAGING_KEY_ID", "AKIDSTAGING")
+	t.Setenv("STAGING_SECRET", "staging-secret")
+	t.Setenv("STAGING_TOKEN", "")
+	env := &AWSCredentialsEnv{AccessKeyID: "STAGING_KEY_ID", SecretAccessKey: "STAGING_SECRET"}
+
+	awsCfg, err := loadAWSConfig(context.Background(), &Config{Region: "us-west-2", AWSCredentialsEnv: env})
+	if err != nil {
+		t.Fatalf("loadAWSConfig: %v", err)
+	}
+	creds, err := awsCfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDSTAGING" || creds.SecretAccessKey != "staging-secret" {
+		t.Errorf("credentials = %s/%s, want the staging variables", creds.AccessKeyID, creds.SecretAccessKey)
+	}
+
+	env.SessionToken = "STAGING_TOKEN"
+	_, err = loadAWSConfig(context.Background(), &Config{Region: "us-west-2", AWSCredentialsEnv: env})
+	if err == nil || !strings.Contains(err.Error(), "STAGING_TOKEN not set") {
+		t.Errorf("err = %v, want the unset session token variable", err)
+	}
+	if w := diagnoseAWSCredentials(&Config{AWSCredentialsEnv: env}); len(w) != 1 {
+		t.Errorf("warnings = %v, want one for the unset variable", w)
+	}
+}
+
+func TestLoadAWSConfig_ProfileFromSharedFiles(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	credsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(configFile, []byte("[profile staging]\nregion = eu-west-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(credsFile,
+		[]byte("[staging]\naws_access_key_id = AKIDFILE\naws_secret_access_key = file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	awsCfg, err := loadAWSConfig(context.Background(), &Config{
+		Region:                    "us-west-2",
+		AWSProfile:                "staging",
+		AWSSharedConfigFiles:      []string{configFile},
+		AWSSharedCredentialsFiles: []string{credsFile},
+	})
+	if err != nil {
+		t.Fatalf("loadAWSConfig: %v", err)
+	}
+	if awsCfg.Region != "us-west-2" {
+		t.Errorf("Region = %q, want the deploy config's region over the profile's", awsCfg.Region)
+	}
+	creds, err := awsCfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDFILE" {
+		t.Errorf("AccessKeyID = %q, want the profile's key", creds.AccessKeyID)
+	}
+}
+
+func TestLoadAWSConfig_EndpointVariants(t *testing.T) {
+	awsCfg, err := loadAWSConfig(context.Background(),
+		&Config{Region: "us-west-2", FIPSEndpoints: true, DualStackEndpoints: true})
+	if err != nil {
+		t.Fatalf("loadAWSConfig: %v", err)
+	}
+	opts := bedrockagentcorecontrol.NewFromConfig(awsCfg).Options().EndpointOptions
+	if opts.UseFIPSEndpoint != aws.FIPSEndpointStateEnabled ||
+		opts.UseDualStackEndpoint != aws.DualStackEndpointStateEnabled {
+		t.Errorf("endpoint options = %+v, want FIPS and dual-stack enabled", opts)
+	}
+}
+
+func TestAWSClientKey(t *testing.T) {
+	base := Config{Region: "us-west-2"}
+	staging := Config{Region: "us-west-2", AWSProfile: "staging"}
+	other := Config{Region: "eu-west-1"}
+	if base.awsClientKey() == staging.awsClientKey() || base.awsClientKey() == other.awsClientKey() {
+		t.Error("configs with different regions or profiles share a client key")
+	}
+	if base.awsClientKey() != (&Config{Region: "us-west-2", DryRun: true}).awsClientKey() {
+		t.Error("settings unrelated to AWS changed the client key")
+	}
+}
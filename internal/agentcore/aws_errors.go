@@ -0,0 +1,91 @@
+package agentcore
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// AWS error codes the adapter branches on. Every modeled exception in the
+// AgentCore control-plane, data-plane, and CloudWatch Logs SDKs implements
+// smithy.APIError and reports its shape name as the code, so matching on
+// the code covers all three clients as well as unmodeled API errors.
+const (
+	errCodeNotFound      = "ResourceNotFoundException"
+	errCodeConflict      = "ConflictException"
+	errCodeAlreadyExists = "ResourceAlreadyExistsException"
+	errCodeValidation    = "ValidationException"
+	errCodeAccessDenied  = "AccessDeniedException"
+	errCodeUnauthorized  = "UnauthorizedException"
+)
+
+// errorClass is a DeployError category with its remediation hint.
+type errorClass struct {
+	category    string
+	remediation string
+}
+
+// errorCodeClasses classifies API error codes that map to a category more
+// specific than ErrCategoryResource.
+var errorCodeClasses = map[string]errorClass{
+	errCodeAccessDenied: {ErrCategoryPermission, hintCheckIAM},
+	errCodeUnauthorized: {ErrCategoryPermission, hintCheckIAM},
+	errCodeValidation:   {ErrCategoryConfiguration, hintCheckConfig},
+}
+
+// awsAPIError returns the AWS API error wrapped in err, or nil when err
+// did not come from an AWS API response.
+func awsAPIError(err error) smithy.APIError {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return nil
+}
+
+// awsErrorCode returns the code of the AWS API error wrapped in err, or ""
+// when there is none.
+func awsErrorCode(err error) string {
+	if apiErr := awsAPIError(err); apiErr != nil {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+// isNotFound reports whether err is a ResourceNotFoundException.
+func isNotFound(err error) bool {
+	return awsErrorCode(err) == errCodeNotFound
+}
+
+// isConflictError reports whether err is a ConflictException, which
+// AgentCore returns when a resource with the same name already exists.
+func isConflictError(err error) bool {
+	return awsErrorCode(err) == errCodeConflict
+}
+
+// isAlreadyExists reports whether err is a ResourceAlreadyExistsException,
+// as returned by CloudWatch Logs.
+func isAlreadyExists(err error) bool {
+	return awsErrorCode(err) == errCodeAlreadyExists
+}
+
+// isMemoryAlreadyExists reports whether a CreateMemory error means the name
+// is taken. AgentCore reports this either as a ConflictException or as a
+// ValidationException whose message says the memory already exists; the
+// latter has no modeled type, so only its message can tell it apart from
+// other validation failures.
+func isMemoryAlreadyExists(err error) bool {
+	apiErr := awsAPIError(err)
+	if apiErr == nil {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case errCodeConflict:
+		return true
+	case errCodeValidation:
+		return strings.Contains(apiErr.ErrorMessage(), "already exists")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestAWSErrorClassification(t *testing.T) {
+	tests := []struct {
+		name              string
+		err               error
+		wantNotFound      bool
+		wantConflict      bool
+		wantAlreadyExists bool
+		wantMemoryExists  bool
+		wantCategory      string
+	}{
+		{name: "nil", wantCategory: ErrCategoryResource},
+		{
+			name:         "control-plane not found",
+			err:          &types.ResourceNotFoundException{Message: aws.String("gone")},
+			wantNotFound: true, wantCategory: ErrCategoryResource,
+		},
+		{
+			name:         "data-plane not found",
+			err:          &dpTypes.ResourceNotFoundException{Message: aws.String("gone")},
+			wantNotFound: true, wantCategory: ErrCategoryResource,
+		},
+		{
+			name:         "wrapped not found",
+			err:          fmt.Errorf("GetGateway %q: %w", "gw", &types.ResourceNotFoundException{}),
+			wantNotFound: true, wantCategory: ErrCategoryResource,
+		},
+		{
+			name:         "unmodeled not found",
+			err:          &smithy.GenericAPIError{Code: "ResourceNotFoundException"},
+			wantNotFound: true, wantCategory: ErrCategoryResource,
+		},
+		{
+			name:         "conflict",
+			err:          fmt.Errorf("create: %w", &types.ConflictException{Message: aws.String("exists")}),
+			wantConflict: true, wantMemoryExists: true, wantCategory: ErrCategoryResource,
+		},
+		{
+			name:              "log group already exists",
+			err:               &cwlTypes.ResourceAlreadyExistsException{Message: aws.String("exists")},
+			wantAlreadyExists: true, wantCategory: ErrCategoryResource,
+		},
+		{
+			name:             "memory name taken",
+			err:              &types.ValidationException{Message: aws.String("Memory with name m already exists")},
+			wantMemoryExists: true, wantCategory: ErrCategoryConfiguration,
+		},
+		{
+			name:         "other validation error",
+			err:          &types.ValidationException{Message: aws.String("name must match pattern")},
+			wantCategory: ErrCategoryConfiguration,
+		},
+		{
+			name:         "access denied",
+			err:          &types.AccessDeniedException{Message: aws.String("no")},
+			wantCategory: ErrCategoryPermission,
+		},
+		{
+			name:         "unauthorized",
+			err:          &dpTypes.UnauthorizedException{Message: aws.String("no")},
+			wantCategory: ErrCategoryPermission,
+		},
+		{
+			name:         "deadline exceeded",
+			err:          fmt.Errorf("poll: %w", context.DeadlineExceeded),
+			wantCategory: ErrCategoryTimeout,
+		},
+		{
+			name:         "plain text mentioning codes",
+			err:          errors.New("ConflictException: ResourceNotFoundException already exists"),
+			wantCategory: ErrCategoryResource,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNotFound(tt.err); got != tt.wantNotFound {
+				t.Errorf("isNotFound = %v, want %v", got, tt.wantNotFound)
+			}
+			if got := isConflictError(tt.err); got != tt.wantConflict {
+				t.Errorf("isConflictError = %v, want %v", got, tt.wantConflict)
+			}
+			if got := isAlreadyExists(tt.err); got != tt.wantAlreadyExists {
+				t.Errorf("isAlreadyExists = %v, want %v", got, tt.wantAlreadyExists)
+			}
+			if got := isMemoryAlreadyExists(tt.err); got != tt.wantMemoryExists {
+				t.Errorf("isMemoryAlreadyExists = %v, want %v", got, tt.wantMemoryExists)
+			}
+			if got, _ := classifyAWSError(tt.err); got != tt.wantCategory {
+				t.Errorf("classifyAWSError category = %q, want %q", got, tt.wantCategory)
+			}
+		})
+	}
+}
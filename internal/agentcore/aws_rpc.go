@@ -0,0 +1,149 @@
+package agentcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// maxRPCResponseBytes bounds the response body read from an AWS API called
+// without an SDK client.
+const maxRPCResponseBytes = 1 << 20
+
+// rpcAPI describes an AWS API the module has no SDK client for, called
+// with SigV4-signed requests through the client's sigV4Client.
+type rpcAPI struct {
+	// endpointPrefix is the service's host prefix and signing name.
+	endpointPrefix string
+
+	// target prefixes the X-Amz-Target header of a JSON protocol API.
+	target string
+
+	// version is the Version parameter of a query protocol API.
+	version string
+}
+
+// APIs called through callJSON and callQuery.
+var (
+	eventBridgeAPI = rpcAPI{endpointPrefix: "events", target: "AWSEvents"}
+	cloudWatchAPI  = rpcAPI{endpointPrefix: "monitoring", version: "2010-08-01"}
+)
+
+// rpcURL returns the regional endpoint of api.
+func (c *realAWSClient) rpcURL(api rpcAPI) string {
+	return "https://" + api.endpointPrefix + "." + c.cfg.Region + "." +
+		partitionByID(regionPartition(c.cfg.Region)).dnsSuffix + "/"
+}
+
+// callJSON calls action of a JSON 1.1 protocol API with in as the request,
+// decoding the response into out when it is non-nil. An error response is
+// returned as a smithy.APIError, so awsErrorCode and isNotFound apply.
+func (c *realAWSClient) callJSON(ctx context.Context, api rpcAPI, action string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encode %s request: %w", action, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcURL(api), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", api.target+"."+action)
+	data, status, err := c.sendRPC(ctx, req, body, api)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices {
+		return jsonRPCError(data, status)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode %s response: %w", action, err)
+	}
+	return nil
+}
+
+// callQuery calls action of a query protocol API with params. Only the
+// error of the response is read.
+func (c *realAWSClient) callQuery(ctx context.Context, api rpcAPI, action string, params url.Values) error {
+	form := url.Values{"Action": {action}, "Version": {api.version}}
+	for k, v := range params {
+		form[k] = v
+	}
+	body := []byte(form.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcURL(api), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	data, status, err := c.sendRPC(ctx, req, body, api)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices {
+		return queryRPCError(data, status)
+	}
+	return nil
+}
+
+// sendRPC signs and sends req, returning the response body and status.
+func (c *realAWSClient) sendRPC(ctx context.Context, req *http.Request, body []byte, api rpcAPI) (
+	[]byte, int, error,
+) {
+	resp, err := c.dataPlane.do(ctx, req, body, api.endpointPrefix, c.cfg.Region)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRPCResponseBytes))
+	if err != nil {
+		return nil, 0, fmt.Errorf("read %s response: %w", api.endpointPrefix, err)
+	}
+	return data, resp.StatusCode, nil
+}
+
+// jsonRPCError decodes the error response of a JSON protocol API. The
+// __type may be qualified by the service's namespace.
+func jsonRPCError(data []byte, status int) error {
+	var body struct {
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+		MessageUpper string `json:"Message"`
+	}
+	_ = json.Unmarshal(data, &body)
+	code := body.Type[strings.LastIndex(body.Type, "#")+1:]
+	if code == "" {
+		code = http.StatusText(status)
+	}
+	msg := body.Message
+	if msg == "" {
+		msg = body.MessageUpper
+	}
+	return &smithy.GenericAPIError{Code: code, Message: msg}
+}
+
+// queryRPCError decodes the error response of a query protocol API.
+func queryRPCError(data []byte, status int) error {
+	var body struct {
+		Error struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+	}
+	_ = xml.Unmarshal(data, &body)
+	code := body.Error.Code
+	if code == "" {
+		code = http.StatusText(status)
+	}
+	return &smithy.GenericAPIError{Code: code, Message: body.Error.Message}
+}
@@ -2,7 +2,6 @@ package agentcore
 
 import (
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 
@@ -19,16 +18,19 @@ import (
 //
 // Each element is a standalone forbid block suitable for a single
 // CreatePolicy call (AWS does not accept multiple policies per statement).
+// When principalPattern is set, the blocks only apply to callers whose ID
+// matches it; otherwise they apply to every caller.
 func generateCedarStatements(
 	_ []prompt.ValidatorConfig,
 	tp *prompt.ToolPolicyPack,
 	gatewayARN string,
 	registeredTools map[string]bool,
+	principalPattern string,
 ) []string {
 	if tp == nil || len(tp.Blocklist) == 0 {
 		return nil
 	}
-	return cedarFromToolPolicy(tp, gatewayARN, registeredTools)
+	return cedarFromToolPolicy(tp, gatewayARN, registeredTools, principalPattern)
 }
 
 // cedarFromToolPolicy generates Cedar forbid blocks from the tool policy
@@ -39,34 +41,52 @@ func generateCedarStatements(
 // in the gateway's Cedar schema. Tools not registered on the gateway cannot
 // be blocked (and cannot be invoked anyway). The registeredTools set filters
 // the blocklist to only tools that exist on the gateway.
-func cedarFromToolPolicy(tp *prompt.ToolPolicyPack, gatewayARN string, registeredTools map[string]bool) []string {
+func cedarFromToolPolicy(
+	tp *prompt.ToolPolicyPack, gatewayARN string, registeredTools map[string]bool, principalPattern string,
+) []string {
 	var blocks []string
 	for _, tool := range tp.Blocklist {
 		if registeredTools != nil && !registeredTools[tool] {
-			log.Printf("agentcore: skipping blocklist entry %q — not registered on gateway (cannot be invoked)", tool)
+			logf("agentcore: skipping blocklist entry %q — not registered on gateway (cannot be invoked)", tool)
 			continue
 		}
-		blocks = append(blocks, cedarToolBlocklist(tool, gatewayARN))
+		blocks = append(blocks, cedarToolBlocklist(tool, gatewayARN, principalPattern))
 	}
 	return blocks
 }
 
 // cedarToolBlocklist generates a forbid block for a blocked tool.
-// Uses the AgentCore Cedar action format: AgentCore::Action::"ToolName___ToolName"
-// (three underscores). The resource is constrained to the specific gateway ARN
-// as required by AWS.
-func cedarToolBlocklist(toolName, gatewayARN string) string {
-	escapedName := escapeCedarString(toolName)
+// Uses the AgentCore Cedar action format:
+// AgentCore::Action::"TargetName___ToolName" (three underscores), where the
+// target is named by gatewayTargetName. The resource is constrained to the
+// specific gateway ARN as required by AWS. A principalPattern limits the
+// block to IAM callers whose ID matches it.
+func cedarToolBlocklist(toolName, gatewayARN, principalPattern string) string {
+	escapedTarget, escapedName := escapeCedarString(gatewayTargetName(toolName)), escapeCedarString(toolName)
 	resourceClause := "resource"
 	if gatewayARN != "" {
 		resourceClause = fmt.Sprintf("resource == AgentCore::Gateway::%q", gatewayARN)
 	}
+	principalClause, condition := "principal", ""
+	if principalPattern != "" {
+		principalClause = "principal is AgentCore::IamEntity"
+		condition = fmt.Sprintf(` when { principal.id like "%s" }`, escapeCedarString(principalPattern))
+	}
 	return fmt.Sprintf(
-		`forbid (principal, action == AgentCore::Action::"%s___%s", %s);`,
-		escapedName, escapedName, resourceClause,
+		`forbid (%s, action == AgentCore::Action::"%s___%s", %s)%s;`,
+		principalClause, escapedTarget, escapedName, resourceClause, condition,
 	)
 }
 
+// agentPrincipalPattern returns a Cedar like pattern matching the ARN of
+// the runtime deployed for agent. Policies are created before runtimes, so
+// the pattern stands in for the ID suffix AgentCore appends to the runtime
+// name.
+func agentPrincipalPattern(cfg *Config, agent string) string {
+	return formatARN("bedrock-agentcore", cfg.Region, extractAccountFromARN(cfg.RuntimeRoleARN),
+		"runtime/"+cfg.awsName(agent)+"-*")
+}
+
 // policyResourceNames returns a sorted list of prompt names that have
 // tool policy blocklist entries. Used by both plan and apply.
 func policyResourceNames(pack *prompt.Pack) []string {
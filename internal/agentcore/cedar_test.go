@@ -30,7 +30,7 @@ func TestCedarValidatorsProduceNothing(t *testing.T) {
 			Params: map[string]interface{}{"schema": map[string]interface{}{"type": "object"}},
 		},
 	}
-	blocks := generateCedarStatements(vals, nil, "", nil)
+	blocks := generateCedarStatements(vals, nil, "", nil, "")
 	if len(blocks) != 0 {
 		t.Errorf("validators should not produce Cedar, got:\n%s", strings.Join(blocks, "\n\n"))
 	}
@@ -42,7 +42,7 @@ func TestCedarMaxRoundsProducesNothing(t *testing.T) {
 	tp := &prompt.ToolPolicyPack{
 		MaxRounds: 5,
 	}
-	blocks := generateCedarStatements(nil, tp, "", nil)
+	blocks := generateCedarStatements(nil, tp, "", nil, "")
 	if len(blocks) != 0 {
 		t.Errorf("max_rounds should not produce Cedar, got:\n%s", strings.Join(blocks, "\n\n"))
 	}
@@ -52,7 +52,7 @@ func TestCedarMaxToolCallsPerTurnProducesNothing(t *testing.T) {
 	tp := &prompt.ToolPolicyPack{
 		MaxToolCallsPerTurn: 3,
 	}
-	blocks := generateCedarStatements(nil, tp, "", nil)
+	blocks := generateCedarStatements(nil, tp, "", nil, "")
 	if len(blocks) != 0 {
 		t.Errorf("max_tool_calls_per_turn should not produce Cedar, got:\n%s", strings.Join(blocks, "\n\n"))
 	}
@@ -64,7 +64,7 @@ func TestCedarToolBlocklist(t *testing.T) {
 	tp := &prompt.ToolPolicyPack{
 		Blocklist: []string{"dangerous_tool", "risky_tool"},
 	}
-	result := strings.Join(generateCedarStatements(nil, tp, "", nil), "\n\n")
+	result := strings.Join(generateCedarStatements(nil, tp, "", nil, ""), "\n\n")
 
 	if !strings.Contains(result, `AgentCore::Action::"dangerous_tool___dangerous_tool"`) {
 		t.Errorf("expected blocklist rule for dangerous_tool, got:\n%s", result)
@@ -83,7 +83,7 @@ func TestCedarToolBlocklistWithGatewayARN(t *testing.T) {
 	}
 	arn := "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/my-gw"
 	tools := map[string]bool{"exec": true}
-	blocks := generateCedarStatements(nil, tp, arn, tools)
+	blocks := generateCedarStatements(nil, tp, arn, tools, "")
 
 	if len(blocks) != 1 {
 		t.Fatalf("expected 1 block, got %d", len(blocks))
@@ -103,7 +103,7 @@ func TestCedarToolBlocklistFiltersUnregisteredTools(t *testing.T) {
 	arn := "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/my-gw"
 	// Only "search" is registered on the gateway.
 	tools := map[string]bool{"search": true}
-	blocks := generateCedarStatements(nil, tp, arn, tools)
+	blocks := generateCedarStatements(nil, tp, arn, tools, "")
 
 	if len(blocks) != 1 {
 		t.Fatalf("expected 1 block (only registered tool), got %d", len(blocks))
@@ -128,7 +128,7 @@ func TestCedarMixed_OnlyBlocklistProducesCedar(t *testing.T) {
 		Blocklist: []string{"exec"},
 		MaxRounds: 10,
 	}
-	result := strings.Join(generateCedarStatements(vals, tp, "", nil), "\n\n")
+	result := strings.Join(generateCedarStatements(vals, tp, "", nil, ""), "\n\n")
 
 	// Only the blocklist entry should produce Cedar.
 	if !strings.Contains(result, `AgentCore::Action::"exec___exec"`) {
@@ -145,7 +145,7 @@ func TestCedarMixed_OnlyBlocklistProducesCedar(t *testing.T) {
 }
 
 func TestCedarEmpty(t *testing.T) {
-	blocks := generateCedarStatements(nil, nil, "", nil)
+	blocks := generateCedarStatements(nil, nil, "", nil, "")
 	if len(blocks) != 0 {
 		t.Errorf("expected no blocks for no rules, got:\n%s", strings.Join(blocks, "\n\n"))
 	}
@@ -153,7 +153,7 @@ func TestCedarEmpty(t *testing.T) {
 
 func TestCedarEmptyToolPolicy(t *testing.T) {
 	tp := &prompt.ToolPolicyPack{}
-	blocks := generateCedarStatements(nil, tp, "", nil)
+	blocks := generateCedarStatements(nil, tp, "", nil, "")
 	if len(blocks) != 0 {
 		t.Errorf("expected no blocks for empty tool policy, got:\n%s", strings.Join(blocks, "\n\n"))
 	}
@@ -244,3 +244,27 @@ func TestEscapeCedarString(t *testing.T) {
 		}
 	}
 }
+
+func TestCedarToolBlocklistScopedToPrincipal(t *testing.T) {
+	tp := &prompt.ToolPolicyPack{Blocklist: []string{"exec"}}
+	pattern := "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/worker-*"
+	blocks := generateCedarStatements(nil, tp, "", nil, pattern)
+
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 block, got %d", len(blocks))
+	}
+	want := `forbid (principal is AgentCore::IamEntity, action == AgentCore::Action::"exec___exec", resource)` +
+		` when { principal.id like "` + pattern + `" };`
+	if blocks[0] != want {
+		t.Errorf("block =\n%s\nwant\n%s", blocks[0], want)
+	}
+}
+
+func TestAgentPrincipalPattern(t *testing.T) {
+	cfg := &Config{Region: "us-west-2", RuntimeRoleARN: "arn:aws:iam::123456789012:role/test"}
+	got := agentPrincipalPattern(cfg, "worker")
+	want := "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/" + cfg.awsName("worker") + "-*"
+	if got != want {
+		t.Errorf("agentPrincipalPattern = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package agentcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// manifestActionAdopt records a resource Apply found already existing and
+// adopted instead of creating.
+const manifestActionAdopt deploy.Action = "ADOPT"
+
+// defaultManifestPrefix is the S3 key prefix change manifests are uploaded
+// under when change_manifest.s3_prefix is not set.
+const defaultManifestPrefix = "promptarena/change-manifests"
+
+// manifestKeyTimeFormat names uploaded manifests by the time Apply finished.
+const manifestKeyTimeFormat = "20060102T150405Z"
+
+// s3BucketRE matches an S3 bucket name.
+var s3BucketRE = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// ChangeManifestConfig uploads the change manifest of every Apply to S3.
+type ChangeManifestConfig struct {
+	// S3Bucket receives the manifests.
+	S3Bucket string `json:"s3_bucket"`
+
+	// S3Prefix is the key prefix manifests are written under. Defaults to
+	// defaultManifestPrefix.
+	S3Prefix string `json:"s3_prefix,omitempty"`
+}
+
+// ChangeManifest records what an Apply touched, for change-management
+// systems that need evidence of each deployment. Apply returns it in the
+// adapter state, replacing the previous Apply's manifest.
+type ChangeManifest struct {
+	PackID    string `json:"pack_id"`
+	Version   string `json:"version,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+	Region    string `json:"region"`
+
+	// Principal is the ARN of the AWS identity Apply ran as. Empty when it
+	// could not be determined.
+	Principal string `json:"principal,omitempty"`
+
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+
+	// The SHA-256 hashes of the request's pack, deploy config, and arena
+	// config, so the manifest identifies its inputs without copying them.
+	PackSHA256         string `json:"pack_sha256"`
+	DeployConfigSHA256 string `json:"deploy_config_sha256"`
+	ArenaConfigSHA256  string `json:"arena_config_sha256,omitempty"`
+
+	// Changes lists every resource in the new state, with what Apply did
+	// to it.
+	Changes []ManifestChange `json:"changes"`
+
+	// Untracked lists resources the prior state held that the pack no
+	// longer declares. Apply drops them from the state but does not delete
+	// them in AWS.
+	Untracked []ManifestChange `json:"untracked,omitempty"`
+}
+
+// ManifestChange is one resource in a ChangeManifest.
+type ManifestChange struct {
+	Type   string        `json:"type"`
+	Name   string        `json:"name"`
+	ARN    string        `json:"arn,omitempty"`
+	Action deploy.Action `json:"action"`
+	Status string        `json:"status,omitempty"`
+}
+
+// principalReporter is implemented by clients that know the AWS identity
+// they call as.
+type principalReporter interface {
+	principal() string
+}
+
+// changeManifestUploader is implemented by clients that can upload a
+// change manifest.
+type changeManifestUploader interface {
+	UploadChangeManifest(ctx context.Context, data []byte, bucket, key string) error
+}
+
+// validateChangeManifest checks the change_manifest settings.
+func validateChangeManifest(c *ChangeManifestConfig) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	if !s3BucketRE.MatchString(c.S3Bucket) {
+		errs = append(errs, fmt.Sprintf("change_manifest.s3_bucket %q must be an S3 bucket name", c.S3Bucket))
+	}
+	if strings.HasPrefix(c.S3Prefix, "/") {
+		errs = append(errs, fmt.Sprintf("change_manifest.s3_prefix %q must not start with /", c.S3Prefix))
+	}
+	return errs
+}
+
+// buildChangeManifest describes the Apply of req that produced resources.
+func buildChangeManifest(
+	req *deploy.PlanRequest, ac *applyContext, resources []ResourceState, started, finished time.Time,
+) *ChangeManifest {
+	m := &ChangeManifest{
+		PackID:             ac.pack.ID,
+		Version:            ac.pack.Version,
+		Workspace:          ac.cfg.Workspace,
+		Region:             ac.cfg.Region,
+		StartedAt:          started.UTC().Format(time.RFC3339),
+		FinishedAt:         finished.UTC().Format(time.RFC3339),
+		PackSHA256:         sha256Hex(req.PackJSON),
+		DeployConfigSHA256: sha256Hex(req.DeployConfig),
+		Changes:            make([]ManifestChange, 0, len(resources)),
+	}
+	if req.ArenaConfig != "" {
+		m.ArenaConfigSHA256 = sha256Hex(req.ArenaConfig)
+	}
+	if pr, ok := ac.client.(principalReporter); ok {
+		m.Principal = pr.principal()
+	}
+
+	seen := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		key := resourceKey(r.Type, r.Name)
+		_, existed := ac.priorMap[key]
+		seen[key] = true
+		action := manifestAction(r, existed)
+		if ac.carried[key] {
+			action = deploy.ActionNoChange
+		}
+		m.Changes = append(m.Changes, ManifestChange{
+			Type: r.Type, Name: r.Name, ARN: r.ARN,
+			Action: action, Status: r.Status,
+		})
+	}
+	for key, r := range ac.priorMap {
+		if !seen[key] {
+			m.Untracked = append(m.Untracked, ManifestChange{
+				Type: r.Type, Name: r.Name, ARN: r.ARN, Action: deploy.ActionNoChange,
+			})
+		}
+	}
+	sort.Slice(m.Untracked, func(i, j int) bool {
+		return resourceKey(m.Untracked[i].Type, m.Untracked[i].Name) <
+			resourceKey(m.Untracked[j].Type, m.Untracked[j].Name)
+	})
+	return m
+}
+
+// manifestAction returns what Apply did to r. A failed resource reports
+// the action Apply attempted.
+func manifestAction(r ResourceState, existed bool) deploy.Action {
+	switch r.Status {
+	case ResStatusUpdated:
+		return deploy.ActionUpdate
+	case ResStatusReplaced:
+		return ActionReplace
+	case ResStatusSkipped:
+		return deploy.ActionNoChange
+	}
+	switch {
+	case existed && r.Status == ResStatusFailed:
+		return deploy.ActionUpdate
+	case !r.isOwned():
+		return manifestActionAdopt
+	default:
+		return deploy.ActionCreate
+	}
+}
+
+// sha256Hex returns the hex SHA-256 hash of s.
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// manifestKey returns the S3 key a manifest is uploaded to.
+func manifestKey(c *ChangeManifestConfig, m *ChangeManifest) (string, error) {
+	finished, err := time.Parse(time.RFC3339, m.FinishedAt)
+	if err != nil {
+		return "", err
+	}
+	prefix := c.S3Prefix
+	if prefix == "" {
+		prefix = defaultManifestPrefix
+	}
+	name := m.PackID
+	if m.Workspace != "" {
+		name += "-" + m.Workspace
+	}
+	return path.Join(prefix, name, finished.Format(manifestKeyTimeFormat)+".json"), nil
+}
+
+// uploadChangeManifest uploads m to the change_manifest bucket and returns
+// its S3 URI. It is a no-op without change_manifest.
+func uploadChangeManifest(ctx context.Context, ac *applyContext, m *ChangeManifest) (string, error) {
+	c := ac.cfg.ChangeManifest
+	if c == nil {
+		return "", nil
+	}
+	uploader, ok := ac.client.(changeManifestUploader)
+	if !ok {
+		return "", fmt.Errorf("AWS client cannot upload change manifests")
+	}
+	key, err := manifestKey(c, m)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := uploader.UploadChangeManifest(ctx, data, c.S3Bucket, key); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("s3://%s/%s", c.S3Bucket, key), nil
+}
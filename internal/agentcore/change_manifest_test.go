@@ -0,0 +1,167 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// manifestClient is a simulated client that records uploaded change
+// manifests and reports a caller principal.
+type manifestClient struct {
+	*simulatedAWSClient
+	uploadErr error
+	uploads   map[string][]byte
+}
+
+func (c *manifestClient) principal() string {
+	return "arn:aws:sts::123456789012:assumed-role/deployer/ci"
+}
+
+func (c *manifestClient) UploadChangeManifest(_ context.Context, data []byte, bucket, key string) error {
+	if c.uploadErr != nil {
+		return c.uploadErr
+	}
+	c.uploads[bucket+"/"+key] = data
+	return nil
+}
+
+func manifestProvider(client *manifestClient) *Provider {
+	p := newSimulatedProvider()
+	p.awsClientFunc = func(_ context.Context, cfg *Config) (awsClient, error) {
+		client.simulatedAWSClient = newSimulatedAWSClient(cfg.Region)
+		return client, nil
+	}
+	return p
+}
+
+func TestApply_ChangeManifest(t *testing.T) {
+	client := &manifestClient{uploads: map[string][]byte{}}
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"change_manifest":{"s3_bucket":"evidence","s3_prefix":"agentcore"}}`
+	prior := mustJSON(t, &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeEvaluator, Name: "retired", ARN: "arn:eval:retired"},
+	}})
+
+	events, stateJSON, err := collectEvents(t, manifestProvider(client), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err = json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatal(err)
+	}
+
+	m := state.Manifest
+	if m == nil {
+		t.Fatal("state has no manifest")
+	}
+	if m.PackID != "mypack" || m.Region != "us-west-2" || m.Principal != client.principal() {
+		t.Errorf("manifest = %+v", m)
+	}
+	if m.PackSHA256 != sha256Hex(singleAgentPack()) || m.DeployConfigSHA256 != sha256Hex(cfg) {
+		t.Error("manifest hashes do not match the request")
+	}
+	if m.StartedAt == "" || m.FinishedAt < m.StartedAt {
+		t.Errorf("times = %s..%s", m.StartedAt, m.FinishedAt)
+	}
+	if len(m.Changes) != len(state.Resources) || m.Changes[0].Action != deploy.ActionCreate {
+		t.Errorf("changes = %+v, want one CREATE per resource", m.Changes)
+	}
+	if len(m.Untracked) != 1 || m.Untracked[0].Name != "retired" {
+		t.Errorf("untracked = %+v, want the retired evaluator", m.Untracked)
+	}
+
+	if len(client.uploads) != 1 {
+		t.Fatalf("uploads = %d, want 1", len(client.uploads))
+	}
+	for key, data := range client.uploads {
+		if !strings.HasPrefix(key, "evidence/agentcore/mypack/") || !strings.HasSuffix(key, ".json") {
+			t.Errorf("upload key = %s", key)
+		}
+		var uploaded ChangeManifest
+		if err = json.Unmarshal(data, &uploaded); err != nil || uploaded.FinishedAt != m.FinishedAt {
+			t.Errorf("uploaded manifest = %s (%v)", data, err)
+		}
+	}
+	if !hasProgress(events, "Uploaded change manifest to s3://evidence/agentcore/mypack/") {
+		t.Error("no progress event for the upload")
+	}
+}
+
+func TestApply_ChangeManifestUploadFailure(t *testing.T) {
+	client := &manifestClient{uploadErr: errors.New("AccessDenied")}
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"change_manifest":{"s3_bucket":"evidence"}}`
+
+	_, stateJSON, err := collectEvents(t, manifestProvider(client), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), "upload change manifest: AccessDenied") {
+		t.Errorf("err = %v, want the upload failure", err)
+	}
+	if !strings.Contains(stateJSON, `"manifest"`) {
+		t.Error("state was not returned with its manifest")
+	}
+}
+
+func hasProgress(events []deploy.ApplyEvent, prefix string) bool {
+	for _, ev := range events {
+		if ev.Type == "progress" && strings.HasPrefix(ev.Message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestManifestAction(t *testing.T) {
+	adopted := false
+	tests := []struct {
+		res     ResourceState
+		existed bool
+		want    deploy.Action
+	}{
+		{ResourceState{Status: ResStatusCreated}, false, deploy.ActionCreate},
+		{ResourceState{Status: ResStatusCreated, Owned: &adopted}, false, manifestActionAdopt},
+		{ResourceState{Status: ResStatusUpdated}, true, deploy.ActionUpdate},
+		{ResourceState{Status: ResStatusReplaced}, true, ActionReplace},
+		{ResourceState{Status: ResStatusSkipped}, true, deploy.ActionNoChange},
+		{ResourceState{Status: ResStatusFailed}, false, deploy.ActionCreate},
+		{ResourceState{Status: ResStatusFailed}, true, deploy.ActionUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/existed=%t", tt.res.Status, tt.existed), func(t *testing.T) {
+			if got := manifestAction(tt.res, tt.existed); got != tt.want {
+				t.Errorf("manifestAction = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestKey(t *testing.T) {
+	m := &ChangeManifest{PackID: "mypack", Workspace: "dev", FinishedAt: time.Date(2026, 10, 17, 9, 15, 41, 0,
+		time.UTC).Format(time.RFC3339)}
+	key, err := manifestKey(&ChangeManifestConfig{S3Bucket: "evidence"}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "promptarena/change-manifests/mypack-dev/20261017T091541Z.json"; key != want {
+		t.Errorf("key = %s, want %s", key, want)
+	}
+}
+
+func TestValidateChangeManifest(t *testing.T) {
+	if errs := validateChangeManifest(&ChangeManifestConfig{S3Bucket: "acme-evidence", S3Prefix: "a/b"}); len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	errs := validateChangeManifest(&ChangeManifestConfig{S3Bucket: "Bad_Bucket", S3Prefix: "/abs"})
+	if len(errs) != 2 {
+		t.Errorf("errors = %v, want bucket and prefix errors", errs)
+	}
+}
@@ -8,9 +8,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
 )
 
-// codeDeployEntryPoint is the Python entrypoint filename required by
+// codeDeployEntryPoint is the default Python entrypoint filename for
 // AgentCore CodeConfiguration.
 const codeDeployEntryPoint = "main.py"
 
@@ -41,19 +46,148 @@ if __name__ == "__main__":
     main()
 `
 
-// buildCodeDeployZIP creates an in-memory ZIP archive containing the Python
-// entrypoint, the pre-compiled Go runtime binary, and the pack JSON. The
-// binary is read from binaryPath on disk; packJSON is the raw pack content.
-func buildCodeDeployZIP(binaryPath, packJSON string) ([]byte, error) {
+// Code deploy layouts selectable with the code_layout config field.
+const (
+	// CodeLayoutPython bundles main.py, which launches the Go binary.
+	CodeLayoutPython = "python"
+	// CodeLayoutBinary makes the Go binary itself the entry point.
+	CodeLayoutBinary = "binary"
+)
+
+// codeLayoutSpec describes how a code deploy layout is packaged and launched.
+type codeLayoutSpec struct {
+	entryPoint string
+	runtime    types.AgentManagedRuntimeType
+	wrapper    bool // bundle main.py alongside the binary
+}
+
+// codeLayouts maps each code_layout value to its packaging. AgentCore
+// requires a managed runtime type on every CodeConfiguration and only offers
+// Python ones, so the binary layout names one even though no interpreter is
+// started.
+var codeLayouts = map[string]codeLayoutSpec{
+	CodeLayoutPython: {
+		entryPoint: codeDeployEntryPoint,
+		runtime:    types.AgentManagedRuntimeTypePython313,
+		wrapper:    true,
+	},
+	CodeLayoutBinary: {
+		entryPoint: codeDeployBinaryName,
+		runtime:    types.AgentManagedRuntimeTypePython313,
+	},
+}
+
+// entryPointPattern matches a file name at the root of the code package.
+var entryPointPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$`)
+
+// codeLayout returns the packaging for the configured code_layout,
+// defaulting to the Python wrapper, with code_runtime and entry_point
+// applied.
+func (c *Config) codeLayout() codeLayoutSpec {
+	spec, ok := codeLayouts[c.CodeLayout]
+	if !ok {
+		spec = codeLayouts[CodeLayoutPython]
+	}
+	if c.EntryPoint != "" {
+		spec.entryPoint = c.EntryPoint
+	}
+	if rt, ok := managedRuntimeType(c.CodeRuntime); ok {
+		spec.runtime = rt
+	}
+	return spec
+}
+
+// codeRuntimeName returns the code_runtime value for a managed runtime
+// type, e.g. "python3.13" for PYTHON_3_13.
+func codeRuntimeName(rt types.AgentManagedRuntimeType) string {
+	name := strings.Replace(strings.ToLower(string(rt)), "_", "", 1)
+	return strings.ReplaceAll(name, "_", ".")
+}
+
+// codeRuntimeNames lists the code_runtime values the AgentCore API
+// accepts.
+func codeRuntimeNames() []string {
+	values := types.AgentManagedRuntimeType("").Values()
+	names := make([]string, len(values))
+	for i, rt := range values {
+		names[i] = codeRuntimeName(rt)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// managedRuntimeType maps a code_runtime value to its managed runtime
+// type.
+func managedRuntimeType(name string) (types.AgentManagedRuntimeType, bool) {
+	for _, rt := range types.AgentManagedRuntimeType("").Values() {
+		if codeRuntimeName(rt) == name {
+			return rt, true
+		}
+	}
+	return "", false
+}
+
+// validateCodeLayout checks the code_layout field.
+func validateCodeLayout(layout string) []string {
+	if layout == "" {
+		return nil
+	}
+	if _, ok := codeLayouts[layout]; !ok {
+		return []string{fmt.Sprintf("code_layout %q must be %q or %q", layout, CodeLayoutPython, CodeLayoutBinary)}
+	}
+	return nil
+}
+
+// validateCodeRuntime checks the code_runtime field against the managed
+// runtime types AgentCore offers.
+func validateCodeRuntime(runtime string) []string {
+	if runtime == "" {
+		return nil
+	}
+	if _, ok := managedRuntimeType(runtime); !ok {
+		return []string{fmt.Sprintf("code_runtime %q must be one of %s",
+			runtime, strings.Join(codeRuntimeNames(), ", "))}
+	}
+	return nil
+}
+
+// validateEntryPoint checks the entry_point field. The Python layout
+// writes its wrapper under the entry point, so it must be a .py file; the
+// binary layout stores the runtime binary under it.
+func validateEntryPoint(entryPoint, layout string) []string {
+	switch {
+	case entryPoint == "":
+		return nil
+	case !entryPointPattern.MatchString(entryPoint):
+		return []string{fmt.Sprintf("entry_point %q must be a file name at the package root", entryPoint)}
+	case entryPoint == codeDeployPackFile:
+		return []string{fmt.Sprintf("entry_point must not be %q", codeDeployPackFile)}
+	case layout != CodeLayoutBinary && !strings.HasSuffix(entryPoint, ".py"):
+		return []string{fmt.Sprintf("entry_point %q must be a .py file with code_layout %q",
+			entryPoint, CodeLayoutPython)}
+	}
+	return nil
+}
+
+// buildCodeDeployZIP creates an in-memory ZIP archive containing the
+// pre-compiled Go runtime binary and the pack JSON, plus the Python
+// wrapper under the entry point when the layout uses it. Without the
+// wrapper the binary itself is stored under the entry point. The binary is
+// read from binaryPath on disk; packJSON is the raw pack content.
+func buildCodeDeployZIP(layout codeLayoutSpec, binaryPath, packJSON string) ([]byte, error) {
 	var buf bytes.Buffer
 	w := zip.NewWriter(&buf)
 
-	if err := addTextFile(w, codeDeployEntryPoint, mainPyContent); err != nil {
-		return nil, fmt.Errorf("add %s: %w", codeDeployEntryPoint, err)
+	binaryName := layout.entryPoint
+	if layout.wrapper {
+		binaryName = codeDeployBinaryName
+		if err := addTextFile(w, layout.entryPoint, mainPyContent); err != nil {
+			return nil, fmt.Errorf("add %s: %w", layout.entryPoint, err)
+		}
 	}
 
-	if err := addBinaryFile(w, codeDeployBinaryName, binaryPath); err != nil {
-		return nil, fmt.Errorf("add %s: %w", codeDeployBinaryName, err)
+	if err := addBinaryFile(w, binaryName, binaryPath); err != nil {
+		return nil, fmt.Errorf("add %s: %w", binaryName, err)
 	}
 
 	if err := addTextFile(w, codeDeployPackFile, packJSON); err != nil {
@@ -138,7 +272,7 @@ func codeDeployS3Bucket(accountID, region string) string {
 func uploadCodePackage(
 	ctx context.Context, client awsClient, cfg *Config, packJSON string,
 ) error {
-	zipData, err := buildCodeDeployZIP(cfg.RuntimeBinaryPath, packJSON)
+	zipData, err := buildCodeDeployZIP(cfg.codeLayout(), cfg.RuntimeBinaryPath, packJSON)
 	if err != nil {
 		return fmt.Errorf("build code deploy ZIP: %w", err)
 	}
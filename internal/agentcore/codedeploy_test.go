@@ -5,7 +5,10 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
 )
 
 func TestBuildCodeDeployZIP_ValidArchive(t *testing.T) {
@@ -19,7 +22,7 @@ func TestBuildCodeDeployZIP_ValidArchive(t *testing.T) {
 
 	packJSON := `{"id":"testpack","version":"v1.0.0"}`
 
-	zipData, err := buildCodeDeployZIP(binaryPath, packJSON)
+	zipData, err := buildCodeDeployZIP(codeLayouts[CodeLayoutPython], binaryPath, packJSON)
 	if err != nil {
 		t.Fatalf("buildCodeDeployZIP: %v", err)
 	}
@@ -58,7 +61,7 @@ func TestBuildCodeDeployZIP_ExecutablePermissions(t *testing.T) {
 		t.Fatalf("write temp binary: %v", err)
 	}
 
-	zipData, err := buildCodeDeployZIP(binaryPath, `{}`)
+	zipData, err := buildCodeDeployZIP(codeLayouts[CodeLayoutPython], binaryPath, `{}`)
 	if err != nil {
 		t.Fatalf("buildCodeDeployZIP: %v", err)
 	}
@@ -83,7 +86,7 @@ func TestBuildCodeDeployZIP_MainPyContent(t *testing.T) {
 		t.Fatalf("write temp binary: %v", err)
 	}
 
-	zipData, err := buildCodeDeployZIP(binaryPath, `{}`)
+	zipData, err := buildCodeDeployZIP(codeLayouts[CodeLayoutPython], binaryPath, `{}`)
 	if err != nil {
 		t.Fatalf("buildCodeDeployZIP: %v", err)
 	}
@@ -124,7 +127,7 @@ func TestBuildCodeDeployZIP_PackJSONContent(t *testing.T) {
 	}
 
 	packJSON := `{"id":"mypack","version":"v2.0.0","name":"My Pack"}`
-	zipData, err := buildCodeDeployZIP(binaryPath, packJSON)
+	zipData, err := buildCodeDeployZIP(codeLayouts[CodeLayoutPython], binaryPath, packJSON)
 	if err != nil {
 		t.Fatalf("buildCodeDeployZIP: %v", err)
 	}
@@ -157,12 +160,164 @@ func TestBuildCodeDeployZIP_PackJSONContent(t *testing.T) {
 }
 
 func TestBuildCodeDeployZIP_MissingBinary(t *testing.T) {
-	_, err := buildCodeDeployZIP("/nonexistent/path/to/binary", `{}`)
+	_, err := buildCodeDeployZIP(codeLayouts[CodeLayoutPython], "/nonexistent/path/to/binary", `{}`)
 	if err == nil {
 		t.Fatal("expected error for missing binary")
 	}
 }
 
+func TestBuildCodeDeployZIP_BinaryLayout(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "promptkit-runtime")
+	if err := os.WriteFile(binaryPath, []byte("binary"), 0o755); err != nil {
+		t.Fatalf("write temp binary: %v", err)
+	}
+
+	zipData, err := buildCodeDeployZIP(codeLayouts[CodeLayoutBinary], binaryPath, `{}`)
+	if err != nil {
+		t.Fatalf("buildCodeDeployZIP: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		t.Fatalf("open ZIP: %v", err)
+	}
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	if len(names) != 2 || names[0] != codeDeployBinaryName || names[1] != codeDeployPackFile {
+		t.Errorf("ZIP files = %v, want [%s %s]", names, codeDeployBinaryName, codeDeployPackFile)
+	}
+}
+
+func TestBuildRuntimeArtifact_Layouts(t *testing.T) {
+	tests := []struct {
+		name           string
+		layout         string
+		runtime        string
+		entryPoint     string
+		wantEntryPoint string
+		wantRuntime    types.AgentManagedRuntimeType
+	}{
+		{name: "default", wantEntryPoint: "main.py"},
+		{name: "python", layout: CodeLayoutPython, wantEntryPoint: "main.py"},
+		{name: "binary", layout: CodeLayoutBinary, wantEntryPoint: "promptkit-runtime"},
+		{
+			name: "overrides", layout: CodeLayoutBinary, runtime: "python3.11", entryPoint: "bootstrap",
+			wantEntryPoint: "bootstrap", wantRuntime: types.AgentManagedRuntimeTypePython311,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Region:         "us-west-2",
+				RuntimeRoleARN: "arn:aws:iam::123456789012:role/test",
+				CodeLayout:     tt.layout,
+				CodeRuntime:    tt.runtime,
+				EntryPoint:     tt.entryPoint,
+			}
+			wantRuntime := tt.wantRuntime
+			if wantRuntime == "" {
+				wantRuntime = types.AgentManagedRuntimeTypePython313
+			}
+			artifact, ok := buildRuntimeArtifact(cfg).(*types.AgentRuntimeArtifactMemberCodeConfiguration)
+			if !ok {
+				t.Fatal("expected a CodeConfiguration artifact")
+			}
+			code := artifact.Value
+			if len(code.EntryPoint) != 1 || code.EntryPoint[0] != tt.wantEntryPoint {
+				t.Errorf("EntryPoint = %v, want [%s]", code.EntryPoint, tt.wantEntryPoint)
+			}
+			if code.Runtime != wantRuntime {
+				t.Errorf("Runtime = %s, want %s", code.Runtime, wantRuntime)
+			}
+		})
+	}
+}
+
+func TestValidateCodeLayout(t *testing.T) {
+	for _, layout := range []string{"", CodeLayoutPython, CodeLayoutBinary} {
+		if errs := validateCodeLayout(layout); len(errs) != 0 {
+			t.Errorf("validateCodeLayout(%q) = %v", layout, errs)
+		}
+	}
+	if errs := validateCodeLayout("node"); len(errs) != 1 {
+		t.Errorf("validateCodeLayout(node) = %v, want one error", errs)
+	}
+}
+
+func TestValidateCodeRuntime(t *testing.T) {
+	for _, runtime := range []string{"", "python3.10", "python3.13"} {
+		if errs := validateCodeRuntime(runtime); len(errs) != 0 {
+			t.Errorf("validateCodeRuntime(%q) = %v", runtime, errs)
+		}
+	}
+	errs := validateCodeRuntime("nodejs")
+	if len(errs) != 1 || !strings.Contains(errs[0], "python3.10, python3.11, python3.12, python3.13") {
+		t.Errorf("validateCodeRuntime(nodejs) = %v, want one error listing the runtimes", errs)
+	}
+}
+
+func TestValidateEntryPoint(t *testing.T) {
+	tests := []struct {
+		entryPoint string
+		layout     string
+		wantErr    string
+	}{
+		{entryPoint: ""},
+		{entryPoint: "launch.py"},
+		{entryPoint: "bootstrap", layout: CodeLayoutBinary},
+		{entryPoint: "bootstrap", wantErr: "must be a .py file"},
+		{entryPoint: "bin/launch.py", wantErr: "file name at the package root"},
+		{entryPoint: "../launch.py", wantErr: "file name at the package root"},
+		{entryPoint: codeDeployPackFile, layout: CodeLayoutBinary, wantErr: "must not be"},
+	}
+	for _, tt := range tests {
+		errs := validateEntryPoint(tt.entryPoint, tt.layout)
+		if tt.wantErr == "" {
+			if len(errs) != 0 {
+				t.Errorf("validateEntryPoint(%q, %q) = %v", tt.entryPoint, tt.layout, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+			t.Errorf("validateEntryPoint(%q, %q) = %v, want %q", tt.entryPoint, tt.layout, errs, tt.wantErr)
+		}
+	}
+}
+
+func TestBuildCodeDeployZIP_CustomEntryPoint(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "promptkit-runtime")
+	if err := os.WriteFile(binaryPath, []byte("binary"), 0o755); err != nil {
+		t.Fatalf("write temp binary: %v", err)
+	}
+	tests := []struct {
+		layout string
+		entry  string
+		want   []string
+	}{
+		{layout: CodeLayoutPython, entry: "launch.py", want: []string{"launch.py", codeDeployBinaryName, codeDeployPackFile}},
+		{layout: CodeLayoutBinary, entry: "bootstrap", want: []string{"bootstrap", codeDeployPackFile}},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CodeLayout: tt.layout, EntryPoint: tt.entry}
+		zipData, err := buildCodeDeployZIP(cfg.codeLayout(), binaryPath, `{}`)
+		if err != nil {
+			t.Fatalf("buildCodeDeployZIP: %v", err)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+		if err != nil {
+			t.Fatalf("open ZIP: %v", err)
+		}
+		var names []string
+		for _, f := range reader.File {
+			names = append(names, f.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: ZIP files = %v, want %v", tt.layout, names, tt.want)
+		}
+	}
+}
+
 func TestCodeDeployS3Key(t *testing.T) {
 	key := codeDeployS3Key("mypack", "v1.0.0")
 	want := "promptkit/mypack/v1.0.0/deployment_package.zip"
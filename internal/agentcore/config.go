@@ -33,9 +33,145 @@ type Config struct {
 	Protocol          string               `json:"protocol,omitempty"`
 	Tags              map[string]string    `json:"tags,omitempty"`
 	DryRun            bool                 `json:"dry_run,omitempty"`
+	EnhancedDryRun    bool                 `json:"enhanced_dry_run,omitempty"`
 	Tools             *ToolsConfig         `json:"tools,omitempty"`
 	Observability     *ObservabilityConfig `json:"observability,omitempty"`
 	A2AAuth           *A2AAuthConfig       `json:"a2a_auth,omitempty"`
+	OnConflict        ConflictPolicy       `json:"on_conflict,omitempty"`
+	ConfirmReplace    bool                 `json:"confirm_replace,omitempty"`
+	IncludeAdopted    bool                 `json:"include_adopted,omitempty"`
+	RuntimeEndpoint   string               `json:"runtime_endpoint,omitempty"`
+	PollInterval      string               `json:"poll_interval,omitempty"`
+	MaxWait           string               `json:"max_wait,omitempty"`
+	CodeLayout        string               `json:"code_layout,omitempty"`
+
+	// CodeRuntime overrides the managed runtime the code package runs on,
+	// e.g. "python3.12". EntryPoint overrides the file AgentCore starts.
+	CodeRuntime string `json:"code_runtime,omitempty"`
+	EntryPoint  string `json:"entry_point,omitempty"`
+
+	// AWSProfile, AWSSharedConfigFiles, and AWSSharedCredentialsFiles
+	// select the shared config profile and files the adapter loads AWS
+	// credentials from. AWSCredentialsEnv instead names the variables
+	// holding explicit credentials.
+	AWSProfile                string             `json:"aws_profile,omitempty"`
+	AWSSharedConfigFiles      []string           `json:"aws_shared_config_files,omitempty"`
+	AWSSharedCredentialsFiles []string           `json:"aws_shared_credentials_files,omitempty"`
+	AWSCredentialsEnv         *AWSCredentialsEnv `json:"aws_credentials_env,omitempty"`
+
+	// Endpoints overrides the endpoint URL of individual AWS services,
+	// keyed by service (see endpointServices). Unset services use the
+	// endpoint the AWS SDK resolves for the region's partition.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+
+	// FIPSEndpoints and DualStackEndpoints make the AWS SDK resolve the
+	// FIPS and the dual-stack (IPv4 and IPv6) endpoint of every service
+	// without an Endpoints override.
+	FIPSEndpoints      bool `json:"fips_endpoints,omitempty"`
+	DualStackEndpoints bool `json:"dualstack_endpoints,omitempty"`
+
+	// DestroyConcurrency is how many resources of one type Destroy deletes
+	// at a time. Zero selects defaultDestroyConcurrency.
+	DestroyConcurrency int `json:"destroy_concurrency,omitempty"`
+
+	// ForceDetachEvaluators lets Destroy remove an evaluator from online
+	// eval configs outside the deployment that still reference it, instead
+	// of failing its deletion.
+	ForceDetachEvaluators bool `json:"force_detach_evaluators,omitempty"`
+
+	// Workspace separates deployments of one pack in one account, such as
+	// dev and prod. It is appended to AWS resource names and tagged.
+	Workspace string `json:"workspace,omitempty"`
+
+	// PreviousPackID is the pack ID the prior state was deployed under.
+	// Plan and Apply map the prior resources named after it to the names
+	// the current pack ID derives, keeping their AWS resources.
+	PreviousPackID string `json:"previous_pack_id,omitempty"`
+
+	// AgentsFilter deploys only the selected members of a multi-agent
+	// pack.
+	AgentsFilter *AgentsFilterConfig `json:"agents_filter,omitempty"`
+
+	// AgentCards overrides the public A2A agent card per agent name; the
+	// "default" entry applies to every agent.
+	AgentCards map[string]*AgentCardConfig `json:"agent_cards,omitempty"`
+
+	// InferenceProfiles routes the runtime and evaluators through Bedrock
+	// inference profiles instead of raw model IDs.
+	InferenceProfiles *InferenceProfilesConfig `json:"inference_profiles,omitempty"`
+
+	// Approval makes Apply wait for its plan to be approved before it
+	// changes anything in AWS.
+	Approval *ApprovalConfig `json:"approval,omitempty"`
+
+	// ChangeManifest uploads the change manifest of every Apply to S3.
+	ChangeManifest *ChangeManifestConfig `json:"change_manifest,omitempty"`
+
+	// IdentityProviders provisions AgentCore Identity OAuth2 credential
+	// providers, keyed by name, for tools that call third-party APIs.
+	IdentityProviders map[string]*IdentityProviderConfig `json:"identity_providers,omitempty"`
+
+	// Gateway configures the shared MCP tool gateway.
+	Gateway *GatewayConfig `json:"gateway,omitempty"`
+
+	// GatewayPartitioning is "per_agent" to give each member of a
+	// multi-agent pack a gateway holding only its own tools.
+	GatewayPartitioning string `json:"gateway_partitioning,omitempty"`
+
+	// Sessions controls per-session metadata kept by the runtime bridge.
+	Sessions *SessionsConfig `json:"sessions,omitempty"`
+
+	// MemoryNamespaces sets the memory namespace of each runtime, and
+	// whether members share memories or keep their own.
+	MemoryNamespaces *MemoryNamespacesConfig `json:"memory_namespaces,omitempty"`
+
+	// Logs provisions a CloudWatch log group with a retention period for
+	// each runtime.
+	Logs *LogsConfig `json:"logs,omitempty"`
+
+	// EvalAlert notifies an SNS topic, Lambda function, or HTTPS endpoint
+	// through an EventBridge rule when online evaluation results score
+	// below a threshold.
+	EvalAlert *EvalAlertConfig `json:"eval_alert,omitempty"`
+
+	// EvalDefaults sets the sampling of llm_as_judge evals that choose
+	// none, and the traffic Plan estimates judge costs from.
+	EvalDefaults *EvalDefaultsConfig `json:"eval_defaults,omitempty"`
+
+	// Lifecycle tunes runtime session and instance lifetimes and the
+	// per-instance invocation cap.
+	Lifecycle *LifecycleConfig `json:"lifecycle,omitempty"`
+
+	// Network places the runtimes in the public network or a VPC.
+	Network *NetworkConfig `json:"network,omitempty"`
+
+	// Rollout controls how the member runtimes of a multi-agent pack are
+	// updated.
+	Rollout *RolloutConfig `json:"rollout,omitempty"`
+
+	// RuntimeEnvPassthrough names deploy environment variables, or
+	// prefixes ending in "*", to copy into each runtime's environment.
+	RuntimeEnvPassthrough []string `json:"runtime_env_passthrough,omitempty"`
+
+	// RedactPatterns adds to the words that mark a runtime environment
+	// variable or config key as secret. Its values are masked in Apply and
+	// Destroy events and errors.
+	RedactPatterns []string `json:"redact_patterns,omitempty"`
+
+	// ResponseModeration has the runtime bridge apply each agent prompt's
+	// banned_words and regex validators to its responses.
+	ResponseModeration bool `json:"response_moderation,omitempty"`
+
+	// Prewarm is how many health prompts Apply sends to each runtime it
+	// created or updated, once the runtime is ready, to warm it up.
+	Prewarm int `json:"prewarm,omitempty"`
+
+	// PostDeployTests are prompts Apply sends to the entry runtime once it
+	// is deployed, with the responses they must match.
+	PostDeployTests *PostDeployTestsConfig `json:"post_deploy_tests,omitempty"`
+
+	// Phases limits Apply to some of its phases.
+	Phases *PhasesConfig `json:"phases,omitempty"`
 
 	// ToolTargets maps tool names to provider-specific target config
 	// (e.g. lambda_arn). These are merged into ArenaConfig.ToolSpecs
@@ -60,6 +196,20 @@ type Config struct {
 	// evaluator resource names to their definitions. NOT serialized.
 	EvalDefs map[string]evals.EvalDef `json:"-"`
 
+	// EvalModelIDs maps evaluator resource names to the inference profile
+	// they invoke in place of their model, populated at apply-time before
+	// the evaluator phase. NOT serialized.
+	EvalModelIDs map[string]string `json:"-"`
+
+	// RuntimeARNs maps agent names to the ARNs of their deployed runtimes,
+	// populated at apply-time before the A2A wiring phase. NOT serialized.
+	RuntimeARNs map[string]string `json:"-"`
+
+	// IdentityProviderARNs maps identity_providers keys to the ARNs of
+	// their credential providers, populated at apply-time before the tool
+	// gateway phase. NOT serialized.
+	IdentityProviderARNs map[string]string `json:"-"`
+
 	// EvalARNs maps evaluator resource names to their ARNs, populated
 	// at apply-time after the evaluator phase. NOT serialized.
 	EvalARNs map[string]string `json:"-"`
@@ -69,10 +219,20 @@ type Config struct {
 	// without creating evaluator resources. NOT serialized.
 	BuiltinEvalIDs []string `json:"-"`
 
+	// EvalSampling is the sampling each evaluator the online eval config
+	// references asked for, populated at apply-time after the evaluator
+	// phase. NOT serialized.
+	EvalSampling []evalSampling `json:"-"`
+
 	// GatewayARN is populated at apply-time after the tool gateway phase.
 	// Used by Cedar tool policies that need a specific gateway resource. NOT serialized.
 	GatewayARN string `json:"-"`
 
+	// AgentGatewayARNs maps agent names to the ARNs of their gateways when
+	// gateway_partitioning is per_agent, populated at apply-time after the
+	// tool gateway phase. NOT serialized.
+	AgentGatewayARNs map[string]string `json:"-"`
+
 	// ArenaConfig is the parsed arena configuration, populated from
 	// PlanRequest.ArenaConfig. NOT part of the deploy config JSON.
 	ArenaConfig *ArenaConfig `json:"-"`
@@ -85,6 +245,24 @@ type Config struct {
 	// determine if a runtime name matches an actual prompt (multi-agent)
 	// vs the pack ID (single-agent). NOT serialized.
 	PromptNames map[string]bool `json:"-"`
+
+	// AgentTools maps each runtime to the pack tools its prompt uses,
+	// populated at apply-time. NOT serialized.
+	AgentTools map[string][]string `json:"-"`
+
+	// RuntimeNames lists the agent runtimes the pack deploys, populated
+	// at apply-time. NOT serialized.
+	RuntimeNames []string `json:"-"`
+
+	// Variables holds the ${var.*} values the deploy config was resolved
+	// with, recorded in state so later requests resolve it the same way.
+	// NOT serialized.
+	Variables map[string]string `json:"-"`
+
+	// renamed maps the names of renamed resources to the names their AWS
+	// resources keep, populated at apply-time from prior state. NOT
+	// serialized.
+	renamed map[string]string
 }
 
 // Valid memory strategy names.
@@ -116,7 +294,7 @@ const (
 )
 
 // arnRE matches an AWS ARN prefix.
-var arnRE = regexp.MustCompile(`^arn:aws:[a-z0-9-]+:[a-z0-9-]*:\d{12}:.+$`)
+var arnRE = regexp.MustCompile(`^arn:aws(-cn|-us-gov)?:[a-z0-9-]+:[a-z0-9-]*:\d{12}:.+$`)
 
 // MemoryConfig holds memory configuration for the deployment.
 type MemoryConfig struct {
@@ -270,19 +448,21 @@ const (
 
 // ToolsConfig holds tool-related settings for the AgentCore runtime.
 type ToolsConfig struct {
-	CodeInterpreter bool `json:"code_interpreter,omitempty"`
+	CodeInterpreter bool             `json:"code_interpreter,omitempty"`
+	Audit           *ToolAuditConfig `json:"audit,omitempty"`
 }
 
 // ObservabilityConfig holds observability settings.
 type ObservabilityConfig struct {
 	CloudWatchLogGroup string `json:"cloudwatch_log_group,omitempty"`
 	TracingEnabled     bool   `json:"tracing_enabled,omitempty"`
+
+	// SavedQueries saves Logs Insights queries scoped to the deployment.
+	SavedQueries bool `json:"saved_queries,omitempty"`
 }
 
-var (
-	regionRE  = regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d+$`)
-	roleARNRE = regexp.MustCompile(`^arn:aws:iam::\d{12}:role/.+$`)
-)
+// roleARNRE matches an IAM role ARN in any partition.
+var roleARNRE = regexp.MustCompile(`^arn:aws(-cn|-us-gov)?:iam::\d{12}:role/.+$`)
 
 // parseConfig unmarshals JSON config into Config.
 func parseConfig(raw string) (*Config, error) {
@@ -293,13 +473,22 @@ func parseConfig(raw string) (*Config, error) {
 	return &cfg, nil
 }
 
+// Validate checks the config as ValidateConfig does and returns an error
+// listing every problem, or nil when the config is valid.
+func (c *Config) Validate() error {
+	if errs := c.validate(); len(errs) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // validate checks the config and returns any validation errors.
 func (c *Config) validate() []string {
 	var errs []string
 
 	if c.Region == "" {
 		errs = append(errs, "region is required")
-	} else if !regionRE.MatchString(c.Region) {
+	} else if _, ok := partitionForRegion(c.Region); !ok {
 		errs = append(errs, fmt.Sprintf("region %q does not match expected format (e.g. us-west-2)", c.Region))
 	}
 
@@ -324,6 +513,45 @@ func (c *Config) validate() []string {
 	errs = append(errs, validateA2AAuth(c.A2AAuth)...)
 	errs = append(errs, validateTags(c.Tags)...)
 	errs = append(errs, validateToolTargetNames(c.ToolTargets)...)
+	errs = append(errs, validateConflictPolicy(c.OnConflict, c.ConfirmReplace)...)
+	errs = append(errs, validateRuntimeEndpoint(c.RuntimeEndpoint)...)
+	errs = append(errs, validatePollTiming(c.PollInterval, c.MaxWait)...)
+	errs = append(errs, validateCodeLayout(c.CodeLayout)...)
+	errs = append(errs, validateCodeRuntime(c.CodeRuntime)...)
+	errs = append(errs, validateEntryPoint(c.EntryPoint, c.CodeLayout)...)
+	errs = append(errs, validateWorkspace(c.Workspace)...)
+	errs = append(errs, validateAgentCards(c.AgentCards)...)
+	errs = append(errs, validateAgentsFilter(c.AgentsFilter)...)
+	errs = append(errs, validateInferenceProfiles(c.InferenceProfiles)...)
+	errs = append(errs, validateApproval(c.Approval)...)
+	errs = append(errs, validateGateway(c.Gateway)...)
+	errs = append(errs, validateGatewayPartitioning(c.GatewayPartitioning)...)
+	errs = append(errs, validateSessions(c.Sessions, c.HasMemory())...)
+	errs = append(errs, validateMemoryNamespaces(c.MemoryNamespaces, c.HasMemory())...)
+	errs = append(errs, validateLogs(c.Logs)...)
+	errs = append(errs, validateEvalAlert(c.EvalAlert, c.Region)...)
+	errs = append(errs, validateEvalDefaults(c.EvalDefaults)...)
+	errs = append(errs, validateLifecycle(c.Lifecycle)...)
+	errs = append(errs, validateNetwork(c.Network)...)
+	errs = append(errs, validateRollout(c.Rollout)...)
+	errs = append(errs, validateEnvPassthrough(c.RuntimeEnvPassthrough)...)
+	errs = append(errs, validateRedactPatterns(c.RedactPatterns)...)
+	errs = append(errs, validateDestroyConcurrency(c.DestroyConcurrency)...)
+	errs = append(errs, validateAWSCredentials(c)...)
+	errs = append(errs, validateEndpoints(c.Endpoints)...)
+	errs = append(errs, validateFIPSEndpoints(c.FIPSEndpoints, c.Region)...)
+	errs = append(errs, validateRegionPartition(c.Region, map[string]string{
+		"runtime_role_arn":                c.RuntimeRoleARN,
+		"memory_store.encryption_key_arn": c.Memory.EncryptionKeyARN,
+	})...)
+	errs = append(errs, validateChangeManifest(c.ChangeManifest)...)
+	errs = append(errs, validateIdentityProviders(c.IdentityProviders)...)
+	errs = append(errs, validatePrewarm(c.Prewarm)...)
+	errs = append(errs, validatePostDeployTests(c.PostDeployTests, c.RuntimeEndpoint)...)
+	errs = append(errs, validatePhases(c.Phases)...)
+	if c.Tools != nil {
+		errs = append(errs, validateToolAudit(c.Tools.Audit, c.HasMemory())...)
+	}
 
 	return errs
 }
@@ -1,6 +1,7 @@
 package agentcore
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -127,6 +128,27 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Region:            "us-west-2",
+		RuntimeRoleARN:    "arn:aws:iam::123456789012:role/test",
+		RuntimeBinaryPath: "/path/to/binary",
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	err := (&Config{Region: "us-west-2"}).Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want error")
+	}
+	for _, want := range []string{"runtime_role_arn is required", "runtime_binary_path is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
 func TestValidateA2AAuth(t *testing.T) {
 	base := Config{
 		Region:            "us-west-2",
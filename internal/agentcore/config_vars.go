@@ -0,0 +1,152 @@
+package agentcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EnvConfigVarPrefix prefixes the environment variables that set deploy
+// config variables: PROMPTARENA_VAR_region sets ${var.region}. They take
+// precedence over the arena config's variables.
+const EnvConfigVarPrefix = "PROMPTARENA_VAR_"
+
+// configVarNamePattern matches the NAME of a ${var.NAME} placeholder.
+const configVarNamePattern = `[A-Za-z_][A-Za-z0-9_]*`
+
+// configVarRE matches a ${var.NAME} placeholder in a deploy config string.
+var configVarRE = regexp.MustCompile(`\$\{var\.(` + configVarNamePattern + `)\}`)
+
+// configVarMarker is present in any deploy config with a placeholder.
+const configVarMarker = "${var."
+
+// configVarLooseRE matches anything written as a placeholder, well formed
+// or not.
+var configVarLooseRE = regexp.MustCompile(`\$\{var\.[^}]*\}?`)
+
+// interpolateConfigVars replaces the ${var.NAME} placeholders in the string
+// values of the deploy config raw with the environment's
+// PROMPTARENA_VAR_NAME or, failing that, vars[NAME]. It returns the
+// resolved config and the values it used. Placeholders naming an undefined
+// variable are reported together in one error.
+func interpolateConfigVars(raw string, vars map[string]string) (string, map[string]string, error) {
+	resolved, used, missing, err := resolveConfigVars(raw, vars)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("undefined deploy config variables: %s (set them in the arena config variables "+
+			"or as %s<name> environment variables)", strings.Join(missing, ", "), EnvConfigVarPrefix)
+	}
+	return resolved, used, nil
+}
+
+// resolveConfigVars is interpolateConfigVars without the error for
+// undefined variables: their placeholders are left in place and their
+// names returned, sorted. Malformed placeholders are an error.
+func resolveConfigVars(raw string, vars map[string]string) (string, map[string]string, []string, error) {
+	if !strings.Contains(raw, configVarMarker) {
+		return raw, nil, nil, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return "", nil, nil, fmt.Errorf("invalid config JSON: %w", err)
+	}
+
+	r := configVarResolver{
+		vars: vars, used: make(map[string]string), missing: make(map[string]bool), malformed: make(map[string]bool),
+	}
+	doc = r.walk(doc)
+	if len(r.malformed) > 0 {
+		return "", nil, nil, fmt.Errorf("malformed deploy config variable placeholders: %s (write them as "+
+			"${var.NAME}, where NAME matches %s)", strings.Join(sortedKeys(r.malformed), ", "), configVarNamePattern)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return "", nil, nil, fmt.Errorf("encode config: %w", err)
+	}
+	return buf.String(), r.used, sortedKeys(r.missing), nil
+}
+
+// configVarResolver substitutes placeholders through a decoded config.
+type configVarResolver struct {
+	vars      map[string]string
+	used      map[string]string
+	missing   map[string]bool
+	malformed map[string]bool
+}
+
+// walk returns v with the placeholders of its string values substituted.
+// Object keys are left as they are.
+func (r *configVarResolver) walk(v any) any {
+	switch t := v.(type) {
+	case string:
+		for _, m := range configVarLooseRE.FindAllString(t, -1) {
+			if configVarRE.FindString(m) != m {
+				r.malformed[strconv.Quote(m)] = true
+			}
+		}
+		return configVarRE.ReplaceAllStringFunc(t, r.resolve)
+	case map[string]any:
+		for k, elem := range t {
+			t[k] = r.walk(elem)
+		}
+	case []any:
+		for i, elem := range t {
+			t[i] = r.walk(elem)
+		}
+	}
+	return v
+}
+
+// resolve returns the value of the placeholder match, recording it.
+func (r *configVarResolver) resolve(match string) string {
+	name := configVarRE.FindStringSubmatch(match)[1]
+	value, ok := os.LookupEnv(EnvConfigVarPrefix + name)
+	if !ok {
+		value, ok = r.vars[name]
+	}
+	if !ok {
+		r.missing[name] = true
+		return match
+	}
+	r.used[name] = value
+	return value
+}
+
+// arenaConfigVars returns the variables of the arena config raw, or nil
+// when it has none or does not parse. A malformed arena config is reported
+// by parseArenaConfig.
+func arenaConfigVars(raw string) map[string]string {
+	if raw == "" {
+		return nil
+	}
+	var arena ArenaConfig
+	if err := json.Unmarshal([]byte(raw), &arena); err != nil {
+		return nil
+	}
+	return arena.Variables
+}
+
+// graphConfigVars returns the variables of the graph request's arena
+// config, falling back to those recorded in its prior state when it has
+// no arena config.
+func graphConfigVars(req *GraphRequest) map[string]string {
+	if req.ArenaConfig != "" {
+		return arenaConfigVars(req.ArenaConfig)
+	}
+	state, err := parseAdapterState(req.PriorState)
+	if err != nil {
+		return nil
+	}
+	return state.Variables
+}
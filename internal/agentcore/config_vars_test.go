@@ -0,0 +1,135 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// templatedDeployConfig is a deploy config whose region and role account
+// come from variables.
+const templatedDeployConfig = `{"region":"${var.region}",` +
+	`"runtime_role_arn":"arn:aws:iam::${var.account}:role/test","runtime_timeout":300}`
+
+func TestInterpolateConfigVars(t *testing.T) {
+	t.Setenv(EnvConfigVarPrefix+"account", "210987654321")
+	got, used, err := interpolateConfigVars(templatedDeployConfig,
+		map[string]string{"region": "eu-west-1", "account": "123456789012", "unused": "x"})
+	if err != nil {
+		t.Fatalf("interpolateConfigVars: %v", err)
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(got), &cfg); err != nil {
+		t.Fatalf("resolved config is not JSON: %v", err)
+	}
+	if cfg["region"] != "eu-west-1" {
+		t.Errorf("region = %v, want eu-west-1", cfg["region"])
+	}
+	if cfg["runtime_role_arn"] != "arn:aws:iam::210987654321:role/test" {
+		t.Errorf("runtime_role_arn = %v, want the environment's account", cfg["runtime_role_arn"])
+	}
+	if !strings.Contains(got, `"runtime_timeout":300`) {
+		t.Errorf("numbers not preserved: %s", got)
+	}
+	if len(used) != 2 || used["account"] != "210987654321" {
+		t.Errorf("used = %v, want region and the environment's account", used)
+	}
+}
+
+func TestInterpolateConfigVars_NoPlaceholders(t *testing.T) {
+	raw := `{"region":"us-west-2"}`
+	got, used, err := interpolateConfigVars(raw, nil)
+	if err != nil || got != raw || used != nil {
+		t.Errorf("interpolateConfigVars = %q, %v, %v; want the config unchanged", got, used, err)
+	}
+}
+
+func TestInterpolateConfigVars_Missing(t *testing.T) {
+	_, _, err := interpolateConfigVars(templatedDeployConfig, nil)
+	if err == nil || !strings.Contains(err.Error(), "account, region") {
+		t.Fatalf("error = %v, want both missing variables listed", err)
+	}
+}
+
+func TestPlan_MissingConfigVariable(t *testing.T) {
+	_, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: singleAgentPackJSON(), DeployConfig: templatedDeployConfig,
+		ArenaConfig: `{"variables":{"region":"us-west-2"}}`,
+	})
+	if err == nil || !strings.Contains(err.Error(), "undefined deploy config variables: account") {
+		t.Errorf("Plan error = %v, want the missing account variable", err)
+	}
+}
+
+func TestApply_RecordsConfigVariables(t *testing.T) {
+	p := newSimulatedProvider()
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"region":"${var.region}"}`
+	cfg = strings.Replace(cfg, `"region":"us-west-2",`, "", 1)
+	_, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: cfg,
+		ArenaConfig: `{"tool_specs":{},"variables":{"region":"eu-central-1"}}`,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	if state.Variables["region"] != "eu-central-1" {
+		t.Fatalf("state variables = %v, want region", state.Variables)
+	}
+
+	resp, err := p.Status(context.Background(), &deploy.StatusRequest{DeployConfig: cfg, PriorState: stateJSON})
+	if err != nil {
+		t.Fatalf("Status with the state's variables: %v", err)
+	}
+	if resp.Status == "" {
+		t.Error("Status returned no status")
+	}
+}
+
+func TestInterpolateConfigVars_Malformed(t *testing.T) {
+	_, _, err := interpolateConfigVars(`{"region":"${var.}","runtime_role_arn":"${var.bad-name}"}`,
+		map[string]string{"region": "us-west-2"})
+	if err == nil || !strings.Contains(err.Error(), `"${var.bad-name}", "${var.}"`) {
+		t.Errorf("error = %v, want both malformed placeholders listed", err)
+	}
+}
+
+func TestValidateConfig_UnresolvedVariables(t *testing.T) {
+	clearRegionEnv(t)
+	t.Setenv(EnvConfigVarPrefix+"account", "210987654321")
+	templated := fmt.Sprintf(`{"region":"${var.region}","runtime_role_arn":"arn:aws:iam::${var.account}:role/test",`+
+		`"runtime_binary_path":%q}`, testBinaryPath(t))
+	for _, tt := range []struct {
+		name      string
+		config    string
+		wantValid bool
+	}{
+		{"templated", templated, true},
+		{"other field invalid", `{"region":"${var.region}","runtime_role_arn":"not-an-arn"}`, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newSimulatedProvider().ValidateConfig(context.Background(),
+				&deploy.ValidateRequest{Config: tt.config})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v: %v", resp.Valid, tt.wantValid, resp.Errors)
+			}
+			all := strings.Join(resp.Errors, "\n")
+			if !strings.Contains(all, "warning: deploy config variables region are not set") {
+				t.Errorf("errors = %v, want a warning naming region", resp.Errors)
+			}
+			if strings.Contains(all, configVarMarker) {
+				t.Errorf("errors = %v, want none about placeholder values", resp.Errors)
+			}
+		})
+	}
+}
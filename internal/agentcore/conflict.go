@@ -0,0 +1,275 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+)
+
+// On-conflict policy values for resources that already exist in AWS.
+const (
+	ConflictAdopt   = "adopt"
+	ConflictFail    = "fail"
+	ConflictReplace = "replace"
+)
+
+// conflictDefaultKey is the on_conflict object key that applies to every
+// resource type without its own entry.
+const conflictDefaultKey = "default"
+
+// validConflictPolicies lists accepted on_conflict values.
+var validConflictPolicies = map[string]bool{
+	ConflictAdopt:   true,
+	ConflictFail:    true,
+	ConflictReplace: true,
+}
+
+// conflictResourceTypes lists the resource types whose create calls can hit
+// an existing resource. Gateway targets and Cedar policies inherit the
+// decision made for their parent gateway and policy engine.
+var conflictResourceTypes = map[string]bool{
+	ResTypeMemory:           true,
+	ResTypeAgentRuntime:     true,
+	ResTypeToolGateway:      true,
+	ResTypeEvaluator:        true,
+	ResTypeOnlineEvalConfig: true,
+	ResTypeCedarPolicy:      true,
+	ResTypeInferenceProfile: true,
+	ResTypeIdentityProvider: true,
+}
+
+// Adoption lookups are retried this many times, the wait doubling from
+// adoptLookupBackoff, while the existing resource is not yet visible.
+const (
+	adoptLookupAttempts = 5
+	adoptLookupBackoff  = time.Second
+)
+
+// errNotListed is wrapped by the find*ByName lookups when no resource of
+// the name is listed.
+var errNotListed = errors.New("not found")
+
+// untaggedResourceTypes are resource types AgentCore cannot tag, so
+// ownership cannot be verified before adopting them.
+var untaggedResourceTypes = map[string]bool{
+	ResTypeCedarPolicy: true,
+}
+
+// ConflictPolicy maps resource types to on_conflict values. In JSON it is
+// either a single string applied to every type or an object keyed by
+// resource type, with an optional "default" entry.
+type ConflictPolicy map[string]string
+
+// UnmarshalJSON accepts both the string and the object form.
+func (p *ConflictPolicy) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*p = ConflictPolicy{conflictDefaultKey: s}
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("on_conflict must be a string or an object of strings: %w", err)
+	}
+	*p = m
+	return nil
+}
+
+// policyFor returns the policy for a resource type. Resource types without
+// an entry use the "default" entry, and adopt when there is none.
+func (p ConflictPolicy) policyFor(resType string) string {
+	if v, ok := p[resType]; ok {
+		return v
+	}
+	if v, ok := p[conflictDefaultKey]; ok {
+		return v
+	}
+	return ConflictAdopt
+}
+
+// usesReplace reports whether any resource type is set to replace.
+func (p ConflictPolicy) usesReplace() bool {
+	for _, v := range p {
+		if v == ConflictReplace {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConflictPolicy checks on_conflict keys and values. Replace deletes
+// resources the adapter did not create, so it also requires confirm_replace.
+func validateConflictPolicy(p ConflictPolicy, confirmReplace bool) []string {
+	var errs []string
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if k != conflictDefaultKey && !conflictResourceTypes[k] {
+			errs = append(errs, fmt.Sprintf("on_conflict key %q is not a resource type that can conflict", k))
+		}
+		if !validConflictPolicies[p[k]] {
+			errs = append(errs, fmt.Sprintf("on_conflict.%s %q must be %q, %q, or %q",
+				k, p[k], ConflictAdopt, ConflictFail, ConflictReplace))
+		}
+	}
+	if p.usesReplace() && !confirmReplace {
+		errs = append(errs,
+			"on_conflict \"replace\" deletes existing resources; set confirm_replace to true to allow it")
+	}
+	return errs
+}
+
+// conflictAction is the outcome of applying the on_conflict policy.
+type conflictAction int
+
+const (
+	// conflictActionAdopt means the existing resource should be used as-is.
+	conflictActionAdopt conflictAction = iota
+	// conflictActionRecreate means the existing resource was deleted and
+	// the caller should retry the create call.
+	conflictActionRecreate
+)
+
+// resolveConflict applies the on_conflict policy to an existing resource.
+// Adopting first verifies that the resource carries this pack's
+// promptpack:pack-id tag; replacing deletes it so the caller can recreate it.
+func (c *realAWSClient) resolveConflict(ctx context.Context, res ResourceState) (conflictAction, error) {
+	resType, name := res.Type, res.Name
+	switch c.cfg.OnConflict.policyFor(resType) {
+	case ConflictFail:
+		return 0, fmt.Errorf("%s %q already exists and on_conflict is %q", resType, name, ConflictFail)
+	case ConflictReplace:
+		logf("agentcore: %s %q already exists, replacing", resType, name)
+		if err := c.DeleteResource(ctx, res); err != nil {
+			return 0, fmt.Errorf("replace %s %q: %w", resType, name, err)
+		}
+		return conflictActionRecreate, nil
+	default:
+		tags, err := c.verifyOwnership(ctx, resType, name, res.ARN)
+		if err != nil {
+			return 0, err
+		}
+		logf("agentcore: %s %q already exists, adopting", resType, name)
+		if err = c.reconcileAdoptedTags(ctx, res, tags); err != nil {
+			return 0, err
+		}
+		c.recordAdopted(res.ARN)
+		return conflictActionAdopt, nil
+	}
+}
+
+// verifyOwnership checks that an existing resource was created for the pack
+// and workspace being deployed, so adoption never captures another team's
+// resource or another workspace's. It returns the resource's tags.
+func (c *realAWSClient) verifyOwnership(ctx context.Context, resType, name, arn string) (map[string]string, error) {
+	if untaggedResourceTypes[resType] {
+		logf("agentcore: %s %q cannot be tagged; adopting without ownership check", resType, name)
+		return nil, nil
+	}
+	tags, err := c.resourceTags(ctx, resType, arn)
+	if err != nil {
+		return nil, fmt.Errorf("verify ownership of %s %q: %w", resType, name, err)
+	}
+	return tags, checkOwnershipTags(resType, name, tags, c.cfg)
+}
+
+// checkOwnershipTags checks that the tags of an existing resource name the
+// pack and workspace cfg deploys.
+func checkOwnershipTags(resType, name string, tags map[string]string, cfg *Config) error {
+	want := cfg.ResourceTags[TagKeyPackID]
+	if got := tags[TagKeyPackID]; got != want && !cfg.renamedFromPreviousPack(name, got) {
+		return fmt.Errorf(
+			"%s %q already exists but is tagged %s=%q, not %q; refusing to adopt it "+
+				"(remove it, rename the pack, or set on_conflict to %q with confirm_replace)",
+			resType, name, TagKeyPackID, got, want, ConflictReplace)
+	}
+	if got := tags[TagKeyWorkspace]; got != cfg.Workspace {
+		return fmt.Errorf(
+			"%s %q already exists but is tagged %s=%q, not %q; refusing to adopt another workspace's resource",
+			resType, name, TagKeyWorkspace, got, cfg.Workspace)
+	}
+	return nil
+}
+
+// resourceTags returns the tags on an existing resource. Inference
+// profiles are Bedrock resources, tagged through the Bedrock API.
+func (c *realAWSClient) resourceTags(ctx context.Context, resType, arn string) (map[string]string, error) {
+	if resType == ResTypeInferenceProfile {
+		return c.inferenceProfileTags(ctx, arn)
+	}
+	out, err := c.client.ListTagsForResource(ctx, &bedrockagentcorecontrol.ListTagsForResourceInput{
+		ResourceArn: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Tags, nil
+}
+
+// onCreateConflict handles a ConflictException from a create call. It looks
+// up the existing resource with find and applies the on_conflict policy.
+// When adopted it returns the existing ARN and true. When replaced it
+// re-issues create until the name is released and returns false; create
+// records its own result, so the caller inspects that as usual.
+func (c *realAWSClient) onCreateConflict(
+	ctx context.Context, resType, name string,
+	find func() (string, error), create func() error,
+) (string, bool, error) {
+	arn, err := c.findExisting(resType, name, find)
+	if err != nil {
+		return "", false, fmt.Errorf("%s %q already exists but could not be found: %w", resType, name, err)
+	}
+	action, err := c.resolveConflict(ctx, ResourceState{Type: resType, Name: name, ARN: arn})
+	if err != nil {
+		return "", false, err
+	}
+	if action == conflictActionAdopt {
+		return arn, true, nil
+	}
+	c.retryCreateAfterReplace(create)
+	return "", false, nil
+}
+
+// findExisting runs the lookup find for a resource whose create call
+// conflicted. AWS is eventually consistent: right after a ConflictException
+// the resource may not be visible to List or Get calls yet, so a not-found
+// result is retried with backoff, for up to adoptLookupAttempts lookups.
+func (c *realAWSClient) findExisting(resType, name string, find func() (string, error)) (string, error) {
+	backoff := adoptLookupBackoff
+	for attempt := 1; ; attempt++ {
+		arn, err := find()
+		if err == nil || attempt == adoptLookupAttempts || !isNotVisible(err) {
+			return arn, err
+		}
+		logf("agentcore: %s %q not visible yet, retrying lookup in %s", resType, name, backoff)
+		c.poll.sleepFor(backoff)
+		backoff *= 2
+	}
+}
+
+// isNotVisible reports whether a lookup error means the resource was not
+// found, rather than that the lookup failed.
+func isNotVisible(err error) bool {
+	return errors.Is(err, errNotListed) || isNotFound(err)
+}
+
+// retryCreateAfterReplace re-issues create while the deleted resource's name
+// is still held, for up to the configured max_wait.
+func (c *realAWSClient) retryCreateAfterReplace(create func() error) {
+	for range c.poll.maxAttempts() {
+		c.poll.sleep()
+		if err := create(); !isConflictError(err) {
+			return
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig_OnConflictString(t *testing.T) {
+	raw := `{
+		"region": "us-west-2",
+		"runtime_role_arn": "arn:aws:iam::123456789012:role/test",
+		"on_conflict": "fail"
+	}`
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, resType := range []string{ResTypeMemory, ResTypeAgentRuntime, ResTypeToolGateway} {
+		if got := cfg.OnConflict.policyFor(resType); got != ConflictFail {
+			t.Errorf("policyFor(%s) = %q, want %q", resType, got, ConflictFail)
+		}
+	}
+}
+
+func TestParseConfig_OnConflictObject(t *testing.T) {
+	raw := `{
+		"region": "us-west-2",
+		"runtime_role_arn": "arn:aws:iam::123456789012:role/test",
+		"on_conflict": {"default": "fail", "memory": "adopt", "agent_runtime": "replace"},
+		"confirm_replace": true
+	}`
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ConfirmReplace {
+		t.Error("ConfirmReplace = false, want true")
+	}
+	tests := map[string]string{
+		ResTypeMemory:       ConflictAdopt,
+		ResTypeAgentRuntime: ConflictReplace,
+		ResTypeEvaluator:    ConflictFail,
+	}
+	for resType, want := range tests {
+		if got := cfg.OnConflict.policyFor(resType); got != want {
+			t.Errorf("policyFor(%s) = %q, want %q", resType, got, want)
+		}
+	}
+}
+
+func TestParseConfig_OnConflictInvalidType(t *testing.T) {
+	raw := `{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test","on_conflict":42}`
+	if _, err := parseConfig(raw); err == nil {
+		t.Fatal("expected error for numeric on_conflict")
+	}
+}
+
+func TestConflictPolicy_DefaultsToAdopt(t *testing.T) {
+	var p ConflictPolicy
+	if got := p.policyFor(ResTypeAgentRuntime); got != ConflictAdopt {
+		t.Errorf("policyFor on nil policy = %q, want %q", got, ConflictAdopt)
+	}
+	p = ConflictPolicy{ResTypeMemory: ConflictFail}
+	if got := p.policyFor(ResTypeAgentRuntime); got != ConflictAdopt {
+		t.Errorf("policyFor without default = %q, want %q", got, ConflictAdopt)
+	}
+}
+
+func TestValidateConflictPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         ConflictPolicy
+		confirmReplace bool
+		wantErr        string
+	}{
+		{name: "nil policy", policy: nil},
+		{name: "adopt default", policy: ConflictPolicy{conflictDefaultKey: ConflictAdopt}},
+		{name: "per type", policy: ConflictPolicy{ResTypeMemory: ConflictAdopt, ResTypeEvaluator: ConflictFail}},
+		{
+			name:           "replace confirmed",
+			policy:         ConflictPolicy{ResTypeAgentRuntime: ConflictReplace},
+			confirmReplace: true,
+		},
+		{
+			name:    "replace unconfirmed",
+			policy:  ConflictPolicy{ResTypeAgentRuntime: ConflictReplace},
+			wantErr: "confirm_replace",
+		},
+		{
+			name:    "unknown value",
+			policy:  ConflictPolicy{conflictDefaultKey: "overwrite"},
+			wantErr: "must be",
+		},
+		{
+			name:    "unknown resource type",
+			policy:  ConflictPolicy{ResTypeA2AEndpoint: ConflictFail},
+			wantErr: "not a resource type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateConflictPolicy(tt.policy, tt.confirmReplace)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_OnConflictReplaceRequiresConfirm(t *testing.T) {
+	cfg := Config{
+		Region:            "us-west-2",
+		RuntimeRoleARN:    "arn:aws:iam::123456789012:role/test",
+		RuntimeBinaryPath: "/path/to/binary",
+		OnConflict:        ConflictPolicy{conflictDefaultKey: ConflictReplace},
+	}
+	if errs := cfg.validate(); len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	cfg.ConfirmReplace = true
+	if errs := cfg.validate(); len(errs) != 0 {
+		t.Errorf("expected no errors with confirm_replace, got %v", errs)
+	}
+}
+
+func TestOnCreateConflict_WaitsForDelayedVisibility(t *testing.T) {
+	c, clk, stub := newStubbedRealClient(10,
+		`{"agentRuntimes":[]}`,
+		`{"agentRuntimes":[]}`,
+		`{"agentRuntimes":[{"agentRuntimeName":"pack_rt","agentRuntimeArn":"arn:rt"}]}`,
+		`{"tags":{"promptpack:pack-id":"pack","promptpack:agent":"pack_rt"}}`)
+	c.cfg.ResourceTags = map[string]string{TagKeyPackID: "pack"}
+	ctx := context.Background()
+
+	arn, adopted, err := c.onCreateConflict(ctx, ResTypeAgentRuntime, "pack_rt",
+		func() (string, error) { return c.findRuntimeByName(ctx, "pack_rt") },
+		func() error { t.Fatal("create retried on adopt"); return nil })
+	if err != nil || !adopted || arn != "arn:rt" {
+		t.Fatalf("onCreateConflict = %q, %v, %v; want arn:rt adopted", arn, adopted, err)
+	}
+	if stub.calls != 4 {
+		t.Errorf("calls = %d, want three lookups and a tag read", stub.calls)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second}; len(clk.slept) != 2 ||
+		clk.slept[0] != want[0] || clk.slept[1] != want[1] {
+		t.Errorf("slept %v, want %v", clk.slept, want)
+	}
+}
+
+func TestOnCreateConflict_LookupRetriesAreBounded(t *testing.T) {
+	c, clk, stub := newStubbedRealClient(10, `{"agentRuntimes":[]}`)
+	ctx := context.Background()
+
+	_, _, err := c.onCreateConflict(ctx, ResTypeAgentRuntime, "pack_rt",
+		func() (string, error) { return c.findRuntimeByName(ctx, "pack_rt") },
+		func() error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "already exists but could not be found") {
+		t.Fatalf("err = %v, want the lookup failure", err)
+	}
+	if stub.calls != adoptLookupAttempts || len(clk.slept) != adoptLookupAttempts-1 {
+		t.Errorf("calls = %d, slept %v; want %d lookups", stub.calls, clk.slept, adoptLookupAttempts)
+	}
+	if last := clk.slept[len(clk.slept)-1]; last != 8*time.Second {
+		t.Errorf("last backoff = %v, want 8s", last)
+	}
+}
+
+func TestFindExisting_DoesNotRetryFailedLookups(t *testing.T) {
+	c, clk, _ := newStubbedRealClient(10)
+	calls := 0
+	_, err := c.findExisting(ResTypeEvaluator, "eval", func() (string, error) {
+		calls++
+		return "", errors.New("access denied")
+	})
+	if err == nil || calls != 1 || len(clk.slept) != 0 {
+		t.Errorf("err = %v after %d calls, slept %v; want one failed lookup", err, calls, clk.slept)
+	}
+}
+
+func TestResolveExistingMemory_DeletingIsNotRetried(t *testing.T) {
+	c, clk, stub := newStubbedRealClient(10,
+		`{"memories":[{"id":"pack_mem-AbCdEfGh","arn":"arn:mem","status":"DELETING"}]}`)
+
+	arn, adopted, err := c.resolveExistingMemory(context.Background(), "pack_mem")
+	if err != nil || adopted || arn != "" {
+		t.Fatalf("resolveExistingMemory = %q, %v, %v; want a deleting memory", arn, adopted, err)
+	}
+	if stub.calls != 1 || len(clk.slept) != 0 {
+		t.Errorf("calls = %d, slept %v; want one lookup", stub.calls, clk.slept)
+	}
+}
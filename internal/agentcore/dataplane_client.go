@@ -34,8 +34,15 @@ type realDataPlaneClient struct {
 func NewDataPlaneClient(
 	region string,
 ) (DataPlaneClient, error) {
+	return newRealDataPlaneClient(context.Background(), region)
+}
+
+// newRealDataPlaneClient loads AWS config for region and wraps the SDK client.
+func newRealDataPlaneClient(
+	ctx context.Context, region string,
+) (*realDataPlaneClient, error) {
 	cfg, err := awscfg.LoadDefaultConfig(
-		context.Background(),
+		ctx,
 		awscfg.WithRegion(region),
 	)
 	if err != nil {
@@ -65,3 +72,30 @@ func (c *realDataPlaneClient) ListEvents(
 ) (*bedrockagentcore.ListEventsOutput, error) {
 	return c.client.ListEvents(ctx, input, opts...)
 }
+
+// ListActors delegates to the underlying SDK client.
+func (c *realDataPlaneClient) ListActors(
+	ctx context.Context,
+	input *bedrockagentcore.ListActorsInput,
+	opts ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.ListActorsOutput, error) {
+	return c.client.ListActors(ctx, input, opts...)
+}
+
+// ListSessions delegates to the underlying SDK client.
+func (c *realDataPlaneClient) ListSessions(
+	ctx context.Context,
+	input *bedrockagentcore.ListSessionsInput,
+	opts ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.ListSessionsOutput, error) {
+	return c.client.ListSessions(ctx, input, opts...)
+}
+
+// DeleteEvent delegates to the underlying SDK client.
+func (c *realDataPlaneClient) DeleteEvent(
+	ctx context.Context,
+	input *bedrockagentcore.DeleteEventInput,
+	opts ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.DeleteEventOutput, error) {
+	return c.client.DeleteEvent(ctx, input, opts...)
+}
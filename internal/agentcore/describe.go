@@ -0,0 +1,76 @@
+package agentcore
+
+import "context"
+
+// configSchemaVersion is bumped whenever configSchema changes in a way that
+// callers may need to detect (new keys, changed enums, removed keys).
+const configSchemaVersion = "45"
+
+// Optional feature names reported by Describe.
+const (
+	FeatureDryRun         = "dry_run"
+	FeatureBlueGreen      = "blue_green"
+	FeatureImport         = "import"
+	FeatureLogs           = "logs"
+	FeatureStatusBatch    = "status_batch"
+	FeatureEvalResults    = "eval_results"
+	FeatureMemoryData     = "memory_data"
+	FeatureApproval       = "approval"
+	FeatureEvalTemplates  = "eval_templates"
+	FeatureEvalPreview    = "eval_preview"
+	FeatureLint           = "lint"
+	FeaturePromote        = "promote"
+	FeatureVersion        = "version"
+	FeatureGraph          = "graph"
+	FeaturePlanDestroy    = "plan_destroy"
+	FeatureEventCodes     = "event_codes"
+	FeatureEnhancedDryRun = "enhanced_dry_run"
+)
+
+// DescribeResponse is the result of the describe method. It lets PromptKit
+// feature-detect this adapter instead of assuming what it supports.
+type DescribeResponse struct {
+	Name                string          `json:"name"`
+	Version             string          `json:"version"`
+	Commit              string          `json:"commit,omitempty"`
+	BuildDate           string          `json:"build_date,omitempty"`
+	ConfigSchemaVersion string          `json:"config_schema_version"`
+	ResourceTypes       []string        `json:"resource_types"`
+	Features            map[string]bool `json:"features"`
+}
+
+// supportedResourceTypes lists every resource type the adapter can manage,
+// in deployment order.
+var supportedResourceTypes = typeNames(applyOrder)
+
+// Describe reports the adapter's resource types, optional features, config
+// schema version, and build version.
+func (p *Provider) Describe(_ context.Context) (*DescribeResponse, error) {
+	return &DescribeResponse{
+		Name:                providerName,
+		Version:             Version,
+		Commit:              Commit,
+		BuildDate:           Date,
+		ConfigSchemaVersion: configSchemaVersion,
+		ResourceTypes:       append([]string(nil), supportedResourceTypes...),
+		Features: map[string]bool{
+			FeatureDryRun:         true,
+			FeatureBlueGreen:      false,
+			FeatureImport:         false,
+			FeatureLogs:           false,
+			FeatureStatusBatch:    true,
+			FeatureEvalResults:    true,
+			FeatureMemoryData:     true,
+			FeatureApproval:       true,
+			FeatureEvalTemplates:  true,
+			FeatureEvalPreview:    true,
+			FeatureLint:           true,
+			FeaturePromote:        true,
+			FeatureVersion:        true,
+			FeatureGraph:          true,
+			FeaturePlanDestroy:    true,
+			FeatureEventCodes:     true,
+			FeatureEnhancedDryRun: true,
+		},
+	}, nil
+}
@@ -0,0 +1,123 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// Bounds for destroy_concurrency, the number of resources of one type
+// Destroy deletes at a time.
+const (
+	defaultDestroyConcurrency = 4
+	maxDestroyConcurrency     = 16
+)
+
+// destroyElapsedPrecision rounds the elapsed times in destroy events.
+const destroyElapsedPrecision = 100 * time.Millisecond
+
+// validateDestroyConcurrency checks destroy_concurrency. Zero selects the
+// default.
+func validateDestroyConcurrency(n int) []string {
+	if n < 0 || n > maxDestroyConcurrency {
+		return []string{fmt.Sprintf("destroy_concurrency %d must be between 1 and %d", n, maxDestroyConcurrency)}
+	}
+	return nil
+}
+
+// destroyConcurrency returns how many resources of one type Destroy
+// deletes at a time.
+func (c *Config) destroyConcurrency() int {
+	if c.DestroyConcurrency > 0 {
+		return c.DestroyConcurrency
+	}
+	return defaultDestroyConcurrency
+}
+
+// lockedDestroyCallback serializes calls to callback, so resources deleted
+// concurrently, and the wait progress of their deletions, can report
+// through it.
+func lockedDestroyCallback(callback deploy.DestroyCallback) deploy.DestroyCallback {
+	var mu sync.Mutex
+	return func(ev *deploy.DestroyEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return callback(ev)
+	}
+}
+
+// deleteUnits splits one type's resources into units that can be deleted
+// concurrently. Resources sharing an ARN, such as the tool_gateway entries
+// of one gateway, delete the same AWS resource and stay in one unit, in
+// state order.
+func deleteUnits(resources []ResourceState) [][]ResourceState {
+	var units [][]ResourceState
+	byARN := make(map[string]int)
+	for _, res := range resources {
+		if i, ok := byARN[res.ARN]; ok && res.ARN != "" {
+			units[i] = append(units[i], res)
+			continue
+		}
+		if res.ARN != "" {
+			byARN[res.ARN] = len(units)
+		}
+		units = append(units, []ResourceState{res})
+	}
+	return units
+}
+
+// destroyResourceGroup deletes one type's resources, up to limit units at
+// a time, emitting a progress event as each deletion starts and a
+// resource or error event with its elapsed time as it ends. callback must
+// be safe for concurrent use.
+func destroyResourceGroup(
+	ctx context.Context, destroyer resourceDestroyer,
+	resources []ResourceState, callback deploy.DestroyCallback, limit int,
+) {
+	sem := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	for _, unit := range deleteUnits(resources) {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			for _, res := range unit {
+				destroyResource(ctx, destroyer, res, callback)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// destroyResource deletes one resource and reports the outcome.
+func destroyResource(
+	ctx context.Context, destroyer resourceDestroyer, res ResourceState, callback deploy.DestroyCallback,
+) {
+	emitDestroyEvent(callback, "progress", fmt.Sprintf("Deleting %s %q", res.Type, res.Name))
+	start := time.Now()
+	err := destroyer.DeleteResource(ctx, res)
+	elapsed := time.Since(start).Round(destroyElapsedPrecision)
+	if err != nil {
+		deployErr := newDeployError("delete", res.Type, res.Name, err)
+		_ = callback(&deploy.DestroyEvent{
+			Type:    "error",
+			Message: fmt.Sprintf("%s (after %s)", deployErr.Error(), elapsed),
+			Resource: &deploy.ResourceResult{
+				Type: res.Type, Name: res.Name,
+				Action: deploy.ActionDelete, Status: ResStatusFailed,
+				Detail: deployErr.Error(),
+			},
+		})
+		return
+	}
+	_ = callback(&deploy.DestroyEvent{
+		Type:    ErrCategoryResource,
+		Message: fmt.Sprintf("Deleted %s %q in %s", res.Type, res.Name, elapsed),
+		Resource: &deploy.ResourceResult{
+			Type: res.Type, Name: res.Name,
+			Action: deploy.ActionDelete, Status: ResStatusDeleted,
+		},
+	})
+}
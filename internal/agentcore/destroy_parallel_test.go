@@ -0,0 +1,136 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// concurrentDestroyer records how many deletions run at once and the
+// order in which each type's deletions finish.
+type concurrentDestroyer struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	peak     map[string]int
+	finished []string
+	overlaps []string
+}
+
+func (d *concurrentDestroyer) DeleteResource(_ context.Context, res ResourceState) error {
+	d.mu.Lock()
+	for typ, n := range d.inFlight {
+		if typ != res.Type && n > 0 {
+			d.overlaps = append(d.overlaps, typ+"+"+res.Type)
+		}
+	}
+	d.inFlight[res.Type]++
+	d.peak[res.Type] = max(d.peak[res.Type], d.inFlight[res.Type])
+	d.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	d.mu.Lock()
+	d.inFlight[res.Type]--
+	d.finished = append(d.finished, res.Type)
+	d.mu.Unlock()
+	return nil
+}
+
+func TestDeleteUnits(t *testing.T) {
+	units := deleteUnits([]ResourceState{
+		{Type: ResTypeToolGateway, Name: "search", ARN: "arn:gw"},
+		{Type: ResTypeToolGateway, Name: "calc", ARN: "arn:gw"},
+		{Type: ResTypeToolGateway, Name: "other", ARN: "arn:gw2"},
+		{Type: ResTypeToolGateway, Name: "no-arn-1"},
+		{Type: ResTypeToolGateway, Name: "no-arn-2"},
+	})
+	var got []string
+	for _, u := range units {
+		var names []string
+		for _, r := range u {
+			names = append(names, r.Name)
+		}
+		got = append(got, strings.Join(names, "+"))
+	}
+	if want := "search+calc,other,no-arn-1,no-arn-2"; strings.Join(got, ",") != want {
+		t.Errorf("units = %v, want %s", got, want)
+	}
+}
+
+func TestDestroy_DeletesTypeConcurrently(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		wantPeak    int
+	}{
+		{0, defaultDestroyConcurrency},
+		{1, 1},
+		{2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("destroy_concurrency=%d", tt.concurrency), func(t *testing.T) {
+			d := &concurrentDestroyer{inFlight: map[string]int{}, peak: map[string]int{}}
+			p := &Provider{destroyerFunc: func(context.Context, *Config) (resourceDestroyer, error) { return d, nil }}
+
+			var resources []ResourceState
+			for i := range 6 {
+				resources = append(resources, ResourceState{
+					Type: ResTypeAgentRuntime, Name: fmt.Sprintf("rt-%d", i), ARN: fmt.Sprintf("arn:rt-%d", i),
+				})
+			}
+			resources = append(resources, ResourceState{Type: ResTypeMemory, Name: "mem", ARN: "arn:mem"})
+
+			var events []*deploy.DestroyEvent
+			err := p.Destroy(context.Background(), &deploy.DestroyRequest{
+				DeployConfig: fmt.Sprintf(`{"region":"us-west-2","destroy_concurrency":%d}`, tt.concurrency),
+				PriorState:   mustJSON(t, &AdapterState{Resources: resources}),
+			}, func(ev *deploy.DestroyEvent) error {
+				events = append(events, ev)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Destroy: %v", err)
+			}
+
+			if d.peak[ResTypeAgentRuntime] != tt.wantPeak {
+				t.Errorf("peak concurrent runtime deletes = %d, want %d", d.peak[ResTypeAgentRuntime], tt.wantPeak)
+			}
+			if len(d.overlaps) > 0 {
+				t.Errorf("types deleted concurrently: %v", d.overlaps)
+			}
+			if last := d.finished[len(d.finished)-1]; last != ResTypeMemory {
+				t.Errorf("last deleted type = %s, want memory after the runtimes", last)
+			}
+
+			deleted := 0
+			for _, ev := range events {
+				if ev.Type == ErrCategoryResource && strings.HasPrefix(ev.Message, "Deleted ") {
+					deleted++
+					if !strings.Contains(ev.Message, " in ") || !strings.HasSuffix(ev.Message, "s") {
+						t.Errorf("event %q has no elapsed time", ev.Message)
+					}
+				}
+			}
+			if deleted != len(resources) {
+				t.Errorf("deleted events = %d, want %d", deleted, len(resources))
+			}
+		})
+	}
+}
+
+func TestValidateDestroyConcurrency(t *testing.T) {
+	for _, n := range []int{0, 1, maxDestroyConcurrency} {
+		if errs := validateDestroyConcurrency(n); len(errs) != 0 {
+			t.Errorf("destroy_concurrency %d: unexpected errors %v", n, errs)
+		}
+	}
+	for _, n := range []int{-1, maxDestroyConcurrency + 1} {
+		if errs := validateDestroyConcurrency(n); len(errs) != 1 {
+			t.Errorf("destroy_concurrency %d: errors = %v, want one", n, errs)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MethodPlanDestroy is the JSON-RPC method that previews Destroy: what it
+// would delete, in which order, and what it would leave in place. It
+// extends the standard adaptersdk method set.
+const MethodPlanDestroy = "plan_destroy"
+
+// Reasons Destroy keeps a resource.
+const (
+	retainReasonAdopted  = "adopted, not created by this deployment; set include_adopted to delete it"
+	retainReasonLogGroup = "retained by logs.retain_on_destroy"
+)
+
+// DestroyPlanRequest is the params object of a plan_destroy call. It takes
+// the same state and config Destroy would.
+type DestroyPlanRequest struct {
+	PriorState   string `json:"prior_state"`
+	DeployConfig string `json:"deploy_config,omitempty"`
+}
+
+// DestroyPlanResponse is the result of a plan_destroy call.
+type DestroyPlanResponse struct {
+	Steps    []DestroyPlanStep     `json:"steps"`
+	Retained []DestroyPlanResource `json:"retained,omitempty"`
+	Deletes  int                   `json:"deletes"`
+	Summary  string                `json:"summary"`
+}
+
+// DestroyPlanStep is one step of the destroy order: the resources of one
+// type, deleted together. Step numbers match Destroy's progress events.
+type DestroyPlanStep struct {
+	Step      int                   `json:"step"`
+	Type      string                `json:"type"`
+	Resources []DestroyPlanResource `json:"resources"`
+}
+
+// DestroyPlanResource is a resource Destroy would delete or keep.
+type DestroyPlanResource struct {
+	Type   string   `json:"type"`
+	Name   string   `json:"name"`
+	ARN    string   `json:"arn,omitempty"`
+	Owned  bool     `json:"owned"`
+	Reason string   `json:"reason,omitempty"` // why a retained resource is kept
+	Impact []string `json:"impact,omitempty"` // what else the deletion takes down
+}
+
+// PlanDestroy reports what Destroy would do with the given state and
+// config: the resources it would delete, step by step in dependency order,
+// with their ownership, and the adopted and retained resources it would
+// skip. Deleting a tool gateway target deletes the whole gateway, so each
+// gateway's first target lists the other targets and the agents that lose
+// their tools. It calls no AWS APIs.
+func (p *Provider) PlanDestroy(_ context.Context, req *DestroyPlanRequest) (*DestroyPlanResponse, error) {
+	state, err := parseAdapterState(req.PriorState)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse prior state: %w", err)
+	}
+	raw := req.DeployConfig
+	if raw == "" {
+		raw = "{}"
+	}
+	cfg, err := p.loadConfig(raw, state.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
+	}
+	if err := checkStateWorkspace(state, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+
+	resources, adopted := splitAdopted(state.Resources, cfg.IncludeAdopted)
+	resources, retained := splitRetainedLogGroups(resources, cfg)
+	out := &DestroyPlanResponse{Steps: destroyPlanSteps(resources), Deletes: len(resources)}
+	for _, r := range adopted {
+		out.Retained = append(out.Retained, destroyPlanResource(r, retainReasonAdopted))
+	}
+	for _, r := range retained {
+		out.Retained = append(out.Retained, destroyPlanResource(r, retainReasonLogGroup))
+	}
+	addGatewayImpact(out.Steps, state.Resources)
+	out.Summary = destroyPlanSummary(out)
+	return out, nil
+}
+
+// destroyPlanSteps groups resources into Destroy's steps: one per type in
+// destroyOrder, numbered as Destroy numbers them, then one per type
+// outside it in the order the types first appear.
+func destroyPlanSteps(resources []ResourceState) []DestroyPlanStep {
+	byType := groupByType(resources)
+	var steps []DestroyPlanStep
+	for i, rtype := range destroyOrder {
+		if group, ok := byType[rtype]; ok {
+			steps = append(steps, destroyPlanStep(i+1, rtype, group))
+		}
+	}
+	var unordered []string
+	for _, r := range resources {
+		if !isInDestroyOrder(r.Type) && !slices.Contains(unordered, r.Type) {
+			unordered = append(unordered, r.Type)
+		}
+	}
+	for i, rtype := range unordered {
+		steps = append(steps, destroyPlanStep(len(destroyOrder)+i+1, rtype, byType[rtype]))
+	}
+	return steps
+}
+
+// destroyPlanStep builds the step deleting resources of one type.
+func destroyPlanStep(step int, rtype string, resources []ResourceState) DestroyPlanStep {
+	s := DestroyPlanStep{Step: step, Type: rtype}
+	for _, r := range resources {
+		s.Resources = append(s.Resources, destroyPlanResource(r, ""))
+	}
+	return s
+}
+
+// destroyPlanResource describes r in a destroy plan.
+func destroyPlanResource(r ResourceState, reason string) DestroyPlanResource {
+	return DestroyPlanResource{Type: r.Type, Name: r.Name, ARN: r.ARN, Owned: r.isOwned(), Reason: reason}
+}
+
+// addGatewayImpact records, on the first deleted target of each tool
+// gateway, that deleting it deletes the gateway with every target on it,
+// and which agents in state lose their tools.
+func addGatewayImpact(steps []DestroyPlanStep, all []ResourceState) {
+	gateways := gatewayARNsByAgent(all)
+	seen := make(map[string]bool)
+	for i := range steps {
+		if steps[i].Type != ResTypeToolGateway {
+			continue
+		}
+		for j := range steps[i].Resources {
+			res := &steps[i].Resources[j]
+			agent, _ := splitGatewayTarget(res.Name)
+			key := res.ARN
+			if key == "" {
+				key = agent
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			res.Impact = gatewayImpact(agent, res, all, gateways)
+		}
+	}
+}
+
+// gatewayImpact lists what deleting the gateway behind target takes down:
+// its other targets, including any this state does not record, and the
+// runtimes that call tools through it.
+func gatewayImpact(
+	agent string, target *DestroyPlanResource, all []ResourceState, gateways map[string]string,
+) []string {
+	gateway := extractResourceID(target.ARN, "gateway")
+	if gateway == "" {
+		gateway = "of this target"
+	}
+	var others, agents []string
+	for _, r := range all {
+		switch {
+		case r.Type == ResTypeToolGateway && r.Name != target.Name && sameGateway(r, target, agent):
+			others = append(others, r.Name)
+		case r.Type == ResTypeAgentRuntime && usesGateway(r.Name, agent, gateways):
+			agents = append(agents, r.Name)
+		}
+	}
+	impact := []string{fmt.Sprintf("deletes gateway %s and every target on it, including targets not in this state",
+		gateway)}
+	if len(others) > 0 {
+		impact = append(impact, "deletes targets: "+strings.Join(others, ", "))
+	}
+	if len(agents) > 0 {
+		impact = append(impact, "removes tools from agents: "+strings.Join(agents, ", "))
+	}
+	return impact
+}
+
+// sameGateway reports whether r is a target on the same gateway as target,
+// whose gateway partition is agent.
+func sameGateway(r ResourceState, target *DestroyPlanResource, agent string) bool {
+	if r.ARN != "" && target.ARN != "" {
+		return r.ARN == target.ARN
+	}
+	a, _ := splitGatewayTarget(r.Name)
+	return a == agent
+}
+
+// usesGateway reports whether the runtime named runtime calls tools
+// through the gateway of partition agent: its own gateway, or the shared
+// one when it has none.
+func usesGateway(runtime, agent string, gateways map[string]string) bool {
+	if agent != "" {
+		return runtime == agent
+	}
+	_, own := gateways[runtime]
+	return !own
+}
+
+// destroyPlanSummary describes the plan in one line.
+func destroyPlanSummary(plan *DestroyPlanResponse) string {
+	if plan.Deletes == 0 && len(plan.Retained) == 0 {
+		return "Nothing to destroy"
+	}
+	summary := fmt.Sprintf("Destroy would delete %d resources in %d steps", plan.Deletes, len(plan.Steps))
+	if len(plan.Retained) > 0 {
+		summary += fmt.Sprintf(" and keep %d", len(plan.Retained))
+	}
+	return summary
+}
@@ -0,0 +1,129 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const sharedGatewayARN = "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-shared"
+
+// destroyPlanProvider returns a provider that fails the test if it is
+// asked for a destroyer.
+func destroyPlanProvider(t *testing.T) *Provider {
+	t.Helper()
+	return &Provider{destroyerFunc: func(context.Context, *Config) (resourceDestroyer, error) {
+		t.Fatal("plan_destroy created a destroyer")
+		return nil, nil
+	}}
+}
+
+func TestPlanDestroy_StepsAndRetained(t *testing.T) {
+	notOwned := false
+	state := mustJSON(t, &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeMemory, Name: "mem", ARN: "arn:mem", Owned: &notOwned},
+		{Type: ResTypeAgentRuntime, Name: "coordinator", ARN: "arn:coordinator"},
+		{Type: ResTypeAgentRuntime, Name: "worker", ARN: "arn:worker"},
+		{Type: ResTypeLogGroup, Name: "worker", ARN: "arn:logs"},
+		{Type: ResTypeToolGateway, Name: "search_tool_gw", ARN: sharedGatewayARN},
+		{Type: ResTypeToolGateway, Name: "calc_tool_gw", ARN: sharedGatewayARN},
+		{Type: "custom_thing", Name: "x"},
+	}})
+	resp, err := destroyPlanProvider(t).PlanDestroy(context.Background(), &DestroyPlanRequest{
+		PriorState: state, DeployConfig: `{"region":"us-west-2","logs":{"retain_on_destroy":true}}`,
+	})
+	if err != nil {
+		t.Fatalf("PlanDestroy: %v", err)
+	}
+
+	var types []string
+	for i, s := range resp.Steps {
+		types = append(types, s.Type)
+		if i > 0 && s.Step <= resp.Steps[i-1].Step {
+			t.Errorf("step %d (%s) does not follow step %d", s.Step, s.Type, resp.Steps[i-1].Step)
+		}
+	}
+	if got := strings.Join(types, ","); got != "agent_runtime,tool_gateway,custom_thing" {
+		t.Errorf("step types = %s, want runtimes, then the gateway, then unordered types", got)
+	}
+	if resp.Deletes != 5 {
+		t.Errorf("deletes = %d, want 5", resp.Deletes)
+	}
+	if len(resp.Retained) != 2 || resp.Retained[0].Reason != retainReasonAdopted || resp.Retained[0].Owned ||
+		resp.Retained[1].Reason != retainReasonLogGroup {
+		t.Errorf("retained = %+v, want the adopted memory and the log group", resp.Retained)
+	}
+	if resp.Summary != "Destroy would delete 5 resources in 3 steps and keep 2" {
+		t.Errorf("summary = %q", resp.Summary)
+	}
+}
+
+func TestPlanDestroy_SharedGatewayImpact(t *testing.T) {
+	state := mustJSON(t, &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "coordinator", ARN: "arn:coordinator"},
+		{Type: ResTypeAgentRuntime, Name: "worker", ARN: "arn:worker"},
+		{Type: ResTypeToolGateway, Name: "search_tool_gw", ARN: sharedGatewayARN},
+		{Type: ResTypeToolGateway, Name: "calc_tool_gw", ARN: sharedGatewayARN},
+		{Type: ResTypeToolGateway, Name: "worker/lookup_tool_gw", ARN: "arn:aws:bedrock-agentcore:us-west-2:1:gateway/gw-w"},
+	}})
+	resp, err := destroyPlanProvider(t).PlanDestroy(context.Background(), &DestroyPlanRequest{PriorState: state})
+	if err != nil {
+		t.Fatalf("PlanDestroy: %v", err)
+	}
+	var targets []DestroyPlanResource
+	for _, s := range resp.Steps {
+		if s.Type == ResTypeToolGateway {
+			targets = s.Resources
+		}
+	}
+	if len(targets) != 3 {
+		t.Fatalf("gateway step = %+v, want 3 targets", targets)
+	}
+	shared := strings.Join(targets[0].Impact, "; ")
+	for _, want := range []string{"deletes gateway gw-shared", "deletes targets: calc_tool_gw",
+		"removes tools from agents: coordinator"} {
+		if !strings.Contains(shared, want) {
+			t.Errorf("shared gateway impact = %q, want %q", shared, want)
+		}
+	}
+	if len(targets[1].Impact) > 0 {
+		t.Errorf("second target on the shared gateway has impact %v, want none", targets[1].Impact)
+	}
+	if own := strings.Join(targets[2].Impact, "; "); !strings.Contains(own, "gateway gw-w") ||
+		!strings.Contains(own, "removes tools from agents: worker") || strings.Contains(own, "deletes targets") {
+		t.Errorf("per-agent gateway impact = %q", own)
+	}
+}
+
+func TestPlanDestroy_IncludeAdopted(t *testing.T) {
+	notOwned := false
+	state := mustJSON(t, &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeMemory, Name: "mem", ARN: "arn:mem", Owned: &notOwned},
+	}})
+	resp, err := destroyPlanProvider(t).PlanDestroy(context.Background(), &DestroyPlanRequest{
+		PriorState: state, DeployConfig: `{"include_adopted":true}`,
+	})
+	if err != nil {
+		t.Fatalf("PlanDestroy: %v", err)
+	}
+	if len(resp.Steps) != 1 || resp.Steps[0].Resources[0].Owned || len(resp.Retained) > 0 {
+		t.Errorf("plan = %+v, want the adopted memory deleted and flagged as not owned", resp)
+	}
+}
+
+func TestPlanDestroy_EmptyAndInvalid(t *testing.T) {
+	p := destroyPlanProvider(t)
+	resp, err := p.PlanDestroy(context.Background(), &DestroyPlanRequest{})
+	if err != nil || resp.Summary != "Nothing to destroy" || len(resp.Steps) > 0 {
+		t.Errorf("empty state = %+v, %v", resp, err)
+	}
+	if _, err := p.PlanDestroy(context.Background(), &DestroyPlanRequest{PriorState: "{"}); err == nil {
+		t.Error("invalid state accepted")
+	}
+	state := mustJSON(t, &AdapterState{Workspace: "prod", Resources: []ResourceState{{Type: ResTypeMemory, Name: "m"}}})
+	if _, err := p.PlanDestroy(context.Background(), &DestroyPlanRequest{
+		PriorState: state, DeployConfig: `{"workspace":"staging"}`,
+	}); err == nil {
+		t.Error("state from another workspace accepted")
+	}
+}
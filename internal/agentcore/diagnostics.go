@@ -22,13 +22,6 @@ func (w DiagnosticWarning) String() string {
 	return fmt.Sprintf("[%s] %s", w.Category, w.Message)
 }
 
-// agentcoreRegions lists AWS regions where Bedrock AgentCore is available.
-var agentcoreRegions = map[string]bool{
-	"us-east-1": true,
-	"us-west-2": true,
-	"eu-west-1": true,
-}
-
 // DiagnoseConfig checks the configuration for common misconfigurations and
 // returns warnings. Unlike validate(), these are non-fatal — they highlight
 // issues that are likely to cause deploy failures.
@@ -38,26 +31,34 @@ func DiagnoseConfig(cfg *Config) []DiagnosticWarning {
 	warnings = append(warnings, diagnoseRoleARN(cfg)...)
 	warnings = append(warnings, diagnoseA2AConfig(cfg)...)
 	warnings = append(warnings, diagnoseMemory(cfg)...)
+	warnings = append(warnings, diagnoseEnvPassthrough(cfg)...)
+	warnings = append(warnings, diagnoseAWSCredentials(cfg)...)
 	return warnings
 }
 
-// diagnoseRegion checks for unsupported or unusual regions.
+// diagnoseRegion checks for regions that do not offer Bedrock AgentCore.
+// An AgentCore control-plane endpoint override points the adapter at an
+// endpoint the region list does not know about, so it skips the check.
 func diagnoseRegion(cfg *Config) []DiagnosticWarning {
-	if cfg.Region == "" {
-		return nil // validate() will catch this
+	if cfg.Region == "" || cfg.Endpoints[EndpointBedrockAgentCoreControl] != "" {
+		return nil // validate() will catch an empty region
 	}
-	if !agentcoreRegions[cfg.Region] {
-		return []DiagnosticWarning{{
-			Category: ErrCategoryConfiguration,
-			Message: fmt.Sprintf(
-				"region %q may not support Bedrock AgentCore", cfg.Region,
-			),
-			Hint: fmt.Sprintf(
-				"supported regions: %s", joinMapKeys(agentcoreRegions),
-			),
-		}}
+	p, ok := partitionForRegion(cfg.Region)
+	if !ok || agentCoreAvailable(cfg.Region) {
+		return nil // validate() will catch a malformed region
 	}
-	return nil
+	hint := fmt.Sprintf("supported regions in partition %s: %s", p.id, joinMapKeys(p.agentCoreRegions))
+	if len(p.agentCoreRegions) == 0 {
+		hint = fmt.Sprintf("no region in partition %s is known to offer it; set endpoints.%s if yours does",
+			p.id, EndpointBedrockAgentCoreControl)
+	}
+	return []DiagnosticWarning{{
+		Category: ErrCategoryConfiguration,
+		Message: fmt.Sprintf(
+			"region %q may not support Bedrock AgentCore", cfg.Region,
+		),
+		Hint: hint,
+	}}
 }
 
 // diagnoseRoleARN checks for common IAM role ARN mistakes.
@@ -20,7 +20,7 @@ func TestDiagnoseConfig_SupportedRegion(t *testing.T) {
 
 func TestDiagnoseConfig_UnsupportedRegion(t *testing.T) {
 	cfg := &Config{
-		Region:         "ap-southeast-1",
+		Region:         "sa-east-1",
 		RuntimeRoleARN: "arn:aws:iam::123456789012:role/test",
 	}
 	warnings := DiagnoseConfig(cfg)
@@ -0,0 +1,258 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// readOnlyGuardID is the middleware ID of the enhanced dry run's guard.
+const readOnlyGuardID = "AgentCoreReadOnlyGuard"
+
+// readOnlyOperationRE matches the names of AWS operations that read and
+// never change anything.
+var readOnlyOperationRE = regexp.MustCompile(`^(Get|List|Head|Describe)[A-Z]`)
+
+// errMutationInDryRun rejects an AWS call that could change something
+// during an enhanced dry run.
+var errMutationInDryRun = errors.New("enhanced dry run allows read-only AWS calls only")
+
+// defaultResourceQuotas are AgentCore's default quotas of resources per
+// account and region, for the types the adapter creates one of per pack
+// entry. They are adjustable, so exceeding one is a warning.
+var defaultResourceQuotas = []struct {
+	resType string
+	quota   int
+}{
+	{ResTypeAgentRuntime, 1000},
+	{ResTypeMemory, 150},
+	{ResTypeEvaluator, 1000},
+}
+
+// dryRunChecker is the read-only view of an AWS client the enhanced dry
+// run checks the account with. It has no method that changes anything.
+type dryRunChecker interface {
+	// roleTrustPolicy returns the trust policy of the IAM role roleARN,
+	// wrapping errRoleNotFound when the role does not exist.
+	roleTrustPolicy(ctx context.Context, roleARN string) (string, error)
+	// countResources returns how many resources of resType the account
+	// has in the region.
+	countResources(ctx context.Context, resType string) (int, error)
+	// bucketExists reports whether the S3 bucket exists.
+	bucketExists(ctx context.Context, bucket string) (bool, error)
+}
+
+// isDryRun reports whether Apply previews the deployment instead of
+// making it. An enhanced dry run is a dry run.
+func (c *Config) isDryRun() bool {
+	return c.DryRun || c.EnhancedDryRun
+}
+
+// registerReadOnlyGuard installs a middleware that fails every AWS call
+// but reads. It is an aws.Config APIOptions entry.
+func registerReadOnlyGuard(stack *middleware.Stack) error {
+	guard := middleware.InitializeMiddlewareFunc(readOnlyGuardID, func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		if op := awsmiddleware.GetOperationName(ctx); !readOnlyOperationRE.MatchString(op) {
+			return middleware.InitializeOutput{}, middleware.Metadata{},
+				fmt.Errorf("%s %s: %w", awsmiddleware.GetServiceID(ctx), op, errMutationInDryRun)
+		}
+		return next.HandleInitialize(ctx, in)
+	})
+	return stack.Initialize.Add(guard, middleware.After)
+}
+
+// dryRunFindings collects the outcome of the enhanced dry run's checks:
+// problems that would make Apply fail, and warnings.
+type dryRunFindings struct {
+	problems []string
+	warnings []string
+}
+
+// runDryRunChecks checks the deployment against AWS with read-only calls:
+// the runtime role exists and trusts AgentCore, the code package builds
+// and its bucket exists, the models are offered in the region, and the
+// account has room under the default quotas. It reports each finding and
+// returns an error when any would make Apply fail.
+func (p *Provider) runDryRunChecks(
+	ctx context.Context, reporter *adaptersdk.ProgressReporter, pack *prompt.Pack, cfg *Config,
+	desired []deploy.ResourceChange,
+) error {
+	var f dryRunFindings
+	f.warnings = checkModelAvailability(ctx, p.modelCatalogFunc, pack, cfg)
+	if _, err := buildCodeDeployZIP(cfg.codeLayout(), cfg.RuntimeBinaryPath, cfg.PackJSON); err != nil {
+		f.problems = append(f.problems, fmt.Sprintf("code package: %v", err))
+	}
+	client, err := p.awsClientFunc(ctx, cfg)
+	if err != nil {
+		f.problems = append(f.problems, fmt.Sprintf("AWS client: %v", err))
+	} else if checker, ok := client.(dryRunChecker); ok {
+		f.checkRuntimeRole(ctx, checker, cfg)
+		f.checkCodeBucket(ctx, checker, cfg)
+		f.checkQuotas(ctx, checker, cfg.Region, desired)
+	}
+
+	for _, w := range f.warnings {
+		if err = reporter.Progress("Warning: "+w, progressNoPercent); err != nil {
+			return err
+		}
+	}
+	for _, problem := range f.problems {
+		if err = reporter.Error(errors.New(problem)); err != nil {
+			return err
+		}
+	}
+	if len(f.problems) > 0 {
+		return fmt.Errorf("agentcore: enhanced dry run found %d problems: %s",
+			len(f.problems), strings.Join(f.problems, "; "))
+	}
+	return reporter.Progress("Enhanced dry run: AWS checks passed", progressNoPercent)
+}
+
+// checkRuntimeRole checks that the runtime role exists and that AgentCore
+// may assume it.
+func (f *dryRunFindings) checkRuntimeRole(ctx context.Context, checker dryRunChecker, cfg *Config) {
+	doc, err := checker.roleTrustPolicy(ctx, cfg.RuntimeRoleARN)
+	switch {
+	case errors.Is(err, errRoleNotFound):
+		f.problems = append(f.problems, fmt.Sprintf("runtime_role_arn %s: %v", cfg.RuntimeRoleARN, err))
+		return
+	case err != nil:
+		f.warnings = append(f.warnings, fmt.Sprintf("could not read runtime role: %v", err))
+		return
+	}
+	trusted, err := trustsServicePrincipal(doc, agentCoreServicePrincipal)
+	if err != nil {
+		f.warnings = append(f.warnings, fmt.Sprintf("runtime role: %v", err))
+		return
+	}
+	if !trusted {
+		f.problems = append(f.problems, fmt.Sprintf("runtime_role_arn %s does not let %s assume it",
+			cfg.RuntimeRoleARN, agentCoreServicePrincipal))
+	}
+}
+
+// checkCodeBucket checks that the S3 bucket the code package is uploaded
+// to exists.
+func (f *dryRunFindings) checkCodeBucket(ctx context.Context, checker dryRunChecker, cfg *Config) {
+	bucket := codeDeployS3Bucket(extractAccountFromARN(cfg.RuntimeRoleARN), cfg.Region)
+	exists, err := checker.bucketExists(ctx, bucket)
+	switch {
+	case err != nil:
+		f.warnings = append(f.warnings, fmt.Sprintf("could not check code bucket %s: %v", bucket, err))
+	case !exists:
+		f.problems = append(f.problems, fmt.Sprintf("code bucket %s does not exist", bucket))
+	}
+}
+
+// checkQuotas warns when the resources the deployment creates would take
+// the account past a default quota. Resources the deployment would adopt
+// or update are counted too, so the check errs on the side of warning.
+func (f *dryRunFindings) checkQuotas(
+	ctx context.Context, checker dryRunChecker, region string, desired []deploy.ResourceChange,
+) {
+	for _, q := range defaultResourceQuotas {
+		planned := 0
+		for _, d := range desired {
+			if d.Type == q.resType {
+				planned++
+			}
+		}
+		if planned == 0 {
+			continue
+		}
+		existing, err := checker.countResources(ctx, q.resType)
+		if err != nil {
+			f.warnings = append(f.warnings, fmt.Sprintf("could not count %s resources: %v", q.resType, err))
+			continue
+		}
+		if existing+planned > q.quota {
+			f.warnings = append(f.warnings, fmt.Sprintf(
+				"%d existing and %d planned %s resources exceed the default quota of %d in %s",
+				existing, planned, q.resType, q.quota, region))
+		}
+	}
+}
+
+// roleTrustPolicy implements dryRunChecker.
+func (c *realAWSClient) roleTrustPolicy(ctx context.Context, roleARN string) (string, error) {
+	return c.iam.trustPolicy(ctx, roleARN)
+}
+
+// bucketExists implements dryRunChecker.
+func (c *realAWSClient) bucketExists(ctx context.Context, bucket string) (bool, error) {
+	_, err := c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	var notFound *s3types.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// countResources implements dryRunChecker.
+func (c *realAWSClient) countResources(ctx context.Context, resType string) (int, error) {
+	switch resType {
+	case ResTypeAgentRuntime:
+		return countPages(func(token *string) (int, *string, error) {
+			out, err := c.client.ListAgentRuntimes(ctx, &bedrockagentcorecontrol.ListAgentRuntimesInput{
+				MaxResults: aws.Int32(listPageSize), NextToken: token,
+			})
+			if err != nil {
+				return 0, nil, err
+			}
+			return len(out.AgentRuntimes), out.NextToken, nil
+		})
+	case ResTypeMemory:
+		return countPages(func(token *string) (int, *string, error) {
+			out, err := c.client.ListMemories(ctx, &bedrockagentcorecontrol.ListMemoriesInput{
+				MaxResults: aws.Int32(listPageSize), NextToken: token,
+			})
+			if err != nil {
+				return 0, nil, err
+			}
+			return len(out.Memories), out.NextToken, nil
+		})
+	case ResTypeEvaluator:
+		return countPages(func(token *string) (int, *string, error) {
+			out, err := c.client.ListEvaluators(ctx, &bedrockagentcorecontrol.ListEvaluatorsInput{
+				MaxResults: aws.Int32(listPageSize), NextToken: token,
+			})
+			if err != nil {
+				return 0, nil, err
+			}
+			return len(out.Evaluators), out.NextToken, nil
+		})
+	}
+	return 0, fmt.Errorf("cannot count %s resources", resType)
+}
+
+// countPages sums the items of every page page returns, following its
+// next tokens.
+func countPages(page func(token *string) (int, *string, error)) (int, error) {
+	total := 0
+	var token *string
+	for {
+		n, next, err := page(token)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if next == nil {
+			return total, nil
+		}
+		token = next
+	}
+}
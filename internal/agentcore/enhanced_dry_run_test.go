@@ -0,0 +1,218 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+)
+
+func enhancedDryRunConfig(t *testing.T) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"memory_store":"session","enhanced_dry_run":true}`, testBinaryPath(t))
+}
+
+// dryRunCheckClient is a simulated client whose read-only checks report
+// a missing role, an untrusted role, or a missing bucket.
+type dryRunCheckClient struct {
+	simulatedAWSClient
+	trustPolicy string
+	roleErr     error
+	noBucket    bool
+	existing    int
+}
+
+func (c *dryRunCheckClient) roleTrustPolicy(context.Context, string) (string, error) {
+	return c.trustPolicy, c.roleErr
+}
+
+func (c *dryRunCheckClient) countResources(context.Context, string) (int, error) {
+	return c.existing, nil
+}
+
+func (c *dryRunCheckClient) bucketExists(context.Context, string) (bool, error) {
+	return !c.noBucket, nil
+}
+
+func TestApply_EnhancedDryRunPasses(t *testing.T) {
+	cloud := NewSimulatedCloud()
+	events, raw, err := collectEvents(t, NewSimulatedProvider(cloud), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: enhancedDryRunConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !strings.Contains(raw, `"status":"planned"`) {
+		t.Errorf("state = %s, want planned resources", raw)
+	}
+	if last := events[len(events)-1]; last.Message != "Enhanced dry run: AWS checks passed" {
+		t.Errorf("last event = %+v", last)
+	}
+	if res := cloud.Resources(); len(res) != 0 {
+		t.Errorf("cloud = %+v, want nothing created", res)
+	}
+}
+
+func TestApply_EnhancedDryRunReportsProblems(t *testing.T) {
+	client := &dryRunCheckClient{
+		trustPolicy: `{"Statement":[{"Effect":"Allow","Principal":{"Service":"lambda.amazonaws.com"},` +
+			`"Action":"sts:AssumeRole"}]}`,
+		noBucket: true,
+		existing: 150,
+	}
+	p := newSimulatedProvider()
+	p.awsClientFunc = func(context.Context, *Config) (awsClient, error) { return client, nil }
+
+	events, raw, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: enhancedDryRunConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), "found 2 problems") {
+		t.Fatalf("Apply error = %v, want 2 problems", err)
+	}
+	if raw == "" {
+		t.Error("Apply returned no preview state")
+	}
+	var errs, warnings []string
+	for _, ev := range events {
+		switch {
+		case ev.Type == "error":
+			errs = append(errs, ev.Message)
+		case strings.HasPrefix(ev.Message, "Warning: "):
+			warnings = append(warnings, ev.Message)
+		}
+	}
+	if len(errs) != 2 || !strings.Contains(errs[0], "does not let bedrock-agentcore.amazonaws.com assume it") ||
+		!strings.Contains(errs[1], "code bucket bedrock-agentcore-code-123456789012-us-west-2 does not exist") {
+		t.Errorf("errors = %q", errs)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "exceed the default quota of 150") {
+		t.Errorf("warnings = %q, want the memory quota", warnings)
+	}
+}
+
+func TestDryRunFindings_RoleNotFound(t *testing.T) {
+	var f dryRunFindings
+	f.checkRuntimeRole(context.Background(), &dryRunCheckClient{roleErr: fmt.Errorf("x: %w", errRoleNotFound)},
+		&Config{RuntimeRoleARN: "arn:aws:iam::123456789012:role/test"})
+	if len(f.problems) != 1 || len(f.warnings) != 0 {
+		t.Errorf("findings = %+v, want a problem", f)
+	}
+
+	f = dryRunFindings{}
+	f.checkRuntimeRole(context.Background(), &dryRunCheckClient{roleErr: errors.New("AccessDenied")},
+		&Config{RuntimeRoleARN: "arn:aws:iam::123456789012:role/test"})
+	if len(f.problems) != 0 || len(f.warnings) != 1 {
+		t.Errorf("findings = %+v, want an unchecked role to warn", f)
+	}
+}
+
+func TestTrustsServicePrincipal(t *testing.T) {
+	for _, tt := range []struct {
+		name, doc string
+		want      bool
+	}{
+		{"string", `{"Statement":[{"Effect":"Allow","Principal":{"Service":"bedrock-agentcore.amazonaws.com"},` +
+			`"Action":"sts:AssumeRole"}]}`, true},
+		{"lists", `{"Statement":[{"Effect":"Allow","Principal":{"Service":["lambda.amazonaws.com",` +
+			`"bedrock-agentcore.amazonaws.com"]},"Action":["sts:TagSession","sts:AssumeRole"]}]}`, true},
+		{"deny", `{"Statement":[{"Effect":"Deny","Principal":{"Service":"bedrock-agentcore.amazonaws.com"},` +
+			`"Action":"sts:AssumeRole"}]}`, false},
+		{"other action", `{"Statement":[{"Effect":"Allow","Principal":{"Service":` +
+			`"bedrock-agentcore.amazonaws.com"},"Action":"sts:TagSession"}]}`, false},
+		{"account principal", `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::1:root"},` +
+			`"Action":"sts:AssumeRole"}]}`, false},
+	} {
+		got, err := trustsServicePrincipal(tt.doc, agentCoreServicePrincipal)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: trustsServicePrincipal = %t, %v; want %t", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestIAMRoleReader_TrustPolicy(t *testing.T) {
+	doc := `{"Statement":[{"Effect":"Allow"}]}`
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		if !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/iam/aws4_request") {
+			t.Errorf("Authorization = %q, want a us-east-1 IAM signature", r.Header.Get("Authorization"))
+		}
+		if form.Get("RoleName") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>NoSuchEntity</Code>` +
+				`<Message>not found</Message></Error></ErrorResponse>`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `<GetRoleResponse><GetRoleResult><Role><AssumeRolePolicyDocument>%s`+
+			`</AssumeRolePolicyDocument></Role></GetRoleResult></GetRoleResponse>`, url.QueryEscape(doc))
+	}))
+	defer srv.Close()
+
+	cfg := &Config{Region: "us-west-2", Endpoints: map[string]string{EndpointIAM: srv.URL}}
+	reader := newIAMRoleReader(aws.Config{Credentials: aws.CredentialsProviderFunc(
+		func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+		})}, cfg)
+
+	got, err := reader.trustPolicy(context.Background(), "arn:aws:iam::123456789012:role/service/writer")
+	if err != nil {
+		t.Fatalf("trustPolicy: %v", err)
+	}
+	if got != doc || form.Get("Action") != "GetRole" || form.Get("RoleName") != "writer" {
+		t.Errorf("trustPolicy = %q with form %v", got, form)
+	}
+	if _, err = reader.trustPolicy(context.Background(), "arn:aws:iam::123456789012:role/missing"); !errors.Is(
+		err, errRoleNotFound) {
+		t.Errorf("missing role error = %v, want errRoleNotFound", err)
+	}
+}
+
+func TestIAMEndpoint(t *testing.T) {
+	for _, tt := range []struct {
+		cfg                  Config
+		wantURL, wantSigning string
+	}{
+		{Config{Region: "us-west-2"}, "https://iam.amazonaws.com", "us-east-1"},
+		{Config{Region: "us-west-2", FIPSEndpoints: true}, "https://iam-fips.amazonaws.com", "us-east-1"},
+		{Config{Region: "us-gov-west-1"}, "https://iam.us-gov.amazonaws.com", "us-gov-west-1"},
+		{Config{Region: "cn-north-1"}, "https://iam.cn-north-1.amazonaws.com.cn", "cn-north-1"},
+	} {
+		u, region := tt.cfg.iamEndpoint()
+		if u != tt.wantURL || region != tt.wantSigning {
+			t.Errorf("%s: iamEndpoint = %s, %s", tt.cfg.Region, u, region)
+		}
+	}
+}
+
+func TestReadOnlyGuard(t *testing.T) {
+	stub := &stubHTTP{bodies: []string{stsIdentityResponse}}
+	client := sts.New(sts.Options{
+		Region:      "us-west-2",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  stub,
+		APIOptions:  []func(*middleware.Stack) error{registerReadOnlyGuard},
+	})
+	if _, err := client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{}); err != nil {
+		t.Fatalf("GetCallerIdentity: %v", err)
+	}
+	_, err := client.AssumeRole(context.Background(), &sts.AssumeRoleInput{
+		RoleArn: aws.String("arn:aws:iam::123456789012:role/test"), RoleSessionName: aws.String("s"),
+	})
+	if !errors.Is(err, errMutationInDryRun) {
+		t.Errorf("AssumeRole error = %v, want errMutationInDryRun", err)
+	}
+	if stub.calls != 1 {
+		t.Errorf("sent %d requests, want only GetCallerIdentity", stub.calls)
+	}
+}
@@ -0,0 +1,127 @@
+package agentcore
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// passthroughWildcard ends a runtime_env_passthrough entry that copies
+// every variable with the given prefix.
+const passthroughWildcard = "*"
+
+// passthroughEntryRE matches a variable name, optionally followed by the
+// wildcard.
+var passthroughEntryRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\*?$`)
+
+// passthroughDeniedPrefixes are never copied into a runtime: AWS_
+// variables carry the deploying identity's credentials and config, which
+// the runtime replaces with its own role, and PROMPTPACK_ variables are
+// set by the adapter.
+var passthroughDeniedPrefixes = []string{"AWS_", "PROMPTPACK_"}
+
+// passthroughDeniedWords mark variables that likely hold credentials.
+// They are matched anywhere in the upper-cased name.
+var passthroughDeniedWords = []string{
+	"SECRET", "PASSWORD", "PASSWD", "TOKEN", "CREDENTIAL", "PRIVATE_KEY", "API_KEY", "ACCESS_KEY",
+}
+
+// passthroughDenied reports whether name must not be copied into a
+// runtime, and why.
+func passthroughDenied(name string) (string, bool) {
+	upper := strings.ToUpper(name)
+	for _, p := range passthroughDeniedPrefixes {
+		if strings.HasPrefix(upper, p) {
+			return fmt.Sprintf("%s variables are not passed through", p+passthroughWildcard), true
+		}
+	}
+	for _, w := range passthroughDeniedWords {
+		if strings.Contains(upper, w) {
+			return fmt.Sprintf("names containing %s may hold credentials", w), true
+		}
+	}
+	return "", false
+}
+
+// validateEnvPassthrough checks runtime_env_passthrough. Exact names on
+// the denylist are rejected; prefixes are accepted, and the denied
+// variables they match are skipped when copying.
+func validateEnvPassthrough(entries []string) []string {
+	var errs []string
+	for _, e := range entries {
+		if !passthroughEntryRE.MatchString(e) || e == passthroughWildcard {
+			errs = append(errs, fmt.Sprintf(
+				"runtime_env_passthrough entry %q must be a variable name or a prefix ending in %q",
+				e, passthroughWildcard))
+			continue
+		}
+		prefix, isPrefix := strings.CutSuffix(e, passthroughWildcard)
+		if isPrefix {
+			for _, p := range passthroughDeniedPrefixes {
+				if strings.HasPrefix(strings.ToUpper(prefix), p) {
+					errs = append(errs, fmt.Sprintf("runtime_env_passthrough entry %q: %s variables are not passed through",
+						e, p+passthroughWildcard))
+				}
+			}
+			continue
+		}
+		if why, denied := passthroughDenied(e); denied {
+			errs = append(errs, fmt.Sprintf("runtime_env_passthrough entry %q: %s", e, why))
+		}
+	}
+	return errs
+}
+
+// injectEnvPassthrough copies the deploy environment's variables named by
+// entries into env, skipping denied ones. environ is in os.Environ form.
+func injectEnvPassthrough(env map[string]string, entries, environ []string) {
+	if len(entries) == 0 {
+		return
+	}
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !passthroughMatches(entries, name) {
+			continue
+		}
+		if _, denied := passthroughDenied(name); denied {
+			continue
+		}
+		env[name] = value
+	}
+}
+
+// passthroughMatches reports whether name is listed in entries, by name
+// or by prefix.
+func passthroughMatches(entries []string, name string) bool {
+	for _, e := range entries {
+		if prefix, ok := strings.CutSuffix(e, passthroughWildcard); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnoseEnvPassthrough warns about exact runtime_env_passthrough names
+// that are not set in the deploy environment, since they are silently
+// left out of the runtime.
+func diagnoseEnvPassthrough(cfg *Config) []DiagnosticWarning {
+	var warnings []DiagnosticWarning
+	for _, e := range cfg.RuntimeEnvPassthrough {
+		if strings.HasSuffix(e, passthroughWildcard) {
+			continue
+		}
+		if _, set := os.LookupEnv(e); !set {
+			warnings = append(warnings, DiagnosticWarning{
+				Category: ErrCategoryConfiguration,
+				Message:  fmt.Sprintf("runtime_env_passthrough variable %s is not set in the deploy environment", e),
+				Hint:     "export it before running Apply, or remove it from runtime_env_passthrough",
+			})
+		}
+	}
+	return warnings
+}
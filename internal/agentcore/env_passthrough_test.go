@@ -0,0 +1,91 @@
+package agentcore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateEnvPassthrough(t *testing.T) {
+	tests := []struct {
+		entry   string
+		wantErr string
+	}{
+		{"FEATURE_FLAGS", ""},
+		{"MYAPP_*", ""},
+		{"SEARCH_ENDPOINT", ""},
+		{"", "must be a variable name"},
+		{"*", "must be a variable name"},
+		{"MY-VAR", "must be a variable name"},
+		{"A*B", "must be a variable name"},
+		{"AWS_SECRET_ACCESS_KEY", "AWS_* variables"},
+		{"AWS_*", "AWS_* variables"},
+		{"PROMPTPACK_PROTOCOL", "PROMPTPACK_* variables"},
+		{"OPENAI_API_KEY", "API_KEY"},
+		{"db_password", "PASSWORD"},
+		{"GITHUB_TOKEN", "TOKEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			errs := validateEnvPassthrough([]string{tt.entry})
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("errors = %v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errors = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInjectEnvPassthrough(t *testing.T) {
+	environ := []string{
+		"FEATURE_FLAGS=beta,fast",
+		"MYAPP_ENDPOINT=https://api.example.com",
+		"MYAPP_SECRET=hunter2",
+		"MYAPP_URL=a=b",
+		"UNLISTED=1",
+		"AWS_SECRET_ACCESS_KEY=abc",
+	}
+	env := map[string]string{}
+	injectEnvPassthrough(env, []string{"FEATURE_FLAGS", "MYAPP_*", "MISSING"}, environ)
+
+	want := map[string]string{
+		"FEATURE_FLAGS":  "beta,fast",
+		"MYAPP_ENDPOINT": "https://api.example.com",
+		"MYAPP_URL":      "a=b",
+	}
+	if len(env) != len(want) {
+		t.Fatalf("env = %v, want %v", env, want)
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("env[%s] = %q, want %q", k, env[k], v)
+		}
+	}
+}
+
+func TestBuildRuntimeEnvVars_Passthrough(t *testing.T) {
+	t.Setenv("FEATURE_FLAGS", "beta")
+	cfg := &Config{Region: "us-west-2", RuntimeEnvPassthrough: []string{"FEATURE_FLAGS"}}
+
+	env := buildRuntimeEnvVars(cfg)
+	if env["FEATURE_FLAGS"] != "beta" {
+		t.Errorf("FEATURE_FLAGS = %q, want beta", env["FEATURE_FLAGS"])
+	}
+	if env["AWS_REGION"] != "us-west-2" {
+		t.Errorf("AWS_REGION = %q, want the adapter's value", env["AWS_REGION"])
+	}
+}
+
+func TestDiagnoseEnvPassthrough(t *testing.T) {
+	t.Setenv("FEATURE_FLAGS", "beta")
+	cfg := &Config{RuntimeEnvPassthrough: []string{"FEATURE_FLAGS", "MYAPP_*", "PASSTHROUGH_UNSET_VAR"}}
+
+	warnings := diagnoseEnvPassthrough(cfg)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "PASSTHROUGH_UNSET_VAR") {
+		t.Errorf("warnings = %+v, want one about PASSTHROUGH_UNSET_VAR", warnings)
+	}
+}
@@ -2,34 +2,56 @@ package agentcore
 
 import (
 	"encoding/json"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/AltairaLabs/PromptKit/runtime/prompt"
 )
 
 // Environment variable keys injected into AgentCore runtimes.
 const (
-	EnvLogGroup        = "PROMPTPACK_LOG_GROUP"
-	EnvTracingEnabled  = "PROMPTPACK_TRACING_ENABLED"
-	EnvMemoryStore     = "PROMPTPACK_MEMORY_STORE"
-	EnvMemoryID        = "PROMPTPACK_MEMORY_ID"
-	EnvA2AAgents       = "PROMPTPACK_AGENTS"
-	EnvA2AAuthMode     = "PROMPTPACK_A2A_AUTH_MODE"
-	EnvA2AAuthRole     = "PROMPTPACK_A2A_AUTH_ROLE"
-	EnvPolicyEngineARN = "PROMPTPACK_POLICY_ENGINE_ARN"
-	EnvMetricsConfig   = "PROMPTPACK_METRICS_CONFIG"
-	EnvDashboardConfig = "PROMPTPACK_DASHBOARD_CONFIG"
-	EnvAgentName       = "PROMPTPACK_AGENT"
-	EnvProviderType    = "PROMPTPACK_PROVIDER_TYPE"
-	EnvProviderModel   = "PROMPTPACK_PROVIDER_MODEL"
-	EnvProtocol        = "PROMPTPACK_PROTOCOL"
+	EnvLogGroup         = "PROMPTPACK_LOG_GROUP"
+	EnvTracingEnabled   = "PROMPTPACK_TRACING_ENABLED"
+	EnvMemoryStore      = "PROMPTPACK_MEMORY_STORE"
+	EnvMemoryID         = "PROMPTPACK_MEMORY_ID"
+	EnvA2AAgents        = "PROMPTPACK_AGENTS"
+	EnvA2AAuthMode      = "PROMPTPACK_A2A_AUTH_MODE"
+	EnvA2AAuthRole      = "PROMPTPACK_A2A_AUTH_ROLE"
+	EnvPolicyEngineARN  = "PROMPTPACK_POLICY_ENGINE_ARN"
+	EnvMetricsConfig    = "PROMPTPACK_METRICS_CONFIG"
+	EnvDashboardConfig  = "PROMPTPACK_DASHBOARD_CONFIG"
+	EnvAgentName        = "PROMPTPACK_AGENT"
+	EnvProviderType     = "PROMPTPACK_PROVIDER_TYPE"
+	EnvProviderModel    = "PROMPTPACK_PROVIDER_MODEL"
+	EnvProtocol         = "PROMPTPACK_PROTOCOL"
+	EnvInferenceProfile = "PROMPTPACK_INFERENCE_PROFILE"
+	EnvGatewaySearch    = "PROMPTPACK_GATEWAY_SEARCH"
+
+	// EnvGatewayURL is the MCP URL of the tool gateway serving the
+	// runtime's agent.
+	EnvGatewayURL = "PROMPTPACK_GATEWAY_URL"
+
+	// EnvAllowedTools lists, comma-separated, the pack tools the prompt
+	// served by the runtime uses, so it advertises no others.
+	EnvAllowedTools = "PROMPTPACK_ALLOWED_TOOLS"
+
+	// EnvResponseModeration makes the runtime bridge enforce the agent
+	// prompt's banned_words and regex validators on its responses.
+	EnvResponseModeration = "PROMPTPACK_RESPONSE_MODERATION"
+
+	// EnvOTELServiceName is the service name the runtime's OTEL spans
+	// carry, which the online eval config filters on.
+	EnvOTELServiceName = "OTEL_SERVICE_NAME"
 )
 
 // buildRuntimeEnvVars constructs the environment variable map that will be
 // passed to CreateAgentRuntime / UpdateAgentRuntime. It reads observability,
-// memory, and auth settings from cfg and merges them into a single map.
+// memory, and auth settings from cfg and merges them into a single map,
+// starting from the deploy environment variables cfg passes through.
 func buildRuntimeEnvVars(cfg *Config) map[string]string {
 	env := make(map[string]string)
+	injectEnvPassthrough(env, cfg.RuntimeEnvPassthrough, os.Environ())
 
 	if cfg.Observability != nil {
 		if cfg.Observability.CloudWatchLogGroup != "" {
@@ -44,6 +66,10 @@ func buildRuntimeEnvVars(cfg *Config) map[string]string {
 		env[EnvMemoryStore] = cfg.MemoryStrategiesCSV()
 	}
 
+	injectToolAuditEnvVars(env, cfg.Tools)
+	injectSessionEnvVars(env, cfg.Sessions)
+	injectLifecycleEnvVars(env, cfg.Lifecycle)
+
 	if cfg.A2AAuth != nil && cfg.A2AAuth.Mode != "" {
 		env[EnvA2AAuthMode] = cfg.A2AAuth.Mode
 		if cfg.A2AAuth.Mode == A2AAuthModeIAM && cfg.RuntimeRoleARN != "" {
@@ -51,9 +77,10 @@ func buildRuntimeEnvVars(cfg *Config) map[string]string {
 		}
 	}
 
-	// Code deploy: pack.json is bundled in the ZIP alongside main.py.
-	// The Go binary reads PROMPTPACK_FILE which is set by main.py at startup.
-	// No pack-related env vars needed here.
+	// Code deploy: pack.json is bundled in the ZIP next to the binary. The
+	// Python layout's main.py points PROMPTPACK_FILE at it; in the binary
+	// layout the runtime finds it beside its own executable. No pack-related
+	// env vars needed here.
 
 	// AWS_REGION is required by the Go runtime to configure Bedrock as the
 	// LLM provider. AgentCore may set it automatically, but we inject it
@@ -66,11 +93,55 @@ func buildRuntimeEnvVars(cfg *Config) map[string]string {
 		env[EnvProtocol] = cfg.Protocol
 	}
 
+	if cfg.ResponseModeration {
+		env[EnvResponseModeration] = strconv.FormatBool(true)
+	}
+
+	// Only packs with tools get a gateway to search.
+	if cfg.gatewaySearchEnabled() && len(cfg.PackTools) > 0 {
+		env[EnvGatewaySearch] = GatewaySearchSemantic
+	}
+
 	injectProviderEnvVars(env, cfg.ArenaConfig)
 
 	return env
 }
 
+// injectToolAuditEnvVars enables the runtime's tool-call audit trail when
+// tools.audit.enabled is set.
+func injectToolAuditEnvVars(env map[string]string, tools *ToolsConfig) {
+	if tools == nil || tools.Audit == nil || !tools.Audit.Enabled {
+		return
+	}
+	env[EnvToolAudit] = strconv.FormatBool(true)
+	if n := tools.Audit.MaxEventsPerSession; n > 0 {
+		env[EnvToolAuditMaxEvents] = strconv.Itoa(n)
+	}
+}
+
+// injectSessionEnvVars enables session metadata persistence and the
+// per-session turn, concurrency, and token limits.
+func injectSessionEnvVars(env map[string]string, sessions *SessionsConfig) {
+	if sessions == nil {
+		return
+	}
+	if sessions.Persist {
+		env[EnvSessionStore] = SessionStoreMemory
+	}
+	if sessions.PersistTasks {
+		env[EnvA2ATaskStore] = A2ATaskStoreMemory
+	}
+	if sessions.MaxTurns > 0 {
+		env[EnvSessionMaxTurns] = strconv.Itoa(sessions.MaxTurns)
+	}
+	if sessions.MaxConcurrent > 0 {
+		env[EnvSessionMaxConcurrent] = strconv.Itoa(sessions.MaxConcurrent)
+	}
+	if sessions.DailyTokenBudget > 0 {
+		env[EnvSessionDailyTokenBudget] = strconv.Itoa(sessions.DailyTokenBudget)
+	}
+}
+
 // injectProviderEnvVars sets provider type and model env vars from the
 // arena config's loaded providers.
 func injectProviderEnvVars(env map[string]string, arena *ArenaConfig) {
@@ -87,7 +158,12 @@ func injectProviderEnvVars(env map[string]string, arena *ArenaConfig) {
 }
 
 // runtimeEnvVarsForAgent returns a copy of cfg.RuntimeEnvVars with
-// PROMPTPACK_AGENT set to the given agent name. Each runtime gets its
+// PROMPTPACK_AGENT set to the given agent name, PROMPTPACK_AGENT_CARD
+// carrying that agent's card overrides, PROMPTPACK_GATEWAY_URL
+// pointing at the agent's tool gateway, PROMPTPACK_MEMORY_NAMESPACE
+// set to the agent's memory namespace, and PROMPTPACK_ALLOWED_TOOLS
+// listing the tools its prompt uses, and OTEL_SERVICE_NAME set to the
+// service name the online eval config evaluates. Each runtime gets its
 // own copy so the per-agent value does not leak across runtimes.
 //
 // For single-agent packs the runtime is named after the pack ID, which
@@ -104,21 +180,35 @@ func runtimeEnvVarsForAgent(cfg *Config, agentName string) map[string]string {
 	if len(cfg.PromptNames) == 0 || cfg.PromptNames[agentName] {
 		env[EnvAgentName] = agentName
 	}
+	if card := agentCardEnvValue(cfg.AgentCards, agentName); card != "" {
+		env[EnvAgentCard] = card
+	}
+	if url := gatewayURL(cfg.gatewayARNFor(agentName)); url != "" {
+		env[EnvGatewayURL] = url
+	}
+	if ns := cfg.memoryNamespace(agentName); ns != "" {
+		env[EnvMemoryNamespace] = ns
+	}
+	if tools := cfg.AgentTools[agentName]; len(tools) > 0 {
+		env[EnvAllowedTools] = strings.Join(tools, ",")
+	}
+	env[EnvOTELServiceName] = cfg.serviceName(agentName)
 	return env
 }
 
 // buildA2AEndpointMap builds a JSON string mapping agent member names to their
-// runtime ARNs. Only successfully created/updated runtimes are included.
+// runtime ARNs. Only successfully created, updated, or replaced runtimes
+// are included.
 func buildA2AEndpointMap(runtimeResources []ResourceState) string {
 	m := make(map[string]string)
 	for _, r := range runtimeResources {
 		if r.Type != ResTypeAgentRuntime {
 			continue
 		}
-		if r.Status != ResStatusCreated && r.Status != ResStatusUpdated {
-			continue
+		switch r.Status {
+		case ResStatusCreated, ResStatusUpdated, ResStatusReplaced:
+			m[r.Name] = r.ARN
 		}
-		m[r.Name] = r.ARN
 	}
 	if len(m) == 0 {
 		return ""
@@ -19,6 +19,24 @@ func TestBuildRuntimeEnvVars(t *testing.T) {
 			cfg:  &Config{},
 			want: map[string]string{},
 		},
+		{
+			name: "gateway search with tools",
+			cfg: &Config{
+				Gateway:   &GatewayConfig{SearchType: GatewaySearchSemantic},
+				PackTools: map[string]*prompt.PackTool{"search": {Name: "search"}},
+			},
+			want: map[string]string{EnvGatewaySearch: GatewaySearchSemantic},
+		},
+		{
+			name: "response moderation",
+			cfg:  &Config{ResponseModeration: true},
+			want: map[string]string{EnvResponseModeration: "true"},
+		},
+		{
+			name: "gateway search without tools is omitted",
+			cfg:  &Config{Gateway: &GatewayConfig{SearchType: GatewaySearchSemantic}},
+			want: map[string]string{},
+		},
 		{
 			name: "observability log group",
 			cfg: &Config{
@@ -475,4 +493,12 @@ func TestRuntimeEnvVarsForAgent(t *testing.T) {
 			t.Errorf("agent B: PROMPTPACK_AGENT = %q, want %q", envB[EnvAgentName], "worker")
 		}
 	})
+
+	t.Run("sets the service name of the agent's spans", func(t *testing.T) {
+		cfg := &Config{Workspace: "dev"}
+
+		if got := runtimeEnvVarsForAgent(cfg, "worker")[EnvOTELServiceName]; got != "worker_dev.DEFAULT" {
+			t.Errorf("OTEL_SERVICE_NAME = %q, want %q", got, "worker_dev.DEFAULT")
+		}
+	})
 }
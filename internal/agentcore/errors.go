@@ -1,6 +1,7 @@
 package agentcore
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -57,13 +58,20 @@ func (e *DeployError) Unwrap() error {
 }
 
 // classifyAWSError inspects an AWS error and returns a category and
-// remediation hint. It checks for common patterns in error messages.
+// remediation hint. AWS API errors are classified by their error code and
+// context deadlines by type; anything else falls back to common patterns
+// in the error message.
 func classifyAWSError(err error) (category, remediation string) {
 	if err == nil {
 		return ErrCategoryResource, ""
 	}
-	msg := err.Error()
-	return classifyErrorMessage(msg)
+	if class, ok := errorCodeClasses[awsErrorCode(err)]; ok {
+		return class.category, class.remediation
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ErrCategoryTimeout, hintRetryOrTimeout
+	}
+	return classifyErrorMessage(err.Error())
 }
 
 // classifyErrorMessage determines category and remediation from an error string.
@@ -122,9 +130,16 @@ func containsAny(s string, substrings []string) bool {
 	return false
 }
 
-// newDeployError creates a DeployError with automatic AWS error classification.
+// newDeployError creates a DeployError with automatic AWS error
+// classification. A permission error that names the denied action gets a
+// hint naming the action to allow and the identity to allow it to.
 func newDeployError(operation, resType, resName string, cause error) *DeployError {
 	category, remediation := classifyAWSError(cause)
+	if category == ErrCategoryPermission {
+		if m, ok := parseMissingPermission(cause.Error()); ok {
+			remediation = m.hint()
+		}
+	}
 	return &DeployError{
 		Category:     category,
 		ResourceType: resType,
@@ -0,0 +1,568 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+// evalAlertSuffix names a pack's eval_alert resource after its pack ID.
+const evalAlertSuffix = "_eval_alert"
+
+// Eval alert metadata keys stored in ResourceState.Metadata, alongside
+// metaLogGroupName.
+const (
+	metaEvalAlertFilter   = "filter"
+	metaEvalAlertTarget   = "target"
+	metaEvalAlertEndpoint = "endpoint"
+)
+
+// Services an eval alert can notify, as named in their ARNs.
+const (
+	evalAlertServiceLambda = "lambda"
+	evalAlertServiceSNS    = "sns"
+)
+
+// evalAlertNamespace is the CloudWatch namespace of the metrics eval alerts
+// count low-scoring results in.
+const evalAlertNamespace = "PromptPack/EvalAlerts"
+
+// evalAlertPeriodSeconds is the period the alarm sums low-scoring results
+// over.
+const evalAlertPeriodSeconds = "60"
+
+// evalAlertTargetID identifies the one target of an eval alert rule.
+const evalAlertTargetID = "eval-alert"
+
+// evalAlertPrincipal is the service principal EventBridge invokes targets
+// as.
+const evalAlertPrincipal = "events.amazonaws.com"
+
+// errCodeResourceConflict is the code Lambda returns when a permission
+// statement with the same ID already exists.
+const errCodeResourceConflict = "ResourceConflictException"
+
+// connectionARNRE matches an EventBridge connection ARN.
+var connectionARNRE = regexp.MustCompile(`^arn:aws[a-z-]*:events:[a-z0-9-]+:\d{12}:connection/.+`)
+
+// EvalAlertConfig provisions an EventBridge rule that notifies an SNS
+// topic, a Lambda function, or an HTTPS endpoint when online evaluation
+// results score below a threshold. A metric filter on the log group the
+// online evaluation config writes its results to counts such results, a
+// CloudWatch alarm fires on the first, and the rule forwards the alarm's
+// state change to the target.
+type EvalAlertConfig struct {
+	// ScoreBelow alerts on results whose score is lower than this value.
+	ScoreBelow float64 `json:"score_below"`
+
+	// TargetARN is the SNS topic or Lambda function the rule notifies.
+	TargetARN string `json:"target_arn,omitempty"`
+
+	// Endpoint is the HTTPS URL the rule posts to, through an EventBridge
+	// API destination, in place of TargetARN.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ConnectionARN is the EventBridge connection holding the endpoint's
+	// authorization. Required with Endpoint.
+	ConnectionARN string `json:"connection_arn,omitempty"`
+
+	// RoleARN is the IAM role EventBridge assumes to invoke the API
+	// destination. Required with Endpoint.
+	RoleARN string `json:"role_arn,omitempty"`
+}
+
+// target returns what the alert notifies, for plan details.
+func (c *EvalAlertConfig) target() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	return c.TargetARN
+}
+
+// validateEvalAlert checks the eval_alert block, including that its ARNs
+// are in region's partition.
+func validateEvalAlert(c *EvalAlertConfig, region string) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	switch {
+	case (c.TargetARN == "") == (c.Endpoint == ""):
+		errs = append(errs, "eval_alert must set exactly one of target_arn and endpoint")
+	case c.Endpoint != "":
+		errs = append(errs, validateEvalAlertEndpoint(c)...)
+	default:
+		if evalAlertTargetService(c.TargetARN) == "" {
+			errs = append(errs, fmt.Sprintf(
+				"eval_alert.target_arn %q must be an SNS topic or Lambda function ARN", c.TargetARN))
+		}
+		if c.ConnectionARN != "" || c.RoleARN != "" {
+			errs = append(errs, "eval_alert.connection_arn and role_arn are only used with endpoint")
+		}
+	}
+	return append(errs, validateRegionPartition(region, map[string]string{
+		"eval_alert.target_arn":     c.TargetARN,
+		"eval_alert.connection_arn": c.ConnectionARN,
+		"eval_alert.role_arn":       c.RoleARN,
+	})...)
+}
+
+// validateEvalAlertEndpoint checks the endpoint of an eval alert and the
+// connection and role its API destination needs.
+func validateEvalAlertEndpoint(c *EvalAlertConfig) []string {
+	var errs []string
+	if u, err := url.Parse(c.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = append(errs, fmt.Sprintf("eval_alert.endpoint %q must be an https URL", c.Endpoint))
+	}
+	if !connectionARNRE.MatchString(c.ConnectionARN) {
+		errs = append(errs, fmt.Sprintf(
+			"eval_alert.connection_arn %q must be an EventBridge connection ARN", c.ConnectionARN))
+	}
+	if !roleARNRE.MatchString(c.RoleARN) {
+		errs = append(errs, fmt.Sprintf("eval_alert.role_arn %q is not a valid IAM role ARN", c.RoleARN))
+	}
+	return errs
+}
+
+// evalAlertTargetService returns the service of an eval alert target ARN,
+// or "" when it is not an SNS topic or Lambda function.
+func evalAlertTargetService(arn string) string {
+	a, ok := parseARN(arn)
+	if !ok || a.Region == "" || a.Account == "" {
+		return ""
+	}
+	switch {
+	case a.Service == evalAlertServiceLambda && lambdaFunctionARNPattern.MatchString(arn),
+		a.Service == evalAlertServiceSNS && a.Resource != "" && !strings.Contains(a.Resource, ":"):
+		return a.Service
+	}
+	return ""
+}
+
+// evalAlertFilterPattern matches result events scoring below threshold.
+// The score field is the one evalResultsQuery aggregates.
+func evalAlertFilterPattern(threshold float64) string {
+	return fmt.Sprintf("{ $.attributes.gen_ai.evaluation.score.value < %s }",
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+}
+
+// planEvalAlert returns the eval_alert change when eval_alert is set and
+// the pack has an online evaluation config whose results it can watch.
+func planEvalAlert(pack *prompt.Pack, cfg *Config, desired []deploy.ResourceChange) []deploy.ResourceChange {
+	if cfg.EvalAlert == nil || !hasChangeOfType(desired, ResTypeOnlineEvalConfig) {
+		return nil
+	}
+	return []deploy.ResourceChange{{
+		Type:   ResTypeEvalAlert,
+		Name:   pack.ID + evalAlertSuffix,
+		Action: deploy.ActionCreate,
+		Detail: fmt.Sprintf("Alert %s when eval results score below %s",
+			cfg.EvalAlert.target(), strconv.FormatFloat(cfg.EvalAlert.ScoreBelow, 'f', -1, 64)),
+	}}
+}
+
+// hasChangeOfType reports whether changes include one of resource type
+// rtype.
+func hasChangeOfType(changes []deploy.ResourceChange, rtype string) bool {
+	for _, c := range changes {
+		if c.Type == rtype {
+			return true
+		}
+	}
+	return false
+}
+
+// evalAlertWarning returns the Plan warning for an eval_alert that has no
+// online evaluation results to watch, or "".
+func evalAlertWarning(cfg *Config, changes []deploy.ResourceChange) string {
+	if cfg.EvalAlert == nil || hasChangeOfType(changes, ResTypeEvalAlert) {
+		return ""
+	}
+	return "eval_alert is set, but the pack has no llm_as_judge or builtin evals to score traces; no alert is created"
+}
+
+// evalAlertDeployment is what PutEvalAlert deployed: the rule, the log
+// group its metric filter watches, and the rule's target.
+type evalAlertDeployment struct {
+	ruleARN, logGroup, target string
+}
+
+// applyEvalAlert alerts on the results of the online evaluation config
+// deployed before it.
+func applyEvalAlert(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if ac.cfg.EvalAlert == nil {
+		return resources, applyErr, nil
+	}
+	oecARN := ""
+	for _, r := range resources {
+		if r.Type == ResTypeOnlineEvalConfig && r.ARN != "" && r.Status != ResStatusFailed {
+			oecARN = r.ARN
+		}
+	}
+	if oecARN == "" {
+		return resources, applyErr, nil
+	}
+	var deployed evalAlertDeployment
+	put := func(ctx context.Context, name string, cfg *Config) (string, error) {
+		d, err := ac.client.PutEvalAlert(ctx, name, oecARN, cfg)
+		if err != nil {
+			return "", err
+		}
+		deployed = *d
+		return d.ruleARN, nil
+	}
+	update := func(ctx context.Context, _ string, name string, cfg *Config) (string, error) {
+		return put(ctx, name, cfg)
+	}
+	phase := applyPhase(ctx, ac.reporter, put, update, nil, ac.cfg,
+		[]string{ac.pack.ID + evalAlertSuffix}, ResTypeEvalAlert, ac.span, ac.priorMap)
+	resources, applyErr, cbErr := mergePhase(resources, applyErr, phase)
+	annotateEvalAlert(resources, ac.cfg, deployed)
+	return resources, applyErr, cbErr
+}
+
+// annotateEvalAlert records on the deployed eval_alert what Destroy and
+// Status need to find its parts: the log group, the name the metric
+// filter, alarm, rule, and API destination share, the rule's target, and
+// the endpoint when the target is an API destination.
+func annotateEvalAlert(resources []ResourceState, cfg *Config, deployed evalAlertDeployment) {
+	for i := range resources {
+		r := &resources[i]
+		if r.Type != ResTypeEvalAlert || r.ARN == "" {
+			continue
+		}
+		r.Metadata = map[string]string{
+			metaLogGroupName:    deployed.logGroup,
+			metaEvalAlertFilter: cfg.awsName(r.Name),
+			metaEvalAlertTarget: deployed.target,
+		}
+		if cfg.EvalAlert.Endpoint != "" {
+			r.Metadata[metaEvalAlertEndpoint] = cfg.EvalAlert.Endpoint
+		}
+	}
+}
+
+// logGroupNameFromARN returns the name of the log group arn refers to, or
+// "" when it is not a log group ARN.
+func logGroupNameFromARN(arn string) string {
+	a, ok := parseARN(arn)
+	if !ok || a.Service != "logs" {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(a.Resource, "log-group:"), ":*")
+}
+
+// evalAlertEventPattern matches the alarm alarmARN going into ALARM.
+func evalAlertEventPattern(alarmARN string) string {
+	pattern, _ := json.Marshal(map[string]any{
+		"source":      []string{"aws.cloudwatch"},
+		"detail-type": []string{"CloudWatch Alarm State Change"},
+		"resources":   []string{alarmARN},
+		"detail":      map[string]any{"state": map[string]any{"value": []string{"ALARM"}}},
+	})
+	return string(pattern)
+}
+
+// PutEvalAlert creates or updates the eval alert name on the results log
+// group of the online evaluation config onlineEvalARN: a metric filter
+// counting results below the threshold, an alarm on that count, and an
+// EventBridge rule sending the alarm's state changes to the target.
+// AgentCore creates the log group on the first result, so it is created
+// here when missing to let the filter attach before then.
+func (c *realAWSClient) PutEvalAlert(ctx context.Context, name, onlineEvalARN string, cfg *Config) (
+	*evalAlertDeployment, error,
+) {
+	logGroup, err := c.evalResultsLogGroup(ctx, ResourceState{Type: ResTypeOnlineEvalConfig, ARN: onlineEvalARN})
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.logsClient.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(logGroup)})
+	if err != nil && !isAlreadyExists(err) {
+		return nil, fmt.Errorf("CreateLogGroup %q: %w", logGroup, err)
+	}
+	alert, awsName := cfg.EvalAlert, cfg.awsName(name)
+	if err = c.deleteLegacySubscription(ctx, logGroup, awsName); err != nil {
+		return nil, err
+	}
+	if err = c.putEvalAlertMetric(ctx, logGroup, awsName, alert.ScoreBelow); err != nil {
+		return nil, err
+	}
+	alarmARN, err := c.putEvalAlertAlarm(ctx, awsName, cfg)
+	if err != nil {
+		return nil, err
+	}
+	target := alert.TargetARN
+	if alert.Endpoint != "" {
+		if target, err = c.putAPIDestination(ctx, awsName, alert); err != nil {
+			return nil, err
+		}
+	}
+	ruleARN, err := c.putEvalAlertRule(ctx, awsName, alarmARN, target, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &evalAlertDeployment{ruleARN: ruleARN, logGroup: logGroup, target: target}, nil
+}
+
+// deleteLegacySubscription deletes the subscription filter earlier
+// versions of the adapter delivered eval alerts through, if there is one.
+func (c *realAWSClient) deleteLegacySubscription(ctx context.Context, logGroup, name string) error {
+	_, err := c.logsClient.DeleteSubscriptionFilter(ctx, &cloudwatchlogs.DeleteSubscriptionFilterInput{
+		FilterName:   aws.String(name),
+		LogGroupName: aws.String(logGroup),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteSubscriptionFilter %q: %w", name, err)
+	}
+	return nil
+}
+
+// putEvalAlertMetric puts the metric filter counting results scoring
+// below threshold in the metric name.
+func (c *realAWSClient) putEvalAlertMetric(ctx context.Context, logGroup, name string, threshold float64) error {
+	_, err := c.logsClient.PutMetricFilter(ctx, &cloudwatchlogs.PutMetricFilterInput{
+		FilterName:    aws.String(name),
+		LogGroupName:  aws.String(logGroup),
+		FilterPattern: aws.String(evalAlertFilterPattern(threshold)),
+		MetricTransformations: []logstypes.MetricTransformation{{
+			MetricName:      aws.String(name),
+			MetricNamespace: aws.String(evalAlertNamespace),
+			MetricValue:     aws.String("1"),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("PutMetricFilter %q: %w", name, err)
+	}
+	return nil
+}
+
+// putEvalAlertAlarm puts the alarm that fires when a minute has a
+// low-scoring result, and returns its ARN.
+func (c *realAWSClient) putEvalAlertAlarm(ctx context.Context, name string, cfg *Config) (string, error) {
+	params := url.Values{
+		"AlarmName":          {name},
+		"AlarmDescription":   {"Online evaluation results scored below the eval_alert threshold"},
+		"Namespace":          {evalAlertNamespace},
+		"MetricName":         {name},
+		"Statistic":          {"Sum"},
+		"Period":             {evalAlertPeriodSeconds},
+		"EvaluationPeriods":  {"1"},
+		"Threshold":          {"1"},
+		"ComparisonOperator": {"GreaterThanOrEqualToThreshold"},
+		"TreatMissingData":   {"notBreaching"},
+	}
+	for i, k := range slices.Sorted(maps.Keys(cfg.ResourceTags)) {
+		prefix := fmt.Sprintf("Tags.member.%d.", i+1)
+		params.Set(prefix+"Key", k)
+		params.Set(prefix+"Value", cfg.ResourceTags[k])
+	}
+	if err := c.callQuery(ctx, cloudWatchAPI, "PutMetricAlarm", params); err != nil {
+		return "", fmt.Errorf("PutMetricAlarm %q: %w", name, err)
+	}
+	p, _ := partitionForRegion(cfg.Region)
+	return fmt.Sprintf("arn:%s:cloudwatch:%s:%s:alarm:%s",
+		p.id, cfg.Region, extractAccountFromARN(cfg.RuntimeRoleARN), name), nil
+}
+
+// putAPIDestination creates or updates the API destination posting to
+// alert's endpoint, and returns its ARN.
+func (c *realAWSClient) putAPIDestination(ctx context.Context, name string, alert *EvalAlertConfig) (string, error) {
+	in := map[string]string{
+		"Name":               name,
+		"ConnectionArn":      alert.ConnectionARN,
+		"InvocationEndpoint": alert.Endpoint,
+		"HttpMethod":         http.MethodPost,
+	}
+	var out struct {
+		APIDestinationArn string `json:"ApiDestinationArn"`
+	}
+	err := c.callJSON(ctx, eventBridgeAPI, "CreateApiDestination", in, &out)
+	if isAlreadyExists(err) {
+		err = c.callJSON(ctx, eventBridgeAPI, "UpdateApiDestination", in, &out)
+	}
+	if err != nil {
+		return "", fmt.Errorf("put API destination %q: %w", name, err)
+	}
+	return out.APIDestinationArn, nil
+}
+
+// putEvalAlertRule puts the rule matching the alarm alarmARN going into
+// ALARM, lets a Lambda target be invoked by it, and points it at target.
+// It returns the rule's ARN.
+func (c *realAWSClient) putEvalAlertRule(ctx context.Context, name, alarmARN, target string, cfg *Config) (
+	string, error,
+) {
+	tags := make([]map[string]string, 0, len(cfg.ResourceTags))
+	for _, k := range slices.Sorted(maps.Keys(cfg.ResourceTags)) {
+		tags = append(tags, map[string]string{"Key": k, "Value": cfg.ResourceTags[k]})
+	}
+	var rule struct {
+		RuleArn string `json:"RuleArn"`
+	}
+	if err := c.callJSON(ctx, eventBridgeAPI, "PutRule", map[string]any{
+		"Name":         name,
+		"Description":  "Alerts when online evaluation results score below the eval_alert threshold",
+		"EventPattern": evalAlertEventPattern(alarmARN),
+		"State":        "ENABLED",
+		"Tags":         tags,
+	}, &rule); err != nil {
+		return "", fmt.Errorf("PutRule %q: %w", name, err)
+	}
+	if evalAlertTargetService(target) == evalAlertServiceLambda {
+		if err := c.allowEventsToInvoke(ctx, name, target, rule.RuleArn); err != nil {
+			return "", err
+		}
+	}
+	ruleTarget := map[string]string{"Id": evalAlertTargetID, "Arn": target}
+	if cfg.EvalAlert.Endpoint != "" {
+		ruleTarget["RoleArn"] = cfg.EvalAlert.RoleARN
+	}
+	var out struct {
+		FailedEntryCount int `json:"FailedEntryCount"`
+		FailedEntries    []struct {
+			ErrorCode, ErrorMessage string
+		} `json:"FailedEntries"`
+	}
+	if err := c.callJSON(ctx, eventBridgeAPI, "PutTargets", map[string]any{
+		"Rule":    name,
+		"Targets": []map[string]string{ruleTarget},
+	}, &out); err != nil {
+		return "", fmt.Errorf("PutTargets %q: %w", name, err)
+	}
+	if out.FailedEntryCount > 0 && len(out.FailedEntries) > 0 {
+		f := out.FailedEntries[0]
+		return "", fmt.Errorf("PutTargets %q: %s: %s", name, f.ErrorCode, f.ErrorMessage)
+	}
+	return rule.RuleArn, nil
+}
+
+// allowEventsToInvoke lets EventBridge invoke the Lambda function for the
+// rule ruleARN. A statement left by an earlier Apply is kept.
+func (c *realAWSClient) allowEventsToInvoke(ctx context.Context, statementID, function, ruleARN string) error {
+	_, err := c.lambdaClient.AddPermission(ctx, &lambda.AddPermissionInput{
+		FunctionName: aws.String(function),
+		StatementId:  aws.String(statementID),
+		Action:       aws.String("lambda:InvokeFunction"),
+		Principal:    aws.String(evalAlertPrincipal),
+		SourceArn:    aws.String(ruleARN),
+	})
+	if err != nil && awsErrorCode(err) != errCodeResourceConflict {
+		return fmt.Errorf("AddPermission on %q: %w", function, err)
+	}
+	return nil
+}
+
+// deleteEvalAlert deletes the rule and its target, the API destination,
+// the alarm, the metric filter and, for a Lambda target, the permission
+// Apply granted. The log group belongs to the online evaluation config and
+// is left in place.
+func (c *realAWSClient) deleteEvalAlert(ctx context.Context, res ResourceState) error {
+	logGroup, name := res.Metadata[metaLogGroupName], res.Metadata[metaEvalAlertFilter]
+	if logGroup == "" || name == "" {
+		return fmt.Errorf("eval alert %q: state is missing the log group or filter name", res.Name)
+	}
+	if err := c.deleteEvalAlertRule(ctx, name); err != nil {
+		return err
+	}
+	if res.Metadata[metaEvalAlertEndpoint] != "" {
+		err := c.callJSON(ctx, eventBridgeAPI, "DeleteApiDestination", map[string]string{"Name": name}, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("DeleteApiDestination %q: %w", name, err)
+		}
+	}
+	if err := c.callQuery(ctx, cloudWatchAPI, "DeleteAlarms", url.Values{"AlarmNames.member.1": {name}}); err != nil {
+		return fmt.Errorf("DeleteAlarms %q: %w", name, err)
+	}
+	_, err := c.logsClient.DeleteMetricFilter(ctx, &cloudwatchlogs.DeleteMetricFilterInput{
+		FilterName:   aws.String(name),
+		LogGroupName: aws.String(logGroup),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteMetricFilter %q: %w", name, err)
+	}
+	if err = c.deleteLegacySubscription(ctx, logGroup, name); err != nil {
+		return err
+	}
+	return c.removeEvalAlertPermission(ctx, res.Metadata[metaEvalAlertTarget], name)
+}
+
+// deleteEvalAlertRule removes the rule's target, then the rule.
+func (c *realAWSClient) deleteEvalAlertRule(ctx context.Context, name string) error {
+	err := c.callJSON(ctx, eventBridgeAPI, "RemoveTargets",
+		map[string]any{"Rule": name, "Ids": []string{evalAlertTargetID}}, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("RemoveTargets %q: %w", name, err)
+	}
+	err = c.callJSON(ctx, eventBridgeAPI, "DeleteRule", map[string]string{"Name": name}, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteRule %q: %w", name, err)
+	}
+	return nil
+}
+
+// removeEvalAlertPermission deletes the permission Apply granted a Lambda
+// target. Other targets have none.
+func (c *realAWSClient) removeEvalAlertPermission(ctx context.Context, target, statementID string) error {
+	if evalAlertTargetService(target) != evalAlertServiceLambda {
+		return nil
+	}
+	_, err := c.lambdaClient.RemovePermission(ctx, &lambda.RemovePermissionInput{
+		FunctionName: aws.String(target),
+		StatementId:  aws.String(statementID),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("RemovePermission on %q: %w", target, err)
+	}
+	return nil
+}
+
+// checkEvalAlert reports an eval alert as unhealthy when its rule is
+// disabled or no longer sends to the target Apply set.
+func (c *realAWSClient) checkEvalAlert(ctx context.Context, res ResourceState) (string, error) {
+	name := res.Metadata[metaEvalAlertFilter]
+	var rule struct {
+		State string `json:"State"`
+	}
+	err := c.callJSON(ctx, eventBridgeAPI, "DescribeRule", map[string]string{"Name": name}, &rule)
+	if isNotFound(err) {
+		return StatusMissing, nil
+	}
+	if err != nil {
+		return StatusUnhealthy, fmt.Errorf("DescribeRule %q: %w", name, err)
+	}
+	if rule.State != "ENABLED" {
+		return StatusUnhealthy, nil
+	}
+	var out struct {
+		Targets []struct {
+			ID  string `json:"Id"`
+			Arn string `json:"Arn"`
+		} `json:"Targets"`
+	}
+	if err = c.callJSON(ctx, eventBridgeAPI, "ListTargetsByRule", map[string]string{"Rule": name}, &out); err != nil {
+		return StatusUnhealthy, fmt.Errorf("ListTargetsByRule %q: %w", name, err)
+	}
+	for _, t := range out.Targets {
+		if t.ID == evalAlertTargetID && t.Arn == res.Metadata[metaEvalAlertTarget] {
+			return StatusHealthy, nil
+		}
+	}
+	return StatusUnhealthy, nil
+}
@@ -0,0 +1,380 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+const (
+	testAlertLambdaARN = "arn:aws:lambda:us-west-2:123456789012:function:page-oncall"
+	testAlertTopicARN  = "arn:aws:sns:us-west-2:123456789012:eval-alerts"
+	testAlertRuleARN   = "arn:aws:events:us-west-2:123456789012:rule/mypack_eval_alert"
+)
+
+func validConfigWithEvalAlert(t *testing.T) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"eval_alert":{"score_below":0.5,"target_arn":%q}}`,
+		testBinaryPath(t), testAlertLambdaARN)
+}
+
+func TestValidateEvalAlert(t *testing.T) {
+	role := "arn:aws:iam::123456789012:role/events-to-endpoint"
+	conn := "arn:aws:events:us-west-2:123456789012:connection/pager/abc"
+	tests := []struct {
+		name  string
+		alert EvalAlertConfig
+		want  string
+	}{
+		{"lambda", EvalAlertConfig{TargetARN: testAlertLambdaARN}, ""},
+		{"sns", EvalAlertConfig{TargetARN: testAlertTopicARN}, ""},
+		{"https", EvalAlertConfig{Endpoint: "https://hooks.example.com/alert", ConnectionARN: conn, RoleARN: role}, ""},
+		{"firehose", EvalAlertConfig{TargetARN: "arn:aws:firehose:us-west-2:123456789012:deliverystream/alerts"},
+			"must be an SNS topic or Lambda function ARN"},
+		{"no target", EvalAlertConfig{ScoreBelow: 0.5}, "exactly one of target_arn and endpoint"},
+		{"both targets", EvalAlertConfig{TargetARN: testAlertLambdaARN, Endpoint: "https://hooks.example.com"},
+			"exactly one of target_arn and endpoint"},
+		{"http endpoint", EvalAlertConfig{Endpoint: "http://hooks.example.com", ConnectionARN: conn, RoleARN: role},
+			"must be an https URL"},
+		{"endpoint without connection", EvalAlertConfig{Endpoint: "https://hooks.example.com", RoleARN: role},
+			"must be an EventBridge connection ARN"},
+		{"endpoint with bad role", EvalAlertConfig{
+			Endpoint: "https://hooks.example.com", ConnectionARN: conn, RoleARN: "role"},
+			"not a valid IAM role ARN"},
+		{"lambda with role", EvalAlertConfig{TargetARN: testAlertLambdaARN, RoleARN: role}, "only used with endpoint"},
+		{"other partition", EvalAlertConfig{TargetARN: "arn:aws-cn:lambda:cn-north-1:123456789012:function:f"},
+			`eval_alert.target_arn is in partition "aws-cn"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := strings.Join(validateEvalAlert(&tt.alert, "us-west-2"), "; ")
+			if tt.want == "" && errs != "" {
+				t.Errorf("validateEvalAlert = %s, want no errors", errs)
+			}
+			if tt.want != "" && !strings.Contains(errs, tt.want) {
+				t.Errorf("validateEvalAlert = %s, want one containing %q", errs, tt.want)
+			}
+		})
+	}
+	if errs := validateEvalAlert(nil, "us-west-2"); errs != nil {
+		t.Errorf("nil eval_alert: errs = %v", errs)
+	}
+}
+
+func TestEvalAlertFilterPattern(t *testing.T) {
+	if got, want := evalAlertFilterPattern(0.25), "{ $.attributes.gen_ai.evaluation.score.value < 0.25 }"; got != want {
+		t.Errorf("evalAlertFilterPattern = %q, want %q", got, want)
+	}
+}
+
+func TestLogGroupNameFromARN(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:logs:us-west-2:123456789012:log-group:/aws/evals/x":   "/aws/evals/x",
+		"arn:aws:logs:us-west-2:123456789012:log-group:/aws/evals/x:*": "/aws/evals/x",
+		"arn:aws:lambda:us-west-2:123456789012:function:f":             "",
+		"not-an-arn": "",
+	}
+	for arn, want := range tests {
+		if got := logGroupNameFromARN(arn); got != want {
+			t.Errorf("logGroupNameFromARN(%q) = %q, want %q", arn, got, want)
+		}
+	}
+}
+
+func TestPlan_EvalAlert(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		pack      string
+		wantAlert bool
+	}{
+		{"with online evals", multiAgentPackWithEvals(), true},
+		{"without evals", singleAgentPack(), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+				PackJSON: tt.pack, DeployConfig: validConfigWithEvalAlert(t), ArenaConfig: validArenaConfigJSON,
+			})
+			if err != nil {
+				t.Fatalf("Plan: %v", err)
+			}
+			if got := hasChangeOfType(resp.Changes, ResTypeEvalAlert); got != tt.wantAlert {
+				t.Errorf("eval_alert planned = %v, want %v: %+v", got, tt.wantAlert, resp.Changes)
+			}
+			if warned := strings.Contains(resp.Summary, "no alert is created"); warned == tt.wantAlert {
+				t.Errorf("summary = %q, want a warning only without an alert", resp.Summary)
+			}
+		})
+	}
+}
+
+func TestApply_EvalAlertState(t *testing.T) {
+	_, raw, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON: multiAgentPackWithEvals(), DeployConfig: validConfigWithEvalAlert(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	alert, ok := findResourceOfType(&state, ResTypeEvalAlert)
+	if !ok {
+		t.Fatalf("no eval_alert in state: %+v", state.Resources)
+	}
+	if alert.Name != "evalpack_eval_alert" || alert.Status != ResStatusCreated ||
+		!strings.HasPrefix(alert.Metadata[metaLogGroupName], evalResultsLogGroupPrefix) ||
+		alert.Metadata[metaEvalAlertFilter] != "evalpack_eval_alert" ||
+		alert.Metadata[metaEvalAlertTarget] != testAlertLambdaARN {
+		t.Errorf("eval alert = %+v", alert)
+	}
+	if a, ok := parseARN(alert.ARN); !ok || a.Service != "events" || a.Resource != "rule/evalpack_eval_alert" {
+		t.Errorf("ARN = %q, want the EventBridge rule", alert.ARN)
+	}
+}
+
+// alertHTTP answers the requests of the services an eval alert spans,
+// recording each as "service action body". respond overrides the answer
+// to an action; other actions get an empty success.
+type alertHTTP struct {
+	requests []string
+	respond  map[string]stubResponse
+}
+
+type stubResponse struct {
+	status int
+	body   string
+}
+
+func (h *alertHTTP) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	service, _, _ := strings.Cut(req.URL.Host, ".")
+	action := req.Method + " " + req.URL.Path
+	if target := req.Header.Get("X-Amz-Target"); target != "" {
+		action = target[strings.LastIndex(target, ".")+1:]
+	} else if form, err := url.ParseQuery(string(body)); err == nil && form.Get("Action") != "" {
+		action = form.Get("Action")
+	}
+	h.requests = append(h.requests, service+" "+action+" "+string(body))
+	resp := stubResponse{status: http.StatusOK, body: "{}"}
+	if r, ok := h.respond[action]; ok {
+		resp = r
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(resp.body)),
+		Request:    req,
+	}, nil
+}
+
+// actions returns the service and action of each recorded request.
+func (h *alertHTTP) actions() []string {
+	out := make([]string, len(h.requests))
+	for i, r := range h.requests {
+		service, rest, _ := strings.Cut(r, " ")
+		action, _, _ := strings.Cut(rest, " ")
+		out[i] = service + " " + action
+	}
+	return out
+}
+
+// request returns the recorded request of action.
+func (h *alertHTTP) request(action string) string {
+	for _, r := range h.requests {
+		if _, rest, _ := strings.Cut(r, " "); strings.HasPrefix(rest, action+" ") {
+			return r
+		}
+	}
+	return ""
+}
+
+func newAlertRealClient(respond map[string]stubResponse) (*realAWSClient, *alertHTTP) {
+	h := &alertHTTP{respond: respond}
+	creds := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+	})
+	return &realAWSClient{
+		client: bedrockagentcorecontrol.New(bedrockagentcorecontrol.Options{
+			Region: "us-west-2", Credentials: aws.AnonymousCredentials{}, HTTPClient: h,
+		}),
+		logsClient: cloudwatchlogs.New(cloudwatchlogs.Options{
+			Region: "us-west-2", Credentials: creds, HTTPClient: h,
+		}),
+		lambdaClient: lambda.New(lambda.Options{Region: "us-west-2", Credentials: creds, HTTPClient: h}),
+		dataPlane:    newSigV4Client(aws.Config{Credentials: creds, HTTPClient: h}),
+		cfg:          &Config{Region: "us-west-2"},
+	}, h
+}
+
+func evalAlertTestConfig(alert *EvalAlertConfig) *Config {
+	return &Config{
+		Region:         "us-west-2",
+		RuntimeRoleARN: "arn:aws:iam::123456789012:role/test",
+		ResourceTags:   map[string]string{"team": "evals"},
+		EvalAlert:      alert,
+	}
+}
+
+var putRuleResponse = stubResponse{http.StatusOK, `{"RuleArn":"` + testAlertRuleARN + `"}`}
+
+func TestPutEvalAlert_LambdaTarget(t *testing.T) {
+	c, h := newAlertRealClient(map[string]stubResponse{"PutRule": putRuleResponse})
+	cfg := evalAlertTestConfig(&EvalAlertConfig{ScoreBelow: 0.5, TargetARN: testAlertLambdaARN})
+
+	d, err := c.PutEvalAlert(context.Background(), "mypack_eval_alert", testOnlineEvalARN, cfg)
+	if err != nil {
+		t.Fatalf("PutEvalAlert: %v", err)
+	}
+	if d.ruleARN != testAlertRuleARN || d.target != testAlertLambdaARN ||
+		!strings.HasPrefix(d.logGroup, evalResultsLogGroupPrefix) {
+		t.Errorf("deployment = %+v", d)
+	}
+	want := []string{
+		"bedrock-agentcore-control GET",
+		"logs CreateLogGroup",
+		"logs DeleteSubscriptionFilter",
+		"logs PutMetricFilter",
+		"monitoring PutMetricAlarm",
+		"events PutRule",
+		"lambda POST",
+		"events PutTargets",
+	}
+	if got := h.actions(); !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	alarmARN := "arn:aws:cloudwatch:us-west-2:123456789012:alarm:mypack_eval_alert"
+	for action, wants := range map[string][]string{
+		"PutMetricFilter": {"score.value < 0.5", `"metricNamespace":"` + evalAlertNamespace + `"`},
+		"PutMetricAlarm":  {"AlarmName=mypack_eval_alert", "Tags.member.1.Key=team"},
+		"PutRule":         {alarmARN, `"State":"ENABLED"`, `"Key":"team"`},
+		"PutTargets":      {`"Arn":"` + testAlertLambdaARN + `"`, `"Id":"` + evalAlertTargetID + `"`},
+	} {
+		for _, w := range wants {
+			if r := h.request(action); !strings.Contains(r, w) {
+				t.Errorf("%s request = %s, want %s", action, r, w)
+			}
+		}
+	}
+	if r := h.requests[6]; !strings.Contains(r, evalAlertPrincipal) || !strings.Contains(r, testAlertRuleARN) {
+		t.Errorf("AddPermission request = %s, want events principal and the rule as source", r)
+	}
+}
+
+func TestPutEvalAlert_HTTPSEndpoint(t *testing.T) {
+	destARN := "arn:aws:events:us-west-2:123456789012:api-destination/mypack_eval_alert/1"
+	c, h := newAlertRealClient(map[string]stubResponse{
+		"PutRule":              putRuleResponse,
+		"CreateApiDestination": {http.StatusBadRequest, `{"__type":"ResourceAlreadyExistsException"}`},
+		"UpdateApiDestination": {http.StatusOK, `{"ApiDestinationArn":"` + destARN + `"}`},
+	})
+	role := "arn:aws:iam::123456789012:role/events-to-endpoint"
+	cfg := evalAlertTestConfig(&EvalAlertConfig{
+		ScoreBelow: 0.5, Endpoint: "https://hooks.example.com/alert",
+		ConnectionARN: "arn:aws:events:us-west-2:123456789012:connection/pager/abc", RoleARN: role,
+	})
+
+	d, err := c.PutEvalAlert(context.Background(), "mypack_eval_alert", testOnlineEvalARN, cfg)
+	if err != nil {
+		t.Fatalf("PutEvalAlert: %v", err)
+	}
+	if d.target != destARN {
+		t.Errorf("target = %q, want the API destination %q", d.target, destARN)
+	}
+	if r := h.request("UpdateApiDestination"); !strings.Contains(r, "https://hooks.example.com/alert") {
+		t.Errorf("UpdateApiDestination request = %s", r)
+	}
+	if r := h.request("PutTargets"); !strings.Contains(r, destARN) || !strings.Contains(r, role) {
+		t.Errorf("PutTargets request = %s, want the destination invoked with the role", r)
+	}
+	if slices.ContainsFunc(h.actions(), func(a string) bool { return strings.HasPrefix(a, "lambda ") }) {
+		t.Errorf("actions = %v, want no Lambda permission", h.actions())
+	}
+}
+
+func TestPutEvalAlert_FailedTarget(t *testing.T) {
+	c, _ := newAlertRealClient(map[string]stubResponse{
+		"PutRule": putRuleResponse,
+		"PutTargets": {http.StatusOK,
+			`{"FailedEntryCount":1,"FailedEntries":[{"ErrorCode":"AccessDenied","ErrorMessage":"no"}]}`},
+	})
+	cfg := evalAlertTestConfig(&EvalAlertConfig{ScoreBelow: 0.5, TargetARN: testAlertTopicARN})
+	_, err := c.PutEvalAlert(context.Background(), "mypack_eval_alert", testOnlineEvalARN, cfg)
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("err = %v, want the failed entry", err)
+	}
+}
+
+func TestDeleteEvalAlert(t *testing.T) {
+	notFound := stubResponse{http.StatusBadRequest, `{"__type":"ResourceNotFoundException"}`}
+	c, h := newAlertRealClient(map[string]stubResponse{"RemoveTargets": notFound})
+	res := ResourceState{Type: ResTypeEvalAlert, Name: "mypack_eval_alert", ARN: testAlertRuleARN,
+		Metadata: map[string]string{
+			metaLogGroupName:    evalResultsLogGroupPrefix + "oec-1",
+			metaEvalAlertFilter: "mypack_eval_alert",
+			metaEvalAlertTarget: testAlertLambdaARN,
+		}}
+
+	if err := c.deleteEvalAlert(context.Background(), res); err != nil {
+		t.Fatalf("deleteEvalAlert: %v", err)
+	}
+	want := []string{
+		"events RemoveTargets",
+		"events DeleteRule",
+		"monitoring DeleteAlarms",
+		"logs DeleteMetricFilter",
+		"logs DeleteSubscriptionFilter",
+		"lambda DELETE",
+	}
+	if got := h.actions(); !slices.Equal(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestCheckEvalAlert(t *testing.T) {
+	res := ResourceState{Type: ResTypeEvalAlert, Name: "mypack_eval_alert", ARN: testAlertRuleARN,
+		Metadata: map[string]string{metaEvalAlertFilter: "mypack_eval_alert", metaEvalAlertTarget: testAlertTopicARN}}
+	enabled := stubResponse{http.StatusOK, `{"State":"ENABLED"}`}
+	targets := func(arn string) stubResponse {
+		return stubResponse{http.StatusOK, `{"Targets":[{"Id":"eval-alert","Arn":"` + arn + `"}]}`}
+	}
+	for _, tt := range []struct {
+		name    string
+		respond map[string]stubResponse
+		want    string
+	}{
+		{"healthy", map[string]stubResponse{"DescribeRule": enabled, "ListTargetsByRule": targets(testAlertTopicARN)},
+			StatusHealthy},
+		{"disabled", map[string]stubResponse{"DescribeRule": {http.StatusOK, `{"State":"DISABLED"}`}},
+			StatusUnhealthy},
+		{"retargeted", map[string]stubResponse{"DescribeRule": enabled, "ListTargetsByRule": targets(testAlertLambdaARN)},
+			StatusUnhealthy},
+		{"missing", map[string]stubResponse{
+			"DescribeRule": {http.StatusBadRequest, `{"__type":"ResourceNotFoundException","Message":"gone"}`}},
+			StatusMissing},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newAlertRealClient(tt.respond)
+			got, err := c.checkEvalAlert(context.Background(), res)
+			if err != nil || got != tt.want {
+				t.Errorf("checkEvalAlert = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
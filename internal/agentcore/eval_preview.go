@@ -0,0 +1,226 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/evals"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	rttypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// MethodEvalPreview is the JSON-RPC method that runs an llm_as_judge eval
+// once against a sample transcript and returns an EvalPreviewResponse.
+const MethodEvalPreview = "eval_preview"
+
+// judgeMaxTokens caps the judge's reply, which only holds a rating and a
+// short rationale.
+const judgeMaxTokens = 1024
+
+// Transcript roles accepted by eval_preview.
+const (
+	transcriptRoleUser      = "user"
+	transcriptRoleAssistant = "assistant"
+)
+
+// EvalPreviewTurn is one message of a sample transcript.
+type EvalPreviewTurn struct {
+	Role    string `json:"role"` // "user" or "assistant"
+	Content string `json:"content"`
+}
+
+// EvalPreviewRequest is the params object of an eval_preview call. The
+// deploy config supplies the region and any evaluator inference profile.
+type EvalPreviewRequest struct {
+	DeployConfig string            `json:"deploy_config"`
+	Eval         evals.EvalDef     `json:"eval"`
+	Transcript   []EvalPreviewTurn `json:"transcript"`
+}
+
+// EvalPreviewResponse is the result of an eval_preview call.
+type EvalPreviewResponse struct {
+	Rating         float64 `json:"rating"`
+	Rationale      string  `json:"rationale"`
+	ScaleSize      int     `json:"scale_size"`
+	ModelID        string  `json:"model_id"`
+	Instructions   string  `json:"instructions"`
+	RawModelOutput string  `json:"raw_model_output"`
+}
+
+// judgeModel sends a single prompt to a Bedrock model and returns its text
+// reply.
+type judgeModel interface {
+	Judge(ctx context.Context, modelID, prompt string) (string, error)
+}
+
+// judgeModelFactory creates a judgeModel for the given config.
+type judgeModelFactory func(ctx context.Context, cfg *Config) (judgeModel, error)
+
+// newRealJudgeModelFactory is the judgeModelFactory used by NewProvider.
+func newRealJudgeModelFactory(ctx context.Context, cfg *Config) (judgeModel, error) {
+	awsCfg, err := loadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := bedrockruntime.NewFromConfig(awsCfg, func(o *bedrockruntime.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointBedrockRuntime)
+	})
+	return &bedrockJudgeModel{client: client}, nil
+}
+
+// bedrockJudgeModel implements judgeModel with the Bedrock Converse API.
+type bedrockJudgeModel struct {
+	client *bedrockruntime.Client
+}
+
+// Judge implements judgeModel.
+func (m *bedrockJudgeModel) Judge(ctx context.Context, modelID, prompt string) (string, error) {
+	out, err := m.client.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId: aws.String(modelID),
+		Messages: []rttypes.Message{{
+			Role:    rttypes.ConversationRoleUser,
+			Content: []rttypes.ContentBlock{&rttypes.ContentBlockMemberText{Value: prompt}},
+		}},
+		InferenceConfig: &rttypes.InferenceConfiguration{
+			MaxTokens:   aws.Int32(judgeMaxTokens),
+			Temperature: aws.Float32(0),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Converse: %w", err)
+	}
+	msg, ok := out.Output.(*rttypes.ConverseOutputMemberMessage)
+	if !ok {
+		return "", errors.New("no message in Converse output")
+	}
+	var text strings.Builder
+	for _, block := range msg.Value.Content {
+		if t, ok := block.(*rttypes.ContentBlockMemberText); ok {
+			text.WriteString(t.Value)
+		}
+	}
+	return text.String(), nil
+}
+
+// EvalPreview runs an llm_as_judge eval's instructions against a sample
+// transcript with the eval's judge model, outside the online eval
+// pipeline, so instructions can be iterated on before deploying.
+func (p *Provider) EvalPreview(ctx context.Context, req *EvalPreviewRequest) (*EvalPreviewResponse, error) {
+	cfg, err := p.loadConfig(req.DeployConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("agentcore: deploy config must set region")
+	}
+	if req.Eval.Type != evalTypeLLMAsJudge {
+		return nil, fmt.Errorf("agentcore: eval_preview supports %s evals, got type %q",
+			evalTypeLLMAsJudge, req.Eval.Type)
+	}
+	if err = validateTranscript(req.Transcript); err != nil {
+		return nil, fmt.Errorf("agentcore: invalid transcript: %w", err)
+	}
+	instructions, err := evalInstructions(req.Eval.Params)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: eval %q: %w", req.Eval.ID, err)
+	}
+	instructions = ensureEvalPlaceholders(instructions)
+
+	modelID := evalParamString(req.Eval.Params, "model", defaultEvalModel)
+	if prof, _ := cfg.InferenceProfiles.forEval("", req.Eval.ID); prof != nil {
+		modelID = prof.model()
+	}
+	size := ratingScaleSize(req.Eval.Params)
+
+	judge, err := p.judgeModelFunc(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to create judge model client: %w", err)
+	}
+	reply, err := judge.Judge(ctx, modelID, judgePrompt(instructions, req.Transcript, size))
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: invoke judge model %s: %w", modelID, err)
+	}
+	rating, rationale, err := parseJudgeReply(reply, size)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: judge model %s: %w", modelID, err)
+	}
+	return &EvalPreviewResponse{
+		Rating:         rating,
+		Rationale:      rationale,
+		ScaleSize:      size,
+		ModelID:        modelID,
+		Instructions:   instructions,
+		RawModelOutput: reply,
+	}, nil
+}
+
+// validateTranscript checks that a sample transcript has known roles and
+// at least one assistant turn to judge.
+func validateTranscript(turns []EvalPreviewTurn) error {
+	hasAssistant := false
+	for i, t := range turns {
+		switch t.Role {
+		case transcriptRoleAssistant:
+			hasAssistant = true
+		case transcriptRoleUser:
+		default:
+			return fmt.Errorf("turn %d: role %q must be %q or %q",
+				i, t.Role, transcriptRoleUser, transcriptRoleAssistant)
+		}
+	}
+	if !hasAssistant {
+		return errors.New("at least one assistant turn is required")
+	}
+	return nil
+}
+
+// judgePrompt fills the evaluator placeholders from the transcript the way
+// AgentCore does for a trace, then asks for a rating on the numerical
+// scale the deployed evaluator would use.
+func judgePrompt(instructions string, turns []EvalPreviewTurn, size int) string {
+	var transcript strings.Builder
+	var lastUser, lastAssistant string
+	for _, t := range turns {
+		fmt.Fprintf(&transcript, "%s: %s\n", t.Role, t.Content)
+		if t.Role == transcriptRoleUser {
+			lastUser = t.Content
+		} else {
+			lastAssistant = t.Content
+		}
+	}
+	prompt := strings.NewReplacer(
+		"{context}", strings.TrimSuffix(transcript.String(), "\n"),
+		"{user_input}", lastUser,
+		"{assistant_turn}", lastAssistant,
+	).Replace(instructions)
+	return fmt.Sprintf("%s\n\nRate the response on a scale from 1 (worst) to %d (best). "+
+		`Reply with only a JSON object of the form {"rating": <number>, "rationale": "<one or two sentences>"}.`,
+		prompt, size)
+}
+
+// parseJudgeReply extracts the rating and rationale from the judge's reply,
+// tolerating text around the JSON object.
+func parseJudgeReply(reply string, size int) (float64, string, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return 0, "", fmt.Errorf("reply has no JSON object: %q", reply)
+	}
+	var verdict struct {
+		Rating    *float64 `json:"rating"`
+		Rationale string   `json:"rationale"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &verdict); err != nil {
+		return 0, "", fmt.Errorf("reply is not valid JSON: %w", err)
+	}
+	if verdict.Rating == nil {
+		return 0, "", errors.New("reply has no rating")
+	}
+	if *verdict.Rating < 1 || *verdict.Rating > float64(size) {
+		return 0, "", fmt.Errorf("rating %g is outside the scale 1-%d", *verdict.Rating, size)
+	}
+	return *verdict.Rating, verdict.Rationale, nil
+}
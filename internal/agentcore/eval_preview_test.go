@@ -0,0 +1,120 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/evals"
+)
+
+// fakeJudge records the prompt it receives and returns a canned reply.
+type fakeJudge struct {
+	reply   string
+	err     error
+	modelID string
+	prompt  string
+}
+
+func (f *fakeJudge) Judge(_ context.Context, modelID, prompt string) (string, error) {
+	f.modelID, f.prompt = modelID, prompt
+	return f.reply, f.err
+}
+
+func judgeProvider(j *fakeJudge) *Provider {
+	p := newSimulatedProvider()
+	p.judgeModelFunc = func(context.Context, *Config) (judgeModel, error) { return j, nil }
+	return p
+}
+
+var sampleTranscript = []EvalPreviewTurn{
+	{Role: "user", Content: "Where is my order?"},
+	{Role: "assistant", Content: "It ships tomorrow."},
+}
+
+func TestEvalPreview(t *testing.T) {
+	judge := &fakeJudge{reply: "Sure.\n" + `{"rating": 4, "rationale": "Direct and accurate."}`}
+	resp, err := judgeProvider(judge).EvalPreview(context.Background(), &EvalPreviewRequest{
+		DeployConfig: `{"region":"us-west-2"}`,
+		Eval: evals.EvalDef{ID: "quality", Type: evalTypeLLMAsJudge, Params: map[string]any{
+			"instructions":      "Judge the reply to {user_input}: {assistant_turn}",
+			"rating_scale_size": 10.0,
+		}},
+		Transcript: sampleTranscript,
+	})
+	if err != nil {
+		t.Fatalf("EvalPreview: %v", err)
+	}
+	if resp.Rating != 4 || resp.Rationale != "Direct and accurate." || resp.ScaleSize != 10 {
+		t.Errorf("resp = %+v", resp)
+	}
+	if resp.ModelID != defaultEvalModel || judge.modelID != defaultEvalModel {
+		t.Errorf("model = %q, judged with %q, want %q", resp.ModelID, judge.modelID, defaultEvalModel)
+	}
+	if !strings.Contains(judge.prompt, "Judge the reply to Where is my order?: It ships tomorrow.") ||
+		!strings.Contains(judge.prompt, "to 10 (best)") {
+		t.Errorf("prompt = %q", judge.prompt)
+	}
+}
+
+func TestEvalPreview_UsesEvaluatorInferenceProfile(t *testing.T) {
+	judge := &fakeJudge{reply: `{"rating": 5, "rationale": "ok"}`}
+	resp, err := judgeProvider(judge).EvalPreview(context.Background(), &EvalPreviewRequest{
+		DeployConfig: `{"region":"us-west-2","inference_profiles":{"evaluators":` +
+			`{"default":{"id":"us.anthropic.claude-sonnet-4-20250514-v1:0"}}}}`,
+		Eval:       evals.EvalDef{ID: "quality", Type: evalTypeLLMAsJudge},
+		Transcript: sampleTranscript,
+	})
+	if err != nil {
+		t.Fatalf("EvalPreview: %v", err)
+	}
+	if resp.ModelID != "us.anthropic.claude-sonnet-4-20250514-v1:0" {
+		t.Errorf("ModelID = %q, want the evaluator profile", resp.ModelID)
+	}
+	if !strings.Contains(resp.Instructions, "{context}") {
+		t.Errorf("Instructions = %q, want the default placeholders", resp.Instructions)
+	}
+}
+
+func TestEvalPreview_Errors(t *testing.T) {
+	for _, name := range regionEnvVars {
+		t.Setenv(name, "")
+	}
+	judgeEval := evals.EvalDef{ID: "quality", Type: evalTypeLLMAsJudge}
+	tests := []struct {
+		name    string
+		req     *EvalPreviewRequest
+		judge   *fakeJudge
+		wantErr string
+	}{
+		{"no region", &EvalPreviewRequest{DeployConfig: `{}`, Eval: judgeEval, Transcript: sampleTranscript},
+			&fakeJudge{}, "must set region"},
+		{"not a judge eval", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`,
+			Eval: evals.EvalDef{ID: "len", Type: "max_length"}, Transcript: sampleTranscript},
+			&fakeJudge{}, `got type "max_length"`},
+		{"no assistant turn", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Eval: judgeEval,
+			Transcript: sampleTranscript[:1]}, &fakeJudge{}, "at least one assistant turn"},
+		{"unknown role", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Eval: judgeEval,
+			Transcript: []EvalPreviewTurn{{Role: "system", Content: "x"}}}, &fakeJudge{}, `role "system"`},
+		{"bad template", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Transcript: sampleTranscript,
+			Eval: evals.EvalDef{ID: "q", Type: evalTypeLLMAsJudge, Params: map[string]any{"template": "brevity"}}},
+			&fakeJudge{}, `unknown template "brevity"`},
+		{"model error", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Eval: judgeEval,
+			Transcript: sampleTranscript}, &fakeJudge{err: errors.New("AccessDenied")}, "AccessDenied"},
+		{"rating out of scale", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Eval: judgeEval,
+			Transcript: sampleTranscript}, &fakeJudge{reply: `{"rating": 9}`}, "outside the scale 1-5"},
+		{"no JSON", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Eval: judgeEval,
+			Transcript: sampleTranscript}, &fakeJudge{reply: "Looks good"}, "no JSON object"},
+		{"no rating", &EvalPreviewRequest{DeployConfig: `{"region":"us-west-2"}`, Eval: judgeEval,
+			Transcript: sampleTranscript}, &fakeJudge{reply: `{"rationale": "fine"}`}, "no rating"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := judgeProvider(tt.judge).EvalPreview(context.Background(), tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// MethodEvalResults is the JSON-RPC method that returns an
+// EvalResultsResponse. It extends the standard adaptersdk method set.
+const MethodEvalResults = "eval_results"
+
+// defaultEvalResultsWindow is the query window when the request sets none.
+const defaultEvalResultsWindow = 24 * time.Hour
+
+// evalResultsLogGroupPrefix is where AgentCore writes online evaluation
+// results when the config does not name an output log group.
+const evalResultsLogGroupPrefix = "/aws/bedrock-agentcore/evaluations/results/"
+
+// evalResultsQuery aggregates evaluation result events by evaluator and by
+// the agent runtime that produced the evaluated trace. Runtime service names
+// follow the "<runtime>.DEFAULT" convention, so the suffix is stripped.
+const evalResultsQuery = "fields `attributes.gen_ai.evaluation.name` as evaluator, " +
+	"`attributes.gen_ai.evaluation.score.value` as score, " +
+	"replace(`resource.attributes.service.name`, \".DEFAULT\", \"\") as agent" +
+	" | filter ispresent(score)" +
+	" | stats avg(score) as avg_score, count(*) as samples by evaluator, agent"
+
+// Column names produced by evalResultsQuery.
+const (
+	evalColEvaluator = "evaluator"
+	evalColAgent     = "agent"
+	evalColAvgScore  = "avg_score"
+	evalColSamples   = "samples"
+)
+
+// queryPollInterval is the delay between GetQueryResults calls. Logs
+// Insights queries usually finish in seconds, well under defaultPollInterval.
+const queryPollInterval = time.Second
+
+// EvalResultsRequest is the params object of an eval_results call. The
+// window ends at End (default now) and starts at Start, or Window before End
+// when Start is empty.
+type EvalResultsRequest struct {
+	DeployConfig string `json:"deploy_config"`
+	PriorState   string `json:"prior_state"`
+	Start        string `json:"start,omitempty"`  // RFC 3339
+	End          string `json:"end,omitempty"`    // RFC 3339
+	Window       string `json:"window,omitempty"` // Go duration, default 24h
+}
+
+// EvalScore is the average score of one evaluator for one agent.
+type EvalScore struct {
+	Evaluator string  `json:"evaluator"`
+	Agent     string  `json:"agent"`
+	AvgScore  float64 `json:"avg_score"`
+	Samples   int     `json:"samples"`
+}
+
+// EvalScoreSummary aggregates scores for one evaluator or one agent. The
+// previous fields compare against the window of equal length immediately
+// before, and are omitted when that window has no samples.
+type EvalScoreSummary struct {
+	Name             string   `json:"name"`
+	AvgScore         float64  `json:"avg_score"`
+	Samples          int      `json:"samples"`
+	PreviousAvgScore *float64 `json:"previous_avg_score,omitempty"`
+	Delta            *float64 `json:"delta,omitempty"`
+}
+
+// EvalResultsResponse is the result of an eval_results call.
+type EvalResultsResponse struct {
+	Start      time.Time          `json:"start"`
+	End        time.Time          `json:"end"`
+	Evaluators []EvalScoreSummary `json:"evaluators"`
+	Agents     []EvalScoreSummary `json:"agents"`
+	Scores     []EvalScore        `json:"scores"`
+}
+
+// evalResultsQuerier reads aggregated online evaluation scores.
+type evalResultsQuerier interface {
+	// QueryEvalScores returns average scores per evaluator and agent for
+	// results written by the online eval config res within [start, end).
+	QueryEvalScores(ctx context.Context, res ResourceState, start, end time.Time) ([]EvalScore, error)
+}
+
+// evalResultsFactory creates an evalResultsQuerier for the given config.
+type evalResultsFactory func(ctx context.Context, cfg *Config) (evalResultsQuerier, error)
+
+// newRealEvalResultsFactory is the evalResultsFactory used by NewProvider.
+func newRealEvalResultsFactory(ctx context.Context, cfg *Config) (evalResultsQuerier, error) {
+	return newRealAWSClient(ctx, cfg)
+}
+
+// EvalResults reports online evaluation scores for a deployment over a time
+// window, averaged per evaluator and per agent, alongside the preceding
+// window so callers can spot regressions.
+func (p *Provider) EvalResults(ctx context.Context, req *EvalResultsRequest) (*EvalResultsResponse, error) {
+	state, err := parseAdapterState(req.PriorState)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse prior state: %w", err)
+	}
+	res, ok := findResourceOfType(state, ResTypeOnlineEvalConfig)
+	if !ok {
+		return nil, fmt.Errorf("agentcore: deployment has no %s; deploy a pack with LLM evals first",
+			ResTypeOnlineEvalConfig)
+	}
+
+	cfg, err := p.loadConfig(req.DeployConfig, state.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
+	}
+	start, end, err := resolveEvalWindow(req, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+
+	querier, err := p.evalResultsFunc(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to create eval results client: %w", err)
+	}
+	current, err := querier.QueryEvalScores(ctx, res, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: query eval results: %w", err)
+	}
+	previous, err := querier.QueryEvalScores(ctx, res, start.Add(-end.Sub(start)), start)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: query previous eval results: %w", err)
+	}
+
+	sortEvalScores(current)
+	return &EvalResultsResponse{
+		Start:      start,
+		End:        end,
+		Evaluators: summarizeScores(current, previous, func(s EvalScore) string { return s.Evaluator }),
+		Agents:     summarizeScores(current, previous, func(s EvalScore) string { return s.Agent }),
+		Scores:     current,
+	}, nil
+}
+
+// findResourceOfType returns the first resource of the given type in state.
+func findResourceOfType(state *AdapterState, resType string) (ResourceState, bool) {
+	for _, r := range state.Resources {
+		if r.Type == resType {
+			return r, true
+		}
+	}
+	return ResourceState{}, false
+}
+
+// resolveEvalWindow computes the [start, end) window from the request.
+func resolveEvalWindow(req *EvalResultsRequest, now time.Time) (start, end time.Time, err error) {
+	end = now.UTC()
+	if req.End != "" {
+		if end, err = time.Parse(time.RFC3339, req.End); err != nil {
+			return start, end, fmt.Errorf("invalid end %q: %w", req.End, err)
+		}
+	}
+
+	window := defaultEvalResultsWindow
+	if req.Window != "" {
+		if window, err = time.ParseDuration(req.Window); err != nil || window <= 0 {
+			return start, end, fmt.Errorf("invalid window %q: must be a positive duration", req.Window)
+		}
+	}
+	start = end.Add(-window)
+	if req.Start != "" {
+		if start, err = time.Parse(time.RFC3339, req.Start); err != nil {
+			return start, end, fmt.Errorf("invalid start %q: %w", req.Start, err)
+		}
+	}
+
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("start %s must be before end %s",
+			start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
+
+// scoreTotals accumulates a sample-weighted average.
+type scoreTotals struct {
+	sum     float64
+	samples int
+}
+
+// add folds in a score averaged over samples.
+func (t *scoreTotals) add(avg float64, samples int) {
+	t.sum += avg * float64(samples)
+	t.samples += samples
+}
+
+// avg returns the weighted average, or 0 when there are no samples.
+func (t *scoreTotals) avg() float64 {
+	if t.samples == 0 {
+		return 0
+	}
+	return t.sum / float64(t.samples)
+}
+
+// totalsBy groups scores by key into weighted totals.
+func totalsBy(scores []EvalScore, key func(EvalScore) string) map[string]*scoreTotals {
+	totals := make(map[string]*scoreTotals)
+	for _, s := range scores {
+		t, ok := totals[key(s)]
+		if !ok {
+			t = &scoreTotals{}
+			totals[key(s)] = t
+		}
+		t.add(s.AvgScore, s.Samples)
+	}
+	return totals
+}
+
+// summarizeScores aggregates current scores by key and compares each group
+// with the same group in the previous window. Results are sorted by name.
+func summarizeScores(current, previous []EvalScore, key func(EvalScore) string) []EvalScoreSummary {
+	cur := totalsBy(current, key)
+	prev := totalsBy(previous, key)
+
+	summaries := make([]EvalScoreSummary, 0, len(cur))
+	for name, t := range cur {
+		s := EvalScoreSummary{Name: name, AvgScore: t.avg(), Samples: t.samples}
+		if p, ok := prev[name]; ok && p.samples > 0 {
+			prevAvg := p.avg()
+			delta := s.AvgScore - prevAvg
+			s.PreviousAvgScore = &prevAvg
+			s.Delta = &delta
+		}
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// sortEvalScores orders scores by evaluator, then agent.
+func sortEvalScores(scores []EvalScore) {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Evaluator != scores[j].Evaluator {
+			return scores[i].Evaluator < scores[j].Evaluator
+		}
+		return scores[i].Agent < scores[j].Agent
+	})
+}
+
+// ---------- evalResultsQuerier implementation ----------
+
+// QueryEvalScores runs evalResultsQuery over the online eval config's
+// results log group with CloudWatch Logs Insights.
+func (c *realAWSClient) QueryEvalScores(
+	ctx context.Context, res ResourceState, start, end time.Time,
+) ([]EvalScore, error) {
+	logGroup, err := c.evalResultsLogGroup(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.logsClient.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+		LogGroupName: aws.String(logGroup),
+		QueryString:  aws.String(evalResultsQuery),
+		StartTime:    aws.Int64(start.Unix()),
+		EndTime:      aws.Int64(end.Unix()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("StartQuery on %q: %w", logGroup, err)
+	}
+	rows, err := c.waitForQueryResults(ctx, aws.ToString(out.QueryId))
+	if err != nil {
+		return nil, err
+	}
+	return parseEvalScoreRows(rows), nil
+}
+
+// evalResultsLogGroup returns the log group the online eval config writes
+// its results to.
+func (c *realAWSClient) evalResultsLogGroup(ctx context.Context, res ResourceState) (string, error) {
+	id := extractResourceID(res.ARN, "online-evaluation-config")
+	if id == "" {
+		id = res.Name
+	}
+	out, err := c.client.GetOnlineEvaluationConfig(ctx,
+		&bedrockagentcorecontrol.GetOnlineEvaluationConfigInput{
+			OnlineEvaluationConfigId: aws.String(id),
+		})
+	if err != nil {
+		return "", fmt.Errorf("GetOnlineEvaluationConfig %q: %w", res.Name, err)
+	}
+	if out.OutputConfig != nil && out.OutputConfig.CloudWatchConfig != nil {
+		if lg := aws.ToString(out.OutputConfig.CloudWatchConfig.LogGroupName); lg != "" {
+			return lg, nil
+		}
+	}
+	return evalResultsLogGroupPrefix + id, nil
+}
+
+// waitForQueryResults polls GetQueryResults until the query completes.
+func (c *realAWSClient) waitForQueryResults(
+	ctx context.Context, queryID string,
+) ([][]logstypes.ResultField, error) {
+	for range defaultMaxPollAttempts {
+		out, err := c.logsClient.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: aws.String(queryID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("GetQueryResults %q: %w", queryID, err)
+		}
+		switch out.Status {
+		case logstypes.QueryStatusComplete:
+			return out.Results, nil
+		case logstypes.QueryStatusFailed, logstypes.QueryStatusCancelled, logstypes.QueryStatusTimeout:
+			return nil, fmt.Errorf("logs query %q ended with status %s", queryID, out.Status)
+		case logstypes.QueryStatusScheduled, logstypes.QueryStatusRunning, logstypes.QueryStatusUnknown:
+			// Transitional — keep polling.
+		}
+		c.poll.sleepFor(queryPollInterval)
+	}
+	return nil, fmt.Errorf("logs query %q did not complete after %d attempts", queryID, defaultMaxPollAttempts)
+}
+
+// parseEvalScoreRows converts Logs Insights rows into EvalScores. Rows
+// without an evaluator name or a numeric score are skipped.
+func parseEvalScoreRows(rows [][]logstypes.ResultField) []EvalScore {
+	var scores []EvalScore
+	for _, row := range rows {
+		fields := make(map[string]string, len(row))
+		for _, f := range row {
+			fields[aws.ToString(f.Field)] = aws.ToString(f.Value)
+		}
+		avg, err := strconv.ParseFloat(fields[evalColAvgScore], 64)
+		if err != nil || fields[evalColEvaluator] == "" {
+			continue
+		}
+		samples, _ := strconv.Atoi(fields[evalColSamples])
+		scores = append(scores, EvalScore{
+			Evaluator: fields[evalColEvaluator],
+			Agent:     strings.TrimSpace(fields[evalColAgent]),
+			AvgScore:  avg,
+			Samples:   samples,
+		})
+	}
+	return scores
+}
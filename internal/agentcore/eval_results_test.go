@@ -0,0 +1,189 @@
+package agentcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// windowedEvalResults returns different scores for the current and the
+// previous window, keyed by window end.
+type windowedEvalResults struct {
+	current, previous []EvalScore
+	currentEnd        time.Time
+	calls             int
+}
+
+func (w *windowedEvalResults) QueryEvalScores(
+	_ context.Context, _ ResourceState, _, end time.Time,
+) ([]EvalScore, error) {
+	w.calls++
+	if end.Equal(w.currentEnd) {
+		return w.current, nil
+	}
+	return w.previous, nil
+}
+
+func evalResultsState(t *testing.T) string {
+	t.Helper()
+	return mustJSON(t, &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "chat"},
+		{Type: ResTypeOnlineEvalConfig, Name: "mypack_online_eval",
+			ARN: "arn:aws:bedrock-agentcore:us-west-2:123456789012:online-evaluation-config/cfg-1"},
+	}})
+}
+
+func TestEvalResults_AggregatesAndCompares(t *testing.T) {
+	end := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	q := &windowedEvalResults{
+		currentEnd: end,
+		current: []EvalScore{
+			{Evaluator: "helpfulness", Agent: "writer", AvgScore: 4, Samples: 3},
+			{Evaluator: "helpfulness", Agent: "editor", AvgScore: 2, Samples: 1},
+			{Evaluator: "toxicity", Agent: "writer", AvgScore: 1, Samples: 2},
+		},
+		previous: []EvalScore{
+			{Evaluator: "helpfulness", Agent: "writer", AvgScore: 4, Samples: 4},
+		},
+	}
+	p := &Provider{evalResultsFunc: func(_ context.Context, _ *Config) (evalResultsQuerier, error) {
+		return q, nil
+	}}
+
+	resp, err := p.EvalResults(context.Background(), &EvalResultsRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   evalResultsState(t),
+		End:          end.Format(time.RFC3339),
+		Window:       "1h",
+	})
+	if err != nil {
+		t.Fatalf("EvalResults: %v", err)
+	}
+	if q.calls != 2 {
+		t.Errorf("querier called %d times, want 2", q.calls)
+	}
+	if !resp.Start.Equal(end.Add(-time.Hour)) || !resp.End.Equal(end) {
+		t.Errorf("window = %s..%s", resp.Start, resp.End)
+	}
+
+	if len(resp.Evaluators) != 2 || resp.Evaluators[0].Name != "helpfulness" {
+		t.Fatalf("evaluators = %+v", resp.Evaluators)
+	}
+	help := resp.Evaluators[0]
+	if help.AvgScore != 3.5 || help.Samples != 4 {
+		t.Errorf("helpfulness avg/samples = %v/%d, want 3.5/4", help.AvgScore, help.Samples)
+	}
+	if help.Delta == nil || *help.Delta != -0.5 {
+		t.Errorf("helpfulness delta = %v, want -0.5", help.Delta)
+	}
+	if resp.Evaluators[1].Delta != nil {
+		t.Error("toxicity has no previous samples, delta should be omitted")
+	}
+
+	if len(resp.Agents) != 2 || resp.Agents[0].Name != "editor" || resp.Agents[1].Samples != 5 {
+		t.Errorf("agents = %+v", resp.Agents)
+	}
+	if len(resp.Scores) != 3 || resp.Scores[0].Agent != "editor" {
+		t.Errorf("scores not sorted by evaluator, agent: %+v", resp.Scores)
+	}
+}
+
+func TestEvalResults_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     EvalResultsRequest
+		wantErr string
+	}{
+		{
+			name:    "no online eval config",
+			req:     EvalResultsRequest{DeployConfig: validDestroyConfig(), PriorState: mustJSON(t, sampleState())},
+			wantErr: ResTypeOnlineEvalConfig,
+		},
+		{
+			name:    "bad window",
+			req:     EvalResultsRequest{DeployConfig: validDestroyConfig(), PriorState: evalResultsState(t), Window: "-1h"},
+			wantErr: "invalid window",
+		},
+		{
+			name: "start after end",
+			req: EvalResultsRequest{
+				DeployConfig: validDestroyConfig(), PriorState: evalResultsState(t),
+				Start: "2026-03-02T00:00:00Z", End: "2026-03-01T00:00:00Z",
+			},
+			wantErr: "must be before",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSimulatedProvider().EvalResults(context.Background(), &tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvalResults_QuerierError(t *testing.T) {
+	p := &Provider{evalResultsFunc: func(_ context.Context, _ *Config) (evalResultsQuerier, error) {
+		return nil, fmt.Errorf("no credentials")
+	}}
+	_, err := p.EvalResults(context.Background(), &EvalResultsRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   evalResultsState(t),
+	})
+	if err == nil || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestParseEvalScoreRows(t *testing.T) {
+	field := func(k, v string) logstypes.ResultField {
+		return logstypes.ResultField{Field: aws.String(k), Value: aws.String(v)}
+	}
+	rows := [][]logstypes.ResultField{
+		{field("evaluator", "helpfulness"), field("agent", "writer"), field("avg_score", "4.25"), field("samples", "8")},
+		{field("evaluator", "helpfulness"), field("agent", "writer"), field("avg_score", "n/a")},
+		{field("agent", "writer"), field("avg_score", "1")},
+	}
+	scores := parseEvalScoreRows(rows)
+	if len(scores) != 1 {
+		t.Fatalf("got %d scores, want 1: %+v", len(scores), scores)
+	}
+	want := EvalScore{Evaluator: "helpfulness", Agent: "writer", AvgScore: 4.25, Samples: 8}
+	if scores[0] != want {
+		t.Errorf("score = %+v, want %+v", scores[0], want)
+	}
+}
+
+func TestServeIO_EvalResults(t *testing.T) {
+	params := map[string]any{
+		"deploy_config": validDestroyConfig(),
+		"prior_state":   evalResultsState(t),
+	}
+	var out bytes.Buffer
+	input := jsonRPCRequest(MethodEvalResults, 9, params)
+	if err := ServeIO(newSimulatedProvider(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ServeIO error: %v", err)
+	}
+	var resp jsonRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response %q: %v", out.String(), err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	var results EvalResultsResponse
+	if err := json.Unmarshal(resp.Result, &results); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if results.End.Sub(results.Start) != defaultEvalResultsWindow {
+		t.Errorf("window = %s, want %s", results.End.Sub(results.Start), defaultEvalResultsWindow)
+	}
+}
@@ -0,0 +1,255 @@
+package agentcore
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/evals"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// turnsPerEstimate is the traffic Plan's judge estimate is given per when
+// eval_defaults.monthly_turns is unset.
+const turnsPerEstimate = 1000
+
+// Resource metadata keys recording eval sampling.
+const (
+	metaEvalTrigger          = "trigger"
+	metaEvalLevel            = "level"
+	metaSamplePercentage     = "sample_percentage"
+	metaSamplingPercentage   = "sampling_percentage"
+	metaEvalSamplingRequests = "eval_sampling"
+)
+
+// defaultJudgeSamplePercentage is the share of turns an every_turn
+// llm_as_judge eval without a sample_percentage is evaluated on. Judging
+// every turn invokes a model per turn, so it must be asked for explicitly.
+const defaultJudgeSamplePercentage = 10.0
+
+// EvalDefaultsConfig sets the sampling of evals that do not choose their
+// own, and the traffic Plan estimates judge costs from.
+type EvalDefaultsConfig struct {
+	// JudgeSamplePercentage is the percentage of turns an every_turn
+	// llm_as_judge eval without a sample_percentage is evaluated on.
+	// Defaults to 10; 100 evaluates every turn.
+	JudgeSamplePercentage float64 `json:"judge_sample_percentage,omitempty"`
+
+	// MonthlyTurns is the expected number of agent turns a month. When
+	// set, Plan estimates the judge invocations sampling leads to.
+	MonthlyTurns int64 `json:"monthly_turns,omitempty"`
+}
+
+// judgeSamplePercentage returns the configured default sampling for
+// every_turn llm_as_judge evals.
+func (c *Config) judgeSamplePercentage() float64 {
+	if c.EvalDefaults != nil && c.EvalDefaults.JudgeSamplePercentage > 0 {
+		return c.EvalDefaults.JudgeSamplePercentage
+	}
+	return defaultJudgeSamplePercentage
+}
+
+// validateEvalDefaults checks the eval_defaults block.
+func validateEvalDefaults(c *EvalDefaultsConfig) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	if c.JudgeSamplePercentage < 0 || c.JudgeSamplePercentage > defaultSamplingPercentage {
+		errs = append(errs, fmt.Sprintf("eval_defaults.judge_sample_percentage %s must be between 0 and 100",
+			formatPercentage(c.JudgeSamplePercentage)))
+	}
+	if c.MonthlyTurns < 0 {
+		errs = append(errs, fmt.Sprintf("eval_defaults.monthly_turns %d must not be negative", c.MonthlyTurns))
+	}
+	return errs
+}
+
+// evalSampling is the share of traffic one eval asks to be evaluated on.
+type evalSampling struct {
+	Name       string // evaluator resource name, or the built-in evaluator ID
+	Trigger    evals.EvalTrigger
+	Percentage float64
+	Judge      bool // an llm_as_judge eval
+	Defaulted  bool // sampled at the judge default rather than its own rate
+}
+
+// collectEvalSampling returns the sampling requested by each eval the
+// online eval config can reference, sorted by name.
+func collectEvalSampling(pack *prompt.Pack, cfg *Config) []evalSampling {
+	var out []evalSampling
+	for i := range pack.Evals {
+		def := &pack.Evals[i]
+		name := def.ID
+		switch def.Type {
+		case evalTypeLLMAsJudge:
+			if name == "" {
+				name = fmt.Sprintf("eval_%d", i)
+			}
+		case evalTypeBuiltin:
+			if name == "" {
+				continue
+			}
+		default:
+			continue
+		}
+		s := evalSampling{Name: name, Trigger: def.Trigger, Percentage: requestedSamplePercentage(def),
+			Judge: def.Type == evalTypeLLMAsJudge}
+		if s.Judge && def.Trigger == evals.TriggerEveryTurn && !hasSamplePercentageParam(def) {
+			s.Percentage, s.Defaulted = cfg.judgeSamplePercentage(), true
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// requestedSamplePercentage returns the percentage of traces or sessions an
+// eval asks for. Sampling triggers use the eval's sample_percentage, which
+// defaults to PromptKit's 5%; other triggers ask for every trace. The
+// older sample_percentage eval param still overrides both.
+func requestedSamplePercentage(def *evals.EvalDef) float64 {
+	if hasSamplePercentageParam(def) {
+		return def.Params["sample_percentage"].(float64)
+	}
+	switch def.Trigger {
+	case evals.TriggerSampleTurns, evals.TriggerSampleSessions:
+		return def.GetSamplePercentage()
+	}
+	return defaultSamplingPercentage
+}
+
+// hasSamplePercentageParam reports whether def sets the older
+// sample_percentage eval param.
+func hasSamplePercentageParam(def *evals.EvalDef) bool {
+	v, ok := def.Params["sample_percentage"].(float64)
+	return ok && v > 0
+}
+
+// referencedEvalSampling keeps the entries the online eval config will
+// reference: created evaluators and built-in evaluators.
+func referencedEvalSampling(all []evalSampling, evalARNs map[string]string, builtinIDs []string) []evalSampling {
+	builtin := make(map[string]bool, len(builtinIDs))
+	for _, id := range builtinIDs {
+		builtin[id] = true
+	}
+	var out []evalSampling
+	for _, s := range all {
+		if evalARNs[s.Name] != "" || builtin[s.Name] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// effectiveSamplingPercentage is the sampling rule for an online eval
+// config. AgentCore applies one rule to every evaluator in a config, so it
+// takes the highest requested rate; no eval is sampled below what it asked
+// for.
+func effectiveSamplingPercentage(sampling []evalSampling) float64 {
+	if len(sampling) == 0 {
+		return defaultSamplingPercentage
+	}
+	pct := 0.0
+	for _, s := range sampling {
+		pct = max(pct, s.Percentage)
+	}
+	return min(pct, defaultSamplingPercentage)
+}
+
+// evalSamplingWarning explains that evals asking for a lower rate are
+// sampled at the config's rate. It returns "" when every eval agrees.
+func evalSamplingWarning(sampling []evalSampling) string {
+	effective := effectiveSamplingPercentage(sampling)
+	var lower []string
+	for _, s := range sampling {
+		if s.Percentage < effective {
+			lower = append(lower, fmt.Sprintf("%s (%s%%)", s.Name, formatPercentage(s.Percentage)))
+		}
+	}
+	if len(lower) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("AgentCore samples every evaluator in an online eval config at one rate; "+
+		"%s will be sampled at %s%%", strings.Join(lower, ", "), formatPercentage(effective))
+}
+
+// judgeSamplingWarning names the every_turn llm_as_judge evals sampled at
+// the default rate and estimates the judge invocations the config's rate
+// leads to, a month when eval_defaults.monthly_turns is set and per
+// thousand turns otherwise. It returns "" when no eval is defaulted.
+func judgeSamplingWarning(sampling []evalSampling, cfg *Config) string {
+	var defaulted []string
+	judges := 0
+	for _, s := range sampling {
+		if s.Defaulted {
+			defaulted = append(defaulted, s.Name)
+		}
+		if s.Judge && mapTriggerToLevel(s.Trigger) == types.EvaluatorLevelTrace {
+			judges++
+		}
+	}
+	if len(defaulted) == 0 {
+		return ""
+	}
+	rate := effectiveSamplingPercentage(sampling) / defaultSamplingPercentage * float64(judges)
+	estimate := fmt.Sprintf("about %.0f judge invocations per %d turns; "+
+		"set eval_defaults.monthly_turns for a monthly estimate", rate*turnsPerEstimate, turnsPerEstimate)
+	if cfg.EvalDefaults != nil && cfg.EvalDefaults.MonthlyTurns > 0 {
+		estimate = fmt.Sprintf("about %.0f judge invocations a month at %d turns a month",
+			rate*float64(cfg.EvalDefaults.MonthlyTurns), cfg.EvalDefaults.MonthlyTurns)
+	}
+	return fmt.Sprintf("llm_as_judge evals %s set no sample_percentage and are sampled at %s%% of turns "+
+		"(eval_defaults.judge_sample_percentage); %s", strings.Join(defaulted, ", "),
+		formatPercentage(cfg.judgeSamplePercentage()), estimate)
+}
+
+// annotateEvalSampling records the configured sampling in the metadata of
+// the evaluator and online eval config resources.
+func annotateEvalSampling(resources []ResourceState, sampling []evalSampling) {
+	byName := make(map[string]evalSampling, len(sampling))
+	for _, s := range sampling {
+		byName[s.Name] = s
+	}
+	for i := range resources {
+		res := &resources[i]
+		switch res.Type {
+		case ResTypeEvaluator:
+			s, ok := byName[res.Name]
+			if !ok {
+				continue
+			}
+			setMetadata(res, metaEvalTrigger, string(s.Trigger))
+			setMetadata(res, metaEvalLevel, string(mapTriggerToLevel(s.Trigger)))
+			setMetadata(res, metaSamplePercentage, formatPercentage(s.Percentage))
+		case ResTypeOnlineEvalConfig:
+			if res.Status == ResStatusFailed {
+				continue
+			}
+			setMetadata(res, metaSamplingPercentage, formatPercentage(effectiveSamplingPercentage(sampling)))
+			setMetadata(res, metaEvalSamplingRequests, formatEvalSampling(sampling))
+		}
+	}
+}
+
+// formatEvalSampling renders sampling as "name=pct" pairs.
+func formatEvalSampling(sampling []evalSampling) string {
+	parts := make([]string, len(sampling))
+	for i, s := range sampling {
+		parts[i] = s.Name + "=" + formatPercentage(s.Percentage)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatPercentage(pct float64) string {
+	return strconv.FormatFloat(pct, 'f', -1, 64)
+}
+
+func setMetadata(res *ResourceState, key, value string) {
+	if res.Metadata == nil {
+		res.Metadata = make(map[string]string)
+	}
+	res.Metadata[key] = value
+}
@@ -0,0 +1,206 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/evals"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func samplingPack() *prompt.Pack {
+	return &prompt.Pack{ID: "mypack", Evals: []evals.EvalDef{
+		{ID: "tone", Type: evalTypeLLMAsJudge, Trigger: evals.TriggerSampleTurns, SamplePercentage: float64Ptr(10)},
+		{ID: "", Type: evalTypeLLMAsJudge, Trigger: evals.TriggerSampleSessions},
+		{ID: "Builtin.Helpfulness", Type: evalTypeBuiltin, Trigger: evals.TriggerSampleTurns,
+			SamplePercentage: float64Ptr(25)},
+		{ID: "legacy", Type: evalTypeLLMAsJudge, Trigger: evals.TriggerEveryTurn,
+			Params: map[string]any{"sample_percentage": 40.0}},
+		{ID: "latency", Type: "latency"},
+		{ID: "brevity", Type: evalTypeLLMAsJudge, Trigger: evals.TriggerEveryTurn},
+	}}
+}
+
+func TestCollectEvalSampling(t *testing.T) {
+	got := collectEvalSampling(samplingPack(), &Config{})
+	want := []evalSampling{
+		{Name: "Builtin.Helpfulness", Trigger: evals.TriggerSampleTurns, Percentage: 25},
+		{Name: "brevity", Trigger: evals.TriggerEveryTurn, Percentage: defaultJudgeSamplePercentage,
+			Judge: true, Defaulted: true},
+		{Name: "eval_1", Trigger: evals.TriggerSampleSessions, Percentage: evals.DefaultSamplePercentage, Judge: true},
+		{Name: "legacy", Trigger: evals.TriggerEveryTurn, Percentage: 40, Judge: true},
+		{Name: "tone", Trigger: evals.TriggerSampleTurns, Percentage: 10, Judge: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCollectEvalSampling_JudgeDefault(t *testing.T) {
+	cfg := &Config{EvalDefaults: &EvalDefaultsConfig{JudgeSamplePercentage: 100}}
+	for _, s := range collectEvalSampling(samplingPack(), cfg) {
+		if s.Name == "brevity" && (s.Percentage != 100 || !s.Defaulted) {
+			t.Errorf("brevity = %+v, want the configured 100%%", s)
+		}
+		if s.Name == "legacy" && s.Percentage != 40 {
+			t.Errorf("legacy = %+v, want its own 40%%", s)
+		}
+	}
+}
+
+func TestValidateEvalDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *EvalDefaultsConfig
+		want string
+	}{
+		{"nil", nil, ""},
+		{"valid", &EvalDefaultsConfig{JudgeSamplePercentage: 2.5, MonthlyTurns: 100000}, ""},
+		{"over 100", &EvalDefaultsConfig{JudgeSamplePercentage: 150}, "must be between 0 and 100"},
+		{"negative turns", &EvalDefaultsConfig{MonthlyTurns: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		errs := strings.Join(validateEvalDefaults(tt.cfg), "; ")
+		if (tt.want == "") != (errs == "") || !strings.Contains(errs, tt.want) {
+			t.Errorf("%s: errs = %q, want %q", tt.name, errs, tt.want)
+		}
+	}
+}
+
+func TestJudgeSamplingWarning(t *testing.T) {
+	sampling := []evalSampling{
+		{Name: "brevity", Trigger: evals.TriggerEveryTurn, Percentage: 10, Judge: true, Defaulted: true},
+		{Name: "tone", Trigger: evals.TriggerSampleTurns, Percentage: 10, Judge: true},
+		{Name: "summary", Trigger: evals.TriggerOnSessionComplete, Percentage: 10, Judge: true},
+	}
+	w := judgeSamplingWarning(sampling, &Config{})
+	if !strings.Contains(w, "llm_as_judge evals brevity set no sample_percentage and are sampled at 10% of turns") ||
+		!strings.Contains(w, "about 200 judge invocations per 1000 turns") {
+		t.Errorf("warning = %q", w)
+	}
+	w = judgeSamplingWarning(sampling, &Config{EvalDefaults: &EvalDefaultsConfig{MonthlyTurns: 50000}})
+	if !strings.Contains(w, "about 10000 judge invocations a month at 50000 turns a month") {
+		t.Errorf("warning = %q", w)
+	}
+	if w := judgeSamplingWarning(sampling[1:], &Config{}); w != "" {
+		t.Errorf("no defaulted evals warned: %q", w)
+	}
+}
+
+func TestEffectiveSamplingPercentage(t *testing.T) {
+	if got := effectiveSamplingPercentage(nil); got != defaultSamplingPercentage {
+		t.Errorf("no evals = %v, want %v", got, defaultSamplingPercentage)
+	}
+	sampled := []evalSampling{{Name: "a", Percentage: 10}, {Name: "b", Percentage: 25}}
+	if got := effectiveSamplingPercentage(sampled); got != 25 {
+		t.Errorf("sampled = %v, want the highest rate 25", got)
+	}
+	sampled = append(sampled, evalSampling{Name: "c", Percentage: 150})
+	if got := effectiveSamplingPercentage(sampled); got != defaultSamplingPercentage {
+		t.Errorf("over 100 = %v, want it capped", got)
+	}
+}
+
+func TestEvalSamplingWarning(t *testing.T) {
+	if w := evalSamplingWarning([]evalSampling{{Name: "a", Percentage: 10}, {Name: "b", Percentage: 10}}); w != "" {
+		t.Errorf("matching rates warned: %q", w)
+	}
+	w := evalSamplingWarning([]evalSampling{
+		{Name: "a", Percentage: 10}, {Name: "b", Percentage: 100}, {Name: "c", Percentage: 2.5},
+	})
+	if !strings.Contains(w, "a (10%), c (2.5%) will be sampled at 100%") {
+		t.Errorf("warning = %q", w)
+	}
+}
+
+func TestReferencedEvalSampling(t *testing.T) {
+	all := collectEvalSampling(samplingPack(), &Config{})
+	got := referencedEvalSampling(all, map[string]string{"tone": "arn:tone"}, []string{"Builtin.Helpfulness"})
+	if len(got) != 2 || got[0].Name != "Builtin.Helpfulness" || got[1].Name != "tone" {
+		t.Errorf("referenced = %+v, want the built-in and the created evaluator", got)
+	}
+}
+
+func TestAnnotateEvalSampling(t *testing.T) {
+	resources := []ResourceState{
+		{Type: ResTypeEvaluator, Name: "tone", Status: ResStatusCreated},
+		{Type: ResTypeEvaluator, Name: "unreferenced", Status: ResStatusFailed},
+		{Type: ResTypeOnlineEvalConfig, Name: "mypack_online_eval", Status: ResStatusCreated},
+	}
+	sampling := []evalSampling{
+		{Name: "Builtin.Helpfulness", Trigger: evals.TriggerSampleTurns, Percentage: 25},
+		{Name: "tone", Trigger: evals.TriggerSampleSessions, Percentage: 10},
+	}
+	annotateEvalSampling(resources, sampling)
+
+	tone := resources[0].Metadata
+	if tone[metaEvalTrigger] != "sample_sessions" || tone[metaEvalLevel] != "SESSION" ||
+		tone[metaSamplePercentage] != "10" {
+		t.Errorf("evaluator metadata = %v", tone)
+	}
+	if resources[1].Metadata != nil {
+		t.Errorf("unreferenced evaluator annotated: %v", resources[1].Metadata)
+	}
+	oec := resources[2].Metadata
+	if oec[metaSamplingPercentage] != "25" || oec[metaEvalSamplingRequests] != "Builtin.Helpfulness=25,tone=10" {
+		t.Errorf("online eval config metadata = %v", oec)
+	}
+}
+
+func TestApply_RecordsEvalSampling(t *testing.T) {
+	var pack map[string]any
+	if err := json.Unmarshal([]byte(multiAgentPackWithEvals()), &pack); err != nil {
+		t.Fatal(err)
+	}
+	pack["evals"] = []map[string]any{
+		{"id": "tone", "type": "llm_as_judge", "trigger": "sample_turns", "sample_percentage": 20,
+			"params": map[string]any{"instructions": "Rate the tone"}},
+	}
+	state, err := applyPackJSON(t, mustJSON(t, pack))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	var evaluator, oec *ResourceState
+	for i := range state.Resources {
+		switch state.Resources[i].Type {
+		case ResTypeEvaluator:
+			evaluator = &state.Resources[i]
+		case ResTypeOnlineEvalConfig:
+			oec = &state.Resources[i]
+		}
+	}
+	if evaluator == nil || evaluator.Metadata[metaSamplePercentage] != "20" ||
+		evaluator.Metadata[metaEvalLevel] != "TRACE" {
+		t.Errorf("evaluator = %+v, want 20%% TRACE sampling recorded", evaluator)
+	}
+	if oec == nil || oec.Metadata[metaSamplingPercentage] != "20" {
+		t.Errorf("online eval config = %+v, want 20%% sampling recorded", oec)
+	}
+}
+
+func applyPackJSON(t *testing.T, packJSON string) (*AdapterState, error) {
+	t.Helper()
+	_, raw, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON:     packJSON,
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatalf("parse state: %v", err)
+	}
+	return &state, nil
+}
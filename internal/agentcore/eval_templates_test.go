@@ -0,0 +1,111 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func TestEvalInstructions(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		contains string
+		wantErr  string
+	}{
+		{"default", nil, defaultEvalInstructions, ""},
+		{"inline", map[string]any{"instructions": "Check quality"}, "Check quality", ""},
+		{"template defaults", map[string]any{"template": "helpfulness"}, "helps users with general questions", ""},
+		{
+			"template vars",
+			map[string]any{"template": "tone", "template_vars": map[string]any{"tone": "warm and concise"}},
+			"speaks to customers and must sound warm and concise", "",
+		},
+		{"missing required var", map[string]any{"template": "tone"}, "", "requires template_vars.tone"},
+		{
+			"unknown var",
+			map[string]any{"template": "safety", "template_vars": map[string]any{"colour": "red"}},
+			"", `no variable "colour"`,
+		},
+		{"unknown template", map[string]any{"template": "brevity"}, "", "available: groundedness, helpfulness"},
+		{
+			"both set",
+			map[string]any{"template": "safety", "instructions": "Be safe"},
+			"", "set only one of instructions and template",
+		},
+		{
+			"non-string var",
+			map[string]any{"template": "safety", "template_vars": map[string]any{"policy": 3.0}},
+			"", "template_vars.policy must be a string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalInstructions(tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evalInstructions: %v", err)
+			}
+			if !strings.Contains(got, tt.contains) {
+				t.Errorf("instructions = %q, want it to contain %q", got, tt.contains)
+			}
+		})
+	}
+}
+
+func TestEvalTemplates_RenderCompletely(t *testing.T) {
+	for name, tmpl := range evalTemplates {
+		vars := map[string]string{}
+		for _, v := range tmpl.Variables {
+			if v.Default == "" {
+				vars[v.Name] = "x"
+			}
+		}
+		got, err := tmpl.render(vars)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if strings.Contains(got, "{{") {
+			t.Errorf("%s: unsubstituted variable in %q", name, got)
+		}
+		if ensureEvalPlaceholders(got) != got {
+			t.Errorf("%s: template lacks an AgentCore placeholder", name)
+		}
+	}
+}
+
+func TestListEvalTemplates(t *testing.T) {
+	resp, err := newSimulatedProvider().ListEvalTemplates(context.Background(), &ListEvalTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("ListEvalTemplates: %v", err)
+	}
+	var names []string
+	for _, tmpl := range resp.Templates {
+		names = append(names, tmpl.Name)
+	}
+	if got := strings.Join(names, ","); got != "groundedness,helpfulness,safety,tone" {
+		t.Errorf("templates = %s", got)
+	}
+}
+
+func TestPlan_RejectsInvalidEvalTemplate(t *testing.T) {
+	pack := `{"id":"mypack","version":"v1.0.0","prompts":{"chat":{"id":"chat","name":"Chat",` +
+		`"system_template":"Hi","version":"v1.0.0"}},"evals":[{"id":"voice","type":"llm_as_judge",` +
+		`"trigger":"every_turn","params":{"template":"tone"}}]}`
+	_, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     pack,
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), `eval "voice": template "tone" requires template_vars.tone`) {
+		t.Errorf("err = %v, want the missing tone variable", err)
+	}
+}
@@ -0,0 +1,184 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// errEvaluatorInUse rejects deleting an evaluator that online eval configs
+// still run.
+var errEvaluatorInUse = errors.New("evaluator is referenced")
+
+// evaluatorUser is an online eval config that references an evaluator.
+type evaluatorUser struct {
+	id, name   string
+	evaluators []types.EvaluatorReference
+}
+
+// evaluatorUsers returns the online eval configs referencing evaluatorID,
+// sorted by name. Configs are listed across all pages, whoever created
+// them, since AWS refuses to delete an evaluator any of them runs.
+func (c *realAWSClient) evaluatorUsers(ctx context.Context, evaluatorID string) ([]evaluatorUser, error) {
+	var users []evaluatorUser
+	var nextToken *string
+	for {
+		out, err := c.client.ListOnlineEvaluationConfigs(ctx, &bedrockagentcorecontrol.ListOnlineEvaluationConfigsInput{
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListOnlineEvaluationConfigs: %w", err)
+		}
+		for _, summary := range out.OnlineEvaluationConfigs {
+			user, getErr := c.evaluatorUserOf(ctx, summary, evaluatorID)
+			if getErr != nil {
+				return nil, getErr
+			}
+			if user != nil {
+				users = append(users, *user)
+			}
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	slices.SortFunc(users, func(a, b evaluatorUser) int { return strings.Compare(a.name, b.name) })
+	return users, nil
+}
+
+// evaluatorUserOf returns the listed online eval config when it references
+// evaluatorID, and nil when it does not or is gone.
+func (c *realAWSClient) evaluatorUserOf(
+	ctx context.Context, summary types.OnlineEvaluationConfigSummary, evaluatorID string,
+) (*evaluatorUser, error) {
+	id := aws.ToString(summary.OnlineEvaluationConfigId)
+	current, err := c.client.GetOnlineEvaluationConfig(ctx,
+		&bedrockagentcorecontrol.GetOnlineEvaluationConfigInput{OnlineEvaluationConfigId: aws.String(id)})
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetOnlineEvaluationConfig %q: %w", id, err)
+	}
+	if !slices.Contains(evaluatorRefIDs(current.Evaluators), evaluatorID) {
+		return nil, nil
+	}
+	return &evaluatorUser{
+		id: id, name: aws.ToString(summary.OnlineEvaluationConfigName), evaluators: current.Evaluators,
+	}, nil
+}
+
+// releaseEvaluator makes sure no online eval config references the
+// evaluator res before it is deleted. Without force_detach_evaluators it
+// fails, naming the configs; with it, the evaluator is removed from each
+// config, and a config left without evaluators is deleted if this
+// deployment owns it.
+func (c *realAWSClient) releaseEvaluator(ctx context.Context, evaluatorID string, res ResourceState) error {
+	users, err := c.evaluatorUsers(ctx, evaluatorID)
+	if err != nil {
+		return fmt.Errorf("DeleteEvaluator %q: %w", res.Name, err)
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	if !c.cfg.ForceDetachEvaluators {
+		names := make([]string, len(users))
+		for i, u := range users {
+			names[i] = u.name
+		}
+		return fmt.Errorf("DeleteEvaluator %q: %w by online eval configs %s; "+
+			"remove it from them or set force_detach_evaluators", res.Name, errEvaluatorInUse, strings.Join(names, ", "))
+	}
+	for _, u := range users {
+		if err = c.detachEvaluator(ctx, evaluatorID, u); err != nil {
+			return fmt.Errorf("DeleteEvaluator %q: %w", res.Name, err)
+		}
+	}
+	return nil
+}
+
+// errForeignOnlineEval rejects deleting an online eval config another
+// deployment owns.
+var errForeignOnlineEval = errors.New("online eval config belongs to another deployment")
+
+// lockOnlineEvalConfig serializes detaches from the online eval config id,
+// since evaluators of one type are deleted concurrently and each detach
+// rewrites the config's evaluator list. It returns the unlock function.
+func (c *realAWSClient) lockOnlineEvalConfig(id string) func() {
+	mu, _ := c.detachLocks.LoadOrStore(id, &sync.Mutex{})
+	m := mu.(*sync.Mutex)
+	m.Lock()
+	return m.Unlock
+}
+
+// detachEvaluator removes evaluatorID from the online eval config u. The
+// config is read again under its lock, so a concurrent detach from it is
+// never undone. The API rejects a config without evaluators, so one that
+// only ran evaluatorID is deleted instead, when this deployment owns it.
+func (c *realAWSClient) detachEvaluator(ctx context.Context, evaluatorID string, u evaluatorUser) error {
+	defer c.lockOnlineEvalConfig(u.id)()
+	current, err := c.client.GetOnlineEvaluationConfig(ctx,
+		&bedrockagentcorecontrol.GetOnlineEvaluationConfigInput{OnlineEvaluationConfigId: aws.String(u.id)})
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("GetOnlineEvaluationConfig %q: %w", u.name, err)
+	}
+	remaining := slices.DeleteFunc(slices.Clone(current.Evaluators), func(ref types.EvaluatorReference) bool {
+		r, ok := ref.(*types.EvaluatorReferenceMemberEvaluatorId)
+		return ok && r.Value == evaluatorID
+	})
+	if len(remaining) == len(current.Evaluators) {
+		return nil
+	}
+	if len(remaining) == 0 {
+		return c.deleteEmptiedOnlineEvalConfig(ctx, evaluatorID, u, aws.ToString(current.OnlineEvaluationConfigArn))
+	}
+	logf("agentcore: detaching evaluator %s from online eval config %q", evaluatorID, u.name)
+	if _, err := c.client.UpdateOnlineEvaluationConfig(ctx, &bedrockagentcorecontrol.UpdateOnlineEvaluationConfigInput{
+		OnlineEvaluationConfigId: aws.String(u.id),
+		Evaluators:               remaining,
+	}); err != nil {
+		return fmt.Errorf("UpdateOnlineEvaluationConfig %q: %w", u.name, err)
+	}
+	if err := c.waitForOnlineEvalConfigReady(ctx, u.id); err != nil {
+		return fmt.Errorf("online eval config %q updated but not active: %w", u.name, err)
+	}
+	return nil
+}
+
+// deleteEmptiedOnlineEvalConfig deletes the online eval config u, which
+// only ran evaluatorID. A config tagged for another pack or workspace is
+// left alone and the detach fails, as AWS would refuse the evaluator's
+// deletion anyway.
+func (c *realAWSClient) deleteEmptiedOnlineEvalConfig(
+	ctx context.Context, evaluatorID string, u evaluatorUser, arn string,
+) error {
+	tags, err := c.resourceTags(ctx, ResTypeOnlineEvalConfig, arn)
+	if err != nil {
+		return fmt.Errorf("verify ownership of online eval config %q: %w", u.name, err)
+	}
+	if checkOwnershipTags(ResTypeOnlineEvalConfig, u.name, tags, c.cfg) != nil {
+		logf("agentcore: not deleting online eval config %q, which only runs evaluator %s "+
+			"but belongs to another deployment", u.name, evaluatorID)
+		return fmt.Errorf("%w: %q only runs evaluator %s; remove it from the config or delete the config",
+			errForeignOnlineEval, u.name, evaluatorID)
+	}
+	logf("agentcore: deleting online eval config %q, which only ran evaluator %s", u.name, evaluatorID)
+	_, err = c.client.DeleteOnlineEvaluationConfig(ctx,
+		&bedrockagentcorecontrol.DeleteOnlineEvaluationConfigInput{OnlineEvaluationConfigId: aws.String(u.id)})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteOnlineEvaluationConfig %q: %w", u.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testEvaluatorARN = "arn:aws:bedrock-agentcore:us-west-2:123456789012:evaluator/ev-tone"
+
+// Online eval configs as listed and got, the second also running another
+// evaluator.
+const (
+	listedOnlineEvalsJSON = `{"onlineEvaluationConfigs":[` +
+		`{"onlineEvaluationConfigId":"oec-2","onlineEvaluationConfigName":"zeta"},` +
+		`{"onlineEvaluationConfigId":"oec-1","onlineEvaluationConfigName":"alpha"}]}`
+	onlyToneOnlineEvalJSON = `{"onlineEvaluationConfigId":"oec-2","status":"ACTIVE",` +
+		`"onlineEvaluationConfigArn":"arn:aws:bedrock-agentcore:us-west-2:1:online-evaluation-config/oec-2",` +
+		`"evaluators":[{"evaluatorId":"ev-tone"}]}`
+	sharedOnlineEvalJSON = `{"onlineEvaluationConfigId":"oec-1","status":"ACTIVE",` +
+		`"evaluators":[{"evaluatorId":"ev-tone"},{"evaluatorId":"Builtin.Helpfulness"}]}`
+)
+
+func evaluatorState() ResourceState {
+	return ResourceState{Type: ResTypeEvaluator, Name: "mypack_tone", ARN: testEvaluatorARN}
+}
+
+func TestDeleteEvaluator_Unreferenced(t *testing.T) {
+	c, rec := newRecordingRealClient(
+		`{"onlineEvaluationConfigs":[{"onlineEvaluationConfigId":"oec-1","onlineEvaluationConfigName":"alpha"}]}`,
+		`{"onlineEvaluationConfigId":"oec-1","evaluators":[{"evaluatorId":"Builtin.Helpfulness"}]}`,
+		`{"evaluatorId":"ev-tone","status":"DELETING"}`)
+
+	if err := c.deleteEvaluator(context.Background(), evaluatorState()); err != nil {
+		t.Fatalf("deleteEvaluator: %v", err)
+	}
+	if len(rec.requests) != 3 || !strings.HasPrefix(rec.requests[2], "DELETE ") ||
+		!strings.Contains(rec.requests[2], "ev-tone") {
+		t.Errorf("requests = %v, want list, get, and the evaluator deleted", rec.requests)
+	}
+}
+
+func TestDeleteEvaluator_ReferencedFails(t *testing.T) {
+	c, rec := newRecordingRealClient(listedOnlineEvalsJSON, onlyToneOnlineEvalJSON, sharedOnlineEvalJSON)
+
+	err := c.deleteEvaluator(context.Background(), evaluatorState())
+	if !errors.Is(err, errEvaluatorInUse) {
+		t.Fatalf("deleteEvaluator error = %v, want errEvaluatorInUse", err)
+	}
+	if !strings.Contains(err.Error(), "online eval configs alpha, zeta;") ||
+		!strings.Contains(err.Error(), "force_detach_evaluators") {
+		t.Errorf("error = %q, want the configs by name and the force option", err)
+	}
+	for _, r := range rec.requests {
+		if strings.HasPrefix(r, "PUT ") || strings.HasPrefix(r, "DELETE ") {
+			t.Errorf("request %s, want only lists and reads", r)
+		}
+	}
+}
+
+func TestDeleteEvaluator_ForceDetach(t *testing.T) {
+	c, rec := newRecordingRealClient(listedOnlineEvalsJSON, onlyToneOnlineEvalJSON, sharedOnlineEvalJSON,
+		sharedOnlineEvalJSON, `{"onlineEvaluationConfigId":"oec-1","status":"UPDATING"}`, sharedOnlineEvalJSON,
+		onlyToneOnlineEvalJSON, `{"tags":{"promptpack:pack-id":"mypack"}}`,
+		`{"onlineEvaluationConfigId":"oec-2","status":"DELETING"}`,
+		`{"evaluatorId":"ev-tone","status":"DELETING"}`)
+	c.cfg.ForceDetachEvaluators = true
+	c.cfg.ResourceTags = map[string]string{TagKeyPackID: "mypack"}
+
+	if err := c.deleteEvaluator(context.Background(), evaluatorState()); err != nil {
+		t.Fatalf("deleteEvaluator: %v", err)
+	}
+	if len(rec.requests) != 10 {
+		t.Fatalf("requests = %v, want list, 2 gets, get, update, get, get, tags, delete config, delete evaluator",
+			rec.requests)
+	}
+	update := rec.requests[4]
+	if !strings.HasPrefix(update, "PUT ") || !strings.Contains(update, "oec-1") ||
+		strings.Contains(update, "ev-tone") || !strings.Contains(update, "Builtin.Helpfulness") {
+		t.Errorf("update = %s, want alpha keeping only its other evaluator", update)
+	}
+	if r := rec.requests[8]; !strings.HasPrefix(r, "DELETE ") || !strings.Contains(r, "oec-2") {
+		t.Errorf("request %s, want zeta deleted, as tone was its only evaluator", r)
+	}
+	if r := rec.requests[9]; !strings.HasPrefix(r, "DELETE ") || !strings.Contains(r, "ev-tone") {
+		t.Errorf("request %s, want the evaluator deleted last", r)
+	}
+}
+
+func TestDeleteEvaluator_ForceDetachRereadsConfig(t *testing.T) {
+	// By the time alpha is detached, another deletion has already removed
+	// tone from it; the stale list from the scan must not be written back.
+	c, rec := newRecordingRealClient(
+		`{"onlineEvaluationConfigs":[{"onlineEvaluationConfigId":"oec-1","onlineEvaluationConfigName":"alpha"}]}`,
+		sharedOnlineEvalJSON,
+		`{"onlineEvaluationConfigId":"oec-1","status":"ACTIVE","evaluators":[{"evaluatorId":"Builtin.Helpfulness"}]}`,
+		`{"evaluatorId":"ev-tone","status":"DELETING"}`)
+	c.cfg.ForceDetachEvaluators = true
+
+	if err := c.deleteEvaluator(context.Background(), evaluatorState()); err != nil {
+		t.Fatalf("deleteEvaluator: %v", err)
+	}
+	for _, r := range rec.requests {
+		if strings.HasPrefix(r, "PUT ") {
+			t.Errorf("request %s, want no update of a config no longer running the evaluator", r)
+		}
+	}
+}
+
+func TestDeleteEvaluator_ForceDetachKeepsForeignConfig(t *testing.T) {
+	c, rec := newRecordingRealClient(
+		`{"onlineEvaluationConfigs":[{"onlineEvaluationConfigId":"oec-2","onlineEvaluationConfigName":"zeta"}]}`,
+		onlyToneOnlineEvalJSON, onlyToneOnlineEvalJSON, `{"tags":{"promptpack:pack-id":"otherpack"}}`)
+	c.cfg.ForceDetachEvaluators = true
+	c.cfg.ResourceTags = map[string]string{TagKeyPackID: "mypack"}
+
+	err := c.deleteEvaluator(context.Background(), evaluatorState())
+	if !errors.Is(err, errForeignOnlineEval) {
+		t.Fatalf("deleteEvaluator error = %v, want errForeignOnlineEval", err)
+	}
+	for _, r := range rec.requests {
+		if strings.HasPrefix(r, "DELETE ") {
+			t.Errorf("request %s, want nothing deleted", r)
+		}
+	}
+}
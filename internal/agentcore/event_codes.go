@@ -0,0 +1,136 @@
+package agentcore
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// Event codes of events that concern no single resource. Resource events
+// are coded <SUBJECT>_<OUTCOME>, for example RUNTIME_CREATE_START,
+// MEMORY_ADOPTED, or POLICY_FAILED; see EventCode.
+const (
+	EventCodeProgress           = "PROGRESS"
+	EventCodeWarning            = "WARNING"
+	EventCodeError              = "ERROR"
+	EventCodeDestroyComplete    = "DESTROY_COMPLETE"
+	EventCodeMissingPermissions = "MISSING_PERMISSIONS"
+)
+
+// Outcomes of resource event codes besides the resource statuses.
+const (
+	eventOutcomeAdopted = "ADOPTED"
+	eventOutcomeFailed  = "FAILED"
+	eventOutcomeStart   = "_START"
+)
+
+// eventCodeSubjects shortens the resource types whose names repeat the
+// adapter's context in codes.
+var eventCodeSubjects = map[string]string{
+	ResTypeAgentRuntime: "RUNTIME",
+	ResTypeCedarPolicy:  "POLICY",
+}
+
+// eventVerbs maps the verbs of resource progress messages to the
+// operation a code names.
+var eventVerbs = map[string]string{
+	"Creating":  "CREATE",
+	"Updating":  "UPDATE",
+	"Replacing": "REPLACE",
+	"Deleting":  "DELETE",
+}
+
+// Message formats that identify resource events without a resource
+// result. Resource types may be written with spaces, as in "Creating
+// inference profile: name".
+var (
+	progressStartRE = regexp.MustCompile(`^(Creating|Updating|Replacing|Deleting) ([a-z0-9_ ]+?)(?:: | ")`)
+	adoptedRE       = regexp.MustCompile(`^Adopted existing ([a-z0-9_]+) "`)
+	deployErrorRE   = regexp.MustCompile(`^(?:create|update|delete|replace) ([a-z0-9_]+) "`)
+)
+
+// Event is an Apply or Destroy event with its stable code. The JSON-RPC
+// and HTTP servers send events in this form, so clients can localize and
+// filter them without parsing the English message.
+type Event struct {
+	Type     string                 `json:"type"`
+	Code     string                 `json:"code,omitempty"`
+	Message  string                 `json:"message,omitempty"`
+	Resource *deploy.ResourceResult `json:"resource,omitempty"`
+}
+
+// codedApplyEvent returns ev with its code.
+func codedApplyEvent(ev *deploy.ApplyEvent) *Event {
+	return &Event{
+		Type: ev.Type, Code: EventCode(ev.Type, ev.Message, ev.Resource), Message: ev.Message, Resource: ev.Resource,
+	}
+}
+
+// codedDestroyEvent returns ev with its code.
+func codedDestroyEvent(ev *deploy.DestroyEvent) *Event {
+	return &Event{
+		Type: ev.Type, Code: EventCode(ev.Type, ev.Message, ev.Resource), Message: ev.Message, Resource: ev.Resource,
+	}
+}
+
+// EventCode returns the stable, machine-readable code of an Apply or
+// Destroy event. Events with a resource result are coded by its type and
+// status, such as MEMORY_CREATED or RUNTIME_SKIPPED. Progress events that
+// start an operation on a resource are coded by the operation, such as
+// RUNTIME_CREATE_START, and errors of a resource operation by the
+// resource, such as POLICY_FAILED. The summary of denied IAM permissions
+// is MISSING_PERMISSIONS. Other events fall back to PROGRESS, WARNING,
+// ERROR, or DESTROY_COMPLETE.
+func EventCode(eventType, message string, res *deploy.ResourceResult) string {
+	if res != nil && res.Status != "" {
+		if subject, ok := eventCodeSubject(res.Type); ok {
+			return subject + "_" + strings.ToUpper(res.Status)
+		}
+	}
+	switch eventType {
+	case "error":
+		if m := deployErrorRE.FindStringSubmatch(message); m != nil {
+			if subject, ok := eventCodeSubject(m[1]); ok {
+				return subject + "_" + eventOutcomeFailed
+			}
+		}
+		return EventCodeError
+	case "complete":
+		return EventCodeDestroyComplete
+	}
+	return progressEventCode(message)
+}
+
+// progressEventCode codes a progress event by its message.
+func progressEventCode(message string) string {
+	if m := progressStartRE.FindStringSubmatch(message); m != nil {
+		if subject, ok := eventCodeSubject(strings.ReplaceAll(m[2], " ", "_")); ok {
+			return subject + "_" + eventVerbs[m[1]] + eventOutcomeStart
+		}
+	}
+	if m := adoptedRE.FindStringSubmatch(message); m != nil {
+		if subject, ok := eventCodeSubject(m[1]); ok {
+			return subject + "_" + eventOutcomeAdopted
+		}
+	}
+	if strings.HasPrefix(message, "Warning: ") {
+		return EventCodeWarning
+	}
+	if strings.HasPrefix(message, missingPermissionsPrefix) {
+		return EventCodeMissingPermissions
+	}
+	return EventCodeProgress
+}
+
+// eventCodeSubject returns the code subject of a resource type, and false
+// when resType is not one the adapter manages.
+func eventCodeSubject(resType string) (string, bool) {
+	if _, ok := lookupResourceType(resType); !ok {
+		return "", false
+	}
+	if subject, ok := eventCodeSubjects[resType]; ok {
+		return subject, true
+	}
+	return strings.ToUpper(resType), true
+}
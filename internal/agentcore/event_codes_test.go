@@ -0,0 +1,88 @@
+package agentcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func TestEventCode(t *testing.T) {
+	for _, tt := range []struct {
+		eventType, message string
+		res                *deploy.ResourceResult
+		want               string
+	}{
+		{"progress", "Creating agent_runtime: mypack", nil, "RUNTIME_CREATE_START"},
+		{"progress", "Updating tool_gateway: mypack_gateway", nil, "TOOL_GATEWAY_UPDATE_START"},
+		{"progress", "Creating inference profile: mypack_claude", nil, "INFERENCE_PROFILE_CREATE_START"},
+		{"progress", "Replacing memory: mypack_memory (strategies changed)", nil, "MEMORY_REPLACE_START"},
+		{"progress", `Deleting cedar_policy "mypack_policy"`, nil, "POLICY_DELETE_START"},
+		{"progress", `Adopted existing memory "mypack_memory"`, nil, "MEMORY_ADOPTED"},
+		{"progress", "Creating widget: x", nil, EventCodeProgress},
+		{"progress", "Warning: region is not known to offer AgentCore", nil, EventCodeWarning},
+		{"progress", "Deploying 3 resources", nil, EventCodeProgress},
+		{"resource", "", &deploy.ResourceResult{Type: ResTypeMemory, Status: ResStatusCreated}, "MEMORY_CREATED"},
+		{"resource", "", &deploy.ResourceResult{Type: ResTypeAgentRuntime, Status: ResStatusSkipped}, "RUNTIME_SKIPPED"},
+		{"error", "", &deploy.ResourceResult{Type: ResTypeCedarPolicy, Status: ResStatusFailed}, "POLICY_FAILED"},
+		{"error", `create cedar_policy "mypack_policy" failed: denied`, nil, "POLICY_FAILED"},
+		{"error", "upload change manifest: denied", nil, EventCodeError},
+		{"complete", "Destroy complete", nil, EventCodeDestroyComplete},
+	} {
+		if got := EventCode(tt.eventType, tt.message, tt.res); got != tt.want {
+			t.Errorf("EventCode(%q, %q) = %q, want %q", tt.eventType, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestApply_ReportsAdopted(t *testing.T) {
+	cloud := NewSimulatedCloud()
+	cloud.Seed("us-west-2", ResTypeAgentRuntime, "mypack", map[string]string{TagKeyPackID: "mypack"})
+	events, _, err := collectEvents(t, NewSimulatedProvider(cloud), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: validConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var codes []string
+	for i := range events {
+		codes = append(codes, codedApplyEvent(&events[i]).Code)
+	}
+	for _, want := range []string{"RUNTIME_CREATE_START", "RUNTIME_CREATED", "RUNTIME_ADOPTED"} {
+		if !slices.Contains(codes, want) {
+			t.Errorf("event codes = %v, want %s", codes, want)
+		}
+	}
+}
+
+func TestServeIO_ApplyEventCodes(t *testing.T) {
+	params := map[string]string{
+		"pack_json": singleAgentPack(), "deploy_config": validConfig(t), "arena_config": validArenaConfigJSON,
+	}
+	var out bytes.Buffer
+	if err := ServeIO(newSimulatedProvider(), strings.NewReader(jsonRPCRequest("apply", 1, params)), &out); err != nil {
+		t.Fatalf("ServeIO: %v", err)
+	}
+	var resp jsonRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response %q: %v", out.String(), err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("apply error: %s", resp.Error.Message)
+	}
+	var result applyResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.AdapterState == "" || len(result.Events) == 0 {
+		t.Fatalf("result = %+v, want state and events", result)
+	}
+	for _, ev := range result.Events {
+		if ev.Code == "" {
+			t.Errorf("event %+v has no code", ev)
+		}
+	}
+}
@@ -10,6 +10,67 @@ import (
 	"github.com/AltairaLabs/PromptKit/runtime/prompt"
 )
 
+// Gateway tool search modes accepted by gateway.search_type.
+const (
+	GatewaySearchSemantic = "semantic"
+	GatewaySearchNone     = "none"
+)
+
+// GatewayConfig holds settings for the shared MCP tool gateway.
+type GatewayConfig struct {
+	// SearchType enables gateway-side tool discovery: with "semantic" the
+	// gateway offers a search tool that finds tools by description.
+	SearchType string `json:"search_type,omitempty"`
+
+	// Instructions tell MCP clients how to use the gateway.
+	Instructions string `json:"instructions,omitempty"`
+
+	// Interceptors are Lambda functions the gateway invokes on each tool
+	// call to transform the request or response.
+	Interceptors []GatewayInterceptor `json:"interceptors,omitempty"`
+
+	// ProbeTargets makes Apply list the gateway's tools over MCP after
+	// creating its targets and warn about targets that serve none.
+	ProbeTargets bool `json:"probe_targets,omitempty"`
+}
+
+// validateGateway checks the gateway search type and interceptors.
+func validateGateway(g *GatewayConfig) []string {
+	if g == nil {
+		return nil
+	}
+	var errs []string
+	switch g.SearchType {
+	case "", GatewaySearchSemantic, GatewaySearchNone:
+	default:
+		errs = append(errs, fmt.Sprintf("gateway.search_type %q must be %q or %q",
+			g.SearchType, GatewaySearchSemantic, GatewaySearchNone))
+	}
+	return append(errs, validateGatewayInterceptors(g.Interceptors)...)
+}
+
+// gatewaySearchEnabled reports whether the gateway is created with
+// semantic tool search.
+func (c *Config) gatewaySearchEnabled() bool {
+	return c.Gateway != nil && c.Gateway.SearchType == GatewaySearchSemantic
+}
+
+// buildGatewayProtocolConfig returns the MCP protocol settings for
+// CreateGateway, or nil when none are configured.
+func buildGatewayProtocolConfig(cfg *Config) types.GatewayProtocolConfiguration {
+	if cfg.Gateway == nil || (!cfg.gatewaySearchEnabled() && cfg.Gateway.Instructions == "") {
+		return nil
+	}
+	mcp := types.MCPGatewayConfiguration{}
+	if cfg.gatewaySearchEnabled() {
+		mcp.SearchType = types.SearchTypeSemantic
+	}
+	if cfg.Gateway.Instructions != "" {
+		mcp.Instructions = aws.String(cfg.Gateway.Instructions)
+	}
+	return &types.GatewayProtocolConfigurationMemberMcp{Value: mcp}
+}
+
 // buildTargetConfig returns the SDK TargetConfiguration for a gateway tool.
 // When the arena config provides a Lambda ARN for the tool, it builds a
 // McpLambdaTargetConfiguration with an inline tool schema so the Cedar
@@ -277,11 +338,15 @@ func mapRestAPIMethod(method string) types.RestApiMethod {
 }
 
 // buildCredentialProviderConfigs returns the credential provider
-// configurations for the given tool target. The credential type is read
-// from the arena config's Credential field. If not set, targets that
-// require credentials (API Gateway, OpenAPI, Smithy) default to
+// configurations for the given tool target. A target attached to an
+// identity provider uses OAuth through it. Otherwise the credential type
+// is read from the arena config's Credential field. If not set, targets
+// that require credentials (API Gateway, OpenAPI, Smithy) default to
 // GATEWAY_IAM_ROLE.
 func buildCredentialProviderConfigs(name string, cfg *Config) []types.CredentialProviderConfiguration {
+	if p, arn := cfg.identityProviderForTool(name); arn != "" {
+		return oauthCredentialProviderConfigs(p, arn)
+	}
 	spec := cfg.ArenaConfig.toolSpecForName(name)
 	if spec == nil {
 		return nil
@@ -0,0 +1,194 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// Gateway interceptor phases accepted by gateway.interceptors[].phases.
+const (
+	InterceptorPhaseRequest  = "request"
+	InterceptorPhaseResponse = "response"
+)
+
+// metaGatewayInterceptors records, on each tool_gateway resource, the
+// comma-separated Lambda ARNs of the interceptors the gateway was created
+// with.
+const metaGatewayInterceptors = "interceptors"
+
+// lambdaFunctionARNPattern matches unqualified and qualified Lambda
+// function ARNs.
+var lambdaFunctionARNPattern = regexp.MustCompile(
+	`^arn:aws[a-z-]*:lambda:[a-z0-9-]+:\d{12}:function:[a-zA-Z0-9_-]+(:[a-zA-Z0-9_$-]+)?$`)
+
+// GatewayInterceptor is a Lambda function the gateway invokes around tool
+// calls, e.g. to enrich request headers or rewrite response schemas.
+type GatewayInterceptor struct {
+	// LambdaARN is the function the gateway invokes. The runtime role
+	// must be allowed to invoke it.
+	LambdaARN string `json:"lambda_arn"`
+
+	// Phases selects when the interceptor runs: "request", "response",
+	// or both.
+	Phases []string `json:"phases"`
+
+	// PassRequestHeaders includes the caller's request headers in the
+	// interceptor input.
+	PassRequestHeaders bool `json:"pass_request_headers,omitempty"`
+}
+
+// validateGatewayInterceptors checks each interceptor's ARN and phases.
+func validateGatewayInterceptors(interceptors []GatewayInterceptor) []string {
+	var errs []string
+	for i, ic := range interceptors {
+		field := fmt.Sprintf("gateway.interceptors[%d]", i)
+		if !lambdaFunctionARNPattern.MatchString(ic.LambdaARN) {
+			errs = append(errs, fmt.Sprintf("%s.lambda_arn %q is not a Lambda function ARN", field, ic.LambdaARN))
+		}
+		if len(ic.Phases) == 0 {
+			errs = append(errs, fmt.Sprintf("%s.phases must list %q, %q, or both",
+				field, InterceptorPhaseRequest, InterceptorPhaseResponse))
+		}
+		seen := make(map[string]bool, len(ic.Phases))
+		for _, phase := range ic.Phases {
+			switch {
+			case phase != InterceptorPhaseRequest && phase != InterceptorPhaseResponse:
+				errs = append(errs, fmt.Sprintf("%s.phases: %q must be %q or %q",
+					field, phase, InterceptorPhaseRequest, InterceptorPhaseResponse))
+			case seen[phase]:
+				errs = append(errs, fmt.Sprintf("%s.phases: %q is listed twice", field, phase))
+			}
+			seen[phase] = true
+		}
+	}
+	return errs
+}
+
+// gatewayInterceptors returns the configured interceptors, if any.
+func (c *Config) gatewayInterceptors() []GatewayInterceptor {
+	if c.Gateway == nil {
+		return nil
+	}
+	return c.Gateway.Interceptors
+}
+
+// buildGatewayInterceptors returns the interceptor settings for
+// CreateGateway and UpdateGateway, or nil when none are configured.
+func buildGatewayInterceptors(cfg *Config) []types.GatewayInterceptorConfiguration {
+	interceptors := cfg.gatewayInterceptors()
+	if len(interceptors) == 0 {
+		return nil
+	}
+	out := make([]types.GatewayInterceptorConfiguration, 0, len(interceptors))
+	for _, ic := range interceptors {
+		points := make([]types.GatewayInterceptionPoint, 0, len(ic.Phases))
+		for _, phase := range ic.Phases {
+			points = append(points, types.GatewayInterceptionPoint(strings.ToUpper(phase)))
+		}
+		conf := types.GatewayInterceptorConfiguration{
+			InterceptionPoints: points,
+			Interceptor: &types.InterceptorConfigurationMemberLambda{
+				Value: types.LambdaInterceptorConfiguration{Arn: aws.String(ic.LambdaARN)},
+			},
+		}
+		if ic.PassRequestHeaders {
+			conf.InputConfiguration = &types.InterceptorInputConfiguration{PassRequestHeaders: aws.Bool(true)}
+		}
+		out = append(out, conf)
+	}
+	return out
+}
+
+// recordGatewayInterceptors notes the interceptors on each tool_gateway
+// resource created or adopted in this apply.
+func recordGatewayInterceptors(resources []ResourceState, cfg *Config) {
+	interceptors := cfg.gatewayInterceptors()
+	if len(interceptors) == 0 {
+		return
+	}
+	arns := make([]string, len(interceptors))
+	for i, ic := range interceptors {
+		arns[i] = ic.LambdaARN
+	}
+	for i := range resources {
+		r := &resources[i]
+		if r.Type != ResTypeToolGateway || r.Status == ResStatusFailed {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = map[string]string{}
+		}
+		r.Metadata[metaGatewayInterceptors] = strings.Join(arns, ",")
+	}
+}
+
+// lambdaFunctionChecker reports the state of a Lambda function.
+type lambdaFunctionChecker interface {
+	FunctionState(ctx context.Context, arn string) (string, error)
+}
+
+// lambdaCheckerFactory creates a lambdaFunctionChecker for the given config.
+type lambdaCheckerFactory func(ctx context.Context, cfg *Config) (lambdaFunctionChecker, error)
+
+// newRealLambdaCheckerFactory is the lambdaCheckerFactory used by NewProvider.
+func newRealLambdaCheckerFactory(ctx context.Context, cfg *Config) (lambdaFunctionChecker, error) {
+	awsCfg, err := loadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := lambda.NewFromConfig(awsCfg, func(o *lambda.Options) { o.BaseEndpoint = cfg.baseEndpoint(EndpointLambda) })
+	return &awsLambdaChecker{client: client}, nil
+}
+
+// awsLambdaChecker implements lambdaFunctionChecker with the Lambda API.
+type awsLambdaChecker struct {
+	client *lambda.Client
+}
+
+// FunctionState implements lambdaFunctionChecker.
+func (c *awsLambdaChecker) FunctionState(ctx context.Context, arn string) (string, error) {
+	out, err := c.client.GetFunctionConfiguration(ctx, &lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(arn),
+	})
+	if err != nil {
+		return "", fmt.Errorf("GetFunctionConfiguration: %w", err)
+	}
+	return string(out.State), nil
+}
+
+// checkInterceptorReachability returns a warning for every gateway
+// interceptor whose Lambda function cannot be found or is not active.
+// Interceptors only apply when the pack has tools, since otherwise no
+// gateway is created. Like the model check, it is advisory: a failed
+// lookup is a warning, not an error.
+func checkInterceptorReachability(
+	ctx context.Context, newChecker lambdaCheckerFactory, pack *prompt.Pack, cfg *Config,
+) []string {
+	interceptors := cfg.gatewayInterceptors()
+	if newChecker == nil || len(interceptors) == 0 || len(pack.Tools) == 0 {
+		return nil
+	}
+	checker, err := newChecker(ctx, cfg)
+	if err != nil {
+		return []string{fmt.Sprintf("could not check gateway interceptors: %v", err)}
+	}
+	var warnings []string
+	for _, ic := range interceptors {
+		state, err := checker.FunctionState(ctx, ic.LambdaARN)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("gateway interceptor %s is not reachable: %v", ic.LambdaARN, err))
+		case state != string(lambdaTypes.StateActive):
+			warnings = append(warnings, fmt.Sprintf("gateway interceptor %s is %s, not Active", ic.LambdaARN, state))
+		}
+	}
+	return warnings
+}
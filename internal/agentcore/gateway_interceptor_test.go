@@ -0,0 +1,202 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+const testInterceptorARN = "arn:aws:lambda:us-west-2:123456789012:function:add-tenant-header"
+
+// fakeLambdaChecker reports fixed function states.
+type fakeLambdaChecker struct {
+	states map[string]string
+	err    error
+}
+
+func (c *fakeLambdaChecker) FunctionState(_ context.Context, arn string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	state, ok := c.states[arn]
+	if !ok {
+		return "", errors.New("ResourceNotFoundException")
+	}
+	return state, nil
+}
+
+func lambdaCheckerFor(c *fakeLambdaChecker) lambdaCheckerFactory {
+	return func(context.Context, *Config) (lambdaFunctionChecker, error) { return c, nil }
+}
+
+func interceptorConfig(interceptors ...GatewayInterceptor) *Config {
+	return &Config{Region: "us-west-2", Gateway: &GatewayConfig{Interceptors: interceptors}}
+}
+
+func TestValidateGatewayInterceptors(t *testing.T) {
+	tests := []struct {
+		name        string
+		interceptor GatewayInterceptor
+		wantErr     string
+	}{
+		{
+			name:        "valid",
+			interceptor: GatewayInterceptor{LambdaARN: testInterceptorARN, Phases: []string{"request", "response"}},
+		},
+		{
+			name:        "qualified arn",
+			interceptor: GatewayInterceptor{LambdaARN: testInterceptorARN + ":live", Phases: []string{"response"}},
+		},
+		{
+			name:        "not a lambda arn",
+			interceptor: GatewayInterceptor{LambdaARN: "add-tenant-header", Phases: []string{"request"}},
+			wantErr:     "gateway.interceptors[0].lambda_arn",
+		},
+		{
+			name:        "no phases",
+			interceptor: GatewayInterceptor{LambdaARN: testInterceptorARN},
+			wantErr:     "phases must list",
+		},
+		{
+			name:        "unknown phase",
+			interceptor: GatewayInterceptor{LambdaARN: testInterceptorARN, Phases: []string{"before"}},
+			wantErr:     `"before" must be`,
+		},
+		{
+			name:        "duplicate phase",
+			interceptor: GatewayInterceptor{LambdaARN: testInterceptorARN, Phases: []string{"request", "request"}},
+			wantErr:     "listed twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateGateway(&GatewayConfig{Interceptors: []GatewayInterceptor{tt.interceptor}})
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("errs = %v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildGatewayInterceptors(t *testing.T) {
+	if got := buildGatewayInterceptors(&Config{}); got != nil {
+		t.Errorf("no gateway config = %v, want nil", got)
+	}
+
+	got := buildGatewayInterceptors(interceptorConfig(
+		GatewayInterceptor{LambdaARN: testInterceptorARN, Phases: []string{"request", "response"}, PassRequestHeaders: true},
+	))
+	if len(got) != 1 {
+		t.Fatalf("got %d interceptors, want 1", len(got))
+	}
+	ic := got[0]
+	want := []types.GatewayInterceptionPoint{
+		types.GatewayInterceptionPointRequest, types.GatewayInterceptionPointResponse,
+	}
+	if fmt.Sprint(ic.InterceptionPoints) != fmt.Sprint(want) {
+		t.Errorf("points = %v, want %v", ic.InterceptionPoints, want)
+	}
+	lambda, ok := ic.Interceptor.(*types.InterceptorConfigurationMemberLambda)
+	if !ok || aws.ToString(lambda.Value.Arn) != testInterceptorARN {
+		t.Errorf("interceptor = %#v", ic.Interceptor)
+	}
+	if ic.InputConfiguration == nil || !aws.ToBool(ic.InputConfiguration.PassRequestHeaders) {
+		t.Errorf("input configuration = %+v, want request headers passed", ic.InputConfiguration)
+	}
+}
+
+func TestCheckInterceptorReachability(t *testing.T) {
+	stopped := "arn:aws:lambda:us-west-2:123456789012:function:rewrite"
+	missing := "arn:aws:lambda:us-west-2:123456789012:function:gone"
+	cfg := interceptorConfig(
+		GatewayInterceptor{LambdaARN: testInterceptorARN, Phases: []string{"request"}},
+		GatewayInterceptor{LambdaARN: stopped, Phases: []string{"response"}},
+		GatewayInterceptor{LambdaARN: missing, Phases: []string{"response"}},
+	)
+	pack := &prompt.Pack{Tools: map[string]*prompt.PackTool{"search": {Name: "search"}}}
+	checker := &fakeLambdaChecker{states: map[string]string{testInterceptorARN: "Active", stopped: "Inactive"}}
+
+	got := checkInterceptorReachability(context.Background(), lambdaCheckerFor(checker), pack, cfg)
+	if len(got) != 2 || !strings.Contains(got[0], stopped+" is Inactive") ||
+		!strings.Contains(got[1], missing+" is not reachable") {
+		t.Errorf("warnings = %v", got)
+	}
+
+	if got := checkInterceptorReachability(context.Background(), lambdaCheckerFor(checker),
+		&prompt.Pack{}, cfg); got != nil {
+		t.Errorf("pack without tools: warnings = %v, want none", got)
+	}
+
+	checker.err = errors.New("AccessDeniedException")
+	got = checkInterceptorReachability(context.Background(), lambdaCheckerFor(checker), pack,
+		interceptorConfig(GatewayInterceptor{LambdaARN: testInterceptorARN, Phases: []string{"request"}}))
+	if len(got) != 1 || !strings.Contains(got[0], "AccessDeniedException") {
+		t.Errorf("lookup failure: warnings = %v", got)
+	}
+}
+
+func interceptorDeployConfig(t *testing.T) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"gateway":{"interceptors":[{"lambda_arn":%q,"phases":["request"]}]}}`,
+		testBinaryPath(t), testInterceptorARN)
+}
+
+func TestPlan_WarnsAboutUnreachableInterceptor(t *testing.T) {
+	p := newSimulatedProvider()
+	p.lambdaCheckFunc = lambdaCheckerFor(&fakeLambdaChecker{})
+
+	resp, err := p.Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     singleAgentPackWithTools(),
+		DeployConfig: interceptorDeployConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if !strings.Contains(resp.Summary, "Warning: gateway interceptor "+testInterceptorARN+" is not reachable") {
+		t.Errorf("summary = %q", resp.Summary)
+	}
+}
+
+func TestApply_RecordsGatewayInterceptors(t *testing.T) {
+	_, raw, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON:     singleAgentPackWithTools(),
+		DeployConfig: interceptorDeployConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	gateways := 0
+	for _, r := range state.Resources {
+		if r.Type != ResTypeToolGateway {
+			continue
+		}
+		gateways++
+		if r.Metadata[metaGatewayInterceptors] != testInterceptorARN {
+			t.Errorf("%s metadata = %v, want the interceptor ARN", r.Name, r.Metadata)
+		}
+	}
+	if gateways == 0 {
+		t.Error("no tool_gateway resources in state")
+	}
+}
@@ -0,0 +1,161 @@
+package agentcore
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// Gateway partitioning modes accepted by gateway_partitioning.
+const (
+	GatewayPartitioningShared   = "shared"
+	GatewayPartitioningPerAgent = "per_agent"
+)
+
+// gatewayPartitionSeparator joins an agent name and a tool name in the
+// tool_gateway resource names of per-agent gateways.
+const gatewayPartitionSeparator = "/"
+
+// validateGatewayPartitioning checks the gateway_partitioning mode.
+func validateGatewayPartitioning(mode string) []string {
+	switch mode {
+	case "", GatewayPartitioningShared, GatewayPartitioningPerAgent:
+		return nil
+	}
+	return []string{fmt.Sprintf("gateway_partitioning %q must be %q or %q",
+		mode, GatewayPartitioningShared, GatewayPartitioningPerAgent)}
+}
+
+// gatewaysPerAgent reports whether each member of pack gets a gateway of
+// its own. Single-agent packs always share one gateway.
+func (c *Config) gatewaysPerAgent(pack *prompt.Pack) bool {
+	return c.GatewayPartitioning == GatewayPartitioningPerAgent && adaptersdk.IsMultiAgent(pack)
+}
+
+// toolGatewayNames returns the tool_gateway resources Apply creates: one
+// per deployed tool on the shared gateway, or with per-agent gateways one
+// per tool an agent lists, named "<agent>/<tool>".
+func toolGatewayNames(pack *prompt.Pack, cfg *Config) []string {
+	if !cfg.gatewaysPerAgent(pack) {
+		return deployedTools(pack)
+	}
+	var names []string
+	for _, agent := range agentRuntimeNames(pack) {
+		for _, tool := range agentTools(pack, agent) {
+			names = append(names, agent+gatewayPartitionSeparator+tool)
+		}
+	}
+	return names
+}
+
+// agentTools returns the sorted pack tools the agent's prompt lists.
+func agentTools(pack *prompt.Pack, agent string) []string {
+	p := pack.Prompts[agent]
+	if p == nil {
+		return nil
+	}
+	var tools []string
+	for _, tool := range p.Tools {
+		if _, ok := pack.Tools[tool]; ok && !slices.Contains(tools, tool) {
+			tools = append(tools, tool)
+		}
+	}
+	slices.Sort(tools)
+	return tools
+}
+
+// deployedTools returns the sorted pack tools a deployed prompt lists: the
+// agents' prompts in a multi-agent pack, or any prompt in a single-agent
+// pack, whose runtime may serve any of them. Tools no such prompt lists
+// get no gateway target.
+func deployedTools(pack *prompt.Pack) []string {
+	prompts := sortedKeys(pack.Prompts)
+	if adaptersdk.IsMultiAgent(pack) {
+		prompts = agentRuntimeNames(pack)
+	}
+	var tools []string
+	for _, name := range prompts {
+		for _, tool := range agentTools(pack, name) {
+			if !slices.Contains(tools, tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	slices.Sort(tools)
+	return tools
+}
+
+// unusedTools returns the sorted pack tools deployedTools leaves out.
+func unusedTools(pack *prompt.Pack) []string {
+	deployed := deployedTools(pack)
+	var unused []string
+	for _, tool := range sortedKeys(pack.Tools) {
+		if !slices.Contains(deployed, tool) {
+			unused = append(unused, tool)
+		}
+	}
+	return unused
+}
+
+// runtimeTools maps each runtime to the pack tools of the prompt it
+// serves. A single-agent runtime serves the prompt named after the pack,
+// else the entry agent, else the pack's only prompt, and is left out when
+// there is none.
+func runtimeTools(pack *prompt.Pack) map[string][]string {
+	tools := make(map[string][]string)
+	if adaptersdk.IsMultiAgent(pack) {
+		for _, agent := range agentRuntimeNames(pack) {
+			tools[agent] = agentTools(pack, agent)
+		}
+		return tools
+	}
+	served := pack.ID
+	switch {
+	case pack.Prompts[served] != nil:
+	case pack.Agents != nil && pack.Prompts[pack.Agents.Entry] != nil:
+		served = pack.Agents.Entry
+	case len(pack.Prompts) == 1:
+		served = sortedKeys(pack.Prompts)[0]
+	default:
+		return tools
+	}
+	tools[pack.ID] = agentTools(pack, served)
+	return tools
+}
+
+// splitGatewayTarget splits a tool_gateway resource name into the agent
+// whose gateway holds the target, "" for the shared gateway, and the
+// target's tool name.
+func splitGatewayTarget(name string) (agent, tool string) {
+	if a, t, ok := strings.Cut(name, gatewayPartitionSeparator); ok {
+		return a, t
+	}
+	return "", name
+}
+
+// gatewayARNsByAgent maps each agent with a gateway of its own to the
+// gateway's ARN, and "" to the shared gateway's.
+func gatewayARNsByAgent(resources []ResourceState) map[string]string {
+	arns := make(map[string]string)
+	for _, r := range resources {
+		if r.Type != ResTypeToolGateway || r.ARN == "" {
+			continue
+		}
+		if agent, _ := splitGatewayTarget(r.Name); arns[agent] == "" {
+			arns[agent] = r.ARN
+		}
+	}
+	return arns
+}
+
+// gatewayARNFor returns the ARN of the gateway serving agent's tools: its
+// own with per-agent gateways, otherwise the shared one.
+func (c *Config) gatewayARNFor(agent string) string {
+	if arn := c.AgentGatewayARNs[agent]; arn != "" {
+		return arn
+	}
+	return c.GatewayARN
+}
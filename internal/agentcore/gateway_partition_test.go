@@ -0,0 +1,253 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// partitionedPack returns a multi-agent pack whose members list different
+// tools: the coordinator lists none, the worker two and an unknown one,
+// and the writer one.
+func partitionedPack() *prompt.Pack {
+	return &prompt.Pack{
+		ID: "teampack",
+		Prompts: map[string]*prompt.PackPrompt{
+			"coordinator": {ID: "coordinator"},
+			"worker":      {ID: "worker", Tools: []string{"search", "lookup", "unknown"}},
+			"writer":      {ID: "writer", Tools: []string{"lookup"}},
+		},
+		Agents: &prompt.AgentsConfig{Entry: "coordinator", Members: map[string]*prompt.AgentDef{
+			"coordinator": {}, "worker": {}, "writer": {},
+		}},
+		Tools: map[string]*prompt.PackTool{
+			"lookup": {Name: "lookup"},
+			"search": {Name: "search"},
+		},
+	}
+}
+
+func partitionedPackJSON(t *testing.T) string {
+	t.Helper()
+	return mustJSON(t, partitionedPack())
+}
+
+func perAgentConfig(t *testing.T) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"gateway_partitioning":"per_agent"}`, testBinaryPath(t))
+}
+
+func TestValidateGatewayPartitioning(t *testing.T) {
+	for mode, wantErr := range map[string]bool{
+		"": false, GatewayPartitioningShared: false, GatewayPartitioningPerAgent: false, "per_tool": true,
+	} {
+		if errs := validateGatewayPartitioning(mode); (len(errs) > 0) != wantErr {
+			t.Errorf("validateGatewayPartitioning(%q) = %v, want error %t", mode, errs, wantErr)
+		}
+	}
+}
+
+func TestToolGatewayNames(t *testing.T) {
+	single := partitionedPack()
+	single.Agents = nil
+	tests := []struct {
+		name string
+		pack *prompt.Pack
+		mode string
+		want []string
+	}{
+		{"shared", partitionedPack(), "", []string{"lookup", "search"}},
+		{"per agent", partitionedPack(), GatewayPartitioningPerAgent,
+			[]string{"worker/lookup", "worker/search", "writer/lookup"}},
+		{"single agent shares", single, GatewayPartitioningPerAgent, []string{"lookup", "search"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toolGatewayNames(tt.pack, &Config{GatewayPartitioning: tt.mode})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("toolGatewayNames = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeployedTools(t *testing.T) {
+	pack := partitionedPack()
+	pack.Tools["unused"] = &prompt.PackTool{Name: "unused"}
+	if got := deployedTools(pack); !slices.Equal(got, []string{"lookup", "search"}) {
+		t.Errorf("deployedTools = %v, want [lookup search]", got)
+	}
+	if got := unusedTools(pack); !slices.Equal(got, []string{"unused"}) {
+		t.Errorf("unusedTools = %v, want [unused]", got)
+	}
+	pack.Prompts["worker"].Tools = nil
+	if got := toolGatewayNames(pack, &Config{}); !slices.Equal(got, []string{"lookup"}) {
+		t.Errorf("toolGatewayNames = %v, want only the writer's [lookup]", got)
+	}
+}
+
+func TestRuntimeTools(t *testing.T) {
+	multi := runtimeTools(partitionedPack())
+	if len(multi) != 3 || len(multi["coordinator"]) != 0 ||
+		!slices.Equal(multi["worker"], []string{"lookup", "search"}) || !slices.Equal(multi["writer"], []string{"lookup"}) {
+		t.Errorf("multi-agent runtimeTools = %v", multi)
+	}
+
+	single := partitionedPack()
+	single.Agents = nil
+	if got := runtimeTools(single); len(got) != 0 {
+		t.Errorf("runtimeTools = %v, want none without a prompt to serve", got)
+	}
+	single.Prompts = map[string]*prompt.PackPrompt{"only": {ID: "only", Tools: []string{"search"}}}
+	if got := runtimeTools(single); !slices.Equal(got["teampack"], []string{"search"}) {
+		t.Errorf("runtimeTools = %v, want the only prompt's tools under the pack ID", got)
+	}
+}
+
+func TestRuntimeEnvVarsForAgent_AllowedTools(t *testing.T) {
+	cfg := &Config{AgentTools: map[string][]string{"worker": {"lookup", "search"}}}
+	if got := runtimeEnvVarsForAgent(cfg, "worker")[EnvAllowedTools]; got != "lookup,search" {
+		t.Errorf("%s = %q, want %q", EnvAllowedTools, got, "lookup,search")
+	}
+	if _, ok := runtimeEnvVarsForAgent(cfg, "coordinator")[EnvAllowedTools]; ok {
+		t.Errorf("%s set for an agent without tools", EnvAllowedTools)
+	}
+}
+
+func TestPlan_UnusedToolsWarning(t *testing.T) {
+	pack := partitionedPack()
+	pack.Tools["unused"] = &prompt.PackTool{Name: "unused"}
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: mustJSON(t, pack), DeployConfig: validDeployConfig, ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if !strings.Contains(resp.Summary, "no deployed prompt uses tools unused") {
+		t.Errorf("summary = %q, want an unused tools warning", resp.Summary)
+	}
+	for _, c := range resp.Changes {
+		if c.Type == ResTypeToolGateway && strings.HasSuffix(c.Name, "unused") {
+			t.Errorf("planned a target for an unused tool: %+v", c)
+		}
+	}
+}
+
+func TestSplitGatewayTarget(t *testing.T) {
+	tests := []struct{ name, agent, tool string }{
+		{"lookup", "", "lookup"},
+		{"worker/lookup", "worker", "lookup"},
+	}
+	for _, tt := range tests {
+		if agent, tool := splitGatewayTarget(tt.name); agent != tt.agent || tool != tt.tool {
+			t.Errorf("splitGatewayTarget(%q) = %q, %q, want %q, %q", tt.name, agent, tool, tt.agent, tt.tool)
+		}
+	}
+}
+
+func TestGatewayARNFor(t *testing.T) {
+	cfg := &Config{GatewayARN: "shared-arn", AgentGatewayARNs: map[string]string{"worker": "worker-arn"}}
+	if got := cfg.gatewayARNFor("worker"); got != "worker-arn" {
+		t.Errorf("gatewayARNFor(worker) = %q, want worker-arn", got)
+	}
+	if got := cfg.gatewayARNFor("coordinator"); got != "shared-arn" {
+		t.Errorf("gatewayARNFor(coordinator) = %q, want shared-arn", got)
+	}
+}
+
+func TestApply_PerAgentGateways(t *testing.T) {
+	_, stateJSON, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON:     partitionedPackJSON(t),
+		DeployConfig: perAgentConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	var names []string
+	for _, r := range state.Resources {
+		if r.Type == ResTypeToolGateway {
+			names = append(names, r.Name)
+		}
+	}
+	want := []string{"worker/lookup", "worker/search", "writer/lookup"}
+	if !slices.Equal(names, want) {
+		t.Errorf("tool_gateway resources = %v, want %v", names, want)
+	}
+}
+
+func TestPlan_PerAgentGateways(t *testing.T) {
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     partitionedPackJSON(t),
+		DeployConfig: perAgentConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	var names []string
+	for _, c := range resp.Changes {
+		if c.Type == ResTypeToolGateway {
+			names = append(names, c.Name)
+		}
+	}
+	want := []string{"worker/lookup_tool_gw", "worker/search_tool_gw", "writer/lookup_tool_gw"}
+	if !slices.Equal(names, want) {
+		t.Errorf("planned tool_gateway changes = %v, want %v", names, want)
+	}
+}
+
+func TestRuntimeEnvVarsForAgent_GatewayURL(t *testing.T) {
+	cfg := &Config{
+		GatewayARN: "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-shared",
+		AgentGatewayARNs: map[string]string{
+			"worker": "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-worker",
+		},
+	}
+	tests := map[string]string{
+		"worker":      "https://gw-worker.gateway.bedrock-agentcore.us-west-2.amazonaws.com/mcp",
+		"coordinator": "https://gw-shared.gateway.bedrock-agentcore.us-west-2.amazonaws.com/mcp",
+	}
+	for agent, want := range tests {
+		if got := runtimeEnvVarsForAgent(cfg, agent)[EnvGatewayURL]; got != want {
+			t.Errorf("%s: %s = %q, want %q", agent, EnvGatewayURL, got, want)
+		}
+	}
+	if _, ok := runtimeEnvVarsForAgent(&Config{}, "worker")[EnvGatewayURL]; ok {
+		t.Errorf("%s set without a gateway", EnvGatewayURL)
+	}
+}
+
+func TestGraph_PerAgentGateways(t *testing.T) {
+	resp, err := newSimulatedProvider().Graph(context.Background(), &GraphRequest{
+		PackJSON: partitionedPackJSON(t), DeployConfig: perAgentConfig(t),
+	})
+	if err != nil {
+		t.Fatalf("Graph: %v", err)
+	}
+	for _, want := range []string{
+		"agent_runtime__worker -->|tools| tool_gateway_worker\n",
+		"agent_runtime__writer -->|tools| tool_gateway_writer\n",
+		"tool_gateway_worker -->|target| tool_gateway__worker_search_tool_gw",
+		"tool_gateway_writer -->|target| tool_gateway__writer_lookup_tool_gw",
+	} {
+		if !strings.Contains(resp.Graph, want) {
+			t.Errorf("graph missing %q:\n%s", want, resp.Graph)
+		}
+	}
+	if strings.Contains(resp.Graph, "coordinator -->|tools|") {
+		t.Errorf("graph connects the coordinator, which has no tools, to a gateway:\n%s", resp.Graph)
+	}
+}
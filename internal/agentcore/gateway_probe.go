@@ -0,0 +1,256 @@
+package agentcore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// gatewayProbeAttempts is how many times Apply lists the gateway's tools
+// while waiting for new targets to start serving them.
+const gatewayProbeAttempts = 6
+
+// gatewayProbeTimeout bounds each MCP request made by the probe.
+const gatewayProbeTimeout = 30 * time.Second
+
+// maxGatewayProbeBytes caps the MCP response bodies the probe reads.
+const maxGatewayProbeBytes = 4 << 20
+
+// mcpProtocolVersion is the MCP revision the probe negotiates.
+const mcpProtocolVersion = "2025-03-26"
+
+// mcpSessionHeader carries the session the gateway assigns on initialize.
+const mcpSessionHeader = "Mcp-Session-Id"
+
+// gatewayToolSeparator joins a target name and a tool name in the tool
+// names a gateway lists.
+const gatewayToolSeparator = "___"
+
+// gatewayToolLister returns the names of every tool the MCP gateway at url
+// lists.
+type gatewayToolLister func(ctx context.Context, url string) ([]string, error)
+
+// gatewayProbeEnabled reports whether Apply verifies gateway targets with
+// an MCP tools/list call after creating them.
+func (c *Config) gatewayProbeEnabled() bool {
+	return c.Gateway != nil && c.Gateway.ProbeTargets
+}
+
+// checkGatewayTargets lists the tools of each gateway with created targets
+// and returns a warning for every created target that lists none, e.g.
+// because the gateway cannot invoke its Lambda. New targets can take a
+// moment to serve tools, so each listing is retried up to
+// gatewayProbeAttempts times. Like the model check, it is advisory.
+func checkGatewayTargets(
+	ctx context.Context, list gatewayToolLister, pl poller, resources []ResourceState,
+) []string {
+	if list == nil {
+		return nil
+	}
+	var warnings []string
+	for _, gw := range createdGatewayTargets(resources) {
+		if url := gatewayURL(gw.arn); url != "" {
+			warnings = append(warnings, probeGateway(ctx, list, pl, url, gw.targets)...)
+		}
+	}
+	return warnings
+}
+
+// probeGateway lists the tools of the gateway at url and returns a warning
+// for every target that lists none.
+func probeGateway(ctx context.Context, list gatewayToolLister, pl poller, url string, targets []string) []string {
+	var missing []string
+	var err error
+	for attempt := 1; ; attempt++ {
+		var tools []string
+		if tools, err = list(ctx, url); err == nil {
+			missing = targetsWithoutTools(targets, tools)
+			if len(missing) == 0 {
+				return nil
+			}
+		}
+		if attempt >= gatewayProbeAttempts || ctx.Err() != nil {
+			break
+		}
+		pl.sleep()
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("could not list the tools of gateway %s: %v", url, err)}
+	}
+	warnings := make([]string, 0, len(missing))
+	for _, name := range missing {
+		warnings = append(warnings, fmt.Sprintf(
+			"gateway target %q lists no tools; check that the gateway can invoke its backend", name))
+	}
+	return warnings
+}
+
+// gatewayProbeTargets is a gateway and the tool_gateway resources Apply created
+// on it.
+type gatewayProbeTargets struct {
+	arn     string
+	targets []string
+}
+
+// createdGatewayTargets groups the gateway targets Apply created by their
+// gateway, in the order the gateways first appear.
+func createdGatewayTargets(resources []ResourceState) []gatewayProbeTargets {
+	var gateways []gatewayProbeTargets
+	index := make(map[string]int)
+	for _, r := range resources {
+		if r.Type != ResTypeToolGateway || r.Status != ResStatusCreated {
+			continue
+		}
+		i, ok := index[r.ARN]
+		if !ok {
+			i = len(gateways)
+			index[r.ARN] = i
+			gateways = append(gateways, gatewayProbeTargets{arn: r.ARN})
+		}
+		gateways[i].targets = append(gateways[i].targets, r.Name)
+	}
+	return gateways
+}
+
+// targetsWithoutTools returns the targets that have no tool in tools. The
+// gateway lists a target's tools under its target name.
+func targetsWithoutTools(targets, tools []string) []string {
+	serving := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if target, _, ok := strings.Cut(tool, gatewayToolSeparator); ok {
+			serving[target] = true
+		}
+	}
+	var missing []string
+	for _, t := range targets {
+		if _, tool := splitGatewayTarget(t); !serving[gatewayTargetName(tool)] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+// mcpResponse is a JSON-RPC response from an MCP server.
+type mcpResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// listMCPTools is the gatewayToolLister used by NewProvider. It initializes
+// an MCP session with the gateway and pages through tools/list.
+func listMCPTools(ctx context.Context, url string) ([]string, error) {
+	initParams := map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": "promptarena-deploy-agentcore", "version": Version},
+	}
+	_, session, err := callMCP(ctx, url, "", "initialize", initParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		result, _, callErr := callMCP(ctx, url, session, "tools/list", params)
+		if callErr != nil {
+			return nil, callErr
+		}
+		var page struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err = json.Unmarshal(result, &page); err != nil {
+			return nil, fmt.Errorf("tools/list: invalid result: %w", err)
+		}
+		for _, t := range page.Tools {
+			names = append(names, t.Name)
+		}
+		if page.NextCursor == "" {
+			return names, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// callMCP sends one JSON-RPC request to an MCP server and returns its
+// result and the session ID the server assigned, if any.
+func callMCP(
+	ctx context.Context, url, session, method string, params any,
+) (json.RawMessage, string, error) {
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return nil, "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, gatewayProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if session != "" {
+		req.Header.Set(mcpSessionHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s: HTTP %d", method, resp.StatusCode)
+	}
+	data, err := readMCPBody(resp)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", method, err)
+	}
+	var rpc mcpResponse
+	if err = json.Unmarshal(data, &rpc); err != nil {
+		return nil, "", fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if rpc.Error != nil {
+		return nil, "", fmt.Errorf("%s: error %d: %s", method, rpc.Error.Code, rpc.Error.Message)
+	}
+	return rpc.Result, resp.Header.Get(mcpSessionHeader), nil
+}
+
+// readMCPBody returns the JSON-RPC message in an MCP response, which is
+// either a JSON body or the last data line of an event stream.
+func readMCPBody(resp *http.Response) ([]byte, error) {
+	body := io.LimitReader(resp.Body, maxGatewayProbeBytes)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return io.ReadAll(body)
+	}
+	var last []byte
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxGatewayProbeBytes)
+	for scanner.Scan() {
+		if data, ok := bytes.CutPrefix(scanner.Bytes(), []byte("data:")); ok {
+			last = bytes.Clone(bytes.TrimSpace(data))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, errors.New("event stream has no data")
+	}
+	return last, nil
+}
@@ -0,0 +1,171 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+const testGatewayARN = "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-1"
+
+// gatewayARNClient is an awsClient whose gateway targets report a real
+// gateway ARN, so the gateway URL can be derived.
+type gatewayARNClient struct {
+	awsClient
+}
+
+func (c *gatewayARNClient) CreateGatewayTool(context.Context, string, *Config) (string, error) {
+	return testGatewayARN, nil
+}
+
+// toolListerFor returns a gatewayToolLister that answers each call with
+// the next listing, repeating the last one.
+func toolListerFor(err error, listings ...[]string) (gatewayToolLister, *int) {
+	calls := 0
+	return func(context.Context, string) ([]string, error) {
+		calls++
+		if err != nil {
+			return nil, err
+		}
+		return listings[min(calls, len(listings))-1], nil
+	}, &calls
+}
+
+func gatewayTargets(names ...string) []ResourceState {
+	resources := make([]ResourceState, 0, len(names))
+	for _, n := range names {
+		resources = append(resources, ResourceState{
+			Type: ResTypeToolGateway, Name: n, ARN: testGatewayARN, Status: ResStatusCreated,
+		})
+	}
+	return resources
+}
+
+func TestCheckGatewayTargets(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []ResourceState
+		err       error
+		listings  [][]string
+		want      []string
+		wantCalls int
+	}{
+		{"all serving", gatewayTargets("search", "calc"), nil,
+			[][]string{{"search___search", "calc___calc"}}, nil, 1},
+		{"serving after retry", gatewayTargets("search", "calc"), nil,
+			[][]string{{"search___search"}, {"search___search", "calc___calc"}}, nil, 2},
+		{"never serving", gatewayTargets("search", "calc"), nil, [][]string{{"search___search"}},
+			[]string{`gateway target "calc" lists no tools`}, gatewayProbeAttempts},
+		{"list fails", gatewayTargets("search"), errors.New("HTTP 403"), nil,
+			[]string{"could not list the tools of gateway https://gw-1.gateway.bedrock-agentcore." +
+				"us-west-2.amazonaws.com/mcp: HTTP 403"}, gatewayProbeAttempts},
+		{"failed targets are skipped", []ResourceState{{Type: ResTypeToolGateway, Name: "search",
+			ARN: testGatewayARN, Status: ResStatusFailed}}, nil, [][]string{{}}, nil, 0},
+		{"no gateway ARN", []ResourceState{{Type: ResTypeToolGateway, Name: "search",
+			Status: ResStatusCreated}}, nil, [][]string{{}}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, calls := toolListerFor(tt.err, tt.listings...)
+			clk := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			got := checkGatewayTargets(context.Background(), list, poller{clock: clk}, tt.resources)
+			if len(got) != len(tt.want) {
+				t.Fatalf("warnings = %v, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("warnings[%d] = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("listed %d times, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestListMCPTools(t *testing.T) {
+	var listCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "initialize":
+			w.Header().Set(mcpSessionHeader, "sess-1")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26"}}`))
+		case "tools/list":
+			listCalls++
+			if r.Header.Get(mcpSessionHeader) != "sess-1" {
+				http.Error(w, "missing session", http.StatusBadRequest)
+				return
+			}
+			if req.Params["cursor"] == nil {
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` +
+					`{"tools":[{"name":"search___search"}],"nextCursor":"p2"}}`))
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "event: message\ndata: "+
+				`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"calc___calc"}]}}`+"\n\n")
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"not found"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	tools, err := listMCPTools(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("listMCPTools: %v", err)
+	}
+	if strings.Join(tools, ",") != "search___search,calc___calc" || listCalls != 2 {
+		t.Errorf("tools = %v after %d tools/list calls", tools, listCalls)
+	}
+
+	if _, _, err := callMCP(context.Background(), srv.URL, "", "ping", nil); err == nil ||
+		!strings.Contains(err.Error(), "error -32601: not found") {
+		t.Errorf("ping: err = %v, want the JSON-RPC error", err)
+	}
+}
+
+func TestApply_ProbesGatewayTargets(t *testing.T) {
+	for _, probe := range []bool{true, false} {
+		p := newSimulatedProvider()
+		p.awsClientFunc = func(_ context.Context, cfg *Config) (awsClient, error) {
+			return &gatewayARNClient{awsClient: newSimulatedAWSClient(cfg.Region)}, nil
+		}
+		list, calls := toolListerFor(nil, []string{"calc___calc", "search___search"})
+		p.gatewayListFunc = list
+
+		events, _, err := collectEvents(t, p, &deploy.PlanRequest{
+			PackJSON: singleAgentPackWithTools(),
+			DeployConfig: fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+				`"runtime_binary_path":%q,"gateway":{"probe_targets":%t}}`, testBinaryPath(t), probe),
+			ArenaConfig: validArenaConfigJSON,
+		})
+		if err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		if want := map[bool]int{true: 1, false: 0}[probe]; *calls != want {
+			t.Errorf("probe_targets=%t: listed tools %d times, want %d", probe, *calls, want)
+		}
+		for _, ev := range events {
+			if strings.Contains(ev.Message, "lists no tools") {
+				t.Errorf("unexpected warning %q when every target serves tools", ev.Message)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package agentcore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// maxGatewayTargetNameLen is the longest gateway target name AgentCore
+// accepts.
+const maxGatewayTargetNameLen = 100
+
+// targetNameHashLen is the number of hex digits of the tool name's hash a
+// sanitized target name ends in.
+const targetNameHashLen = 8
+
+// metaTargetName records, on a tool_gateway resource, the name of the
+// gateway target created for its tool.
+const metaTargetName = "target_name"
+
+// outputTargetName suffixes the output key of each tool's target name.
+const outputTargetName = ".target_name"
+
+// invalidTargetNameRE matches the runs of characters AgentCore rejects in
+// a gateway target name.
+var invalidTargetNameRE = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// gatewayTargetName returns the name the gateway target serving tool is
+// created under. A tool name AgentCore accepts is used as is. Otherwise
+// each run of rejected characters becomes "-", the name is cut to fit, and
+// a hash of the whole tool name is appended, so that tools whose names
+// sanitize alike still get targets of their own.
+func gatewayTargetName(tool string) string {
+	if len(tool) <= maxGatewayTargetNameLen && gatewayToolNameRE.MatchString(tool) {
+		return tool
+	}
+	sum := sha256.Sum256([]byte(tool))
+	hash := hex.EncodeToString(sum[:])[:targetNameHashLen]
+	name := strings.Trim(invalidTargetNameRE.ReplaceAllString(tool, "-"), "-")
+	if limit := maxGatewayTargetNameLen - len(hash) - 1; len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-")
+	}
+	if name == "" {
+		return hash
+	}
+	return name + "-" + hash
+}
+
+// resourceTargetName returns the name of the gateway target a tool_gateway
+// resource stands for, as recorded in state, or as derived from its tool
+// for state written before it was recorded.
+func resourceTargetName(res ResourceState) string {
+	if name := res.Metadata[metaTargetName]; name != "" {
+		return name
+	}
+	_, tool := splitGatewayTarget(res.Name)
+	return gatewayTargetName(tool)
+}
+
+// recordGatewayTargetNames notes the target name on each tool_gateway
+// resource created or adopted in this apply.
+func recordGatewayTargetNames(resources []ResourceState) {
+	for i := range resources {
+		r := &resources[i]
+		if r.Type != ResTypeToolGateway || r.Status == ResStatusFailed {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = map[string]string{}
+		}
+		_, tool := splitGatewayTarget(r.Name)
+		r.Metadata[metaTargetName] = gatewayTargetName(tool)
+	}
+}
+
+// gatewayTargetOutputs maps "{tool}.target_name" to the target name of
+// each deployed tool_gateway resource, so clients can tell the
+// "<target>___<tool>" names the gateway lists its tools under.
+func gatewayTargetOutputs(resources []ResourceState) map[string]string {
+	outputs := make(map[string]string)
+	for _, r := range resources {
+		if r.Type == ResTypeToolGateway && r.Status != ResStatusFailed {
+			outputs[r.Name+outputTargetName] = resourceTargetName(r)
+		}
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+	return outputs
+}
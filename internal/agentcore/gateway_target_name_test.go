@@ -0,0 +1,82 @@
+package agentcore
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// awsTargetNameRE matches the gateway target names AgentCore accepts.
+var awsTargetNameRE = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,100}$`)
+
+func TestGatewayTargetName(t *testing.T) {
+	for _, tool := range []string{"lookup_order", "web-search", "a"} {
+		if got := gatewayTargetName(tool); got != tool {
+			t.Errorf("gatewayTargetName(%q) = %q, want it unchanged", tool, got)
+		}
+	}
+
+	dotted, coloned := gatewayTargetName("crm.lookup"), gatewayTargetName("crm:lookup")
+	if !strings.HasPrefix(dotted, "crm-lookup-") || !strings.HasPrefix(coloned, "crm-lookup-") || dotted == coloned {
+		t.Errorf("targets = %q and %q, want distinct crm-lookup-<hash> names", dotted, coloned)
+	}
+	if gatewayTargetName("crm.lookup") != dotted {
+		t.Error("gatewayTargetName is not deterministic")
+	}
+
+	long := strings.Repeat("x", maxGatewayTargetNameLen)
+	for _, tool := range []string{long + "a", long + "b", "  ..  ", "tool with spaces " + long} {
+		got := gatewayTargetName(tool)
+		if !awsTargetNameRE.MatchString(got) || strings.HasSuffix(got, "-") {
+			t.Errorf("gatewayTargetName(%q) = %q, want a valid target name", tool, got)
+		}
+	}
+	if gatewayTargetName(long+"a") == gatewayTargetName(long+"b") {
+		t.Error("truncated names of different tools collide")
+	}
+}
+
+func TestRecordGatewayTargetNames(t *testing.T) {
+	resources := []ResourceState{
+		{Type: ResTypeToolGateway, Name: "crm.lookup", ARN: "arn:gw", Status: ResStatusCreated},
+		{Type: ResTypeToolGateway, Name: "worker/search", ARN: "arn:gw-w", Status: ResStatusCreated},
+		{Type: ResTypeToolGateway, Name: "broken", Status: ResStatusFailed},
+	}
+	recordGatewayTargetNames(resources)
+
+	want := gatewayTargetName("crm.lookup")
+	if got := resources[0].Metadata[metaTargetName]; got != want {
+		t.Errorf("target_name = %q, want %q", got, want)
+	}
+	if resources[2].Metadata != nil {
+		t.Errorf("failed target metadata = %v, want none", resources[2].Metadata)
+	}
+	outputs := gatewayTargetOutputs(resources)
+	if len(outputs) != 2 || outputs["crm.lookup.target_name"] != want ||
+		outputs["worker/search.target_name"] != "search" {
+		t.Errorf("outputs = %v", outputs)
+	}
+	if got := resourceTargetName(ResourceState{Name: "worker/crm.lookup"}); got != want {
+		t.Errorf("target of state without metadata = %q, want %q", got, want)
+	}
+}
+
+func TestCreateGatewayTool_SanitizedTargetName(t *testing.T) {
+	c, rec := newRecordingRealClient(`{"gatewayArn":"arn:aws:bedrock-agentcore:us-west-2:1:gateway/gw-1"}`)
+	c.gateways = map[string]*toolGateway{"": {id: "gw-1", arn: "arn:aws:bedrock-agentcore:us-west-2:1:gateway/gw-1"}}
+
+	if _, err := c.CreateGatewayTool(context.Background(), "crm.lookup", &Config{}); err != nil {
+		t.Fatalf("CreateGatewayTool: %v", err)
+	}
+	if len(rec.requests) != 1 || !strings.Contains(rec.requests[0], `"name":"`+gatewayTargetName("crm.lookup")+`"`) {
+		t.Errorf("requests = %v, want CreateGatewayTarget with the sanitized name", rec.requests)
+	}
+}
+
+func TestCedarToolBlocklist_TargetName(t *testing.T) {
+	got := cedarToolBlocklist("crm.lookup", "", "")
+	if want := `AgentCore::Action::"` + gatewayTargetName("crm.lookup") + `___crm.lookup"`; !strings.Contains(got, want) {
+		t.Errorf("statement = %s, want action %s", got, want)
+	}
+}
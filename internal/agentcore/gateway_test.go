@@ -608,3 +608,33 @@ func TestMapRestAPIMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGateway(t *testing.T) {
+	for searchType, wantErr := range map[string]bool{
+		"": false, GatewaySearchSemantic: false, GatewaySearchNone: false, "keyword": true,
+	} {
+		if errs := validateGateway(&GatewayConfig{SearchType: searchType}); (len(errs) > 0) != wantErr {
+			t.Errorf("search_type %q: errors = %v, wantErr %v", searchType, errs, wantErr)
+		}
+	}
+}
+
+func TestBuildGatewayProtocolConfig(t *testing.T) {
+	if pc := buildGatewayProtocolConfig(&Config{Gateway: &GatewayConfig{SearchType: GatewaySearchNone}}); pc != nil {
+		t.Errorf("search_type none: protocol config = %+v, want nil", pc)
+	}
+
+	pc := buildGatewayProtocolConfig(&Config{Gateway: &GatewayConfig{
+		SearchType: GatewaySearchSemantic, Instructions: "Search before calling tools.",
+	}})
+	mcp, ok := pc.(*types.GatewayProtocolConfigurationMemberMcp)
+	if !ok {
+		t.Fatalf("protocol config = %T, want MCP", pc)
+	}
+	if mcp.Value.SearchType != types.SearchTypeSemantic {
+		t.Errorf("search type = %q, want SEMANTIC", mcp.Value.SearchType)
+	}
+	if mcp.Value.Instructions == nil || *mcp.Value.Instructions != "Search before calling tools." {
+		t.Errorf("instructions = %v", mcp.Value.Instructions)
+	}
+}
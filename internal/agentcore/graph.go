@@ -0,0 +1,396 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// MethodGraph is the JSON-RPC method that renders the deployment topology
+// as DOT or Mermaid text. It extends the standard adaptersdk method set.
+const MethodGraph = "graph"
+
+// Graph output formats.
+const (
+	GraphFormatMermaid = "mermaid"
+	GraphFormatDOT     = "dot"
+)
+
+// Graph sources: the resources Plan would deploy, or those in state.
+const (
+	graphSourcePlan  = "plan"
+	graphSourceState = "state"
+)
+
+// graphGatewayID is the node ID of the shared tool gateway that fronts
+// every gateway target. Per-agent gateways get the agent name appended.
+const graphGatewayID = "tool_gateway"
+
+// Edge labels of the topology graph.
+const (
+	graphEdgeA2A       = "a2a"
+	graphEdgeMemory    = "memory"
+	graphEdgeTools     = "tools"
+	graphEdgeTarget    = "target"
+	graphEdgeOAuth     = "oauth"
+	graphEdgePolicy    = "policy"
+	graphEdgeModel     = "model"
+	graphEdgeLogs      = "logs"
+	graphEdgeEndpoint  = "endpoint"
+	graphEdgeEvaluator = "evaluator"
+	graphEdgeTraces    = "traces"
+)
+
+// graphIDUnsafe matches characters not allowed in a graph node ID.
+var graphIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// GraphRequest is the params object of a graph call. Without Deployed the
+// graph shows what Plan would deploy for PackJSON, with each resource's
+// action against PriorState when one is given. With Deployed it shows the
+// resources in PriorState with their status; PackJSON is then optional and
+// only supplies the A2A entry agent.
+type GraphRequest struct {
+	PackJSON     string `json:"pack_json,omitempty"`
+	DeployConfig string `json:"deploy_config,omitempty"`
+	ArenaConfig  string `json:"arena_config,omitempty"`
+	PriorState   string `json:"prior_state,omitempty"`
+	Deployed     bool   `json:"deployed,omitempty"`
+	Format       string `json:"format,omitempty"` // "mermaid" (default) or "dot"
+}
+
+// GraphResponse is the result of a graph call.
+type GraphResponse struct {
+	Format string `json:"format"`
+	Source string `json:"source"` // "plan" or "state"
+	Graph  string `json:"graph"`
+	Nodes  int    `json:"nodes"`
+	Edges  int    `json:"edges"`
+}
+
+// graphResource is one resource drawn in the graph, with its planned
+// action or deployed status.
+type graphResource struct {
+	Type  string
+	Name  string
+	State string
+}
+
+// graphNode is a box in the rendered graph.
+type graphNode struct {
+	ID    string
+	Label string
+}
+
+// graphEdge connects two nodes by ID.
+type graphEdge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// topology is the graph of a deployment's resources.
+type topology struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+// graphInputs is what the topology is built from.
+type graphInputs struct {
+	resources []graphResource
+	cfg       *Config
+	packName  string // names the shared inference profiles
+	entry     string // the A2A entry agent; "" for a single agent
+}
+
+// Graph renders the planned or deployed resource graph: runtimes, the tool
+// gateway and its targets, identity providers, Cedar policies, memory,
+// inference profiles, evaluators, endpoints, log groups, and the A2A edges
+// from the entry agent to the other members. It calls no AWS APIs.
+func (p *Provider) Graph(_ context.Context, req *GraphRequest) (*GraphResponse, error) {
+	format := req.Format
+	if format == "" {
+		format = GraphFormatMermaid
+	}
+	if format != GraphFormatMermaid && format != GraphFormatDOT {
+		return nil, fmt.Errorf("agentcore: graph format %q must be %q or %q", format, GraphFormatMermaid, GraphFormatDOT)
+	}
+	in, source, err := p.graphInputs(req)
+	if err != nil {
+		return nil, err
+	}
+	topo := buildTopology(in)
+	out := &GraphResponse{Format: format, Source: source, Nodes: len(topo.nodes), Edges: len(topo.edges)}
+	if format == GraphFormatDOT {
+		out.Graph = topo.dot()
+	} else {
+		out.Graph = topo.mermaid()
+	}
+	return out, nil
+}
+
+// graphInputs parses the request into the resources to draw and the
+// context their edges are derived from, and reports the graph's source.
+func (p *Provider) graphInputs(req *GraphRequest) (*graphInputs, string, error) {
+	raw := req.DeployConfig
+	if raw == "" {
+		raw = "{}"
+	}
+	cfg, err := p.loadConfig(raw, graphConfigVars(req))
+	if err != nil {
+		return nil, "", fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
+	}
+	if cfg.ArenaConfig, err = parseOptionalArenaConfig(req.ArenaConfig); err != nil {
+		return nil, "", fmt.Errorf("agentcore: %w", err)
+	}
+	var prior *AdapterState
+	if req.PriorState != "" {
+		prior = &AdapterState{}
+		if err = json.Unmarshal([]byte(req.PriorState), prior); err != nil {
+			return nil, "", fmt.Errorf("agentcore: failed to parse prior state: %w", err)
+		}
+	}
+	var pack *prompt.Pack
+	if req.PackJSON != "" {
+		if pack, err = parsePack(req.PackJSON); err != nil {
+			return nil, "", fmt.Errorf("agentcore: failed to parse pack: %w", err)
+		}
+	}
+
+	in := &graphInputs{cfg: cfg}
+	if pack != nil {
+		in.packName = runtimeProfilePackName(pack)
+		if adaptersdk.IsMultiAgent(pack) {
+			in.entry = pack.Agents.Entry
+		}
+	}
+	if req.Deployed {
+		if prior == nil {
+			return nil, "", fmt.Errorf("agentcore: a deployed graph requires prior_state")
+		}
+		if pack == nil {
+			in.packName = prior.PackID
+		}
+		for _, r := range prior.Resources {
+			in.resources = append(in.resources, graphResource{Type: r.Type, Name: r.Name, State: r.Status})
+		}
+		return in, graphSourceState, nil
+	}
+	if pack == nil {
+		return nil, "", fmt.Errorf("agentcore: a planned graph requires pack_json")
+	}
+	for _, c := range diffResources(generateDesiredResources(pack, cfg), prior, cfg) {
+		in.resources = append(in.resources, graphResource{Type: c.Type, Name: c.Name, State: string(c.Action)})
+	}
+	return in, graphSourcePlan, nil
+}
+
+// buildTopology lays out the resources as nodes and derives the edges
+// between them from the adapter's naming conventions. A2A endpoints are
+// drawn as edges between runtimes rather than as nodes.
+func buildTopology(in *graphInputs) *topology {
+	topo := &topology{}
+	byType := make(map[string][]graphResource)
+	for _, r := range in.resources {
+		if r.Type == ResTypeA2AEndpoint || !slices.Contains(supportedResourceTypes, r.Type) {
+			continue
+		}
+		byType[r.Type] = append(byType[r.Type], r)
+		label := r.Type + "\n" + r.Name
+		if r.State != "" {
+			label += " (" + r.State + ")"
+		}
+		topo.nodes = append(topo.nodes, graphNode{ID: graphNodeID(r.Type, r.Name), Label: label})
+	}
+	for _, agent := range graphGatewayAgents(byType[ResTypeToolGateway]) {
+		label := "tool gateway"
+		if agent != "" {
+			label += "\n" + agent
+		}
+		topo.nodes = append(topo.nodes, graphNode{ID: graphGatewayNodeID(agent), Label: label})
+	}
+
+	topo.addRuntimeEdges(byType, in)
+	topo.addGatewayEdges(byType, in.cfg)
+	topo.addEvalEdges(byType, in.packName)
+	return topo
+}
+
+// addRuntimeEdges connects each runtime to the memory, tool gateway,
+// inference profile, log group, and endpoint it uses, and the entry agent
+// to the other members.
+func (t *topology) addRuntimeEdges(byType map[string][]graphResource, in *graphInputs) {
+	runtimes := byType[ResTypeAgentRuntime]
+	for _, r := range runtimes {
+		id := graphNodeID(r.Type, r.Name)
+		if in.entry != "" && r.Name != in.entry && hasGraphResource(runtimes, in.entry) {
+			t.connect(graphNodeID(ResTypeAgentRuntime, in.entry), id, graphEdgeA2A)
+		}
+		for _, m := range byType[ResTypeMemory] {
+			t.connect(id, graphNodeID(m.Type, m.Name), graphEdgeMemory)
+		}
+		if gw, ok := graphGatewayFor(byType[ResTypeToolGateway], r.Name); ok {
+			t.connect(id, gw, graphEdgeTools)
+		}
+		for _, ip := range byType[ResTypeInferenceProfile] {
+			if strings.HasSuffix(ip.Name, runtimeProfileSuffix) {
+				t.connect(id, graphNodeID(ip.Type, ip.Name), graphEdgeModel)
+			}
+		}
+		if hasGraphResource(byType[ResTypeLogGroup], r.Name) {
+			t.connect(id, graphNodeID(ResTypeLogGroup, r.Name), graphEdgeLogs)
+		}
+		if hasGraphResource(byType[ResTypeRuntimeEndpoint], r.Name) {
+			t.connect(graphNodeID(ResTypeRuntimeEndpoint, r.Name), id, graphEdgeEndpoint)
+		}
+	}
+}
+
+// addGatewayEdges connects each tool gateway to its targets, each target
+// to the identity provider it authenticates through, and each Cedar policy
+// to the gateway it is enforced on.
+func (t *topology) addGatewayEdges(byType map[string][]graphResource, cfg *Config) {
+	for _, target := range byType[ResTypeToolGateway] {
+		id := graphNodeID(target.Type, target.Name)
+		agent, tool := splitGatewayTarget(strings.TrimSuffix(target.Name, toolGatewaySuffix))
+		t.connect(graphGatewayNodeID(agent), id, graphEdgeTarget)
+		if idp := identityProviderNameForTool(cfg, tool); hasGraphResource(byType[ResTypeIdentityProvider], idp) {
+			t.connect(id, graphNodeID(ResTypeIdentityProvider, idp), graphEdgeOAuth)
+		}
+	}
+	for _, p := range byType[ResTypeCedarPolicy] {
+		if gw, ok := graphGatewayFor(byType[ResTypeToolGateway], p.Name); ok {
+			t.connect(graphNodeID(p.Type, p.Name), gw, graphEdgePolicy)
+		}
+	}
+}
+
+// graphGatewayAgents returns the sorted gateway partitions of the tool
+// gateway targets: "" for the shared gateway and an agent name for each
+// per-agent gateway.
+func graphGatewayAgents(targets []graphResource) []string {
+	var agents []string
+	for _, target := range targets {
+		agent, _ := splitGatewayTarget(target.Name)
+		if !slices.Contains(agents, agent) {
+			agents = append(agents, agent)
+		}
+	}
+	slices.Sort(agents)
+	return agents
+}
+
+// graphGatewayFor returns the node ID of the gateway serving agent: its own
+// when it has one, otherwise the shared gateway. ok is false when neither
+// exists.
+func graphGatewayFor(targets []graphResource, agent string) (string, bool) {
+	agents := graphGatewayAgents(targets)
+	switch {
+	case slices.Contains(agents, agent):
+		return graphGatewayNodeID(agent), true
+	case slices.Contains(agents, ""):
+		return graphGatewayID, true
+	}
+	return "", false
+}
+
+// graphGatewayNodeID returns the node ID of the gateway of agent, or of the
+// shared gateway when agent is "".
+func graphGatewayNodeID(agent string) string {
+	if agent == "" {
+		return graphGatewayID
+	}
+	return graphIDUnsafe.ReplaceAllString(graphGatewayID+"_"+agent, "_")
+}
+
+// addEvalEdges connects the online eval config to the evaluators it runs
+// and the runtimes whose traces it reads, and each evaluator to its
+// inference profile.
+func (t *topology) addEvalEdges(byType map[string][]graphResource, packName string) {
+	profiles := byType[ResTypeInferenceProfile]
+	for _, ev := range byType[ResTypeEvaluator] {
+		profile := strings.TrimSuffix(ev.Name, evalResourceSuffix) + evalProfileSuffix
+		if !hasGraphResource(profiles, profile) {
+			profile = packName + evalProfileSuffix
+		}
+		if hasGraphResource(profiles, profile) {
+			t.connect(graphNodeID(ev.Type, ev.Name), graphNodeID(ResTypeInferenceProfile, profile), graphEdgeModel)
+		}
+	}
+	for _, oe := range byType[ResTypeOnlineEvalConfig] {
+		id := graphNodeID(oe.Type, oe.Name)
+		for _, ev := range byType[ResTypeEvaluator] {
+			t.connect(id, graphNodeID(ev.Type, ev.Name), graphEdgeEvaluator)
+		}
+		for _, r := range byType[ResTypeAgentRuntime] {
+			t.connect(id, graphNodeID(r.Type, r.Name), graphEdgeTraces)
+		}
+	}
+}
+
+// connect adds an edge.
+func (t *topology) connect(from, to, label string) {
+	t.edges = append(t.edges, graphEdge{From: from, To: to, Label: label})
+}
+
+// mermaid renders the topology as a Mermaid flowchart.
+func (t *topology) mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, n := range t.nodes {
+		label := strings.ReplaceAll(n.Label, `"`, "#quot;")
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", n.ID, strings.ReplaceAll(label, "\n", "<br/>"))
+	}
+	for _, e := range t.edges {
+		fmt.Fprintf(&sb, "  %s -->|%s| %s\n", e.From, e.Label, e.To)
+	}
+	return sb.String()
+}
+
+// dot renders the topology as a Graphviz digraph.
+func (t *topology) dot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph agentcore {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range t.nodes {
+		fmt.Fprintf(&sb, "  %s [label=%s];\n", n.ID, dotQuote(n.Label))
+	}
+	for _, e := range t.edges {
+		fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", e.From, e.To, dotQuote(e.Label))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotQuote quotes s as a DOT string, with newlines as line breaks.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// graphNodeID returns the node ID of a resource, safe to use unquoted in
+// both DOT and Mermaid.
+func graphNodeID(resType, name string) string {
+	return graphIDUnsafe.ReplaceAllString(resType+"__"+name, "_")
+}
+
+// hasGraphResource reports whether resources includes one named name.
+func hasGraphResource(resources []graphResource, name string) bool {
+	return name != "" && slices.ContainsFunc(resources, func(r graphResource) bool { return r.Name == name })
+}
+
+// identityProviderNameForTool returns the resource name of the identity
+// provider a tool authenticates through, or "" when it has none.
+func identityProviderNameForTool(cfg *Config, tool string) string {
+	for _, k := range sortedKeys(cfg.IdentityProviders) {
+		if p := cfg.IdentityProviders[k]; p != nil && slices.Contains(p.Tools, tool) {
+			return k + identityProviderSuffix
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGraph_PlannedMermaid(t *testing.T) {
+	cfg := strings.TrimSuffix(validConfigWithMemory(t), "}") + `,"identity_providers":{"google":{` +
+		`"vendor":"GoogleOauth2","client_id":"id","client_secret_env":"GOOGLE_SECRET","tools":["lookup"]}}}`
+	resp, err := newSimulatedProvider().Graph(context.Background(), &GraphRequest{
+		PackJSON: multiAgentPack(), DeployConfig: cfg,
+	})
+	if err != nil {
+		t.Fatalf("Graph: %v", err)
+	}
+	if resp.Format != GraphFormatMermaid || resp.Source != graphSourcePlan {
+		t.Errorf("format = %q, source = %q", resp.Format, resp.Source)
+	}
+	for _, want := range []string{
+		"flowchart LR\n",
+		`agent_runtime__coordinator["agent_runtime<br/>coordinator (CREATE)"]`,
+		"agent_runtime__coordinator -->|a2a| agent_runtime__worker",
+		"agent_runtime__worker -->|memory| memory__multipack_memory",
+		"agent_runtime__worker -->|tools| tool_gateway\n",
+		"tool_gateway -->|target| tool_gateway__lookup_tool_gw",
+		"tool_gateway__lookup_tool_gw -->|oauth| identity_provider__google_identity",
+	} {
+		if !strings.Contains(resp.Graph, want) {
+			t.Errorf("graph missing %q:\n%s", want, resp.Graph)
+		}
+	}
+	if strings.Contains(resp.Graph, "a2a_endpoint") || strings.Contains(resp.Graph, "worker -->|a2a|") {
+		t.Errorf("graph draws A2A endpoints as nodes or from a member:\n%s", resp.Graph)
+	}
+	if resp.Nodes != 6 || resp.Edges != 7 {
+		t.Errorf("nodes = %d, edges = %d, want 6 and 7:\n%s", resp.Nodes, resp.Edges, resp.Graph)
+	}
+}
+
+func TestGraph_DeployedDOT(t *testing.T) {
+	state := mustJSON(t, &AdapterState{PackID: "evalpack", Resources: []ResourceState{
+		{Type: ResTypeInferenceProfile, Name: "evalpack_eval_profile", Status: "created"},
+		{Type: ResTypeAgentRuntime, Name: "evalpack", Status: "created"},
+		{Type: ResTypeRuntimeEndpoint, Name: "evalpack", Status: "created"},
+		{Type: ResTypeEvaluator, Name: "quality_eval", Status: "failed"},
+		{Type: ResTypeOnlineEvalConfig, Name: "evalpack_online_eval", Status: "created"},
+	}})
+	resp, err := newSimulatedProvider().Graph(context.Background(), &GraphRequest{
+		PriorState: state, Deployed: true, Format: GraphFormatDOT,
+	})
+	if err != nil {
+		t.Fatalf("Graph: %v", err)
+	}
+	if resp.Source != graphSourceState {
+		t.Errorf("source = %q, want state", resp.Source)
+	}
+	for _, want := range []string{
+		"digraph agentcore {\n",
+		`evaluator__quality_eval [label="evaluator\nquality_eval (failed)"];`,
+		`runtime_endpoint__evalpack -> agent_runtime__evalpack [label="endpoint"];`,
+		`evaluator__quality_eval -> inference_profile__evalpack_eval_profile [label="model"];`,
+		`online_eval_config__evalpack_online_eval -> agent_runtime__evalpack [label="traces"];`,
+	} {
+		if !strings.Contains(resp.Graph, want) {
+			t.Errorf("graph missing %q:\n%s", want, resp.Graph)
+		}
+	}
+}
+
+func TestGraph_InvalidRequests(t *testing.T) {
+	p := newSimulatedProvider()
+	tests := []struct {
+		name    string
+		req     *GraphRequest
+		wantErr string
+	}{
+		{"unknown format", &GraphRequest{PackJSON: singleAgentPack(), Format: "svg"}, `graph format "svg"`},
+		{"plan without pack", &GraphRequest{}, "requires pack_json"},
+		{"deployed without state", &GraphRequest{PackJSON: singleAgentPack(), Deployed: true}, "requires prior_state"},
+		{"bad state", &GraphRequest{PackJSON: singleAgentPack(), PriorState: "{"}, "failed to parse prior state"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.Graph(context.Background(), tt.req); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphNodeID(t *testing.T) {
+	if got := graphNodeID(ResTypeAgentRuntime, "my-agent.v2"); got != "agent_runtime__my_agent_v2" {
+		t.Errorf("graphNodeID = %q", got)
+	}
+}
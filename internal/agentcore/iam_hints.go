@@ -0,0 +1,151 @@
+package agentcore
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// missingPermissionsPrefix starts the progress message that sums up the
+// IAM permissions AWS denied during an Apply or Destroy.
+const missingPermissionsPrefix = "Missing IAM permissions: "
+
+// accessDeniedRE matches the action, and the principal and resource when
+// given, of an AccessDenied message such as "User: arn:aws:sts::123:
+// assumed-role/Deployer/s is not authorized to perform:
+// bedrock-agentcore:CreateAgentRuntime on resource: arn:... because no
+// identity-based policy allows ...".
+var accessDeniedRE = regexp.MustCompile(
+	`(?:User: (\S+) is )?not authorized to perform: ([\w-]+:\w+)(?: on resource: (\S+))?`)
+
+// missingPermission is an IAM action AWS denied, parsed from the message
+// of an AccessDenied error.
+type missingPermission struct {
+	principal string
+	action    string
+	resource  string
+}
+
+// parseMissingPermission returns the permission an AccessDenied message
+// reports as missing, and false when msg names no action.
+func parseMissingPermission(msg string) (missingPermission, bool) {
+	m := accessDeniedRE.FindStringSubmatch(msg)
+	if m == nil {
+		return missingPermission{}, false
+	}
+	return missingPermission{
+		principal: m[1],
+		action:    m[2],
+		resource:  strings.TrimRight(m[3], ".,;)"),
+	}, true
+}
+
+// identity names the IAM identity that needs the permission: "role NAME"
+// for a role or assumed role, "user NAME" for a user, and the deploying
+// identity when AWS did not say.
+func (m missingPermission) identity() string {
+	if m.principal == "" {
+		return "the deploying identity"
+	}
+	arn, ok := parseARN(m.principal)
+	if !ok {
+		return m.principal
+	}
+	kind, rest, _ := strings.Cut(arn.Resource, "/")
+	switch kind {
+	case "assumed-role":
+		role, _, _ := strings.Cut(rest, "/")
+		return "role " + role
+	case "role", "user":
+		return kind + " " + path.Base(rest)
+	}
+	return m.principal
+}
+
+// grant describes the action and the resource it is needed on.
+func (m missingPermission) grant() string {
+	if m.resource == "" {
+		return m.action
+	}
+	return m.action + " on " + m.resource
+}
+
+// hint is the remediation of the AccessDenied error m came from.
+func (m missingPermission) hint() string {
+	return fmt.Sprintf("allow %s in a policy attached to %s", m.grant(), m.identity())
+}
+
+// permissionLog collects the permissions AWS denied in the error events
+// of an Apply or Destroy, for the summary at its end. It is safe for
+// concurrent use.
+type permissionLog struct {
+	mu      sync.Mutex
+	missing []missingPermission
+}
+
+// record adds the permission the error message msg reports as missing,
+// if any, unless it is already recorded.
+func (l *permissionLog) record(msg string) {
+	m, ok := parseMissingPermission(msg)
+	if !ok {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, seen := range l.missing {
+		if seen.identity() == m.identity() && seen.grant() == m.grant() {
+			return
+		}
+	}
+	l.missing = append(l.missing, m)
+}
+
+// applyCallback wraps callback so the log records the error events it
+// receives.
+func (l *permissionLog) applyCallback(callback deploy.ApplyCallback) deploy.ApplyCallback {
+	return func(ev *deploy.ApplyEvent) error {
+		if ev.Type == "error" {
+			l.record(ev.Message)
+		}
+		return callback(ev)
+	}
+}
+
+// destroyCallback wraps callback so the log records the error events it
+// receives.
+func (l *permissionLog) destroyCallback(callback deploy.DestroyCallback) deploy.DestroyCallback {
+	return func(ev *deploy.DestroyEvent) error {
+		if ev.Type == "error" {
+			l.record(ev.Message)
+		}
+		return callback(ev)
+	}
+}
+
+// summary lists every recorded permission by the identity that needs it,
+// in the order they were denied, or returns "" when none was.
+func (l *permissionLog) summary() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.missing) == 0 {
+		return ""
+	}
+	var identities []string
+	grants := make(map[string][]string)
+	for _, m := range l.missing {
+		id := m.identity()
+		if _, ok := grants[id]; !ok {
+			identities = append(identities, id)
+		}
+		grants[id] = append(grants[id], m.grant())
+	}
+	parts := make([]string, len(identities))
+	for i, id := range identities {
+		parts[i] = fmt.Sprintf("%s needs %s", id, strings.Join(grants[id], ", "))
+	}
+	return missingPermissionsPrefix + strings.Join(parts, "; ")
+}
@@ -0,0 +1,121 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/smithy-go"
+)
+
+const createRuntimeDenied = "User: arn:aws:sts::123456789012:assumed-role/Deployer/ci is not authorized to perform: " +
+	"bedrock-agentcore:CreateAgentRuntime on resource: arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/* " +
+	"because no identity-based policy allows the bedrock-agentcore:CreateAgentRuntime action"
+
+func TestParseMissingPermission(t *testing.T) {
+	for _, tt := range []struct {
+		msg, wantHint string
+	}{
+		{createRuntimeDenied, "allow bedrock-agentcore:CreateAgentRuntime on " +
+			"arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/* in a policy attached to role Deployer"},
+		{"User: arn:aws:iam::123456789012:user/ops/alice is not authorized to perform: iam:PassRole " +
+			"on resource: arn:aws:iam::123456789012:role/AgentRuntime.",
+			"allow iam:PassRole on arn:aws:iam::123456789012:role/AgentRuntime in a policy attached to user alice"},
+		{"You are not authorized to perform: logs:CreateLogGroup",
+			"allow logs:CreateLogGroup in a policy attached to the deploying identity"},
+	} {
+		m, ok := parseMissingPermission(tt.msg)
+		if !ok || m.hint() != tt.wantHint {
+			t.Errorf("parseMissingPermission(%q) hint = %q, %v; want %q", tt.msg, m.hint(), ok, tt.wantHint)
+		}
+	}
+	if _, ok := parseMissingPermission("Access denied while validating the role"); ok {
+		t.Error("parsed a denial that names no action")
+	}
+}
+
+func TestNewDeployError_AccessDeniedHint(t *testing.T) {
+	err := newDeployError("create", ResTypeAgentRuntime, "mypack", fmt.Errorf("CreateAgentRuntime: %w",
+		&smithy.GenericAPIError{Code: errCodeAccessDenied, Message: createRuntimeDenied}))
+	if err.Category != ErrCategoryPermission || !strings.HasPrefix(err.Remediation,
+		"allow bedrock-agentcore:CreateAgentRuntime on ") {
+		t.Errorf("DeployError = %+v, want a targeted permission hint", err)
+	}
+
+	err = newDeployError("create", ResTypeAgentRuntime, "mypack",
+		&smithy.GenericAPIError{Code: errCodeAccessDenied, Message: "Access denied"})
+	if err.Remediation != hintCheckIAM {
+		t.Errorf("remediation = %q, want the generic IAM hint", err.Remediation)
+	}
+}
+
+func TestPermissionLog_Summary(t *testing.T) {
+	var l permissionLog
+	if got := l.summary(); got != "" {
+		t.Errorf("empty summary = %q", got)
+	}
+	passRole := "User: arn:aws:sts::123456789012:assumed-role/Deployer/other is not authorized to perform: " +
+		"iam:PassRole on resource: arn:aws:iam::123456789012:role/AgentRuntime"
+	for _, msg := range []string{createRuntimeDenied, "simulated failure", passRole, createRuntimeDenied,
+		"You are not authorized to perform: s3:PutObject"} {
+		l.record(msg)
+	}
+	want := missingPermissionsPrefix + "role Deployer needs bedrock-agentcore:CreateAgentRuntime on " +
+		"arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/*, iam:PassRole on " +
+		"arn:aws:iam::123456789012:role/AgentRuntime; the deploying identity needs s3:PutObject"
+	if got := l.summary(); got != want {
+		t.Errorf("summary =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// deniedDestroyer denies every delete.
+type deniedDestroyer struct{}
+
+// deleteActions are the IAM actions deniedDestroyer reports per type.
+var deleteActions = map[string]string{
+	ResTypeToolGateway:  "bedrock-agentcore:DeleteGateway",
+	ResTypeAgentRuntime: "bedrock-agentcore:DeleteAgentRuntime",
+	ResTypeA2AEndpoint:  "bedrock-agentcore:DeleteAgentRuntimeEndpoint",
+	ResTypeEvaluator:    "bedrock-agentcore:DeleteEvaluator",
+}
+
+func (deniedDestroyer) DeleteResource(_ context.Context, res ResourceState) error {
+	return fmt.Errorf("delete %s: %w", res.Name, &smithy.GenericAPIError{Code: errCodeAccessDenied,
+		Message: "User: arn:aws:sts::123456789012:assumed-role/Deployer/ci is not authorized to perform: " +
+			deleteActions[res.Type] + " on resource: " + res.ARN})
+}
+
+func TestDestroy_MissingPermissionsSummary(t *testing.T) {
+	p := newSimulatedProvider()
+	p.destroyerFunc = func(context.Context, *Config) (resourceDestroyer, error) { return deniedDestroyer{}, nil }
+	var summary, hint string
+	err := p.Destroy(context.Background(), &deploy.DestroyRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   mustJSON(t, sampleState()),
+	}, func(ev *deploy.DestroyEvent) error {
+		switch {
+		case EventCode(ev.Type, ev.Message, ev.Resource) == EventCodeMissingPermissions:
+			summary = ev.Message
+		case ev.Type == "error" && strings.Contains(ev.Message, "rt-1"):
+			hint = ev.Message
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if !strings.Contains(hint, "[hint: allow bedrock-agentcore:DeleteAgentRuntime on "+
+		"arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/rt-1 in a policy attached to role Deployer]") {
+		t.Errorf("error event = %q, want a targeted hint", hint)
+	}
+	if !strings.HasPrefix(summary, missingPermissionsPrefix+"role Deployer needs ") {
+		t.Errorf("summary = %q", summary)
+	}
+	for _, res := range sampleState().Resources {
+		if !strings.Contains(summary, deleteActions[res.Type]+" on "+res.ARN) {
+			t.Errorf("summary %q is missing the delete of %s", summary, res.Name)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// iamAPIVersion is the version of the IAM query API.
+const iamAPIVersion = "2010-05-08"
+
+// agentCoreServicePrincipal is the service principal AgentCore assumes the
+// runtime role as.
+const agentCoreServicePrincipal = "bedrock-agentcore.amazonaws.com"
+
+// errRoleNotFound reports an IAM role that does not exist.
+var errRoleNotFound = errors.New("role does not exist")
+
+// iamRoleReader reads IAM roles with the IAM query API. GetRole is the
+// only IAM call the adapter makes, so it signs the request itself rather
+// than depend on the IAM SDK module.
+type iamRoleReader struct {
+	endpoint      string
+	signingRegion string
+	http          *sigV4Client
+}
+
+// newIAMRoleReader returns an iamRoleReader calling as awsCfg's
+// credentials.
+func newIAMRoleReader(awsCfg aws.Config, cfg *Config) *iamRoleReader {
+	endpoint, region := cfg.iamEndpoint()
+	return &iamRoleReader{endpoint: endpoint, signingRegion: region, http: newSigV4Client(awsCfg)}
+}
+
+// iamGetRoleResponse is the part of the GetRole response the adapter reads.
+type iamGetRoleResponse struct {
+	AssumeRolePolicyDocument string `xml:"GetRoleResult>Role>AssumeRolePolicyDocument"`
+}
+
+// iamErrorResponse is the error body of the IAM query API.
+type iamErrorResponse struct {
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// trustPolicy returns the trust policy document of the role roleARN names.
+func (r *iamRoleReader) trustPolicy(ctx context.Context, roleARN string) (string, error) {
+	roleName := roleARN[strings.LastIndex(roleARN, "/")+1:]
+	body := url.Values{
+		"Action": {"GetRole"}, "RoleName": {roleName}, "Version": {iamAPIVersion},
+	}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	resp, err := r.http.do(ctx, req, []byte(body), "iam", r.signingRegion)
+	if err != nil {
+		return "", fmt.Errorf("IAM GetRole %q: %w", roleName, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("IAM GetRole %q: %w", roleName, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr iamErrorResponse
+		_ = xml.Unmarshal(data, &apiErr)
+		if apiErr.Code == "NoSuchEntity" {
+			return "", fmt.Errorf("IAM role %q: %w", roleName, errRoleNotFound)
+		}
+		return "", fmt.Errorf("IAM GetRole %q: %s %s (HTTP %d)", roleName, apiErr.Code, apiErr.Message,
+			resp.StatusCode)
+	}
+	var out iamGetRoleResponse
+	if err = xml.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("IAM GetRole %q: decode response: %w", roleName, err)
+	}
+	// IAM returns policy documents URL-encoded.
+	return url.QueryUnescape(out.AssumeRolePolicyDocument)
+}
+
+// iamPolicyDocument is the part of an IAM trust policy the adapter reads.
+// Action and Principal.Service may each be a string or a list.
+type iamPolicyDocument struct {
+	Statement []struct {
+		Effect    string          `json:"Effect"`
+		Action    json.RawMessage `json:"Action"`
+		Principal struct {
+			Service json.RawMessage `json:"Service"`
+		} `json:"Principal"`
+	} `json:"Statement"`
+}
+
+// trustsServicePrincipal reports whether the trust policy doc lets service
+// assume the role.
+func trustsServicePrincipal(doc, service string) (bool, error) {
+	var policy iamPolicyDocument
+	if err := json.Unmarshal([]byte(doc), &policy); err != nil {
+		return false, fmt.Errorf("parse trust policy: %w", err)
+	}
+	for _, st := range policy.Statement {
+		if st.Effect != "Allow" || !slices.Contains(stringOrList(st.Principal.Service), service) {
+			continue
+		}
+		for _, action := range stringOrList(st.Action) {
+			if action == "sts:AssumeRole" || action == "sts:*" || action == "*" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// stringOrList decodes a policy element holding a string or a list of
+// strings.
+func stringOrList(raw json.RawMessage) []string {
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return []string{one}
+	}
+	var list []string
+	_ = json.Unmarshal(raw, &list)
+	return list
+}
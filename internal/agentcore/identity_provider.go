@@ -0,0 +1,369 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// identityProviderSuffix is appended to an identity_providers key to name
+// its credential provider resource.
+const identityProviderSuffix = "_identity"
+
+// identityListPageSize is the largest MaxResults ListOauth2CredentialProviders
+// accepts.
+const identityListPageSize = 20
+
+// IdentityProviderConfig provisions an AgentCore Identity OAuth2 credential
+// provider, through which gateway targets obtain tokens for third-party
+// APIs.
+type IdentityProviderConfig struct {
+	// Vendor is the AgentCore credential provider vendor, such as
+	// GoogleOauth2, or CustomOauth2 for any other authorization server.
+	Vendor string `json:"vendor"`
+
+	// ClientID is the OAuth2 client ID. ClientSecretEnv names the deploy
+	// environment variable holding the client secret, which stays out of
+	// the deploy config.
+	ClientID        string `json:"client_id"`
+	ClientSecretEnv string `json:"client_secret_env"`
+
+	// DiscoveryURL is the authorization server's OpenID Connect discovery
+	// URL. CustomOauth2 providers require it; other vendors take none.
+	DiscoveryURL string `json:"discovery_url,omitempty"`
+
+	// Tools names the pack tools whose gateway targets authenticate
+	// through the provider, requesting Scopes.
+	Tools  []string `json:"tools,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// identityVendorConfigs builds the provider settings of the vendors
+// AgentCore has a dedicated configuration for. Other built-in vendors use
+// the included configuration, and CustomOauth2 its discovery URL.
+var identityVendorConfigs = map[types.CredentialProviderVendorType]func(
+	id, secret *string,
+) types.Oauth2ProviderConfigInput{
+	types.CredentialProviderVendorTypeGoogleOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberGoogleOauth2ProviderConfig{
+			Value: types.GoogleOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+	types.CredentialProviderVendorTypeGithubOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberGithubOauth2ProviderConfig{
+			Value: types.GithubOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+	types.CredentialProviderVendorTypeSlackOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberSlackOauth2ProviderConfig{
+			Value: types.SlackOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+	types.CredentialProviderVendorTypeSalesforceOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberSalesforceOauth2ProviderConfig{
+			Value: types.SalesforceOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+	types.CredentialProviderVendorTypeMicrosoftOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberMicrosoftOauth2ProviderConfig{
+			Value: types.MicrosoftOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+	types.CredentialProviderVendorTypeAtlassianOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberAtlassianOauth2ProviderConfig{
+			Value: types.AtlassianOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+	types.CredentialProviderVendorTypeLinkedinOauth2: func(id, secret *string) types.Oauth2ProviderConfigInput {
+		return &types.Oauth2ProviderConfigInputMemberLinkedinOauth2ProviderConfig{
+			Value: types.LinkedinOauth2ProviderConfigInput{ClientId: id, ClientSecret: secret},
+		}
+	},
+}
+
+// validateIdentityProviders checks each identity provider's vendor, client
+// settings, and discovery URL, and that no tool is attached to two of them.
+func validateIdentityProviders(providers map[string]*IdentityProviderConfig) []string {
+	var errs []string
+	owner := make(map[string]string)
+	for _, k := range sortedKeys(providers) {
+		field := "identity_providers." + k
+		p := providers[k]
+		if p == nil {
+			errs = append(errs, field+" must be an object")
+			continue
+		}
+		errs = append(errs, validateIdentityProvider(field, p)...)
+		for _, tool := range p.Tools {
+			if other, ok := owner[tool]; ok {
+				errs = append(errs, fmt.Sprintf("%s.tools: tool %q is already attached to identity_providers.%s",
+					field, tool, other))
+				continue
+			}
+			owner[tool] = k
+		}
+	}
+	return errs
+}
+
+// validateIdentityProvider checks a single identity provider entry.
+func validateIdentityProvider(field string, p *IdentityProviderConfig) []string {
+	var errs []string
+	vendor := types.CredentialProviderVendorType(p.Vendor)
+	if !slices.Contains(vendor.Values(), vendor) {
+		errs = append(errs, fmt.Sprintf("%s.vendor %q is not an AgentCore credential provider vendor", field, p.Vendor))
+	}
+	if p.ClientID == "" {
+		errs = append(errs, field+".client_id is required")
+	}
+	if !envVarNameRE.MatchString(p.ClientSecretEnv) {
+		errs = append(errs, fmt.Sprintf("%s.client_secret_env %q must be an environment variable name",
+			field, p.ClientSecretEnv))
+	}
+	switch {
+	case vendor == types.CredentialProviderVendorTypeCustomOauth2 && p.DiscoveryURL == "":
+		errs = append(errs, fmt.Sprintf("%s.discovery_url is required for vendor %s", field, p.Vendor))
+	case vendor == types.CredentialProviderVendorTypeCustomOauth2:
+		if u, err := url.Parse(p.DiscoveryURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("%s.discovery_url %q must be an https URL", field, p.DiscoveryURL))
+		}
+	case p.DiscoveryURL != "":
+		errs = append(errs, fmt.Sprintf("%s.discovery_url is only accepted for vendor %s",
+			field, types.CredentialProviderVendorTypeCustomOauth2))
+	}
+	return errs
+}
+
+// identityProviderNames returns the credential provider resource names of
+// the configured identity providers, sorted by key.
+func identityProviderNames(cfg *Config) []string {
+	keys := sortedKeys(cfg.IdentityProviders)
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k + identityProviderSuffix
+	}
+	return names
+}
+
+// identityProviderConfig returns the config entry a credential provider
+// resource was named after.
+func (c *Config) identityProviderConfig(name string) *IdentityProviderConfig {
+	return c.IdentityProviders[strings.TrimSuffix(name, identityProviderSuffix)]
+}
+
+// identityProviderForTool returns the identity provider a tool's gateway
+// target authenticates through, and the ARN Apply created it with. The ARN
+// is empty when the tool has no provider or its creation failed.
+func (c *Config) identityProviderForTool(tool string) (*IdentityProviderConfig, string) {
+	for _, k := range sortedKeys(c.IdentityProviders) {
+		if p := c.IdentityProviders[k]; p != nil && slices.Contains(p.Tools, tool) {
+			return p, c.IdentityProviderARNs[k]
+		}
+	}
+	return nil, ""
+}
+
+// generateIdentityProviderResources returns identity_provider resource
+// changes for every configured identity provider.
+func generateIdentityProviderResources(_ *prompt.Pack, cfg *Config) []deploy.ResourceChange {
+	names := identityProviderNames(cfg)
+	changes := make([]deploy.ResourceChange, 0, len(names))
+	for _, name := range names {
+		changes = append(changes, deploy.ResourceChange{
+			Type:   ResTypeIdentityProvider,
+			Name:   name,
+			Action: deploy.ActionCreate,
+			Detail: fmt.Sprintf("Create %s OAuth2 credential provider", cfg.identityProviderConfig(name).Vendor),
+		})
+	}
+	return changes
+}
+
+// applyIdentityProviders creates or updates the identity providers and
+// records their ARNs, so the tool gateway phase that follows can attach
+// them to gateway targets.
+func applyIdentityProviders(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	names := identityProviderNames(ac.cfg)
+	if len(names) == 0 {
+		return resources, applyErr, nil
+	}
+	phase := applyPhase(ctx, ac.reporter, ac.client.CreateIdentityProvider, ac.client.UpdateIdentityProvider, nil,
+		ac.cfg, names, ResTypeIdentityProvider, ac.span, ac.priorMap)
+	ac.cfg.IdentityProviderARNs = make(map[string]string)
+	for _, r := range phase.resources {
+		if r.ARN != "" {
+			ac.cfg.IdentityProviderARNs[strings.TrimSuffix(r.Name, identityProviderSuffix)] = r.ARN
+		}
+	}
+	return mergePhase(resources, applyErr, phase)
+}
+
+// identityProviderInput resolves the provider settings of a credential
+// provider resource, reading its client secret from the environment.
+func identityProviderInput(name string, cfg *Config) (types.Oauth2ProviderConfigInput, error) {
+	p := cfg.identityProviderConfig(name)
+	if p == nil {
+		return nil, fmt.Errorf("identity provider %q is not configured", name)
+	}
+	secret := os.Getenv(p.ClientSecretEnv)
+	if secret == "" {
+		return nil, fmt.Errorf("environment variable %s, holding the client secret, is not set", p.ClientSecretEnv)
+	}
+	id := aws.String(p.ClientID)
+	vendor := types.CredentialProviderVendorType(p.Vendor)
+	if build, ok := identityVendorConfigs[vendor]; ok {
+		return build(id, aws.String(secret)), nil
+	}
+	if vendor == types.CredentialProviderVendorTypeCustomOauth2 {
+		return &types.Oauth2ProviderConfigInputMemberCustomOauth2ProviderConfig{
+			Value: types.CustomOauth2ProviderConfigInput{
+				ClientId: id, ClientSecret: aws.String(secret),
+				OauthDiscovery: &types.Oauth2DiscoveryMemberDiscoveryUrl{Value: p.DiscoveryURL},
+			},
+		}, nil
+	}
+	return &types.Oauth2ProviderConfigInputMemberIncludedOauth2ProviderConfig{
+		Value: types.IncludedOauth2ProviderConfigInput{ClientId: id, ClientSecret: aws.String(secret)},
+	}, nil
+}
+
+// oauthCredentialProviderConfigs returns the gateway target credentials
+// that obtain tokens from the identity provider at arn.
+func oauthCredentialProviderConfigs(p *IdentityProviderConfig, arn string) []types.CredentialProviderConfiguration {
+	return []types.CredentialProviderConfiguration{{
+		CredentialProviderType: types.CredentialProviderTypeOauth,
+		CredentialProvider: &types.CredentialProviderMemberOauthCredentialProvider{
+			Value: types.OAuthCredentialProvider{
+				ProviderArn: aws.String(arn),
+				Scopes:      append([]string{}, p.Scopes...),
+			},
+		},
+	}}
+}
+
+// ---------- realAWSClient implementation ----------
+
+// CreateIdentityProvider creates an OAuth2 credential provider. AgentCore
+// stores the client secret in Secrets Manager on the caller's behalf.
+func (c *realAWSClient) CreateIdentityProvider(ctx context.Context, name string, cfg *Config) (string, error) {
+	providerInput, err := identityProviderInput(name, cfg)
+	if err != nil {
+		return "", fmt.Errorf("CreateOauth2CredentialProvider %q: %w", name, err)
+	}
+	awsName := cfg.awsName(name)
+	input := &bedrockagentcorecontrol.CreateOauth2CredentialProviderInput{
+		Name:                      aws.String(awsName),
+		CredentialProviderVendor:  types.CredentialProviderVendorType(cfg.identityProviderConfig(name).Vendor),
+		Oauth2ProviderConfigInput: providerInput,
+		Tags:                      cfg.ResourceTags,
+	}
+
+	out, err := c.client.CreateOauth2CredentialProvider(ctx, input)
+	if isConflictError(err) {
+		arn, adopted, conflictErr := c.onCreateConflict(ctx, ResTypeIdentityProvider, name,
+			func() (string, error) { return c.findIdentityProviderARN(ctx, awsName) },
+			func() error {
+				out, err = c.client.CreateOauth2CredentialProvider(ctx, input)
+				return err
+			})
+		if conflictErr != nil || adopted {
+			return arn, conflictErr
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("CreateOauth2CredentialProvider %q: %w", name, err)
+	}
+	return aws.ToString(out.CredentialProviderArn), nil
+}
+
+// UpdateIdentityProvider replaces an OAuth2 credential provider's client
+// settings, so a rotated client secret takes effect on the next Apply.
+func (c *realAWSClient) UpdateIdentityProvider(
+	ctx context.Context, _ string, name string, cfg *Config,
+) (string, error) {
+	providerInput, err := identityProviderInput(name, cfg)
+	if err != nil {
+		return "", fmt.Errorf("UpdateOauth2CredentialProvider %q: %w", name, err)
+	}
+	out, err := c.client.UpdateOauth2CredentialProvider(ctx, &bedrockagentcorecontrol.UpdateOauth2CredentialProviderInput{
+		Name:                      aws.String(cfg.awsName(name)),
+		CredentialProviderVendor:  types.CredentialProviderVendorType(cfg.identityProviderConfig(name).Vendor),
+		Oauth2ProviderConfigInput: providerInput,
+	})
+	if err != nil {
+		return "", fmt.Errorf("UpdateOauth2CredentialProvider %q: %w", name, err)
+	}
+	return aws.ToString(out.CredentialProviderArn), nil
+}
+
+// findIdentityProviderARN returns the ARN of the OAuth2 credential
+// provider named awsName.
+func (c *realAWSClient) findIdentityProviderARN(ctx context.Context, awsName string) (string, error) {
+	out, err := c.client.GetOauth2CredentialProvider(ctx, &bedrockagentcorecontrol.GetOauth2CredentialProviderInput{
+		Name: aws.String(awsName),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.CredentialProviderArn), nil
+}
+
+func (c *realAWSClient) deleteIdentityProvider(ctx context.Context, res ResourceState) error {
+	_, err := c.client.DeleteOauth2CredentialProvider(ctx, &bedrockagentcorecontrol.DeleteOauth2CredentialProviderInput{
+		Name: aws.String(c.cfg.awsName(res.Name)),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteOauth2CredentialProvider %q: %w", res.Name, err)
+	}
+	return nil
+}
+
+func (c *realAWSClient) checkIdentityProvider(ctx context.Context, res ResourceState) (string, error) {
+	_, err := c.findIdentityProviderARN(ctx, c.cfg.awsName(res.Name))
+	if err != nil {
+		if isNotFound(err) {
+			return StatusMissing, nil
+		}
+		return StatusUnhealthy, fmt.Errorf("GetOauth2CredentialProvider %q: %w", res.Name, err)
+	}
+	return StatusHealthy, nil
+}
+
+// listIdentityProviderCandidates lists OAuth2 credential providers for the
+// orphan scan.
+func (c *realAWSClient) listIdentityProviderCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.client.ListOauth2CredentialProviders(ctx,
+			&bedrockagentcorecontrol.ListOauth2CredentialProvidersInput{
+				MaxResults: aws.Int32(identityListPageSize), NextToken: nextToken,
+			})
+		if err != nil {
+			return nil, fmt.Errorf("ListOauth2CredentialProviders: %w", err)
+		}
+		for _, p := range page.CredentialProviders {
+			name := aws.ToString(p.Name)
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{Type: ResTypeIdentityProvider, Name: name, ID: name},
+				arn:            aws.ToString(p.CredentialProviderArn),
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
@@ -0,0 +1,163 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// identityRecordingClient records the credential provider configurations
+// each gateway target would be created with.
+type identityRecordingClient struct {
+	simulatedAWSClient
+	targetCreds map[string][]types.CredentialProviderConfiguration
+}
+
+func (c *identityRecordingClient) CreateGatewayTool(ctx context.Context, name string, cfg *Config) (string, error) {
+	c.targetCreds[name] = buildCredentialProviderConfigs(name, cfg)
+	return c.simulatedAWSClient.CreateGatewayTool(ctx, name, cfg)
+}
+
+func TestValidateIdentityProviders(t *testing.T) {
+	valid := func() *IdentityProviderConfig {
+		return &IdentityProviderConfig{Vendor: "GoogleOauth2", ClientID: "id", ClientSecretEnv: "GOOGLE_SECRET"}
+	}
+	tests := []struct {
+		name    string
+		edit    func(p *IdentityProviderConfig)
+		wantErr string
+	}{
+		{"valid", func(*IdentityProviderConfig) {}, ""},
+		{"unknown vendor", func(p *IdentityProviderConfig) { p.Vendor = "MyOauth" }, "not an AgentCore credential"},
+		{"no client id", func(p *IdentityProviderConfig) { p.ClientID = "" }, "client_id is required"},
+		{"bad secret env", func(p *IdentityProviderConfig) { p.ClientSecretEnv = "MY-SECRET" }, "client_secret_env"},
+		{"custom without discovery", func(p *IdentityProviderConfig) { p.Vendor = "CustomOauth2" },
+			"discovery_url is required"},
+		{"custom with http discovery", func(p *IdentityProviderConfig) {
+			p.Vendor, p.DiscoveryURL = "CustomOauth2", "http://idp.example.com/.well-known/openid-configuration"
+		}, "must be an https URL"},
+		{"discovery for built-in vendor", func(p *IdentityProviderConfig) {
+			p.DiscoveryURL = "https://accounts.google.com/.well-known/openid-configuration"
+		}, "only accepted for vendor CustomOauth2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.edit(p)
+			errs := validateIdentityProviders(map[string]*IdentityProviderConfig{"google": p})
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("errs = %v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+
+	shared := map[string]*IdentityProviderConfig{"a": valid(), "b": valid()}
+	shared["a"].Tools = []string{"search"}
+	shared["b"].Tools = []string{"search"}
+	errs := validateIdentityProviders(shared)
+	if len(errs) != 1 || !strings.Contains(errs[0], `tool "search" is already attached to identity_providers.a`) {
+		t.Errorf("errs = %v, want the shared tool reported", errs)
+	}
+}
+
+func TestIdentityProviderInput(t *testing.T) {
+	t.Setenv("IDP_SECRET", "s3cret")
+	cfg := &Config{IdentityProviders: map[string]*IdentityProviderConfig{
+		"google": {Vendor: "GoogleOauth2", ClientID: "id", ClientSecretEnv: "IDP_SECRET"},
+		"okta":   {Vendor: "OktaOauth2", ClientID: "id", ClientSecretEnv: "IDP_SECRET"},
+		"corp": {Vendor: "CustomOauth2", ClientID: "id", ClientSecretEnv: "IDP_SECRET",
+			DiscoveryURL: "https://idp.example.com/.well-known/openid-configuration"},
+		"unset": {Vendor: "GoogleOauth2", ClientID: "id", ClientSecretEnv: "IDP_SECRET_UNSET"},
+	}}
+
+	in, err := identityProviderInput("google_identity", cfg)
+	google, ok := in.(*types.Oauth2ProviderConfigInputMemberGoogleOauth2ProviderConfig)
+	if err != nil || !ok || aws.ToString(google.Value.ClientSecret) != "s3cret" {
+		t.Errorf("google = %#v, %v, want the Google config with the secret", in, err)
+	}
+	if in, err = identityProviderInput("okta_identity", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = in.(*types.Oauth2ProviderConfigInputMemberIncludedOauth2ProviderConfig); !ok {
+		t.Errorf("okta = %#v, want the included config", in)
+	}
+	if in, err = identityProviderInput("corp_identity", cfg); err != nil {
+		t.Fatal(err)
+	}
+	custom, ok := in.(*types.Oauth2ProviderConfigInputMemberCustomOauth2ProviderConfig)
+	if !ok {
+		t.Fatalf("corp = %#v, want the custom config", in)
+	}
+	if d, ok := custom.Value.OauthDiscovery.(*types.Oauth2DiscoveryMemberDiscoveryUrl); !ok ||
+		d.Value != cfg.IdentityProviders["corp"].DiscoveryURL {
+		t.Errorf("discovery = %#v, want the discovery URL", custom.Value.OauthDiscovery)
+	}
+	if _, err = identityProviderInput("unset_identity", cfg); err == nil ||
+		!strings.Contains(err.Error(), "IDP_SECRET_UNSET") {
+		t.Errorf("err = %v, want the unset secret variable named", err)
+	}
+}
+
+func TestApply_IdentityProviderAttachedToGatewayTarget(t *testing.T) {
+	client := &identityRecordingClient{
+		simulatedAWSClient: *newSimulatedAWSClient("us-west-2"),
+		targetCreds:        map[string][]types.CredentialProviderConfiguration{},
+	}
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) { return client, nil }
+
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"identity_providers":{"google":{` +
+		`"vendor":"GoogleOauth2","client_id":"id","client_secret_env":"GOOGLE_SECRET",` +
+		`"tools":["search"],"scopes":["https://www.googleapis.com/auth/drive.readonly"]}}}`
+	_, stateJSON, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON: singleAgentPackWithTools(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	state, err := parseAdapterState(stateJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := state.Resources[0]
+	if idp.Type != ResTypeIdentityProvider || idp.Name != "google_identity" || idp.ARN == "" {
+		t.Fatalf("first resource = %+v, want the identity provider", idp)
+	}
+
+	creds := client.targetCreds["search"]
+	if len(creds) != 1 || creds[0].CredentialProviderType != types.CredentialProviderTypeOauth {
+		t.Fatalf("search credentials = %#v, want one OAUTH provider", creds)
+	}
+	oauth := creds[0].CredentialProvider.(*types.CredentialProviderMemberOauthCredentialProvider).Value
+	if aws.ToString(oauth.ProviderArn) != idp.ARN || len(oauth.Scopes) != 1 {
+		t.Errorf("search OAuth = %+v, want the provider ARN and scope", oauth)
+	}
+	if creds = client.targetCreds["calc"]; len(creds) != 0 {
+		t.Errorf("calc credentials = %#v, want none", creds)
+	}
+}
+
+func TestGenerateDesiredResources_IdentityProviders(t *testing.T) {
+	cfg := &Config{IdentityProviders: map[string]*IdentityProviderConfig{
+		"slack":  {Vendor: "SlackOauth2"},
+		"github": {Vendor: "GithubOauth2"},
+	}}
+	changes := generateIdentityProviderResources(nil, cfg)
+	if len(changes) != 2 || changes[0].Name != "github_identity" || changes[1].Name != "slack_identity" {
+		t.Fatalf("changes = %+v, want github then slack", changes)
+	}
+	if changes[1].Detail != "Create SlackOauth2 OAuth2 credential provider" {
+		t.Errorf("detail = %q", changes[1].Detail)
+	}
+}
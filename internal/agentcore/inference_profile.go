@@ -0,0 +1,389 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrockTypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+// Inference profile resource name suffixes. The runtime profile is named
+// after the pack; evaluator profiles after their eval, or after the pack
+// for the shared "default" entry.
+const (
+	runtimeProfileSuffix = "_runtime_profile"
+	evalProfileSuffix    = "_eval_profile"
+)
+
+// evalProfileDefaultKey is the inference_profiles.evaluators key that
+// applies to every evaluator without its own entry.
+const evalProfileDefaultKey = "default"
+
+// InferenceProfileConfig routes a model through a Bedrock inference
+// profile. Exactly one of ID and CopyFrom is set.
+type InferenceProfileConfig struct {
+	// ID is an existing system-defined (cross-region) or application
+	// inference profile, by ID or ARN. It is used as-is.
+	ID string `json:"id,omitempty"`
+
+	// CopyFrom is a foundation model or system-defined inference profile,
+	// by ID or ARN. The adapter creates an application inference profile
+	// from it and tracks the profile as a resource.
+	CopyFrom string `json:"copy_from,omitempty"`
+}
+
+// InferenceProfilesConfig selects inference profiles for the runtime's
+// provider model and for LLM-as-a-Judge evaluators.
+type InferenceProfilesConfig struct {
+	Runtime *InferenceProfileConfig `json:"runtime,omitempty"`
+
+	// Evaluators is keyed by eval ID; the "default" entry applies to every
+	// evaluator without its own entry.
+	Evaluators map[string]*InferenceProfileConfig `json:"evaluators,omitempty"`
+}
+
+// forEval returns the profile for an evaluator, falling back to the
+// "default" entry, and the resource name an application profile created
+// for it would have.
+func (c *InferenceProfilesConfig) forEval(packID, evalName string) (*InferenceProfileConfig, string) {
+	if c == nil {
+		return nil, ""
+	}
+	if p := c.Evaluators[evalName]; p != nil {
+		return p, evalName + evalProfileSuffix
+	}
+	if p := c.Evaluators[evalProfileDefaultKey]; p != nil {
+		return p, packID + evalProfileSuffix
+	}
+	return nil, ""
+}
+
+// forRuntime returns the runtime profile and its resource name.
+func (c *InferenceProfilesConfig) forRuntime(packID string) (*InferenceProfileConfig, string) {
+	if c == nil || c.Runtime == nil {
+		return nil, ""
+	}
+	return c.Runtime, packID + runtimeProfileSuffix
+}
+
+// model returns the identifier the profile routes to before any
+// application profile exists: the profile ID, or the model it copies.
+func (p *InferenceProfileConfig) model() string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.CopyFrom
+}
+
+// validateInferenceProfiles checks that every entry sets exactly one of id
+// and copy_from.
+func validateInferenceProfiles(c *InferenceProfilesConfig) []string {
+	if c == nil {
+		return nil
+	}
+	errs := validateInferenceProfile("inference_profiles.runtime", c.Runtime)
+	for _, k := range sortedKeys(c.Evaluators) {
+		errs = append(errs, validateInferenceProfile("inference_profiles.evaluators."+k, c.Evaluators[k])...)
+	}
+	return errs
+}
+
+// validateInferenceProfile checks a single inference profile entry.
+func validateInferenceProfile(field string, p *InferenceProfileConfig) []string {
+	switch {
+	case p == nil:
+		return nil
+	case p.ID == "" && p.CopyFrom == "":
+		return []string{field + ": set id or copy_from"}
+	case p.ID != "" && p.CopyFrom != "":
+		return []string{field + ": set only one of id and copy_from"}
+	}
+	return nil
+}
+
+// applicationProfile is an application inference profile the adapter
+// creates.
+type applicationProfile struct {
+	Name     string
+	CopyFrom string
+}
+
+// applicationProfiles lists the application inference profiles the
+// deployment needs, runtime first. A profile shared by several evaluators
+// through the "default" entry is listed once.
+func applicationProfiles(pack *prompt.Pack, cfg *Config) []applicationProfile {
+	packName := runtimeProfilePackName(pack)
+	var profiles []applicationProfile
+	seen := make(map[string]bool)
+	add := func(p *InferenceProfileConfig, name string) {
+		if p == nil || p.CopyFrom == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		profiles = append(profiles, applicationProfile{Name: name, CopyFrom: p.CopyFrom})
+	}
+
+	add(cfg.InferenceProfiles.forRuntime(packName))
+	for _, evalName := range evalResourceNames(pack) {
+		add(cfg.InferenceProfiles.forEval(packName, evalName))
+	}
+	return profiles
+}
+
+// runtimeProfilePackName returns the pack name used in profile resource
+// names, matching the single-agent runtime name.
+func runtimeProfilePackName(pack *prompt.Pack) string {
+	if pack.ID == "" {
+		return defaultPackName
+	}
+	return pack.ID
+}
+
+// generateInferenceProfileResources returns inference_profile resource
+// changes for every application profile the deployment creates.
+func generateInferenceProfileResources(pack *prompt.Pack, cfg *Config) []deploy.ResourceChange {
+	profiles := applicationProfiles(pack, cfg)
+	changes := make([]deploy.ResourceChange, 0, len(profiles))
+	for _, ap := range profiles {
+		changes = append(changes, deploy.ResourceChange{
+			Type:   ResTypeInferenceProfile,
+			Name:   ap.Name,
+			Action: deploy.ActionCreate,
+			Detail: fmt.Sprintf("Create application inference profile from %s", ap.CopyFrom),
+		})
+	}
+	return changes
+}
+
+// applyInferenceProfiles creates the deployment's application inference
+// profiles and routes the runtime and evaluators through their configured
+// profiles. It runs before runtimes and evaluators are created so both see
+// the resolved profile.
+func applyInferenceProfiles(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if ac.cfg.InferenceProfiles == nil {
+		return resources, applyErr, nil
+	}
+	arns := make(map[string]string)
+	for _, ap := range applicationProfiles(ac.pack, ac.cfg) {
+		res, err := createInferenceProfileResource(ctx, ac, ap)
+		if err != nil {
+			applyErr = combineErrors(applyErr, err)
+		}
+		if res != nil {
+			resources = append(resources, *res)
+			arns[ap.Name] = res.ARN
+		}
+	}
+	resolveInferenceProfiles(ac.pack, ac.cfg, arns)
+	return resources, applyErr, nil
+}
+
+// createInferenceProfileResource creates one application inference profile.
+func createInferenceProfileResource(
+	ctx context.Context, ac *applyContext, ap applicationProfile,
+) (*ResourceState, error) {
+	if err := ac.reporter.Progress("Creating inference profile: "+ap.Name, ac.span.start); err != nil {
+		return nil, err
+	}
+
+	arn, err := ac.client.CreateInferenceProfile(ctx, ap.Name, ap.CopyFrom, ac.cfg)
+	if err != nil {
+		deployErr := newDeployError("create", ResTypeInferenceProfile, ap.Name, err)
+		_ = ac.reporter.Error(deployErr)
+		return &ResourceState{
+			Type: ResTypeInferenceProfile, Name: ap.Name, Status: ResStatusFailed,
+		}, deployErr
+	}
+
+	if err := ac.reporter.Resource(&deploy.ResourceResult{
+		Type: ResTypeInferenceProfile, Name: ap.Name,
+		Action: deploy.ActionCreate, Status: ResStatusCreated,
+		Detail: arn,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &ResourceState{
+		Type: ResTypeInferenceProfile, Name: ap.Name, ARN: arn, Status: ResStatusCreated,
+	}, nil
+}
+
+// resolveInferenceProfiles injects the runtime's profile into its env vars
+// and records each evaluator's profile in cfg.EvalModelIDs. arns maps
+// application profile names to the ARNs created for them; a profile whose
+// creation failed is skipped so its user keeps the plain model.
+func resolveInferenceProfiles(pack *prompt.Pack, cfg *Config, arns map[string]string) {
+	packName := runtimeProfilePackName(pack)
+	resolve := func(p *InferenceProfileConfig, name string) string {
+		if p == nil {
+			return ""
+		}
+		if p.ID != "" {
+			return p.ID
+		}
+		return arns[name]
+	}
+
+	if id := resolve(cfg.InferenceProfiles.forRuntime(packName)); id != "" {
+		cfg.RuntimeEnvVars[EnvInferenceProfile] = id
+	}
+	cfg.EvalModelIDs = make(map[string]string)
+	for _, evalName := range evalResourceNames(pack) {
+		if id := resolve(cfg.InferenceProfiles.forEval(packName, evalName)); id != "" {
+			cfg.EvalModelIDs[evalName] = id
+		}
+	}
+}
+
+// ---------- realAWSClient implementation ----------
+
+// CreateInferenceProfile creates an application inference profile that
+// copies copyFrom, a foundation model or system-defined inference profile.
+// Application profiles are ACTIVE on creation, so there is nothing to wait for.
+func (c *realAWSClient) CreateInferenceProfile(
+	ctx context.Context, name, copyFrom string, cfg *Config,
+) (string, error) {
+	source, err := c.inferenceProfileSourceARN(ctx, copyFrom)
+	if err != nil {
+		return "", fmt.Errorf("CreateInferenceProfile %q: %w", name, err)
+	}
+
+	awsName := cfg.awsName(name)
+	input := &bedrock.CreateInferenceProfileInput{
+		InferenceProfileName: aws.String(awsName),
+		ModelSource:          &bedrockTypes.InferenceProfileModelSourceMemberCopyFrom{Value: source},
+		Tags:                 bedrockTags(cfg.ResourceTags),
+	}
+
+	out, err := c.bedrockClient.CreateInferenceProfile(ctx, input)
+	if isConflictError(err) {
+		arn, adopted, conflictErr := c.onCreateConflict(ctx, ResTypeInferenceProfile, name,
+			func() (string, error) { return c.findInferenceProfileByName(ctx, awsName) },
+			func() error {
+				out, err = c.bedrockClient.CreateInferenceProfile(ctx, input)
+				return err
+			})
+		if conflictErr != nil || adopted {
+			return arn, conflictErr
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("CreateInferenceProfile %q: %w", name, err)
+	}
+	return aws.ToString(out.InferenceProfileArn), nil
+}
+
+// inferenceProfileSourceARN resolves copyFrom to the ARN CreateInferenceProfile
+// requires. A geography-prefixed ID names a system-defined profile, whose
+// ARN includes the account and is looked up; any other ID is a foundation
+// model.
+func (c *realAWSClient) inferenceProfileSourceARN(ctx context.Context, copyFrom string) (string, error) {
+	if strings.HasPrefix(copyFrom, "arn:") {
+		return copyFrom, nil
+	}
+	if baseModelID(copyFrom) == copyFrom {
+		return formatARN("bedrock", c.cfg.Region, "", "foundation-model/"+copyFrom), nil
+	}
+	out, err := c.bedrockClient.GetInferenceProfile(ctx, &bedrock.GetInferenceProfileInput{
+		InferenceProfileIdentifier: aws.String(copyFrom),
+	})
+	if err != nil {
+		return "", fmt.Errorf("look up inference profile %q: %w", copyFrom, err)
+	}
+	return aws.ToString(out.InferenceProfileArn), nil
+}
+
+// findInferenceProfileByName lists application inference profiles and
+// returns the ARN of the one matching name.
+func (c *realAWSClient) findInferenceProfileByName(ctx context.Context, name string) (string, error) {
+	var nextToken *string
+	for {
+		out, err := c.bedrockClient.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+			TypeEquals: bedrockTypes.InferenceProfileTypeApplication,
+		})
+		if err != nil {
+			return "", err
+		}
+		for _, p := range out.InferenceProfileSummaries {
+			if aws.ToString(p.InferenceProfileName) == name {
+				return aws.ToString(p.InferenceProfileArn), nil
+			}
+		}
+		if out.NextToken == nil {
+			return "", fmt.Errorf("inference profile %q %w", name, errNotListed)
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// inferenceProfileTags returns the tags on an application inference
+// profile. Bedrock tags them through its own API, not AgentCore's.
+func (c *realAWSClient) inferenceProfileTags(ctx context.Context, arn string) (map[string]string, error) {
+	out, err := c.bedrockClient.ListTagsForResource(ctx, &bedrock.ListTagsForResourceInput{
+		ResourceARN: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(out.Tags))
+	for _, t := range out.Tags {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return tags, nil
+}
+
+func (c *realAWSClient) deleteInferenceProfile(ctx context.Context, res ResourceState) error {
+	id := res.ARN
+	if id == "" {
+		id = res.Name
+	}
+	_, err := c.bedrockClient.DeleteInferenceProfile(ctx, &bedrock.DeleteInferenceProfileInput{
+		InferenceProfileIdentifier: aws.String(id),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteInferenceProfile %q: %w", res.Name, err)
+	}
+	return nil
+}
+
+func (c *realAWSClient) checkInferenceProfile(ctx context.Context, res ResourceState) (string, error) {
+	id := res.ARN
+	if id == "" {
+		id = res.Name
+	}
+	out, err := c.bedrockClient.GetInferenceProfile(ctx, &bedrock.GetInferenceProfileInput{
+		InferenceProfileIdentifier: aws.String(id),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return StatusMissing, nil
+		}
+		return StatusUnhealthy, fmt.Errorf("GetInferenceProfile %q: %w", res.Name, err)
+	}
+	if out.Status == bedrockTypes.InferenceProfileStatusActive {
+		return StatusHealthy, nil
+	}
+	return StatusUnhealthy, nil
+}
+
+// bedrockTags converts a tag map to the Bedrock control-plane tag list.
+func bedrockTags(tags map[string]string) []bedrockTypes.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make([]bedrockTypes.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		out = append(out, bedrockTypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return out
+}
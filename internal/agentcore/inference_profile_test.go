@@ -0,0 +1,214 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/evals"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+const testSystemProfile = "us.anthropic.claude-sonnet-4-20250514-v1:0"
+
+func TestValidateInferenceProfiles(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *InferenceProfilesConfig
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"runtime id", &InferenceProfilesConfig{Runtime: &InferenceProfileConfig{ID: testSystemProfile}}, nil},
+		{
+			"runtime empty",
+			&InferenceProfilesConfig{Runtime: &InferenceProfileConfig{}},
+			[]string{"inference_profiles.runtime: set id or copy_from"},
+		},
+		{
+			"evaluator both",
+			&InferenceProfilesConfig{Evaluators: map[string]*InferenceProfileConfig{
+				"default": {ID: "a", CopyFrom: "b"},
+				"tone":    {CopyFrom: "anthropic.claude-3-haiku-20240307-v1:0"},
+			}},
+			[]string{"inference_profiles.evaluators.default: set only one of id and copy_from"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateInferenceProfiles(tt.cfg)
+			if strings.Join(got, ";") != strings.Join(tt.want, ";") {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplicationProfiles(t *testing.T) {
+	pack := &prompt.Pack{ID: "mypack", Evals: []evals.EvalDef{
+		{ID: "tone", Type: evalTypeLLMAsJudge},
+		{ID: "quality", Type: evalTypeLLMAsJudge},
+		{ID: "safety", Type: evalTypeLLMAsJudge},
+		{ID: "length", Type: "max_length"},
+	}}
+	cfg := &Config{InferenceProfiles: &InferenceProfilesConfig{
+		Runtime: &InferenceProfileConfig{CopyFrom: testSystemProfile},
+		Evaluators: map[string]*InferenceProfileConfig{
+			"default": {CopyFrom: "anthropic.claude-3-haiku-20240307-v1:0"},
+			"safety":  {ID: testSystemProfile},
+		},
+	}}
+
+	got := applicationProfiles(pack, cfg)
+	want := []applicationProfile{
+		{Name: "mypack_runtime_profile", CopyFrom: testSystemProfile},
+		{Name: "mypack_eval_profile", CopyFrom: "anthropic.claude-3-haiku-20240307-v1:0"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("profiles = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("profiles[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModelUses_InferenceProfiles(t *testing.T) {
+	pack := &prompt.Pack{ID: "mypack", Evals: []evals.EvalDef{{ID: "tone", Type: evalTypeLLMAsJudge}}}
+	cfg := &Config{
+		ArenaConfig: &ArenaConfig{LoadedProviders: map[string]*ArenaProvider{"p": {Model: "m-1"}}},
+		InferenceProfiles: &InferenceProfilesConfig{
+			Runtime:    &InferenceProfileConfig{ID: testSystemProfile},
+			Evaluators: map[string]*InferenceProfileConfig{"tone": {CopyFrom: "amazon.nova-pro-v1:0"}},
+		},
+	}
+
+	got := modelUses(pack, cfg)
+	if len(got) != 2 || got[0].ModelID != "amazon.nova-pro-v1:0" || got[1].ModelID != testSystemProfile {
+		t.Errorf("uses = %+v, want the evaluator's copy_from model and the runtime profile", got)
+	}
+}
+
+// profileRecordingClient records the inference profile each runtime and
+// evaluator is created with.
+type profileRecordingClient struct {
+	simulatedAWSClient
+	mu             sync.Mutex
+	runtimeProfile string
+	evalModels     map[string]string
+}
+
+func (c *profileRecordingClient) CreateRuntime(ctx context.Context, name string, cfg *Config) (string, error) {
+	c.mu.Lock()
+	c.runtimeProfile = cfg.RuntimeEnvVars[EnvInferenceProfile]
+	c.mu.Unlock()
+	return c.simulatedAWSClient.CreateRuntime(ctx, name, cfg)
+}
+
+func (c *profileRecordingClient) CreateEvaluator(ctx context.Context, name string, cfg *Config) (string, error) {
+	c.mu.Lock()
+	c.evalModels[name] = cfg.EvalModelIDs[name]
+	c.mu.Unlock()
+	return c.simulatedAWSClient.CreateEvaluator(ctx, name, cfg)
+}
+
+func TestApply_InferenceProfiles(t *testing.T) {
+	client := &profileRecordingClient{
+		simulatedAWSClient: *newSimulatedAWSClient("us-west-2"),
+		evalModels:         map[string]string{},
+	}
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) { return client, nil }
+
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"inference_profiles":{` +
+		`"runtime":{"copy_from":"` + testSystemProfile + `"},` +
+		`"evaluators":{"quality_check":{"id":"` + testSystemProfile + `"}}}}`
+	_, stateJSON, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     multiAgentPackWithEvals(),
+		DeployConfig: cfg,
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	wantARN := "arn:aws:bedrock:us-west-2:123456789012:application-inference-profile/evalpack_runtime_profile"
+	if client.runtimeProfile != wantARN {
+		t.Errorf("runtime %s = %q, want %q", EnvInferenceProfile, client.runtimeProfile, wantARN)
+	}
+	if got := client.evalModels["quality_check"]; got != testSystemProfile {
+		t.Errorf("quality_check model = %q, want %q", got, testSystemProfile)
+	}
+	if got := client.evalModels["latency_check"]; got != "" {
+		t.Errorf("latency_check model = %q, want its own model", got)
+	}
+
+	state, err := parseAdapterState(stateJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := state.Resources[0]; r.Type != ResTypeInferenceProfile || r.ARN != wantARN {
+		t.Errorf("first resource = %+v, want the runtime inference profile", r)
+	}
+}
+
+func TestCreateInferenceProfile_ResolvesSourceARN(t *testing.T) {
+	tests := []struct {
+		name      string
+		copyFrom  string
+		bodies    []string
+		wantCalls int
+	}{
+		{"foundation model", "anthropic.claude-3-haiku-20240307-v1:0", nil, 1},
+		{"arn", "arn:aws:bedrock:us-west-2::foundation-model/amazon.nova-pro-v1:0", nil, 1},
+		{
+			"system profile", testSystemProfile,
+			[]string{`{"inferenceProfileArn":"arn:aws:bedrock:us-west-2:123456789012:inference-profile/us.x"}`}, 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := `{"inferenceProfileArn":"arn:aws:bedrock:us-west-2:123456789012:application-inference-profile/p1"}`
+			c, _, stub := newStubbedRealClient(1, append(tt.bodies, created)...)
+			arn, err := c.CreateInferenceProfile(context.Background(), "mypack_runtime_profile", tt.copyFrom, &Config{})
+			if err != nil {
+				t.Fatalf("CreateInferenceProfile: %v", err)
+			}
+			if !strings.HasSuffix(arn, "application-inference-profile/p1") {
+				t.Errorf("arn = %q", arn)
+			}
+			if stub.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", stub.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCheckInferenceProfile(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"status":"ACTIVE"}`: StatusHealthy,
+		`{"status":"OTHER"}`:  StatusUnhealthy,
+	} {
+		c, _, _ := newStubbedRealClient(1, body)
+		got, err := c.CheckResource(context.Background(), ResourceState{
+			Type: ResTypeInferenceProfile, Name: "p", ARN: "arn:aws:bedrock:us-west-2:1:application-inference-profile/p",
+		})
+		if err != nil || got != want {
+			t.Errorf("%s: status = %q, %v; want %q", body, got, err, want)
+		}
+	}
+}
+
+func TestGenerateDesiredResources_InferenceProfiles(t *testing.T) {
+	pack := &prompt.Pack{ID: "mypack", Prompts: map[string]*prompt.PackPrompt{"default": {ID: "default"}}}
+	cfg := &Config{InferenceProfiles: &InferenceProfilesConfig{
+		Runtime: &InferenceProfileConfig{CopyFrom: testSystemProfile},
+	}}
+
+	desired := generateDesiredResources(pack, cfg)
+	if len(desired) != 2 || desired[0].Type != ResTypeInferenceProfile || desired[0].Name != "mypack_runtime_profile" {
+		t.Errorf("desired = %+v, want the runtime inference profile before the runtime", desired)
+	}
+}
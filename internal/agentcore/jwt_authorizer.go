@@ -0,0 +1,93 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// oidcDiscoveryTimeout bounds the request Plan makes to a2a_auth's
+// discovery URL.
+const oidcDiscoveryTimeout = 10 * time.Second
+
+// maxOIDCDiscoveryBytes caps how much of a discovery document Plan reads.
+const maxOIDCDiscoveryBytes = 1 << 20
+
+// oidcConfiguration holds the fields of an OpenID Connect discovery
+// document that the AgentCore JWT authorizer relies on.
+type oidcConfiguration struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// oidcDiscoveryFetcher returns the body served at an OIDC discovery URL.
+type oidcDiscoveryFetcher func(ctx context.Context, discoveryURL string) ([]byte, error)
+
+// fetchOIDCDiscovery is the oidcDiscoveryFetcher used by NewProvider.
+func fetchOIDCDiscovery(ctx context.Context, discoveryURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, oidcDiscoveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxOIDCDiscoveryBytes))
+}
+
+// checkJWTAuthorizer verifies, when a2a_auth uses JWT mode, that the
+// discovery URL serves an OIDC configuration with a JWKS URI and that the
+// authorizer restricts tokens to some audience or client. A runtime
+// deployed with a broken authorizer answers every request with 401, so
+// unlike the model and interceptor checks these are errors, not warnings.
+func checkJWTAuthorizer(ctx context.Context, fetch oidcDiscoveryFetcher, cfg *Config) []string {
+	auth := cfg.A2AAuth
+	if fetch == nil || auth == nil || auth.Mode != A2AAuthModeJWT {
+		return nil
+	}
+	var errs []string
+	if len(auth.AllowedAud) == 0 && len(auth.AllowedClts) == 0 {
+		errs = append(errs, "a2a_auth.allowed_audience or a2a_auth.allowed_clients must list at least one value")
+	}
+	body, err := fetch(ctx, auth.DiscoveryURL)
+	if err != nil {
+		return append(errs, fmt.Sprintf("a2a_auth.discovery_url %s could not be fetched: %v", auth.DiscoveryURL, err))
+	}
+	if docErr := validateOIDCConfiguration(body); docErr != nil {
+		errs = append(errs, fmt.Sprintf("a2a_auth.discovery_url %s is not a valid OIDC configuration: %v",
+			auth.DiscoveryURL, docErr))
+	}
+	return errs
+}
+
+// validateOIDCConfiguration checks that body is an OIDC discovery
+// document naming its issuer and an absolute JWKS URI.
+func validateOIDCConfiguration(body []byte) error {
+	var doc oidcConfiguration
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if doc.Issuer == "" {
+		return fmt.Errorf("issuer is missing")
+	}
+	if doc.JWKSURI == "" {
+		return fmt.Errorf("jwks_uri is missing")
+	}
+	u, err := url.Parse(doc.JWKSURI)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("jwks_uri %q is not an absolute URL", doc.JWKSURI)
+	}
+	return nil
+}
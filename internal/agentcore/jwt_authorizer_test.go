@@ -0,0 +1,110 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+const testOIDCDocument = `{"issuer":"https://login.example.com",` +
+	`"jwks_uri":"https://login.example.com/.well-known/jwks.json"}`
+
+func oidcFetcherFor(body string, err error) oidcDiscoveryFetcher {
+	return func(context.Context, string) ([]byte, error) { return []byte(body), err }
+}
+
+func jwtConfig(aud ...string) *Config {
+	return &Config{A2AAuth: &A2AAuthConfig{
+		Mode:         A2AAuthModeJWT,
+		DiscoveryURL: "https://login.example.com/.well-known/openid-configuration",
+		AllowedAud:   aud,
+	}}
+}
+
+func TestCheckJWTAuthorizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   *Config
+		fetch oidcDiscoveryFetcher
+		want  []string
+	}{
+		{"valid", jwtConfig("my-app"), oidcFetcherFor(testOIDCDocument, nil), nil},
+		{"iam mode is not fetched", &Config{A2AAuth: &A2AAuthConfig{Mode: A2AAuthModeIAM}},
+			oidcFetcherFor("", errors.New("unexpected fetch")), nil},
+		{"no a2a_auth", &Config{}, oidcFetcherFor("", errors.New("unexpected fetch")), nil},
+		{"no audiences or clients", jwtConfig(), oidcFetcherFor(testOIDCDocument, nil),
+			[]string{"allowed_audience or a2a_auth.allowed_clients must list at least one value"}},
+		{"unreachable", jwtConfig("my-app"), oidcFetcherFor("", errors.New("HTTP 404")),
+			[]string{"could not be fetched: HTTP 404"}},
+		{"not JSON", jwtConfig("my-app"), oidcFetcherFor("<html></html>", nil),
+			[]string{"not a valid OIDC configuration: invalid JSON"}},
+		{"missing jwks_uri", jwtConfig("my-app"), oidcFetcherFor(`{"issuer":"https://login.example.com"}`, nil),
+			[]string{"jwks_uri is missing"}},
+		{"relative jwks_uri", jwtConfig("my-app"),
+			oidcFetcherFor(`{"issuer":"https://login.example.com","jwks_uri":"/keys"}`, nil),
+			[]string{`jwks_uri "/keys" is not an absolute URL`}},
+		{"missing issuer", jwtConfig("my-app"), oidcFetcherFor(`{"jwks_uri":"https://login.example.com/keys"}`, nil),
+			[]string{"issuer is missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkJWTAuthorizer(context.Background(), tt.fetch, tt.cfg)
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("errors[%d] = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchOIDCDiscovery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testOIDCDocument))
+	}))
+	defer srv.Close()
+
+	body, err := fetchOIDCDiscovery(context.Background(), srv.URL+"/.well-known/openid-configuration")
+	if err != nil {
+		t.Fatalf("fetchOIDCDiscovery: %v", err)
+	}
+	if string(body) != testOIDCDocument {
+		t.Errorf("body = %q", body)
+	}
+
+	if _, err := fetchOIDCDiscovery(context.Background(), srv.URL+"/missing"); err == nil ||
+		!strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("missing document: err = %v, want HTTP 404", err)
+	}
+}
+
+func TestPlan_RejectsBrokenJWTAuthorizer(t *testing.T) {
+	p := newSimulatedProvider()
+	p.oidcFetchFunc = oidcFetcherFor(`{"issuer":"https://login.example.com"}`, nil)
+
+	_, err := p.Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(),
+		DeployConfig: fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+			`"runtime_binary_path":%q,"a2a_auth":{"mode":"jwt",`+
+			`"discovery_url":"https://login.example.com/.well-known/openid-configuration",`+
+			`"allowed_audience":["my-app"]}}`, testBinaryPath(t)),
+		ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid a2a_auth") ||
+		!strings.Contains(err.Error(), "jwks_uri is missing") {
+		t.Errorf("Plan error = %v, want an invalid a2a_auth error about jwks_uri", err)
+	}
+}
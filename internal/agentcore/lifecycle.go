@@ -0,0 +1,191 @@
+package agentcore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// EnvMaxConcurrentInvocations caps the invocations a runtime instance
+// serves at once.
+const EnvMaxConcurrentInvocations = "PROMPTPACK_MAX_CONCURRENT_INVOCATIONS"
+
+// Lifecycle bounds AgentCore accepts for idle_session_timeout and
+// max_lifetime.
+const (
+	minLifecycleDuration = time.Minute
+	maxLifecycleDuration = 8 * time.Hour
+)
+
+// Runtime metadata keys recording the lifecycle Apply set, so Plan can
+// show what an update changes.
+const (
+	metaIdleSessionTimeout       = "idle_session_timeout"
+	metaMaxLifetime              = "max_lifetime"
+	metaMaxConcurrentInvocations = "max_concurrent_invocations"
+)
+
+// LifecycleConfig tunes how long runtime sessions and instances live and
+// how much work each instance takes on. Unset fields keep the service
+// defaults.
+type LifecycleConfig struct {
+	// IdleSessionTimeout ends a session that has been idle this long, such
+	// as "10m". AgentCore defaults to 15 minutes.
+	IdleSessionTimeout string `json:"idle_session_timeout,omitempty"`
+
+	// MaxLifetime replaces a runtime instance once it has run this long,
+	// such as "4h". AgentCore defaults to 8 hours.
+	MaxLifetime string `json:"max_lifetime,omitempty"`
+
+	// MaxConcurrentInvocations caps the invocations each instance serves
+	// at once; the runtime answers 429 past it. AgentCore has no such
+	// setting, so the runtime bridge enforces it. 0 means unlimited.
+	MaxConcurrentInvocations int `json:"max_concurrent_invocations,omitempty"`
+}
+
+// validateLifecycle checks the lifecycle block.
+func validateLifecycle(c *LifecycleConfig) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	idle, err := parseLifecycleDuration("lifecycle.idle_session_timeout", c.IdleSessionTimeout)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	lifetime, err := parseLifecycleDuration("lifecycle.max_lifetime", c.MaxLifetime)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if idle > 0 && lifetime > 0 && idle > lifetime {
+		errs = append(errs, fmt.Sprintf("lifecycle.idle_session_timeout %s must not be longer than max_lifetime %s",
+			idle, lifetime))
+	}
+	if c.MaxConcurrentInvocations < 0 {
+		errs = append(errs, fmt.Sprintf("lifecycle.max_concurrent_invocations %d must not be negative",
+			c.MaxConcurrentInvocations))
+	}
+	return errs
+}
+
+// parseLifecycleDuration parses an optional lifecycle duration, which
+// AgentCore takes in whole seconds.
+func parseLifecycleDuration(field, value string) (time.Duration, error) {
+	d, err := parseBoundedDuration(field, value, minLifecycleDuration, maxLifecycleDuration)
+	if err != nil {
+		return 0, err
+	}
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("%s %s must be a whole number of seconds", field, d)
+	}
+	return d, nil
+}
+
+// lifecycleSeconds returns a validated lifecycle duration in seconds, or
+// 0 when it is unset.
+func lifecycleSeconds(value string) int32 {
+	d, _ := time.ParseDuration(value)
+	return int32(d / time.Second) //nolint:gosec // validated to at most 8 hours
+}
+
+// buildLifecycleConfiguration returns the runtime lifecycle to send with
+// CreateAgentRuntime and UpdateAgentRuntime, or nil to keep the service
+// defaults.
+func buildLifecycleConfiguration(cfg *Config) *types.LifecycleConfiguration {
+	if cfg.Lifecycle == nil {
+		return nil
+	}
+	var lc types.LifecycleConfiguration
+	if s := lifecycleSeconds(cfg.Lifecycle.IdleSessionTimeout); s > 0 {
+		lc.IdleRuntimeSessionTimeout = aws.Int32(s)
+	}
+	if s := lifecycleSeconds(cfg.Lifecycle.MaxLifetime); s > 0 {
+		lc.MaxLifetime = aws.Int32(s)
+	}
+	if lc.IdleRuntimeSessionTimeout == nil && lc.MaxLifetime == nil {
+		return nil
+	}
+	return &lc
+}
+
+// injectLifecycleEnvVars passes the per-instance invocation cap to the
+// runtime bridge.
+func injectLifecycleEnvVars(env map[string]string, lifecycle *LifecycleConfig) {
+	if lifecycle != nil && lifecycle.MaxConcurrentInvocations > 0 {
+		env[EnvMaxConcurrentInvocations] = strconv.Itoa(lifecycle.MaxConcurrentInvocations)
+	}
+}
+
+// lifecycleMetadata returns the runtime metadata recording cfg's
+// lifecycle. Unset fields are left out.
+func lifecycleMetadata(cfg *Config) map[string]string {
+	if cfg.Lifecycle == nil {
+		return nil
+	}
+	meta := make(map[string]string)
+	if s := lifecycleSeconds(cfg.Lifecycle.IdleSessionTimeout); s > 0 {
+		meta[metaIdleSessionTimeout] = strconv.Itoa(int(s))
+	}
+	if s := lifecycleSeconds(cfg.Lifecycle.MaxLifetime); s > 0 {
+		meta[metaMaxLifetime] = strconv.Itoa(int(s))
+	}
+	if n := cfg.Lifecycle.MaxConcurrentInvocations; n > 0 {
+		meta[metaMaxConcurrentInvocations] = strconv.Itoa(n)
+	}
+	if len(meta) == 0 {
+		return nil
+	}
+	return meta
+}
+
+// annotateRuntimeLifecycle records the lifecycle on each deployed runtime.
+func annotateRuntimeLifecycle(resources []ResourceState, cfg *Config) {
+	meta := lifecycleMetadata(cfg)
+	if meta == nil {
+		return
+	}
+	for i := range resources {
+		r := &resources[i]
+		if r.Type != ResTypeAgentRuntime || r.Status == ResStatusFailed {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string, len(meta))
+		}
+		for k, v := range meta {
+			r.Metadata[k] = v
+		}
+	}
+}
+
+// lifecycleChanges describes how cfg's lifecycle differs from the one
+// recorded on a prior runtime, or returns "" when it is unchanged.
+func lifecycleChanges(prior ResourceState, cfg *Config) string {
+	want := lifecycleMetadata(cfg)
+	var changes []string
+	for _, key := range []string{metaIdleSessionTimeout, metaMaxLifetime, metaMaxConcurrentInvocations} {
+		from, to := prior.Metadata[key], want[key]
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s",
+				key, lifecycleValueLabel(key, from), lifecycleValueLabel(key, to)))
+		}
+	}
+	return strings.Join(changes, ", ")
+}
+
+// lifecycleValueLabel formats a recorded lifecycle value for a plan
+// detail.
+func lifecycleValueLabel(key, value string) string {
+	switch {
+	case value == "":
+		return "default"
+	case key == metaMaxConcurrentInvocations:
+		return value
+	default:
+		return value + "s"
+	}
+}
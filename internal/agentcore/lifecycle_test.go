@@ -0,0 +1,146 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestValidateLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *LifecycleConfig
+		wantErr string
+	}{
+		{name: "nil"},
+		{name: "valid", cfg: &LifecycleConfig{IdleSessionTimeout: "10m", MaxLifetime: "4h", MaxConcurrentInvocations: 8}},
+		{name: "idle too short", cfg: &LifecycleConfig{IdleSessionTimeout: "30s"}, wantErr: "between 1m0s and 8h0m0s"},
+		{name: "lifetime too long", cfg: &LifecycleConfig{MaxLifetime: "9h"}, wantErr: "lifecycle.max_lifetime"},
+		{name: "not a duration", cfg: &LifecycleConfig{MaxLifetime: "soon"}, wantErr: "not a valid duration"},
+		{name: "fractional seconds", cfg: &LifecycleConfig{IdleSessionTimeout: "90.5s"}, wantErr: "whole number"},
+		{
+			name:    "idle longer than lifetime",
+			cfg:     &LifecycleConfig{IdleSessionTimeout: "2h", MaxLifetime: "1h"},
+			wantErr: "must not be longer than max_lifetime",
+		},
+		{name: "negative cap", cfg: &LifecycleConfig{MaxConcurrentInvocations: -1}, wantErr: "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateLifecycle(tt.cfg)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("errs = %v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildLifecycleConfiguration(t *testing.T) {
+	if lc := buildLifecycleConfiguration(&Config{}); lc != nil {
+		t.Errorf("no lifecycle: got %+v, want nil", lc)
+	}
+	capOnly := &Config{Lifecycle: &LifecycleConfig{MaxConcurrentInvocations: 4}}
+	if lc := buildLifecycleConfiguration(capOnly); lc != nil {
+		t.Errorf("cap only: got %+v, want nil", lc)
+	}
+
+	lc := buildLifecycleConfiguration(&Config{Lifecycle: &LifecycleConfig{IdleSessionTimeout: "10m", MaxLifetime: "4h"}})
+	if lc == nil || aws.ToInt32(lc.IdleRuntimeSessionTimeout) != 600 || aws.ToInt32(lc.MaxLifetime) != 14400 {
+		t.Errorf("lifecycle = %+v, want 600s idle and 14400s lifetime", lc)
+	}
+}
+
+func TestBuildRuntimeEnvVars_MaxConcurrentInvocations(t *testing.T) {
+	env := buildRuntimeEnvVars(&Config{Lifecycle: &LifecycleConfig{MaxConcurrentInvocations: 8}})
+	if env[EnvMaxConcurrentInvocations] != "8" {
+		t.Errorf("%s = %q, want 8", EnvMaxConcurrentInvocations, env[EnvMaxConcurrentInvocations])
+	}
+	env = buildRuntimeEnvVars(&Config{Lifecycle: &LifecycleConfig{IdleSessionTimeout: "10m"}})
+	if _, ok := env[EnvMaxConcurrentInvocations]; ok {
+		t.Errorf("%s set without a cap", EnvMaxConcurrentInvocations)
+	}
+}
+
+func TestApply_RecordsRuntimeLifecycle(t *testing.T) {
+	cfg := strings.TrimSuffix(validConfig(t), "}") +
+		`,"lifecycle":{"idle_session_timeout":"10m","max_concurrent_invocations":8}}`
+	_, raw, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: cfg,
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	rt, ok := findResourceOfType(&state, ResTypeAgentRuntime)
+	if !ok {
+		t.Fatal("no agent_runtime in state")
+	}
+	if rt.Metadata[metaIdleSessionTimeout] != "600" || rt.Metadata[metaMaxConcurrentInvocations] != "8" {
+		t.Errorf("metadata = %v", rt.Metadata)
+	}
+	if _, ok := rt.Metadata[metaMaxLifetime]; ok {
+		t.Errorf("metadata = %v, want no max_lifetime", rt.Metadata)
+	}
+}
+
+func TestPlan_RuntimeLifecycleChange(t *testing.T) {
+	prior := AdapterState{Resources: []ResourceState{{
+		Type: ResTypeAgentRuntime, Name: "mypack", ARN: "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/mypack-abc",
+		Metadata: map[string]string{metaIdleSessionTimeout: "900", metaMaxConcurrentInvocations: "8"},
+	}}}
+	tests := []struct {
+		name       string
+		lifecycle  string
+		wantDetail string
+	}{
+		{
+			name:      "changed",
+			lifecycle: `,"lifecycle":{"idle_session_timeout":"10m","max_lifetime":"4h","max_concurrent_invocations":8}`,
+			wantDetail: "Update agent_runtime mypack (lifecycle: idle_session_timeout 900s -> 600s, " +
+				"max_lifetime default -> 14400s)",
+		},
+		{
+			name:       "unchanged",
+			lifecycle:  `,"lifecycle":{"idle_session_timeout":"15m","max_concurrent_invocations":8}`,
+			wantDetail: "Update agent_runtime mypack",
+		},
+		{
+			name: "removed",
+			wantDetail: "Update agent_runtime mypack (lifecycle: idle_session_timeout 900s -> default, " +
+				"max_concurrent_invocations 8 -> default)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+				PackJSON:     singleAgentPack(),
+				DeployConfig: strings.TrimSuffix(validDeployConfig, "}") + tt.lifecycle + "}",
+				PriorState:   mustJSON(t, prior),
+				ArenaConfig:  validArenaConfigJSON,
+			})
+			if err != nil {
+				t.Fatalf("Plan: %v", err)
+			}
+			for _, c := range resp.Changes {
+				if c.Type == ResTypeAgentRuntime && c.Detail != tt.wantDetail {
+					t.Errorf("detail = %q, want %q", c.Detail, tt.wantDetail)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// MethodLint is the JSON-RPC method that checks a pack against AgentCore's
+// limits before Plan. It extends the standard adaptersdk method set.
+const MethodLint = "lint"
+
+// Lint finding severities. Errors are limits AgentCore enforces; warnings
+// are adjustable quotas or sizes likely to cause trouble.
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+)
+
+// Lint rule names, reported in LintFinding.Rule.
+const (
+	lintRuleResourceName     = "resource_name"
+	lintRuleToolName         = "tool_name"
+	lintRuleGatewayTools     = "gateway_tools"
+	lintRuleRuntimeEnv       = "runtime_env_size"
+	lintRuleEvalInstructions = "eval_instructions"
+	lintRuleCedarStatement   = "cedar_statement"
+	lintRuleAgents           = "agents"
+)
+
+// Limits lint checks a pack against.
+const (
+	// maxGatewayToolNameLen is the longest tool name Bedrock models accept.
+	// The gateway lists each tool as "<target>___<tool>", and the adapter
+	// names targets after their tools (see gatewayTargetName).
+	maxGatewayToolNameLen = 64
+
+	// maxGatewayTargets is AgentCore's default quota of targets per
+	// gateway. The adapter creates one target per pack tool.
+	maxGatewayTargets = 100
+
+	// maxRuntimeEnvBytes is the combined size of a runtime's environment
+	// variable names and values lint allows.
+	maxRuntimeEnvBytes = 16 << 10
+
+	// maxEvalInstructionsLen is the longest judge instruction AgentCore
+	// accepts for an evaluator.
+	maxEvalInstructionsLen = 10000
+
+	// maxCedarStatementBytes is the largest Cedar statement AgentCore
+	// accepts in one policy.
+	maxCedarStatementBytes = 10000
+)
+
+// gatewayToolNameRE matches the characters Bedrock models accept in a tool
+// name.
+var gatewayToolNameRE = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// LintRequest is the params object of a lint call. The deploy and arena
+// configs are optional; they refine names and environment sizes.
+type LintRequest struct {
+	PackJSON     string `json:"pack_json"`
+	DeployConfig string `json:"deploy_config,omitempty"`
+	ArenaConfig  string `json:"arena_config,omitempty"`
+}
+
+// LintFinding is one problem lint found in a pack.
+type LintFinding struct {
+	Severity string `json:"severity"` // "error" or "warning"
+	Rule     string `json:"rule"`
+	Subject  string `json:"subject,omitempty"` // the agent, tool, eval, or prompt
+	Message  string `json:"message"`
+}
+
+// LintResponse is the result of a lint call, with errors ahead of
+// warnings.
+type LintResponse struct {
+	Findings []LintFinding `json:"findings"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+}
+
+// Lint checks a pack against AgentCore-specific limits: resource and tool
+// names, tools per gateway, runtime environment size, judge instruction
+// length, Cedar statement size, and the multi-agent entry. It calls no AWS
+// APIs.
+func (p *Provider) Lint(_ context.Context, req *LintRequest) (*LintResponse, error) {
+	pack, err := parsePack(req.PackJSON)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse pack: %w", err)
+	}
+	raw := req.DeployConfig
+	if raw == "" {
+		raw = "{}"
+	}
+	cfg, err := p.loadConfig(raw, arenaConfigVars(req.ArenaConfig))
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
+	}
+	if cfg.ArenaConfig, err = parseOptionalArenaConfig(req.ArenaConfig); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+
+	var findings []LintFinding
+	findings = append(findings, lintResourceNames(pack, cfg)...)
+	findings = append(findings, lintTools(pack)...)
+	findings = append(findings, lintRuntimeEnv(pack, cfg)...)
+	findings = append(findings, lintEvals(pack)...)
+	findings = append(findings, lintCedar(pack, cfg)...)
+	findings = append(findings, lintAgents(pack)...)
+	return newLintResponse(findings), nil
+}
+
+// parseOptionalArenaConfig parses the arena config, which lint does not
+// require.
+func parseOptionalArenaConfig(raw string) (*ArenaConfig, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	return parseArenaConfig(raw)
+}
+
+// newLintResponse sorts findings and counts them by severity.
+func newLintResponse(findings []LintFinding) *LintResponse {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity == LintSeverityError
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Subject < b.Subject
+	})
+	resp := &LintResponse{Findings: make([]LintFinding, 0, len(findings))}
+	for _, f := range findings {
+		if f.Severity == LintSeverityError {
+			resp.Errors++
+		} else {
+			resp.Warnings++
+		}
+		resp.Findings = append(resp.Findings, f)
+	}
+	return resp
+}
+
+// lintResourceNames checks every AWS resource name the pack derives, with
+// any workspace appended, and that no two elements derive the same one.
+func lintResourceNames(pack *prompt.Pack, cfg *Config) []LintFinding {
+	var findings []LintFinding
+	for name, resType := range collectDerivedNames(pack, cfg) {
+		if err := validateAWSName(cfg.awsName(name), resType); err != nil {
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityError, Rule: lintRuleResourceName, Subject: name, Message: err.Error(),
+			})
+		}
+	}
+	for _, collision := range findNameCollisions(pack, cfg) {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityError, Rule: lintRuleResourceName, Message: collision,
+		})
+	}
+	return findings
+}
+
+// lintTools checks the pack's tool names and count against the gateway's
+// limits.
+func lintTools(pack *prompt.Pack) []LintFinding {
+	var findings []LintFinding
+	for name := range pack.Tools {
+		listed := gatewayTargetName(name) + gatewayToolSeparator + name
+		switch {
+		case !gatewayToolNameRE.MatchString(name):
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityError, Rule: lintRuleToolName, Subject: name,
+				Message: fmt.Sprintf("tool name %q must contain only letters, digits, _ and -", name),
+			})
+		case len(listed) > maxGatewayToolNameLen:
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityError, Rule: lintRuleToolName, Subject: name,
+				Message: fmt.Sprintf("the gateway lists tool %q as %q, longer than %d characters",
+					name, listed, maxGatewayToolNameLen),
+			})
+		}
+	}
+	if n := len(deployedTools(pack)); n > maxGatewayTargets {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityWarning, Rule: lintRuleGatewayTools,
+			Message: fmt.Sprintf("the pack's %d deployed tools exceed the default quota of %d targets per gateway",
+				n, maxGatewayTargets),
+		})
+	}
+	return findings
+}
+
+// lintRuntimeEnv checks the size of the environment each runtime gets.
+// Apply adds the A2A endpoint map to multi-agent runtimes, which this
+// does not count.
+func lintRuntimeEnv(pack *prompt.Pack, cfg *Config) []LintFinding {
+	cfg.PackTools = pack.Tools
+	cfg.PromptNames = extractPromptNames(pack)
+	cfg.AgentTools = runtimeTools(pack)
+	cfg.RuntimeNames = agentRuntimeNames(pack)
+	cfg.RuntimeEnvVars = buildRuntimeEnvVars(cfg)
+	injectMetricsConfig(cfg, pack)
+	injectDashboardConfig(cfg, pack)
+
+	var findings []LintFinding
+	for _, name := range agentRuntimeNames(pack) {
+		size := 0
+		for k, v := range runtimeEnvVarsForAgent(cfg, name) {
+			size += len(k) + len(v)
+		}
+		if size > maxRuntimeEnvBytes {
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityWarning, Rule: lintRuleRuntimeEnv, Subject: name,
+				Message: fmt.Sprintf("runtime environment is %d bytes, more than %d", size, maxRuntimeEnvBytes),
+			})
+		}
+	}
+	return findings
+}
+
+// lintEvals checks the judge instructions of the pack's llm_as_judge
+// evals.
+func lintEvals(pack *prompt.Pack) []LintFinding {
+	var findings []LintFinding
+	for i := range pack.Evals {
+		e := &pack.Evals[i]
+		if e.Type != evalTypeLLMAsJudge {
+			continue
+		}
+		instructions, err := evalInstructions(e.Params)
+		switch {
+		case err != nil:
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityError, Rule: lintRuleEvalInstructions, Subject: e.ID, Message: err.Error(),
+			})
+		case len(instructions) > maxEvalInstructionsLen:
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityError, Rule: lintRuleEvalInstructions, Subject: e.ID,
+				Message: fmt.Sprintf("judge instructions are %d characters, more than %d",
+					len(instructions), maxEvalInstructionsLen),
+			})
+		}
+	}
+	return findings
+}
+
+// lintCedar checks the size of the Cedar statements generated for each
+// prompt's tool blocklist. The gateway ARN does not exist yet, so a
+// placeholder of typical length stands in for it.
+func lintCedar(pack *prompt.Pack, cfg *Config) []LintFinding {
+	gatewayARN := formatARN("bedrock-agentcore", cfg.Region, extractAccountFromARN(cfg.RuntimeRoleARN),
+		"gateway/"+cfg.awsName(pack.ID+"_gateway")+"-0123456789")
+	var agents []string
+	if adaptersdk.IsMultiAgent(pack) {
+		agents = agentRuntimeNames(pack)
+	}
+
+	var findings []LintFinding
+	for _, name := range policyResourceNames(pack) {
+		pattern := ""
+		if slices.Contains(agents, name) {
+			pattern = agentPrincipalPattern(cfg, name)
+		}
+		p := pack.Prompts[name]
+		for i, stmt := range generateCedarStatements(p.Validators, p.ToolPolicy, gatewayARN, nil, pattern) {
+			if len(stmt) > maxCedarStatementBytes {
+				findings = append(findings, LintFinding{
+					Severity: LintSeverityError, Rule: lintRuleCedarStatement, Subject: name,
+					Message: fmt.Sprintf("Cedar statement %d is %d bytes, more than %d",
+						i, len(stmt), maxCedarStatementBytes),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// lintAgents checks the multi-agent section: the entry and every member
+// must name a prompt.
+func lintAgents(pack *prompt.Pack) []LintFinding {
+	errs, warnings := pack.ValidateAgents()
+	findings := make([]LintFinding, 0, len(errs)+len(warnings))
+	for _, e := range errs {
+		findings = append(findings, LintFinding{Severity: LintSeverityError, Rule: lintRuleAgents, Message: e})
+	}
+	for _, w := range warnings {
+		findings = append(findings, LintFinding{Severity: LintSeverityWarning, Rule: lintRuleAgents, Message: w})
+	}
+	return findings
+}
@@ -0,0 +1,160 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func lintPack(t *testing.T, p map[string]any) string {
+	t.Helper()
+	p["template_engine"] = map[string]any{"version": "1.0", "syntax": "handlebars"}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func findingsByRule(resp *LintResponse) map[string][]LintFinding {
+	byRule := make(map[string][]LintFinding)
+	for _, f := range resp.Findings {
+		byRule[f.Rule] = append(byRule[f.Rule], f)
+	}
+	return byRule
+}
+
+func TestLint_CleanPacks(t *testing.T) {
+	for _, pack := range []string{singleAgentPackWithTools(), multiAgentPack(), multiAgentPackWithEvals()} {
+		resp, err := newSimulatedProvider().Lint(context.Background(), &LintRequest{
+			PackJSON: pack, DeployConfig: `{"region":"us-west-2"}`,
+		})
+		if err != nil {
+			t.Fatalf("Lint: %v", err)
+		}
+		if len(resp.Findings) != 0 {
+			t.Errorf("findings = %+v, want none", resp.Findings)
+		}
+	}
+}
+
+func TestLint_Findings(t *testing.T) {
+	tools := map[string]any{
+		"search.v2":             map[string]any{"name": "search.v2", "description": "bad charset"},
+		strings.Repeat("t", 31): map[string]any{"name": strings.Repeat("t", 31), "description": "too long"},
+	}
+	for i := range maxGatewayTargets {
+		name := fmt.Sprintf("tool%d", i)
+		tools[name] = map[string]any{"name": name, "description": "filler"}
+	}
+	toolNames := make([]string, 0, len(tools))
+	for name := range tools {
+		toolNames = append(toolNames, name)
+	}
+	pack := lintPack(t, map[string]any{
+		"id": "lintpack", "version": "v1.0.0", "name": "Lint Pack",
+		"prompts": map[string]any{
+			"coordinator": map[string]any{
+				"id": "coordinator", "name": "C", "system_template": "x", "version": "v1", "tools": toolNames,
+			},
+		},
+		"agents": map[string]any{
+			"entry":   "missing",
+			"members": map[string]any{"coordinator": map[string]any{"description": "c"}},
+		},
+		"tools": tools,
+		"evals": []map[string]any{
+			{"id": "templated", "type": "llm_as_judge", "trigger": "every_turn",
+				"params": map[string]any{"template": "brevity"}},
+			{"id": "verbose", "type": "llm_as_judge", "trigger": "every_turn",
+				"params": map[string]any{"instructions": strings.Repeat("x", maxEvalInstructionsLen+1)}},
+		},
+	})
+
+	resp, err := newSimulatedProvider().Lint(context.Background(), &LintRequest{
+		PackJSON: pack, DeployConfig: `{"region":"us-west-2"}`,
+	})
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	byRule := findingsByRule(resp)
+	for rule, want := range map[string]int{
+		lintRuleToolName:         2,
+		lintRuleGatewayTools:     1,
+		lintRuleEvalInstructions: 2,
+		lintRuleAgents:           1,
+		lintRuleResourceName:     1, // search.v2_tool_gw
+	} {
+		if got := len(byRule[rule]); got != want {
+			t.Errorf("%s findings = %+v, want %d", rule, byRule[rule], want)
+		}
+	}
+	if resp.Errors != 6 || resp.Warnings != 1 {
+		t.Errorf("errors, warnings = %d, %d, want 6, 1", resp.Errors, resp.Warnings)
+	}
+	if last := resp.Findings[len(resp.Findings)-1]; last.Severity != LintSeverityWarning {
+		t.Errorf("last finding = %+v, want the warning after the errors", last)
+	}
+}
+
+func TestLint_WorkspaceLengthensNames(t *testing.T) {
+	pack := lintPack(t, map[string]any{
+		"id": strings.Repeat("p", 41), "version": "v1.0.0", "name": "P",
+		"prompts": map[string]any{"chat": map[string]any{"id": "chat", "name": "C", "system_template": "x"}},
+	})
+	resp, err := newSimulatedProvider().Lint(context.Background(), &LintRequest{
+		PackJSON: pack, DeployConfig: `{"region":"us-west-2","workspace":"staging"}`,
+	})
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	if got := findingsByRule(resp)[lintRuleResourceName]; len(got) != 1 {
+		t.Errorf("resource_name findings = %+v, want the runtime name with the workspace", got)
+	}
+}
+
+func TestLint_RuntimeEnvSize(t *testing.T) {
+	t.Setenv("LINT_BIG_VALUE", strings.Repeat("v", maxRuntimeEnvBytes))
+	resp, err := newSimulatedProvider().Lint(context.Background(), &LintRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: `{"region":"us-west-2","runtime_env_passthrough":["LINT_BIG_VALUE"]}`,
+	})
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	got := findingsByRule(resp)[lintRuleRuntimeEnv]
+	if len(got) != 1 || got[0].Subject != "mypack" || got[0].Severity != LintSeverityWarning {
+		t.Errorf("runtime_env_size findings = %+v, want one warning for the runtime", got)
+	}
+}
+
+func TestLint_CedarStatementSize(t *testing.T) {
+	blocked := strings.Repeat("b", maxCedarStatementBytes)
+	pack := lintPack(t, map[string]any{
+		"id": "cedarpack", "version": "v1.0.0", "name": "P",
+		"prompts": map[string]any{"chat": map[string]any{
+			"id": "chat", "name": "C", "system_template": "x",
+			"tool_policy": map[string]any{"blocklist": []string{blocked, "delete"}},
+		}},
+	})
+	resp, err := newSimulatedProvider().Lint(context.Background(), &LintRequest{PackJSON: pack})
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	got := findingsByRule(resp)[lintRuleCedarStatement]
+	if len(got) != 1 || got[0].Subject != "chat" || !strings.Contains(got[0].Message, "statement 0") {
+		t.Errorf("cedar_statement findings = %+v, want the oversized blocklist entry", got)
+	}
+}
+
+func TestLint_BadInput(t *testing.T) {
+	p := newSimulatedProvider()
+	if _, err := p.Lint(context.Background(), &LintRequest{PackJSON: "{"}); err == nil {
+		t.Error("want an error for an unparsable pack")
+	}
+	if _, err := p.Lint(context.Background(), &LintRequest{PackJSON: singleAgentPack(), DeployConfig: "{"}); err == nil {
+		t.Error("want an error for an unparsable deploy config")
+	}
+}
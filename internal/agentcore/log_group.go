@@ -0,0 +1,274 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// cloudWatchRetentionDays lists the retention periods CloudWatch Logs
+// accepts, in days.
+var cloudWatchRetentionDays = []int{
+	1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731,
+	1096, 1827, 2192, 2557, 2922, 3288, 3653,
+}
+
+// Log group metadata keys stored in ResourceState.Metadata.
+const (
+	metaLogGroupName  = "log_group"
+	metaRetentionDays = "retention_days"
+)
+
+// LogsConfig provisions a CloudWatch log group for each runtime, so its
+// logs expire instead of piling up in the group AgentCore would otherwise
+// create with no retention.
+type LogsConfig struct {
+	// RetentionDays is how long CloudWatch keeps runtime logs. It must be
+	// one of the periods CloudWatch accepts, such as 7, 30, or 365.
+	RetentionDays int `json:"retention_days"`
+
+	// RetainOnDestroy keeps the log groups, and the logs in them, when the
+	// deployment is destroyed.
+	RetainOnDestroy bool `json:"retain_on_destroy,omitempty"`
+}
+
+// validateLogs checks the logs block.
+func validateLogs(c *LogsConfig) []string {
+	if c == nil {
+		return nil
+	}
+	if !slices.Contains(cloudWatchRetentionDays, c.RetentionDays) {
+		return []string{fmt.Sprintf("logs.retention_days %d must be a CloudWatch retention period: %v",
+			c.RetentionDays, cloudWatchRetentionDays)}
+	}
+	return nil
+}
+
+// retainsLogGroups reports whether Destroy keeps the runtime log groups.
+func (c *Config) retainsLogGroups() bool {
+	return c.Logs != nil && c.Logs.RetainOnDestroy
+}
+
+// invokedEndpoint returns the endpoint clients invoke runtimes through,
+// which names the log group AgentCore writes to.
+func (c *Config) invokedEndpoint() string {
+	if c.RuntimeEndpoint != "" {
+		return c.RuntimeEndpoint
+	}
+	return defaultEndpointName
+}
+
+// runtimeLogGroupName returns the log group AgentCore writes a runtime's
+// logs to when it is invoked through endpoint, or "" when the ARN has no
+// runtime ID.
+func runtimeLogGroupName(runtimeARN, endpoint string) string {
+	id := extractResourceID(runtimeARN, "runtime")
+	if id == "" {
+		return ""
+	}
+	return runtimeLogGroupPrefix + id + "-" + endpoint
+}
+
+// generateLogGroupResources returns one log_group change per planned agent
+// runtime when logs is configured.
+func generateLogGroupResources(desired []deploy.ResourceChange, cfg *Config) []deploy.ResourceChange {
+	if cfg.Logs == nil {
+		return nil
+	}
+	var groups []deploy.ResourceChange
+	for _, d := range desired {
+		if d.Type != ResTypeAgentRuntime {
+			continue
+		}
+		groups = append(groups, deploy.ResourceChange{
+			Type:   ResTypeLogGroup,
+			Name:   d.Name,
+			Action: deploy.ActionCreate,
+			Detail: fmt.Sprintf("Create log group for %s with %d-day retention", d.Name, cfg.Logs.RetentionDays),
+		})
+	}
+	return groups
+}
+
+// applyRuntimeLogGroups creates or updates the log group of every deployed
+// runtime. It runs after the runtime endpoints, whose name is part of the
+// log group's.
+func applyRuntimeLogGroups(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if ac.cfg.Logs == nil {
+		return resources, applyErr, nil
+	}
+	var runtimes []ResourceState
+	for _, r := range resources {
+		if r.Type == ResTypeAgentRuntime && r.ARN != "" && r.Status != ResStatusFailed {
+			runtimes = append(runtimes, r)
+		}
+	}
+
+	for i, rt := range runtimes {
+		pct := ac.span.at(i, len(runtimes))
+		op := resolveOp(ResTypeLogGroup, rt.Name, true, ac.priorMap)
+
+		if err := ac.reporter.Progress(fmt.Sprintf("%s %s: %s", op.verb, ResTypeLogGroup, rt.Name), pct); err != nil {
+			return resources, applyErr, err
+		}
+
+		res, err := deployLogGroup(ctx, ac, rt, op)
+		if err != nil {
+			deployErr := newDeployError(op.failVerb, ResTypeLogGroup, rt.Name, err)
+			_ = ac.reporter.Error(deployErr)
+			resources = append(resources, ResourceState{
+				Type: ResTypeLogGroup, Name: rt.Name, Status: ResStatusFailed,
+			})
+			applyErr = combineErrors(applyErr, deployErr)
+			continue
+		}
+
+		if err := ac.reporter.Resource(&deploy.ResourceResult{
+			Type: ResTypeLogGroup, Name: rt.Name, Action: op.action,
+			Status: op.status, Detail: res.Metadata[metaLogGroupName],
+		}); err != nil {
+			return resources, applyErr, err
+		}
+		resources = append(resources, res)
+	}
+	return resources, applyErr, nil
+}
+
+// deployLogGroup creates or updates one runtime's log group.
+func deployLogGroup(
+	ctx context.Context, ac *applyContext, rt ResourceState, op resourceOp,
+) (ResourceState, error) {
+	name := runtimeLogGroupName(rt.ARN, ac.cfg.invokedEndpoint())
+	if name == "" {
+		return ResourceState{}, fmt.Errorf("could not extract runtime ID from ARN %q", rt.ARN)
+	}
+	arn, err := ac.client.PutLogGroup(ctx, name, ac.cfg)
+	if err != nil {
+		return ResourceState{}, err
+	}
+	return ResourceState{
+		Type:   ResTypeLogGroup,
+		Name:   rt.Name,
+		ARN:    arn,
+		Status: op.status,
+		Metadata: map[string]string{
+			metaLogGroupName:  name,
+			metaRetentionDays: strconv.Itoa(ac.cfg.Logs.RetentionDays),
+		},
+	}, nil
+}
+
+// splitRetainedLogGroups separates the log groups Destroy keeps when
+// logs.retain_on_destroy is set from the resources it deletes.
+func splitRetainedLogGroups(resources []ResourceState, cfg *Config) (deletable, retained []ResourceState) {
+	if !cfg.retainsLogGroups() {
+		return resources, nil
+	}
+	for _, r := range resources {
+		if r.Type == ResTypeLogGroup {
+			retained = append(retained, r)
+		} else {
+			deletable = append(deletable, r)
+		}
+	}
+	return deletable, retained
+}
+
+// emitRetainedSkips reports each log group Destroy left in place.
+func emitRetainedSkips(callback deploy.DestroyCallback, retained []ResourceState) {
+	for _, res := range retained {
+		_ = callback(&deploy.DestroyEvent{
+			Type:    ErrCategoryResource,
+			Message: fmt.Sprintf("Retained %s %q", res.Type, res.Name),
+			Resource: &deploy.ResourceResult{
+				Type: res.Type, Name: res.Name,
+				Action: deploy.ActionNoChange, Status: ResStatusSkipped,
+				Detail: retainReasonLogGroup,
+			},
+		})
+	}
+}
+
+// logGroupARN returns a log group's ARN without the ":*" suffix
+// DescribeLogGroups reports, which TagResource does not accept.
+func logGroupARN(region, accountID, name string) string {
+	return formatARN("logs", region, accountID, "log-group:"+name)
+}
+
+// PutLogGroup creates the log group if it does not exist, tags it, and
+// sets its retention. AgentCore may already have created the group for a
+// runtime that was invoked before logs was configured, so an existing
+// group is tagged and updated instead.
+func (c *realAWSClient) PutLogGroup(ctx context.Context, name string, cfg *Config) (string, error) {
+	arn := logGroupARN(cfg.Region, extractAccountFromARN(cfg.RuntimeRoleARN), name)
+	input := &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(name)}
+	if len(cfg.ResourceTags) > 0 {
+		input.Tags = cfg.ResourceTags
+	}
+	_, err := c.logsClient.CreateLogGroup(ctx, input)
+	if err != nil && !isAlreadyExists(err) {
+		return "", fmt.Errorf("CreateLogGroup %q: %w", name, err)
+	}
+	if err != nil && len(cfg.ResourceTags) > 0 {
+		_, err = c.logsClient.TagResource(ctx, &cloudwatchlogs.TagResourceInput{
+			ResourceArn: aws.String(arn),
+			Tags:        cfg.ResourceTags,
+		})
+		if err != nil {
+			return "", fmt.Errorf("TagResource %q: %w", name, err)
+		}
+	}
+
+	_, err = c.logsClient.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String(name),
+		RetentionInDays: aws.Int32(int32(cfg.Logs.RetentionDays)), //nolint:gosec // validated retention period
+	})
+	if err != nil {
+		return "", fmt.Errorf("PutRetentionPolicy %q: %w", name, err)
+	}
+	return arn, nil
+}
+
+func (c *realAWSClient) deleteLogGroup(ctx context.Context, res ResourceState) error {
+	name := res.Metadata[metaLogGroupName]
+	if name == "" {
+		return fmt.Errorf("log group %q: state is missing the log group name", res.Name)
+	}
+	_, err := c.logsClient.DeleteLogGroup(ctx, &cloudwatchlogs.DeleteLogGroupInput{
+		LogGroupName: aws.String(name),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteLogGroup %q: %w", name, err)
+	}
+	return nil
+}
+
+// checkLogGroup reports a log group as unhealthy when its retention no
+// longer matches the one Apply set.
+func (c *realAWSClient) checkLogGroup(ctx context.Context, res ResourceState) (string, error) {
+	name := res.Metadata[metaLogGroupName]
+	out, err := c.logsClient.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(name),
+	})
+	if err != nil {
+		return StatusUnhealthy, fmt.Errorf("DescribeLogGroups %q: %w", name, err)
+	}
+	for _, g := range out.LogGroups {
+		if aws.ToString(g.LogGroupName) != name {
+			continue
+		}
+		if strconv.Itoa(int(aws.ToInt32(g.RetentionInDays))) != res.Metadata[metaRetentionDays] {
+			return StatusUnhealthy, nil
+		}
+		return StatusHealthy, nil
+	}
+	return StatusMissing, nil
+}
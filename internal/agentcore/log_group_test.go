@@ -0,0 +1,177 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func validConfigWithLogs(t *testing.T, extra string) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"logs":{"retention_days":30}%s}`, testBinaryPath(t), extra)
+}
+
+func TestValidateLogs(t *testing.T) {
+	for _, days := range []int{1, 30, 3653} {
+		if errs := validateLogs(&LogsConfig{RetentionDays: days}); len(errs) != 0 {
+			t.Errorf("retention_days %d: errs = %v", days, errs)
+		}
+	}
+	for _, days := range []int{0, 2, 10000} {
+		errs := validateLogs(&LogsConfig{RetentionDays: days})
+		if len(errs) != 1 || !strings.Contains(errs[0], "logs.retention_days") {
+			t.Errorf("retention_days %d: errs = %v, want one error", days, errs)
+		}
+	}
+	if errs := validateLogs(nil); errs != nil {
+		t.Errorf("nil logs: errs = %v", errs)
+	}
+}
+
+func TestRuntimeLogGroupName(t *testing.T) {
+	arn := "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/chat-abc"
+	if got, want := runtimeLogGroupName(arn, "live"), "/aws/bedrock-agentcore/runtimes/chat-abc-live"; got != want {
+		t.Errorf("runtimeLogGroupName = %q, want %q", got, want)
+	}
+	if got := runtimeLogGroupName("not-an-arn", "DEFAULT"); got != "" {
+		t.Errorf("runtimeLogGroupName(bad arn) = %q, want empty", got)
+	}
+}
+
+func TestPlan_LogGroupPerRuntime(t *testing.T) {
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     multiAgentPackJSON(),
+		DeployConfig: validConfigWithLogs(t, ""),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	runtimes, groups := 0, 0
+	for _, c := range resp.Changes {
+		switch c.Type {
+		case ResTypeAgentRuntime:
+			runtimes++
+		case ResTypeLogGroup:
+			groups++
+			if !strings.Contains(c.Detail, "30-day retention") {
+				t.Errorf("detail = %q", c.Detail)
+			}
+		}
+	}
+	if runtimes == 0 || groups != runtimes {
+		t.Errorf("log groups = %d, want one per runtime (%d)", groups, runtimes)
+	}
+}
+
+func TestApply_LogGroupState(t *testing.T) {
+	tests := []struct {
+		name      string
+		extra     string
+		wantGroup string
+	}{
+		{name: "default endpoint", wantGroup: "/aws/bedrock-agentcore/runtimes/mypack-DEFAULT"},
+		{name: "runtime endpoint", extra: `,"runtime_endpoint":"live"`,
+			wantGroup: "/aws/bedrock-agentcore/runtimes/mypack-live"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, raw, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+				PackJSON:     singleAgentPack(),
+				DeployConfig: validConfigWithLogs(t, tt.extra),
+				ArenaConfig:  validArenaConfigJSON,
+			})
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			var state AdapterState
+			if err := json.Unmarshal([]byte(raw), &state); err != nil {
+				t.Fatal(err)
+			}
+			lg, ok := findResourceOfType(&state, ResTypeLogGroup)
+			if !ok {
+				t.Fatalf("no log_group in state: %+v", state.Resources)
+			}
+			if lg.Name != "mypack" || lg.Status != ResStatusCreated ||
+				lg.Metadata[metaLogGroupName] != tt.wantGroup || lg.Metadata[metaRetentionDays] != "30" {
+				t.Errorf("log group = %+v", lg)
+			}
+			if !strings.HasSuffix(lg.ARN, ":log-group:"+tt.wantGroup) {
+				t.Errorf("ARN = %q", lg.ARN)
+			}
+		})
+	}
+}
+
+func TestApply_LogGroupFailure(t *testing.T) {
+	provider := &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			return &failingAWSClient{
+				simulatedAWSClient: *newSimulatedAWSClient(cfg.Region),
+				failOn:             map[string]bool{ResTypeLogGroup: true},
+			}, nil
+		},
+	}
+	_, raw, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfigWithLogs(t, ""),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), ResTypeLogGroup) {
+		t.Fatalf("err = %v, want log_group failure", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	if lg, ok := findResourceOfType(&state, ResTypeLogGroup); !ok || lg.Status != ResStatusFailed {
+		t.Errorf("log group = %+v, want failed", lg)
+	}
+}
+
+func TestDestroy_LogGroupRetention(t *testing.T) {
+	state := &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "rt", ARN: "arn:rt"},
+		{Type: ResTypeLogGroup, Name: "lg", ARN: "arn:lg"},
+	}}
+	tests := []struct {
+		name        string
+		logs        string
+		wantDeleted string
+	}{
+		{name: "deleted by default", wantDeleted: "rt,lg"},
+		{name: "retained", logs: `,"logs":{"retention_days":7,"retain_on_destroy":true}`, wantDeleted: "rt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &recordingDestroyer{}
+			p := &Provider{
+				destroyerFunc: func(context.Context, *Config) (resourceDestroyer, error) { return d, nil },
+			}
+			var skipped []string
+			err := p.Destroy(context.Background(), &deploy.DestroyRequest{
+				DeployConfig: strings.TrimSuffix(validDestroyConfig(), "}") + tt.logs + "}",
+				PriorState:   mustJSON(t, state),
+			}, func(e *deploy.DestroyEvent) error {
+				if e.Resource != nil && e.Resource.Status == ResStatusSkipped {
+					skipped = append(skipped, e.Resource.Name)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Destroy: %v", err)
+			}
+			if got := strings.Join(d.deleted, ","); got != tt.wantDeleted {
+				t.Errorf("deleted = %s, want %s", got, tt.wantDeleted)
+			}
+			if tt.logs != "" && (len(skipped) != 1 || skipped[0] != "lg") {
+				t.Errorf("skipped = %v, want [lg]", skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// Saved Logs Insights queries, the names of a deployment's logs_query
+// resources.
+const (
+	LogsQueryErrorsByAgent      = "errors_by_agent"
+	LogsQueryLatencyPercentiles = "latency_percentiles"
+	LogsQueryEvalScoreTrend     = "eval_score_trend"
+	LogsQueryToolFailures       = "tool_failures"
+)
+
+// logsQueryFolder is the saved-query folder every deployment's queries are
+// filed under, followed by the deployment's AWS name.
+const logsQueryFolder = "promptarena/"
+
+// logsQueryOutputPrefix starts the output holding each saved query's ID.
+const logsQueryOutputPrefix = "logs_query."
+
+// outputLogsInsightsURL is the output holding the Logs Insights console
+// URL, whose saved-query list shows the deployment's queries.
+const outputLogsInsightsURL = "logs_insights_url"
+
+// logsInsightsURLFormat is the Logs Insights console URL for a region.
+const logsInsightsURLFormat = "https://%[1]s.console.aws.amazon.com/cloudwatch/home?region=%[1]s#logsV2:logs-insights"
+
+// Logs query metadata keys stored in ResourceState.Metadata.
+const (
+	metaQueryName      = "query_name"
+	metaQueryLogGroups = "log_groups"
+)
+
+// logsQuerySpec is one saved query.
+type logsQuerySpec struct {
+	name   string
+	detail string
+	query  string
+
+	// evalResults runs the query over the online evaluation results
+	// instead of the runtime logs.
+	evalResults bool
+}
+
+// logsQuerySpecs lists the saved queries in the order Apply creates them.
+// The runtime queries read the JSON lines the runtime and PromptKit log;
+// @log names the runtime's log group, which carries its runtime ID.
+var logsQuerySpecs = []logsQuerySpec{
+	{
+		name:   LogsQueryErrorsByAgent,
+		detail: "Errors and 5xx responses by runtime",
+		query: `fields @timestamp, @log, msg, error` +
+			` | filter level = "ERROR" or status >= 500` +
+			` | stats count(*) as errors by @log` +
+			` | sort errors desc`,
+	},
+	{
+		name:   LogsQueryLatencyPercentiles,
+		detail: "Invocation latency percentiles by runtime",
+		query: `filter msg = "access" and path = "/invocations"` +
+			` | stats pct(latency_ms, 50) as p50, pct(latency_ms, 90) as p90, pct(latency_ms, 99) as p99,` +
+			` count(*) as requests by bin(5m), @log`,
+	},
+	{
+		name:        LogsQueryEvalScoreTrend,
+		detail:      "Hourly average online eval score by evaluator",
+		evalResults: true,
+		query: "fields `attributes.gen_ai.evaluation.name` as evaluator," +
+			" `attributes.gen_ai.evaluation.score.value` as score" +
+			" | filter ispresent(score)" +
+			" | stats avg(score) as avg_score, count(*) as samples by bin(1h), evaluator",
+	},
+	{
+		name:   LogsQueryToolFailures,
+		detail: "Failed tool calls by tool and runtime",
+		query: `filter ispresent(tool) and ispresent(error)` +
+			` | stats count(*) as failures by tool, @log` +
+			` | sort failures desc`,
+	},
+}
+
+// logsQueryDefinition is the saved query PutLogsQuery creates or updates.
+type logsQueryDefinition struct {
+	// Name is the query's name in CloudWatch, folders included.
+	Name          string
+	QueryString   string
+	LogGroupNames []string
+
+	// ID is the query definition to update, or "" to create one.
+	ID string
+}
+
+// savedQueriesEnabled reports whether Apply provisions the saved Logs
+// Insights queries.
+func (c *Config) savedQueriesEnabled() bool {
+	return c.Observability != nil && c.Observability.SavedQueries
+}
+
+// logsQueryName returns the CloudWatch name of the saved query name.
+func (c *Config) logsQueryName(packID, name string) string {
+	return logsQueryFolder + c.awsName(packID) + "/" + name
+}
+
+// planLogsQueries returns one logs_query change per saved query when
+// observability.saved_queries is set. The eval score trend needs an online
+// evaluation config whose results it reads.
+func planLogsQueries(pack *prompt.Pack, cfg *Config, desired []deploy.ResourceChange) []deploy.ResourceChange {
+	if !cfg.savedQueriesEnabled() {
+		return nil
+	}
+	var changes []deploy.ResourceChange
+	for _, spec := range logsQuerySpecs {
+		if spec.evalResults && !hasChangeOfType(desired, ResTypeOnlineEvalConfig) {
+			continue
+		}
+		changes = append(changes, deploy.ResourceChange{
+			Type:   ResTypeLogsQuery,
+			Name:   spec.name,
+			Action: deploy.ActionCreate,
+			Detail: fmt.Sprintf("Save Logs Insights query %s: %s",
+				cfg.logsQueryName(pack.ID, spec.name), spec.detail),
+		})
+	}
+	return changes
+}
+
+// applyLogsQueries saves the Logs Insights queries over the log groups of
+// the runtimes and online evaluation config deployed before them.
+func applyLogsQueries(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if !ac.cfg.savedQueriesEnabled() {
+		return resources, applyErr, nil
+	}
+	runtimeGroups, evalGroup := logsQuerySources(resources, ac.cfg.invokedEndpoint())
+	if len(runtimeGroups) == 0 {
+		return resources, applyErr, nil
+	}
+
+	for i, spec := range logsQuerySpecs {
+		groups := runtimeGroups
+		if spec.evalResults {
+			if evalGroup == "" {
+				continue
+			}
+			groups = []string{evalGroup}
+		}
+		pct := ac.span.at(i, len(logsQuerySpecs))
+		op := resolveOp(ResTypeLogsQuery, spec.name, true, ac.priorMap)
+		if err := ac.reporter.Progress(fmt.Sprintf("%s %s: %s", op.verb, ResTypeLogsQuery, spec.name), pct); err != nil {
+			return resources, applyErr, err
+		}
+
+		res, err := deployLogsQuery(ctx, ac, spec, groups, op)
+		if err != nil {
+			deployErr := newDeployError(op.failVerb, ResTypeLogsQuery, spec.name, err)
+			_ = ac.reporter.Error(deployErr)
+			resources = append(resources, ResourceState{
+				Type: ResTypeLogsQuery, Name: spec.name, Status: ResStatusFailed,
+			})
+			applyErr = combineErrors(applyErr, deployErr)
+			continue
+		}
+
+		if err := ac.reporter.Resource(&deploy.ResourceResult{
+			Type: ResTypeLogsQuery, Name: spec.name, Action: op.action,
+			Status: op.status, Detail: res.Metadata[metaQueryName],
+		}); err != nil {
+			return resources, applyErr, err
+		}
+		resources = append(resources, res)
+	}
+	return resources, applyErr, nil
+}
+
+// logsQuerySources returns the log groups of the deployed runtimes, when
+// invoked through endpoint, and the results log group of the deployed
+// online evaluation config, or "" when there is none.
+func logsQuerySources(resources []ResourceState, endpoint string) (runtimeGroups []string, evalGroup string) {
+	for _, r := range resources {
+		if r.ARN == "" || r.Status == ResStatusFailed {
+			continue
+		}
+		switch r.Type {
+		case ResTypeAgentRuntime:
+			if name := runtimeLogGroupName(r.ARN, endpoint); name != "" {
+				runtimeGroups = append(runtimeGroups, name)
+			}
+		case ResTypeOnlineEvalConfig:
+			if id := extractResourceID(r.ARN, "online-evaluation-config"); id != "" {
+				evalGroup = evalResultsLogGroupPrefix + id
+			}
+		}
+	}
+	return runtimeGroups, evalGroup
+}
+
+// deployLogsQuery creates one saved query, or updates the one the prior
+// state records.
+func deployLogsQuery(
+	ctx context.Context, ac *applyContext, spec logsQuerySpec, groups []string, op resourceOp,
+) (ResourceState, error) {
+	def := logsQueryDefinition{
+		Name:          ac.cfg.logsQueryName(ac.pack.ID, spec.name),
+		QueryString:   spec.query,
+		LogGroupNames: groups,
+		ID:            logsQueryID(op.priorARN),
+	}
+	arn, err := ac.client.PutLogsQuery(ctx, def, ac.cfg)
+	if err != nil {
+		return ResourceState{}, err
+	}
+	return ResourceState{
+		Type:   ResTypeLogsQuery,
+		Name:   spec.name,
+		ARN:    arn,
+		Status: op.status,
+		Metadata: map[string]string{
+			metaQueryName:      def.Name,
+			metaQueryLogGroups: strings.Join(groups, ","),
+		},
+	}, nil
+}
+
+// logsQueryOutputs returns the ID of each saved query and the Logs
+// Insights console URL, or nil when no query was saved.
+func logsQueryOutputs(resources []ResourceState, region string) map[string]string {
+	outputs := make(map[string]string)
+	for _, r := range resources {
+		if r.Type == ResTypeLogsQuery && r.ARN != "" {
+			outputs[logsQueryOutputPrefix+r.Name] = logsQueryID(r.ARN)
+		}
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+	outputs[outputLogsInsightsURL] = fmt.Sprintf(logsInsightsURLFormat, region)
+	return outputs
+}
+
+// logsQueryARN returns the ARN-style identifier state records for a saved
+// query. Query definitions have no ARN of their own.
+func logsQueryARN(region, accountID, id string) string {
+	return formatARN("logs", region, accountID, "query-definition:"+id)
+}
+
+// logsQueryID returns the query definition ID of a logsQueryARN, or "".
+func logsQueryID(arn string) string {
+	a, ok := parseARN(arn)
+	if !ok || a.Service != "logs" {
+		return ""
+	}
+	id, ok := strings.CutPrefix(a.Resource, "query-definition:")
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// PutLogsQuery saves def, updating the query definition def.ID when it
+// still exists and creating one otherwise.
+func (c *realAWSClient) PutLogsQuery(ctx context.Context, def logsQueryDefinition, cfg *Config) (string, error) {
+	input := &cloudwatchlogs.PutQueryDefinitionInput{
+		Name:          aws.String(def.Name),
+		QueryString:   aws.String(def.QueryString),
+		LogGroupNames: def.LogGroupNames,
+	}
+	if def.ID != "" {
+		input.QueryDefinitionId = aws.String(def.ID)
+	}
+	out, err := c.logsClient.PutQueryDefinition(ctx, input)
+	if def.ID != "" && isNotFound(err) {
+		input.QueryDefinitionId = nil
+		out, err = c.logsClient.PutQueryDefinition(ctx, input)
+	}
+	if err != nil {
+		return "", fmt.Errorf("PutQueryDefinition %q: %w", def.Name, err)
+	}
+	id := aws.ToString(out.QueryDefinitionId)
+	return logsQueryARN(cfg.Region, extractAccountFromARN(cfg.RuntimeRoleARN), id), nil
+}
+
+func (c *realAWSClient) deleteLogsQuery(ctx context.Context, res ResourceState) error {
+	id := logsQueryID(res.ARN)
+	if id == "" {
+		return fmt.Errorf("logs query %q: state is missing the query definition ID", res.Name)
+	}
+	_, err := c.logsClient.DeleteQueryDefinition(ctx, &cloudwatchlogs.DeleteQueryDefinitionInput{
+		QueryDefinitionId: aws.String(id),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteQueryDefinition %q: %w", res.Name, err)
+	}
+	return nil
+}
+
+// checkLogsQuery reports a saved query as missing when it was deleted, and
+// unhealthy when its log groups no longer match the ones Apply set.
+func (c *realAWSClient) checkLogsQuery(ctx context.Context, res ResourceState) (string, error) {
+	id, name := logsQueryID(res.ARN), res.Metadata[metaQueryName]
+	input := &cloudwatchlogs.DescribeQueryDefinitionsInput{QueryDefinitionNamePrefix: aws.String(name)}
+	for {
+		out, err := c.logsClient.DescribeQueryDefinitions(ctx, input)
+		if err != nil {
+			return StatusUnhealthy, fmt.Errorf("DescribeQueryDefinitions %q: %w", name, err)
+		}
+		for _, q := range out.QueryDefinitions {
+			if aws.ToString(q.QueryDefinitionId) != id {
+				continue
+			}
+			if strings.Join(q.LogGroupNames, ",") != res.Metadata[metaQueryLogGroups] {
+				return StatusUnhealthy, nil
+			}
+			return StatusHealthy, nil
+		}
+		if out.NextToken == nil {
+			return StatusMissing, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
@@ -0,0 +1,131 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func validConfigWithSavedQueries(t *testing.T) string {
+	t.Helper()
+	return strings.TrimSuffix(validConfig(t), "}") + `,"observability":{"saved_queries":true}}`
+}
+
+func TestPlan_LogsQueries(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		pack string
+		want []string
+	}{
+		{"with online evals", multiAgentPackWithEvals(), []string{
+			LogsQueryErrorsByAgent, LogsQueryLatencyPercentiles, LogsQueryEvalScoreTrend, LogsQueryToolFailures,
+		}},
+		{"without evals", singleAgentPack(), []string{
+			LogsQueryErrorsByAgent, LogsQueryLatencyPercentiles, LogsQueryToolFailures,
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+				PackJSON: tt.pack, DeployConfig: validConfigWithSavedQueries(t), ArenaConfig: validArenaConfigJSON,
+			})
+			if err != nil {
+				t.Fatalf("Plan: %v", err)
+			}
+			var got []string
+			for _, c := range resp.Changes {
+				if c.Type == ResTypeLogsQuery {
+					got = append(got, c.Name)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("planned queries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_LogsQueriesOff(t *testing.T) {
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: validConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if hasChangeOfType(resp.Changes, ResTypeLogsQuery) {
+		t.Errorf("logs_query planned without saved_queries: %+v", resp.Changes)
+	}
+}
+
+func TestApply_LogsQueries(t *testing.T) {
+	p := newSimulatedProvider()
+	req := &deploy.PlanRequest{
+		PackJSON: multiAgentPackWithEvals(), DeployConfig: validConfigWithSavedQueries(t),
+		ArenaConfig: validArenaConfigJSON,
+	}
+	_, raw, err := collectEvents(t, p, req)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := make(map[string]ResourceState)
+	for _, r := range state.Resources {
+		if r.Type == ResTypeLogsQuery {
+			queries[r.Name] = r
+		}
+	}
+	if len(queries) != len(logsQuerySpecs) {
+		t.Fatalf("saved queries = %v, want %d", queries, len(logsQuerySpecs))
+	}
+	errors := queries[LogsQueryErrorsByAgent]
+	if errors.Metadata[metaQueryName] != "promptarena/evalpack/errors_by_agent" {
+		t.Errorf("query name = %q", errors.Metadata[metaQueryName])
+	}
+	if groups := strings.Split(errors.Metadata[metaQueryLogGroups], ","); len(groups) != 2 ||
+		!strings.HasPrefix(groups[0], runtimeLogGroupPrefix) {
+		t.Errorf("errors_by_agent log groups = %v, want both runtimes'", groups)
+	}
+	trend := queries[LogsQueryEvalScoreTrend]
+	if !strings.HasPrefix(trend.Metadata[metaQueryLogGroups], evalResultsLogGroupPrefix) {
+		t.Errorf("eval_score_trend log groups = %q, want the eval results", trend.Metadata[metaQueryLogGroups])
+	}
+
+	id := logsQueryID(errors.ARN)
+	if id == "" || state.Outputs[logsQueryOutputPrefix+LogsQueryErrorsByAgent] != id {
+		t.Errorf("outputs = %v, want the errors_by_agent query ID %q", state.Outputs, id)
+	}
+	if !strings.Contains(state.Outputs[outputLogsInsightsURL], "region=us-west-2#logsV2:logs-insights") {
+		t.Errorf("logs insights URL = %q", state.Outputs[outputLogsInsightsURL])
+	}
+
+	req.PriorState = raw
+	_, again, err := collectEvents(t, p, req)
+	if err != nil {
+		t.Fatalf("second Apply: %v", err)
+	}
+	if err := json.Unmarshal([]byte(again), &state); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := findResourceOfType(&state, ResTypeLogsQuery)
+	if r.Status != ResStatusUpdated || logsQueryID(r.ARN) != id {
+		t.Errorf("second Apply query = %+v, want %s updated in place", r, id)
+	}
+}
+
+func TestLogsQueryID(t *testing.T) {
+	arn := logsQueryARN("us-west-2", "123456789012", "0b8a-42")
+	if got := logsQueryID(arn); got != "0b8a-42" {
+		t.Errorf("logsQueryID(%q) = %q", arn, got)
+	}
+	for _, arn := range []string{"", "arn:aws:logs:us-west-2:123456789012:log-group:x", "not-an-arn"} {
+		if got := logsQueryID(arn); got != "" {
+			t.Errorf("logsQueryID(%q) = %q, want empty", arn, got)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+)
+
+// JSON-RPC methods for managing the records held in a deployment's memory.
+// They extend the standard adaptersdk method set.
+const (
+	MethodMemoryList  = "memory_list"
+	MethodMemoryPurge = "memory_purge"
+)
+
+// memoryDataPageSize is the MaxResults value for memory data-plane listings.
+const memoryDataPageSize = 100
+
+// hoursPerDay converts older_than_days into a duration.
+const hoursPerDay = 24
+
+// memoryDataClient is the subset of the data-plane API used to list and
+// delete memory records.
+type memoryDataClient interface {
+	ListActors(
+		ctx context.Context,
+		input *bedrockagentcore.ListActorsInput,
+		opts ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.ListActorsOutput, error)
+
+	ListSessions(
+		ctx context.Context,
+		input *bedrockagentcore.ListSessionsInput,
+		opts ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.ListSessionsOutput, error)
+
+	ListEvents(
+		ctx context.Context,
+		input *bedrockagentcore.ListEventsInput,
+		opts ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.ListEventsOutput, error)
+
+	DeleteEvent(
+		ctx context.Context,
+		input *bedrockagentcore.DeleteEventInput,
+		opts ...func(*bedrockagentcore.Options),
+	) (*bedrockagentcore.DeleteEventOutput, error)
+}
+
+// memoryDataFactory creates a memoryDataClient for the given config.
+type memoryDataFactory func(ctx context.Context, cfg *Config) (memoryDataClient, error)
+
+// newRealMemoryDataFactory is the memoryDataFactory used by NewProvider.
+func newRealMemoryDataFactory(ctx context.Context, cfg *Config) (memoryDataClient, error) {
+	awsCfg, err := loadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := bedrockagentcore.NewFromConfig(awsCfg, func(o *bedrockagentcore.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointBedrockAgentCore)
+	})
+	return &realDataPlaneClient{client: client}, nil
+}
+
+// MemoryListRequest is the params object of a memory_list call. ActorID
+// limits the listing to one actor.
+type MemoryListRequest struct {
+	DeployConfig string `json:"deploy_config"`
+	PriorState   string `json:"prior_state"`
+	ActorID      string `json:"actor_id,omitempty"`
+}
+
+// MemorySession is one session held in memory.
+type MemorySession struct {
+	SessionID string     `json:"session_id"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// MemoryActor is one actor and its sessions.
+type MemoryActor struct {
+	ActorID  string          `json:"actor_id"`
+	Sessions []MemorySession `json:"sessions"`
+}
+
+// MemoryListResponse is the result of a memory_list call.
+type MemoryListResponse struct {
+	MemoryID string        `json:"memory_id"`
+	Actors   []MemoryActor `json:"actors"`
+}
+
+// MemoryPurgeRequest is the params object of a memory_purge call. It
+// deletes the events of the listed sessions, events older than
+// OlderThanDays, or, when both are set, the listed sessions' older events.
+// SessionIDs require ActorID; otherwise ActorID limits the purge to one
+// actor. DryRun counts matching events without deleting them.
+type MemoryPurgeRequest struct {
+	DeployConfig  string   `json:"deploy_config"`
+	PriorState    string   `json:"prior_state"`
+	ActorID       string   `json:"actor_id,omitempty"`
+	SessionIDs    []string `json:"session_ids,omitempty"`
+	OlderThanDays int      `json:"older_than_days,omitempty"`
+	DryRun        bool     `json:"dry_run,omitempty"`
+}
+
+// PurgedSession reports the events deleted from one session.
+type PurgedSession struct {
+	ActorID       string `json:"actor_id"`
+	SessionID     string `json:"session_id"`
+	DeletedEvents int    `json:"deleted_events"`
+}
+
+// MemoryPurgeResponse is the result of a memory_purge call. Only sessions
+// with matching events are listed.
+type MemoryPurgeResponse struct {
+	MemoryID      string          `json:"memory_id"`
+	DryRun        bool            `json:"dry_run,omitempty"`
+	DeletedEvents int             `json:"deleted_events"`
+	Sessions      []PurgedSession `json:"sessions"`
+}
+
+// memoryRecords lists and deletes records in one memory.
+type memoryRecords struct {
+	client   memoryDataClient
+	memoryID string
+}
+
+// MemoryList lists the actors and sessions held in a deployment's memory.
+func (p *Provider) MemoryList(ctx context.Context, req *MemoryListRequest) (*MemoryListResponse, error) {
+	m, err := p.openMemoryRecords(ctx, req.DeployConfig, req.PriorState)
+	if err != nil {
+		return nil, err
+	}
+	actorIDs, err := m.actorsFor(ctx, req.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MemoryListResponse{MemoryID: m.memoryID, Actors: []MemoryActor{}}
+	for _, actorID := range actorIDs {
+		sessions, listErr := m.sessions(ctx, actorID)
+		if listErr != nil {
+			return nil, listErr
+		}
+		resp.Actors = append(resp.Actors, MemoryActor{ActorID: actorID, Sessions: sessions})
+	}
+	return resp, nil
+}
+
+// MemoryPurge deletes session events from a deployment's memory, for
+// example to honour a data deletion request.
+func (p *Provider) MemoryPurge(ctx context.Context, req *MemoryPurgeRequest) (*MemoryPurgeResponse, error) {
+	if err := validateMemoryPurge(req); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+	m, err := p.openMemoryRecords(ctx, req.DeployConfig, req.PriorState)
+	if err != nil {
+		return nil, err
+	}
+
+	var cutoff time.Time
+	if req.OlderThanDays > 0 {
+		cutoff = time.Now().Add(-time.Duration(req.OlderThanDays) * hoursPerDay * time.Hour)
+	}
+
+	targets, err := m.purgeTargets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MemoryPurgeResponse{MemoryID: m.memoryID, DryRun: req.DryRun, Sessions: []PurgedSession{}}
+	for _, t := range targets {
+		n, purgeErr := m.purgeSession(ctx, t.ActorID, t.SessionID, cutoff, req.DryRun)
+		if purgeErr != nil {
+			return nil, purgeErr
+		}
+		if n > 0 {
+			t.DeletedEvents = n
+			resp.Sessions = append(resp.Sessions, t)
+			resp.DeletedEvents += n
+		}
+	}
+	return resp, nil
+}
+
+// validateMemoryPurge checks that a purge request selects something.
+func validateMemoryPurge(req *MemoryPurgeRequest) error {
+	switch {
+	case req.OlderThanDays < 0:
+		return fmt.Errorf("older_than_days must not be negative, got %d", req.OlderThanDays)
+	case len(req.SessionIDs) == 0 && req.OlderThanDays == 0:
+		return fmt.Errorf("memory_purge requires session_ids or older_than_days")
+	case len(req.SessionIDs) > 0 && req.ActorID == "":
+		return fmt.Errorf("session_ids require actor_id")
+	}
+	return nil
+}
+
+// openMemoryRecords resolves the deployment's memory from prior state and
+// creates a data-plane client for it.
+func (p *Provider) openMemoryRecords(
+	ctx context.Context, deployConfig, priorState string,
+) (*memoryRecords, error) {
+	state, err := parseAdapterState(priorState)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse prior state: %w", err)
+	}
+	res, ok := findResourceOfType(state, ResTypeMemory)
+	if !ok {
+		return nil, fmt.Errorf("agentcore: deployment has no %s resource", ResTypeMemory)
+	}
+	memoryID := extractResourceID(res.ARN, "memory")
+	if memoryID == "" {
+		memoryID = res.Name
+	}
+
+	cfg, err := p.loadConfig(deployConfig, state.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
+	}
+	client, err := p.memoryDataFunc(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to create memory data client: %w", err)
+	}
+	return &memoryRecords{client: client, memoryID: memoryID}, nil
+}
+
+// actorsFor returns actorID alone when set, otherwise every actor.
+func (m *memoryRecords) actorsFor(ctx context.Context, actorID string) ([]string, error) {
+	if actorID != "" {
+		return []string{actorID}, nil
+	}
+	return m.actors(ctx)
+}
+
+// purgeTargets expands a purge request into the sessions to scan.
+func (m *memoryRecords) purgeTargets(ctx context.Context, req *MemoryPurgeRequest) ([]PurgedSession, error) {
+	var targets []PurgedSession
+	if len(req.SessionIDs) > 0 {
+		for _, id := range req.SessionIDs {
+			targets = append(targets, PurgedSession{ActorID: req.ActorID, SessionID: id})
+		}
+		return targets, nil
+	}
+
+	actorIDs, err := m.actorsFor(ctx, req.ActorID)
+	if err != nil {
+		return nil, err
+	}
+	for _, actorID := range actorIDs {
+		sessions, listErr := m.sessions(ctx, actorID)
+		if listErr != nil {
+			return nil, listErr
+		}
+		for _, s := range sessions {
+			targets = append(targets, PurgedSession{ActorID: actorID, SessionID: s.SessionID})
+		}
+	}
+	return targets, nil
+}
+
+// actors lists every actor in the memory.
+func (m *memoryRecords) actors(ctx context.Context) ([]string, error) {
+	var ids []string
+	var nextToken *string
+	for {
+		out, err := m.client.ListActors(ctx, &bedrockagentcore.ListActorsInput{
+			MemoryId:   aws.String(m.memoryID),
+			MaxResults: aws.Int32(memoryDataPageSize),
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListActors on memory %q: %w", m.memoryID, err)
+		}
+		for _, a := range out.ActorSummaries {
+			ids = append(ids, aws.ToString(a.ActorId))
+		}
+		if out.NextToken == nil {
+			return ids, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// sessions lists every session of an actor.
+func (m *memoryRecords) sessions(ctx context.Context, actorID string) ([]MemorySession, error) {
+	sessions := []MemorySession{}
+	var nextToken *string
+	for {
+		out, err := m.client.ListSessions(ctx, &bedrockagentcore.ListSessionsInput{
+			MemoryId:   aws.String(m.memoryID),
+			ActorId:    aws.String(actorID),
+			MaxResults: aws.Int32(memoryDataPageSize),
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListSessions for actor %q: %w", actorID, err)
+		}
+		for _, s := range out.SessionSummaries {
+			sessions = append(sessions, MemorySession{SessionID: aws.ToString(s.SessionId), CreatedAt: s.CreatedAt})
+		}
+		if out.NextToken == nil {
+			return sessions, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// purgeSession deletes a session's events, or only those before cutoff when
+// cutoff is set, and returns how many matched. Dry runs only count.
+func (m *memoryRecords) purgeSession(
+	ctx context.Context, actorID, sessionID string, cutoff time.Time, dryRun bool,
+) (int, error) {
+	eventIDs, err := m.eventsBefore(ctx, actorID, sessionID, cutoff)
+	if err != nil || dryRun {
+		return len(eventIDs), err
+	}
+	for i, id := range eventIDs {
+		_, delErr := m.client.DeleteEvent(ctx, &bedrockagentcore.DeleteEventInput{
+			MemoryId:  aws.String(m.memoryID),
+			ActorId:   aws.String(actorID),
+			SessionId: aws.String(sessionID),
+			EventId:   aws.String(id),
+		})
+		if delErr != nil && !isNotFound(delErr) {
+			return i, fmt.Errorf("DeleteEvent %q in session %q: %w", id, sessionID, delErr)
+		}
+	}
+	return len(eventIDs), nil
+}
+
+// eventsBefore lists the IDs of a session's events, limited to events
+// before cutoff when cutoff is set.
+func (m *memoryRecords) eventsBefore(
+	ctx context.Context, actorID, sessionID string, cutoff time.Time,
+) ([]string, error) {
+	var ids []string
+	var nextToken *string
+	for {
+		out, err := m.client.ListEvents(ctx, &bedrockagentcore.ListEventsInput{
+			MemoryId:        aws.String(m.memoryID),
+			ActorId:         aws.String(actorID),
+			SessionId:       aws.String(sessionID),
+			IncludePayloads: aws.Bool(false),
+			MaxResults:      aws.Int32(memoryDataPageSize),
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListEvents in session %q: %w", sessionID, err)
+		}
+		for _, e := range out.Events {
+			if cutoff.IsZero() || (e.EventTimestamp != nil && e.EventTimestamp.Before(cutoff)) {
+				ids = append(ids, aws.ToString(e.EventId))
+			}
+		}
+		if out.NextToken == nil {
+			return ids, nil
+		}
+		nextToken = out.NextToken
+	}
+}
@@ -0,0 +1,331 @@
+package agentcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+// fakeMemoryData holds events keyed by actor, then session. Listings return
+// one item per page to exercise pagination.
+type fakeMemoryData struct {
+	memoryID string
+	events   map[string]map[string][]types.Event
+	deleted  []string
+	failList bool
+}
+
+func (f *fakeMemoryData) checkMemory(id *string) error {
+	if aws.ToString(id) != f.memoryID {
+		return fmt.Errorf("unknown memory %q", aws.ToString(id))
+	}
+	return nil
+}
+
+// fakePage returns the item at the offset encoded in token and the next token.
+func fakePage(token *string, n int) (int, *string) {
+	i := 0
+	if token != nil {
+		_, _ = fmt.Sscanf(*token, "%d", &i)
+	}
+	if i+1 >= n {
+		return i, nil
+	}
+	return i, aws.String(fmt.Sprint(i + 1))
+}
+
+func (f *fakeMemoryData) ListActors(
+	_ context.Context, in *bedrockagentcore.ListActorsInput, _ ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.ListActorsOutput, error) {
+	if err := f.checkMemory(in.MemoryId); err != nil {
+		return nil, err
+	}
+	if f.failList {
+		return nil, fmt.Errorf("throttled")
+	}
+	actors := sortedKeys(f.events)
+	out := &bedrockagentcore.ListActorsOutput{}
+	if len(actors) == 0 {
+		return out, nil
+	}
+	i, next := fakePage(in.NextToken, len(actors))
+	out.ActorSummaries = []types.ActorSummary{{ActorId: aws.String(actors[i])}}
+	out.NextToken = next
+	return out, nil
+}
+
+func (f *fakeMemoryData) ListSessions(
+	_ context.Context, in *bedrockagentcore.ListSessionsInput, _ ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.ListSessionsOutput, error) {
+	if err := f.checkMemory(in.MemoryId); err != nil {
+		return nil, err
+	}
+	sessions := sortedKeys(f.events[aws.ToString(in.ActorId)])
+	out := &bedrockagentcore.ListSessionsOutput{}
+	if len(sessions) == 0 {
+		return out, nil
+	}
+	i, next := fakePage(in.NextToken, len(sessions))
+	out.SessionSummaries = []types.SessionSummary{{
+		ActorId:   in.ActorId,
+		SessionId: aws.String(sessions[i]),
+		CreatedAt: aws.Time(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}}
+	out.NextToken = next
+	return out, nil
+}
+
+func (f *fakeMemoryData) ListEvents(
+	_ context.Context, in *bedrockagentcore.ListEventsInput, _ ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.ListEventsOutput, error) {
+	if err := f.checkMemory(in.MemoryId); err != nil {
+		return nil, err
+	}
+	if aws.ToBool(in.IncludePayloads) {
+		return nil, fmt.Errorf("payloads should not be requested")
+	}
+	events := f.events[aws.ToString(in.ActorId)][aws.ToString(in.SessionId)]
+	out := &bedrockagentcore.ListEventsOutput{}
+	if len(events) == 0 {
+		return out, nil
+	}
+	i, next := fakePage(in.NextToken, len(events))
+	out.Events = []types.Event{events[i]}
+	out.NextToken = next
+	return out, nil
+}
+
+func (f *fakeMemoryData) DeleteEvent(
+	_ context.Context, in *bedrockagentcore.DeleteEventInput, _ ...func(*bedrockagentcore.Options),
+) (*bedrockagentcore.DeleteEventOutput, error) {
+	if err := f.checkMemory(in.MemoryId); err != nil {
+		return nil, err
+	}
+	f.deleted = append(f.deleted, aws.ToString(in.EventId))
+	return &bedrockagentcore.DeleteEventOutput{EventId: in.EventId}, nil
+}
+
+func memoryEvent(id string, age time.Duration) types.Event {
+	return types.Event{EventId: aws.String(id), EventTimestamp: aws.Time(time.Now().Add(-age))}
+}
+
+func newFakeMemoryData() *fakeMemoryData {
+	const day = 24 * time.Hour
+	return &fakeMemoryData{
+		memoryID: "mem-abc123",
+		events: map[string]map[string][]types.Event{
+			"alice": {
+				"s1": {memoryEvent("a1-old", 40*day), memoryEvent("a1-new", time.Hour)},
+				"s2": {memoryEvent("a2-new", time.Hour)},
+			},
+			"bob": {
+				"s3": {memoryEvent("b3-old", 31*day), memoryEvent("b3-older", 90*day)},
+			},
+		},
+	}
+}
+
+func memoryDataProvider(f *fakeMemoryData) *Provider {
+	return &Provider{memoryDataFunc: func(_ context.Context, _ *Config) (memoryDataClient, error) {
+		return f, nil
+	}}
+}
+
+func memoryDataState(t *testing.T) string {
+	t.Helper()
+	return mustJSON(t, &AdapterState{Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "chat"},
+		{Type: ResTypeMemory, Name: "mypack_memory",
+			ARN: "arn:aws:bedrock-agentcore:us-west-2:123456789012:memory/mem-abc123"},
+	}})
+}
+
+func TestMemoryList(t *testing.T) {
+	p := memoryDataProvider(newFakeMemoryData())
+	resp, err := p.MemoryList(context.Background(), &MemoryListRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   memoryDataState(t),
+	})
+	if err != nil {
+		t.Fatalf("MemoryList: %v", err)
+	}
+	if resp.MemoryID != "mem-abc123" {
+		t.Errorf("memory id = %q", resp.MemoryID)
+	}
+	if len(resp.Actors) != 2 || resp.Actors[0].ActorID != "alice" || len(resp.Actors[0].Sessions) != 2 {
+		t.Fatalf("actors = %+v", resp.Actors)
+	}
+	if resp.Actors[1].Sessions[0].SessionID != "s3" || resp.Actors[1].Sessions[0].CreatedAt == nil {
+		t.Errorf("bob sessions = %+v", resp.Actors[1].Sessions)
+	}
+
+	one, err := p.MemoryList(context.Background(), &MemoryListRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   memoryDataState(t),
+		ActorID:      "bob",
+	})
+	if err != nil {
+		t.Fatalf("MemoryList actor: %v", err)
+	}
+	if len(one.Actors) != 1 || one.Actors[0].ActorID != "bob" {
+		t.Errorf("filtered actors = %+v", one.Actors)
+	}
+}
+
+func TestMemoryPurge(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         MemoryPurgeRequest
+		wantDeleted []string
+		wantCount   int
+	}{
+		{
+			name:        "sessions of one actor",
+			req:         MemoryPurgeRequest{ActorID: "alice", SessionIDs: []string{"s1", "s2"}},
+			wantDeleted: []string{"a1-old", "a1-new", "a2-new"},
+			wantCount:   3,
+		},
+		{
+			name:        "older than across actors",
+			req:         MemoryPurgeRequest{OlderThanDays: 30},
+			wantDeleted: []string{"a1-old", "b3-old", "b3-older"},
+			wantCount:   3,
+		},
+		{
+			name:        "older than for one actor",
+			req:         MemoryPurgeRequest{ActorID: "bob", OlderThanDays: 60},
+			wantDeleted: []string{"b3-older"},
+			wantCount:   1,
+		},
+		{
+			name:      "dry run deletes nothing",
+			req:       MemoryPurgeRequest{OlderThanDays: 30, DryRun: true},
+			wantCount: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeMemoryData()
+			tt.req.DeployConfig = validDestroyConfig()
+			tt.req.PriorState = memoryDataState(t)
+			resp, err := memoryDataProvider(f).MemoryPurge(context.Background(), &tt.req)
+			if err != nil {
+				t.Fatalf("MemoryPurge: %v", err)
+			}
+			if resp.DeletedEvents != tt.wantCount || resp.DryRun != tt.req.DryRun {
+				t.Errorf("deleted_events = %d dry_run = %v, want %d", resp.DeletedEvents, resp.DryRun, tt.wantCount)
+			}
+			if strings.Join(f.deleted, ",") != strings.Join(tt.wantDeleted, ",") {
+				t.Errorf("deleted = %v, want %v", f.deleted, tt.wantDeleted)
+			}
+			sum := 0
+			for _, s := range resp.Sessions {
+				sum += s.DeletedEvents
+			}
+			if sum != resp.DeletedEvents {
+				t.Errorf("session totals %d != deleted_events %d", sum, resp.DeletedEvents)
+			}
+		})
+	}
+}
+
+func TestMemoryPurge_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     MemoryPurgeRequest
+		state   string
+		wantErr string
+	}{
+		{name: "nothing selected", req: MemoryPurgeRequest{ActorID: "alice"}, wantErr: "requires session_ids"},
+		{name: "negative days", req: MemoryPurgeRequest{OlderThanDays: -1}, wantErr: "must not be negative"},
+		{name: "sessions without actor", req: MemoryPurgeRequest{SessionIDs: []string{"s1"}}, wantErr: "require actor_id"},
+		{
+			name:    "no memory resource",
+			req:     MemoryPurgeRequest{OlderThanDays: 1},
+			state:   `{"resources":[{"type":"agent_runtime","name":"chat"}]}`,
+			wantErr: ResTypeMemory,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.DeployConfig = validDestroyConfig()
+			tt.req.PriorState = tt.state
+			if tt.state == "" {
+				tt.req.PriorState = memoryDataState(t)
+			}
+			_, err := memoryDataProvider(newFakeMemoryData()).MemoryPurge(context.Background(), &tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryList_ClientErrors(t *testing.T) {
+	f := newFakeMemoryData()
+	f.failList = true
+	_, err := memoryDataProvider(f).MemoryList(context.Background(), &MemoryListRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   memoryDataState(t),
+	})
+	if err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Errorf("err = %v", err)
+	}
+
+	p := &Provider{memoryDataFunc: func(_ context.Context, _ *Config) (memoryDataClient, error) {
+		return nil, fmt.Errorf("no credentials")
+	}}
+	_, err = p.MemoryList(context.Background(), &MemoryListRequest{
+		DeployConfig: validDestroyConfig(),
+		PriorState:   memoryDataState(t),
+	})
+	if err == nil || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestServeIO_MemoryPurge(t *testing.T) {
+	f := newFakeMemoryData()
+	params := map[string]any{
+		"deploy_config": validDestroyConfig(),
+		"prior_state":   memoryDataState(t),
+		"actor_id":      "alice",
+		"session_ids":   []string{"s2"},
+	}
+	var out bytes.Buffer
+	input := jsonRPCRequest(MethodMemoryPurge, 11, params) +
+		jsonRPCRequest(MethodMemoryPurge, 12, map[string]any{"deploy_config": validDestroyConfig()})
+	if err := ServeIO(memoryDataProvider(f), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ServeIO error: %v", err)
+	}
+
+	dec := json.NewDecoder(&out)
+	var ok, bad jsonRPCResponse
+	if err := dec.Decode(&ok); err != nil {
+		t.Fatalf("decode first response: %v", err)
+	}
+	if err := dec.Decode(&bad); err != nil {
+		t.Fatalf("decode second response: %v", err)
+	}
+	if ok.Error != nil {
+		t.Fatalf("unexpected error: %s", ok.Error.Message)
+	}
+	var purge MemoryPurgeResponse
+	if err := json.Unmarshal(ok.Result, &purge); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if purge.DeletedEvents != 1 || len(f.deleted) != 1 || f.deleted[0] != "a2-new" {
+		t.Errorf("purge = %+v, deleted = %v", purge, f.deleted)
+	}
+	if bad.Error == nil || !strings.Contains(bad.Error.Message, "session_ids") {
+		t.Errorf("expected validation error, got %+v", bad)
+	}
+}
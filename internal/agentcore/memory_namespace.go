@@ -0,0 +1,128 @@
+package agentcore
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// EnvMemoryNamespace is the memory namespace a runtime reads and writes its
+// conversation events under.
+const EnvMemoryNamespace = "PROMPTPACK_MEMORY_NAMESPACE"
+
+// Memory sharing modes accepted by memory_namespaces.sharing.
+const (
+	MemorySharingShared   = "shared"
+	MemorySharingIsolated = "isolated"
+)
+
+// maxMemoryNamespaceLen caps the length of a memory namespace.
+const maxMemoryNamespaceLen = 128
+
+// memoryNamespaceRE matches memory namespaces: slash-separated segments of
+// letters, digits, hyphens, and underscores. A namespace is a memory actor
+// ID, so it must start with a letter or digit.
+var memoryNamespaceRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*(/[a-zA-Z0-9_-]+)*$`)
+
+// reservedMemoryActors are the actors the runtime keeps its own events
+// under, which a namespace must not reuse.
+var reservedMemoryActors = []string{ToolAuditActorID, SessionMetaActorID}
+
+// MemoryNamespacesConfig sets the memory namespace each runtime reads and
+// writes its conversation events under. Memory strategies extract
+// long-term memories per namespace, so members with different namespaces
+// do not see each other's memories.
+type MemoryNamespacesConfig struct {
+	// Sharing is "shared" (default), where members use Namespace, or
+	// "isolated", where each member uses one named after it.
+	Sharing string `json:"sharing,omitempty"`
+	// Namespace is the namespace shared members use. Empty keeps the
+	// runtime's default namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Agents sets the namespace of individual members, overriding the
+	// sharing mode.
+	Agents map[string]string `json:"agents,omitempty"`
+}
+
+// validateMemoryNamespaces checks the sharing mode and the syntax of every
+// namespace. Namespaces partition memory, so they require memory_store.
+func validateMemoryNamespaces(c *MemoryNamespacesConfig, hasMemory bool) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	if !hasMemory {
+		errs = append(errs, "memory_namespaces requires memory_store")
+	}
+	switch c.Sharing {
+	case "", MemorySharingShared, MemorySharingIsolated:
+	default:
+		errs = append(errs, fmt.Sprintf("memory_namespaces.sharing %q must be %q or %q",
+			c.Sharing, MemorySharingShared, MemorySharingIsolated))
+	}
+	if c.Namespace != "" {
+		errs = append(errs, validateMemoryNamespace("memory_namespaces.namespace", c.Namespace)...)
+	}
+	for _, agent := range sortedKeys(c.Agents) {
+		errs = append(errs, validateMemoryNamespace(
+			fmt.Sprintf("memory_namespaces.agents[%q]", agent), c.Agents[agent])...)
+	}
+	return errs
+}
+
+// validateMemoryNamespace checks the syntax of one namespace.
+func validateMemoryNamespace(field, ns string) []string {
+	switch {
+	case len(ns) > maxMemoryNamespaceLen:
+		return []string{fmt.Sprintf("%s is longer than %d characters", field, maxMemoryNamespaceLen)}
+	case !memoryNamespaceRE.MatchString(ns):
+		return []string{fmt.Sprintf("%s %q must be slash-separated segments of letters, digits, "+
+			"hyphens, and underscores, starting with a letter or digit", field, ns)}
+	case slices.Contains(reservedMemoryActors, ns):
+		return []string{fmt.Sprintf("%s %q is reserved by the runtime", field, ns)}
+	}
+	return nil
+}
+
+// validateMemoryNamespaceAgents checks the memory namespaces against the
+// pack: every agents key must be one of its runtimes, and with isolated
+// sharing every runtime name must itself be a valid namespace.
+func validateMemoryNamespaceAgents(pack *prompt.Pack, cfg *Config) []string {
+	c := cfg.MemoryNamespaces
+	if c == nil {
+		return nil
+	}
+	runtimes := agentRuntimeNames(pack)
+	var errs []string
+	for _, agent := range sortedKeys(c.Agents) {
+		if !slices.Contains(runtimes, agent) {
+			errs = append(errs, fmt.Sprintf("memory_namespaces.agents[%q] is not an agent of the pack", agent))
+		}
+	}
+	if c.Sharing != MemorySharingIsolated {
+		return errs
+	}
+	for _, name := range runtimes {
+		if _, ok := c.Agents[name]; !ok {
+			errs = append(errs, validateMemoryNamespace(fmt.Sprintf("isolated namespace of agent %q", name), name)...)
+		}
+	}
+	return errs
+}
+
+// memoryNamespace returns the namespace of agent's runtime, or "" for the
+// runtime's default.
+func (c *Config) memoryNamespace(agent string) string {
+	ns := c.MemoryNamespaces
+	switch {
+	case ns == nil:
+		return ""
+	case ns.Agents[agent] != "":
+		return ns.Agents[agent]
+	case ns.Sharing == MemorySharingIsolated:
+		return agent
+	}
+	return ns.Namespace
+}
@@ -0,0 +1,145 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+func TestValidateMemoryNamespaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *MemoryNamespacesConfig
+		hasMemory bool
+		want      string
+	}{
+		{"unset", nil, false, ""},
+		{"valid", &MemoryNamespacesConfig{
+			Sharing: MemorySharingShared, Namespace: "support",
+			Agents: map[string]string{"worker": "support/worker_1"},
+		}, true, ""},
+		{"isolated", &MemoryNamespacesConfig{Sharing: MemorySharingIsolated}, true, ""},
+		{"no memory", &MemoryNamespacesConfig{}, false, "requires memory_store"},
+		{"bad sharing", &MemoryNamespacesConfig{Sharing: "partial"}, true, `sharing "partial"`},
+		{"bad namespace", &MemoryNamespacesConfig{Namespace: "/support"}, true, "memory_namespaces.namespace"},
+		{"bad agent namespace", &MemoryNamespacesConfig{Agents: map[string]string{"worker": "a b"}}, true,
+			`memory_namespaces.agents["worker"]`},
+		{"empty agent namespace", &MemoryNamespacesConfig{Agents: map[string]string{"worker": ""}}, true,
+			`memory_namespaces.agents["worker"]`},
+		{"too long", &MemoryNamespacesConfig{Namespace: strings.Repeat("a", maxMemoryNamespaceLen+1)}, true,
+			"longer than"},
+		{"reserved", &MemoryNamespacesConfig{Namespace: ToolAuditActorID}, true, "reserved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateMemoryNamespaces(tt.cfg, tt.hasMemory)
+			if tt.want == "" {
+				if len(errs) > 0 {
+					t.Errorf("errors = %v, want none", errs)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(errs, "; "), tt.want) {
+				t.Errorf("errors = %v, want one containing %q", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMemoryNamespaceAgents(t *testing.T) {
+	pack := partitionedPack()
+	tests := []struct {
+		name string
+		cfg  *MemoryNamespacesConfig
+		want string
+	}{
+		{"unset", nil, ""},
+		{"known agent", &MemoryNamespacesConfig{Agents: map[string]string{"worker": "team"}}, ""},
+		{"unknown agent", &MemoryNamespacesConfig{Agents: map[string]string{"editor": "team"}},
+			`agents["editor"] is not an agent`},
+		{"isolated", &MemoryNamespacesConfig{Sharing: MemorySharingIsolated}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateMemoryNamespaceAgents(pack, &Config{MemoryNamespaces: tt.cfg})
+			if tt.want == "" {
+				if len(errs) > 0 {
+					t.Errorf("errors = %v, want none", errs)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(errs, "; "), tt.want) {
+				t.Errorf("errors = %v, want one containing %q", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMemoryNamespaceAgents_IsolatedInvalidName(t *testing.T) {
+	pack := &prompt.Pack{ID: "-pack"}
+	cfg := &Config{MemoryNamespaces: &MemoryNamespacesConfig{Sharing: MemorySharingIsolated}}
+	errs := validateMemoryNamespaceAgents(pack, cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0], `isolated namespace of agent "-pack"`) {
+		t.Errorf("errors = %v, want the runtime name rejected", errs)
+	}
+
+	cfg.MemoryNamespaces.Agents = map[string]string{"-pack": "pack"}
+	if errs := validateMemoryNamespaceAgents(pack, cfg); len(errs) > 0 {
+		t.Errorf("errors = %v, want the override to replace the runtime name", errs)
+	}
+}
+
+func TestConfigMemoryNamespace(t *testing.T) {
+	agents := map[string]string{"writer": "drafts"}
+	tests := []struct {
+		name  string
+		cfg   *MemoryNamespacesConfig
+		agent string
+		want  string
+	}{
+		{"unset", nil, "worker", ""},
+		{"shared default", &MemoryNamespacesConfig{}, "worker", ""},
+		{"shared", &MemoryNamespacesConfig{Namespace: "team"}, "worker", "team"},
+		{"isolated", &MemoryNamespacesConfig{Sharing: MemorySharingIsolated, Namespace: "team"}, "worker", "worker"},
+		{"override shared", &MemoryNamespacesConfig{Namespace: "team", Agents: agents}, "writer", "drafts"},
+		{"override isolated", &MemoryNamespacesConfig{Sharing: MemorySharingIsolated, Agents: agents}, "writer", "drafts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{MemoryNamespaces: tt.cfg}
+			if got := cfg.memoryNamespace(tt.agent); got != tt.want {
+				t.Errorf("memoryNamespace(%q) = %q, want %q", tt.agent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuntimeEnvVarsForAgent_MemoryNamespace(t *testing.T) {
+	cfg := &Config{MemoryNamespaces: &MemoryNamespacesConfig{
+		Sharing: MemorySharingIsolated, Agents: map[string]string{"writer": "drafts"},
+	}}
+	for agent, want := range map[string]string{"worker": "worker", "writer": "drafts"} {
+		if got := runtimeEnvVarsForAgent(cfg, agent)[EnvMemoryNamespace]; got != want {
+			t.Errorf("%s: %s = %q, want %q", agent, EnvMemoryNamespace, got, want)
+		}
+	}
+	if _, ok := runtimeEnvVarsForAgent(&Config{}, "worker")[EnvMemoryNamespace]; ok {
+		t.Errorf("%s set without memory_namespaces", EnvMemoryNamespace)
+	}
+}
+
+func TestPlan_MemoryNamespaceUnknownAgent(t *testing.T) {
+	deployConfig := fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"memory_store":"session",`+
+		`"memory_namespaces":{"agents":{"editor":"drafts"}}}`, testBinaryPath(t))
+	_, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: multiAgentPack(), DeployConfig: deployConfig, ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), `memory_namespaces.agents["editor"] is not an agent`) {
+		t.Errorf("Plan error = %v, want the unknown agent rejected", err)
+	}
+}
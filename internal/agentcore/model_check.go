@@ -0,0 +1,206 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/credentials"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrockTypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+// maxModelSuggestions caps the alternatives listed for an unavailable model.
+const maxModelSuggestions = 3
+
+// inferenceProfileGeos are the geography prefixes of cross-region inference
+// profile IDs, e.g. "us.anthropic.claude-sonnet-4-20250514-v1:0".
+var inferenceProfileGeos = map[string]bool{
+	"us": true, "eu": true, "apac": true, "us-gov": true, "jp": true, "au": true, "ca": true, "global": true,
+}
+
+// modelCatalog lists the Bedrock model IDs that can be invoked in a region:
+// foundation models plus active inference profiles.
+type modelCatalog interface {
+	AvailableModelIDs(ctx context.Context) ([]string, error)
+}
+
+// modelCatalogFactory creates a modelCatalog for the given config.
+type modelCatalogFactory func(ctx context.Context, cfg *Config) (modelCatalog, error)
+
+// newRealModelCatalogFactory is the modelCatalogFactory used by NewProvider.
+func newRealModelCatalogFactory(ctx context.Context, cfg *Config) (modelCatalog, error) {
+	awsCfg, err := loadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := bedrock.NewFromConfig(awsCfg, func(o *bedrock.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointBedrock)
+	})
+	return &bedrockModelCatalog{client: client}, nil
+}
+
+// bedrockModelCatalog implements modelCatalog with the Bedrock control plane.
+type bedrockModelCatalog struct {
+	client *bedrock.Client
+}
+
+// AvailableModelIDs implements modelCatalog.
+func (c *bedrockModelCatalog) AvailableModelIDs(ctx context.Context) ([]string, error) {
+	models, err := c.client.ListFoundationModels(ctx, &bedrock.ListFoundationModelsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("ListFoundationModels: %w", err)
+	}
+	ids := make([]string, 0, len(models.ModelSummaries))
+	for _, m := range models.ModelSummaries {
+		ids = append(ids, aws.ToString(m.ModelId))
+	}
+
+	var nextToken *string
+	for {
+		out, err := c.client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListInferenceProfiles: %w", err)
+		}
+		for _, p := range out.InferenceProfileSummaries {
+			if p.Status == bedrockTypes.InferenceProfileStatusActive {
+				ids = append(ids, aws.ToString(p.InferenceProfileId))
+			}
+		}
+		if out.NextToken == nil {
+			return ids, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// modelUse is one place the deployment invokes a Bedrock model.
+type modelUse struct {
+	// Owner describes the user of the model, e.g. `evaluator "tone"`.
+	Owner   string
+	ModelID string
+}
+
+// modelUses lists the Bedrock models the deployment relies on: the model
+// of each LLM-as-a-Judge evaluator and the runtime's provider model. A
+// user routed through an inference profile relies on the profile, or on
+// the model an application profile would copy.
+func modelUses(pack *prompt.Pack, cfg *Config) []modelUse {
+	packName := runtimeProfilePackName(pack)
+	var uses []modelUse
+	for i := range pack.Evals {
+		if pack.Evals[i].Type != evalTypeLLMAsJudge {
+			continue
+		}
+		modelID := evalParamString(pack.Evals[i].Params, "model", defaultEvalModel)
+		if p, _ := cfg.InferenceProfiles.forEval(packName, pack.Evals[i].ID); p != nil {
+			modelID = p.model()
+		}
+		uses = append(uses, modelUse{
+			Owner:   fmt.Sprintf("evaluator %q", pack.Evals[i].ID),
+			ModelID: modelID,
+		})
+	}
+	if p, _ := cfg.InferenceProfiles.forRuntime(packName); p != nil {
+		uses = append(uses, modelUse{Owner: "runtime", ModelID: p.model()})
+	} else if p := cfg.ArenaConfig.firstProvider(); p != nil && p.Model != "" {
+		uses = append(uses, modelUse{Owner: "runtime", ModelID: p.Model})
+	}
+	return uses
+}
+
+// checkModelAvailability returns a warning for every model the deployment
+// uses that Bedrock does not offer in cfg.Region, with suggested
+// alternatives. A failed lookup yields a single warning rather than an
+// error, since the check is advisory and needs extra IAM permissions.
+func checkModelAvailability(
+	ctx context.Context, newCatalog modelCatalogFactory, pack *prompt.Pack, cfg *Config,
+) []string {
+	uses := modelUses(pack, cfg)
+	if newCatalog == nil || len(uses) == 0 {
+		return nil
+	}
+	catalog, err := newCatalog(ctx, cfg)
+	if err != nil {
+		return []string{fmt.Sprintf("could not check model availability in %s: %v", cfg.Region, err)}
+	}
+	ids, err := catalog.AvailableModelIDs(ctx)
+	if err != nil {
+		return []string{fmt.Sprintf("could not check model availability in %s: %v", cfg.Region, err)}
+	}
+	return unavailableModels(uses, ids, cfg.Region)
+}
+
+// unavailableModels returns a warning for each use whose model is not in
+// available. ARNs are skipped because they name account-specific resources
+// such as application inference profiles.
+func unavailableModels(uses []modelUse, available []string, region string) []string {
+	offered := make(map[string]bool, len(available))
+	for _, id := range available {
+		offered[id] = true
+	}
+	sorted := append([]string(nil), available...)
+	sort.Strings(sorted)
+
+	var warnings []string
+	for _, u := range uses {
+		if offered[u.ModelID] || strings.HasPrefix(u.ModelID, "arn:") {
+			continue
+		}
+		msg := fmt.Sprintf("%s model %q is not available in %s", u.Owner, u.ModelID, region)
+		if alts := suggestModels(u.ModelID, sorted, offered); len(alts) > 0 {
+			msg += "; try " + strings.Join(alts, ", ")
+		}
+		warnings = append(warnings, msg)
+	}
+	return warnings
+}
+
+// suggestModels proposes available IDs for an unavailable model: its
+// Bedrock ID when id is a PromptKit Claude model name, then the same model
+// under another inference profile or as a plain foundation model, and
+// failing that other models from the same provider.
+func suggestModels(id string, sorted []string, offered map[string]bool) []string {
+	if mapped, ok := credentials.BedrockModelMapping[id]; ok {
+		id = mapped
+		if offered[mapped] {
+			return []string{mapped}
+		}
+	}
+	base := baseModelID(id)
+	var same, sameProvider []string
+	for _, cand := range sorted {
+		candBase := baseModelID(cand)
+		switch {
+		case candBase == base:
+			same = append(same, cand)
+		case modelProvider(candBase) == modelProvider(base):
+			sameProvider = append(sameProvider, cand)
+		}
+	}
+	if len(same) > 0 {
+		return same[:min(len(same), maxModelSuggestions)]
+	}
+	return sameProvider[:min(len(sameProvider), maxModelSuggestions)]
+}
+
+// baseModelID strips an inference profile geography prefix from id.
+func baseModelID(id string) string {
+	if geo, rest, ok := strings.Cut(id, "."); ok && inferenceProfileGeos[geo] {
+		return rest
+	}
+	return id
+}
+
+// modelProvider returns the provider part of a base model ID, e.g.
+// "anthropic" for "anthropic.claude-3-haiku-20240307-v1:0".
+func modelProvider(base string) string {
+	provider, _, _ := strings.Cut(base, ".")
+	return provider
+}
@@ -0,0 +1,142 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/evals"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// fakeModelCatalog serves a fixed list of model IDs.
+type fakeModelCatalog struct {
+	ids []string
+	err error
+}
+
+func (c *fakeModelCatalog) AvailableModelIDs(context.Context) ([]string, error) {
+	return c.ids, c.err
+}
+
+func catalogFactory(c *fakeModelCatalog) modelCatalogFactory {
+	return func(context.Context, *Config) (modelCatalog, error) { return c, nil }
+}
+
+var usWest2Models = []string{
+	"anthropic.claude-3-haiku-20240307-v1:0",
+	"anthropic.claude-3-5-haiku-20241022-v1:0",
+	"us.anthropic.claude-sonnet-4-20250514-v1:0",
+	"amazon.nova-pro-v1:0",
+}
+
+func TestUnavailableModels(t *testing.T) {
+	tests := []struct {
+		name    string
+		modelID string
+		want    string
+	}{
+		{name: "available", modelID: "anthropic.claude-3-haiku-20240307-v1:0"},
+		{name: "inference profile available", modelID: "us.anthropic.claude-sonnet-4-20250514-v1:0"},
+		{name: "arn skipped", modelID: "arn:aws:bedrock:us-west-2:123456789012:application-inference-profile/x"},
+		{
+			name:    "base ID suggests regional profile",
+			modelID: "anthropic.claude-sonnet-4-20250514-v1:0",
+			want:    `try us.anthropic.claude-sonnet-4-20250514-v1:0`,
+		},
+		{
+			name:    "other geography suggests local profile",
+			modelID: "eu.anthropic.claude-sonnet-4-20250514-v1:0",
+			want:    `try us.anthropic.claude-sonnet-4-20250514-v1:0`,
+		},
+		{
+			name:    "PromptKit model name suggests Bedrock ID",
+			modelID: "claude-3-5-haiku-20241022",
+			want:    `try anthropic.claude-3-5-haiku-20241022-v1:0`,
+		},
+		{
+			name:    "unknown model suggests same provider",
+			modelID: "amazon.nova-premier-v1:0",
+			want:    `"amazon.nova-premier-v1:0" is not available in us-west-2; try amazon.nova-pro-v1:0`,
+		},
+		{
+			name:    "no alternatives",
+			modelID: "meta.llama4-v1:0",
+			want:    `runtime model "meta.llama4-v1:0" is not available in us-west-2`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unavailableModels([]modelUse{{Owner: "runtime", ModelID: tt.modelID}}, usWest2Models, "us-west-2")
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("unexpected warnings: %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.HasSuffix(got[0], tt.want) {
+				t.Errorf("warnings = %v, want one ending in %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelUses(t *testing.T) {
+	pack := &prompt.Pack{Evals: []evals.EvalDef{
+		{ID: "tone", Type: evalTypeLLMAsJudge},
+		{ID: "custom", Type: evalTypeLLMAsJudge, Params: map[string]any{"model": "amazon.nova-pro-v1:0"}},
+		{ID: "length", Type: "max_length"},
+	}}
+	arena := &ArenaConfig{LoadedProviders: map[string]*ArenaProvider{"p": {Type: "claude", Model: "m-1"}}}
+
+	got := modelUses(pack, &Config{ArenaConfig: arena})
+	want := []modelUse{
+		{Owner: `evaluator "tone"`, ModelID: defaultEvalModel},
+		{Owner: `evaluator "custom"`, ModelID: "amazon.nova-pro-v1:0"},
+		{Owner: "runtime", ModelID: "m-1"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("uses = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("uses[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCheckModelAvailability_LookupFailure(t *testing.T) {
+	cfg := &Config{Region: "us-west-2", ArenaConfig: &ArenaConfig{
+		LoadedProviders: map[string]*ArenaProvider{"p": {Model: "m-1"}},
+	}}
+	got := checkModelAvailability(context.Background(),
+		catalogFactory(&fakeModelCatalog{err: errors.New("AccessDenied")}), &prompt.Pack{}, cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "could not check model availability in us-west-2") {
+		t.Errorf("warnings = %v", got)
+	}
+
+	if got := checkModelAvailability(context.Background(), nil, &prompt.Pack{}, cfg); got != nil {
+		t.Errorf("warnings without a catalog = %v, want none", got)
+	}
+}
+
+func TestPlan_WarnsAboutUnavailableModels(t *testing.T) {
+	p := newSimulatedProvider()
+	p.modelCatalogFunc = catalogFactory(&fakeModelCatalog{ids: usWest2Models})
+
+	resp, err := p.Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     singleAgentPackJSON(),
+		DeployConfig: validDeployConfig,
+		ArenaConfig:  `{"loaded_providers":{"claude":{"type":"claude","model":"anthropic.claude-opus-9-v1:0"}}}`,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	want := "Plan: 1 to create, 0 to update, 0 to delete\n" +
+		`Warning: runtime model "anthropic.claude-opus-9-v1:0" is not available in us-west-2; try `
+	if !strings.HasPrefix(resp.Summary, want) {
+		t.Errorf("summary = %q, want prefix %q", resp.Summary, want)
+	}
+}
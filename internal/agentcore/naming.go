@@ -39,74 +39,189 @@ func validateAWSName(name, resourceType string) error {
 // same naming convention.
 const toolGatewaySuffix = "_tool_gw"
 
+// evalResourceSuffix is the suffix appended to eval IDs to name their
+// evaluator resources.
+const evalResourceSuffix = "_eval"
+
+// derivedName is one resource name a pack derives, with the pack element
+// it is derived from.
+type derivedName struct {
+	name    string
+	resType string
+	source  string // the pack or config element, e.g. `tool "search"`
+	base    string // the element's own name, or "" when renaming it cannot help
+	suffix  string // name is base+suffix when base is set
+}
+
+// deriveNames lists every resource name the pack derives, simulating the
+// same naming patterns used by generateDesiredResources and apply phases.
+// Unlike collectDerivedNames it keeps names derived more than once.
+func deriveNames(pack *prompt.Pack, cfg *Config) []derivedName {
+	var names []derivedName
+	names = append(names, derivePackLevelNames(pack, cfg)...)
+	names = append(names, deriveEvalNames(pack)...)
+	names = append(names, deriveToolNames(pack)...)
+	names = append(names, deriveAgentNames(pack)...)
+	return names
+}
+
 // collectDerivedNames builds a map of all derived resource names to their
-// resource types, simulating the same naming patterns used by
-// generateDesiredResources and apply phases.
+// resource types.
 func collectDerivedNames(pack *prompt.Pack, cfg *Config) map[string]string {
 	names := make(map[string]string)
-	collectPackLevelNames(names, pack, cfg)
-	collectEvalNames(names, pack)
-	collectToolNames(names, pack)
-	collectAgentNames(names, pack)
+	for _, d := range deriveNames(pack, cfg) {
+		names[d.name] = d.resType
+	}
 	return names
 }
 
-// collectPackLevelNames adds memory, cedar policy, and online eval names.
-func collectPackLevelNames(names map[string]string, pack *prompt.Pack, cfg *Config) {
+// suffixedName derives a name by appending suffix to an element's name.
+func suffixedName(base, suffix, resType, source string) derivedName {
+	return derivedName{name: base + suffix, resType: resType, source: source, base: base, suffix: suffix}
+}
+
+// packSource describes the pack itself as a name source.
+func packSource(pack *prompt.Pack) string {
+	return fmt.Sprintf("pack %q", pack.ID)
+}
+
+// derivePackLevelNames lists memory, cedar policy, inference profile, and
+// identity provider names.
+func derivePackLevelNames(pack *prompt.Pack, cfg *Config) []derivedName {
+	var names []derivedName
 	if cfg.HasMemory() {
-		names[pack.ID+"_memory"] = ResTypeMemory
+		names = append(names, suffixedName(pack.ID, "_memory", ResTypeMemory, packSource(pack)))
 	}
 	for _, policyName := range policyResourceNames(pack) {
-		names[policyName+"_policy_engine"] = ResTypeCedarPolicy
+		names = append(names, suffixedName(policyName, "_policy_engine", ResTypeCedarPolicy,
+			fmt.Sprintf("prompt %q", policyName)))
+	}
+	for _, ap := range applicationProfiles(pack, cfg) {
+		names = append(names, derivedName{name: ap.Name, resType: ResTypeInferenceProfile, source: "inference_profiles"})
 	}
+	for _, key := range sortedKeys(cfg.IdentityProviders) {
+		names = append(names, suffixedName(key, identityProviderSuffix, ResTypeIdentityProvider,
+			"identity_providers."+key))
+	}
+	return names
 }
 
-// collectEvalNames adds evaluator and online eval config names.
-func collectEvalNames(names map[string]string, pack *prompt.Pack) {
+// deriveEvalNames lists evaluator and online eval config names.
+func deriveEvalNames(pack *prompt.Pack) []derivedName {
+	var names []derivedName
 	hasOnlineEval := false
 	for i := range pack.Evals {
 		switch pack.Evals[i].Type {
 		case evalTypeLLMAsJudge:
-			names[pack.Evals[i].ID+"_eval"] = ResTypeEvaluator
+			id := pack.Evals[i].ID
+			names = append(names, suffixedName(id, evalResourceSuffix, ResTypeEvaluator, fmt.Sprintf("eval %q", id)))
 			hasOnlineEval = true
 		case evalTypeBuiltin:
 			hasOnlineEval = true
 		}
 	}
 	if hasOnlineEval {
-		names[pack.ID+"_online_eval"] = ResTypeOnlineEvalConfig
+		names = append(names, suffixedName(pack.ID, "_online_eval", ResTypeOnlineEvalConfig, packSource(pack)))
 	}
+	return names
 }
 
-// collectToolNames adds tool gateway names.
-func collectToolNames(names map[string]string, pack *prompt.Pack) {
-	for toolName := range pack.Tools {
-		names[toolName+toolGatewaySuffix] = ResTypeToolGateway
+// deriveToolNames lists the tool gateway names of the deployed tools.
+func deriveToolNames(pack *prompt.Pack) []derivedName {
+	var names []derivedName
+	for _, toolName := range deployedTools(pack) {
+		names = append(names, suffixedName(toolName, toolGatewaySuffix, ResTypeToolGateway,
+			fmt.Sprintf("tool %q", toolName)))
 	}
+	return names
 }
 
-// collectAgentNames adds runtime, a2a endpoint, and gateway names.
-func collectAgentNames(names map[string]string, pack *prompt.Pack) {
+// deriveAgentNames lists runtime, a2a endpoint, and gateway names.
+func deriveAgentNames(pack *prompt.Pack) []derivedName {
 	if adaptersdk.IsMultiAgent(pack) {
-		for memberName := range pack.Agents.Members {
-			names[memberName] = ResTypeAgentRuntime
-			names[memberName+"_endpoint"] = ResTypeA2AEndpoint
+		var names []derivedName
+		for _, member := range sortedKeys(pack.Agents.Members) {
+			source := fmt.Sprintf("agent %q", member)
+			names = append(names,
+				suffixedName(member, "", ResTypeAgentRuntime, source),
+				suffixedName(member, "_endpoint", ResTypeA2AEndpoint, source))
 		}
-		if pack.Agents.Entry != "" {
-			names[pack.Agents.Entry+"_gateway"] = "gateway"
+		if entry := pack.Agents.Entry; entry != "" {
+			names = append(names, suffixedName(entry, "_gateway", "gateway", fmt.Sprintf("agent %q", entry)))
 		}
-		return
+		return names
 	}
-	name := pack.ID
-	if name == "" {
-		name = defaultPackName
+	if pack.ID == "" {
+		return []derivedName{{name: defaultPackName, resType: ResTypeAgentRuntime, source: packSource(pack)}}
+	}
+	return []derivedName{suffixedName(pack.ID, "", ResTypeAgentRuntime, packSource(pack))}
+}
+
+// findNameCollisions reports resource names, with any workspace appended,
+// that more than one pack element derives. AWS would reject the second
+// create as a conflict, or an update would silently act on the other
+// element's resource, so each collision names a rename that separates them.
+func findNameCollisions(pack *prompt.Pack, cfg *Config) []string {
+	derived := deriveNames(pack, cfg)
+	byName := make(map[string][]derivedName)
+	taken := make(map[string]bool, len(derived))
+	for _, d := range derived {
+		name := cfg.awsName(d.name)
+		byName[name] = append(byName[name], d)
+		taken[d.name] = true
+	}
+
+	var errs []string
+	for _, name := range sortedKeys(byName) {
+		group := byName[name]
+		if len(group) < 2 {
+			continue
+		}
+		sources := make([]string, len(group))
+		for i, d := range group {
+			sources[i] = fmt.Sprintf("%s (%s)", d.source, d.resType)
+		}
+		msg := fmt.Sprintf("resource name %q is derived from %s", name, strings.Join(sources, " and "))
+		if d, ok := renameCandidate(group); ok {
+			msg += fmt.Sprintf("; rename %s, e.g. to %q", d.source, suggestRename(d, taken))
+		}
+		errs = append(errs, msg)
+	}
+	return errs
+}
+
+// renameCandidate picks the element of a collision to suggest renaming:
+// one whose name is used unsuffixed, since renaming it is least likely to
+// collide again, else the last renameable one.
+func renameCandidate(group []derivedName) (derivedName, bool) {
+	var candidate derivedName
+	found := false
+	for _, d := range group {
+		if d.base == "" {
+			continue
+		}
+		if d.suffix == "" {
+			return d, true
+		}
+		candidate, found = d, true
+	}
+	return candidate, found
+}
+
+// suggestRename returns d's base name with the lowest numeric suffix whose
+// derived name no other element already uses.
+func suggestRename(d derivedName, taken map[string]bool) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s_%d", d.base, n)
+		if !taken[candidate+d.suffix] {
+			return candidate
+		}
 	}
-	names[name] = ResTypeAgentRuntime
 }
 
-// validateResourceNames collects all derived resource names and validates them
-// against the AWS naming pattern. Returns a list of validation errors, or nil
-// if all names are valid.
+// validateResourceNames collects all derived resource names and validates them,
+// with any workspace appended, against the AWS naming pattern. Returns a list
+// of validation errors, or nil if all names are valid.
 func validateResourceNames(pack *prompt.Pack, cfg *Config) []string {
 	derived := collectDerivedNames(pack, cfg)
 
@@ -119,7 +234,7 @@ func validateResourceNames(pack *prompt.Pack, cfg *Config) []string {
 
 	var errs []string
 	for _, name := range keys {
-		if err := validateAWSName(name, derived[name]); err != nil {
+		if err := validateAWSName(cfg.awsName(name), derived[name]); err != nil {
 			errs = append(errs, err.Error())
 		}
 	}
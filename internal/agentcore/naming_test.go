@@ -101,7 +101,8 @@ func TestCollectDerivedNames_EmptyPackID(t *testing.T) {
 
 func TestCollectDerivedNames_WithTools(t *testing.T) {
 	pack := &prompt.Pack{
-		ID: "toolpack",
+		ID:      "toolpack",
+		Prompts: map[string]*prompt.PackPrompt{"toolpack": {Tools: []string{"search", "calc"}}},
 		Tools: map[string]*prompt.PackTool{
 			"search": {Name: "search"},
 			"calc":   {Name: "calc"},
@@ -230,7 +231,8 @@ func TestValidateResourceNames_HyphenatedPackID(t *testing.T) {
 
 func TestValidateResourceNames_HyphenatedToolName(t *testing.T) {
 	pack := &prompt.Pack{
-		ID: "mypack",
+		ID:      "mypack",
+		Prompts: map[string]*prompt.PackPrompt{"mypack": {Tools: []string{"web-search"}}},
 		Tools: map[string]*prompt.PackTool{
 			"web-search": {Name: "web-search"},
 		},
@@ -322,6 +324,7 @@ func TestCollectDerivedNames_FullPack(t *testing.T) {
 			"worker": map[string]any{
 				"id":              "worker",
 				"system_template": "work",
+				"tools":           []string{"search"},
 			},
 		},
 		"agents": map[string]any{
@@ -384,3 +387,56 @@ func TestFormatNameErrors(t *testing.T) {
 		t.Errorf("formatNameErrors = %q, want %q", result, "error one; error two")
 	}
 }
+
+func TestFindNameCollisions(t *testing.T) {
+	pack := &prompt.Pack{
+		ID: "multi",
+		Agents: &prompt.AgentsConfig{
+			Entry: "router",
+			Members: map[string]*prompt.AgentDef{
+				"router":           {},
+				"search_tool_gw":   {},
+				"search_tool_gw_2": {},
+			},
+		},
+		Prompts: map[string]*prompt.PackPrompt{"router": {Tools: []string{"search"}}},
+		Tools: map[string]*prompt.PackTool{
+			"search": {Name: "search"},
+		},
+	}
+
+	if errs := findNameCollisions(pack, &Config{}); len(errs) != 1 ||
+		errs[0] != `resource name "search_tool_gw" is derived from tool "search" (tool_gateway) and `+
+			`agent "search_tool_gw" (agent_runtime); rename agent "search_tool_gw", e.g. to "search_tool_gw_3"` {
+		t.Errorf("collisions = %v", errs)
+	}
+
+	// The workspace suffix applies to every name alike.
+	if errs := findNameCollisions(pack, &Config{Workspace: "dev"}); len(errs) != 1 ||
+		!strings.Contains(errs[0], `"search_tool_gw_dev"`) {
+		t.Errorf("workspace collisions = %v", errs)
+	}
+
+	delete(pack.Tools, "search")
+	if errs := findNameCollisions(pack, &Config{}); len(errs) != 0 {
+		t.Errorf("expected no collisions, got %v", errs)
+	}
+}
+
+func TestFindNameCollisions_SuffixedOnly(t *testing.T) {
+	pack := &prompt.Pack{
+		ID: "multi",
+		Agents: &prompt.AgentsConfig{
+			Entry: "router",
+			Members: map[string]*prompt.AgentDef{
+				"router":     {},
+				"a":          {},
+				"a_endpoint": {},
+			},
+		},
+	}
+	errs := findNameCollisions(pack, &Config{})
+	if len(errs) != 1 || !strings.HasSuffix(errs[0], `rename agent "a_endpoint", e.g. to "a_endpoint_2"`) {
+		t.Errorf("collisions = %v", errs)
+	}
+}
@@ -0,0 +1,67 @@
+package agentcore
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// Runtime network modes.
+const (
+	networkModePublic = string(types.NetworkModePublic)
+	networkModeVPC    = string(types.NetworkModeVpc)
+)
+
+// NetworkConfig selects the network the runtimes run in. AgentCore cannot
+// move an existing runtime between modes, so changing Mode replaces the
+// runtimes.
+type NetworkConfig struct {
+	// Mode is PUBLIC (the default) or VPC.
+	Mode string `json:"mode,omitempty"`
+
+	// Subnets and SecurityGroups place the runtimes in a VPC. Both are
+	// required in VPC mode and rejected in PUBLIC mode.
+	Subnets        []string `json:"subnets,omitempty"`
+	SecurityGroups []string `json:"security_groups,omitempty"`
+}
+
+// validateNetwork checks the network block.
+func validateNetwork(c *NetworkConfig) []string {
+	if c == nil {
+		return nil
+	}
+	switch c.Mode {
+	case "", networkModePublic:
+		if len(c.Subnets) > 0 || len(c.SecurityGroups) > 0 {
+			return []string{"network.subnets and network.security_groups require network.mode VPC"}
+		}
+	case networkModeVPC:
+		if len(c.Subnets) == 0 || len(c.SecurityGroups) == 0 {
+			return []string{"network.mode VPC requires network.subnets and network.security_groups"}
+		}
+	default:
+		return []string{fmt.Sprintf("network.mode %q must be %s or %s", c.Mode, networkModePublic, networkModeVPC)}
+	}
+	return nil
+}
+
+// networkMode returns the network mode the runtimes run in.
+func (c *Config) networkMode() string {
+	if c.Network == nil || c.Network.Mode == "" {
+		return networkModePublic
+	}
+	return c.Network.Mode
+}
+
+// buildNetworkConfiguration returns the network configuration to send
+// with CreateAgentRuntime and UpdateAgentRuntime.
+func buildNetworkConfiguration(cfg *Config) *types.NetworkConfiguration {
+	nc := &types.NetworkConfiguration{NetworkMode: types.NetworkMode(cfg.networkMode())}
+	if nc.NetworkMode == types.NetworkModeVpc {
+		nc.NetworkModeConfig = &types.VpcConfig{
+			Subnets:        cfg.Network.Subnets,
+			SecurityGroups: cfg.Network.SecurityGroups,
+		}
+	}
+	return nc
+}
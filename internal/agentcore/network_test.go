@@ -0,0 +1,55 @@
+package agentcore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+func TestValidateNetwork(t *testing.T) {
+	vpc := &NetworkConfig{Mode: networkModeVPC, Subnets: []string{"subnet-1"}, SecurityGroups: []string{"sg-1"}}
+	tests := []struct {
+		name    string
+		cfg     *NetworkConfig
+		wantErr string
+	}{
+		{name: "nil"},
+		{name: "public", cfg: &NetworkConfig{Mode: networkModePublic}},
+		{name: "vpc", cfg: vpc},
+		{name: "unknown mode", cfg: &NetworkConfig{Mode: "PRIVATE"}, wantErr: "must be PUBLIC or VPC"},
+		{name: "vpc without subnets", cfg: &NetworkConfig{Mode: networkModeVPC}, wantErr: "requires network.subnets"},
+		{
+			name: "subnets in public mode", cfg: &NetworkConfig{Subnets: []string{"subnet-1"}},
+			wantErr: "require network.mode VPC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateNetwork(tt.cfg)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("errs = %v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildNetworkConfiguration(t *testing.T) {
+	if nc := buildNetworkConfiguration(&Config{}); nc.NetworkMode != types.NetworkModePublic || nc.NetworkModeConfig != nil {
+		t.Errorf("default network = %+v, want PUBLIC", nc)
+	}
+	cfg := &Config{Network: &NetworkConfig{
+		Mode: networkModeVPC, Subnets: []string{"subnet-1"}, SecurityGroups: []string{"sg-1"},
+	}}
+	nc := buildNetworkConfiguration(cfg)
+	if nc.NetworkMode != types.NetworkModeVpc || nc.NetworkModeConfig == nil ||
+		nc.NetworkModeConfig.Subnets[0] != "subnet-1" || nc.NetworkModeConfig.SecurityGroups[0] != "sg-1" {
+		t.Errorf("vpc network = %+v", nc)
+	}
+}
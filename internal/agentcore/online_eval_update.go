@@ -0,0 +1,194 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// onlineEvalSpec is the desired shape of an online eval config, shared by
+// create and update.
+type onlineEvalSpec struct {
+	logGroups    []string
+	serviceNames []string
+	evaluators   []types.EvaluatorReference
+	samplingPct  float64
+}
+
+// errNoEvaluatorRefs rejects an online eval config with nothing to run.
+var errNoEvaluatorRefs = errors.New("no evaluator references available")
+
+// buildOnlineEvalSpec derives the online eval config from cfg.
+func buildOnlineEvalSpec(cfg *Config) (onlineEvalSpec, error) {
+	evalRefs := buildEvaluatorReferences(cfg.EvalARNs, cfg.BuiltinEvalIDs)
+	if len(evalRefs) == 0 {
+		return onlineEvalSpec{}, errNoEvaluatorRefs
+	}
+	return onlineEvalSpec{
+		logGroups:    []string{resolveLogGroup(cfg)},
+		serviceNames: cfg.serviceNames(),
+		evaluators:   evalRefs,
+		samplingPct:  effectiveSamplingPercentage(cfg.EvalSampling),
+	}, nil
+}
+
+// serviceName returns the OTEL service name the runtime of the named
+// agent publishes its spans under. It follows the AgentCore convention
+// <runtime-name>.DEFAULT, and Apply sets it as the runtime's
+// OTEL_SERVICE_NAME.
+func (c *Config) serviceName(agent string) string {
+	return c.awsName(agent) + "." + defaultEndpointName
+}
+
+// serviceNames returns the service name of every runtime the pack
+// deploys, so the online eval config evaluates the spans of each member
+// of a multi-agent pack and not only those of a runtime named after the
+// pack.
+func (c *Config) serviceNames() []string {
+	runtimes := c.RuntimeNames
+	if len(runtimes) == 0 {
+		runtimes = []string{c.ResourceTags[TagKeyPackID]}
+	}
+	names := make([]string, len(runtimes))
+	for i, name := range runtimes {
+		names[i] = c.serviceName(name)
+	}
+	return names
+}
+
+// prepareOnlineEvalSpec builds the spec and makes sure its log group
+// exists. For the default "aws/spans" group, Transaction Search must be
+// enabled — the group is AWS-managed and cannot be created manually.
+func (c *realAWSClient) prepareOnlineEvalSpec(ctx context.Context, cfg *Config) (onlineEvalSpec, error) {
+	spec, err := buildOnlineEvalSpec(cfg)
+	if err != nil {
+		return spec, err
+	}
+	for _, lg := range spec.logGroups {
+		if lg == defaultTraceLogGroup {
+			continue
+		}
+		if err := c.ensureLogGroup(ctx, lg); err != nil {
+			return spec, err
+		}
+	}
+	return spec, nil
+}
+
+func (s *onlineEvalSpec) dataSource() types.DataSourceConfig {
+	return &types.DataSourceConfigMemberCloudWatchLogs{
+		Value: types.CloudWatchLogsInputConfig{
+			LogGroupNames: s.logGroups,
+			ServiceNames:  s.serviceNames,
+		},
+	}
+}
+
+func (s *onlineEvalSpec) rule() *types.Rule {
+	return &types.Rule{
+		SamplingConfig: &types.SamplingConfig{
+			SamplingPercentage: aws.Float64(s.samplingPct),
+		},
+	}
+}
+
+// UpdateOnlineEvalConfig brings an existing online eval config in line with
+// the pack. Only the evaluator references, data source, and sampling rule
+// that differ are sent; an unchanged config is left alone. Filters and
+// session settings set outside the adapter are kept.
+func (c *realAWSClient) UpdateOnlineEvalConfig(
+	ctx context.Context, arn string, name string, cfg *Config,
+) (string, error) {
+	id := extractResourceID(arn, "online-evaluation-config")
+	if id == "" {
+		return "", fmt.Errorf("UpdateOnlineEvaluationConfig %q: could not extract ID from ARN %q", name, arn)
+	}
+	spec, err := c.prepareOnlineEvalSpec(ctx, cfg)
+	if err != nil {
+		return arn, fmt.Errorf("UpdateOnlineEvaluationConfig %q: %w", name, err)
+	}
+	current, err := c.client.GetOnlineEvaluationConfig(ctx,
+		&bedrockagentcorecontrol.GetOnlineEvaluationConfigInput{OnlineEvaluationConfigId: aws.String(id)})
+	if err != nil {
+		return arn, fmt.Errorf("GetOnlineEvaluationConfig %q: %w", name, err)
+	}
+
+	input, changed := diffOnlineEvalConfig(current, &spec)
+	if len(changed) == 0 {
+		logf("agentcore: online eval config %q unchanged", name)
+		return arn, nil
+	}
+	input.OnlineEvaluationConfigId = aws.String(id)
+	logf("agentcore: updating online eval config %q: %v", name, changed)
+	if _, err := c.client.UpdateOnlineEvaluationConfig(ctx, input); err != nil {
+		return arn, fmt.Errorf("UpdateOnlineEvaluationConfig %q: %w", name, err)
+	}
+	if err := c.waitForOnlineEvalConfigReady(ctx, id); err != nil {
+		return arn, fmt.Errorf("online eval config %q updated but not active: %w", name, err)
+	}
+	return arn, nil
+}
+
+// diffOnlineEvalConfig compares the deployed config with spec. It returns
+// an update carrying only the fields that differ, and their names.
+func diffOnlineEvalConfig(
+	current *bedrockagentcorecontrol.GetOnlineEvaluationConfigOutput, spec *onlineEvalSpec,
+) (*bedrockagentcorecontrol.UpdateOnlineEvaluationConfigInput, []string) {
+	input := &bedrockagentcorecontrol.UpdateOnlineEvaluationConfigInput{}
+	var changed []string
+
+	if !sameStringSet(evaluatorRefIDs(current.Evaluators), evaluatorRefIDs(spec.evaluators)) {
+		input.Evaluators = spec.evaluators
+		changed = append(changed, "evaluators")
+	}
+
+	cw, ok := current.DataSourceConfig.(*types.DataSourceConfigMemberCloudWatchLogs)
+	if !ok || !sameStringSet(cw.Value.LogGroupNames, spec.logGroups) ||
+		!sameStringSet(cw.Value.ServiceNames, spec.serviceNames) {
+		input.DataSourceConfig = spec.dataSource()
+		changed = append(changed, "data_source")
+	}
+
+	if currentSamplingPercentage(current.Rule) != spec.samplingPct {
+		rule := spec.rule()
+		if current.Rule != nil {
+			rule.Filters = current.Rule.Filters
+			rule.SessionConfig = current.Rule.SessionConfig
+		}
+		input.Rule = rule
+		changed = append(changed, "sampling")
+	}
+	return input, changed
+}
+
+// evaluatorRefIDs returns the evaluator IDs in refs.
+func evaluatorRefIDs(refs []types.EvaluatorReference) []string {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if r, ok := ref.(*types.EvaluatorReferenceMemberEvaluatorId); ok {
+			ids = append(ids, r.Value)
+		}
+	}
+	return ids
+}
+
+func currentSamplingPercentage(rule *types.Rule) float64 {
+	if rule == nil || rule.SamplingConfig == nil {
+		return 0
+	}
+	return aws.ToFloat64(rule.SamplingConfig.SamplingPercentage)
+}
+
+// sameStringSet reports whether a and b hold the same strings, ignoring
+// order and duplicates.
+func sameStringSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
@@ -0,0 +1,254 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+const testOnlineEvalARN = "arn:aws:bedrock-agentcore:us-west-2:123456789012:online-evaluation-config/oec-1"
+
+func onlineEvalTestConfig() *Config {
+	return &Config{
+		ResourceTags: map[string]string{TagKeyPackID: "mypack"},
+		EvalARNs: map[string]string{
+			"tone": "arn:aws:bedrock-agentcore:us-west-2:123456789012:evaluator/ev-tone",
+		},
+		BuiltinEvalIDs: []string{"Builtin.Helpfulness"},
+		EvalSampling:   []evalSampling{{Name: "tone", Percentage: 10}},
+	}
+}
+
+// deployedOnlineEval is a GetOnlineEvaluationConfig result matching
+// onlineEvalTestConfig.
+func deployedOnlineEval() *bedrockagentcorecontrol.GetOnlineEvaluationConfigOutput {
+	return &bedrockagentcorecontrol.GetOnlineEvaluationConfigOutput{
+		Evaluators: []types.EvaluatorReference{
+			&types.EvaluatorReferenceMemberEvaluatorId{Value: "Builtin.Helpfulness"},
+			&types.EvaluatorReferenceMemberEvaluatorId{Value: "ev-tone"},
+		},
+		DataSourceConfig: &types.DataSourceConfigMemberCloudWatchLogs{Value: types.CloudWatchLogsInputConfig{
+			LogGroupNames: []string{defaultTraceLogGroup},
+			ServiceNames:  []string{"mypack.DEFAULT"},
+		}},
+		Rule: &types.Rule{
+			SamplingConfig: &types.SamplingConfig{SamplingPercentage: aws.Float64(10)},
+			Filters: []types.Filter{{
+				Key:      aws.String("env"),
+				Operator: types.FilterOperatorEquals,
+				Value:    &types.FilterValueMemberStringValue{Value: "prod"},
+			}},
+		},
+	}
+}
+
+func TestBuildOnlineEvalSpec_NoEvaluators(t *testing.T) {
+	if _, err := buildOnlineEvalSpec(&Config{}); err != errNoEvaluatorRefs {
+		t.Errorf("err = %v, want %v", err, errNoEvaluatorRefs)
+	}
+}
+
+func TestBuildOnlineEvalSpec_ServiceNames(t *testing.T) {
+	cfg := onlineEvalTestConfig()
+	spec, _ := buildOnlineEvalSpec(cfg)
+	if !slices.Equal(spec.serviceNames, []string{"mypack.DEFAULT"}) {
+		t.Errorf("single-agent service names = %v", spec.serviceNames)
+	}
+
+	cfg.RuntimeNames = []string{"router", "worker"}
+	spec, _ = buildOnlineEvalSpec(cfg)
+	if want := []string{"router.DEFAULT", "worker.DEFAULT"}; !slices.Equal(spec.serviceNames, want) {
+		t.Errorf("multi-agent service names = %v, want %v", spec.serviceNames, want)
+	}
+	for _, name := range cfg.RuntimeNames {
+		if got := runtimeEnvVarsForAgent(cfg, name)[EnvOTELServiceName]; !slices.Contains(spec.serviceNames, got) {
+			t.Errorf("runtime %s publishes %q, which the online eval config does not evaluate", name, got)
+		}
+	}
+	if _, changed := diffOnlineEvalConfig(deployedOnlineEval(), &spec); !slices.Equal(changed, []string{"data_source"}) {
+		t.Errorf("changed = %v, want the data source updated", changed)
+	}
+}
+
+func TestDiffOnlineEvalConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(cfg *Config)
+		wantChanged []string
+	}{
+		{name: "unchanged", mutate: func(*Config) {}},
+		{
+			name:        "evaluator added",
+			mutate:      func(cfg *Config) { cfg.BuiltinEvalIDs = append(cfg.BuiltinEvalIDs, "Builtin.Correctness") },
+			wantChanged: []string{"evaluators"},
+		},
+		{
+			name: "log group changed",
+			mutate: func(cfg *Config) {
+				cfg.Observability = &ObservabilityConfig{CloudWatchLogGroup: "/custom/traces"}
+			},
+			wantChanged: []string{"data_source"},
+		},
+		{
+			name:        "sampling changed",
+			mutate:      func(cfg *Config) { cfg.EvalSampling[0].Percentage = 50 },
+			wantChanged: []string{"sampling"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := onlineEvalTestConfig()
+			tt.mutate(cfg)
+			spec, err := buildOnlineEvalSpec(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input, changed := diffOnlineEvalConfig(deployedOnlineEval(), &spec)
+			if !slices.Equal(changed, tt.wantChanged) {
+				t.Fatalf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if (input.Evaluators != nil) != slices.Contains(changed, "evaluators") ||
+				(input.DataSourceConfig != nil) != slices.Contains(changed, "data_source") ||
+				(input.Rule != nil) != slices.Contains(changed, "sampling") {
+				t.Errorf("input carries fields outside %v: %+v", changed, input)
+			}
+		})
+	}
+}
+
+func TestDiffOnlineEvalConfig_SamplingKeepsFilters(t *testing.T) {
+	cfg := onlineEvalTestConfig()
+	cfg.EvalSampling[0].Percentage = 50
+	spec, _ := buildOnlineEvalSpec(cfg)
+
+	input, _ := diffOnlineEvalConfig(deployedOnlineEval(), &spec)
+	if got := aws.ToFloat64(input.Rule.SamplingConfig.SamplingPercentage); got != 50 {
+		t.Errorf("sampling = %v, want 50", got)
+	}
+	if len(input.Rule.Filters) != 1 || aws.ToString(input.Rule.Filters[0].Key) != "env" {
+		t.Errorf("filters = %+v, want the deployed filter kept", input.Rule.Filters)
+	}
+}
+
+const deployedOnlineEvalJSON = `{"onlineEvaluationConfigId":"oec-1","status":"ACTIVE",` +
+	`"evaluators":[{"evaluatorId":"ev-tone"},{"evaluatorId":"Builtin.Helpfulness"}],` +
+	`"dataSourceConfig":{"cloudWatchLogs":{"logGroupNames":["aws/spans"],"serviceNames":["mypack.DEFAULT"]}},` +
+	`"rule":{"samplingConfig":{"samplingPercentage":10}}}`
+
+func TestUpdateOnlineEvalConfig_Unchanged(t *testing.T) {
+	c, _, stub := newStubbedRealClient(5, deployedOnlineEvalJSON)
+
+	arn, err := c.UpdateOnlineEvalConfig(context.Background(), testOnlineEvalARN, "mypack_online_eval",
+		onlineEvalTestConfig())
+	if err != nil {
+		t.Fatalf("UpdateOnlineEvalConfig: %v", err)
+	}
+	if arn != testOnlineEvalARN {
+		t.Errorf("arn = %q", arn)
+	}
+	if stub.calls != 1 {
+		t.Errorf("made %d calls, want only GetOnlineEvaluationConfig", stub.calls)
+	}
+}
+
+// recordingHTTP answers like stubHTTP and keeps each request body.
+type recordingHTTP struct {
+	stubHTTP
+	requests []string
+}
+
+func (r *recordingHTTP) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	r.requests = append(r.requests, req.Method+" "+req.URL.Path+" "+string(body))
+	return r.stubHTTP.Do(req)
+}
+
+// newRecordingRealClient is newStubbedRealClient with the AgentCore
+// control-plane requests recorded.
+func newRecordingRealClient(bodies ...string) (*realAWSClient, *recordingHTTP) {
+	c, _, _ := newStubbedRealClient(5)
+	rec := &recordingHTTP{stubHTTP: stubHTTP{bodies: bodies}}
+	c.client = bedrockagentcorecontrol.New(bedrockagentcorecontrol.Options{
+		Region: "us-west-2", Credentials: aws.AnonymousCredentials{}, HTTPClient: rec,
+	})
+	return c, rec
+}
+
+func TestUpdateOnlineEvalConfig_SendsChangedFields(t *testing.T) {
+	c, rec := newRecordingRealClient(
+		deployedOnlineEvalJSON, `{"onlineEvaluationConfigId":"oec-1","status":"UPDATING"}`, deployedOnlineEvalJSON)
+	cfg := onlineEvalTestConfig()
+	cfg.EvalSampling[0].Percentage = 50
+
+	if _, err := c.UpdateOnlineEvalConfig(context.Background(), testOnlineEvalARN, "mypack_online_eval",
+		cfg); err != nil {
+		t.Fatalf("UpdateOnlineEvalConfig: %v", err)
+	}
+	if len(rec.requests) != 3 {
+		t.Fatalf("requests = %v, want get, update, get", rec.requests)
+	}
+	update := rec.requests[1]
+	if !strings.HasPrefix(update, "PUT ") || !strings.Contains(update, `"samplingPercentage":50`) {
+		t.Errorf("update request = %s", update)
+	}
+	if strings.Contains(update, "evaluators") || strings.Contains(update, "dataSourceConfig") {
+		t.Errorf("update sent unchanged fields: %s", update)
+	}
+}
+
+// onlineEvalUpdateClient records online eval config updates.
+type onlineEvalUpdateClient struct {
+	simulatedAWSClient
+	updated []string
+}
+
+func (c *onlineEvalUpdateClient) UpdateOnlineEvalConfig(
+	ctx context.Context, arn string, name string, cfg *Config,
+) (string, error) {
+	c.updated = append(c.updated, name)
+	return c.simulatedAWSClient.UpdateOnlineEvalConfig(ctx, arn, name, cfg)
+}
+
+func TestApply_UpdatesOnlineEvalConfig(t *testing.T) {
+	client := &onlineEvalUpdateClient{simulatedAWSClient: *newSimulatedAWSClient("us-west-2")}
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) { return client, nil }
+
+	prior := AdapterState{PackID: "evalpack", Resources: []ResourceState{
+		{Type: ResTypeOnlineEvalConfig, Name: "evalpack_online_eval", ARN: testOnlineEvalARN, Status: ResStatusCreated},
+	}}
+	_, raw, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     multiAgentPackWithEvals(),
+		DeployConfig: validConfig(t),
+		PriorState:   mustJSON(t, prior),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !slices.Equal(client.updated, []string{"evalpack_online_eval"}) {
+		t.Errorf("updated = %v, want the online eval config", client.updated)
+	}
+
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range state.Resources {
+		if r.Type == ResTypeOnlineEvalConfig && (r.Status != ResStatusUpdated || r.ARN != testOnlineEvalARN) {
+			t.Errorf("online eval config = %+v, want updated in place", r)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrockTypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// orphanTypePolicyEngine has no ResTypeX constant because state never
+// tracks policy engines on their own.
+const orphanTypePolicyEngine = "policy_engine"
+
+// runtimeLogGroupPrefix is where AgentCore writes runtime logs. AgentCore
+// creates these groups itself and leaves them behind when a runtime is
+// deleted.
+const runtimeLogGroupPrefix = "/aws/bedrock-agentcore/runtimes/"
+
+// orphanResource is an AWS resource belonging to the pack that still
+// exists after Destroy.
+type orphanResource struct {
+	Type string
+	Name string
+	ID   string // identifier the delete command takes
+}
+
+// reportOrphans runs the destroyer's orphan scan, if it has one, and emits
+// a summary of what was left behind with a cleanup command for each. Scan
+// failures are reported but never fail the destroy.
+func reportOrphans(
+	ctx context.Context, destroyer resourceDestroyer, packID, region string,
+	resources []ResourceState, callback deploy.DestroyCallback,
+) {
+	scanner, ok := destroyer.(orphanScanner)
+	if !ok {
+		return
+	}
+	if packID == "" {
+		emitDestroyEvent(callback, "progress", "Orphan scan skipped: prior state has no pack ID")
+		return
+	}
+	orphans, err := scanner.ScanOrphans(ctx, packID, resources)
+	if err != nil {
+		emitDestroyEvent(callback, "progress", fmt.Sprintf("Orphan scan incomplete: %v", err))
+	}
+	emitDestroyEvent(callback, "progress", formatOrphanReport(packID, orphans, region))
+}
+
+// destroyPackID returns the pack ID the destroyed resources were tagged
+// with: a promptpack:pack-id in the config's tags wins over the state's.
+func destroyPackID(state *AdapterState, cfg *Config) string {
+	if id := cfg.Tags[TagKeyPackID]; id != "" {
+		return id
+	}
+	return state.PackID
+}
+
+// formatOrphanReport renders the orphan scan summary.
+func formatOrphanReport(packID string, orphans []orphanResource, region string) string {
+	if len(orphans) == 0 {
+		return fmt.Sprintf("Orphan scan: no resources for %s=%s remain", TagKeyPackID, packID)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Type != orphans[j].Type {
+			return orphans[i].Type < orphans[j].Type
+		}
+		return orphans[i].Name < orphans[j].Name
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "Orphan scan: %d resources for %s=%s remain; to remove them:",
+		len(orphans), TagKeyPackID, packID)
+	for _, o := range orphans {
+		fmt.Fprintf(&b, "\n  %s %q: %s", o.Type, o.Name, orphanCleanupCommand(o, region))
+	}
+	return b.String()
+}
+
+// orphanCleanupCommand returns the AWS CLI command that deletes o.
+func orphanCleanupCommand(o orphanResource, region string) string {
+	const control = "aws bedrock-agentcore-control "
+	var cmd string
+	switch o.Type {
+	case ResTypeAgentRuntime:
+		cmd = control + "delete-agent-runtime --agent-runtime-id " + o.ID
+	case ResTypeToolGateway:
+		cmd = control + "delete-gateway --gateway-identifier " + o.ID
+	case ResTypeMemory:
+		cmd = control + "delete-memory --memory-id " + o.ID
+	case ResTypeEvaluator:
+		cmd = control + "delete-evaluator --evaluator-id " + o.ID
+	case ResTypeOnlineEvalConfig:
+		cmd = control + "delete-online-evaluation-config --online-evaluation-config-id " + o.ID
+	case orphanTypePolicyEngine:
+		cmd = control + "delete-policy-engine --policy-engine-id " + o.ID
+	case ResTypeIdentityProvider:
+		cmd = control + "delete-oauth2-credential-provider --name " + o.ID
+	case ResTypeInferenceProfile:
+		cmd = "aws bedrock delete-inference-profile --inference-profile-identifier " + o.ID
+	case ResTypeLogGroup:
+		cmd = "aws logs delete-log-group --log-group-name " + o.ID
+	default:
+		return "delete it in the AWS console"
+	}
+	if region != "" {
+		cmd += " --region " + region
+	}
+	return cmd
+}
+
+// ---------- orphanScanner implementation ----------
+
+// ScanOrphans implements orphanScanner. Tagged AgentCore and Bedrock
+// resources are matched on the promptpack:pack-id and promptpack:workspace
+// tags. Policy engines and log groups cannot be found by tag, so they are
+// looked up from the destroyed state instead. Resources already being
+// deleted are not reported. Each lookup that fails is skipped and its
+// error returned alongside whatever the others found.
+func (c *realAWSClient) ScanOrphans(
+	ctx context.Context, packID string, resources []ResourceState,
+) ([]orphanResource, error) {
+	scans := []func(context.Context) ([]taggedCandidate, error){
+		c.listRuntimeCandidates,
+		c.listGatewayCandidates,
+		c.listMemoryCandidates,
+		c.listEvaluatorCandidates,
+		c.listOnlineEvalCandidates,
+		c.listInferenceProfileCandidates,
+		c.listIdentityProviderCandidates,
+	}
+	var orphans []orphanResource
+	var errs []error
+	for _, scan := range scans {
+		candidates, err := scan(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tagged, err := c.filterByPackID(ctx, packID, candidates)
+		orphans = append(orphans, tagged...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	engines, err := c.remainingPolicyEngines(ctx, resources)
+	orphans = append(orphans, engines...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	groups, err := c.remainingLogGroups(ctx, resources)
+	orphans = append(orphans, groups...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return orphans, errors.Join(errs...)
+}
+
+// taggedCandidate is a listed resource whose tags have yet to be checked.
+type taggedCandidate struct {
+	orphanResource
+	arn string
+}
+
+// filterByPackID keeps the candidates tagged with packID and the deploy
+// config's workspace.
+func (c *realAWSClient) filterByPackID(
+	ctx context.Context, packID string, candidates []taggedCandidate,
+) ([]orphanResource, error) {
+	var orphans []orphanResource
+	for _, cand := range candidates {
+		tags, err := c.resourceTags(ctx, cand.Type, cand.arn)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return orphans, fmt.Errorf("read tags of %s %q: %w", cand.Type, cand.Name, err)
+		}
+		if tags[TagKeyPackID] == packID && tags[TagKeyWorkspace] == c.cfg.Workspace {
+			orphans = append(orphans, cand.orphanResource)
+		}
+	}
+	return orphans, nil
+}
+
+func (c *realAWSClient) listRuntimeCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.client.ListAgentRuntimes(ctx, &bedrockagentcorecontrol.ListAgentRuntimesInput{
+			MaxResults: aws.Int32(listPageSize), NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListAgentRuntimes: %w", err)
+		}
+		for _, rt := range page.AgentRuntimes {
+			if rt.Status == types.AgentRuntimeStatusDeleting {
+				continue
+			}
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{
+					Type: ResTypeAgentRuntime, Name: aws.ToString(rt.AgentRuntimeName),
+					ID: aws.ToString(rt.AgentRuntimeId),
+				},
+				arn: aws.ToString(rt.AgentRuntimeArn),
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// listGatewayCandidates lists gateways. Gateway summaries carry no ARN, so
+// each live gateway is fetched for it.
+func (c *realAWSClient) listGatewayCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.client.ListGateways(ctx, &bedrockagentcorecontrol.ListGatewaysInput{
+			MaxResults: aws.Int32(listPageSize), NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListGateways: %w", err)
+		}
+		for _, gw := range page.Items {
+			if gw.Status == types.GatewayStatusDeleting {
+				continue
+			}
+			detail, err := c.client.GetGateway(ctx, &bedrockagentcorecontrol.GetGatewayInput{
+				GatewayIdentifier: gw.GatewayId,
+			})
+			if err != nil {
+				return out, fmt.Errorf("GetGateway %q: %w", aws.ToString(gw.Name), err)
+			}
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{
+					Type: ResTypeToolGateway, Name: aws.ToString(gw.Name), ID: aws.ToString(gw.GatewayId),
+				},
+				arn: aws.ToString(detail.GatewayArn),
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+func (c *realAWSClient) listMemoryCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.client.ListMemories(ctx, &bedrockagentcorecontrol.ListMemoriesInput{
+			MaxResults: aws.Int32(listPageSize), NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListMemories: %w", err)
+		}
+		for _, m := range page.Memories {
+			if m.Status == types.MemoryStatusDeleting {
+				continue
+			}
+			id := aws.ToString(m.Id)
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{Type: ResTypeMemory, Name: id, ID: id},
+				arn:            aws.ToString(m.Arn),
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+func (c *realAWSClient) listEvaluatorCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.client.ListEvaluators(ctx, &bedrockagentcorecontrol.ListEvaluatorsInput{
+			MaxResults: aws.Int32(listPageSize), NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListEvaluators: %w", err)
+		}
+		for _, ev := range page.Evaluators {
+			// Built-in evaluators are shared by every account and never tagged.
+			if ev.Status == types.EvaluatorStatusDeleting || ev.EvaluatorType == types.EvaluatorTypeBuiltin {
+				continue
+			}
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{
+					Type: ResTypeEvaluator, Name: aws.ToString(ev.EvaluatorName), ID: aws.ToString(ev.EvaluatorId),
+				},
+				arn: aws.ToString(ev.EvaluatorArn),
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+func (c *realAWSClient) listOnlineEvalCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.client.ListOnlineEvaluationConfigs(ctx,
+			&bedrockagentcorecontrol.ListOnlineEvaluationConfigsInput{
+				MaxResults: aws.Int32(listPageSize), NextToken: nextToken,
+			})
+		if err != nil {
+			return nil, fmt.Errorf("ListOnlineEvaluationConfigs: %w", err)
+		}
+		for _, oc := range page.OnlineEvaluationConfigs {
+			if oc.Status == types.OnlineEvaluationConfigStatusDeleting {
+				continue
+			}
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{
+					Type: ResTypeOnlineEvalConfig, Name: aws.ToString(oc.OnlineEvaluationConfigName),
+					ID: aws.ToString(oc.OnlineEvaluationConfigId),
+				},
+				arn: aws.ToString(oc.OnlineEvaluationConfigArn),
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+func (c *realAWSClient) listInferenceProfileCandidates(ctx context.Context) ([]taggedCandidate, error) {
+	var out []taggedCandidate
+	var nextToken *string
+	for {
+		page, err := c.bedrockClient.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
+			MaxResults: aws.Int32(listPageSize), NextToken: nextToken,
+			TypeEquals: bedrockTypes.InferenceProfileTypeApplication,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListInferenceProfiles: %w", err)
+		}
+		for _, p := range page.InferenceProfileSummaries {
+			arn := aws.ToString(p.InferenceProfileArn)
+			out = append(out, taggedCandidate{
+				orphanResource: orphanResource{
+					Type: ResTypeInferenceProfile, Name: aws.ToString(p.InferenceProfileName), ID: arn,
+				},
+				arn: arn,
+			})
+		}
+		if page.NextToken == nil {
+			return out, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// remainingPolicyEngines returns the policy engines recorded on the state's
+// Cedar policies that still exist. Engines cannot be tagged, and gateway
+// association adds policies that can keep an engine from being deleted.
+func (c *realAWSClient) remainingPolicyEngines(
+	ctx context.Context, resources []ResourceState,
+) ([]orphanResource, error) {
+	var orphans []orphanResource
+	seen := make(map[string]bool)
+	for _, res := range resources {
+		engineID := res.Metadata["policy_engine_id"]
+		if res.Type != ResTypeCedarPolicy || engineID == "" || seen[engineID] {
+			continue
+		}
+		seen[engineID] = true
+		out, err := c.client.GetPolicyEngine(ctx, &bedrockagentcorecontrol.GetPolicyEngineInput{
+			PolicyEngineId: aws.String(engineID),
+		})
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return orphans, fmt.Errorf("GetPolicyEngine %q: %w", engineID, err)
+		}
+		if out.Status == types.PolicyEngineStatusDeleting {
+			continue
+		}
+		orphans = append(orphans, orphanResource{
+			Type: orphanTypePolicyEngine, Name: aws.ToString(out.Name), ID: engineID,
+		})
+	}
+	return orphans, nil
+}
+
+// remainingLogGroups returns the log groups AgentCore created for the
+// state's runtimes, plus the configured observability log group, that
+// still exist. Log groups kept by logs.retain_on_destroy are left out.
+func (c *realAWSClient) remainingLogGroups(
+	ctx context.Context, resources []ResourceState,
+) ([]orphanResource, error) {
+	var prefixes []string
+	retained := map[string]bool{}
+	for _, res := range resources {
+		if res.Type == ResTypeLogGroup && c.cfg.retainsLogGroups() {
+			retained[res.Metadata[metaLogGroupName]] = true
+		}
+		if res.Type != ResTypeAgentRuntime {
+			continue
+		}
+		if id := extractResourceID(res.ARN, "runtime"); id != "" {
+			prefixes = append(prefixes, runtimeLogGroupPrefix+id)
+		}
+	}
+
+	var orphans []orphanResource
+	for _, prefix := range prefixes {
+		names, err := c.logGroupsWithPrefix(ctx, prefix)
+		if err != nil {
+			return orphans, err
+		}
+		for _, name := range names {
+			if !retained[name] {
+				orphans = append(orphans, orphanResource{Type: ResTypeLogGroup, Name: name, ID: name})
+			}
+		}
+	}
+
+	group := resolveLogGroup(c.cfg)
+	if group == defaultTraceLogGroup {
+		return orphans, nil
+	}
+	names, err := c.logGroupsWithPrefix(ctx, group)
+	if err != nil {
+		return orphans, err
+	}
+	for _, name := range names {
+		if name == group {
+			orphans = append(orphans, orphanResource{Type: ResTypeLogGroup, Name: name, ID: name})
+		}
+	}
+	return orphans, nil
+}
+
+// logGroupsWithPrefix lists the names of log groups starting with prefix.
+func (c *realAWSClient) logGroupsWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	var nextToken *string
+	for {
+		page, err := c.logsClient.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+			LogGroupNamePrefix: aws.String(prefix), NextToken: nextToken,
+		})
+		if err != nil {
+			return names, fmt.Errorf("DescribeLogGroups %q: %w", prefix, err)
+		}
+		for _, g := range page.LogGroups {
+			names = append(names, aws.ToString(g.LogGroupName))
+		}
+		if page.NextToken == nil {
+			return names, nil
+		}
+		nextToken = page.NextToken
+	}
+}
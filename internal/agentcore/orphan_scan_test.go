@@ -0,0 +1,151 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// scanningDestroyer deletes nothing and reports a fixed orphan scan result.
+type scanningDestroyer struct {
+	recordingDestroyer
+	orphans    []orphanResource
+	err        error
+	scannedFor string
+}
+
+func (d *scanningDestroyer) ScanOrphans(
+	_ context.Context, packID string, _ []ResourceState,
+) ([]orphanResource, error) {
+	d.scannedFor = packID
+	return d.orphans, d.err
+}
+
+func destroyMessages(t *testing.T, d resourceDestroyer, deployConfig string, state *AdapterState) []string {
+	t.Helper()
+	p := &Provider{
+		destroyerFunc: func(_ context.Context, _ *Config) (resourceDestroyer, error) { return d, nil },
+	}
+	var msgs []string
+	err := p.Destroy(context.Background(), &deploy.DestroyRequest{
+		DeployConfig: deployConfig,
+		PriorState:   mustJSON(t, state),
+	}, func(e *deploy.DestroyEvent) error {
+		msgs = append(msgs, e.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	return msgs
+}
+
+func orphanReport(msgs []string) string {
+	for _, m := range msgs {
+		if strings.HasPrefix(m, "Orphan scan") {
+			return m
+		}
+	}
+	return ""
+}
+
+func TestDestroy_ReportsOrphans(t *testing.T) {
+	d := &scanningDestroyer{orphans: []orphanResource{
+		{Type: ResTypeLogGroup, Name: "/aws/bedrock-agentcore/runtimes/rt-1-DEFAULT",
+			ID: "/aws/bedrock-agentcore/runtimes/rt-1-DEFAULT"},
+		{Type: ResTypeToolGateway, Name: "mypack_gateway", ID: "gw-1"},
+	}}
+	state := &AdapterState{PackID: "mypack", Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "rt", ARN: "arn:rt"},
+	}}
+	msgs := destroyMessages(t, d, validDestroyConfig(), state)
+
+	report := orphanReport(msgs)
+	want := "Orphan scan: 2 resources for promptpack:pack-id=mypack remain; to remove them:\n" +
+		`  log_group "/aws/bedrock-agentcore/runtimes/rt-1-DEFAULT": aws logs delete-log-group ` +
+		"--log-group-name /aws/bedrock-agentcore/runtimes/rt-1-DEFAULT --region us-west-2\n" +
+		`  tool_gateway "mypack_gateway": aws bedrock-agentcore-control delete-gateway ` +
+		"--gateway-identifier gw-1 --region us-west-2"
+	if report != want {
+		t.Errorf("report =\n%s\nwant\n%s", report, want)
+	}
+	if msgs[len(msgs)-1] != "Destroy complete" {
+		t.Errorf("last message = %q, want the report before completion", msgs[len(msgs)-1])
+	}
+}
+
+func TestDestroy_OrphanScanClean(t *testing.T) {
+	d := &scanningDestroyer{}
+	state := &AdapterState{PackID: "mypack", Resources: []ResourceState{{Type: ResTypeMemory, Name: "mem"}}}
+	cfg := `{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",` +
+		`"tags":{"promptpack:pack-id":"custom-id"}}`
+	report := orphanReport(destroyMessages(t, d, cfg, state))
+
+	if d.scannedFor != "custom-id" {
+		t.Errorf("scanned for %q, want the pack ID tag override", d.scannedFor)
+	}
+	if report != "Orphan scan: no resources for promptpack:pack-id=custom-id remain" {
+		t.Errorf("report = %q", report)
+	}
+}
+
+func TestDestroy_OrphanScanErrorDoesNotFail(t *testing.T) {
+	d := &scanningDestroyer{
+		orphans: []orphanResource{{Type: orphanTypePolicyEngine, Name: "chat_policy_engine", ID: "pe-1"}},
+		err:     errors.New("ListGateways: access denied"),
+	}
+	state := &AdapterState{PackID: "mypack", Resources: []ResourceState{{Type: ResTypeMemory, Name: "mem"}}}
+	msgs := destroyMessages(t, d, validDestroyConfig(), state)
+
+	joined := strings.Join(msgs, "\n")
+	if !strings.Contains(joined, "Orphan scan incomplete: ListGateways: access denied") {
+		t.Errorf("scan error not reported:\n%s", joined)
+	}
+	if !strings.Contains(joined, "delete-policy-engine --policy-engine-id pe-1") {
+		t.Errorf("partial results not reported:\n%s", joined)
+	}
+}
+
+func TestDestroy_OrphanScanSkips(t *testing.T) {
+	state := &AdapterState{Resources: []ResourceState{{Type: ResTypeMemory, Name: "mem"}}}
+
+	d := &scanningDestroyer{}
+	report := orphanReport(destroyMessages(t, d, validDestroyConfig(), state))
+	if report != "Orphan scan skipped: prior state has no pack ID" {
+		t.Errorf("report = %q", report)
+	}
+
+	state.PackID = "mypack"
+	if report := orphanReport(destroyMessages(t, &recordingDestroyer{}, validDestroyConfig(), state)); report != "" {
+		t.Errorf("destroyer without a scanner reported %q", report)
+	}
+}
+
+func TestOrphanCleanupCommand(t *testing.T) {
+	tests := []struct {
+		orphan orphanResource
+		want   string
+	}{
+		{orphanResource{Type: ResTypeAgentRuntime, ID: "rt-1"},
+			"aws bedrock-agentcore-control delete-agent-runtime --agent-runtime-id rt-1"},
+		{orphanResource{Type: ResTypeMemory, ID: "mem-1"},
+			"aws bedrock-agentcore-control delete-memory --memory-id mem-1"},
+		{orphanResource{Type: ResTypeEvaluator, ID: "ev-1"},
+			"aws bedrock-agentcore-control delete-evaluator --evaluator-id ev-1"},
+		{orphanResource{Type: ResTypeOnlineEvalConfig, ID: "oc-1"},
+			"aws bedrock-agentcore-control delete-online-evaluation-config --online-evaluation-config-id oc-1"},
+		{orphanResource{Type: ResTypeInferenceProfile, ID: "arn:ip"},
+			"aws bedrock delete-inference-profile --inference-profile-identifier arn:ip"},
+		{orphanResource{Type: ResTypeIdentityProvider, ID: "google_identity"},
+			"aws bedrock-agentcore-control delete-oauth2-credential-provider --name google_identity"},
+		{orphanResource{Type: "unknown"}, "delete it in the AWS console"},
+	}
+	for _, tt := range tests {
+		if got := orphanCleanupCommand(tt.orphan, ""); got != tt.want {
+			t.Errorf("orphanCleanupCommand(%s) = %q, want %q", tt.orphan.Type, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// Output types accepted in a pack's "outputs" declaration. Each names a
+// value Apply reads from the resources it deployed.
+const (
+	// OutputRuntimeARN is an agent runtime's ARN.
+	OutputRuntimeARN = "runtime_arn"
+	// OutputInvocationARN is the ARN clients invoke an agent with: its
+	// runtime endpoint when runtime_endpoint is set, else its runtime.
+	OutputInvocationARN = "invocation_arn"
+	// OutputGatewayARN is the shared tool gateway's ARN.
+	OutputGatewayARN = "gateway_arn"
+	// OutputGatewayURL is the shared tool gateway's MCP URL.
+	OutputGatewayURL = "gateway_url"
+	// OutputMemoryARN is the deployment memory's ARN.
+	OutputMemoryARN = "memory_arn"
+	// OutputMemoryID is the deployment memory's ID.
+	OutputMemoryID = "memory_id"
+)
+
+// outputTypes lists the output types in documentation order.
+var outputTypes = []string{
+	OutputRuntimeARN, OutputInvocationARN, OutputGatewayARN, OutputGatewayURL, OutputMemoryARN, OutputMemoryID,
+}
+
+// outputNamePattern keeps declared output names apart from the built-in
+// "<agent>.<field>" outputs and usable as template variables.
+var outputNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// gatewayURLFormat is the MCP URL AgentCore serves a gateway on, from its
+// ID and region.
+const gatewayURLFormat = "https://%s.gateway.bedrock-agentcore.%s.%s/mcp"
+
+// OutputDecl declares one deployment output. Packs declare outputs in a
+// top-level "outputs" map keyed by output name, which PromptKit passes
+// through untouched.
+type OutputDecl struct {
+	Type string `json:"type"`
+	// Agent selects the runtime for runtime_arn and invocation_arn. It
+	// defaults to the entry agent of a multi-agent pack.
+	Agent       string `json:"agent,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// isRuntimeOutput reports whether the output is read from an agent runtime.
+func (d OutputDecl) isRuntimeOutput() bool {
+	return d.Type == OutputRuntimeARN || d.Type == OutputInvocationARN
+}
+
+// parsePackOutputs reads the "outputs" declaration from the raw pack JSON.
+func parsePackOutputs(packJSON string) (map[string]OutputDecl, error) {
+	var raw struct {
+		Outputs map[string]OutputDecl `json:"outputs"`
+	}
+	if err := json.Unmarshal([]byte(packJSON), &raw); err != nil {
+		return nil, fmt.Errorf("invalid outputs: %w", err)
+	}
+	return raw.Outputs, nil
+}
+
+// loadOutputs parses and validates the pack's declared outputs.
+func loadOutputs(packJSON string, pack *prompt.Pack, cfg *Config) (map[string]OutputDecl, error) {
+	decls, err := parsePackOutputs(packJSON)
+	if err != nil {
+		return nil, err
+	}
+	if errs := validateOutputs(decls, pack, cfg); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid outputs: %s", strings.Join(errs, "; "))
+	}
+	return decls, nil
+}
+
+// validateOutputs checks each declared output against the pack and config:
+// the type must be known, and the resource it reads must be deployed.
+func validateOutputs(decls map[string]OutputDecl, pack *prompt.Pack, cfg *Config) []string {
+	runtimes := agentRuntimeNames(pack)
+	var errs []string
+	for _, name := range sortedKeys(decls) {
+		d := decls[name]
+		if !outputNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Sprintf("output %q: name must match %s", name, outputNamePattern))
+		}
+		switch {
+		case !slices.Contains(outputTypes, d.Type):
+			errs = append(errs, fmt.Sprintf("output %q: type %q must be one of %s",
+				name, d.Type, strings.Join(outputTypes, ", ")))
+		case d.Agent != "" && !d.isRuntimeOutput():
+			errs = append(errs, fmt.Sprintf("output %q: agent only applies to %s and %s",
+				name, OutputRuntimeARN, OutputInvocationARN))
+		case d.Agent != "" && !slices.Contains(runtimes, d.Agent):
+			errs = append(errs, fmt.Sprintf("output %q: agent %q is not an agent in the pack", name, d.Agent))
+		case (d.Type == OutputGatewayARN || d.Type == OutputGatewayURL) && len(deployedTools(pack)) == 0:
+			errs = append(errs, fmt.Sprintf("output %q: %s needs a prompt that uses tools", name, d.Type))
+		case (d.Type == OutputMemoryARN || d.Type == OutputMemoryID) && !cfg.HasMemory():
+			errs = append(errs, fmt.Sprintf("output %q: %s needs memory_store", name, d.Type))
+		}
+	}
+	return errs
+}
+
+// outputAgent returns the runtime an output reads from.
+func outputAgent(d OutputDecl, pack *prompt.Pack) string {
+	if d.Agent != "" {
+		return d.Agent
+	}
+	if pack.Agents != nil && pack.Agents.Entry != "" {
+		return pack.Agents.Entry
+	}
+	return pack.ID
+}
+
+// resolveOutputs reads each declared output from the deployed resources.
+// Outputs whose resource failed are left out.
+func resolveOutputs(
+	decls map[string]OutputDecl, pack *prompt.Pack, resources []ResourceState,
+) map[string]string {
+	if len(decls) == 0 {
+		return nil
+	}
+	outputs := make(map[string]string, len(decls))
+	for name, d := range decls {
+		if v := resolveOutput(d, pack, resources); v != "" {
+			outputs[name] = v
+		}
+	}
+	return outputs
+}
+
+// resolveOutput returns one output's value, or "" when its resource was
+// not deployed.
+func resolveOutput(d OutputDecl, pack *prompt.Pack, resources []ResourceState) string {
+	switch d.Type {
+	case OutputRuntimeARN:
+		return deployedARN(resources, ResTypeAgentRuntime, outputAgent(d, pack))
+	case OutputInvocationARN:
+		agent := outputAgent(d, pack)
+		if arn := deployedARN(resources, ResTypeRuntimeEndpoint, agent); arn != "" {
+			return arn
+		}
+		return deployedARN(resources, ResTypeAgentRuntime, agent)
+	case OutputGatewayARN:
+		return findGatewayARN(resources)
+	case OutputGatewayURL:
+		return gatewayURL(findGatewayARN(resources))
+	case OutputMemoryARN:
+		return deployedARN(resources, ResTypeMemory, pack.ID+"_memory")
+	case OutputMemoryID:
+		return extractResourceID(deployedARN(resources, ResTypeMemory, pack.ID+"_memory"), "memory")
+	}
+	return ""
+}
+
+// deployedARN returns the ARN of the named resource unless it failed.
+func deployedARN(resources []ResourceState, resType, name string) string {
+	for _, r := range resources {
+		if r.Type == resType && r.Name == name && r.Status != ResStatusFailed {
+			return r.ARN
+		}
+	}
+	return ""
+}
+
+// gatewayURL derives a gateway's MCP URL from its ARN, in the DNS domain
+// of the ARN's partition, or returns "" when the ARN is not a gateway ARN.
+func gatewayURL(arn string) string {
+	a, ok := parseARN(arn)
+	id := extractResourceID(arn, "gateway")
+	if !ok || id == "" {
+		return ""
+	}
+	return fmt.Sprintf(gatewayURLFormat, id, a.Region, partitionByID(a.Partition).dnsSuffix)
+}
+
+// mergeOutputs adds declared outputs to the built-in ones.
+func mergeOutputs(builtin, declared map[string]string) map[string]string {
+	if len(declared) == 0 {
+		return builtin
+	}
+	if builtin == nil {
+		builtin = make(map[string]string, len(declared))
+	}
+	maps.Copy(builtin, declared)
+	return builtin
+}
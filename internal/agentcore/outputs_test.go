@@ -0,0 +1,147 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// packWithOutputs adds an "outputs" declaration to a pack's JSON.
+func packWithOutputs(t *testing.T, packJSON string, outputs map[string]OutputDecl) string {
+	t.Helper()
+	var pack map[string]any
+	if err := json.Unmarshal([]byte(packJSON), &pack); err != nil {
+		t.Fatal(err)
+	}
+	pack["outputs"] = outputs
+	return mustJSON(t, pack)
+}
+
+func TestValidateOutputs(t *testing.T) {
+	pack := &prompt.Pack{
+		ID:      "multi",
+		Prompts: map[string]*prompt.PackPrompt{"coordinator": {}, "worker": {}},
+		Agents: &prompt.AgentsConfig{
+			Entry:   "coordinator",
+			Members: map[string]*prompt.AgentDef{"coordinator": {}, "worker": {}},
+		},
+	}
+	tests := []struct {
+		name    string
+		decl    OutputDecl
+		outName string
+		cfg     *Config
+		wantErr string
+	}{
+		{name: "runtime for entry", decl: OutputDecl{Type: OutputRuntimeARN}},
+		{name: "invocation for agent", decl: OutputDecl{Type: OutputInvocationARN, Agent: "worker"}},
+		{
+			name: "memory with store",
+			decl: OutputDecl{Type: OutputMemoryID},
+			cfg:  &Config{Memory: MemoryConfig{Strategies: []string{"episodic"}}},
+		},
+		{name: "bad name", outName: "api.arn", decl: OutputDecl{Type: OutputRuntimeARN}, wantErr: "name must match"},
+		{name: "unknown type", decl: OutputDecl{Type: "endpoint"}, wantErr: `type "endpoint" must be one of`},
+		{name: "agent on gateway", decl: OutputDecl{Type: OutputGatewayARN, Agent: "worker"}, wantErr: "agent only applies"},
+		{name: "unknown agent", decl: OutputDecl{Type: OutputRuntimeARN, Agent: "ghost"}, wantErr: "not an agent"},
+		{name: "gateway without tools", decl: OutputDecl{Type: OutputGatewayURL}, wantErr: "needs a prompt that uses tools"},
+		{name: "memory without store", decl: OutputDecl{Type: OutputMemoryARN}, wantErr: "needs memory_store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.outName
+			if name == "" {
+				name = "api"
+			}
+			cfg := tt.cfg
+			if cfg == nil {
+				cfg = &Config{}
+			}
+			errs := validateOutputs(map[string]OutputDecl{name: tt.decl}, pack, cfg)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("errs = %v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveOutput(t *testing.T) {
+	const (
+		runtimeARN  = "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/rt-1"
+		endpointARN = runtimeARN + "/runtime-endpoint/live"
+		gatewayARN  = "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-abc"
+		memoryARN   = "arn:aws:bedrock-agentcore:us-west-2:123456789012:memory/mem-1"
+	)
+	pack := &prompt.Pack{ID: "mypack"}
+	resources := []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "mypack", ARN: runtimeARN, Status: ResStatusCreated},
+		{Type: ResTypeRuntimeEndpoint, Name: "mypack", ARN: endpointARN, Status: ResStatusCreated},
+		{Type: ResTypeToolGateway, Name: "mypack_gateway", ARN: gatewayARN, Status: ResStatusCreated},
+		{Type: ResTypeMemory, Name: "mypack_memory", ARN: memoryARN, Status: ResStatusCreated},
+	}
+	want := map[string]string{
+		OutputRuntimeARN:    runtimeARN,
+		OutputInvocationARN: endpointARN,
+		OutputGatewayARN:    gatewayARN,
+		OutputGatewayURL:    "https://gw-abc.gateway.bedrock-agentcore.us-west-2.amazonaws.com/mcp",
+		OutputMemoryARN:     memoryARN,
+		OutputMemoryID:      "mem-1",
+	}
+	for typ, w := range want {
+		if got := resolveOutput(OutputDecl{Type: typ}, pack, resources); got != w {
+			t.Errorf("%s = %q, want %q", typ, got, w)
+		}
+	}
+
+	resources[1].Status = ResStatusFailed
+	if got := resolveOutput(OutputDecl{Type: OutputInvocationARN}, pack, resources); got != runtimeARN {
+		t.Errorf("invocation_arn with failed endpoint = %q, want the runtime ARN", got)
+	}
+}
+
+func TestApply_ResolvesDeclaredOutputs(t *testing.T) {
+	pack := packWithOutputs(t, singleAgentPack(), map[string]OutputDecl{
+		"agent_arn": {Type: OutputInvocationARN, Description: "ARN clients invoke"},
+		"memory":    {Type: OutputMemoryID},
+	})
+	_, raw, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON:     pack,
+		DeployConfig: validConfigWithMemory(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	runtimeARN := deployedARN(state.Resources, ResTypeAgentRuntime, "mypack")
+	if runtimeARN == "" || state.Outputs["agent_arn"] != runtimeARN {
+		t.Errorf("agent_arn = %q, want runtime ARN %q", state.Outputs["agent_arn"], runtimeARN)
+	}
+	if state.Outputs["memory"] == "" {
+		t.Errorf("memory output missing: %v", state.Outputs)
+	}
+}
+
+func TestPlan_RejectsInvalidOutputs(t *testing.T) {
+	_, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     packWithOutputs(t, singleAgentPack(), map[string]OutputDecl{"gw": {Type: OutputGatewayURL}}),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid outputs") {
+		t.Errorf("err = %v, want invalid outputs", err)
+	}
+}
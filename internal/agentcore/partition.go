@@ -0,0 +1,247 @@
+package agentcore
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// AWS partitions the adapter deploys to.
+const (
+	PartitionAWS      = "aws"
+	PartitionGovCloud = "aws-us-gov"
+	PartitionChina    = "aws-cn"
+)
+
+// Services whose endpoints the endpoints config overrides.
+const (
+	EndpointBedrockAgentCoreControl = "bedrock_agentcore_control"
+	EndpointBedrockAgentCore        = "bedrock_agentcore"
+	EndpointBedrock                 = "bedrock"
+	EndpointBedrockRuntime          = "bedrock_runtime"
+	EndpointSTS                     = "sts"
+	EndpointLogs                    = "logs"
+	EndpointS3                      = "s3"
+	EndpointLambda                  = "lambda"
+	EndpointIAM                     = "iam"
+)
+
+// endpointServices lists the keys the endpoints config accepts.
+var endpointServices = []string{
+	EndpointBedrockAgentCoreControl, EndpointBedrockAgentCore, EndpointBedrock, EndpointBedrockRuntime,
+	EndpointSTS, EndpointLogs, EndpointS3, EndpointLambda, EndpointIAM,
+}
+
+// awsPartition describes an AWS partition: the regions in it, the DNS
+// suffix of its service endpoints, its global IAM endpoint, and the regions
+// that offer Bedrock AgentCore.
+type awsPartition struct {
+	id               string
+	dnsSuffix        string
+	regionRE         *regexp.Regexp
+	agentCoreRegions map[string]bool
+
+	// iamHost and iamFIPSHost are the hosts of the partition's IAM
+	// endpoint, which signs requests for iamRegion.
+	iamHost     string
+	iamFIPSHost string
+	iamRegion   string
+}
+
+// awsPartitions lists the partitions in the order their region patterns
+// are tried: the commercial pattern also matches GovCloud and China
+// region names, so it comes last.
+var awsPartitions = []awsPartition{
+	{
+		id:               PartitionGovCloud,
+		dnsSuffix:        "amazonaws.com",
+		regionRE:         regexp.MustCompile(`^us-gov-[a-z]+-\d+$`),
+		agentCoreRegions: map[string]bool{},
+		iamHost:          "iam.us-gov.amazonaws.com",
+		iamFIPSHost:      "iam.us-gov.amazonaws.com",
+		iamRegion:        "us-gov-west-1",
+	},
+	{
+		id:               PartitionChina,
+		dnsSuffix:        "amazonaws.com.cn",
+		regionRE:         regexp.MustCompile(`^cn-[a-z]+-\d+$`),
+		agentCoreRegions: map[string]bool{},
+		iamHost:          "iam.cn-north-1.amazonaws.com.cn",
+		iamRegion:        "cn-north-1",
+	},
+	{
+		id:        PartitionAWS,
+		dnsSuffix: "amazonaws.com",
+		regionRE:  regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d+$`),
+		agentCoreRegions: map[string]bool{
+			"us-east-1":      true,
+			"us-east-2":      true,
+			"us-west-2":      true,
+			"ap-south-1":     true,
+			"ap-southeast-1": true,
+			"ap-southeast-2": true,
+			"ap-northeast-1": true,
+			"eu-central-1":   true,
+			"eu-west-1":      true,
+		},
+		iamHost:     "iam.amazonaws.com",
+		iamFIPSHost: "iam-fips.amazonaws.com",
+		iamRegion:   "us-east-1",
+	},
+}
+
+// partitionForRegion returns the partition a region belongs to, or false
+// when the region name fits none.
+func partitionForRegion(region string) (awsPartition, bool) {
+	for _, p := range awsPartitions {
+		if p.regionRE.MatchString(region) {
+			return p, true
+		}
+	}
+	return awsPartition{}, false
+}
+
+// partitionByID returns the partition with the given ID, falling back to
+// the commercial partition for an unknown ID.
+func partitionByID(id string) awsPartition {
+	for _, p := range awsPartitions {
+		if p.id == id {
+			return p
+		}
+	}
+	return awsPartitions[len(awsPartitions)-1]
+}
+
+// regionPartition returns the ID of the partition a region belongs to,
+// or PartitionAWS when the region name fits none.
+func regionPartition(region string) string {
+	if p, ok := partitionForRegion(region); ok {
+		return p.id
+	}
+	return PartitionAWS
+}
+
+// arnParts holds the segments of an ARN:
+// arn:partition:service:region:account-id:resource.
+type arnParts struct {
+	Partition string
+	Service   string
+	Region    string
+	Account   string
+	Resource  string
+}
+
+// arnSegments is the number of colon-separated segments of an ARN; the
+// resource segment may itself contain colons.
+const arnSegments = 6
+
+// parseARN splits an ARN into its segments, or returns false when arn is
+// not an ARN.
+func parseARN(arn string) (arnParts, bool) {
+	parts := strings.SplitN(arn, ":", arnSegments)
+	if len(parts) != arnSegments || parts[0] != "arn" || parts[1] == "" {
+		return arnParts{}, false
+	}
+	return arnParts{
+		Partition: parts[1], Service: parts[2], Region: parts[3], Account: parts[4], Resource: parts[5],
+	}, true
+}
+
+// formatARN builds an ARN in the partition region belongs to.
+func formatARN(service, region, account, resource string) string {
+	return fmt.Sprintf("arn:%s:%s:%s:%s:%s", regionPartition(region), service, region, account, resource)
+}
+
+// validateRegionPartition checks that ARNs given alongside region are in
+// region's partition: IAM rejects roles from another partition.
+func validateRegionPartition(region string, arns map[string]string) []string {
+	p, ok := partitionForRegion(region)
+	if !ok {
+		return nil
+	}
+	var errs []string
+	for _, field := range sortedKeys(arns) {
+		a, ok := parseARN(arns[field])
+		if ok && a.Partition != p.id {
+			errs = append(errs, fmt.Sprintf("%s is in partition %q, but region %s is in %q",
+				field, a.Partition, region, p.id))
+		}
+	}
+	return errs
+}
+
+// loopbackHosts are the endpoint hosts allowed over plain http, for AWS
+// emulators running beside the adapter.
+var loopbackHosts = map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true}
+
+// validateEndpoints checks that every endpoint override names a known
+// service and is an https URL, or an http URL of a loopback host.
+func validateEndpoints(endpoints map[string]string) []string {
+	var errs []string
+	for _, service := range sortedKeys(endpoints) {
+		if !slices.Contains(endpointServices, service) {
+			errs = append(errs, fmt.Sprintf("endpoints.%s is not a known service (one of %s)",
+				service, strings.Join(endpointServices, ", ")))
+			continue
+		}
+		if !validEndpointURL(endpoints[service]) {
+			errs = append(errs, fmt.Sprintf("endpoints.%s %q must be an https URL, or http on localhost",
+				service, endpoints[service]))
+		}
+	}
+	return errs
+}
+
+// validEndpointURL reports whether raw is an https URL or an http URL of a
+// loopback host.
+func validEndpointURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "https" || (u.Scheme == "http" && loopbackHosts[u.Hostname()])
+}
+
+// validateFIPSEndpoints checks that the region's partition offers FIPS
+// endpoints: the China partition has none.
+func validateFIPSEndpoints(fips bool, region string) []string {
+	if fips && regionPartition(region) == PartitionChina {
+		return []string{fmt.Sprintf("fips_endpoints is not available in region %s (partition %s)",
+			region, PartitionChina)}
+	}
+	return nil
+}
+
+// baseEndpoint returns the endpoint override for service, or nil to let
+// the AWS SDK resolve the endpoint for the region's partition.
+func (c *Config) baseEndpoint(service string) *string {
+	if u := c.Endpoints[service]; u != "" {
+		return aws.String(u)
+	}
+	return nil
+}
+
+// iamEndpoint returns the IAM endpoint URL of the region's partition, or
+// the endpoints override, and the region IAM requests are signed for.
+func (c *Config) iamEndpoint() (endpoint, signingRegion string) {
+	p := partitionByID(regionPartition(c.Region))
+	if u := c.Endpoints[EndpointIAM]; u != "" {
+		return u, p.iamRegion
+	}
+	host := p.iamHost
+	if c.FIPSEndpoints && p.iamFIPSHost != "" {
+		host = p.iamFIPSHost
+	}
+	return "https://" + host, p.iamRegion
+}
+
+// agentCoreAvailable reports whether Bedrock AgentCore is offered in
+// region.
+func agentCoreAvailable(region string) bool {
+	p, ok := partitionForRegion(region)
+	return ok && p.agentCoreRegions[region]
+}
@@ -0,0 +1,191 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func TestPartitionForRegion(t *testing.T) {
+	tests := map[string]string{
+		"us-west-2":      PartitionAWS,
+		"eu-central-1":   PartitionAWS,
+		"us-gov-west-1":  PartitionGovCloud,
+		"us-gov-east-1":  PartitionGovCloud,
+		"cn-north-1":     PartitionChina,
+		"cn-northwest-1": PartitionChina,
+		"us_west_2":      "",
+		"":               "",
+	}
+	for region, want := range tests {
+		p, ok := partitionForRegion(region)
+		if ok != (want != "") || p.id != want {
+			t.Errorf("partitionForRegion(%q) = %q, %t, want %q", region, p.id, ok, want)
+		}
+	}
+}
+
+func TestParseARN(t *testing.T) {
+	a, ok := parseARN("arn:aws-cn:logs:cn-north-1:123456789012:log-group:/aws/agent:*")
+	if !ok {
+		t.Fatal("parseARN rejected a valid ARN")
+	}
+	want := arnParts{Partition: PartitionChina, Service: "logs", Region: "cn-north-1",
+		Account: "123456789012", Resource: "log-group:/aws/agent:*"}
+	if a != want {
+		t.Errorf("parseARN = %+v, want %+v", a, want)
+	}
+	for _, bad := range []string{"", "runtime/abc", "arn::iam::123:role/x", "arn:aws:iam:123"} {
+		if _, ok := parseARN(bad); ok {
+			t.Errorf("parseARN(%q) accepted a malformed ARN", bad)
+		}
+	}
+}
+
+func TestFormatARN(t *testing.T) {
+	tests := map[string]string{
+		"us-west-2":     "arn:aws:logs:us-west-2:123456789012:log-group:x",
+		"us-gov-west-1": "arn:aws-us-gov:logs:us-gov-west-1:123456789012:log-group:x",
+		"cn-north-1":    "arn:aws-cn:logs:cn-north-1:123456789012:log-group:x",
+	}
+	for region, want := range tests {
+		if got := formatARN("logs", region, "123456789012", "log-group:x"); got != want {
+			t.Errorf("formatARN(%s) = %q, want %q", region, got, want)
+		}
+	}
+}
+
+func TestGatewayURL_Partitions(t *testing.T) {
+	tests := []struct{ arn, want string }{
+		{"arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-1",
+			"https://gw-1.gateway.bedrock-agentcore.us-west-2.amazonaws.com/mcp"},
+		{"arn:aws-us-gov:bedrock-agentcore:us-gov-west-1:123456789012:gateway/gw-1",
+			"https://gw-1.gateway.bedrock-agentcore.us-gov-west-1.amazonaws.com/mcp"},
+		{"arn:aws-cn:bedrock-agentcore:cn-north-1:123456789012:gateway/gw-1",
+			"https://gw-1.gateway.bedrock-agentcore.cn-north-1.amazonaws.com.cn/mcp"},
+		{"arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/rt-1", ""},
+	}
+	for _, tt := range tests {
+		if got := gatewayURL(tt.arn); got != tt.want {
+			t.Errorf("gatewayURL(%s) = %q, want %q", tt.arn, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_Partitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		region string
+		role   string
+		want   string
+	}{
+		{"govcloud", "us-gov-west-1", "arn:aws-us-gov:iam::123456789012:role/agent", ""},
+		{"china", "cn-north-1", "arn:aws-cn:iam::123456789012:role/agent", ""},
+		{"role in another partition", "cn-north-1", "arn:aws:iam::123456789012:role/agent",
+			`runtime_role_arn is in partition "aws", but region cn-north-1 is in "aws-cn"`},
+		{"unknown partition", "us-west-2", "arn:aws-iso:iam::123456789012:role/agent", "not a valid IAM role ARN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Region: tt.region, RuntimeRoleARN: tt.role, RuntimeBinaryPath: "/bin/runtime"}
+			errs := strings.Join(cfg.validate(), "; ")
+			if tt.want == "" && errs != "" {
+				t.Errorf("validate = %s, want no errors", errs)
+			}
+			if tt.want != "" && !strings.Contains(errs, tt.want) {
+				t.Errorf("validate = %s, want one containing %q", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	errs := validateEndpoints(map[string]string{
+		EndpointBedrockAgentCoreControl: "https://bedrock-agentcore-control.us-gov-west-1.amazonaws.com",
+		EndpointS3:                      "http://s3.example.com",
+		EndpointLogs:                    "http://localhost:4566",
+		"dynamodb":                      "https://dynamodb.us-west-2.amazonaws.com",
+	})
+	got := strings.Join(errs, "; ")
+	if len(errs) != 2 || !strings.Contains(got, "endpoints.dynamodb is not a known service") ||
+		!strings.Contains(got, "endpoints.s3") {
+		t.Errorf("validateEndpoints = %v, want dynamodb and s3 rejected", errs)
+	}
+}
+
+func TestValidateFIPSEndpoints(t *testing.T) {
+	if errs := validateFIPSEndpoints(true, "cn-north-1"); len(errs) != 1 {
+		t.Errorf("validateFIPSEndpoints(cn-north-1) = %v, want one error", errs)
+	}
+	for _, region := range []string{"us-west-2", "us-gov-west-1"} {
+		if errs := validateFIPSEndpoints(true, region); len(errs) > 0 {
+			t.Errorf("validateFIPSEndpoints(%s) = %v, want none", region, errs)
+		}
+	}
+	if errs := validateFIPSEndpoints(false, "cn-north-1"); len(errs) > 0 {
+		t.Errorf("validateFIPSEndpoints without fips = %v, want none", errs)
+	}
+}
+
+func TestBaseEndpoint(t *testing.T) {
+	cfg := &Config{Endpoints: map[string]string{EndpointS3: "https://s3.example.com"}}
+	if got := cfg.baseEndpoint(EndpointS3); got == nil || *got != "https://s3.example.com" {
+		t.Errorf("baseEndpoint(s3) = %v, want the override", got)
+	}
+	if got := cfg.baseEndpoint(EndpointLogs); got != nil {
+		t.Errorf("baseEndpoint(logs) = %q, want nil", *got)
+	}
+	if got := (&Config{}).baseEndpoint(EndpointS3); got != nil {
+		t.Errorf("baseEndpoint without endpoints = %q, want nil", *got)
+	}
+}
+
+func TestDiagnoseRegion_Partitions(t *testing.T) {
+	gov := &Config{Region: "us-gov-west-1"}
+	warnings := diagnoseRegion(gov)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Hint, "endpoints."+EndpointBedrockAgentCoreControl) {
+		t.Errorf("diagnoseRegion(us-gov-west-1) = %v, want a warning pointing at the endpoint override", warnings)
+	}
+	gov.Endpoints = map[string]string{
+		EndpointBedrockAgentCoreControl: "https://bedrock-agentcore-control.us-gov-west-1.amazonaws.com",
+	}
+	if warnings := diagnoseRegion(gov); len(warnings) > 0 {
+		t.Errorf("diagnoseRegion with an endpoint override = %v, want none", warnings)
+	}
+}
+
+func TestAWSClientKey_Endpoints(t *testing.T) {
+	base := Config{Region: "us-west-2"}
+	local := Config{Region: "us-west-2", Endpoints: map[string]string{EndpointS3: "https://s3.example.com"}}
+	if base.awsClientKey() == local.awsClientKey() {
+		t.Error("configs with different endpoints share a client key")
+	}
+	fips := Config{Region: "us-west-2", FIPSEndpoints: true}
+	dualStack := Config{Region: "us-west-2", DualStackEndpoints: true}
+	if base.awsClientKey() == fips.awsClientKey() || fips.awsClientKey() == dualStack.awsClientKey() {
+		t.Error("configs with different endpoint variants share a client key")
+	}
+}
+
+func TestApply_GovCloud(t *testing.T) {
+	deployConfig := fmt.Sprintf(`{"region":"us-gov-west-1",`+
+		`"runtime_role_arn":"arn:aws-us-gov:iam::123456789012:role/test","runtime_binary_path":%q}`,
+		testBinaryPath(t))
+	_, stateJSON, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: deployConfig, ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	rt, ok := findResourceOfType(&state, ResTypeAgentRuntime)
+	if !ok || !strings.HasPrefix(rt.ARN, "arn:aws-us-gov:bedrock-agentcore:us-gov-west-1:") {
+		t.Errorf("runtime = %+v, want a GovCloud ARN", rt)
+	}
+}
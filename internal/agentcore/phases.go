@@ -0,0 +1,120 @@
+package agentcore
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// PhasesConfig limits Apply to some of its phases, named by resource type,
+// so an operator can re-run one phase or leave one alone. A phase Apply
+// skips keeps the prior state's resources of its type untouched.
+type PhasesConfig struct {
+	// Include runs only the listed phases.
+	Include []string `json:"include,omitempty"`
+	// Exclude runs every phase but the listed ones.
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// validatePhases checks that the phases block names registered resource
+// types and sets include or exclude, not both.
+func validatePhases(c *PhasesConfig) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	if len(c.Include) > 0 && len(c.Exclude) > 0 {
+		errs = append(errs, "phases: set include or exclude, not both")
+	}
+	for field, names := range map[string][]string{"include": c.Include, "exclude": c.Exclude} {
+		for i, name := range names {
+			if _, ok := lookupResourceType(name); !ok {
+				errs = append(errs, fmt.Sprintf("phases.%s[%d] %q is not a resource type (one of %s)",
+					field, i, name, strings.Join(typeNames(applyOrder), ", ")))
+			}
+		}
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// runsPhase reports whether Apply runs the phase of resType.
+func (c *Config) runsPhase(resType string) bool {
+	if c.Phases == nil {
+		return true
+	}
+	if len(c.Phases.Include) > 0 {
+		return slices.Contains(c.Phases.Include, resType)
+	}
+	return !slices.Contains(c.Phases.Exclude, resType)
+}
+
+// skipApplyPhase carries the prior state's resources of t into resources
+// unchanged, in place of running its phase, and restores the config that
+// later phases read from them. The error is non-nil when the progress
+// callback aborted Apply.
+func skipApplyPhase(ac *applyContext, t resourceType, resources []ResourceState) ([]ResourceState, error) {
+	var carried []ResourceState
+	for _, key := range sortedKeys(ac.priorMap) {
+		if r := ac.priorMap[key]; r.Type == t.name {
+			carried = append(carried, r)
+			ac.carried[key] = true
+		}
+	}
+	msg := fmt.Sprintf("Skipping %s phase; keeping %d prior resources", t.name, len(carried))
+	if err := ac.reporter.Progress(msg, progressNoPercent); err != nil {
+		return resources, err
+	}
+	if t.restore != nil {
+		t.restore(ac, carried)
+	}
+	return append(resources, carried...), nil
+}
+
+// restoreMemoryID points the runtimes at a carried memory.
+func restoreMemoryID(ac *applyContext, carried []ResourceState) {
+	for _, r := range carried {
+		if r.ARN != "" {
+			ac.cfg.RuntimeEnvVars[EnvMemoryID] = r.ARN
+		}
+	}
+}
+
+// restoreInferenceProfiles routes the runtime and evaluators through the
+// carried application inference profiles.
+func restoreInferenceProfiles(ac *applyContext, carried []ResourceState) {
+	if ac.cfg.InferenceProfiles == nil {
+		return
+	}
+	arns := make(map[string]string, len(carried))
+	for _, r := range carried {
+		arns[r.Name] = r.ARN
+	}
+	resolveInferenceProfiles(ac.pack, ac.cfg, arns)
+}
+
+// restoreIdentityProviderARNs records carried identity providers for the
+// gateway targets that use them.
+func restoreIdentityProviderARNs(ac *applyContext, carried []ResourceState) {
+	ac.cfg.IdentityProviderARNs = make(map[string]string)
+	for _, r := range carried {
+		if r.ARN != "" {
+			ac.cfg.IdentityProviderARNs[strings.TrimSuffix(r.Name, identityProviderSuffix)] = r.ARN
+		}
+	}
+}
+
+// restoreGatewayARN records the gateways of tool_gateway resources for the
+// Cedar policies scoped to them and the runtimes that use them.
+func restoreGatewayARN(ac *applyContext, resources []ResourceState) {
+	arns := gatewayARNsByAgent(resources)
+	ac.cfg.GatewayARN = arns[""]
+	delete(arns, "")
+	ac.cfg.AgentGatewayARNs = arns
+}
+
+// restorePolicyEngineARNs points the runtimes at carried policy engines.
+func restorePolicyEngineARNs(ac *applyContext, carried []ResourceState) {
+	injectPolicyEngineARNs(ac.cfg, carried)
+}
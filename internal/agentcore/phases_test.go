@@ -0,0 +1,121 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// evalsOnlyClient fails every runtime and gateway call, so an Apply that
+// runs only the eval phases succeeds with it.
+type evalsOnlyClient struct {
+	simulatedAWSClient
+}
+
+func (c *evalsOnlyClient) CreateRuntime(context.Context, string, *Config) (string, error) {
+	return "", errors.New("runtime phase ran")
+}
+
+func (c *evalsOnlyClient) UpdateRuntime(context.Context, string, string, *Config) (string, error) {
+	return "", errors.New("runtime phase ran")
+}
+
+func (c *evalsOnlyClient) CreateGatewayTool(context.Context, string, *Config) (string, error) {
+	return "", errors.New("tool gateway phase ran")
+}
+
+func (c *evalsOnlyClient) CreateA2AWiring(context.Context, string, *Config) (string, error) {
+	return "", errors.New("a2a phase ran")
+}
+
+func TestValidatePhases(t *testing.T) {
+	if errs := validatePhases(&PhasesConfig{Exclude: []string{ResTypeCedarPolicy}}); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	errs := validatePhases(&PhasesConfig{Include: []string{ResTypeEvaluator, "evals"}, Exclude: []string{ResTypeMemory}})
+	if len(errs) != 2 || !strings.Contains(errs[0], `phases.include[1] "evals" is not a resource type`) ||
+		errs[1] != "phases: set include or exclude, not both" {
+		t.Errorf("errs = %v", errs)
+	}
+}
+
+func TestConfig_RunsPhase(t *testing.T) {
+	cfg := &Config{}
+	if !cfg.runsPhase(ResTypeCedarPolicy) {
+		t.Error("no phases config should run every phase")
+	}
+	cfg.Phases = &PhasesConfig{Exclude: []string{ResTypeCedarPolicy}}
+	if cfg.runsPhase(ResTypeCedarPolicy) || !cfg.runsPhase(ResTypeAgentRuntime) {
+		t.Error("exclude should skip only the listed phase")
+	}
+	cfg.Phases = &PhasesConfig{Include: []string{ResTypeEvaluator}}
+	if !cfg.runsPhase(ResTypeEvaluator) || cfg.runsPhase(ResTypeAgentRuntime) {
+		t.Error("include should run only the listed phase")
+	}
+}
+
+func TestApply_PhasesIncludeCarriesPriorState(t *testing.T) {
+	_, priorJSON, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON: multiAgentPackWithEvals(), DeployConfig: validConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("first apply: %v", err)
+	}
+	var prior AdapterState
+	if err := json.Unmarshal([]byte(priorJSON), &prior); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(context.Context, *Config) (awsClient, error) {
+		return &evalsOnlyClient{simulatedAWSClient: *newSimulatedAWSClient("us-west-2")}, nil
+	}
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"phases":{"include":["evaluator","online_eval_config"]}}`
+	events, stateJSON, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON: multiAgentPackWithEvals(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON,
+		PriorState: priorJSON,
+	})
+	if err != nil {
+		t.Fatalf("phased apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Resources) != len(prior.Resources) {
+		t.Fatalf("resources = %d, want the prior %d", len(state.Resources), len(prior.Resources))
+	}
+
+	priorByKey := make(map[string]ResourceState)
+	for _, r := range prior.Resources {
+		priorByKey[resourceKey(r.Type, r.Name)] = r
+	}
+	actions := make(map[string]deploy.Action)
+	for _, c := range state.Manifest.Changes {
+		actions[resourceKey(c.Type, c.Name)] = c.Action
+	}
+	for _, r := range state.Resources {
+		key := resourceKey(r.Type, r.Name)
+		if r.Type == ResTypeEvaluator || r.Type == ResTypeOnlineEvalConfig {
+			continue
+		}
+		if p := priorByKey[key]; r.ARN != p.ARN || r.Status != p.Status {
+			t.Errorf("%s changed: %+v, prior %+v", key, r, p)
+		}
+		if actions[key] != deploy.ActionNoChange {
+			t.Errorf("%s manifest action = %q, want no_change", key, actions[key])
+		}
+	}
+
+	var skipped bool
+	for _, ev := range events {
+		skipped = skipped || strings.HasPrefix(ev.Message, "Skipping agent_runtime phase; keeping 2 prior resources")
+	}
+	if !skipped {
+		t.Error("no progress event reports the skipped runtime phase")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/AltairaLabs/PromptKit/runtime/deploy"
 	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
@@ -12,15 +13,15 @@ import (
 )
 
 // Plan generates a deployment plan for the given pack and config.
-func (p *Provider) Plan(_ context.Context, req *deploy.PlanRequest) (*deploy.PlanResponse, error) {
+func (p *Provider) Plan(ctx context.Context, req *deploy.PlanRequest) (*deploy.PlanResponse, error) {
 	// 1. Parse the pack.
-	pack, err := adaptersdk.ParsePack([]byte(req.PackJSON))
+	pack, err := parsePack(req.PackJSON)
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: failed to parse pack: %w", err)
 	}
 
 	// 2. Parse the deploy config.
-	cfg, err := parseConfig(req.DeployConfig)
+	cfg, err := p.loadConfig(req.DeployConfig, arenaConfigVars(req.ArenaConfig))
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: invalid deploy config: %w", err)
 	}
@@ -43,20 +44,74 @@ func (p *Provider) Plan(_ context.Context, req *deploy.PlanRequest) (*deploy.Pla
 			return nil, fmt.Errorf("agentcore: failed to parse prior state: %w", err)
 		}
 	}
+	if err := checkStateWorkspace(prior, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
 
 	// 5. Validate derived resource names before generating the plan.
 	if nameErrs := validateResourceNames(pack, cfg); len(nameErrs) > 0 {
 		return nil, fmt.Errorf("agentcore: invalid resource names: %s", formatNameErrors(nameErrs))
 	}
+	if collisions := findNameCollisions(pack, cfg); len(collisions) > 0 {
+		return nil, fmt.Errorf("agentcore: resource name collisions: %s", formatNameErrors(collisions))
+	}
+	if evalErrs := validateEvalTemplates(pack); len(evalErrs) > 0 {
+		return nil, fmt.Errorf("agentcore: invalid eval params: %s", strings.Join(evalErrs, "; "))
+	}
+	if nsErrs := validateMemoryNamespaceAgents(pack, cfg); len(nsErrs) > 0 {
+		return nil, fmt.Errorf("agentcore: invalid memory_namespaces: %s", strings.Join(nsErrs, "; "))
+	}
+	if _, err := loadOutputs(req.PackJSON, pack, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
+	if authErrs := checkJWTAuthorizer(ctx, p.oidcFetchFunc, cfg); len(authErrs) > 0 {
+		return nil, fmt.Errorf("agentcore: invalid a2a_auth: %s", strings.Join(authErrs, "; "))
+	}
+	if err := filterAgents(pack, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
 
 	// 6. Generate desired resources.
 	desired := generateDesiredResources(pack, cfg)
 
-	// 7. Diff against prior state.
-	changes := diffResources(desired, prior)
+	// 7. Diff against prior state, renaming the resources of a previous
+	// pack ID first.
+	var renames map[string]string
+	if prior != nil {
+		prior.Resources, renames = renamePriorResources(prior.Resources, desired, pack.ID, cfg)
+	}
+	changes := diffResources(desired, prior, cfg)
+	annotateRenames(changes, renames)
 
-	// 8. Build summary.
+	// 8. Build summary, flagging models Bedrock does not offer in the region
+	// and gateway interceptors that cannot be reached.
 	summary := buildSummary(changes)
+	for _, w := range checkModelAvailability(ctx, p.modelCatalogFunc, pack, cfg) {
+		summary += "\nWarning: " + w
+	}
+	for _, w := range checkInterceptorReachability(ctx, p.lambdaCheckFunc, pack, cfg) {
+		summary += "\nWarning: " + w
+	}
+	sampling := collectEvalSampling(pack, cfg)
+	if w := evalSamplingWarning(sampling); w != "" {
+		summary += "\nWarning: " + w
+	}
+	if w := judgeSamplingWarning(sampling, cfg); w != "" {
+		summary += "\nWarning: " + w
+	}
+	if unused := unusedTools(pack); len(unused) > 0 {
+		summary += "\nWarning: no deployed prompt uses tools " + strings.Join(unused, ", ") + "; they get no gateway target"
+	}
+	if w := evalAlertWarning(cfg, changes); w != "" {
+		summary += "\nWarning: " + w
+	}
+	if cfg.Workspace != "" {
+		summary += fmt.Sprintf("\nWorkspace %s: AWS resource names end in %q", cfg.Workspace, "_"+cfg.Workspace)
+	}
+	if len(renames) > 0 {
+		summary += fmt.Sprintf("\nRenaming %d resources from pack %s to %s; their AWS resources keep their names",
+			len(renames), cfg.PreviousPackID, pack.ID)
+	}
 
 	return &deploy.PlanResponse{
 		Changes: changes,
@@ -69,26 +124,35 @@ func resourceKey(typ, name string) string {
 	return typ + "/" + name
 }
 
-// generateDesiredResources builds the list of desired resources from the pack.
-// For multi-agent packs it uses adaptersdk.GenerateAgentResourcePlan as a
-// starting point and adds tool_gateway (for pack-level tools) and
-// evaluator (for evals). For single-agent packs it generates a
-// single agent_runtime resource.
+// generateDesiredResources builds the list of desired resources from the
+// pack, asking each registered resource type for its changes in apply
+// order.
 func generateDesiredResources(pack *prompt.Pack, cfg *Config) []deploy.ResourceChange {
 	var desired []deploy.ResourceChange
+	for _, t := range applyOrder {
+		desired = append(desired, t.plan(pack, cfg, desired)...)
+	}
+	return desired
+}
 
-	// Memory resource (before tools/runtimes).
-	if cfg.HasMemory() {
-		memName := pack.ID + "_memory"
-		desired = append(desired, deploy.ResourceChange{
-			Type:   ResTypeMemory,
-			Name:   memName,
-			Action: deploy.ActionCreate,
-			Detail: fmt.Sprintf("Create memory store (%s) for %s", cfg.MemoryStrategiesCSV(), pack.ID),
-		})
+// generateMemoryResources returns the memory resource change when memory is
+// configured.
+func generateMemoryResources(pack *prompt.Pack, cfg *Config) []deploy.ResourceChange {
+	if !cfg.HasMemory() {
+		return nil
 	}
+	return []deploy.ResourceChange{{
+		Type:   ResTypeMemory,
+		Name:   pack.ID + "_memory",
+		Action: deploy.ActionCreate,
+		Detail: fmt.Sprintf("Create memory store (%s) for %s", cfg.MemoryStrategiesCSV(), pack.ID),
+	}}
+}
 
-	// Cedar policy resources (per prompt with validators or tool_policy).
+// generateCedarPolicyResources returns one cedar_policy change per prompt
+// with validators or tool_policy.
+func generateCedarPolicyResources(pack *prompt.Pack) []deploy.ResourceChange {
+	var desired []deploy.ResourceChange
 	for _, name := range policyResourceNames(pack) {
 		desired = append(desired, deploy.ResourceChange{
 			Type:   ResTypeCedarPolicy,
@@ -97,63 +161,48 @@ func generateDesiredResources(pack *prompt.Pack, cfg *Config) []deploy.ResourceC
 			Detail: fmt.Sprintf("Create Cedar policy for prompt %s", name),
 		})
 	}
-
-	desired = append(desired, generateAgentResources(pack)...)
-	desired = append(desired, generateEvalResources(pack)...)
-	desired = append(desired, generateOnlineEvalConfigResources(pack)...)
-
 	return desired
 }
 
-// generateAgentResources returns agent_runtime and tool_gateway resource changes
-// for the pack. Tool gateways are created for any pack that defines tools.
+// generateAgentResources returns the agent_runtime changes for the pack,
+// plus the a2a_endpoint changes of a multi-agent pack.
 func generateAgentResources(pack *prompt.Pack) []deploy.ResourceChange {
 	if adaptersdk.IsMultiAgent(pack) {
-		return generateMultiAgentResources(pack)
+		return adaptersdk.GenerateAgentResourcePlan(pack)
 	}
 
 	name := pack.ID
 	if name == "" {
 		name = defaultPackName
 	}
-	desired := []deploy.ResourceChange{{
+	return []deploy.ResourceChange{{
 		Type:   ResTypeAgentRuntime,
 		Name:   name,
 		Action: deploy.ActionCreate,
 		Detail: fmt.Sprintf("Create AgentCore runtime for %s", name),
 	}}
-
-	if len(pack.Tools) > 0 {
-		toolNames := sortedKeys(pack.Tools)
-		for _, tn := range toolNames {
-			desired = append(desired, deploy.ResourceChange{
-				Type:   ResTypeToolGateway,
-				Name:   tn + toolGatewaySuffix,
-				Action: deploy.ActionCreate,
-				Detail: fmt.Sprintf("Create tool gateway for %s", tn),
-			})
-		}
-	}
-
-	return desired
 }
 
-// generateMultiAgentResources returns SDK-generated resources plus tool_gateways.
-func generateMultiAgentResources(pack *prompt.Pack) []deploy.ResourceChange {
-	desired := adaptersdk.GenerateAgentResourcePlan(pack)
-
-	if len(pack.Tools) > 0 {
-		toolNames := sortedKeys(pack.Tools)
-		for _, name := range toolNames {
-			desired = append(desired, deploy.ResourceChange{
-				Type:   ResTypeToolGateway,
-				Name:   name + toolGatewaySuffix,
-				Action: deploy.ActionCreate,
-				Detail: fmt.Sprintf("Create tool gateway for %s", name),
-			})
+// generateToolGatewayResources returns one tool_gateway change per pack
+// tool. Tool gateways are created for any pack that defines tools.
+func generateToolGatewayResources(pack *prompt.Pack, cfg *Config) []deploy.ResourceChange {
+	var desired []deploy.ResourceChange
+	for _, name := range toolGatewayNames(pack, cfg) {
+		detail := fmt.Sprintf("Create tool gateway for %s", name)
+		agent, tool := splitGatewayTarget(name)
+		if agent != "" {
+			detail = fmt.Sprintf("Create tool gateway for %s on agent %s's gateway", tool, agent)
+		}
+		if target := gatewayTargetName(tool); target != tool {
+			detail += fmt.Sprintf(" as target %q", target)
 		}
+		desired = append(desired, deploy.ResourceChange{
+			Type:   ResTypeToolGateway,
+			Name:   name + toolGatewaySuffix,
+			Action: deploy.ActionCreate,
+			Detail: detail,
+		})
 	}
-
 	return desired
 }
 
@@ -175,7 +224,7 @@ func generateEvalResources(pack *prompt.Pack) []deploy.ResourceChange {
 		}
 		resources = append(resources, deploy.ResourceChange{
 			Type:   ResTypeEvaluator,
-			Name:   pack.Evals[i].ID + "_eval",
+			Name:   pack.Evals[i].ID + evalResourceSuffix,
 			Action: deploy.ActionCreate,
 			Detail: fmt.Sprintf("Create evaluator for %s", pack.Evals[i].ID),
 		})
@@ -186,14 +235,15 @@ func generateEvalResources(pack *prompt.Pack) []deploy.ResourceChange {
 // generateOnlineEvalConfigResources returns a single online_eval_config resource
 // if the pack has any llm_as_judge or builtin evals. The config wires evaluators
 // to agent runtime traces via CloudWatch.
-func generateOnlineEvalConfigResources(pack *prompt.Pack) []deploy.ResourceChange {
+func generateOnlineEvalConfigResources(pack *prompt.Pack, cfg *Config) []deploy.ResourceChange {
 	for i := range pack.Evals {
 		if pack.Evals[i].Type == evalTypeLLMAsJudge || pack.Evals[i].Type == evalTypeBuiltin {
 			return []deploy.ResourceChange{{
 				Type:   ResTypeOnlineEvalConfig,
 				Name:   pack.ID + "_online_eval",
 				Action: deploy.ActionCreate,
-				Detail: fmt.Sprintf("Create online evaluation config for %s", pack.ID),
+				Detail: fmt.Sprintf("Create online evaluation config for %s (sampling %s%%)", pack.ID,
+					formatPercentage(effectiveSamplingPercentage(collectEvalSampling(pack, cfg)))),
 			}}
 		}
 	}
@@ -201,8 +251,10 @@ func generateOnlineEvalConfigResources(pack *prompt.Pack) []deploy.ResourceChang
 }
 
 // diffResources compares desired resources against prior state and assigns
-// the correct action (CREATE, UPDATE, DELETE, NO_CHANGE) to each change.
-func diffResources(desired []deploy.ResourceChange, prior *AdapterState) []deploy.ResourceChange {
+// the correct action (CREATE, UPDATE, REPLACE, DELETE, NO_CHANGE) to each
+// change. A resource is replaced when cfg asks for a change AgentCore
+// cannot make in place.
+func diffResources(desired []deploy.ResourceChange, prior *AdapterState, cfg *Config) []deploy.ResourceChange {
 	if prior == nil || len(prior.Resources) == 0 {
 		// No prior state — everything is CREATE (already set by generators).
 		return desired
@@ -222,13 +274,22 @@ func diffResources(desired []deploy.ResourceChange, prior *AdapterState) []deplo
 		key := resourceKey(d.Type, d.Name)
 		seen[key] = true
 
-		if _, exists := priorMap[key]; exists {
+		if p, exists := priorMap[key]; exists {
+			if reason := replaceReason(p, cfg); reason != "" {
+				changes = append(changes, deploy.ResourceChange{
+					Type:   d.Type,
+					Name:   d.Name,
+					Action: ActionReplace,
+					Detail: fmt.Sprintf("Replace %s %s: %s", d.Type, d.Name, reason),
+				})
+				continue
+			}
 			// Resource existed before — mark as UPDATE.
 			changes = append(changes, deploy.ResourceChange{
 				Type:   d.Type,
 				Name:   d.Name,
 				Action: deploy.ActionUpdate,
-				Detail: fmt.Sprintf("Update %s %s", d.Type, d.Name),
+				Detail: updateDetail(p, cfg),
 			})
 		} else {
 			// New resource.
@@ -261,16 +322,32 @@ func diffResources(desired []deploy.ResourceChange, prior *AdapterState) []deplo
 	return changes
 }
 
+// updateDetail describes an update to a prior resource, including any
+// runtime lifecycle change.
+func updateDetail(prior ResourceState, cfg *Config) string {
+	detail := fmt.Sprintf("Update %s %s", prior.Type, prior.Name)
+	if prior.Type != ResTypeAgentRuntime {
+		return detail
+	}
+	if changes := lifecycleChanges(prior, cfg); changes != "" {
+		detail += " (lifecycle: " + changes + ")"
+	}
+	return detail
+}
+
 // buildSummary produces a human-readable summary line such as
-// "Plan: 3 to create, 1 to update, 0 to delete".
+// "Plan: 3 to create, 1 to update, 0 to delete". Replacements are only
+// mentioned when there are some.
 func buildSummary(changes []deploy.ResourceChange) string {
-	var create, update, del int
+	var create, update, replace, del int
 	for _, c := range changes {
 		switch c.Action {
 		case deploy.ActionCreate:
 			create++
 		case deploy.ActionUpdate:
 			update++
+		case ActionReplace:
+			replace++
 		case deploy.ActionDelete:
 			del++
 		case deploy.ActionNoChange:
@@ -279,5 +356,9 @@ func buildSummary(changes []deploy.ResourceChange) string {
 			// drift detected but not tallied separately
 		}
 	}
+	if replace > 0 {
+		return fmt.Sprintf("Plan: %d to create, %d to update, %d to replace, %d to delete",
+			create, update, replace, del)
+	}
 	return fmt.Sprintf("Plan: %d to create, %d to update, %d to delete", create, update, del)
 }
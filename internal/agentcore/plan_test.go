@@ -68,6 +68,7 @@ func multiAgentPackWithToolsAndEvalsJSON() string {
 			"worker": map[string]any{
 				"id":              "worker",
 				"system_template": "work",
+				"tools":           []string{"search"},
 			},
 		},
 		"agents": map[string]any{
@@ -363,7 +364,7 @@ func TestDiffResources_NoPrior(t *testing.T) {
 		{Type: "agent_runtime", Name: "a", Action: deploy.ActionCreate},
 		{Type: "tool_gateway", Name: "b", Action: deploy.ActionCreate},
 	}
-	result := diffResources(desired, nil)
+	result := diffResources(desired, nil, &Config{})
 	if len(result) != 2 {
 		t.Fatalf("expected 2 changes, got %d", len(result))
 	}
@@ -383,7 +384,7 @@ func TestDiffResources_MultipleDeletes_Sorted(t *testing.T) {
 			{Type: "a2a_endpoint", Name: "mid"},
 		},
 	}
-	result := diffResources(nil, prior)
+	result := diffResources(nil, prior, &Config{})
 	if len(result) != 3 {
 		t.Fatalf("expected 3 deletes, got %d", len(result))
 	}
@@ -412,7 +413,7 @@ func TestDiffResources_MixedWithMultipleDeletes(t *testing.T) {
 			{Type: "agent_runtime", Name: "old_a"},
 		},
 	}
-	result := diffResources(desired, prior)
+	result := diffResources(desired, prior, &Config{})
 	if len(result) != 3 {
 		t.Fatalf("expected 3 changes (1 update + 2 deletes), got %d", len(result))
 	}
@@ -638,3 +639,25 @@ func TestPlan_InvalidArenaConfig(t *testing.T) {
 		t.Errorf("error = %q, want 'invalid arena_config JSON'", err.Error())
 	}
 }
+
+func TestPlan_ResourceNameCollision(t *testing.T) {
+	provider := newSimulatedProvider()
+	packJSON := `{
+		"id":"multi","version":"v1.0.0",
+		"prompts":{
+			"router":{"id":"router","system_template":"r","tools":["lookup"]},
+			"lookup_tool_gw":{"id":"lookup_tool_gw","system_template":"w"}
+		},
+		"agents":{"entry":"router","members":{"router":{},"lookup_tool_gw":{}}},
+		"tools":{"lookup":{"name":"lookup","description":"look things up"}}
+	}`
+	_, err := provider.Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     packJSON,
+		DeployConfig: validDeployConfig,
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), "resource name collisions") ||
+		!strings.Contains(err.Error(), `rename agent "lookup_tool_gw"`) {
+		t.Errorf("err = %v, want a collision naming the agent to rename", err)
+	}
+}
@@ -0,0 +1,130 @@
+package agentcore
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultPollInterval is the delay between status checks when waiting for a
+// resource to become ready.
+const defaultPollInterval = 5 * time.Second
+
+// defaultMaxPollAttempts limits how long we wait for a resource to become
+// ready (5 minutes at defaultPollInterval).
+const defaultMaxPollAttempts = 60
+
+// Bounds for the poll_interval and max_wait config fields.
+const (
+	minPollInterval = time.Second
+	maxPollInterval = time.Minute
+	minMaxWait      = 10 * time.Second
+	maxMaxWait      = 2 * time.Hour
+)
+
+// clock abstracts time so that polling loops can be tested without sleeping.
+type clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// systemClock is the clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// poller paces the waitFor* loops of realAWSClient. The zero value polls
+// every defaultPollInterval for up to defaultMaxPollAttempts on the system
+// clock.
+type poller struct {
+	clock    clock
+	interval time.Duration
+	attempts int
+}
+
+// newPoller builds a poller from the config's poll_interval and max_wait.
+func newPoller(cfg *Config) poller {
+	interval, maxWait := cfg.pollTiming()
+	attempts := int((maxWait + interval - 1) / interval)
+	return poller{clock: systemClock{}, interval: interval, attempts: attempts}
+}
+
+// maxAttempts is how many status checks a wait loop makes before giving up.
+func (p poller) maxAttempts() int {
+	if p.attempts > 0 {
+		return p.attempts
+	}
+	return defaultMaxPollAttempts
+}
+
+// now returns the current time on the poller's clock.
+func (p poller) now() time.Time {
+	if p.clock == nil {
+		return time.Now()
+	}
+	return p.clock.Now()
+}
+
+// sleep waits one poll interval.
+func (p poller) sleep() {
+	interval := p.interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	p.sleepFor(interval)
+}
+
+// sleepFor waits d on the poller's clock.
+func (p poller) sleepFor(d time.Duration) {
+	if p.clock == nil {
+		time.Sleep(d)
+		return
+	}
+	p.clock.Sleep(d)
+}
+
+// pollTiming returns the configured poll interval and maximum wait, falling
+// back to the defaults for unset or invalid values.
+func (c *Config) pollTiming() (interval, maxWait time.Duration) {
+	interval, maxWait = defaultPollInterval, defaultMaxPollAttempts*defaultPollInterval
+	if d, err := time.ParseDuration(c.PollInterval); err == nil && d > 0 {
+		interval = d
+	}
+	if d, err := time.ParseDuration(c.MaxWait); err == nil && d > 0 {
+		maxWait = d
+	}
+	return interval, maxWait
+}
+
+// validatePollTiming checks poll_interval and max_wait against their bounds.
+func validatePollTiming(pollInterval, maxWait string) []string {
+	var errs []string
+	interval, err := parseBoundedDuration("poll_interval", pollInterval, minPollInterval, maxPollInterval)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	wait, err := parseBoundedDuration("max_wait", maxWait, minMaxWait, maxMaxWait)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if interval > 0 && wait > 0 && wait < interval {
+		errs = append(errs, fmt.Sprintf("max_wait %s must not be shorter than poll_interval %s", wait, interval))
+	}
+	return errs
+}
+
+// parseBoundedDuration parses an optional duration field and checks that it
+// lies within [lo, hi]. An empty value returns 0.
+func parseBoundedDuration(field, value string, lo, hi time.Duration) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a valid duration (e.g. %q)", field, value, lo.String())
+	}
+	if d < lo || d > hi {
+		return 0, fmt.Errorf("%s %s must be between %s and %s", field, d, lo, hi)
+	}
+	return d, nil
+}
@@ -0,0 +1,172 @@
+package agentcore
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	smithyrand "github.com/aws/smithy-go/rand"
+)
+
+// fakeClock advances only when Sleep is called.
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+// stubHTTP answers every SDK request with the next canned JSON body,
+// repeating the last one once they run out.
+type stubHTTP struct {
+	bodies []string
+	calls  int
+}
+
+func (s *stubHTTP) Do(req *http.Request) (*http.Response, error) {
+	body := s.bodies[min(s.calls, len(s.bodies)-1)]
+	s.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// newStubbedRealClient returns a realAWSClient whose control-plane calls,
+// AgentCore and Bedrock alike, are answered by bodies and whose polling runs on a fake clock.
+func newStubbedRealClient(attempts int, bodies ...string) (*realAWSClient, *fakeClock, *stubHTTP) {
+	stub := &stubHTTP{bodies: bodies}
+	clk := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client := bedrockagentcorecontrol.New(bedrockagentcorecontrol.Options{
+		Region:      "us-west-2",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  stub,
+	})
+	// Bedrock falls back to bearer-token auth without signing credentials.
+	bedrockClient := bedrock.New(bedrock.Options{
+		Region: "us-west-2",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+		}),
+		HTTPClient:               stub,
+		IdempotencyTokenProvider: smithyrand.NewUUIDIdempotencyToken(rand.Reader),
+	})
+	return &realAWSClient{
+		client:        client,
+		bedrockClient: bedrockClient,
+		cfg:           &Config{Region: "us-west-2"},
+		poll:          poller{clock: clk, interval: 2 * time.Second, attempts: attempts},
+	}, clk, stub
+}
+
+func TestWaitForRuntimeReady_FakeClock(t *testing.T) {
+	c, clk, stub := newStubbedRealClient(10,
+		`{"status":"CREATING"}`, `{"status":"CREATING"}`, `{"status":"READY"}`)
+
+	if err := c.waitForRuntimeReady(context.Background(), "rt-1"); err != nil {
+		t.Fatalf("waitForRuntimeReady: %v", err)
+	}
+	if stub.calls != 3 {
+		t.Errorf("GetAgentRuntime called %d times, want 3", stub.calls)
+	}
+	if len(clk.slept) != 2 || clk.slept[0] != 2*time.Second {
+		t.Errorf("slept %v, want two 2s intervals", clk.slept)
+	}
+}
+
+func TestWaitForRuntimeReady_TimesOutAfterMaxAttempts(t *testing.T) {
+	c, clk, _ := newStubbedRealClient(waitProgressEvery, `{"status":"UPDATING"}`)
+	var reports []string
+	c.SetWaitProgress(func(msg string) { reports = append(reports, msg) })
+
+	err := c.waitForRuntimeReady(context.Background(), "rt-1")
+	if err == nil || !strings.Contains(err.Error(), "after 6 attempts") {
+		t.Fatalf("err = %v, want timeout after 6 attempts", err)
+	}
+	if len(clk.slept) != waitProgressEvery {
+		t.Errorf("slept %d times, want %d", len(clk.slept), waitProgressEvery)
+	}
+	want := "runtime rt-1 still UPDATING, 10s elapsed, attempt 6/6"
+	if len(reports) != 1 || reports[0] != want {
+		t.Errorf("reports = %v, want [%q]", reports, want)
+	}
+}
+
+func TestWaitForRuntimeEndpointReady_TerminalFailure(t *testing.T) {
+	c, clk, _ := newStubbedRealClient(10,
+		`{"status":"UPDATING"}`, `{"status":"UPDATE_FAILED","failureReason":"bad version"}`)
+
+	err := c.waitForRuntimeEndpointReady(context.Background(), "rt-1", "live")
+	if err == nil || !strings.Contains(err.Error(), "UPDATE_FAILED: bad version") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(clk.slept) != 1 {
+		t.Errorf("slept %d times, want 1", len(clk.slept))
+	}
+}
+
+func TestNewPoller(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantInterval time.Duration
+		wantAttempts int
+	}{
+		{name: "defaults", wantInterval: defaultPollInterval, wantAttempts: defaultMaxPollAttempts},
+		{name: "fast", cfg: Config{PollInterval: "1s", MaxWait: "30s"}, wantInterval: time.Second, wantAttempts: 30},
+		{name: "rounds up", cfg: Config{PollInterval: "4s", MaxWait: "10s"}, wantInterval: 4 * time.Second, wantAttempts: 3},
+		{name: "invalid falls back", cfg: Config{PollInterval: "soon"}, wantInterval: defaultPollInterval, wantAttempts: 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPoller(&tt.cfg)
+			if p.interval != tt.wantInterval || p.maxAttempts() != tt.wantAttempts {
+				t.Errorf("interval=%s attempts=%d, want %s/%d", p.interval, p.maxAttempts(), tt.wantInterval, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestValidatePollTiming(t *testing.T) {
+	tests := []struct {
+		name         string
+		interval     string
+		maxWait      string
+		wantContains string
+	}{
+		{name: "unset"},
+		{name: "in bounds", interval: "1s", maxWait: "1m"},
+		{name: "not a duration", interval: "5", wantContains: "not a valid duration"},
+		{name: "interval too short", interval: "100ms", wantContains: "poll_interval 100ms must be between"},
+		{name: "wait too long", maxWait: "3h", wantContains: "max_wait 3h0m0s must be between"},
+		{name: "wait shorter than interval", interval: "30s", maxWait: "15s", wantContains: "must not be shorter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePollTiming(tt.interval, tt.maxWait)
+			if tt.wantContains == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantContains) {
+				t.Errorf("errs = %v, want containing %q", errs, tt.wantContains)
+			}
+		})
+	}
+}
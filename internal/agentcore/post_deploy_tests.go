@@ -0,0 +1,250 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Post-deploy test failure policies accepted by post_deploy_tests.on_failure.
+const (
+	PostDeployOnFailureWarn     = "warn"
+	PostDeployOnFailureFail     = "fail"
+	PostDeployOnFailureRollback = "rollback"
+)
+
+// maxPostDeployTests caps the tests Apply runs after a deployment.
+const maxPostDeployTests = 50
+
+// postDeployTestSessionPrefix starts the session ID of test invocations,
+// which AgentCore requires to be at least 33 characters.
+const postDeployTestSessionPrefix = "promptarena-test-"
+
+// maxReportedResponse caps how much of a failing test's response its
+// progress event quotes.
+const maxReportedResponse = 200
+
+// metaRolledBackFrom records, on a runtime endpoint a failed post-deploy
+// test rolled back, the runtime version it was moved off.
+const metaRolledBackFrom = "rolled_back_from"
+
+// PostDeployTestsConfig lists synthetic conversations Apply runs against
+// the entry runtime once it is deployed.
+type PostDeployTestsConfig struct {
+	Tests []PostDeployTest `json:"tests"`
+	// OnFailure is what a failing test does to the deployment: "warn"
+	// (default), "fail", or "rollback".
+	OnFailure string `json:"on_failure,omitempty"`
+}
+
+// PostDeployTest is one prompt and the regex its response must match.
+type PostDeployTest struct {
+	Name   string `json:"name,omitempty"`
+	Prompt string `json:"prompt"`
+	Expect string `json:"expect"`
+}
+
+// label names the test in progress events: its name, or its position.
+func (t *PostDeployTest) label(i int) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// validatePostDeployTests checks the tests and the failure policy. A
+// rollback moves runtime endpoints, so it requires runtime_endpoint.
+func validatePostDeployTests(c *PostDeployTestsConfig, runtimeEndpoint string) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	switch n := len(c.Tests); {
+	case n == 0:
+		errs = append(errs, "post_deploy_tests.tests must list at least one test")
+	case n > maxPostDeployTests:
+		errs = append(errs, fmt.Sprintf("post_deploy_tests.tests lists %d tests, more than %d", n, maxPostDeployTests))
+	}
+	for i, t := range c.Tests {
+		if t.Prompt == "" {
+			errs = append(errs, fmt.Sprintf("post_deploy_tests.tests[%d].prompt is required", i))
+		}
+		if t.Expect == "" {
+			errs = append(errs, fmt.Sprintf("post_deploy_tests.tests[%d].expect is required", i))
+		} else if _, err := regexp.Compile(t.Expect); err != nil {
+			errs = append(errs, fmt.Sprintf("post_deploy_tests.tests[%d].expect %q is not a valid regex: %v", i, t.Expect, err))
+		}
+	}
+	switch c.OnFailure {
+	case "", PostDeployOnFailureWarn, PostDeployOnFailureFail:
+	case PostDeployOnFailureRollback:
+		if runtimeEndpoint == "" {
+			errs = append(errs, `post_deploy_tests.on_failure "rollback" requires runtime_endpoint`)
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("post_deploy_tests.on_failure %q must be %q, %q, or %q", c.OnFailure,
+			PostDeployOnFailureWarn, PostDeployOnFailureFail, PostDeployOnFailureRollback))
+	}
+	return errs
+}
+
+// runPostDeployTests sends each post-deploy test prompt to the entry
+// runtime, through the endpoint clients use, and reports whether its
+// response matched. When a test fails, on_failure decides the outcome: a
+// warning, a failed Apply, or a failed Apply whose runtime endpoints are
+// rolled back to their prior versions in resources. It returns the error
+// that fails Apply, and separately the error of a progress callback that
+// aborted it.
+func runPostDeployTests(
+	ctx context.Context, newInvoker runtimeInvokerFactory, ac *applyContext, resources []ResourceState,
+) (testErr, cbErr error) {
+	c := ac.cfg.PostDeployTests
+	if c == nil || newInvoker == nil {
+		return nil, nil
+	}
+	entry := outputAgent(OutputDecl{}, ac.pack)
+	arn := deployedRuntimeARN(resources, entry)
+	if arn == "" {
+		msg := fmt.Sprintf("Warning: skipping post-deploy tests: runtime %s is not deployed", entry)
+		return nil, ac.reporter.Progress(msg, progressNoPercent)
+	}
+
+	invoker, err := newInvoker(ctx, ac.cfg)
+	if err != nil {
+		return onPostDeployFailure(ctx, ac, resources, fmt.Errorf("agentcore: post-deploy tests: %w", err))
+	}
+	failed := 0
+	for i := range c.Tests {
+		msg, passed := runPostDeployTest(ctx, invoker, arn, i, &c.Tests[i])
+		if !passed {
+			failed++
+		}
+		if err := ac.reporter.Progress(msg, progressNoPercent); err != nil {
+			return nil, err
+		}
+	}
+	if failed == 0 {
+		return nil, nil
+	}
+	return onPostDeployFailure(ctx, ac, resources,
+		fmt.Errorf("agentcore: %d of %d post-deploy tests failed", failed, len(c.Tests)))
+}
+
+// deployedRuntimeARN returns the ARN of the named runtime in resources,
+// or "" when it was not deployed.
+func deployedRuntimeARN(resources []ResourceState, name string) string {
+	for _, r := range resources {
+		if r.Type == ResTypeAgentRuntime && r.Name == name && r.Status != ResStatusFailed {
+			return r.ARN
+		}
+	}
+	return ""
+}
+
+// runPostDeployTest invokes the runtime with the i-th test's prompt in a
+// session of its own and returns the progress message reporting the
+// outcome, and whether the test passed.
+func runPostDeployTest(
+	ctx context.Context, invoker runtimeInvoker, runtimeARN string, i int, t *PostDeployTest,
+) (string, bool) {
+	payload, err := json.Marshal(map[string]string{"prompt": t.Prompt})
+	if err != nil {
+		return fmt.Sprintf("Post-deploy test %s failed: %v", t.label(i), err), false
+	}
+	sessionID := fmt.Sprintf("%s%d-%s", postDeployTestSessionPrefix, i,
+		time.Now().UTC().Format("20060102T150405.000000000Z"))
+	body, err := invoker.Invoke(ctx, runtimeARN, sessionID, payload)
+	if err != nil {
+		return fmt.Sprintf("Post-deploy test %s failed: %v", t.label(i), err), false
+	}
+	// The expression was compiled when the config was validated.
+	reply := invocationReply(body)
+	if !regexp.MustCompile(t.Expect).MatchString(reply) {
+		if len(reply) > maxReportedResponse {
+			reply = reply[:maxReportedResponse] + "..."
+		}
+		return fmt.Sprintf("Post-deploy test %s failed: response %q does not match %q",
+			t.label(i), reply, t.Expect), false
+	}
+	return fmt.Sprintf("Post-deploy test %s passed", t.label(i)), true
+}
+
+// invocationReply returns the agent's answer in an invocation response:
+// the bridge's response field, or the whole body when it has none.
+func invocationReply(body []byte) string {
+	var resp struct {
+		Response *string `json:"response"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Response != nil {
+		return *resp.Response
+	}
+	return string(body)
+}
+
+// onPostDeployFailure applies the on_failure policy to testErr.
+func onPostDeployFailure(
+	ctx context.Context, ac *applyContext, resources []ResourceState, testErr error,
+) (error, error) {
+	switch ac.cfg.PostDeployTests.OnFailure {
+	case PostDeployOnFailureFail:
+		return testErr, nil
+	case PostDeployOnFailureRollback:
+		rollbackErr, cbErr := rollbackRuntimeEndpoints(ctx, ac, resources)
+		return errors.Join(testErr, rollbackErr), cbErr
+	}
+	return nil, ac.reporter.Progress("Warning: "+testErr.Error(), progressNoPercent)
+}
+
+// rollbackRuntimeEndpoints points each runtime endpoint this Apply moved
+// back at the runtime version it served in the prior state, and records
+// the version it left in the endpoint's metadata. Runtimes are versioned,
+// so the prior version still exists unless the runtime was replaced.
+func rollbackRuntimeEndpoints(
+	ctx context.Context, ac *applyContext, resources []ResourceState,
+) (rollbackErr, cbErr error) {
+	var errs []error
+	rolledBack := 0
+	for i := range resources {
+		r := &resources[i]
+		version := rollbackVersion(ac.priorMap, r)
+		if version == "" {
+			continue
+		}
+		_, err := ac.client.PinRuntimeEndpoint(ctx, r.Metadata[metaRuntimeARN], r.Metadata[metaQualifier], version, ac.cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("agentcore: roll back runtime endpoint %s: %w", r.Name, err))
+			continue
+		}
+		rolledBack++
+		r.Metadata[metaRolledBackFrom] = r.Metadata[metaRuntimeVersion]
+		r.Metadata[metaRuntimeVersion] = version
+		msg := fmt.Sprintf("Rolled back runtime endpoint %s to version %s", r.Name, version)
+		if cbErr = ac.reporter.Progress(msg, progressNoPercent); cbErr != nil {
+			return errors.Join(errs...), cbErr
+		}
+	}
+	if rolledBack == 0 && len(errs) == 0 {
+		cbErr = ac.reporter.Progress("Warning: no runtime endpoint has a prior version to roll back to", progressNoPercent)
+	}
+	return errors.Join(errs...), cbErr
+}
+
+// rollbackVersion returns the runtime version a runtime endpoint rolls
+// back to: the one it served in the prior state, when this Apply moved it
+// to another version of the same runtime. Otherwise it returns "".
+func rollbackVersion(priorMap map[string]ResourceState, r *ResourceState) string {
+	if r.Type != ResTypeRuntimeEndpoint || r.ARN == "" || r.Status == ResStatusFailed {
+		return ""
+	}
+	prior, ok := priorMap[resourceKey(r.Type, r.Name)]
+	if !ok || prior.Metadata[metaRuntimeARN] != r.Metadata[metaRuntimeARN] {
+		return ""
+	}
+	if version := prior.Metadata[metaRuntimeVersion]; version != r.Metadata[metaRuntimeVersion] {
+		return version
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// postDeployConfig returns a deploy config with a runtime endpoint and two
+// post-deploy tests: "greeting" expects a hello, "refunds" five days.
+func postDeployConfig(t *testing.T, onFailure string) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"runtime_endpoint":"live","post_deploy_tests":{"tests":[`+
+		`{"name":"greeting","prompt":"Say hello.","expect":"(?i)hello"},`+
+		`{"name":"refunds","prompt":"How long do refunds take?","expect":"five business days"}],`+
+		`"on_failure":%q}}`, testBinaryPath(t), onFailure)
+}
+
+// slowRefunds answers every prompt with a greeting, and the refund
+// question with a reply the "refunds" test rejects.
+func slowRefunds(prompt string) string {
+	if strings.Contains(prompt, "refunds") {
+		return "Refunds take about a week."
+	}
+	return "Hello! How can I help?"
+}
+
+func postDeployEvents(t *testing.T, events []deploy.ApplyEvent, substr string) []string {
+	t.Helper()
+	var msgs []string
+	for _, ev := range events {
+		if strings.Contains(ev.Message, substr) {
+			msgs = append(msgs, ev.Message)
+		}
+	}
+	return msgs
+}
+
+func TestValidatePostDeployTests(t *testing.T) {
+	ok := []PostDeployTest{{Prompt: "hi", Expect: "hello"}}
+	tests := []struct {
+		name     string
+		cfg      *PostDeployTestsConfig
+		endpoint string
+		want     string
+	}{
+		{"unset", nil, "", ""},
+		{"valid", &PostDeployTestsConfig{Tests: ok, OnFailure: PostDeployOnFailureFail}, "", ""},
+		{"rollback with endpoint", &PostDeployTestsConfig{Tests: ok, OnFailure: PostDeployOnFailureRollback}, "live", ""},
+		{"no tests", &PostDeployTestsConfig{}, "", "at least one test"},
+		{"too many tests", &PostDeployTestsConfig{Tests: make([]PostDeployTest, maxPostDeployTests+1)}, "", "more than"},
+		{"no prompt", &PostDeployTestsConfig{Tests: []PostDeployTest{{Expect: "x"}}}, "", "tests[0].prompt is required"},
+		{"no expect", &PostDeployTestsConfig{Tests: []PostDeployTest{{Prompt: "x"}}}, "", "tests[0].expect is required"},
+		{"bad regex", &PostDeployTestsConfig{Tests: []PostDeployTest{{Prompt: "x", Expect: "("}}}, "", "not a valid regex"},
+		{"bad policy", &PostDeployTestsConfig{Tests: ok, OnFailure: "retry"}, "", `on_failure "retry"`},
+		{"rollback without endpoint", &PostDeployTestsConfig{Tests: ok, OnFailure: PostDeployOnFailureRollback},
+			"", "requires runtime_endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePostDeployTests(tt.cfg, tt.endpoint)
+			if tt.want == "" {
+				if len(errs) > 0 {
+					t.Errorf("errors = %v, want none", errs)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(errs, "; "), tt.want) {
+				t.Errorf("errors = %v, want one containing %q", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_PostDeployTestsPass(t *testing.T) {
+	p := newSimulatedProvider()
+	invoker := &fakeRuntimeInvoker{reply: func(string) string { return "Hello, refunds take five business days." }}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+
+	events, _, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: multiAgentPack(), DeployConfig: postDeployConfig(t, PostDeployOnFailureFail),
+		ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(invoker.arns) != 2 || len(invoker.sessions) != 2 {
+		t.Fatalf("invoked %v in %d sessions, want 2 invocations in 2 sessions", invoker.arns, len(invoker.sessions))
+	}
+	for _, arn := range invoker.arns {
+		if !strings.HasSuffix(arn, "runtime/coordinator") {
+			t.Errorf("invoked %q, want the entry runtime coordinator", arn)
+		}
+	}
+	if passed := postDeployEvents(t, events, "passed"); len(passed) != 2 {
+		t.Errorf("pass events = %v, want 2", passed)
+	}
+}
+
+func TestApply_PostDeployTestsWarn(t *testing.T) {
+	p := newSimulatedProvider()
+	p.invokerFunc = runtimeInvokerFor(&fakeRuntimeInvoker{reply: slowRefunds})
+
+	events, _, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: postDeployConfig(t, ""), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply = %v, want failed tests to only warn", err)
+	}
+	if failed := postDeployEvents(t, events, "Post-deploy test refunds failed"); len(failed) != 1 {
+		t.Errorf("failure events = %v, want 1", failed)
+	}
+	if warned := postDeployEvents(t, events, "Warning: agentcore: 1 of 2 post-deploy tests failed"); len(warned) != 1 {
+		t.Errorf("warnings = %v, want 1", warned)
+	}
+}
+
+func TestApply_PostDeployTestsFail(t *testing.T) {
+	p := newSimulatedProvider()
+	p.invokerFunc = runtimeInvokerFor(&fakeRuntimeInvoker{reply: slowRefunds})
+
+	_, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: postDeployConfig(t, PostDeployOnFailureFail),
+		ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 post-deploy tests failed") {
+		t.Fatalf("Apply = %v, want the failed test to fail it", err)
+	}
+	if stateJSON == "" {
+		t.Error("Apply returned no state, want the deployed resources kept")
+	}
+}
+
+func TestApply_PostDeployTestsRollback(t *testing.T) {
+	runtimeARN := "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/mypack"
+	prior := mustJSON(t, &AdapterState{PackID: "mypack", Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "mypack", ARN: runtimeARN, Status: ResStatusCreated},
+		{Type: ResTypeRuntimeEndpoint, Name: "mypack", ARN: runtimeARN + "/runtime-endpoint/live",
+			Status: ResStatusCreated, Metadata: map[string]string{
+				metaQualifier: "live", metaRuntimeARN: runtimeARN, metaRuntimeVersion: "0",
+			}},
+	}})
+	p := newSimulatedProvider()
+	p.invokerFunc = runtimeInvokerFor(&fakeRuntimeInvoker{reply: slowRefunds})
+
+	events, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: postDeployConfig(t, PostDeployOnFailureRollback),
+		ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err == nil || !strings.Contains(err.Error(), "post-deploy tests failed") {
+		t.Fatalf("Apply = %v, want the failed test to fail it", err)
+	}
+	if rolled := postDeployEvents(t, events, "Rolled back runtime endpoint mypack to version 0"); len(rolled) != 1 {
+		t.Errorf("rollback events = %v, want 1", rolled)
+	}
+
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	ep, ok := findResourceOfType(&state, ResTypeRuntimeEndpoint)
+	if !ok {
+		t.Fatal("state has no runtime_endpoint")
+	}
+	if ep.Metadata[metaRuntimeVersion] != "0" || ep.Metadata[metaRolledBackFrom] != "1" {
+		t.Errorf("endpoint metadata = %v, want version 0 rolled back from 1", ep.Metadata)
+	}
+}
+
+func TestApply_PostDeployTestsRollbackWithoutPrior(t *testing.T) {
+	p := newSimulatedProvider()
+	p.invokerFunc = runtimeInvokerFor(&fakeRuntimeInvoker{reply: slowRefunds})
+
+	events, _, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: postDeployConfig(t, PostDeployOnFailureRollback),
+		ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil {
+		t.Fatal("Apply = nil, want the failed test to fail it")
+	}
+	if warned := postDeployEvents(t, events, "no runtime endpoint has a prior version"); len(warned) != 1 {
+		t.Errorf("warnings = %v, want 1", warned)
+	}
+}
+
+func TestInvocationReply(t *testing.T) {
+	tests := map[string]string{
+		`{"response":"Hello","status":"completed"}`: "Hello",
+		`{"status":"completed"}`:                    `{"status":"completed"}`,
+		"plain text":                                "plain text",
+	}
+	for body, want := range tests {
+		if got := invocationReply([]byte(body)); got != want {
+			t.Errorf("invocationReply(%s) = %q, want %q", body, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+)
+
+// maxPrewarm caps the warm-up invocations Apply sends to each runtime.
+const maxPrewarm = 10
+
+// prewarmPayload is the lightweight health prompt sent to warm a runtime.
+const prewarmPayload = `{"prompt":"Reply with OK."}`
+
+// prewarmSessionPrefix starts the session ID of warm-up invocations, which
+// AgentCore requires to be at least 33 characters.
+const prewarmSessionPrefix = "promptarena-prewarm-"
+
+// Metadata keys recording a runtime's warm-up timings.
+const (
+	metaPrewarmFirstMs = "prewarm_first_ms"
+	metaPrewarmWarmMs  = "prewarm_warm_ms"
+)
+
+// validatePrewarm checks that prewarm is between 0 and maxPrewarm.
+func validatePrewarm(n int) []string {
+	if n < 0 || n > maxPrewarm {
+		return []string{fmt.Sprintf("prewarm must be between 0 and %d, got %d", maxPrewarm, n)}
+	}
+	return nil
+}
+
+// prewarmRuntimes sends cfg.Prewarm health prompts to every runtime this
+// Apply created or updated, one after another in a single session, and
+// reports how long the first (cold) and the remaining (warm) invocations
+// took. The timings are also recorded in the runtime's metadata. Like the
+// gateway probe, it is advisory: a failed invocation is a warning. The
+// error is non-nil only when the progress callback aborted Apply.
+func prewarmRuntimes(
+	ctx context.Context, newInvoker runtimeInvokerFactory, reporter *adaptersdk.ProgressReporter,
+	cfg *Config, resources []ResourceState,
+) error {
+	targets := prewarmTargets(resources)
+	if newInvoker == nil || cfg.Prewarm == 0 || len(targets) == 0 {
+		return nil
+	}
+	invoker, err := newInvoker(ctx, cfg)
+	if err != nil {
+		return reporter.Progress("Warning: could not pre-warm runtimes: "+err.Error(), progressNoPercent)
+	}
+	for _, i := range targets {
+		msg := prewarmRuntime(ctx, invoker, cfg.Prewarm, &resources[i])
+		if err := reporter.Progress(msg, progressNoPercent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prewarmTargets returns the indexes of the agent runtimes in resources
+// that this Apply created, updated, or replaced.
+func prewarmTargets(resources []ResourceState) []int {
+	var targets []int
+	for i, r := range resources {
+		if r.Type != ResTypeAgentRuntime || r.ARN == "" {
+			continue
+		}
+		switch r.Status {
+		case ResStatusCreated, ResStatusUpdated, ResStatusReplaced:
+			targets = append(targets, i)
+		}
+	}
+	return targets
+}
+
+// prewarmRuntime invokes r n times and returns the progress message
+// reporting the timings, or a warning when an invocation failed.
+func prewarmRuntime(ctx context.Context, invoker runtimeInvoker, n int, r *ResourceState) string {
+	sessionID := prewarmSessionPrefix + time.Now().UTC().Format("20060102T150405.000000000Z")
+	durations := make([]time.Duration, 0, n)
+	for range n {
+		start := time.Now()
+		if _, err := invoker.Invoke(ctx, r.ARN, sessionID, []byte(prewarmPayload)); err != nil {
+			return fmt.Sprintf("Warning: pre-warm invocation %d of runtime %s failed: %v", len(durations)+1, r.Name, err)
+		}
+		durations = append(durations, time.Since(start))
+	}
+
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]string)
+	}
+	first := durations[0]
+	r.Metadata[metaPrewarmFirstMs] = strconv.FormatInt(first.Milliseconds(), 10)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pre-warmed runtime %s with %d invocations: first %s", r.Name, n, first.Round(time.Millisecond))
+	if warm := durations[1:]; len(warm) > 0 {
+		var total time.Duration
+		for _, d := range warm {
+			total += d
+		}
+		avg := total / time.Duration(len(warm))
+		r.Metadata[metaPrewarmWarmMs] = strconv.FormatInt(avg.Milliseconds(), 10)
+		fmt.Fprintf(&b, ", warm average %s", avg.Round(time.Millisecond))
+	}
+	return b.String()
+}
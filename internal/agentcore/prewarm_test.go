@@ -0,0 +1,161 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// fakeRuntimeInvoker records the invocations it receives and answers each
+// prompt with reply's response, or "OK" when reply is nil.
+type fakeRuntimeInvoker struct {
+	err      error
+	reply    func(prompt string) string
+	arns     []string
+	sessions map[string]bool
+}
+
+func (f *fakeRuntimeInvoker) Invoke(_ context.Context, runtimeARN, sessionID string, payload []byte) ([]byte, error) {
+	var req struct {
+		Prompt string `json:"prompt"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("invalid payload %s", payload)
+	}
+	f.arns = append(f.arns, runtimeARN)
+	if f.sessions == nil {
+		f.sessions = make(map[string]bool)
+	}
+	f.sessions[sessionID] = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	response := "OK"
+	if f.reply != nil {
+		response = f.reply(req.Prompt)
+	}
+	return json.Marshal(map[string]string{"response": response, "status": "completed"})
+}
+
+func runtimeInvokerFor(f runtimeInvoker) runtimeInvokerFactory {
+	return func(context.Context, *Config) (runtimeInvoker, error) { return f, nil }
+}
+
+func prewarmConfig(t *testing.T, n int) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"prewarm":%d}`, testBinaryPath(t), n)
+}
+
+func TestValidatePrewarm(t *testing.T) {
+	for n, wantErr := range map[int]bool{-1: true, 0: false, 3: false, maxPrewarm: false, maxPrewarm + 1: true} {
+		if errs := validatePrewarm(n); (len(errs) > 0) != wantErr {
+			t.Errorf("validatePrewarm(%d) = %v, want error %t", n, errs, wantErr)
+		}
+	}
+}
+
+func TestApply_PrewarmsRuntimes(t *testing.T) {
+	p := newSimulatedProvider()
+	invoker := &fakeRuntimeInvoker{}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+
+	events, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: prewarmConfig(t, 3), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(invoker.arns) != 3 || len(invoker.sessions) != 1 {
+		t.Fatalf("invoked %v in %d sessions, want 3 invocations in 1 session", invoker.arns, len(invoker.sessions))
+	}
+	for _, sid := range sortedKeys(invoker.sessions) {
+		if len(sid) < 33 {
+			t.Errorf("session ID %q is shorter than 33 characters", sid)
+		}
+	}
+
+	var msg string
+	for _, ev := range events {
+		if strings.HasPrefix(ev.Message, "Pre-warmed runtime") {
+			msg = ev.Message
+		}
+	}
+	if !strings.Contains(msg, "with 3 invocations: first ") || !strings.Contains(msg, "warm average") {
+		t.Errorf("pre-warm event = %q, want the first and warm average timings", msg)
+	}
+
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	rt, ok := findResourceOfType(&state, ResTypeAgentRuntime)
+	if !ok {
+		t.Fatal("state has no agent_runtime")
+	}
+	if rt.ARN != invoker.arns[0] {
+		t.Errorf("invoked %q, want runtime %q", invoker.arns[0], rt.ARN)
+	}
+	for _, key := range []string{metaPrewarmFirstMs, metaPrewarmWarmMs} {
+		if _, ok := rt.Metadata[key]; !ok {
+			t.Errorf("runtime metadata %v has no %q", rt.Metadata, key)
+		}
+	}
+}
+
+func TestApply_PrewarmOff(t *testing.T) {
+	p := newSimulatedProvider()
+	invoker := &fakeRuntimeInvoker{}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+
+	if _, _, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: prewarmConfig(t, 0), ArenaConfig: validArenaConfigJSON,
+	}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(invoker.arns) != 0 {
+		t.Errorf("invoked %v with prewarm 0, want no invocations", invoker.arns)
+	}
+}
+
+func TestApply_PrewarmFailureWarns(t *testing.T) {
+	p := newSimulatedProvider()
+	invoker := &fakeRuntimeInvoker{err: errors.New("ThrottlingException")}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+
+	events, _, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: prewarmConfig(t, 3), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply = %v, want a failed pre-warm to only warn", err)
+	}
+	if len(invoker.arns) != 1 {
+		t.Errorf("invoked %d times, want pre-warm to stop at the first failure", len(invoker.arns))
+	}
+	var warned bool
+	for _, ev := range events {
+		warned = warned || strings.Contains(ev.Message, "Warning: pre-warm invocation 1 of runtime")
+	}
+	if !warned {
+		t.Error("no warning for the failed pre-warm invocation")
+	}
+}
+
+func TestPrewarmTargets(t *testing.T) {
+	resources := []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "created", ARN: "arn:1", Status: ResStatusCreated},
+		{Type: ResTypeAgentRuntime, Name: "updated", ARN: "arn:2", Status: ResStatusUpdated},
+		{Type: ResTypeAgentRuntime, Name: "failed", ARN: "arn:3", Status: ResStatusFailed},
+		{Type: ResTypeAgentRuntime, Name: "carried", ARN: "arn:4"},
+		{Type: ResTypeMemory, Name: "mem", ARN: "arn:5", Status: ResStatusCreated},
+	}
+	got := prewarmTargets(resources)
+	if len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("prewarmTargets = %v, want [0 1]", got)
+	}
+}
@@ -0,0 +1,255 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// MethodPromote is the JSON-RPC method that deploys the pack version a
+// source environment runs to a target environment. It extends the
+// standard adaptersdk method set.
+const MethodPromote = "promote"
+
+// PromoteRequest is the params object of a promote call. PackJSON must be
+// the pack the source state was applied from; DeployConfig, ArenaConfig,
+// and TargetState belong to the target environment.
+type PromoteRequest struct {
+	SourceState  string `json:"source_state"`
+	PackJSON     string `json:"pack_json"`
+	DeployConfig string `json:"deploy_config"`
+	ArenaConfig  string `json:"arena_config"`
+	TargetState  string `json:"target_state,omitempty"`
+}
+
+// PromoteResponse is the result of a promote call.
+type PromoteResponse struct {
+	// State is the target's new adapter state.
+	State string `json:"state"`
+
+	// FromVersion is the pack version the target ran before, empty for a
+	// first deployment. ToVersion is the promoted version.
+	FromVersion string `json:"from_version,omitempty"`
+	ToVersion   string `json:"to_version"`
+
+	// Resources pairs each resource in either environment with its ARN in
+	// the source and in the target.
+	Resources []PromotedResource `json:"resources"`
+
+	// Outputs pairs each output of either environment with its source and
+	// target value.
+	Outputs []PromotedOutput `json:"outputs,omitempty"`
+
+	// Events are the events of the target Apply.
+	Events []*Event `json:"events,omitempty"`
+}
+
+// PromotedResource maps one resource between the environments. An empty
+// ARN means the resource does not exist on that side.
+type PromotedResource struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	SourceARN string `json:"source_arn,omitempty"`
+	TargetARN string `json:"target_arn,omitempty"`
+}
+
+// PromotedOutput maps one deployment output between the environments.
+type PromotedOutput struct {
+	Key    string `json:"key"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target,omitempty"`
+}
+
+// Promotion records, in the target state, which environment a deployment
+// was promoted from.
+type Promotion struct {
+	Version    string `json:"version"`
+	Workspace  string `json:"workspace,omitempty"`
+	PromotedAt string `json:"promoted_at"`
+}
+
+// Promote applies the pack version the source environment runs to the
+// target environment, then maps the target's resources and outputs to the
+// source's. The target goes through a normal Apply against its own prior
+// state, including any approval its deploy config requires. When the
+// source renamed its resources from the pack ID the target still runs,
+// the target Apply renames them the same way. When that Apply fails, the
+// response holds its events alongside the error.
+func (p *Provider) Promote(ctx context.Context, req *PromoteRequest) (*PromoteResponse, error) {
+	source, target, err := parsePromotion(req)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: promote: %w", err)
+	}
+	deployConfig, err := carryRenames(req.DeployConfig, source, target)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: promote: %w", err)
+	}
+
+	var events []*Event
+	stateJSON, err := p.Apply(ctx, &deploy.PlanRequest{
+		PackJSON:     req.PackJSON,
+		DeployConfig: deployConfig,
+		ArenaConfig:  req.ArenaConfig,
+		PriorState:   req.TargetState,
+	}, func(ev *deploy.ApplyEvent) error {
+		events = append(events, codedApplyEvent(ev))
+		return nil
+	})
+	if err != nil {
+		return &PromoteResponse{FromVersion: target.Version, ToVersion: source.Version, Events: events}, err
+	}
+
+	var state AdapterState
+	if err = json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		return nil, fmt.Errorf("agentcore: promote: %w", err)
+	}
+	state.PromotedFrom = &Promotion{
+		Version:    source.Version,
+		Workspace:  source.Workspace,
+		PromotedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	out, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("agentcore: failed to marshal state: %w", err)
+	}
+
+	return &PromoteResponse{
+		State:       string(out),
+		FromVersion: target.Version,
+		ToVersion:   source.Version,
+		Resources:   promotedResources(source, &state),
+		Outputs:     promotedOutputs(source.Outputs, state.Outputs),
+		Events:      events,
+	}, nil
+}
+
+// parsePromotion parses the source and target states and checks that the
+// pack is the one the source runs and that the target is another
+// deployment of it.
+func parsePromotion(req *PromoteRequest) (source, target *AdapterState, err error) {
+	source, err = parseAdapterState(req.SourceState)
+	if err != nil {
+		return nil, nil, fmt.Errorf("source_state: %w", err)
+	}
+	if source.PackID == "" || len(source.Resources) == 0 {
+		return nil, nil, errors.New("source_state holds no deployment")
+	}
+	target, err = parseAdapterState(req.TargetState)
+	if err != nil {
+		return nil, nil, fmt.Errorf("target_state: %w", err)
+	}
+	pack, err := parsePack(req.PackJSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse pack: %w", err)
+	}
+
+	if pack.ID != source.PackID || pack.Version != source.Version {
+		return nil, nil, fmt.Errorf("pack %s@%s is not the version the source runs (%s@%s)",
+			pack.ID, pack.Version, source.PackID, source.Version)
+	}
+	if target.PackID != "" && target.PackID != source.PackID && !renamedFromPack(source, target.PackID) {
+		return nil, nil, fmt.Errorf("target runs pack %q, not %q", target.PackID, source.PackID)
+	}
+	if arn := sharedARN(source.Resources, target.Resources); arn != "" {
+		return nil, nil, fmt.Errorf("source and target are the same deployment: both hold %s", arn)
+	}
+	return source, target, nil
+}
+
+// renamedFromPack reports whether the source holds resources renamed from
+// the pack ID packID with previous_pack_id.
+func renamedFromPack(source *AdapterState, packID string) bool {
+	for _, r := range source.Resources {
+		if from := r.Metadata[metaRenamedFrom]; from != "" {
+			if _, ok := renamedPackName(from, packID, source.PackID); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// carryRenames returns the target's deploy config with previous_pack_id
+// set to the pack ID the target runs when the source was renamed from it,
+// so the target Apply maps its resources to the source's names, keeping
+// their AWS names, rather than creating new ones. A previous_pack_id the
+// config sets is kept.
+func carryRenames(deployConfig string, source, target *AdapterState) (string, error) {
+	if target.PackID == "" || target.PackID == source.PackID {
+		return deployConfig, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(deployConfig), &fields); err != nil {
+		return "", fmt.Errorf("invalid config JSON: %w", err)
+	}
+	if _, ok := fields["previous_pack_id"]; ok {
+		return deployConfig, nil
+	}
+	fields["previous_pack_id"], _ = json.Marshal(target.PackID)
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("encode config: %w", err)
+	}
+	return string(out), nil
+}
+
+// sharedARN returns an ARN that appears in both resource lists, or "".
+func sharedARN(a, b []ResourceState) string {
+	arns := make(map[string]bool, len(a))
+	for _, r := range a {
+		if r.ARN != "" {
+			arns[r.ARN] = true
+		}
+	}
+	for _, r := range b {
+		if arns[r.ARN] {
+			return r.ARN
+		}
+	}
+	return ""
+}
+
+// promotedResources pairs the resources of both states by type and name,
+// in resource key order.
+func promotedResources(source, target *AdapterState) []PromotedResource {
+	byKey := make(map[string]*PromotedResource)
+	entry := func(r ResourceState) *PromotedResource {
+		key := resourceKey(r.Type, r.Name)
+		if byKey[key] == nil {
+			byKey[key] = &PromotedResource{Type: r.Type, Name: r.Name}
+		}
+		return byKey[key]
+	}
+	for _, r := range source.Resources {
+		entry(r).SourceARN = r.ARN
+	}
+	for _, r := range target.Resources {
+		entry(r).TargetARN = r.ARN
+	}
+
+	resources := make([]PromotedResource, 0, len(byKey))
+	for _, key := range sortedKeys(byKey) {
+		resources = append(resources, *byKey[key])
+	}
+	return resources
+}
+
+// promotedOutputs pairs the outputs of both environments by key.
+func promotedOutputs(source, target map[string]string) []PromotedOutput {
+	keys := make(map[string]bool, len(source)+len(target))
+	for k := range source {
+		keys[k] = true
+	}
+	for k := range target {
+		keys[k] = true
+	}
+	outputs := make([]PromotedOutput, 0, len(keys))
+	for _, k := range sortedKeys(keys) {
+		outputs = append(outputs, PromotedOutput{Key: k, Source: source[k], Target: target[k]})
+	}
+	return outputs
+}
@@ -0,0 +1,219 @@
+package agentcore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// workspaceConfig returns a valid deploy config for workspace. The prod
+// workspace is in another region.
+func workspaceConfig(t *testing.T, workspace string) string {
+	t.Helper()
+	cfg := validConfig(t)
+	if workspace == "prod" {
+		cfg = strings.Replace(cfg, "us-west-2", "us-east-1", 1)
+	}
+	return strings.TrimSuffix(cfg, "}") + `,"workspace":"` + workspace + `"}`
+}
+
+// stagingState applies singleAgentPackWithTools to the staging workspace
+// and returns its state.
+func stagingState(t *testing.T) string {
+	t.Helper()
+	_, state, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON: singleAgentPackWithTools(), DeployConfig: workspaceConfig(t, "staging"),
+		ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply staging: %v", err)
+	}
+	return state
+}
+
+func TestPromote(t *testing.T) {
+	source := stagingState(t)
+	resp, err := newSimulatedProvider().Promote(context.Background(), &PromoteRequest{
+		SourceState: source, PackJSON: singleAgentPackWithTools(),
+		DeployConfig: workspaceConfig(t, "prod"), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if resp.FromVersion != "" || resp.ToVersion != "v1.0.0" {
+		t.Errorf("versions = %q -> %q, want a first deployment of v1.0.0", resp.FromVersion, resp.ToVersion)
+	}
+	if len(resp.Events) == 0 {
+		t.Error("no apply events")
+	}
+
+	var sourceState AdapterState
+	if err = json.Unmarshal([]byte(source), &sourceState); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Resources) != len(sourceState.Resources) {
+		t.Fatalf("resources = %+v, want one per source resource", resp.Resources)
+	}
+	for _, r := range resp.Resources {
+		if r.SourceARN == "" || r.TargetARN == "" || r.SourceARN == r.TargetARN {
+			t.Errorf("resource %s/%s maps %q to %q", r.Type, r.Name, r.SourceARN, r.TargetARN)
+		}
+	}
+	for _, o := range resp.Outputs {
+		if o.Source == "" || o.Target == "" {
+			t.Errorf("output %s = %q -> %q, want both sides", o.Key, o.Source, o.Target)
+		}
+	}
+
+	var state AdapterState
+	if err = json.Unmarshal([]byte(resp.State), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Workspace != "prod" || state.PromotedFrom == nil ||
+		state.PromotedFrom.Workspace != "staging" || state.PromotedFrom.Version != "v1.0.0" {
+		t.Errorf("state workspace = %q, promoted_from = %+v", state.Workspace, state.PromotedFrom)
+	}
+}
+
+func TestPromote_Errors(t *testing.T) {
+	source := stagingState(t)
+	var pack map[string]any
+	if err := json.Unmarshal([]byte(singleAgentPackWithTools()), &pack); err != nil {
+		t.Fatal(err)
+	}
+	pack["version"] = "v2.0.0"
+	newVersion := mustJSON(t, pack)
+	tests := []struct {
+		name    string
+		req     PromoteRequest
+		wantErr string
+	}{
+		{"no source", PromoteRequest{PackJSON: singleAgentPackWithTools()}, "holds no deployment"},
+		{"other version", PromoteRequest{SourceState: source, PackJSON: newVersion},
+			"pack toolpack@v2.0.0 is not the version the source runs (toolpack@v1.0.0)"},
+		{"same deployment", PromoteRequest{SourceState: source, PackJSON: singleAgentPackWithTools(),
+			TargetState: source}, "source and target are the same deployment"},
+		{"target runs another pack", PromoteRequest{SourceState: source, PackJSON: singleAgentPackWithTools(),
+			TargetState: mustJSON(t, &AdapterState{PackID: "otherpack"})}, `target runs pack "otherpack"`},
+		{"bad pack", PromoteRequest{SourceState: source, PackJSON: "{"}, "failed to parse pack"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.DeployConfig = workspaceConfig(t, "prod")
+			_, err := newSimulatedProvider().Promote(context.Background(), &tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPromote_CarriesRenames(t *testing.T) {
+	stagingCfg := strings.TrimSuffix(validConfigWithMemory(t), "}") + `,"workspace":"staging"}`
+	prodCfg := strings.Replace(stagingCfg, "staging", "prod", 1)
+	prodCfg = strings.Replace(prodCfg, "us-west-2", "us-east-1", 1)
+
+	staging := NewSimulatedProvider(NewSimulatedCloud())
+	_, prior := applyPack(t, staging, singleAgentPack(), stagingCfg, "")
+	_, source := applyPack(t, staging, renamedPack("newpack"),
+		strings.TrimSuffix(stagingCfg, "}")+`,"previous_pack_id":"mypack"}`, prior)
+	prodCloud := NewSimulatedCloud()
+	prod := NewSimulatedProvider(prodCloud)
+	before, target := applyPack(t, prod, singleAgentPack(), prodCfg, "")
+	memARN := ""
+	if mem, ok := findResourceOfType(before, ResTypeMemory); ok {
+		memARN = mem.ARN
+	}
+
+	resp, err := prod.Promote(context.Background(), &PromoteRequest{
+		SourceState: source, PackJSON: renamedPack("newpack"), DeployConfig: prodCfg,
+		ArenaConfig: validArenaConfigJSON, TargetState: target,
+	})
+	if err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	var state AdapterState
+	if err = json.Unmarshal([]byte(resp.State), &state); err != nil {
+		t.Fatal(err)
+	}
+	mem, _ := findResourceOfType(&state, ResTypeMemory)
+	if mem.Name != "newpack_memory" || mem.ARN != memARN || mem.Metadata[metaRenamedFrom] != "mypack_memory" {
+		t.Errorf("target memory = %+v, want %s renamed from mypack_memory", mem, memARN)
+	}
+	if _, ok := prodCloud.Resource(ResTypeAgentRuntime, "newpack"); ok {
+		t.Error("Promote created a runtime named newpack in the target")
+	}
+}
+
+func TestPromote_ApplyErrorKeepsEvents(t *testing.T) {
+	sim := newSimulatedProvider()
+	p := &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			return &failingAWSClient{
+				simulatedAWSClient: *newSimulatedAWSClient(cfg.Region),
+				failOn:             map[string]bool{"agent_runtime": true},
+			}, nil
+		},
+		destroyerFunc: sim.destroyerFunc,
+		checkerFunc:   sim.checkerFunc,
+	}
+	resp, err := p.Promote(context.Background(), &PromoteRequest{
+		SourceState: stagingState(t), PackJSON: singleAgentPackWithTools(),
+		DeployConfig: workspaceConfig(t, "prod"), ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil {
+		t.Fatal("Promote succeeded, want the target Apply's error")
+	}
+	if resp == nil || len(resp.Events) == 0 || resp.ToVersion != "v1.0.0" {
+		t.Fatalf("response = %+v, want the events of the failed Apply", resp)
+	}
+	if !slices.ContainsFunc(resp.Events, func(ev *Event) bool { return ev.Type == "error" }) {
+		t.Errorf("events = %+v, want the runtime's error event", resp.Events)
+	}
+}
+
+func TestServeIO_PromoteAwaitingApprovalRunsInBackground(t *testing.T) {
+	source := stagingState(t)
+	p, _ := approvalProvider()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeIO(p, inR, outW)
+		_ = outW.Close()
+	}()
+
+	_, _ = io.WriteString(inW, jsonRPCRequest(MethodPromote, 1, PromoteRequest{
+		SourceState: source, PackJSON: singleAgentPackWithTools(), ArenaConfig: validArenaConfigJSON,
+		DeployConfig: strings.TrimSuffix(workspaceConfig(t, "prod"), "}") + `,"approval":{"required":true}}`,
+	}))
+	pending := waitForPending(t, p)
+	_, _ = io.WriteString(inW, jsonRPCRequest(MethodApprove, 2, ApproveRequest{PlanID: pending.PlanID, Approved: true}))
+	_ = inW.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(outR)
+	scanner.Buffer(nil, maxRPCLineSize)
+	for scanner.Scan() {
+		var resp jsonRPCResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("parse response %q: %v", scanner.Text(), err)
+		}
+		if resp.Error != nil {
+			t.Errorf("response %s error: %s", resp.ID, resp.Error.Message)
+		}
+		ids = append(ids, string(resp.ID))
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("ServeIO: %v", err)
+	}
+	if strings.Join(ids, ",") != "2,1" {
+		t.Errorf("response ids = %v, want the approve answered before the promote", ids)
+	}
+}
@@ -2,25 +2,30 @@ package agentcore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/AltairaLabs/PromptKit/runtime/deploy"
 )
 
-// configSchema is the JSON Schema for the agentcore provider config.
+// configSchema is the JSON Schema for the agentcore provider config, with
+// phaseNamesPlaceholder standing in for the names of the phases. Callers
+// get providerConfigSchema.
 const configSchema = `{
   "$schema": "http://json-schema.org/draft-07/schema#",
   "type": "object",
-  "required": ["region", "runtime_role_arn"],
+  "required": ["runtime_role_arn"],
   "properties": {
     "region": {
       "type": "string",
-      "pattern": "^[a-z]{2}-[a-z]+-\\d+$",
-      "description": "AWS region for AgentCore deployment"
+      "pattern": "^([a-z]{2}|us-gov)-[a-z]+-\\d+$",
+      "description": "AWS region for AgentCore deployment; defaults to AWS_REGION, and the adapter's --region flag overrides it"
     },
     "runtime_role_arn": {
       "type": "string",
-      "pattern": "^arn:aws:iam::\\d{12}:role/.+$",
+      "pattern": "^arn:aws(-cn|-us-gov)?:iam::\\d{12}:role/.+$",
       "description": "IAM role ARN for the AgentCore runtime"
     },
     "memory_store": {
@@ -42,14 +47,25 @@ const configSchema = `{
     "tools": {
       "type": "object",
       "properties": {
-        "code_interpreter": { "type": "boolean" }
+        "code_interpreter": { "type": "boolean" },
+        "audit": {
+          "type": "object",
+          "properties": {
+            "enabled": { "type": "boolean" },
+            "max_events_per_session": { "type": "integer", "minimum": 0, "maximum": 10000 }
+          }
+        }
       }
     },
     "observability": {
       "type": "object",
       "properties": {
         "cloudwatch_log_group": { "type": "string" },
-        "tracing_enabled": { "type": "boolean" }
+        "tracing_enabled": { "type": "boolean" },
+        "saved_queries": {
+          "type": "boolean",
+          "description": "When true, Apply saves Logs Insights queries over the deployment's runtime and eval results log groups"
+        }
       }
     },
     "tags": {
@@ -57,10 +73,83 @@ const configSchema = `{
       "additionalProperties": { "type": "string" },
       "description": "User-defined tags to apply to all created AWS resources"
     },
+    "runtime_env_passthrough": {
+      "type": "array",
+      "items": { "type": "string", "pattern": "^[A-Za-z_][A-Za-z0-9_]*\\*?$" },
+      "description": "Deploy environment variables, or prefixes ending in *, copied into each runtime's environment. Credential-like names are never copied"
+    },
+    "redact_patterns": {
+      "type": "array",
+      "items": { "type": "string", "minLength": 1 },
+      "description": "Extra words, matched in any case, marking runtime env vars and config keys whose values are masked in Apply and Destroy events"
+    },
     "dry_run": {
       "type": "boolean",
       "description": "When true, Apply simulates resource creation without calling AWS APIs"
     },
+    "enhanced_dry_run": {
+      "type": "boolean",
+      "description": "When true, Apply is a dry run that also checks the deployment with read-only AWS calls"
+    },
+    "response_moderation": {
+      "type": "boolean",
+      "description": "When true, the runtime bridge blocks or annotates responses that break the agent prompt's banned_words and regex validators"
+    },
+    "prewarm": {
+      "type": "integer",
+      "minimum": 0,
+      "maximum": 10,
+      "description": "Health prompts sent to each created or updated runtime once it is ready, with cold and warm timings reported in Apply events"
+    },
+    "post_deploy_tests": {
+      "type": "object",
+      "description": "Prompts Apply sends to the entry runtime after deploying it, with the regex each response must match",
+      "properties": {
+        "tests": {
+          "type": "array",
+          "minItems": 1,
+          "maxItems": 50,
+          "items": {
+            "type": "object",
+            "properties": {
+              "name": {"type": "string", "description": "Name of the test in Apply events"},
+              "prompt": {"type": "string", "description": "Prompt sent to the entry runtime"},
+              "expect": {"type": "string", "description": "Regex the response must match"}
+            },
+            "required": ["prompt", "expect"],
+            "additionalProperties": false
+          }
+        },
+        "on_failure": {
+          "type": "string",
+          "enum": ["warn", "fail", "rollback"],
+          "description": "warn (default) reports failed tests; fail fails Apply; rollback also points runtime endpoints back at their prior versions"
+        }
+      },
+      "required": ["tests"],
+      "additionalProperties": false
+    },
+    "phases": {
+      "type": "object",
+      "description": "Limits Apply to some phases, named by resource type; skipped phases keep their prior state entries",
+      "properties": {
+        "include": {
+          "type": "array",
+          "items": {
+            "enum": "$PHASE_NAMES"
+          },
+          "description": "Run only these phases"
+        },
+        "exclude": {
+          "type": "array",
+          "items": {
+            "enum": "$PHASE_NAMES"
+          },
+          "description": "Run every phase but these"
+        }
+      },
+      "additionalProperties": false
+    },
     "a2a_auth": {
       "type": "object",
       "required": ["mode"],
@@ -94,11 +183,464 @@ const configSchema = `{
       "type": "string",
       "enum": ["http", "a2a", "both"],
       "description": "Server protocol mode: http (port 8080), a2a (port 9000), or both (default)"
+    },
+    "on_conflict": {
+      "oneOf": [
+        {"type": "string", "enum": ["adopt", "fail", "replace"]},
+        {
+          "type": "object",
+          "properties": {
+            "default": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "memory": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "agent_runtime": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "tool_gateway": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "evaluator": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "online_eval_config": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "cedar_policy": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "inference_profile": {"type": "string", "enum": ["adopt", "fail", "replace"]},
+            "identity_provider": {"type": "string", "enum": ["adopt", "fail", "replace"]}
+          },
+          "additionalProperties": false
+        }
+      ],
+      "description": "What to do when a resource already exists: adopt (default), fail, or replace"
+    },
+    "confirm_replace": {
+      "type": "boolean",
+      "description": "Must be true when any on_conflict value is replace"
+    },
+    "include_adopted": {
+      "type": "boolean",
+      "description": "Let Destroy delete resources that Apply adopted instead of creating"
+    },
+    "destroy_concurrency": {
+      "type": "integer",
+      "minimum": 1,
+      "maximum": 16,
+      "description": "How many resources of one type Destroy deletes at a time (default 4)"
+    },
+    "force_detach_evaluators": {
+      "type": "boolean",
+      "description": "Let Destroy remove evaluators from online eval configs that still reference them"
+    },
+    "aws_profile": {
+      "type": "string",
+      "description": "Shared config profile the adapter loads AWS credentials from"
+    },
+    "aws_shared_config_files": {
+      "type": "array",
+      "items": {"type": "string", "minLength": 1},
+      "description": "Shared config files to load instead of ~/.aws/config"
+    },
+    "aws_shared_credentials_files": {
+      "type": "array",
+      "items": {"type": "string", "minLength": 1},
+      "description": "Shared credentials files to load instead of ~/.aws/credentials"
+    },
+    "aws_credentials_env": {
+      "type": "object",
+      "description": "Names of the environment variables holding explicit AWS credentials",
+      "properties": {
+        "access_key_id": {"type": "string", "description": "Variable holding the access key ID"},
+        "secret_access_key": {"type": "string", "description": "Variable holding the secret access key"},
+        "session_token": {"type": "string", "description": "Variable holding the session token"}
+      },
+      "required": ["access_key_id", "secret_access_key"],
+      "additionalProperties": false
+    },
+    "endpoints": {
+      "type": "object",
+      "description": "Endpoint URL overrides per AWS service; unset services use the endpoint of the region's partition; http only for localhost",
+      "properties": {
+        "bedrock_agentcore_control": {"type": "string", "pattern": "^https?://"},
+        "bedrock_agentcore": {"type": "string", "pattern": "^https?://"},
+        "bedrock": {"type": "string", "pattern": "^https?://"},
+        "bedrock_runtime": {"type": "string", "pattern": "^https?://"},
+        "sts": {"type": "string", "pattern": "^https?://"},
+        "logs": {"type": "string", "pattern": "^https?://"},
+        "s3": {"type": "string", "pattern": "^https?://"},
+        "lambda": {"type": "string", "pattern": "^https?://"},
+        "iam": {"type": "string", "pattern": "^https?://"}
+      },
+      "additionalProperties": false
+    },
+    "fips_endpoints": {
+      "type": "boolean",
+      "description": "Use the FIPS endpoint of every AWS service without an endpoints override"
+    },
+    "dualstack_endpoints": {
+      "type": "boolean",
+      "description": "Use the dual-stack (IPv4 and IPv6) endpoint of every AWS service without an endpoints override"
+    },
+    "runtime_endpoint": {
+      "type": "string",
+      "pattern": "^[a-zA-Z][a-zA-Z0-9_]{0,47}$",
+      "description": "Named endpoint created on each runtime and pointed at the newly deployed version"
+    },
+    "poll_interval": {
+      "type": "string",
+      "description": "Delay between readiness checks as a Go duration (1s to 1m, default 5s)"
+    },
+    "max_wait": {
+      "type": "string",
+      "description": "Maximum time to wait for a resource to become ready as a Go duration (10s to 2h, default 5m)"
+    },
+    "code_layout": {
+      "type": "string",
+      "enum": ["python", "binary"],
+      "description": "How the code package launches the runtime: python (main.py wrapper, default) or binary (Go binary as entry point)"
+    },
+    "code_runtime": {
+      "type": "string",
+      "enum": ["python3.10", "python3.11", "python3.12", "python3.13"],
+      "description": "Managed runtime the code package runs on (default python3.13)"
+    },
+    "entry_point": {
+      "type": "string",
+      "pattern": "^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$",
+      "description": "File AgentCore starts: the wrapper's name (a .py file) with code_layout python, the binary's name with binary"
+    },
+    "workspace": {
+      "type": "string",
+      "pattern": "^[a-zA-Z0-9][a-zA-Z0-9_]{0,15}$",
+      "description": "Separates deployments of the same pack in one account (e.g. dev, prod); appended to AWS resource names and tagged"
+    },
+    "previous_pack_id": {
+      "type": "string",
+      "description": "Pack ID the deployment was applied under before the pack was renamed; Plan and Apply map its resources to the new ID instead of recreating them"
+    },
+    "agents_filter": {
+      "type": "object",
+      "description": "Deploy only selected members of a multi-agent pack; include lists the only members deployed and exclude removes members",
+      "properties": {
+        "include": {"type": "array", "items": {"type": "string", "minLength": 1}},
+        "exclude": {"type": "array", "items": {"type": "string", "minLength": 1}}
+      },
+      "additionalProperties": false
+    },
+    "agent_cards": {
+      "type": "object",
+      "description": "Public A2A agent card overrides keyed by agent name; the default key applies to every agent",
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "display_name": {"type": "string"},
+          "description": {"type": "string"},
+          "icon_url": {"type": "string", "format": "uri"},
+          "documentation_url": {"type": "string", "format": "uri"},
+          "provider_organization": {"type": "string"},
+          "provider_url": {"type": "string", "format": "uri"}
+        },
+        "additionalProperties": false
+      }
+    },
+    "inference_profiles": {
+      "type": "object",
+      "description": "Route the runtime and evaluators through Bedrock inference profiles instead of raw model IDs",
+      "properties": {
+        "runtime": {"$ref": "#/definitions/inference_profile"},
+        "evaluators": {
+          "type": "object",
+          "description": "Inference profiles keyed by eval ID; the default key applies to every evaluator",
+          "additionalProperties": {"$ref": "#/definitions/inference_profile"}
+        }
+      },
+      "additionalProperties": false
+    },
+    "gateway_partitioning": {
+      "type": "string",
+      "enum": ["shared", "per_agent"],
+      "description": "shared (default) puts every tool on one gateway; per_agent gives each member of a multi-agent pack a gateway with its own tools"
+    },
+    "gateway": {
+      "type": "object",
+      "description": "Settings for the shared MCP tool gateway",
+      "properties": {
+        "search_type": {
+          "type": "string",
+          "enum": ["semantic", "none"],
+          "description": "semantic enables gateway-side tool discovery; none (default) lists every tool"
+        },
+        "instructions": {"type": "string", "description": "Instructions for MCP clients using the gateway"},
+        "interceptors": {
+          "type": "array",
+          "description": "Lambda functions the gateway invokes to transform tool requests or responses",
+          "items": {
+            "type": "object",
+            "properties": {
+              "lambda_arn": {"type": "string", "description": "ARN of the interceptor Lambda function"},
+              "phases": {
+                "type": "array",
+                "items": {"type": "string", "enum": ["request", "response"]},
+                "description": "When the interceptor runs"
+              },
+              "pass_request_headers": {"type": "boolean", "description": "Include request headers in the input"}
+            },
+            "required": ["lambda_arn", "phases"],
+            "additionalProperties": false
+          }
+        },
+        "probe_targets": {
+          "type": "boolean",
+          "description": "After creating targets, list the gateway's tools over MCP and warn about targets that serve none"
+        }
+      },
+      "additionalProperties": false
+    },
+    "logs": {
+      "type": "object",
+      "description": "Provision a CloudWatch log group with retention for each runtime",
+      "properties": {
+        "retention_days": {
+          "type": "integer",
+          "enum": [1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653],
+          "description": "Days CloudWatch keeps runtime logs"
+        },
+        "retain_on_destroy": {
+          "type": "boolean",
+          "description": "Keep the log groups when the deployment is destroyed"
+        }
+      },
+      "required": ["retention_days"],
+      "additionalProperties": false
+    },
+    "eval_alert": {
+      "type": "object",
+      "description": "Notify an SNS topic, Lambda function, or HTTPS endpoint through an EventBridge rule when online evaluation results score below a threshold",
+      "properties": {
+        "score_below": {
+          "type": "number",
+          "description": "Alert on results whose score is lower than this"
+        },
+        "target_arn": {
+          "type": "string",
+          "description": "SNS topic or Lambda function ARN the rule notifies"
+        },
+        "endpoint": {
+          "type": "string",
+          "description": "HTTPS URL the rule posts to through an API destination, in place of target_arn"
+        },
+        "connection_arn": {
+          "type": "string",
+          "description": "EventBridge connection holding the endpoint's authorization; required with endpoint"
+        },
+        "role_arn": {
+          "type": "string",
+          "description": "IAM role EventBridge assumes to invoke the API destination; required with endpoint"
+        }
+      },
+      "required": ["score_below"],
+      "additionalProperties": false
+    },
+    "eval_defaults": {
+      "type": "object",
+      "description": "Default sampling for every_turn llm_as_judge evals without a sample_percentage, and the traffic Plan estimates judge invocations from",
+      "properties": {
+        "judge_sample_percentage": {
+          "type": "number",
+          "minimum": 0,
+          "maximum": 100,
+          "description": "Percentage of turns such evals are evaluated on (default 10)"
+        },
+        "monthly_turns": {
+          "type": "integer",
+          "minimum": 0,
+          "description": "Expected agent turns a month, for Plan's judge invocation estimate"
+        }
+      },
+      "additionalProperties": false
+    },
+    "lifecycle": {
+      "type": "object",
+      "description": "Runtime session and instance lifetimes, and the per-instance invocation cap",
+      "properties": {
+        "idle_session_timeout": {
+          "type": "string",
+          "description": "End sessions idle this long, between 1m and 8h (e.g. \"10m\"; default 15m)"
+        },
+        "max_lifetime": {
+          "type": "string",
+          "description": "Replace runtime instances after this long, between 1m and 8h (e.g. \"4h\"; default 8h)"
+        },
+        "max_concurrent_invocations": {
+          "type": "integer",
+          "minimum": 0,
+          "description": "Invocations each runtime instance serves at once; the runtime answers 429 past it (0 = unlimited)"
+        }
+      },
+      "additionalProperties": false
+    },
+    "network": {
+      "type": "object",
+      "description": "Network the runtimes run in; changing the mode replaces them",
+      "properties": {
+        "mode": {
+          "type": "string",
+          "enum": ["PUBLIC", "VPC"],
+          "description": "PUBLIC (default) or VPC"
+        },
+        "subnets": {
+          "type": "array",
+          "items": {"type": "string"},
+          "description": "Subnet IDs of VPC runtimes; required in VPC mode"
+        },
+        "security_groups": {
+          "type": "array",
+          "items": {"type": "string"},
+          "description": "Security group IDs of VPC runtimes; required in VPC mode"
+        }
+      },
+      "additionalProperties": false
+    },
+    "rollout": {
+      "type": "object",
+      "description": "How Apply updates the member runtimes of a multi-agent pack",
+      "properties": {
+        "strategy": {
+          "type": "string",
+          "enum": ["rolling", "all_at_once"],
+          "description": "rolling (default) updates members one at a time and stops at the first unhealthy one; all_at_once updates all without health checks"
+        }
+      },
+      "additionalProperties": false
+    },
+    "sessions": {
+      "type": "object",
+      "description": "Per-session metadata kept by the runtime bridge",
+      "properties": {
+        "persist": {
+          "type": "boolean",
+          "description": "Keep session metadata in memory so it survives runtime restarts (requires memory_store)"
+        },
+        "persist_tasks": {
+          "type": "boolean",
+          "description": "Keep A2A tasks in memory so tasks/get and tasks/list survive runtime restarts (requires memory_store)"
+        },
+        "max_turns": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 10000,
+          "description": "Reject invocations once a session has served this many turns (0 = unlimited)"
+        },
+        "max_concurrent": {
+          "type": "integer",
+          "minimum": 0,
+          "description": "Reject invocations of a session that already has this many running (0 = unlimited)"
+        },
+        "daily_token_budget": {
+          "type": "integer",
+          "minimum": 0,
+          "description": "Reject invocations of a session once it has used this many tokens in the current UTC day (0 = unlimited)"
+        }
+      },
+      "additionalProperties": false
+    },
+    "memory_namespaces": {
+      "type": "object",
+      "description": "Memory namespace each runtime keeps its conversation events under (requires memory_store)",
+      "properties": {
+        "sharing": {
+          "type": "string",
+          "enum": ["shared", "isolated"],
+          "default": "shared",
+          "description": "shared: members use namespace; isolated: each member uses a namespace named after it"
+        },
+        "namespace": {
+          "type": "string",
+          "maxLength": 128,
+          "pattern": "^[a-zA-Z0-9][a-zA-Z0-9_-]*(/[a-zA-Z0-9_-]+)*$",
+          "description": "Namespace shared members use (default: the runtime's default namespace)"
+        },
+        "agents": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string",
+            "maxLength": 128,
+            "pattern": "^[a-zA-Z0-9][a-zA-Z0-9_-]*(/[a-zA-Z0-9_-]+)*$"
+          },
+          "description": "Namespace per agent member, overriding sharing"
+        }
+      },
+      "additionalProperties": false
+    },
+    "approval": {
+      "type": "object",
+      "description": "Make Apply wait for its plan to be approved through the approve method before changing anything in AWS",
+      "properties": {
+        "required": {"type": "boolean"},
+        "timeout": {
+          "type": "string",
+          "description": "How long Apply waits for approval as a Go duration (10s to 24h, default 15m)"
+        }
+      },
+      "additionalProperties": false
+    },
+    "change_manifest": {
+      "type": "object",
+      "description": "Upload the change manifest of every Apply to S3",
+      "properties": {
+        "s3_bucket": {"type": "string", "description": "Bucket that receives the manifests"},
+        "s3_prefix": {
+          "type": "string",
+          "description": "Key prefix for the manifests (default promptarena/change-manifests)"
+        }
+      },
+      "required": ["s3_bucket"],
+      "additionalProperties": false
+    },
+    "identity_providers": {
+      "type": "object",
+      "description": "AgentCore Identity OAuth2 credential providers keyed by name, for tools that call third-party APIs",
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "vendor": {"type": "string", "description": "Credential provider vendor, e.g. GoogleOauth2 or CustomOauth2"},
+          "client_id": {"type": "string", "description": "OAuth2 client ID"},
+          "client_secret_env": {"type": "string", "description": "Variable holding the OAuth2 client secret"},
+          "discovery_url": {
+            "type": "string",
+            "format": "uri",
+            "description": "OpenID Connect discovery URL; required for CustomOauth2"
+          },
+          "tools": {
+            "type": "array",
+            "items": {"type": "string"},
+            "description": "Tools whose gateway targets authenticate through the provider"
+          },
+          "scopes": {
+            "type": "array",
+            "items": {"type": "string"},
+            "description": "OAuth2 scopes the gateway targets request"
+          }
+        },
+        "required": ["vendor", "client_id", "client_secret_env"],
+        "additionalProperties": false
+      }
+    }
+  },
+  "definitions": {
+    "inference_profile": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Existing system-defined or application inference profile ID or ARN, used as-is"
+        },
+        "copy_from": {
+          "type": "string",
+          "description": "Foundation model or system-defined profile ID or ARN to create an application inference profile from"
+        }
+      },
+      "oneOf": [{"required": ["id"]}, {"required": ["copy_from"]}],
+      "additionalProperties": false
     }
   },
   "additionalProperties": false
 }`
 
+// providerName is the adapter name reported by GetProviderInfo and Describe.
+const providerName = "agentcore"
+
 // awsClientFactory creates an awsClient for the given config.
 type awsClientFactory func(ctx context.Context, cfg *Config) (awsClient, error)
 
@@ -113,6 +655,20 @@ type Provider struct {
 	awsClientFunc awsClientFactory
 	destroyerFunc destroyerFactory
 	checkerFunc   checkerFactory
+
+	evalResultsFunc  evalResultsFactory
+	memoryDataFunc   memoryDataFactory
+	modelCatalogFunc modelCatalogFactory
+	lambdaCheckFunc  lambdaCheckerFactory
+	oidcFetchFunc    oidcDiscoveryFetcher
+	judgeModelFunc   judgeModelFactory
+	gatewayListFunc  gatewayToolLister
+	invokerFunc      runtimeInvokerFactory
+
+	// regionOverride, when set, replaces every deploy config's region.
+	regionOverride string
+
+	approvals approvalGate
 }
 
 // NewProvider creates a new Provider with the real AWS
@@ -123,26 +679,59 @@ func NewProvider() *Provider {
 		awsClientFunc: newRealAWSClientFactory,
 		destroyerFunc: newRealDestroyerFactory,
 		checkerFunc:   newRealCheckerFactory,
+
+		evalResultsFunc:  newRealEvalResultsFactory,
+		memoryDataFunc:   newRealMemoryDataFactory,
+		modelCatalogFunc: newRealModelCatalogFactory,
+		lambdaCheckFunc:  newRealLambdaCheckerFactory,
+		oidcFetchFunc:    fetchOIDCDiscovery,
+		judgeModelFunc:   newRealJudgeModelFactory,
+		gatewayListFunc:  listMCPTools,
+		invokerFunc:      newRealRuntimeInvokerFactory,
 	}
 }
 
+// phaseNamesPlaceholder marks the enums of phase names in configSchema.
+const phaseNamesPlaceholder = `"$PHASE_NAMES"`
+
+// providerConfigSchema is configSchema with the phase names filled in from
+// applyOrder, so the schema accepts every registered resource type.
+var providerConfigSchema = strings.ReplaceAll(configSchema, phaseNamesPlaceholder, phaseNamesJSON())
+
+// phaseNamesJSON returns the registered resource types, in apply order, as
+// a JSON array. Marshaling a string slice cannot fail.
+func phaseNamesJSON() string {
+	out, _ := json.Marshal(typeNames(applyOrder))
+	return string(out)
+}
+
 // GetProviderInfo returns metadata about the agentcore adapter.
 func (p *Provider) GetProviderInfo(_ context.Context) (*deploy.ProviderInfo, error) {
 	return &deploy.ProviderInfo{
-		Name:         "agentcore",
-		Version:      Version,
-		Capabilities: []string{"plan", "apply", "destroy", "status", "diagnose"},
-		ConfigSchema: configSchema,
+		Name:    providerName,
+		Version: Version,
+		Capabilities: []string{
+			"plan", "apply", "destroy", "status", "diagnose",
+			MethodDescribe, MethodStatusBatch, MethodEvalResults, MethodMemoryList, MethodMemoryPurge,
+			MethodApprove, MethodPendingApprovals, MethodListEvalTemplates, MethodEvalPreview,
+			MethodLint, MethodPromote, MethodVersion, MethodGraph,
+		},
+		ConfigSchema: providerConfigSchema,
 	}, nil
 }
 
 // ValidateConfig parses and validates the provider configuration.
 // In addition to hard errors, it runs diagnostic checks and appends
 // non-fatal warnings so the user can fix common misconfigurations.
+//
+// The request carries no arena config, so ${var.*} placeholders resolve
+// from the environment only. Those it cannot resolve are reported as a
+// warning, and the checks of the fields using them are skipped: Plan and
+// Apply resolve them from the arena config's variables.
 func (p *Provider) ValidateConfig(
 	_ context.Context, req *deploy.ValidateRequest,
 ) (*deploy.ValidateResponse, error) {
-	cfg, err := parseConfig(req.Config)
+	cfg, unresolved, err := p.loadConfigUnresolved(req.Config)
 	if err != nil {
 		return &deploy.ValidateResponse{
 			Valid:  false,
@@ -150,22 +739,31 @@ func (p *Provider) ValidateConfig(
 		}, nil
 	}
 
-	errs := cfg.validate()
+	errs := withoutPlaceholders(cfg.validate())
 
-	// Append diagnostic warnings as informational entries.
-	warnings := DiagnoseConfig(cfg)
-	for _, w := range warnings {
-		errs = append(errs, "warning: "+w.String())
+	// Append warnings as informational entries. Only validation errors
+	// affect the valid flag.
+	var warnings []string
+	if len(unresolved) > 0 {
+		warnings = append(warnings, fmt.Sprintf("warning: deploy config variables %s are not set in the "+
+			"environment, so the fields using them were not validated; Plan and Apply resolve them from the "+
+			"arena config variables", strings.Join(unresolved, ", ")))
+	}
+	for _, w := range DiagnoseConfig(cfg) {
+		warnings = append(warnings, "warning: "+w.String())
 	}
-
-	// Only validation errors (not warnings) affect the valid flag.
-	validationErrorCount := len(errs) - len(warnings)
 	return &deploy.ValidateResponse{
-		Valid:  validationErrorCount == 0,
-		Errors: errs,
+		Valid:  len(errs) == 0,
+		Errors: append(errs, withoutPlaceholders(warnings)...),
 	}, nil
 }
 
+// withoutPlaceholders drops the messages about a value that still holds a
+// ${var.*} placeholder, which ValidateConfig cannot judge.
+func withoutPlaceholders(msgs []string) []string {
+	return slices.DeleteFunc(msgs, func(m string) bool { return strings.Contains(m, configVarMarker) })
+}
+
 // Import imports an existing AWS resource into the adapter state.
 func (p *Provider) Import(
 	_ context.Context, _ *deploy.ImportRequest,
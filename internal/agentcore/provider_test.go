@@ -3,6 +3,7 @@ package agentcore
 import (
 	"bytes"
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 
@@ -74,8 +75,8 @@ func TestGetProviderInfo(t *testing.T) {
 	if info.Version == "" {
 		t.Error("version is empty")
 	}
-	if len(info.Capabilities) != 5 {
-		t.Errorf("capabilities = %v, want 5 items", info.Capabilities)
+	if len(info.Capabilities) != 18 {
+		t.Errorf("capabilities = %v, want 18 items", info.Capabilities)
 	}
 	if info.ConfigSchema == "" {
 		t.Error("config_schema is empty")
@@ -259,3 +260,26 @@ func TestNewProvider(t *testing.T) {
 		t.Error("checkerFunc is nil")
 	}
 }
+
+func TestProviderConfigSchema_PhaseNames(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Phases struct {
+				Properties map[string]struct {
+					Items struct {
+						Enum []string `json:"enum"`
+					} `json:"items"`
+				} `json:"properties"`
+			} `json:"phases"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(providerConfigSchema), &schema); err != nil {
+		t.Fatalf("config schema is not JSON: %v", err)
+	}
+	want := typeNames(applyOrder)
+	for _, field := range []string{"include", "exclude"} {
+		if got := schema.Properties.Phases.Properties[field].Items.Enum; !slices.Equal(got, want) {
+			t.Errorf("phases.%s enum = %v, want %v", field, got, want)
+		}
+	}
+}
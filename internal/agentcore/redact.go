@@ -0,0 +1,234 @@
+package agentcore
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// redactMask replaces the hidden part of a redacted value.
+const redactMask = "****"
+
+// redactKeep is how many trailing characters of a redacted value stay
+// visible, so operators can still tell values apart.
+const redactKeep = 4
+
+// redactMinLength is the shortest value masked inside free text. Shorter
+// values would match ordinary words in event messages.
+const redactMinLength = 6
+
+// redactDefaultPatterns mark secret-bearing keys unless redact_patterns
+// adds more. They are the words that keep a variable out of
+// runtime_env_passthrough.
+var redactDefaultPatterns = passthroughDeniedWords
+
+// validateRedactPatterns checks that no redact_patterns entry is blank,
+// since a blank pattern would match every key.
+func validateRedactPatterns(patterns []string) []string {
+	var errs []string
+	for i, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			errs = append(errs, fmt.Sprintf("redact_patterns[%d] must not be empty", i))
+		}
+	}
+	return errs
+}
+
+// maskValue hides all but the last redactKeep characters of v. Values of
+// redactKeep characters or fewer are hidden entirely.
+func maskValue(v string) string {
+	if len(v) <= redactKeep {
+		return redactMask
+	}
+	return redactMask + v[len(v)-redactKeep:]
+}
+
+// redactor masks secret values in Apply and Destroy event messages,
+// resource details, returned errors, and the adapter's log lines. A value is secret when its
+// runtime environment variable name or deploy config key matches a
+// redaction pattern; identity provider client secrets and explicit AWS
+// credentials are always secret.
+type redactor struct {
+	secrets []string // longest first, so overlapping values mask fully
+}
+
+// newRedactor collects the secret values of cfg's config keys, its runtime
+// environment, and the deploy environment variables it reads credentials
+// from.
+func newRedactor(cfg *Config) *redactor {
+	patterns := make([]string, 0, len(redactDefaultPatterns)+len(cfg.RedactPatterns))
+	patterns = append(patterns, redactDefaultPatterns...)
+	for _, p := range cfg.RedactPatterns {
+		patterns = append(patterns, strings.ToUpper(p))
+	}
+	secretKey := func(key string) bool {
+		upper := strings.ToUpper(key)
+		return slices.ContainsFunc(patterns, func(p string) bool { return strings.Contains(upper, p) })
+	}
+
+	seen := map[string]bool{}
+	r := &redactor{}
+	add := func(v string) {
+		if len(v) >= redactMinLength && !seen[v] {
+			seen[v] = true
+			r.secrets = append(r.secrets, v)
+		}
+	}
+	for k, v := range buildRuntimeEnvVars(cfg) {
+		if secretKey(k) {
+			add(v)
+		}
+	}
+	if raw, err := json.Marshal(cfg); err == nil {
+		var tree any
+		if json.Unmarshal(raw, &tree) == nil {
+			collectSecretValues(tree, "", secretKey, add)
+		}
+	}
+	for _, p := range cfg.IdentityProviders {
+		add(os.Getenv(p.ClientSecretEnv))
+	}
+	if creds := cfg.AWSCredentialsEnv; creds != nil {
+		add(os.Getenv(creds.AccessKeyID))
+		add(os.Getenv(creds.SecretAccessKey))
+		add(os.Getenv(creds.SessionToken))
+	}
+	slices.SortFunc(r.secrets, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	return r
+}
+
+// collectSecretValues walks a decoded config and adds the string values
+// under secret keys. Values in arrays take the key of the array.
+func collectSecretValues(node any, key string, secretKey func(string) bool, add func(string)) {
+	switch v := node.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if k != "redact_patterns" {
+				collectSecretValues(child, k, secretKey, add)
+			}
+		}
+	case []any:
+		for _, child := range v {
+			collectSecretValues(child, key, secretKey, add)
+		}
+	case string:
+		if key != "" && secretKey(key) {
+			add(v)
+		}
+	}
+}
+
+// redact masks every secret value in s.
+func (r *redactor) redact(s string) string {
+	for _, secret := range r.secrets {
+		if strings.Contains(s, secret) {
+			s = strings.ReplaceAll(s, secret, maskValue(secret))
+		}
+	}
+	return s
+}
+
+// redactResource masks secret values in res's detail, returning a copy
+// when anything changed.
+func (r *redactor) redactResource(res *deploy.ResourceResult) *deploy.ResourceResult {
+	if res == nil {
+		return nil
+	}
+	if detail := r.redact(res.Detail); detail != res.Detail {
+		masked := *res
+		masked.Detail = detail
+		return &masked
+	}
+	return res
+}
+
+// applyCallback wraps callback so every event it receives is redacted.
+func (r *redactor) applyCallback(callback deploy.ApplyCallback) deploy.ApplyCallback {
+	if len(r.secrets) == 0 {
+		return callback
+	}
+	return func(ev *deploy.ApplyEvent) error {
+		return callback(&deploy.ApplyEvent{
+			Type: ev.Type, Message: r.redact(ev.Message), Resource: r.redactResource(ev.Resource),
+		})
+	}
+}
+
+// destroyCallback wraps callback so every event it receives is redacted.
+func (r *redactor) destroyCallback(callback deploy.DestroyCallback) deploy.DestroyCallback {
+	if len(r.secrets) == 0 {
+		return callback
+	}
+	return func(ev *deploy.DestroyEvent) error {
+		return callback(&deploy.DestroyEvent{
+			Type: ev.Type, Message: r.redact(ev.Message), Resource: r.redactResource(ev.Resource),
+		})
+	}
+}
+
+// redactError masks secret values in err's message. The result still
+// unwraps to err, so errors.Is and errors.As see the original chain.
+func (r *redactor) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := r.redact(err.Error()); msg != err.Error() {
+		return &redactedError{err: err, msg: msg}
+	}
+	return err
+}
+
+// logRedactors holds the redactors of the Apply and Destroy calls running
+// now. The adapter logs through the one standard logger, so logf masks
+// the secrets of all of them.
+var logRedactors struct {
+	mu  sync.Mutex
+	set map[*redactor]bool
+}
+
+// maskLogs makes logf mask r's secrets until the returned func is called.
+func (r *redactor) maskLogs() func() {
+	if len(r.secrets) == 0 {
+		return func() {}
+	}
+	logRedactors.mu.Lock()
+	defer logRedactors.mu.Unlock()
+	if logRedactors.set == nil {
+		logRedactors.set = make(map[*redactor]bool)
+	}
+	logRedactors.set[r] = true
+	return func() {
+		logRedactors.mu.Lock()
+		defer logRedactors.mu.Unlock()
+		delete(logRedactors.set, r)
+	}
+}
+
+// logf logs like log.Printf, with the secrets of every running Apply and
+// Destroy masked. All adapter logging goes through it.
+func logf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	logRedactors.mu.Lock()
+	for r := range logRedactors.set {
+		msg = r.redact(msg)
+	}
+	logRedactors.mu.Unlock()
+	log.Print(msg)
+}
+
+// redactedError is an error whose message has had secrets masked.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
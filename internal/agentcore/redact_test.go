@@ -0,0 +1,138 @@
+package agentcore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// envEchoingClient fails runtime creation with an error that quotes the
+// runtime's environment, as an AWS validation error can.
+type envEchoingClient struct {
+	simulatedAWSClient
+}
+
+func (c *envEchoingClient) CreateRuntime(_ context.Context, name string, cfg *Config) (string, error) {
+	return "", fmt.Errorf("ValidationException: runtime %s environment %v rejected", name, cfg.RuntimeEnvVars)
+}
+
+func TestMaskValue(t *testing.T) {
+	tests := map[string]string{
+		"abcd":                   "****",
+		"postgres://db/app?pw=x": "****pw=x",
+		"":                       "****",
+	}
+	for in, want := range tests {
+		if got := maskValue(in); got != want {
+			t.Errorf("maskValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRedactor_SecretValues(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://db.internal/app")
+	t.Setenv("IDP_SECRET", "client-s3cret-value")
+	cfg := &Config{
+		Region:                "us-west-2",
+		RuntimeRoleARN:        "arn:aws:iam::123456789012:role/agent",
+		RuntimeEnvPassthrough: []string{"DATABASE_URL"},
+		RedactPatterns:        []string{"database", "arn"},
+		A2AAuth:               &A2AAuthConfig{Mode: A2AAuthModeJWT, DiscoveryURL: "https://idp.example.com/.well-known"},
+		IdentityProviders: map[string]*IdentityProviderConfig{
+			"google": {Vendor: "GoogleOauth2", ClientID: "id", ClientSecretEnv: "IDP_SECRET"},
+		},
+	}
+	r := newRedactor(cfg)
+
+	msg := "env DATABASE_URL=postgres://db.internal/app role arn:aws:iam::123456789012:role/agent " +
+		"secret client-s3cret-value discovery https://idp.example.com/.well-known region us-west-2"
+	got := r.redact(msg)
+	for _, leaked := range []string{"db.internal", "123456789012", "client-s3cret"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("redacted message still contains %q: %s", leaked, got)
+		}
+	}
+	for _, kept := range []string{"****/app", "****gent", "****alue", "https://idp.example.com", "us-west-2"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("redacted message lacks %q: %s", kept, got)
+		}
+	}
+
+	cfg.RedactPatterns = append(cfg.RedactPatterns, "URL")
+	if got = newRedactor(cfg).redact(msg); strings.Contains(got, "idp.example.com") {
+		t.Errorf("discovery URL not masked with the URL pattern: %s", got)
+	}
+}
+
+func TestApply_RedactsEventsAndError(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://db.internal/app")
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) {
+		return &envEchoingClient{simulatedAWSClient: *newSimulatedAWSClient("us-west-2")}, nil
+	}
+	cfg := strings.TrimSuffix(validConfig(t), "}") +
+		`,"runtime_env_passthrough":["DATABASE_URL"],"redact_patterns":["DATABASE"]}`
+
+	events, _, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON,
+	})
+	if err == nil || strings.Contains(err.Error(), "db.internal") || !strings.Contains(err.Error(), "****/app") {
+		t.Fatalf("err = %v, want the runtime failure with the value masked", err)
+	}
+	var deployErr *DeployError
+	if !errors.As(err, &deployErr) {
+		t.Errorf("redacted error does not unwrap to a DeployError: %T", err)
+	}
+	var masked bool
+	for _, ev := range events {
+		text := ev.Message
+		if ev.Resource != nil {
+			text += ev.Resource.Detail
+		}
+		if strings.Contains(text, "db.internal") {
+			t.Errorf("%s event leaks the secret: %s", ev.Type, text)
+		}
+		masked = masked || strings.Contains(text, "****/app")
+	}
+	if !masked {
+		t.Error("no event carries the masked value")
+	}
+}
+
+func TestLogf_MasksRunningRedactors(t *testing.T) {
+	var buf bytes.Buffer
+	prevOut, prevFlags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+	}()
+
+	cfg := &Config{RuntimeRoleARN: "arn:aws:iam::123456789012:role/agent", RedactPatterns: []string{"arn"}}
+	release := newRedactor(cfg).maskLogs()
+	logf("agentcore: runtime role %s", cfg.RuntimeRoleARN)
+	release()
+	logf("agentcore: runtime role %s", cfg.RuntimeRoleARN)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[0] != "agentcore: runtime role ****gent" {
+		t.Errorf("log = %q, want the role masked while the redactor runs", buf.String())
+	}
+	if len(lines) == 2 && !strings.Contains(lines[1], "123456789012") {
+		t.Errorf("log after release = %q, want it unmasked", lines[1])
+	}
+}
+
+func TestValidateRedactPatterns(t *testing.T) {
+	errs := validateRedactPatterns([]string{"TOKEN", " "})
+	if len(errs) != 1 || errs[0] != "redact_patterns[1] must not be empty" {
+		t.Errorf("errs = %v", errs)
+	}
+}
@@ -0,0 +1,150 @@
+package agentcore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// metaRenamedFrom is the metadata key recording the name a resource was
+// created under before previous_pack_id renamed it. Its AWS resource keeps
+// that name.
+const metaRenamedFrom = "renamed_from"
+
+// renamedPackName returns name with the pack ID oldID it is derived from
+// replaced by newID, and false when name is not derived from oldID.
+func renamedPackName(name, oldID, newID string) (string, bool) {
+	if name == oldID {
+		return newID, true
+	}
+	if rest, ok := strings.CutPrefix(name, oldID+"_"); ok {
+		return newID + "_" + rest, true
+	}
+	return "", false
+}
+
+// renamePriorResources maps the prior resources named after cfg's
+// previous_pack_id to the names packID derives, so Plan and Apply update or
+// adopt them instead of creating new resources and orphaning the old ones.
+// A resource is renamed only when the pack derives its new name and no
+// longer its old one. Renamed resources keep their ARN and record in
+// metadata the name their AWS resource keeps. It returns the resources and
+// the old name of each renamed resource, keyed by resourceKey.
+func renamePriorResources(
+	prior []ResourceState, desired []deploy.ResourceChange, packID string, cfg *Config,
+) ([]ResourceState, map[string]string) {
+	if cfg.PreviousPackID == "" || cfg.PreviousPackID == packID {
+		return prior, nil
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		wanted[resourceKey(d.Type, d.Name)] = true
+	}
+	held := make(map[string]bool, len(prior))
+	for _, r := range prior {
+		held[resourceKey(r.Type, r.Name)] = true
+	}
+
+	renames := make(map[string]string)
+	out := make([]ResourceState, 0, len(prior))
+	for _, r := range prior {
+		name, ok := renamedPackName(r.Name, cfg.PreviousPackID, packID)
+		key := resourceKey(r.Type, name)
+		if !ok || !wanted[key] || held[key] || wanted[resourceKey(r.Type, r.Name)] {
+			out = append(out, r)
+			continue
+		}
+		from := r.Metadata[metaRenamedFrom]
+		if from == "" {
+			from = r.Name
+		}
+		renames[key] = r.Name
+		r.Name = name
+		if name == from {
+			r.Metadata = withoutMetadata(r.Metadata, metaRenamedFrom)
+		} else {
+			r.Metadata = withMetadata(r.Metadata, metaRenamedFrom, from)
+		}
+		out = append(out, r)
+	}
+	return out, renames
+}
+
+// withoutMetadata returns a copy of metadata without key.
+func withoutMetadata(metadata map[string]string, key string) map[string]string {
+	out := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// trackRenames records the names the AWS resources of renamed prior
+// resources keep, so awsName creates and adopts them under those names.
+func (c *Config) trackRenames(prior map[string]ResourceState) {
+	for _, r := range prior {
+		if from := r.Metadata[metaRenamedFrom]; from != "" {
+			if c.renamed == nil {
+				c.renamed = make(map[string]string)
+			}
+			c.renamed[r.Name] = from
+		}
+	}
+}
+
+// renamedFromPreviousPack reports whether the resource name was renamed
+// from previous_pack_id and its AWS resource is tagged with it, so
+// adoption accepts it as the deployment's own.
+func (c *Config) renamedFromPreviousPack(name, taggedPackID string) bool {
+	return c.PreviousPackID != "" && taggedPackID == c.PreviousPackID && c.renamed[name] != ""
+}
+
+// annotateRenames notes the old name of each renamed resource in its
+// change's detail.
+func annotateRenames(changes []deploy.ResourceChange, renames map[string]string) {
+	for i := range changes {
+		if from, ok := renames[resourceKey(changes[i].Type, changes[i].Name)]; ok {
+			changes[i].Detail += fmt.Sprintf(" (renamed from %s)", from)
+		}
+	}
+}
+
+// markRenamed records on each renamed resource the name its AWS resource
+// keeps. A renamed resource Apply found under that name is the
+// deployment's own, so it is not marked adopted.
+func markRenamed(resources []ResourceState, priorMap map[string]ResourceState) {
+	for i := range resources {
+		prior, ok := priorMap[resourceKey(resources[i].Type, resources[i].Name)]
+		from := prior.Metadata[metaRenamedFrom]
+		if !ok || from == "" || resources[i].ARN == "" {
+			continue
+		}
+		resources[i].Metadata = withMetadata(resources[i].Metadata, metaRenamedFrom, from)
+		if prior.isOwned() && prior.ARN == resources[i].ARN {
+			resources[i].Owned = nil
+		}
+	}
+}
+
+// applyPriorMap parses the prior state into a lookup map, renaming the
+// resources of previous_pack_id, and tracks the renames in cfg.
+func applyPriorMap(priorState string, pack *prompt.Pack, cfg *Config) map[string]ResourceState {
+	priorMap := parsePriorState(priorState)
+	if cfg.PreviousPackID != "" {
+		prior := make([]ResourceState, 0, len(priorMap))
+		for _, r := range priorMap {
+			prior = append(prior, r)
+		}
+		renamed, _ := renamePriorResources(prior, generateDesiredResources(pack, cfg), pack.ID, cfg)
+		priorMap = make(map[string]ResourceState, len(renamed))
+		for _, r := range renamed {
+			priorMap[resourceKey(r.Type, r.Name)] = r
+		}
+	}
+	cfg.trackRenames(priorMap)
+	return priorMap
+}
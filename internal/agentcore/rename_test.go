@@ -0,0 +1,142 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// renamedPack returns singleAgentPack with its ID changed to id.
+func renamedPack(id string) string {
+	return strings.Replace(singleAgentPack(), `"id":"mypack"`, `"id":"`+id+`"`, 1)
+}
+
+func applyPack(t *testing.T, p *Provider, pack, deployConfig, prior string) (*AdapterState, string) {
+	t.Helper()
+	_, raw, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: pack, DeployConfig: deployConfig, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		t.Fatal(err)
+	}
+	return &state, raw
+}
+
+func TestRenamedPackName(t *testing.T) {
+	for _, tt := range []struct {
+		name, want string
+		ok         bool
+	}{
+		{"mypack", "newpack", true},
+		{"mypack_memory", "newpack_memory", true},
+		{"mypack_online_eval", "newpack_online_eval", true},
+		{"mypackage", "", false},
+		{"worker", "", false},
+	} {
+		got, ok := renamedPackName(tt.name, "mypack", "newpack")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("renamedPackName(%q) = %q, %t; want %q, %t", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPlan_PreviousPackIDRenames(t *testing.T) {
+	p := newSimulatedProvider()
+	_, prior := applyPack(t, p, singleAgentPack(), validConfigWithMemory(t), "")
+
+	cfg := strings.TrimSuffix(validConfigWithMemory(t), "}") + `,"previous_pack_id":"mypack"}`
+	resp, err := p.Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: renamedPack("newpack"), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	for _, c := range resp.Changes {
+		if c.Action == deploy.ActionCreate || c.Action == deploy.ActionDelete {
+			t.Errorf("change %s %s %s, want the renamed resources updated", c.Action, c.Type, c.Name)
+		}
+	}
+	runtime := findChange(resp.Changes, ResTypeAgentRuntime, "newpack")
+	if runtime == nil || !strings.HasSuffix(runtime.Detail, "(renamed from mypack)") {
+		t.Errorf("runtime change = %+v, want a rename from mypack", runtime)
+	}
+	if !strings.Contains(resp.Summary, "Renaming 2 resources from pack mypack to newpack") {
+		t.Errorf("summary = %q", resp.Summary)
+	}
+
+	resp, err = p.Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: renamedPack("newpack"), DeployConfig: validConfigWithMemory(t), ArenaConfig: validArenaConfigJSON,
+		PriorState: prior,
+	})
+	if err != nil {
+		t.Fatalf("Plan without previous_pack_id: %v", err)
+	}
+	if c := findChange(resp.Changes, ResTypeAgentRuntime, "mypack"); c == nil || c.Action != deploy.ActionDelete {
+		t.Errorf("without previous_pack_id the old runtime change = %+v, want a delete", c)
+	}
+}
+
+func TestApply_PreviousPackIDKeepsAWSResources(t *testing.T) {
+	cloud := NewSimulatedCloud()
+	p := NewSimulatedProvider(cloud)
+	first, prior := applyPack(t, p, singleAgentPack(), validConfigWithMemory(t), "")
+	arns := make(map[string]string)
+	for _, r := range first.Resources {
+		arns[r.Type] = r.ARN
+	}
+
+	cfg := strings.TrimSuffix(validConfigWithMemory(t), "}") + `,"previous_pack_id":"mypack"}`
+	renamed, raw := applyPack(t, p, renamedPack("newpack"), cfg, prior)
+	for _, r := range renamed.Resources {
+		if r.Type != ResTypeAgentRuntime && r.Type != ResTypeMemory {
+			continue
+		}
+		if r.ARN != arns[r.Type] || r.Metadata[metaRenamedFrom] == "" || !r.isOwned() {
+			t.Errorf("%s %s = %+v, want the owned prior resource %s", r.Type, r.Name, r, arns[r.Type])
+		}
+	}
+	if _, ok := cloud.Resource(ResTypeAgentRuntime, "newpack"); ok {
+		t.Error("Apply created a runtime named newpack")
+	}
+	if mem, _ := cloud.Resource(ResTypeMemory, "mypack_memory"); mem.Tags[TagKeyPackID] != "newpack" {
+		t.Errorf("memory tags = %v, want the new pack ID", mem.Tags)
+	}
+
+	// Later applies keep the AWS names without previous_pack_id.
+	again, _ := applyPack(t, p, renamedPack("newpack"), validConfigWithMemory(t), raw)
+	mem, _ := findResourceOfType(again, ResTypeMemory)
+	if mem.Name != "newpack_memory" || mem.ARN != arns[ResTypeMemory] || mem.Metadata[metaRenamedFrom] != "mypack_memory" {
+		t.Errorf("memory after a later Apply = %+v, want %s renamed from mypack_memory", mem, arns[ResTypeMemory])
+	}
+	if _, ok := cloud.Resource(ResTypeMemory, "newpack_memory"); ok {
+		t.Error("a later Apply created a memory named newpack_memory")
+	}
+}
+
+func TestRenamePriorResources_RenameBack(t *testing.T) {
+	prior := []ResourceState{{
+		Type: ResTypeAgentRuntime, Name: "newpack", ARN: "arn:runtime",
+		Metadata: map[string]string{metaRenamedFrom: "mypack"},
+	}}
+	desired := []deploy.ResourceChange{{Type: ResTypeAgentRuntime, Name: "mypack"}}
+	got, renames := renamePriorResources(prior, desired, "mypack", &Config{PreviousPackID: "newpack"})
+	if got[0].Name != "mypack" || got[0].Metadata[metaRenamedFrom] != "" || renames["agent_runtime/mypack"] != "newpack" {
+		t.Errorf("renamed back = %+v, %v; want mypack without a recorded rename", got[0], renames)
+	}
+}
+
+func findChange(changes []deploy.ResourceChange, resType, name string) *deploy.ResourceChange {
+	for i := range changes {
+		if changes[i].Type == resType && changes[i].Name == name {
+			return &changes[i]
+		}
+	}
+	return nil
+}
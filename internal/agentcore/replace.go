@@ -0,0 +1,144 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+)
+
+// ActionReplace marks a change AgentCore cannot make in place: Apply
+// deletes the existing resource and creates it again under the same name.
+// The deploy SDK has no replace action, so the adapter defines its own.
+const ActionReplace deploy.Action = "REPLACE"
+
+// Memory metadata keys stored in ResourceState.Metadata. They record the
+// settings a memory was created with, so a later Plan can tell which
+// changes need a replacement.
+const (
+	metaMemoryStrategies    = "strategies"
+	metaMemoryEncryptionKey = "encryption_key_arn"
+)
+
+// Runtime metadata keys recording the settings a runtime was created with
+// that AgentCore cannot change in place.
+const (
+	metaRuntimeNetworkMode = "network_mode"
+	metaRuntimeAWSName     = "aws_name"
+)
+
+// replaceReason returns why prior cannot be updated in place to match cfg,
+// or "" when it can. Resources whose state predates the recorded settings
+// are never replaced.
+func replaceReason(prior ResourceState, cfg *Config) string {
+	switch prior.Type {
+	case ResTypeMemory:
+		return memoryReplaceReason(prior, cfg)
+	case ResTypeAgentRuntime:
+		return runtimeReplaceReason(prior, cfg)
+	}
+	return ""
+}
+
+// runtimeReplaceReason reports network mode flips and changes of the name
+// the runtime is created under, neither of which UpdateAgentRuntime can
+// apply.
+func runtimeReplaceReason(prior ResourceState, cfg *Config) string {
+	if mode, ok := prior.Metadata[metaRuntimeNetworkMode]; ok && mode != cfg.networkMode() {
+		return fmt.Sprintf("network mode changed from %s to %s", mode, cfg.networkMode())
+	}
+	if name, ok := prior.Metadata[metaRuntimeAWSName]; ok && name != cfg.awsName(prior.Name) {
+		return fmt.Sprintf("runtime name changed from %s to %s", name, cfg.awsName(prior.Name))
+	}
+	return ""
+}
+
+// recordRuntimeSettings notes on each deployed runtime the settings
+// runtimeReplaceReason compares.
+func recordRuntimeSettings(resources []ResourceState, cfg *Config) {
+	for i := range resources {
+		r := &resources[i]
+		if r.Type != ResTypeAgentRuntime || r.Status == ResStatusFailed {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string)
+		}
+		r.Metadata[metaRuntimeNetworkMode] = cfg.networkMode()
+		r.Metadata[metaRuntimeAWSName] = cfg.awsName(r.Name)
+	}
+}
+
+// memoryReplaceReason reports strategy removals and encryption key
+// changes, neither of which AgentCore can apply to an existing memory.
+func memoryReplaceReason(prior ResourceState, cfg *Config) string {
+	recorded, ok := prior.Metadata[metaMemoryStrategies]
+	if !ok {
+		return ""
+	}
+	want := make(map[string]bool, len(cfg.Memory.Strategies))
+	for _, s := range cfg.Memory.Strategies {
+		want[s] = true
+	}
+	var removed []string
+	for _, s := range strings.Split(recorded, ",") {
+		if s != "" && !want[s] {
+			removed = append(removed, s)
+		}
+	}
+	if len(removed) > 0 {
+		return fmt.Sprintf("strategies removed: %s", strings.Join(removed, ", "))
+	}
+	if prior.Metadata[metaMemoryEncryptionKey] != cfg.Memory.EncryptionKeyARN {
+		return "encryption key changed"
+	}
+	return ""
+}
+
+// memoryMetadata records the settings a memory is created with.
+func memoryMetadata(cfg *Config) map[string]string {
+	meta := map[string]string{metaMemoryStrategies: cfg.MemoryStrategiesCSV()}
+	if cfg.Memory.EncryptionKeyARN != "" {
+		meta[metaMemoryEncryptionKey] = cfg.Memory.EncryptionKeyARN
+	}
+	return meta
+}
+
+// deletionWaiter is implemented by clients whose deletes finish in the
+// background, so a replacement must wait before creating the resource
+// again under the same name.
+type deletionWaiter interface {
+	WaitDeleted(ctx context.Context, res ResourceState) error
+}
+
+// deleteForReplace deletes prior so it can be created again. Adopted
+// resources are only replaced when include_adopted is set, matching
+// Destroy.
+func deleteForReplace(
+	ctx context.Context, reporter *adaptersdk.ProgressReporter,
+	client awsClient, prior ResourceState, reason string, includeAdopted bool,
+) error {
+	if !prior.isOwned() && !includeAdopted {
+		return fmt.Errorf("must be replaced (%s) but was adopted; set include_adopted to let Apply replace it", reason)
+	}
+	destroyer, ok := client.(resourceDestroyer)
+	if !ok {
+		return fmt.Errorf("must be replaced (%s) but the AWS client cannot delete resources", reason)
+	}
+	if err := reporter.Progress(
+		fmt.Sprintf("Replacing %s: %s (%s)", prior.Type, prior.Name, reason), 0,
+	); err != nil {
+		return err
+	}
+	if err := destroyer.DeleteResource(ctx, prior); err != nil {
+		return fmt.Errorf("delete before replace: %w", err)
+	}
+	if waiter, ok := client.(deletionWaiter); ok {
+		if err := waiter.WaitDeleted(ctx, prior); err != nil {
+			return fmt.Errorf("wait for delete before replace: %w", err)
+		}
+	}
+	return nil
+}
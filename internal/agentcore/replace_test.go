@@ -0,0 +1,237 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func TestMemoryReplaceReason(t *testing.T) {
+	tests := []struct {
+		name string
+		meta map[string]string
+		cfg  MemoryConfig
+		want string
+	}{
+		{"no recorded settings", nil, MemoryConfig{Strategies: []string{"episodic"}}, ""},
+		{
+			"unchanged",
+			map[string]string{metaMemoryStrategies: "episodic"},
+			MemoryConfig{Strategies: []string{"episodic"}}, "",
+		},
+		{
+			"strategy added",
+			map[string]string{metaMemoryStrategies: "episodic"},
+			MemoryConfig{Strategies: []string{"episodic", "semantic"}}, "",
+		},
+		{
+			"strategies removed",
+			map[string]string{metaMemoryStrategies: "episodic,semantic,summary"},
+			MemoryConfig{Strategies: []string{"episodic"}}, "strategies removed: semantic, summary",
+		},
+		{
+			"encryption key changed",
+			map[string]string{metaMemoryStrategies: "episodic", metaMemoryEncryptionKey: "arn:kms:a"},
+			MemoryConfig{Strategies: []string{"episodic"}, EncryptionKeyARN: "arn:kms:b"}, "encryption key changed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prior := ResourceState{Type: ResTypeMemory, Name: "mypack_memory", Metadata: tt.meta}
+			if got := replaceReason(prior, &Config{Memory: tt.cfg}); got != tt.want {
+				t.Errorf("replaceReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuntimeReplaceReason(t *testing.T) {
+	vpc := &NetworkConfig{Mode: networkModeVPC, Subnets: []string{"subnet-1"}, SecurityGroups: []string{"sg-1"}}
+	tests := []struct {
+		name string
+		meta map[string]string
+		cfg  *Config
+		want string
+	}{
+		{"no recorded settings", nil, &Config{Network: vpc}, ""},
+		{
+			"unchanged",
+			map[string]string{metaRuntimeNetworkMode: networkModePublic, metaRuntimeAWSName: "mypack"},
+			&Config{}, "",
+		},
+		{
+			"network mode flipped",
+			map[string]string{metaRuntimeNetworkMode: networkModePublic},
+			&Config{Network: vpc}, "network mode changed from PUBLIC to VPC",
+		},
+		{
+			"name changed",
+			map[string]string{metaRuntimeAWSName: "mypack_old"},
+			&Config{}, "runtime name changed from mypack_old to mypack",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prior := ResourceState{Type: ResTypeAgentRuntime, Name: "mypack", Metadata: tt.meta}
+			if got := replaceReason(prior, tt.cfg); got != tt.want {
+				t.Errorf("replaceReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffResources_Replace(t *testing.T) {
+	desired := []deploy.ResourceChange{{Type: ResTypeMemory, Name: "mypack_memory", Action: deploy.ActionCreate}}
+	prior := &AdapterState{Resources: []ResourceState{{
+		Type: ResTypeMemory, Name: "mypack_memory", ARN: "arn:mem",
+		Metadata: map[string]string{metaMemoryStrategies: "episodic,semantic"},
+	}}}
+	cfg := &Config{Memory: MemoryConfig{Strategies: []string{"episodic"}}}
+
+	changes := diffResources(desired, prior, cfg)
+	if len(changes) != 1 || changes[0].Action != ActionReplace {
+		t.Fatalf("changes = %+v, want a single replace", changes)
+	}
+	if !strings.Contains(changes[0].Detail, "strategies removed: semantic") {
+		t.Errorf("detail = %q, want the replace reason", changes[0].Detail)
+	}
+	if got := buildSummary(changes); got != "Plan: 0 to create, 0 to update, 1 to replace, 0 to delete" {
+		t.Errorf("summary = %q", got)
+	}
+}
+
+// replacingClient is a simulated client that records deletes.
+type replacingClient struct {
+	simulatedAWSClient
+	recordingDestroyer
+}
+
+func priorStateWithMemory(owned *bool) string {
+	state := AdapterState{
+		PackID:  "mypack",
+		Version: "v1.0.0",
+		Resources: []ResourceState{{
+			Type: ResTypeMemory, Name: "mypack_memory", ARN: "arn:old-memory", Status: ResStatusCreated,
+			Metadata: map[string]string{metaMemoryStrategies: "episodic,semantic"},
+			Owned:    owned,
+		}},
+	}
+	b, _ := json.Marshal(state)
+	return string(b)
+}
+
+func applyWithPriorMemory(t *testing.T, owned *bool) (*replacingClient, []deploy.ApplyEvent, *AdapterState, error) {
+	t.Helper()
+	client := &replacingClient{simulatedAWSClient: *newSimulatedAWSClient("us-west-2")}
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) { return client, nil }
+
+	events, stateJSON, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfigWithMemory(t),
+		PriorState:   priorStateWithMemory(owned),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	state, parseErr := parseAdapterState(stateJSON)
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+	return client, events, state, err
+}
+
+func TestApply_ReplacesMemory(t *testing.T) {
+	client, events, state, err := applyWithPriorMemory(t, nil)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(client.deleted) != 1 || client.deleted[0] != "mypack_memory" {
+		t.Errorf("deleted = %v, want the prior memory", client.deleted)
+	}
+
+	var action deploy.Action
+	for _, ev := range events {
+		if ev.Type == "resource" && ev.Resource != nil && ev.Resource.Type == ResTypeMemory {
+			action = ev.Resource.Action
+		}
+	}
+	if action != ActionReplace {
+		t.Errorf("memory action = %q, want %q", action, ActionReplace)
+	}
+
+	mem := state.Resources[0]
+	if mem.Type != ResTypeMemory || mem.Status != ResStatusReplaced || mem.ARN == "arn:old-memory" {
+		t.Errorf("memory state = %+v, want a replaced memory with a new ARN", mem)
+	}
+	if got := mem.Metadata[metaMemoryStrategies]; got != "episodic" {
+		t.Errorf("recorded strategies = %q, want %q", got, "episodic")
+	}
+}
+
+func TestApply_ReplaceAdoptedMemory(t *testing.T) {
+	notOwned := false
+	client, _, state, err := applyWithPriorMemory(t, &notOwned)
+	if err == nil || !strings.Contains(err.Error(), "include_adopted") {
+		t.Fatalf("Apply error = %v, want an include_adopted error", err)
+	}
+	if len(client.deleted) != 0 {
+		t.Errorf("deleted = %v, want no deletes", client.deleted)
+	}
+	if mem := state.Resources[0]; mem.ARN != "arn:old-memory" {
+		t.Errorf("memory state = %+v, want the prior memory kept", mem)
+	}
+}
+
+func TestApply_ReplacesRuntimeOnNetworkModeFlip(t *testing.T) {
+	client := &replacingClient{simulatedAWSClient: *newSimulatedAWSClient("us-west-2")}
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) { return client, nil }
+	prior, _ := json.Marshal(AdapterState{
+		PackID: "mypack", Version: "v1.0.0",
+		Resources: []ResourceState{{
+			Type: ResTypeAgentRuntime, Name: "mypack", ARN: "arn:old-runtime", Status: ResStatusCreated,
+			Metadata: map[string]string{metaRuntimeNetworkMode: networkModePublic, metaRuntimeAWSName: "mypack"},
+		}},
+	})
+	deployConfig := strings.TrimSuffix(validConfig(t), "}") +
+		`,"network":{"mode":"VPC","subnets":["subnet-1"],"security_groups":["sg-1"]}}`
+
+	events, stateJSON, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: deployConfig,
+		PriorState: string(prior), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(client.deleted) != 1 || client.deleted[0] != "mypack" {
+		t.Errorf("deleted = %v, want the prior runtime", client.deleted)
+	}
+	var action deploy.Action
+	for _, ev := range events {
+		if ev.Type == "resource" && ev.Resource != nil && ev.Resource.Type == ResTypeAgentRuntime {
+			action = ev.Resource.Action
+		}
+	}
+	if action != ActionReplace {
+		t.Errorf("runtime action = %q, want %q", action, ActionReplace)
+	}
+	state, parseErr := parseAdapterState(stateJSON)
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+	var found bool
+	for _, r := range state.Resources {
+		if r.Type != ResTypeAgentRuntime {
+			continue
+		}
+		found = true
+		if r.Status != ResStatusReplaced || r.ARN == "arn:old-runtime" || r.Metadata[metaRuntimeNetworkMode] != networkModeVPC {
+			t.Errorf("runtime state = %+v, want a replaced VPC runtime with a new ARN", r)
+		}
+	}
+	if !found {
+		t.Error("no runtime in state")
+	}
+}
@@ -0,0 +1,328 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// resourceType describes one kind of resource the adapter manages. Plan,
+// Apply, Destroy, and Status take their ordering and per-type behavior
+// from resourceTypes, so a new kind of resource is added by registering
+// it there rather than by editing each of them.
+type resourceType struct {
+	name string
+
+	// dependsOn lists the types that must be applied first. Destroy
+	// deletes a type before the types it depends on.
+	dependsOn []string
+
+	// deleteAfter lists types that must be deleted before this one. It
+	// overrides dependsOn for types that must outlive a dependent, such as
+	// a policy engine that stays associated with the gateway.
+	deleteAfter []string
+
+	// plan returns the type's desired changes. desired holds the changes
+	// planned for the types applied before it.
+	plan planFunc
+
+	// apply deploys the type and appends its state to resources. The
+	// third result is non-nil when the progress callback aborted Apply.
+	apply func(
+		ctx context.Context, ac *applyContext, resources []ResourceState, applyErr error,
+	) ([]ResourceState, error, error)
+
+	// restore, when set, restores the config later phases read from the
+	// type's resources when Apply skips its phase and carries them over.
+	restore func(ac *applyContext, carried []ResourceState)
+
+	// remove and check delete and health-check one resource of the type.
+	remove func(c *realAWSClient, ctx context.Context, res ResourceState) error
+	check  func(c *realAWSClient, ctx context.Context, res ResourceState) (string, error)
+}
+
+// planFunc returns a type's desired changes, given the changes planned for
+// the types applied before it.
+type planFunc func(pack *prompt.Pack, cfg *Config, desired []deploy.ResourceChange) []deploy.ResourceChange
+
+// planPack adapts a generator that only reads the pack.
+func planPack(generate func(*prompt.Pack) []deploy.ResourceChange) planFunc {
+	return func(pack *prompt.Pack, _ *Config, _ []deploy.ResourceChange) []deploy.ResourceChange {
+		return generate(pack)
+	}
+}
+
+// planPackConfig adapts a generator that reads the pack and config.
+func planPackConfig(generate func(*prompt.Pack, *Config) []deploy.ResourceChange) planFunc {
+	return func(pack *prompt.Pack, cfg *Config, _ []deploy.ResourceChange) []deploy.ResourceChange {
+		return generate(pack, cfg)
+	}
+}
+
+// planPerRuntime adapts a generator that derives changes from the planned
+// runtimes.
+func planPerRuntime(generate func([]deploy.ResourceChange, *Config) []deploy.ResourceChange) planFunc {
+	return func(_ *prompt.Pack, cfg *Config, desired []deploy.ResourceChange) []deploy.ResourceChange {
+		return generate(desired, cfg)
+	}
+}
+
+// resourceTypes registers every resource type. Registration order breaks
+// ties between types the dependency graph leaves unordered.
+var resourceTypes = []resourceType{
+	{
+		name:    ResTypeMemory,
+		plan:    planPackConfig(generateMemoryResources),
+		apply:   applyMemory,
+		restore: restoreMemoryID,
+		remove:  (*realAWSClient).deleteMemory,
+		check:   (*realAWSClient).checkMemory,
+	},
+	{
+		name:    ResTypeInferenceProfile,
+		plan:    planPackConfig(generateInferenceProfileResources),
+		apply:   applyInferenceProfiles,
+		restore: restoreInferenceProfiles,
+		remove:  (*realAWSClient).deleteInferenceProfile,
+		check:   (*realAWSClient).checkInferenceProfile,
+	},
+	{
+		name:    ResTypeIdentityProvider,
+		plan:    planPackConfig(generateIdentityProviderResources),
+		apply:   applyIdentityProviders,
+		restore: restoreIdentityProviderARNs,
+		remove:  (*realAWSClient).deleteIdentityProvider,
+		check:   (*realAWSClient).checkIdentityProvider,
+	},
+	{
+		// Gateway targets obtain their OAuth tokens from identity
+		// providers.
+		name:      ResTypeToolGateway,
+		dependsOn: []string{ResTypeIdentityProvider},
+		plan:      planPackConfig(generateToolGatewayResources),
+		apply:     applyToolGateways,
+		restore:   restoreGatewayARN,
+		remove:    (*realAWSClient).deleteGateway,
+		check: func(c *realAWSClient, ctx context.Context, res ResourceState) (string, error) {
+			status, _, err := c.checkGateway(ctx, res)
+			return status, err
+		},
+	},
+	{
+		name:        ResTypeCedarPolicy,
+		dependsOn:   []string{ResTypeToolGateway},
+		deleteAfter: []string{ResTypeToolGateway},
+		plan:        planPack(generateCedarPolicyResources),
+		apply:       applyCedarPolicies,
+		restore:     restorePolicyEngineARNs,
+		remove:      (*realAWSClient).deleteCedarPolicy,
+		check:       (*realAWSClient).checkCedarPolicy,
+	},
+	{
+		name:      ResTypeAgentRuntime,
+		dependsOn: []string{ResTypeMemory, ResTypeInferenceProfile, ResTypeCedarPolicy},
+		plan:      planPack(planAgentRuntimes),
+		apply:     applyAgentRuntimes,
+		remove:    (*realAWSClient).deleteRuntime,
+		check:     (*realAWSClient).checkRuntime,
+	},
+	{
+		name:      ResTypeA2AEndpoint,
+		dependsOn: []string{ResTypeAgentRuntime},
+		plan:      planPack(planA2AResources),
+		apply:     applyA2AEndpoints,
+		remove: func(_ *realAWSClient, _ context.Context, res ResourceState) error {
+			logf("agentcore: a2a_endpoint %q goes with its runtime; skipping delete", res.Name)
+			return nil
+		},
+		check: func(c *realAWSClient, ctx context.Context, res ResourceState) (string, error) {
+			status, _, err := c.checkA2AWiring(ctx, res)
+			return status, err
+		},
+	},
+	{
+		// Endpoints point at each runtime's current version, which A2A
+		// wiring may bump.
+		name:      ResTypeRuntimeEndpoint,
+		dependsOn: []string{ResTypeAgentRuntime, ResTypeA2AEndpoint},
+		plan:      planPerRuntime(generateEndpointResources),
+		apply:     applyRuntimeEndpoints,
+		remove:    (*realAWSClient).deleteRuntimeEndpoint,
+		check:     (*realAWSClient).checkRuntimeEndpoint,
+	},
+	{
+		// Log groups are named after the invoked endpoint, and outlive the
+		// runtime so it cannot recreate them without retention.
+		name:        ResTypeLogGroup,
+		dependsOn:   []string{ResTypeRuntimeEndpoint},
+		deleteAfter: []string{ResTypeRuntimeEndpoint, ResTypeAgentRuntime},
+		plan:        planPerRuntime(generateLogGroupResources),
+		apply:       applyRuntimeLogGroups,
+		remove:      (*realAWSClient).deleteLogGroup,
+		check:       (*realAWSClient).checkLogGroup,
+	},
+	{
+		name:      ResTypeEvaluator,
+		dependsOn: []string{ResTypeInferenceProfile},
+		plan:      planPack(generateEvalResources),
+		apply:     applyEvaluators,
+		remove:    (*realAWSClient).deleteEvaluator,
+		check:     (*realAWSClient).checkEvaluator,
+	},
+	{
+		name:      ResTypeOnlineEvalConfig,
+		dependsOn: []string{ResTypeEvaluator, ResTypeAgentRuntime},
+		plan:      planPackConfig(generateOnlineEvalConfigResources),
+		apply:     applyOnlineEvalConfig,
+		remove:    (*realAWSClient).deleteOnlineEvalConfig,
+		check:     (*realAWSClient).checkOnlineEvalConfig,
+	},
+	{
+		// Alerts subscribe to the log group the online evaluation config
+		// writes its results to.
+		name:      ResTypeEvalAlert,
+		dependsOn: []string{ResTypeOnlineEvalConfig},
+		plan:      planEvalAlert,
+		apply:     applyEvalAlert,
+		remove:    (*realAWSClient).deleteEvalAlert,
+		check:     (*realAWSClient).checkEvalAlert,
+	},
+	{
+		// Saved queries read the log groups of the invoked runtime
+		// endpoints and of the online evaluation results.
+		name:      ResTypeLogsQuery,
+		dependsOn: []string{ResTypeRuntimeEndpoint, ResTypeOnlineEvalConfig},
+		plan:      planLogsQueries,
+		apply:     applyLogsQueries,
+		remove:    (*realAWSClient).deleteLogsQuery,
+		check:     (*realAWSClient).checkLogsQuery,
+	},
+}
+
+// applyOrder lists the registered types in the order Apply deploys them.
+var applyOrder = sortResourceTypes(resourceTypes, applyEdges, false)
+
+// destroyOrder lists the registered types in the order Destroy deletes
+// them.
+var destroyOrder = typeNames(sortResourceTypes(resourceTypes, deleteEdges, true))
+
+// applyEdges returns the types that must be applied before t.
+func applyEdges(t resourceType, _ []resourceType) []string {
+	return t.dependsOn
+}
+
+// deleteEdges returns the types that must be deleted before t: those that
+// depend on it, unless they must outlive it, and those in t.deleteAfter.
+func deleteEdges(t resourceType, all []resourceType) []string {
+	var before []string
+	for _, other := range all {
+		if slices.Contains(other.dependsOn, t.name) && !slices.Contains(other.deleteAfter, t.name) {
+			before = append(before, other.name)
+		}
+	}
+	for _, name := range t.deleteAfter {
+		if !slices.Contains(before, name) {
+			before = append(before, name)
+		}
+	}
+	return before
+}
+
+// sortResourceTypes orders types so each comes after the types edges
+// returns for it. Among types that are ready at the same time it takes
+// the one registered first, or last when preferLast is set, so that Apply
+// follows registration order and Destroy its reverse wherever the graph
+// allows. It panics on a cycle or an unknown type, which are programming
+// errors.
+func sortResourceTypes(
+	all []resourceType, edges func(resourceType, []resourceType) []string, preferLast bool,
+) []resourceType {
+	done := make(map[string]bool, len(all))
+	sorted := make([]resourceType, 0, len(all))
+	for len(sorted) < len(all) {
+		next := nextReadyType(all, edges, done, preferLast)
+		if next == -1 {
+			panic("agentcore: resource type dependencies contain a cycle")
+		}
+		done[all[next].name] = true
+		sorted = append(sorted, all[next])
+	}
+	return sorted
+}
+
+// nextReadyType returns the index of the first, or last, type not yet done
+// whose edges are all done, or -1 when there is none.
+func nextReadyType(
+	all []resourceType, edges func(resourceType, []resourceType) []string,
+	done map[string]bool, preferLast bool,
+) int {
+	next := -1
+	for i, t := range all {
+		if done[t.name] || !allDone(edges(t, all), done, all) {
+			continue
+		}
+		if !preferLast {
+			return i
+		}
+		next = i
+	}
+	return next
+}
+
+// allDone reports whether every named type is done.
+func allDone(names []string, done map[string]bool, all []resourceType) bool {
+	for _, name := range names {
+		if !slices.ContainsFunc(all, func(t resourceType) bool { return t.name == name }) {
+			panic(fmt.Sprintf("agentcore: unknown resource type %q in dependencies", name))
+		}
+		if !done[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// typeNames returns the names of types in order.
+func typeNames(types []resourceType) []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.name
+	}
+	return names
+}
+
+// lookupResourceType returns the registered type with the given name.
+func lookupResourceType(name string) (resourceType, bool) {
+	i := slices.IndexFunc(resourceTypes, func(t resourceType) bool { return t.name == name })
+	if i < 0 {
+		return resourceType{}, false
+	}
+	return resourceTypes[i], true
+}
+
+// planAgentRuntimes returns the agent_runtime changes of
+// generateAgentResources.
+func planAgentRuntimes(pack *prompt.Pack) []deploy.ResourceChange {
+	var changes []deploy.ResourceChange
+	for _, c := range generateAgentResources(pack) {
+		if c.Type == ResTypeAgentRuntime {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// planA2AResources returns the rest of generateAgentResources: the A2A
+// endpoints of a multi-agent pack and the entry gateway that fronts them.
+func planA2AResources(pack *prompt.Pack) []deploy.ResourceChange {
+	var changes []deploy.ResourceChange
+	for _, c := range generateAgentResources(pack) {
+		if c.Type != ResTypeAgentRuntime {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
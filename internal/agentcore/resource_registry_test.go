@@ -0,0 +1,75 @@
+package agentcore
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApplyOrder(t *testing.T) {
+	want := []string{
+		ResTypeMemory, ResTypeInferenceProfile, ResTypeIdentityProvider, ResTypeToolGateway, ResTypeCedarPolicy,
+		ResTypeAgentRuntime, ResTypeA2AEndpoint, ResTypeRuntimeEndpoint, ResTypeLogGroup,
+		ResTypeEvaluator, ResTypeOnlineEvalConfig, ResTypeEvalAlert, ResTypeLogsQuery,
+	}
+	if got := typeNames(applyOrder); !slices.Equal(got, want) {
+		t.Errorf("applyOrder = %v, want %v", got, want)
+	}
+	for i, rt := range applyOrder {
+		for _, dep := range rt.dependsOn {
+			if slices.Index(typeNames(applyOrder), dep) > i {
+				t.Errorf("%s is applied before its dependency %s", rt.name, dep)
+			}
+		}
+	}
+}
+
+func TestDestroyOrder(t *testing.T) {
+	if len(destroyOrder) != len(resourceTypes) {
+		t.Fatalf("destroyOrder = %v, want every registered type", destroyOrder)
+	}
+	before := func(a, b string) bool {
+		return slices.Index(destroyOrder, a) < slices.Index(destroyOrder, b)
+	}
+	for _, tt := range []struct{ first, then string }{
+		{ResTypeEvalAlert, ResTypeOnlineEvalConfig},
+		{ResTypeLogsQuery, ResTypeOnlineEvalConfig},
+		{ResTypeLogsQuery, ResTypeRuntimeEndpoint},
+		{ResTypeOnlineEvalConfig, ResTypeEvaluator},
+		{ResTypeA2AEndpoint, ResTypeAgentRuntime},
+		{ResTypeAgentRuntime, ResTypeMemory},
+		{ResTypeAgentRuntime, ResTypeInferenceProfile},
+		{ResTypeToolGateway, ResTypeIdentityProvider},
+		// Overridden by deleteAfter: the gateway goes before the policy
+		// engine associated with it, and the runtime before its log group.
+		{ResTypeToolGateway, ResTypeCedarPolicy},
+		{ResTypeAgentRuntime, ResTypeLogGroup},
+	} {
+		if !before(tt.first, tt.then) {
+			t.Errorf("%s must be deleted before %s: %v", tt.first, tt.then, destroyOrder)
+		}
+	}
+}
+
+func TestSortResourceTypes_PanicsOnCycle(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "cycle") {
+			t.Errorf("recover() = %v, want a cycle panic", r)
+		}
+	}()
+	sortResourceTypes([]resourceType{
+		{name: "a", dependsOn: []string{"b"}},
+		{name: "b", dependsOn: []string{"a"}},
+	}, applyEdges, false)
+}
+
+func TestRegisteredTypesAreComplete(t *testing.T) {
+	for _, rt := range resourceTypes {
+		if rt.plan == nil || rt.apply == nil || rt.remove == nil || rt.check == nil {
+			t.Errorf("resource type %s is missing a plan, apply, remove, or check function", rt.name)
+		}
+	}
+	if _, ok := lookupResourceType("unknown"); ok {
+		t.Error("lookupResourceType(unknown) found a type")
+	}
+}
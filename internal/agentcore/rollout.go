@@ -0,0 +1,167 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+)
+
+// Rollout strategies for updating the member runtimes of a multi-agent
+// pack.
+const (
+	// RolloutRolling updates members one at a time, health-checking each
+	// before the next, and stops at the first unhealthy member.
+	RolloutRolling = "rolling"
+
+	// RolloutAllAtOnce updates every member in turn without health checks,
+	// carrying on past failures.
+	RolloutAllAtOnce = "all_at_once"
+)
+
+// rolloutSessionPrefix starts the session ID of rollout health-check
+// invocations, which AgentCore requires to be at least 33 characters.
+const rolloutSessionPrefix = "promptarena-rollout-"
+
+// rolloutStoppedDetail is the detail of members a stopped rollout left on
+// their prior version.
+const rolloutStoppedDetail = "rollout stopped by an earlier unhealthy member"
+
+// RolloutConfig controls how Apply updates the member runtimes of a
+// multi-agent pack.
+type RolloutConfig struct {
+	// Strategy is "rolling" (the default) or "all_at_once".
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// validateRollout checks the rollout block.
+func validateRollout(c *RolloutConfig) []string {
+	if c == nil {
+		return nil
+	}
+	switch c.Strategy {
+	case "", RolloutRolling, RolloutAllAtOnce:
+		return nil
+	}
+	return []string{fmt.Sprintf("rollout.strategy %q must be %q or %q", c.Strategy, RolloutRolling, RolloutAllAtOnce)}
+}
+
+// rollingUpdate reports whether the members of multi-agent packs are
+// updated one at a time behind health checks.
+func (c *Config) rollingUpdate() bool {
+	return c.Rollout == nil || c.Rollout.Strategy != RolloutAllAtOnce
+}
+
+// rolloutHealthCheck verifies that an updated runtime serves traffic.
+// UpdateRuntime has already waited for it to be READY; check sends it a
+// synthetic invocation.
+type rolloutHealthCheck struct {
+	invoker runtimeInvoker // nil checks READY only
+}
+
+// newRolloutHealthCheck creates the health check, falling back to READY
+// only, with a warning, when runtimes cannot be invoked.
+func newRolloutHealthCheck(
+	ctx context.Context, newInvoker runtimeInvokerFactory, reporter *adaptersdk.ProgressReporter, cfg *Config,
+) (*rolloutHealthCheck, error) {
+	if newInvoker == nil {
+		return &rolloutHealthCheck{}, nil
+	}
+	invoker, err := newInvoker(ctx, cfg)
+	if err != nil {
+		msg := "Warning: rollout health checks cannot invoke runtimes, checking READY only: " + err.Error()
+		return &rolloutHealthCheck{}, reporter.Progress(msg, progressNoPercent)
+	}
+	return &rolloutHealthCheck{invoker: invoker}, nil
+}
+
+// check invokes the runtime at arn with the health prompt.
+func (h *rolloutHealthCheck) check(ctx context.Context, arn string) error {
+	if h.invoker == nil {
+		return nil
+	}
+	sessionID := rolloutSessionPrefix + time.Now().UTC().Format("20060102T150405.000000000Z")
+	if _, err := h.invoker.Invoke(ctx, arn, sessionID, []byte(prewarmPayload)); err != nil {
+		return fmt.Errorf("health check invocation: %w", err)
+	}
+	return nil
+}
+
+// applyRuntimesRolling creates or updates the runtimes of names one at a
+// time. After each update it health-checks the runtime; on the first
+// failure, whether of the update or of its health check, it stops and
+// leaves the remaining members on their prior version, returning their
+// prior state as carried. Members updated before the failure keep serving.
+func applyRuntimesRolling(
+	ctx context.Context, ac *applyContext, names []string,
+) (result applyPhaseResult, carried []ResourceState) {
+	health, err := newRolloutHealthCheck(ctx, ac.newInvoker, ac.reporter, ac.cfg)
+	if err != nil {
+		result.callbackErr = err
+		return result, nil
+	}
+	for i, name := range names {
+		step := applyPhase(ctx, ac.reporter, ac.client.CreateRuntime, ac.client.UpdateRuntime, ac.replaceWith(),
+			ac.cfg, []string{name}, ResTypeAgentRuntime, ac.span, ac.priorMap)
+		result.resources = append(result.resources, step.resources...)
+		result.err = combineErrors(result.err, step.err)
+		if step.callbackErr != nil {
+			result.callbackErr = step.callbackErr
+			return result, nil
+		}
+		if step.err == nil {
+			step.err = checkUpdatedRuntime(ctx, ac.reporter, health, result.resources)
+			result.err = combineErrors(result.err, step.err)
+		}
+		if step.err != nil {
+			carried, result.callbackErr = skipRemainingRuntimes(ac, names[i+1:])
+			return result, carried
+		}
+	}
+	return result, nil
+}
+
+// checkUpdatedRuntime health-checks the last runtime in resources when the
+// rollout updated or replaced it, marking it failed when unhealthy.
+func checkUpdatedRuntime(
+	ctx context.Context, reporter *adaptersdk.ProgressReporter, health *rolloutHealthCheck, resources []ResourceState,
+) error {
+	r := &resources[len(resources)-1]
+	if r.Status != ResStatusUpdated && r.Status != ResStatusReplaced {
+		return nil
+	}
+	verb := "update"
+	if r.Status == ResStatusReplaced {
+		verb = "replace"
+	}
+	if err := health.check(ctx, r.ARN); err != nil {
+		r.Status = ResStatusFailed
+		deployErr := newDeployError(verb, ResTypeAgentRuntime, r.Name, err)
+		_ = reporter.Error(deployErr)
+		return deployErr
+	}
+	return nil
+}
+
+// skipRemainingRuntimes reports the members a stopped rollout did not
+// touch and returns their prior state, unchanged. The error is non-nil
+// when the progress callback aborted Apply.
+func skipRemainingRuntimes(ac *applyContext, names []string) ([]ResourceState, error) {
+	var carried []ResourceState
+	for _, name := range names {
+		if err := ac.reporter.Resource(&deploy.ResourceResult{
+			Type: ResTypeAgentRuntime, Name: name, Action: deploy.ActionNoChange,
+			Status: ResStatusSkipped, Detail: rolloutStoppedDetail,
+		}); err != nil {
+			return carried, err
+		}
+		key := resourceKey(ResTypeAgentRuntime, name)
+		if prior, ok := ac.priorMap[key]; ok {
+			carried = append(carried, prior)
+			ac.carried[key] = true
+		}
+	}
+	return carried, nil
+}
@@ -0,0 +1,178 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// unhealthyRuntimeInvoker fails the invocations of the runtime ARNs in
+// unhealthy and records every ARN it is asked to invoke.
+type unhealthyRuntimeInvoker struct {
+	unhealthy map[string]bool
+	arns      []string
+}
+
+func (f *unhealthyRuntimeInvoker) Invoke(_ context.Context, runtimeARN, sessionID string, _ []byte) ([]byte, error) {
+	if len(sessionID) < 33 {
+		return nil, errors.New("session ID too short")
+	}
+	f.arns = append(f.arns, runtimeARN)
+	if f.unhealthy[runtimeARN] {
+		return nil, errors.New("runtime returned 502")
+	}
+	return []byte(`{"response":"OK"}`), nil
+}
+
+// deployMultiAgent applies the multi-agent pack without health checks and
+// returns its state and the runtime ARNs by member name.
+func deployMultiAgent(t *testing.T, p *Provider, deployConfig string) (string, map[string]string) {
+	t.Helper()
+	_, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: multiAgentPack(), DeployConfig: deployConfig, ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("initial Apply: %v", err)
+	}
+	return stateJSON, runtimeARNs(t, stateJSON)
+}
+
+func runtimeARNs(t *testing.T, stateJSON string) map[string]string {
+	t.Helper()
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	arns := make(map[string]string)
+	for _, r := range state.Resources {
+		if r.Type == ResTypeAgentRuntime {
+			arns[r.Name] = r.ARN
+		}
+	}
+	return arns
+}
+
+func runtimeStatuses(t *testing.T, stateJSON string) map[string]string {
+	t.Helper()
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, r := range state.Resources {
+		if r.Type == ResTypeAgentRuntime {
+			statuses[r.Name] = r.Status
+		}
+	}
+	return statuses
+}
+
+func TestValidateRollout(t *testing.T) {
+	for _, tt := range []struct {
+		cfg     *RolloutConfig
+		wantErr bool
+	}{
+		{cfg: nil},
+		{cfg: &RolloutConfig{}},
+		{cfg: &RolloutConfig{Strategy: RolloutRolling}},
+		{cfg: &RolloutConfig{Strategy: RolloutAllAtOnce}},
+		{cfg: &RolloutConfig{Strategy: "blue_green"}, wantErr: true},
+	} {
+		if errs := validateRollout(tt.cfg); (len(errs) > 0) != tt.wantErr {
+			t.Errorf("validateRollout(%+v) = %v, want error %t", tt.cfg, errs, tt.wantErr)
+		}
+	}
+}
+
+func TestApply_RollingUpdateHealthChecksEachMember(t *testing.T) {
+	p := newSimulatedProvider()
+	cfg := validConfig(t)
+	prior, arns := deployMultiAgent(t, p, cfg)
+
+	invoker := &unhealthyRuntimeInvoker{}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+	_, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: multiAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	want := []string{arns["coordinator"], arns["worker"]}
+	if strings.Join(invoker.arns, ",") != strings.Join(want, ",") {
+		t.Errorf("health checks invoked %v, want %v", invoker.arns, want)
+	}
+	for name, status := range runtimeStatuses(t, stateJSON) {
+		if status != ResStatusUpdated {
+			t.Errorf("runtime %s status = %q, want updated", name, status)
+		}
+	}
+}
+
+func TestApply_RollingUpdateStopsAtUnhealthyMember(t *testing.T) {
+	p := newSimulatedProvider()
+	cfg := validConfig(t)
+	prior, arns := deployMultiAgent(t, p, cfg)
+
+	invoker := &unhealthyRuntimeInvoker{unhealthy: map[string]bool{arns["coordinator"]: true}}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+	events, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: multiAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err == nil || !strings.Contains(err.Error(), "health check") {
+		t.Fatalf("Apply error = %v, want a health check failure", err)
+	}
+	if len(invoker.arns) != 1 {
+		t.Errorf("health checks invoked %v, want only the coordinator", invoker.arns)
+	}
+
+	statuses := runtimeStatuses(t, stateJSON)
+	if statuses["coordinator"] != ResStatusFailed {
+		t.Errorf("coordinator status = %q, want failed", statuses["coordinator"])
+	}
+	if statuses["worker"] != ResStatusCreated {
+		t.Errorf("worker status = %q, want its prior status created", statuses["worker"])
+	}
+	if got := runtimeARNs(t, stateJSON)["worker"]; got != arns["worker"] {
+		t.Errorf("worker ARN = %q, want prior %q", got, arns["worker"])
+	}
+
+	var skipped bool
+	for _, ev := range events {
+		if ev.Resource != nil && ev.Resource.Name == "worker" && ev.Resource.Type == ResTypeAgentRuntime {
+			if ev.Resource.Status != ResStatusSkipped {
+				t.Errorf("worker event status = %q, want skipped", ev.Resource.Status)
+			}
+			skipped = true
+		}
+	}
+	if !skipped {
+		t.Error("no skipped event for the worker runtime")
+	}
+}
+
+func TestApply_AllAtOnceSkipsHealthChecks(t *testing.T) {
+	p := newSimulatedProvider()
+	cfg := strings.TrimSuffix(validConfig(t), "}") + `,"rollout":{"strategy":"all_at_once"}}`
+	prior, arns := deployMultiAgent(t, p, cfg)
+
+	invoker := &unhealthyRuntimeInvoker{unhealthy: map[string]bool{arns["coordinator"]: true}}
+	p.invokerFunc = runtimeInvokerFor(invoker)
+	_, stateJSON, err := collectEvents(t, p, &deploy.PlanRequest{
+		PackJSON: multiAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(invoker.arns) != 0 {
+		t.Errorf("all_at_once invoked %v, want no health checks", invoker.arns)
+	}
+	for name, status := range runtimeStatuses(t, stateJSON) {
+		if status != ResStatusUpdated {
+			t.Errorf("runtime %s status = %q, want updated", name, status)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// defaultEndpointName is the endpoint AgentCore creates for every runtime.
+// It always tracks the latest version, so it cannot be managed as a
+// runtime_endpoint.
+const defaultEndpointName = "DEFAULT"
+
+// endpointNameRE matches AgentCore runtime endpoint names.
+var endpointNameRE = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,47}$`)
+
+// Runtime endpoint metadata keys stored in ResourceState.Metadata.
+const (
+	metaQualifier      = "qualifier"
+	metaRuntimeARN     = "runtime_arn"
+	metaRuntimeVersion = "runtime_version"
+)
+
+// Output key suffixes for runtime endpoints, prefixed with the agent name.
+const (
+	outputInvocationARN = ".invocation_arn"
+	outputQualifier     = ".qualifier"
+)
+
+// validateRuntimeEndpoint checks the runtime_endpoint name.
+func validateRuntimeEndpoint(name string) []string {
+	switch {
+	case name == "":
+		return nil
+	case name == defaultEndpointName:
+		return []string{fmt.Sprintf("runtime_endpoint %q is reserved by AgentCore", name)}
+	case !endpointNameRE.MatchString(name):
+		return []string{fmt.Sprintf(
+			"runtime_endpoint %q must start with a letter and contain only letters, digits, "+
+				"and underscores (max 48 chars)", name)}
+	}
+	return nil
+}
+
+// generateEndpointResources returns one runtime_endpoint change per planned
+// agent runtime when runtime_endpoint is configured.
+func generateEndpointResources(desired []deploy.ResourceChange, cfg *Config) []deploy.ResourceChange {
+	if cfg.RuntimeEndpoint == "" {
+		return nil
+	}
+	var endpoints []deploy.ResourceChange
+	for _, d := range desired {
+		if d.Type != ResTypeAgentRuntime {
+			continue
+		}
+		endpoints = append(endpoints, deploy.ResourceChange{
+			Type:   ResTypeRuntimeEndpoint,
+			Name:   d.Name,
+			Action: deploy.ActionCreate,
+			Detail: fmt.Sprintf("Create runtime endpoint %s for %s", cfg.RuntimeEndpoint, d.Name),
+		})
+	}
+	return endpoints
+}
+
+// applyRuntimeEndpoints points the configured endpoint of every deployed
+// runtime at the runtime's current version. It runs after A2A injection,
+// which may publish a new version of the entry runtime.
+func applyRuntimeEndpoints(
+	ctx context.Context, ac *applyContext,
+	resources []ResourceState, applyErr error,
+) ([]ResourceState, error, error) {
+	if ac.cfg.RuntimeEndpoint == "" {
+		return resources, applyErr, nil
+	}
+	var runtimes []ResourceState
+	for _, r := range resources {
+		if r.Type == ResTypeAgentRuntime && r.ARN != "" && r.Status != ResStatusFailed {
+			runtimes = append(runtimes, r)
+		}
+	}
+
+	for i, rt := range runtimes {
+		pct := ac.span.at(i, len(runtimes))
+		op := resolveOp(ResTypeRuntimeEndpoint, rt.Name, true, ac.priorMap)
+
+		if err := ac.reporter.Progress(
+			fmt.Sprintf("%s %s: %s", op.verb, ResTypeRuntimeEndpoint, rt.Name), pct,
+		); err != nil {
+			return resources, applyErr, err
+		}
+
+		res, err := deployRuntimeEndpoint(ctx, ac, rt, op)
+		if err != nil {
+			deployErr := newDeployError(op.failVerb, ResTypeRuntimeEndpoint, rt.Name, err)
+			_ = ac.reporter.Error(deployErr)
+			resources = append(resources, ResourceState{
+				Type: ResTypeRuntimeEndpoint, Name: rt.Name, Status: ResStatusFailed,
+			})
+			applyErr = combineErrors(applyErr, deployErr)
+			continue
+		}
+
+		if err := ac.reporter.Resource(&deploy.ResourceResult{
+			Type: ResTypeRuntimeEndpoint, Name: rt.Name, Action: op.action,
+			Status: op.status, Detail: res.ARN,
+		}); err != nil {
+			return resources, applyErr, err
+		}
+		resources = append(resources, res)
+	}
+	return resources, applyErr, nil
+}
+
+// deployRuntimeEndpoint creates or repoints one runtime's endpoint.
+func deployRuntimeEndpoint(
+	ctx context.Context, ac *applyContext, rt ResourceState, op resourceOp,
+) (ResourceState, error) {
+	arn, version, err := ac.client.DeployRuntimeEndpoint(ctx, rt.ARN, ac.cfg.RuntimeEndpoint, ac.cfg)
+	if err != nil {
+		return ResourceState{}, err
+	}
+	return ResourceState{
+		Type:   ResTypeRuntimeEndpoint,
+		Name:   rt.Name,
+		ARN:    arn,
+		Status: op.status,
+		Metadata: map[string]string{
+			metaQualifier:      ac.cfg.RuntimeEndpoint,
+			metaRuntimeARN:     rt.ARN,
+			metaRuntimeVersion: version,
+		},
+	}, nil
+}
+
+// buildOutputs collects the values clients need to invoke the deployment:
+// the qualified invocation ARN and qualifier of each runtime endpoint.
+func buildOutputs(resources []ResourceState) map[string]string {
+	outputs := make(map[string]string)
+	for _, r := range resources {
+		if r.Type != ResTypeRuntimeEndpoint || r.ARN == "" {
+			continue
+		}
+		outputs[r.Name+outputInvocationARN] = r.ARN
+		outputs[r.Name+outputQualifier] = r.Metadata[metaQualifier]
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+	return outputs
+}
+
+// endpointRuntimeID returns the ID of the runtime an endpoint belongs to.
+func endpointRuntimeID(res ResourceState) string {
+	return extractResourceID(res.Metadata[metaRuntimeARN], "runtime")
+}
+
+// DeployRuntimeEndpoint points the named endpoint of a runtime at the
+// runtime's current version, creating the endpoint if needed, and polls
+// until it is READY. It returns the endpoint ARN and the version it serves.
+func (c *realAWSClient) DeployRuntimeEndpoint(
+	ctx context.Context, runtimeARN, endpointName string, cfg *Config,
+) (string, string, error) {
+	runtimeID := extractResourceID(runtimeARN, "runtime")
+	if runtimeID == "" {
+		return "", "", fmt.Errorf("endpoint %q: could not extract runtime ID from ARN %q", endpointName, runtimeARN)
+	}
+	rt, err := c.client.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
+		AgentRuntimeId: aws.String(runtimeID),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("GetAgentRuntime %q: %w", runtimeID, err)
+	}
+	version := aws.ToString(rt.AgentRuntimeVersion)
+
+	arn, err := c.upsertRuntimeEndpoint(ctx, runtimeID, endpointName, version, cfg)
+	if err != nil {
+		return "", "", err
+	}
+	if err := c.waitForRuntimeEndpointReady(ctx, runtimeID, endpointName); err != nil {
+		return arn, version, fmt.Errorf("endpoint %q not ready: %w", endpointName, err)
+	}
+	return arn, version, nil
+}
+
+// PinRuntimeEndpoint points the named endpoint of a runtime at the given
+// runtime version, such as the one a rollback returns to, and polls until
+// it is READY. It returns the endpoint ARN.
+func (c *realAWSClient) PinRuntimeEndpoint(
+	ctx context.Context, runtimeARN, endpointName, version string, cfg *Config,
+) (string, error) {
+	runtimeID := extractResourceID(runtimeARN, "runtime")
+	if runtimeID == "" {
+		return "", fmt.Errorf("endpoint %q: could not extract runtime ID from ARN %q", endpointName, runtimeARN)
+	}
+	arn, err := c.upsertRuntimeEndpoint(ctx, runtimeID, endpointName, version, cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := c.waitForRuntimeEndpointReady(ctx, runtimeID, endpointName); err != nil {
+		return arn, fmt.Errorf("endpoint %q not ready: %w", endpointName, err)
+	}
+	return arn, nil
+}
+
+// upsertRuntimeEndpoint updates the endpoint if it exists, else creates it.
+func (c *realAWSClient) upsertRuntimeEndpoint(
+	ctx context.Context, runtimeID, endpointName, version string, cfg *Config,
+) (string, error) {
+	_, err := c.client.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(runtimeID),
+		EndpointName:   aws.String(endpointName),
+	})
+	if err == nil {
+		input := &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
+			AgentRuntimeId:      aws.String(runtimeID),
+			EndpointName:        aws.String(endpointName),
+			AgentRuntimeVersion: aws.String(version),
+		}
+		out, updateErr := c.client.UpdateAgentRuntimeEndpoint(ctx, input)
+		if updateErr != nil {
+			return "", fmt.Errorf("UpdateAgentRuntimeEndpoint %q: %w", endpointName, updateErr)
+		}
+		return aws.ToString(out.AgentRuntimeEndpointArn), nil
+	}
+	if !isNotFound(err) {
+		return "", fmt.Errorf("GetAgentRuntimeEndpoint %q: %w", endpointName, err)
+	}
+
+	input := &bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput{
+		AgentRuntimeId:      aws.String(runtimeID),
+		Name:                aws.String(endpointName),
+		AgentRuntimeVersion: aws.String(version),
+	}
+	if len(cfg.ResourceTags) > 0 {
+		input.Tags = cfg.ResourceTags
+	}
+	out, err := c.client.CreateAgentRuntimeEndpoint(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("CreateAgentRuntimeEndpoint %q: %w", endpointName, err)
+	}
+	return aws.ToString(out.AgentRuntimeEndpointArn), nil
+}
+
+// waitForRuntimeEndpointReady polls GetAgentRuntimeEndpoint until the
+// status is READY or a terminal failure state.
+func (c *realAWSClient) waitForRuntimeEndpointReady(ctx context.Context, runtimeID, name string) error {
+	tracker := c.newPollTracker("runtime endpoint", runtimeID+"/"+name)
+	for attempt := range c.poll.maxAttempts() {
+		out, err := c.client.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+			AgentRuntimeId: aws.String(runtimeID),
+			EndpointName:   aws.String(name),
+		})
+		if err != nil {
+			return fmt.Errorf("polling runtime endpoint %q: %w", name, err)
+		}
+		switch out.Status {
+		case types.AgentRuntimeEndpointStatusReady:
+			return nil
+		case types.AgentRuntimeEndpointStatusCreateFailed, types.AgentRuntimeEndpointStatusUpdateFailed:
+			reason := ""
+			if out.FailureReason != nil {
+				reason = ": " + *out.FailureReason
+			}
+			return fmt.Errorf("runtime endpoint %q entered status %s%s", name, out.Status, reason)
+		case types.AgentRuntimeEndpointStatusCreating,
+			types.AgentRuntimeEndpointStatusUpdating,
+			types.AgentRuntimeEndpointStatusDeleting:
+			// Transitional states — keep polling.
+		}
+		tracker.waiting(attempt, string(out.Status))
+		c.poll.sleep()
+	}
+	return fmt.Errorf("runtime endpoint %q did not become ready after %d attempts", name, c.poll.maxAttempts())
+}
+
+func (c *realAWSClient) deleteRuntimeEndpoint(ctx context.Context, res ResourceState) error {
+	runtimeID := endpointRuntimeID(res)
+	qualifier := res.Metadata[metaQualifier]
+	if runtimeID == "" || qualifier == "" {
+		return fmt.Errorf("runtime endpoint %q: state is missing runtime ARN or qualifier", res.Name)
+	}
+	_, err := c.client.DeleteAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.DeleteAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(runtimeID),
+		EndpointName:   aws.String(qualifier),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("DeleteAgentRuntimeEndpoint %q: %w", res.Name, err)
+	}
+	return nil
+}
+
+func (c *realAWSClient) checkRuntimeEndpoint(ctx context.Context, res ResourceState) (string, error) {
+	out, err := c.client.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(endpointRuntimeID(res)),
+		EndpointName:   aws.String(res.Metadata[metaQualifier]),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return StatusMissing, nil
+		}
+		return StatusUnhealthy, fmt.Errorf("GetAgentRuntimeEndpoint %q: %w", res.Name, err)
+	}
+	if out.Status == types.AgentRuntimeEndpointStatusReady {
+		return StatusHealthy, nil
+	}
+	return StatusUnhealthy, nil
+}
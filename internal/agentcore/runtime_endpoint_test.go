@@ -0,0 +1,171 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func validConfigWithEndpoint(t *testing.T) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"runtime_endpoint":"live"}`, testBinaryPath(t))
+}
+
+func TestValidateRuntimeEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: ""},
+		{name: "live"},
+		{name: "prod_v2"},
+		{name: "DEFAULT", wantErr: "reserved"},
+		{name: "2live", wantErr: "must start with a letter"},
+		{name: "live-prod", wantErr: "must start with a letter"},
+		{name: strings.Repeat("a", 49), wantErr: "max 48"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateRuntimeEndpoint(tt.name)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlan_RuntimeEndpointPerRuntime(t *testing.T) {
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON:     multiAgentPackJSON(),
+		DeployConfig: validConfigWithEndpoint(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	var runtimes, endpoints []string
+	for _, c := range resp.Changes {
+		switch c.Type {
+		case ResTypeAgentRuntime:
+			runtimes = append(runtimes, c.Name)
+		case ResTypeRuntimeEndpoint:
+			endpoints = append(endpoints, c.Name)
+		}
+	}
+	if len(endpoints) == 0 || strings.Join(endpoints, ",") != strings.Join(runtimes, ",") {
+		t.Errorf("endpoints %v, want one per runtime %v", endpoints, runtimes)
+	}
+}
+
+func TestApply_RuntimeEndpoint_StateAndOutputs(t *testing.T) {
+	provider := newSimulatedProvider()
+	req := &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfigWithEndpoint(t),
+		ArenaConfig:  validArenaConfigJSON,
+	}
+
+	events, stateStr, err := collectEvents(t, provider, req)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateStr), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	ep, ok := findResourceOfType(&state, ResTypeRuntimeEndpoint)
+	if !ok {
+		t.Fatalf("no runtime_endpoint in state: %+v", state.Resources)
+	}
+	if ep.Name != "mypack" || ep.Status != ResStatusCreated {
+		t.Errorf("endpoint = %+v", ep)
+	}
+	if ep.Metadata[metaQualifier] != "live" || ep.Metadata[metaRuntimeVersion] != "1" {
+		t.Errorf("endpoint metadata = %v", ep.Metadata)
+	}
+	if !strings.HasPrefix(ep.ARN, ep.Metadata[metaRuntimeARN]+"/runtime-endpoint/live") {
+		t.Errorf("endpoint ARN %q does not belong to runtime %q", ep.ARN, ep.Metadata[metaRuntimeARN])
+	}
+	if state.Outputs["mypack.invocation_arn"] != ep.ARN || state.Outputs["mypack.qualifier"] != "live" {
+		t.Errorf("outputs = %v", state.Outputs)
+	}
+
+	var sawEvent bool
+	for _, ev := range events {
+		if ev.Type == "resource" && ev.Resource != nil && ev.Resource.Type == ResTypeRuntimeEndpoint {
+			sawEvent = ev.Resource.Detail == ep.ARN
+		}
+	}
+	if !sawEvent {
+		t.Error("expected a runtime_endpoint resource event carrying the endpoint ARN")
+	}
+
+	// Redeploying repoints the existing endpoint.
+	req.PriorState = stateStr
+	_, stateStr, err = collectEvents(t, provider, req)
+	if err != nil {
+		t.Fatalf("second Apply: %v", err)
+	}
+	state = AdapterState{}
+	if err := json.Unmarshal([]byte(stateStr), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	if ep, _ = findResourceOfType(&state, ResTypeRuntimeEndpoint); ep.Status != ResStatusUpdated {
+		t.Errorf("endpoint status on redeploy = %q, want %q", ep.Status, ResStatusUpdated)
+	}
+}
+
+func TestApply_WithoutRuntimeEndpoint_NoOutputs(t *testing.T) {
+	_, stateStr, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if strings.Contains(stateStr, ResTypeRuntimeEndpoint) || strings.Contains(stateStr, `"outputs"`) {
+		t.Errorf("state should have no endpoint or outputs: %s", stateStr)
+	}
+}
+
+func TestApply_RuntimeEndpointFailure(t *testing.T) {
+	provider := &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			return &failingAWSClient{
+				simulatedAWSClient: *newSimulatedAWSClient(cfg.Region),
+				failOn:             map[string]bool{ResTypeRuntimeEndpoint: true},
+			}, nil
+		},
+	}
+	_, stateStr, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfigWithEndpoint(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err == nil || !strings.Contains(err.Error(), ResTypeRuntimeEndpoint) {
+		t.Fatalf("err = %v, want runtime_endpoint failure", err)
+	}
+	var state AdapterState
+	if err := json.Unmarshal([]byte(stateStr), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	ep, ok := findResourceOfType(&state, ResTypeRuntimeEndpoint)
+	if !ok || ep.Status != ResStatusFailed {
+		t.Errorf("endpoint = %+v, want failed", ep)
+	}
+	if state.Outputs != nil {
+		t.Errorf("outputs = %v, want none for a failed endpoint", state.Outputs)
+	}
+}
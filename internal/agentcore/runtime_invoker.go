@@ -0,0 +1,68 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+)
+
+// maxInvokeResponseBytes caps how much of a runtime's response an
+// invocation reads.
+const maxInvokeResponseBytes = 1 << 20
+
+// runtimeInvoker invokes deployed agent runtimes.
+type runtimeInvoker interface {
+	// Invoke sends payload to the runtime and returns its response.
+	Invoke(ctx context.Context, runtimeARN, sessionID string, payload []byte) ([]byte, error)
+}
+
+// runtimeInvokerFactory creates a runtimeInvoker for the given config.
+type runtimeInvokerFactory func(ctx context.Context, cfg *Config) (runtimeInvoker, error)
+
+// newRealRuntimeInvokerFactory is the runtimeInvokerFactory used by
+// NewProvider.
+func newRealRuntimeInvokerFactory(ctx context.Context, cfg *Config) (runtimeInvoker, error) {
+	awsCfg, err := loadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := bedrockagentcore.NewFromConfig(awsCfg, func(o *bedrockagentcore.Options) {
+		o.BaseEndpoint = cfg.baseEndpoint(EndpointBedrockAgentCore)
+	})
+	return &awsRuntimeInvoker{client: client, endpoint: cfg.invokedEndpoint()}, nil
+}
+
+// awsRuntimeInvoker implements runtimeInvoker with the AgentCore data-plane
+// API, invoking runtimes through the endpoint clients use.
+type awsRuntimeInvoker struct {
+	client   *bedrockagentcore.Client
+	endpoint string
+}
+
+// Invoke implements runtimeInvoker. It reads the whole response, up to
+// maxInvokeResponseBytes, so the call returns once the runtime has
+// answered.
+func (c *awsRuntimeInvoker) Invoke(ctx context.Context, runtimeARN, sessionID string, payload []byte) ([]byte, error) {
+	out, err := c.client.InvokeAgentRuntime(ctx, &bedrockagentcore.InvokeAgentRuntimeInput{
+		AgentRuntimeArn:  aws.String(runtimeARN),
+		Qualifier:        aws.String(c.endpoint),
+		RuntimeSessionId: aws.String(sessionID),
+		Payload:          payload,
+		ContentType:      aws.String("application/json"),
+		Accept:           aws.String("application/json"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("InvokeAgentRuntime: %w", err)
+	}
+	defer out.Response.Close()
+	body, err := io.ReadAll(io.LimitReader(out.Response, maxInvokeResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	// Drain the rest so the call still waits for the whole answer.
+	_, err = io.Copy(io.Discard, out.Response)
+	return body, err
+}
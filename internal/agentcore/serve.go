@@ -0,0 +1,296 @@
+package agentcore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+)
+
+// MethodDescribe is the JSON-RPC method that returns a DescribeResponse.
+// It extends the standard adaptersdk method set.
+const MethodDescribe = "describe"
+
+// Line buffer limits, matching adaptersdk so large pack payloads still fit.
+const (
+	maxRPCLineSize    = 10 * 1024 * 1024
+	initialRPCBufSize = 64 * 1024
+)
+
+// rpcEnvelope is the subset of a JSON-RPC request needed for routing.
+type rpcEnvelope struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// rpcResult is a JSON-RPC 2.0 response carrying either a result or an error.
+type rpcResult struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Serve runs the adapter's JSON-RPC server on stdin/stdout.
+func Serve(p *Provider) error {
+	return ServeIO(p, os.Stdin, os.Stdout)
+}
+
+// ServeIO reads JSON-RPC requests line by line from r and writes responses
+// to w. Adapter-specific methods, and apply and destroy so their events
+// carry codes, are answered here; every other line is handed to adaptersdk
+// unchanged so responses stay in request order. The
+// one exception is an apply or promote that waits for approval: it runs in
+// the background so the approve call can be read, and its response is
+// written when it finishes.
+func ServeIO(p *Provider, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, initialRPCBufSize), maxRPCLineSize)
+	out := &lockedWriter{w: w}
+	enc := json.NewEncoder(out)
+	var bg backgroundApplies
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var env rpcEnvelope
+		if json.Unmarshal([]byte(line), &env) == nil {
+			if awaitsApproval(&env) {
+				bg.run(p, &env, out)
+				continue
+			}
+			handled, err := p.serveExtension(enc, &env)
+			if err != nil {
+				return err
+			}
+			if handled {
+				continue
+			}
+		}
+
+		if err := adaptersdk.ServeIO(p, strings.NewReader(line+"\n"), out); err != nil {
+			return err
+		}
+	}
+
+	if err := bg.wait(); err != nil {
+		return err
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("agentcore: read error: %w", err)
+	}
+	return nil
+}
+
+// awaitsApproval reports whether env is an apply or promote whose deploy
+// config requires approval.
+func awaitsApproval(env *rpcEnvelope) bool {
+	if env.Method != adaptersdk.MethodApply && env.Method != MethodPromote {
+		return false
+	}
+	var req struct {
+		DeployConfig string `json:"deploy_config"`
+	}
+	if json.Unmarshal(env.Params, &req) != nil {
+		return false
+	}
+	cfg, err := parseConfig(req.DeployConfig)
+	return err == nil && !cfg.isDryRun() && cfg.requiresApproval()
+}
+
+// backgroundApplies tracks apply requests served off the read loop.
+type backgroundApplies struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs error
+}
+
+// run serves env in a new goroutine.
+func (b *backgroundApplies) run(p *Provider, env *rpcEnvelope, w io.Writer) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		if _, err := p.serveExtension(json.NewEncoder(w), env); err != nil {
+			b.mu.Lock()
+			b.errs = combineErrors(b.errs, err)
+			b.mu.Unlock()
+		}
+	}()
+}
+
+// wait blocks until every background apply has been answered.
+func (b *backgroundApplies) wait() error {
+	b.wg.Wait()
+	return b.errs
+}
+
+// lockedWriter serialises writes so responses from background applies do
+// not interleave with the read loop's. Each response is a single Write.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(b []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(b)
+}
+
+// serveExtension answers adapter-specific methods. It reports false for
+// methods that adaptersdk should handle.
+func (p *Provider) serveExtension(enc *json.Encoder, env *rpcEnvelope) (bool, error) {
+	switch env.Method {
+	case adaptersdk.MethodApply:
+		return true, writeCall(enc, env, p.applyForRPC)
+	case adaptersdk.MethodDestroy:
+		return true, writeCall(enc, env, p.destroyForRPC)
+	case MethodDescribe:
+		return true, p.writeDescribe(enc, env.ID)
+	case MethodVersion:
+		return true, writeRPC(enc, rpcResult{JSONRPC: "2.0", Result: p.Version(context.Background()), ID: env.ID})
+	case MethodStatusBatch:
+		return true, p.writeStatusBatch(enc, env)
+	case MethodEvalResults:
+		return true, writeCall(enc, env, p.EvalResults)
+	case MethodMemoryList:
+		return true, writeCall(enc, env, p.MemoryList)
+	case MethodMemoryPurge:
+		return true, writeCall(enc, env, p.MemoryPurge)
+	case MethodListEvalTemplates:
+		return true, writeCall(enc, env, p.ListEvalTemplates)
+	case MethodEvalPreview:
+		return true, writeCall(enc, env, p.EvalPreview)
+	case MethodLint:
+		return true, writeCall(enc, env, p.Lint)
+	case MethodPromote:
+		return true, writeCall(enc, env, p.Promote)
+	case MethodApprove:
+		return true, writeCall(enc, env, p.Approve)
+	case MethodPendingApprovals:
+		return true, writeCall(enc, env, p.PendingApprovals)
+	case MethodGraph:
+		return true, writeCall(enc, env, p.Graph)
+	case MethodPlanDestroy:
+		return true, writeCall(enc, env, p.PlanDestroy)
+	default:
+		return false, nil
+	}
+}
+
+// applyResult is the result of the apply method: adaptersdk's, with coded
+// events.
+type applyResult struct {
+	AdapterState string   `json:"adapter_state"`
+	Events       []*Event `json:"events,omitempty"`
+}
+
+// destroyResult is the result of the destroy method: adaptersdk's, with
+// coded events.
+type destroyResult struct {
+	Status string   `json:"status"`
+	Events []*Event `json:"events,omitempty"`
+}
+
+// applyForRPC runs Apply, collecting its events with their codes.
+func (p *Provider) applyForRPC(ctx context.Context, req *deploy.PlanRequest) (*applyResult, error) {
+	var events []*Event
+	state, err := p.Apply(ctx, req, func(ev *deploy.ApplyEvent) error {
+		events = append(events, codedApplyEvent(ev))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &applyResult{AdapterState: state, Events: events}, nil
+}
+
+// destroyForRPC runs Destroy, collecting its events with their codes.
+func (p *Provider) destroyForRPC(ctx context.Context, req *deploy.DestroyRequest) (*destroyResult, error) {
+	var events []*Event
+	err := p.Destroy(ctx, req, func(ev *deploy.DestroyEvent) error {
+		events = append(events, codedDestroyEvent(ev))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &destroyResult{Status: "destroyed", Events: events}, nil
+}
+
+// writeDescribe answers a describe request.
+func (p *Provider) writeDescribe(enc *json.Encoder, id json.RawMessage) error {
+	desc, err := p.Describe(context.Background())
+	if err != nil {
+		return fmt.Errorf("agentcore: describe: %w", err)
+	}
+	return writeRPC(enc, rpcResult{JSONRPC: "2.0", Result: desc, ID: id})
+}
+
+// writeStatusBatch answers a status_batch request.
+func (p *Provider) writeStatusBatch(enc *json.Encoder, env *rpcEnvelope) error {
+	var req StatusBatchRequest
+	if err := json.Unmarshal(env.Params, &req); err != nil {
+		return writeRPC(enc, invalidParams(env.ID, err))
+	}
+	return writeRPC(enc, rpcResult{JSONRPC: "2.0", Result: p.StatusBatch(context.Background(), &req), ID: env.ID})
+}
+
+// writeCall decodes params into Req, calls fn, and writes its result or
+// error. It backs extension methods that take params and can fail. A
+// result fn returns along with an error, such as the events of a failed
+// promote, is sent as the error's data.
+func writeCall[Req, Resp any](
+	enc *json.Encoder, env *rpcEnvelope, fn func(context.Context, *Req) (Resp, error),
+) error {
+	var req Req
+	if err := json.Unmarshal(env.Params, &req); err != nil {
+		return writeRPC(enc, invalidParams(env.ID, err))
+	}
+	resp, err := fn(context.Background(), &req)
+	if err != nil {
+		rpcErr := &rpcError{Code: adaptersdk.CodeInternalError, Message: err.Error()}
+		if v := reflect.ValueOf(resp); v.Kind() == reflect.Pointer && !v.IsNil() {
+			rpcErr.Data = resp
+		}
+		return writeRPC(enc, rpcResult{JSONRPC: "2.0", Error: rpcErr, ID: env.ID})
+	}
+	return writeRPC(enc, rpcResult{JSONRPC: "2.0", Result: resp, ID: env.ID})
+}
+
+// invalidParams builds the error response for params that do not decode,
+// matching the message shape adaptersdk uses.
+func invalidParams(id json.RawMessage, err error) rpcResult {
+	return rpcResult{
+		JSONRPC: "2.0",
+		Error:   &rpcError{Code: adaptersdk.CodeParseError, Message: "invalid params: " + err.Error()},
+		ID:      id,
+	}
+}
+
+// writeRPC writes one response line.
+func writeRPC(enc *json.Encoder, resp rpcResult) error {
+	if err := enc.Encode(resp); err != nil {
+		return fmt.Errorf("agentcore: write error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package agentcore
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// EnvServeToken names the environment variable holding the bearer token
+// that callers of the HTTP service mode must present.
+const EnvServeToken = "PROMPTARENA_ADAPTER_TOKEN"
+
+// HTTP service mode routes. Plan, status, and the approval calls answer
+// with one JSON body; apply and destroy stream Server-Sent Events.
+const (
+	httpPathHealth           = "/healthz"
+	httpPathPlan             = "/v1/plan"
+	httpPathApply            = "/v1/apply"
+	httpPathStatus           = "/v1/status"
+	httpPathDestroy          = "/v1/destroy"
+	httpPathApprove          = "/v1/approve"
+	httpPathPendingApprovals = "/v1/pending_approvals"
+)
+
+// sseEventDone is the last event of an apply or destroy stream. Its data
+// is an httpStreamResult.
+const sseEventDone = "done"
+
+const httpReadHeaderTimeout = 10 * time.Second
+
+// httpStreamResult ends an apply or destroy stream. AdapterState is set by a
+// successful apply; Error is set when the operation failed.
+type httpStreamResult struct {
+	AdapterState string `json:"adapter_state,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// httpError is the body of a non-2xx response.
+type httpError struct {
+	Error string `json:"error"`
+}
+
+// NewHTTPHandler returns the adapter's HTTP API. Every route but the health
+// check requires "Authorization: Bearer <token>".
+func NewHTTPHandler(p *Provider, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+httpPathHealth, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("POST "+httpPathPlan, requireToken(token, jsonHandler(p.Plan)))
+	mux.Handle("POST "+httpPathStatus, requireToken(token, jsonHandler(p.Status)))
+	mux.Handle("POST "+httpPathApprove, requireToken(token, jsonHandler(p.Approve)))
+	mux.Handle("POST "+httpPathPendingApprovals, requireToken(token, jsonHandler(p.PendingApprovals)))
+	mux.Handle("POST "+httpPathApply, requireToken(token, http.HandlerFunc(p.serveApplyHTTP)))
+	mux.Handle("POST "+httpPathDestroy, requireToken(token, http.HandlerFunc(p.serveDestroyHTTP)))
+	return mux
+}
+
+// ServeHTTP serves the HTTP API on addr until ctx is cancelled. Requests in
+// flight, such as a running apply, are allowed to finish before it returns.
+func ServeHTTP(ctx context.Context, p *Provider, addr, token string) error {
+	if token == "" {
+		return fmt.Errorf("agentcore: %s must be set to serve over HTTP", EnvServeToken)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("agentcore: listen %s: %w", addr, err)
+	}
+	return serveHTTPListener(ctx, p, ln, token)
+}
+
+func serveHTTPListener(ctx context.Context, p *Provider, ln net.Listener, token string) error {
+	srv := &http.Server{
+		Handler:           NewHTTPHandler(p, token),
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+	logf("agentcore: serving HTTP on %s", ln.Addr())
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("agentcore: serve: %w", err)
+	case <-ctx.Done():
+	}
+	logf("agentcore: shutting down, waiting for requests in flight")
+	if err := srv.Shutdown(context.Background()); err != nil {
+		return fmt.Errorf("agentcore: shutdown: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("agentcore: serve: %w", err)
+	}
+	return nil
+}
+
+// requireToken rejects requests that do not carry the bearer token.
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeHTTPError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// jsonHandler decodes the body into Req, calls fn, and writes its result.
+// It is the HTTP counterpart of writeCall.
+func jsonHandler[Req, Resp any](fn func(context.Context, *Req) (Resp, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		if !decodeHTTPBody(w, r, &req) {
+			return
+		}
+		resp, err := fn(r.Context(), &req)
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeHTTPJSON(w, http.StatusOK, resp)
+	})
+}
+
+// serveApplyHTTP runs Apply and streams its events. A client that
+// disconnects cancels the apply.
+func (p *Provider) serveApplyHTTP(w http.ResponseWriter, r *http.Request) {
+	var req deploy.PlanRequest
+	if !decodeHTTPBody(w, r, &req) {
+		return
+	}
+	stream := newSSEWriter(w)
+	state, err := p.Apply(r.Context(), &req, func(e *deploy.ApplyEvent) error {
+		return stream.send(e.Type, codedApplyEvent(e))
+	})
+	stream.finish(httpStreamResult{AdapterState: state}, err)
+}
+
+// serveDestroyHTTP runs Destroy and streams its events.
+func (p *Provider) serveDestroyHTTP(w http.ResponseWriter, r *http.Request) {
+	var req deploy.DestroyRequest
+	if !decodeHTTPBody(w, r, &req) {
+		return
+	}
+	stream := newSSEWriter(w)
+	err := p.Destroy(r.Context(), &req, func(e *deploy.DestroyEvent) error {
+		return stream.send(e.Type, codedDestroyEvent(e))
+	})
+	stream.finish(httpStreamResult{}, err)
+}
+
+// sseWriter writes Server-Sent Events, flushing after each one.
+type sseWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	return &sseWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+// send writes one event. An error means the client has gone away.
+func (s *sseWriter) send(event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// finish writes the done event, recording err when the operation failed.
+func (s *sseWriter) finish(res httpStreamResult, err error) {
+	if err != nil {
+		res = httpStreamResult{Error: err.Error()}
+	}
+	if sendErr := s.send(sseEventDone, res); sendErr != nil {
+		logf("agentcore: write %s event: %v", sseEventDone, sendErr)
+	}
+}
+
+// decodeHTTPBody decodes the JSON body into v, answering 400 when it
+// cannot. Bodies are capped at the JSON-RPC line limit.
+func decodeHTTPBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRPCLineSize)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeHTTPJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logf("agentcore: write response: %v", err)
+	}
+}
+
+func writeHTTPError(w http.ResponseWriter, code int, err error) {
+	writeHTTPJSON(w, code, httpError{Error: err.Error()})
+}
@@ -0,0 +1,202 @@
+package agentcore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+const testServeToken = "s3cret"
+
+func postHTTP(t *testing.T, srv *httptest.Server, path, token string, body any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(mustJSON(t, body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// sseEvent is one parsed Server-Sent Event.
+type sseEvent struct {
+	name string
+	data string
+}
+
+func readSSE(t *testing.T, resp *http.Response) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var cur sseEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			cur.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			cur.data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events = append(events, cur)
+			cur = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestHTTPHandler_RequiresToken(t *testing.T) {
+	srv := httptest.NewServer(NewHTTPHandler(newSimulatedProvider(), testServeToken))
+	defer srv.Close()
+
+	for _, token := range []string{"", "wrong"} {
+		resp := postHTTP(t, srv, httpPathPlan, token, deploy.PlanRequest{})
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("token %q: status = %d, want 401 with a Bearer challenge", token, resp.StatusCode)
+		}
+	}
+
+	resp, err := srv.Client().Get(srv.URL + httpPathHealth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("health status = %d, want 200 without a token", resp.StatusCode)
+	}
+}
+
+func TestHTTPHandler_Plan(t *testing.T) {
+	srv := httptest.NewServer(NewHTTPHandler(newSimulatedProvider(), testServeToken))
+	defer srv.Close()
+
+	resp := postHTTP(t, srv, httpPathPlan, testServeToken, deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	var plan deploy.PlanResponse
+	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) == 0 {
+		t.Error("plan has no changes")
+	}
+}
+
+func TestHTTPHandler_Errors(t *testing.T) {
+	srv := httptest.NewServer(NewHTTPHandler(newSimulatedProvider(), testServeToken))
+	defer srv.Close()
+
+	resp := postHTTP(t, srv, httpPathStatus, testServeToken, "not an object")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad body status = %d, want 400", resp.StatusCode)
+	}
+
+	resp = postHTTP(t, srv, httpPathPlan, testServeToken, deploy.PlanRequest{DeployConfig: "{"})
+	var body httpError
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError || body.Error == "" {
+		t.Errorf("provider error: status = %d, body = %+v", resp.StatusCode, body)
+	}
+}
+
+func TestHTTPHandler_ApplyStreamsEvents(t *testing.T) {
+	srv := httptest.NewServer(NewHTTPHandler(newSimulatedProvider(), testServeToken))
+	defer srv.Close()
+
+	resp := postHTTP(t, srv, httpPathApply, testServeToken, deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+	events := readSSE(t, resp)
+	if len(events) < 2 {
+		t.Fatalf("events = %+v, want progress and a done event", events)
+	}
+	if events[0].name != "progress" {
+		t.Errorf("first event = %q, want progress", events[0].name)
+	}
+	done := events[len(events)-1]
+	var res httpStreamResult
+	if err := json.Unmarshal([]byte(done.data), &res); err != nil {
+		t.Fatal(err)
+	}
+	if done.name != sseEventDone || res.Error != "" || !strings.Contains(res.AdapterState, "agent_runtime") {
+		t.Errorf("done = %+v", done)
+	}
+}
+
+func TestHTTPHandler_DestroyReportsFailure(t *testing.T) {
+	srv := httptest.NewServer(NewHTTPHandler(newSimulatedProvider(), testServeToken))
+	defer srv.Close()
+
+	resp := postHTTP(t, srv, httpPathDestroy, testServeToken, deploy.DestroyRequest{PriorState: "{"})
+	events := readSSE(t, resp)
+	if len(events) == 0 {
+		t.Fatal("no events")
+	}
+	done := events[len(events)-1]
+	var res httpStreamResult
+	if err := json.Unmarshal([]byte(done.data), &res); err != nil {
+		t.Fatal(err)
+	}
+	if done.name != sseEventDone || res.Error == "" {
+		t.Errorf("done = %+v, want the destroy error", done)
+	}
+}
+
+func TestServeHTTP_RequiresToken(t *testing.T) {
+	err := ServeHTTP(context.Background(), newSimulatedProvider(), "127.0.0.1:0", "")
+	if err == nil || !strings.Contains(err.Error(), EnvServeToken) {
+		t.Errorf("err = %v, want a missing token error", err)
+	}
+}
+
+func TestServeHTTPListener_ShutsDownOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveHTTPListener(ctx, newSimulatedProvider(), ln, testServeToken) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + httpPathHealth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
@@ -0,0 +1,86 @@
+package agentcore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestServeIO_Describe(t *testing.T) {
+	var out bytes.Buffer
+	err := ServeIO(newSimulatedProvider(), strings.NewReader(jsonRPCRequest(MethodDescribe, 7, nil)), &out)
+	if err != nil {
+		t.Fatalf("ServeIO error: %v", err)
+	}
+
+	var resp jsonRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response %q: %v", out.String(), err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	if string(resp.ID) != "7" {
+		t.Errorf("id = %s, want 7", resp.ID)
+	}
+
+	var desc DescribeResponse
+	if err := json.Unmarshal(resp.Result, &desc); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if desc.Name != providerName || desc.Version != Version {
+		t.Errorf("name/version = %q/%q", desc.Name, desc.Version)
+	}
+	if desc.ConfigSchemaVersion != configSchemaVersion {
+		t.Errorf("config_schema_version = %q, want %q", desc.ConfigSchemaVersion, configSchemaVersion)
+	}
+	if len(desc.ResourceTypes) != 13 {
+		t.Errorf("resource_types = %v, want 13 items", desc.ResourceTypes)
+	}
+	if !desc.Features[FeatureDryRun] {
+		t.Error("expected dry_run feature")
+	}
+	for _, f := range []string{FeatureBlueGreen, FeatureImport, FeatureLogs} {
+		if _, ok := desc.Features[f]; !ok {
+			t.Errorf("feature %q not reported", f)
+		}
+	}
+}
+
+func TestServeIO_DelegatesStandardMethods(t *testing.T) {
+	input := jsonRPCRequest("get_provider_info", 1, nil) +
+		"\n" +
+		jsonRPCRequest(MethodDescribe, 2, nil) +
+		jsonRPCRequest("no_such_method", 3, nil)
+
+	var out bytes.Buffer
+	if err := ServeIO(newSimulatedProvider(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ServeIO error: %v", err)
+	}
+
+	var resps []jsonRPCResponse
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var resp jsonRPCResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("parse response %q: %v", scanner.Text(), err)
+		}
+		resps = append(resps, resp)
+	}
+	if len(resps) != 3 {
+		t.Fatalf("got %d responses, want 3", len(resps))
+	}
+	for i, want := range []string{"1", "2", "3"} {
+		if string(resps[i].ID) != want {
+			t.Errorf("response %d id = %s, want %s", i, resps[i].ID, want)
+		}
+	}
+	if resps[0].Error != nil || resps[1].Error != nil {
+		t.Error("expected get_provider_info and describe to succeed")
+	}
+	if resps[2].Error == nil {
+		t.Error("expected method-not-found error for unknown method")
+	}
+}
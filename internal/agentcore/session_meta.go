@@ -0,0 +1,210 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+// Environment variables controlling the runtime's session metadata.
+const (
+	EnvSessionStore    = "PROMPTPACK_SESSION_STORE"
+	EnvSessionMaxTurns = "PROMPTPACK_SESSION_MAX_TURNS"
+
+	EnvSessionMaxConcurrent    = "PROMPTPACK_SESSION_MAX_CONCURRENT"
+	EnvSessionDailyTokenBudget = "PROMPTPACK_SESSION_DAILY_TOKEN_BUDGET"
+)
+
+// SessionStoreMemory persists session metadata as memory events.
+const SessionStoreMemory = "memory"
+
+// Constants for session metadata persistence.
+const (
+	// SessionMetaActorID is the memory actor that owns session metadata
+	// events, kept apart from conversation history and tool audit events.
+	SessionMetaActorID = "promptkit-session-meta"
+
+	// sessionMetaPrefix marks the JSON text of a session metadata event.
+	sessionMetaPrefix = "promptkit:session_meta:"
+
+	// sessionMetaKind tags session metadata events in their metadata.
+	sessionMetaKind = "session_meta"
+
+	// maxSessionMaxTurns is the largest accepted per-session turn limit.
+	maxSessionMaxTurns = 10000
+)
+
+// SessionsConfig controls the runtime's per-session metadata.
+type SessionsConfig struct {
+	// Persist stores session metadata in the deployment's memory so it
+	// survives runtime restarts.
+	Persist bool `json:"persist,omitempty"`
+	// PersistTasks stores the runtime's A2A tasks in the deployment's
+	// memory so tasks/get and tasks/list survive runtime restarts.
+	PersistTasks bool `json:"persist_tasks,omitempty"`
+	// MaxTurns rejects invocations once a session has served this many
+	// turns. 0 means unlimited.
+	MaxTurns int `json:"max_turns,omitempty"`
+	// MaxConcurrent rejects invocations of a session that already has
+	// this many running, so one session's streams cannot take every slot
+	// of a runtime instance. 0 means unlimited.
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+	// DailyTokenBudget rejects invocations of a session once its turns
+	// have used this many input and output tokens in the current UTC day.
+	// 0 means unlimited.
+	DailyTokenBudget int `json:"daily_token_budget,omitempty"`
+}
+
+// SessionMeta is the lightweight state the runtime keeps per session. It
+// never holds conversation content.
+type SessionMeta struct {
+	SessionID  string    `json:"session_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	LastTaskID string    `json:"last_task_id,omitempty"`
+	Turns      int       `json:"turns"`
+
+	// InputTokens and OutputTokens total the token usage the session's
+	// turns reported.
+	InputTokens  int `json:"input_tokens,omitempty"`
+	OutputTokens int `json:"output_tokens,omitempty"`
+
+	// BudgetDay is the UTC date, as YYYY-MM-DD, whose token usage
+	// DayTokens counts. They are kept only under a daily token budget.
+	BudgetDay string `json:"budget_day,omitempty"`
+	DayTokens int    `json:"day_tokens,omitempty"`
+}
+
+// SessionMetaStore persists SessionMeta snapshots as memory events, one
+// event per completed turn. The snapshot with the most turns wins on load.
+type SessionMetaStore struct {
+	memoryID string
+	client   DataPlaneClient
+}
+
+// NewSessionMetaStore creates a SessionMetaStore writing to memoryID.
+func NewSessionMetaStore(memoryID string, client DataPlaneClient) *SessionMetaStore {
+	return &SessionMetaStore{memoryID: memoryID, client: client}
+}
+
+// Load returns the latest snapshot for sessionID, or nil if the session has
+// none.
+func (s *SessionMetaStore) Load(ctx context.Context, sessionID string) (*SessionMeta, error) {
+	var latest *SessionMeta
+	var nextToken *string
+	for {
+		out, err := s.client.ListEvents(ctx, &bedrockagentcore.ListEventsInput{
+			MemoryId:        aws.String(s.memoryID),
+			ActorId:         aws.String(SessionMetaActorID),
+			SessionId:       aws.String(sessionID),
+			IncludePayloads: aws.Bool(true),
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListEvents session metadata for session %q: %w", sessionID, err)
+		}
+		for i := range out.Events {
+			if meta := decodeSessionMeta(&out.Events[i]); meta != nil && newerSessionMeta(meta, latest) {
+				latest = meta
+			}
+		}
+		if out.NextToken == nil {
+			return latest, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// Save writes meta as a new snapshot event.
+func (s *SessionMetaStore) Save(ctx context.Context, meta *SessionMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encode session metadata: %w", err)
+	}
+	ts := meta.UpdatedAt
+	_, err = s.client.CreateEvent(ctx, &bedrockagentcore.CreateEventInput{
+		MemoryId:       aws.String(s.memoryID),
+		ActorId:        aws.String(SessionMetaActorID),
+		SessionId:      aws.String(meta.SessionID),
+		EventTimestamp: &ts,
+		Payload: []dpTypes.PayloadType{
+			&dpTypes.PayloadTypeMemberConversational{
+				Value: dpTypes.Conversational{
+					Role:    dpTypes.RoleOther,
+					Content: &dpTypes.ContentMemberText{Value: sessionMetaPrefix + string(data)},
+				},
+			},
+		},
+		Metadata: map[string]dpTypes.MetadataValue{
+			toolAuditKindKey: &dpTypes.MetadataValueMemberStringValue{Value: sessionMetaKind},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("CreateEvent session metadata for session %q: %w", meta.SessionID, err)
+	}
+	return nil
+}
+
+// decodeSessionMeta returns the snapshot carried by event, or nil if the
+// event is not a session metadata snapshot.
+func decodeSessionMeta(event *dpTypes.Event) *SessionMeta {
+	for _, p := range event.Payload {
+		conv, ok := p.(*dpTypes.PayloadTypeMemberConversational)
+		if !ok {
+			continue
+		}
+		text, ok := conv.Value.Content.(*dpTypes.ContentMemberText)
+		if !ok || !strings.HasPrefix(text.Value, sessionMetaPrefix) {
+			continue
+		}
+		var meta SessionMeta
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(text.Value, sessionMetaPrefix)), &meta); err != nil {
+			continue
+		}
+		return &meta
+	}
+	return nil
+}
+
+// newerSessionMeta reports whether a supersedes b.
+func newerSessionMeta(a, b *SessionMeta) bool {
+	if b == nil {
+		return true
+	}
+	if a.Turns != b.Turns {
+		return a.Turns > b.Turns
+	}
+	return a.UpdatedAt.After(b.UpdatedAt)
+}
+
+// validateSessions checks the sessions settings. Persisting writes to the
+// deployment's memory, so it requires memory_store.
+func validateSessions(s *SessionsConfig, hasMemory bool) []string {
+	if s == nil {
+		return nil
+	}
+	var errs []string
+	if s.Persist && !hasMemory {
+		errs = append(errs, "sessions.persist requires memory_store")
+	}
+	if s.PersistTasks && !hasMemory {
+		errs = append(errs, "sessions.persist_tasks requires memory_store")
+	}
+	if s.MaxTurns < 0 || s.MaxTurns > maxSessionMaxTurns {
+		errs = append(errs, fmt.Sprintf("sessions.max_turns %d must be between 0 and %d",
+			s.MaxTurns, maxSessionMaxTurns))
+	}
+	if s.MaxConcurrent < 0 {
+		errs = append(errs, fmt.Sprintf("sessions.max_concurrent %d must not be negative", s.MaxConcurrent))
+	}
+	if s.DailyTokenBudget < 0 {
+		errs = append(errs, fmt.Sprintf("sessions.daily_token_budget %d must not be negative", s.DailyTokenBudget))
+	}
+	return errs
+}
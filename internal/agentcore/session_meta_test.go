@@ -0,0 +1,156 @@
+package agentcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+func TestSessionMetaStore_SaveAndLoad(t *testing.T) {
+	mock := &mockDataPlaneClient{}
+	store := NewSessionMetaStore("mem-1", mock)
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for turn := 1; turn <= 3; turn++ {
+		meta := &SessionMeta{
+			SessionID: "sess-1", CreatedAt: created, UpdatedAt: created.Add(time.Duration(turn) * time.Minute),
+			LastTaskID: fmt.Sprintf("task-%d", turn), Turns: turn,
+		}
+		if err := store.Save(context.Background(), meta); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	in := mock.createCalls[0]
+	if aws.ToString(in.ActorId) != SessionMetaActorID || aws.ToString(in.SessionId) != "sess-1" {
+		t.Errorf("event ids = %s/%s", aws.ToString(in.ActorId), aws.ToString(in.SessionId))
+	}
+
+	// Serve the saved events newest first, split across two pages, with an
+	// unrelated event mixed in.
+	events := make([]dpTypes.Event, 0, len(mock.createCalls)+1)
+	for i := len(mock.createCalls) - 1; i >= 0; i-- {
+		events = append(events, dpTypes.Event{SessionId: aws.String("sess-1"), Payload: mock.createCalls[i].Payload})
+	}
+	events = append(events, dpTypes.Event{Payload: []dpTypes.PayloadType{
+		&dpTypes.PayloadTypeMemberConversational{Value: dpTypes.Conversational{
+			Role: dpTypes.RoleUser, Content: &dpTypes.ContentMemberText{Value: "hello"},
+		}},
+	}})
+	var listCalls []*bedrockagentcore.ListEventsInput
+	mock.listEventsFn = func(_ context.Context, input *bedrockagentcore.ListEventsInput,
+		_ ...func(*bedrockagentcore.Options)) (*bedrockagentcore.ListEventsOutput, error) {
+		listCalls = append(listCalls, input)
+		if input.NextToken == nil {
+			return &bedrockagentcore.ListEventsOutput{Events: events[2:], NextToken: aws.String("p2")}, nil
+		}
+		return &bedrockagentcore.ListEventsOutput{Events: events[:2]}, nil
+	}
+
+	got, err := store.Load(context.Background(), "sess-1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got == nil || got.Turns != 3 || got.LastTaskID != "task-3" || !got.CreatedAt.Equal(created) {
+		t.Errorf("Load = %+v, want the turn 3 snapshot", got)
+	}
+	if len(listCalls) != 2 || aws.ToString(listCalls[0].ActorId) != SessionMetaActorID {
+		t.Errorf("ListEvents calls = %d", len(listCalls))
+	}
+}
+
+func TestSessionMetaStore_LoadUnknownAndError(t *testing.T) {
+	mock := &mockDataPlaneClient{}
+	got, err := NewSessionMetaStore("mem-1", mock).Load(context.Background(), "new")
+	if err != nil || got != nil {
+		t.Errorf("Load unknown session = %+v, %v, want nil, nil", got, err)
+	}
+
+	mock.listEventsFn = func(context.Context, *bedrockagentcore.ListEventsInput,
+		...func(*bedrockagentcore.Options)) (*bedrockagentcore.ListEventsOutput, error) {
+		return nil, errors.New("throttled")
+	}
+	if _, err := NewSessionMetaStore("mem-1", mock).Load(context.Background(), "s"); err == nil ||
+		!strings.Contains(err.Error(), "throttled") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestValidateSessions(t *testing.T) {
+	tests := []struct {
+		name      string
+		sessions  *SessionsConfig
+		hasMemory bool
+		wantErr   string
+	}{
+		{name: "unset"},
+		{name: "turn limit only", sessions: &SessionsConfig{MaxTurns: 20}},
+		{name: "persist with memory", sessions: &SessionsConfig{Persist: true}, hasMemory: true},
+		{name: "persist without memory", sessions: &SessionsConfig{Persist: true}, wantErr: "requires memory_store"},
+		{name: "persist tasks with memory", sessions: &SessionsConfig{PersistTasks: true}, hasMemory: true},
+		{
+			name: "persist tasks without memory", sessions: &SessionsConfig{PersistTasks: true},
+			wantErr: "persist_tasks requires memory_store",
+		},
+		{name: "negative limit", sessions: &SessionsConfig{MaxTurns: -1}, wantErr: "between 0 and 10000"},
+		{name: "fairness limits", sessions: &SessionsConfig{MaxConcurrent: 2, DailyTokenBudget: 100000}},
+		{
+			name: "negative concurrency", sessions: &SessionsConfig{MaxConcurrent: -1},
+			wantErr: "max_concurrent -1 must not be negative",
+		},
+		{
+			name: "negative budget", sessions: &SessionsConfig{DailyTokenBudget: -5},
+			wantErr: "daily_token_budget -5 must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateSessions(tt.sessions, tt.hasMemory)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildRuntimeEnvVars_Sessions(t *testing.T) {
+	cfg := &Config{Sessions: &SessionsConfig{
+		Persist: true, PersistTasks: true, MaxTurns: 30, MaxConcurrent: 2, DailyTokenBudget: 100000,
+	}}
+	env := buildRuntimeEnvVars(cfg)
+	if env[EnvSessionStore] != SessionStoreMemory || env[EnvSessionMaxTurns] != "30" {
+		t.Errorf("env = %v", env)
+	}
+	if env[EnvSessionMaxConcurrent] != "2" || env[EnvSessionDailyTokenBudget] != "100000" {
+		t.Errorf("fairness env = %q/%q", env[EnvSessionMaxConcurrent], env[EnvSessionDailyTokenBudget])
+	}
+	if env[EnvA2ATaskStore] != A2ATaskStoreMemory {
+		t.Errorf("%s = %q, want %q", EnvA2ATaskStore, env[EnvA2ATaskStore], A2ATaskStoreMemory)
+	}
+
+	cfg.Sessions = &SessionsConfig{}
+	env = buildRuntimeEnvVars(cfg)
+	if _, ok := env[EnvSessionStore]; ok {
+		t.Errorf("%s set without persist", EnvSessionStore)
+	}
+	if _, ok := env[EnvA2ATaskStore]; ok {
+		t.Errorf("%s set without persist_tasks", EnvA2ATaskStore)
+	}
+	for _, name := range []string{EnvSessionMaxTurns, EnvSessionMaxConcurrent, EnvSessionDailyTokenBudget} {
+		if _, ok := env[name]; ok {
+			t.Errorf("%s set without a limit", name)
+		}
+	}
+}
@@ -4,20 +4,28 @@ package agentcore
 const (
 	ResTypeMemory           = "memory"
 	ResTypeAgentRuntime     = "agent_runtime"
+	ResTypeRuntimeEndpoint  = "runtime_endpoint"
 	ResTypeToolGateway      = "tool_gateway"
 	ResTypeA2AEndpoint      = "a2a_endpoint"
 	ResTypeEvaluator        = "evaluator"
 	ResTypeOnlineEvalConfig = "online_eval_config"
 	ResTypeCedarPolicy      = "cedar_policy"
+	ResTypeInferenceProfile = "inference_profile"
+	ResTypeLogGroup         = "log_group"
+	ResTypeIdentityProvider = "identity_provider"
+	ResTypeEvalAlert        = "eval_alert"
+	ResTypeLogsQuery        = "logs_query"
 )
 
 // Resource lifecycle status constants used in ResourceState.Status.
 const (
-	ResStatusCreated = "created"
-	ResStatusUpdated = "updated"
-	ResStatusFailed  = "failed"
-	ResStatusPlanned = "planned"
-	ResStatusDeleted = "deleted"
+	ResStatusCreated  = "created"
+	ResStatusUpdated  = "updated"
+	ResStatusReplaced = "replaced"
+	ResStatusFailed   = "failed"
+	ResStatusPlanned  = "planned"
+	ResStatusDeleted  = "deleted"
+	ResStatusSkipped  = "skipped"
 )
 
 // Health status constants returned by resource checks.
@@ -27,6 +35,16 @@ const (
 	StatusMissing   = "missing"
 )
 
+// Aggregate deployment status constants returned by Status and StatusBatch.
+const (
+	DeployStatusDeployed    = "deployed"
+	DeployStatusDegraded    = "degraded"
+	DeployStatusNotDeployed = "not_deployed"
+	// DeployStatusError is only reported by StatusBatch, for a deployment
+	// that could not be checked.
+	DeployStatusError = "error"
+)
+
 // AdapterState holds resource info from previous deploys. It is serialized
 // as the opaque "prior_state" string exchanged between Plan, Apply, and Status.
 type AdapterState struct {
@@ -34,6 +52,27 @@ type AdapterState struct {
 	PackID     string          `json:"pack_id,omitempty"`
 	Version    string          `json:"version,omitempty"`
 	DeployedAt string          `json:"deployed_at,omitempty"`
+
+	// Workspace is the deploy config's workspace when the state was
+	// written. Empty for the default workspace.
+	Workspace string `json:"workspace,omitempty"`
+
+	// Outputs holds values clients need to invoke the deployment, such as
+	// "<agent>.invocation_arn" and "<agent>.qualifier" for runtime endpoints.
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Manifest records what the Apply that wrote the state changed. It is
+	// nil for state written by a dry run or before the field existed.
+	Manifest *ChangeManifest `json:"manifest,omitempty"`
+
+	// PromotedFrom is set when the state was written by Promote.
+	PromotedFrom *Promotion `json:"promoted_from,omitempty"`
+
+	// Variables records the ${var.*} values the deploy config was resolved
+	// with, so Destroy and Status resolve it without the arena config. The
+	// values are stored as they are, unredacted, since later requests need
+	// them: secrets belong in the *_env fields, not in variables.
+	Variables map[string]string `json:"variables,omitempty"`
 }
 
 // ResourceState describes a single deployed resource.
@@ -43,4 +82,14 @@ type ResourceState struct {
 	ARN      string            `json:"arn,omitempty"`
 	Status   string            `json:"status,omitempty"`
 	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Owned is false for resources Apply adopted instead of creating. It is
+	// omitted for owned resources, so state written before the field
+	// existed reads as owned.
+	Owned *bool `json:"owned,omitempty"`
+}
+
+// isOwned reports whether this deployment created the resource.
+func (r ResourceState) isOwned() bool {
+	return r.Owned == nil || *r.Owned
 }
@@ -19,7 +19,7 @@ import (
 // Constants for the AgentCore state store.
 const (
 	// defaultActorID is the actor ID used for all events
-	// written by the state store.
+	// written by the state store when it has no namespace.
 	defaultActorID = "promptkit"
 
 	// maxPayloadItems is the maximum number of payload items
@@ -45,6 +45,7 @@ const (
 // resource.
 type StateStore struct {
 	memoryID    string
+	actorID     string
 	client      DataPlaneClient
 	savedCounts map[string]int
 	mu          sync.Mutex
@@ -60,8 +61,23 @@ var (
 func NewStateStore(
 	memoryID string, client DataPlaneClient,
 ) *StateStore {
+	return NewNamespacedStateStore(memoryID, "", client)
+}
+
+// NewNamespacedStateStore creates a StateStore that reads and
+// writes events under namespace as their actor ID. Memory
+// strategies keep long-term memories per actor, so stores
+// with different namespaces do not share them. An empty
+// namespace uses the default actor.
+func NewNamespacedStateStore(
+	memoryID, namespace string, client DataPlaneClient,
+) *StateStore {
+	if namespace == "" {
+		namespace = defaultActorID
+	}
 	return &StateStore{
 		memoryID:    memoryID,
+		actorID:     namespace,
 		client:      client,
 		savedCounts: make(map[string]int),
 	}
@@ -176,6 +192,8 @@ func (s *StateStore) loadAllMessages(
 			ctx,
 			&bedrockagentcore.ListEventsInput{
 				MemoryId:  aws.String(s.memoryID),
+				ActorId:   aws.String(s.actorID),
+				SessionId: aws.String(sessionID),
 				NextToken: nextToken,
 			},
 		)
@@ -303,7 +321,7 @@ func (s *StateStore) writeBatches(
 			ctx,
 			&bedrockagentcore.CreateEventInput{
 				MemoryId:       aws.String(s.memoryID),
-				ActorId:        aws.String(defaultActorID),
+				ActorId:        aws.String(s.actorID),
 				SessionId:      aws.String(sessionID),
 				EventTimestamp: &now,
 				Payload:        payloads[i:end],
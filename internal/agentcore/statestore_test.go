@@ -699,3 +699,44 @@ func TestRoleMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespacedStateStore_ActorID(t *testing.T) {
+	var listed *bedrockagentcore.ListEventsInput
+	mock := &mockDataPlaneClient{
+		listEventsFn: func(
+			_ context.Context,
+			input *bedrockagentcore.ListEventsInput,
+			_ ...func(*bedrockagentcore.Options),
+		) (*bedrockagentcore.ListEventsOutput, error) {
+			listed = input
+			return &bedrockagentcore.ListEventsOutput{}, nil
+		},
+	}
+	store := NewNamespacedStateStore("mem-1", "support/worker", mock)
+
+	state := &statestore.ConversationState{
+		ID:       "sess-1",
+		Messages: []types.Message{{Role: "user", Content: "hello"}},
+	}
+	if err := store.Save(context.Background(), state); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := store.Load(context.Background(), "sess-2"); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got := aws.ToString(mock.createCalls[0].ActorId); got != "support/worker" {
+		t.Errorf("CreateEvent actor = %q, want %q", got, "support/worker")
+	}
+	if listed == nil || aws.ToString(listed.ActorId) != "support/worker" ||
+		aws.ToString(listed.SessionId) != "sess-2" {
+		t.Errorf("ListEvents input = %+v, want actor support/worker and session sess-2", listed)
+	}
+}
+
+func TestNamespacedStateStore_DefaultActor(t *testing.T) {
+	store := NewNamespacedStateStore("mem-1", "", &mockDataPlaneClient{})
+	if store.actorID != defaultActorID {
+		t.Errorf("actorID = %q, want %q", store.actorID, defaultActorID)
+	}
+}
@@ -4,22 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/AltairaLabs/PromptKit/runtime/deploy"
 )
 
-// destroyOrder defines the reverse dependency order for teardown.
-// Resources are grouped by type; each group is destroyed in sequence.
-var destroyOrder = []string{
-	ResTypeOnlineEvalConfig,
-	ResTypeToolGateway,
-	ResTypeCedarPolicy,
-	ResTypeEvaluator,
-	ResTypeA2AEndpoint,
-	ResTypeAgentRuntime,
-	ResTypeMemory,
-}
-
 // Destroy tears down deployed resources in reverse dependency order,
 // streaming progress events via the callback.
 func (p *Provider) Destroy(
@@ -36,20 +25,34 @@ func (p *Provider) Destroy(
 		return nil
 	}
 
-	cfg, err := parseConfig(req.DeployConfig)
+	cfg, err := p.loadConfig(req.DeployConfig, state.Variables)
 	if err != nil {
 		return fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
 	}
+	if err := checkStateWorkspace(state, cfg); err != nil {
+		return fmt.Errorf("agentcore: %w", err)
+	}
 
+	redact := newRedactor(cfg)
+	defer redact.maskLogs()()
+	permissions := &permissionLog{}
+	callback = lockedDestroyCallback(permissions.destroyCallback(redact.destroyCallback(callback)))
 	destroyer, err := p.destroyerFunc(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("agentcore: failed to create destroyer: %w", err)
+		return redact.redactError(fmt.Errorf("agentcore: failed to create destroyer: %w", err))
+	}
+	if ws, ok := destroyer.(waitProgressSetter); ok {
+		ws.SetWaitProgress(func(msg string) { emitDestroyEvent(callback, "progress", msg) })
 	}
 
-	byType := groupByType(state.Resources)
+	resources, adopted := splitAdopted(state.Resources, cfg.IncludeAdopted)
+	emitAdoptedSkips(callback, adopted)
+	resources, retained := splitRetainedLogGroups(resources, cfg)
+	emitRetainedSkips(callback, retained)
+	byType := groupByType(resources)
 
 	emitDestroyEvent(callback, "progress",
-		fmt.Sprintf("Destroying %d resources", len(state.Resources)))
+		fmt.Sprintf("Destroying %d resources", len(resources)))
 
 	for step, rtype := range destroyOrder {
 		resources, ok := byType[rtype]
@@ -58,43 +61,21 @@ func (p *Provider) Destroy(
 		}
 		emitDestroyEvent(callback, "progress",
 			fmt.Sprintf("Step %d: deleting %s resources (%d)", step+1, rtype, len(resources)))
-		destroyResourceGroup(ctx, destroyer, resources, callback)
+		destroyResourceGroup(ctx, destroyer, resources, callback, cfg.destroyConcurrency())
 	}
 
-	destroyUnorderedResources(ctx, destroyer, state.Resources, callback)
+	destroyUnorderedResources(ctx, destroyer, resources, callback)
+	reportOrphans(ctx, destroyer, destroyPackID(state, cfg), cfg.Region, state.Resources, callback)
 
-	emitDestroyEvent(callback, "complete", "Destroy complete")
-	return nil
-}
-
-// destroyResourceGroup deletes a slice of resources, emitting events for each.
-func destroyResourceGroup(
-	ctx context.Context, destroyer resourceDestroyer,
-	resources []ResourceState, callback deploy.DestroyCallback,
-) {
-	for _, res := range resources {
-		if err := destroyer.DeleteResource(ctx, res); err != nil {
-			deployErr := newDeployError("delete", res.Type, res.Name, err)
-			_ = callback(&deploy.DestroyEvent{
-				Type:    "error",
-				Message: deployErr.Error(),
-				Resource: &deploy.ResourceResult{
-					Type: res.Type, Name: res.Name,
-					Action: deploy.ActionDelete, Status: ResStatusFailed,
-					Detail: deployErr.Error(),
-				},
-			})
-			continue
-		}
-		_ = callback(&deploy.DestroyEvent{
-			Type:    ErrCategoryResource,
-			Message: fmt.Sprintf("Deleted %s %q", res.Type, res.Name),
-			Resource: &deploy.ResourceResult{
-				Type: res.Type, Name: res.Name,
-				Action: deploy.ActionDelete, Status: ResStatusDeleted,
-			},
-		})
+	if summary := permissions.summary(); summary != "" {
+		emitDestroyEvent(callback, "progress", summary)
 	}
+	complete := "Destroy complete"
+	if summary := apiCallsSummary(destroyer); summary != "" {
+		complete += "; " + summary
+	}
+	emitDestroyEvent(callback, "complete", complete)
+	return nil
 }
 
 // destroyUnorderedResources handles resource types not in the standard
@@ -148,28 +129,36 @@ func (p *Provider) Status(
 
 	if len(state.Resources) == 0 {
 		return &deploy.StatusResponse{
-			Status: "not_deployed",
+			Status: DeployStatusNotDeployed,
 		}, nil
 	}
 
-	cfg, err := parseConfig(req.DeployConfig)
+	cfg, err := p.loadConfig(req.DeployConfig, state.Variables)
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: failed to parse deploy config: %w", err)
 	}
+	if err := checkStateWorkspace(state, cfg); err != nil {
+		return nil, fmt.Errorf("agentcore: %w", err)
+	}
 
 	checker, err := p.checkerFunc(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("agentcore: failed to create checker: %w", err)
 	}
 
+	return checkDeployment(ctx, checker, state), nil
+}
+
+// checkDeployment checks every resource in state and aggregates the result:
+// "deployed" when all are healthy, otherwise "degraded".
+func checkDeployment(
+	ctx context.Context, checker resourceChecker, state *AdapterState,
+) *deploy.StatusResponse {
 	var resources []deploy.ResourceStatus
 	hasUnhealthy := false
 
 	for _, res := range state.Resources {
-		health, checkErr := checker.CheckResource(ctx, res)
-		if checkErr != nil {
-			health = StatusUnhealthy
-		}
+		health, detail := checkResource(ctx, checker, res)
 		if health != StatusHealthy {
 			hasUnhealthy = true
 		}
@@ -177,12 +166,13 @@ func (p *Provider) Status(
 			Type:   res.Type,
 			Name:   res.Name,
 			Status: health,
+			Detail: detail,
 		})
 	}
 
-	aggregateStatus := "deployed"
+	aggregateStatus := DeployStatusDeployed
 	if hasUnhealthy {
-		aggregateStatus = "degraded"
+		aggregateStatus = DeployStatusDegraded
 	}
 
 	stateJSON, _ := json.Marshal(state)
@@ -191,7 +181,25 @@ func (p *Provider) Status(
 		Status:    aggregateStatus,
 		Resources: resources,
 		State:     string(stateJSON),
-	}, nil
+	}
+}
+
+// checkResource returns the health of res and, when the checker provides
+// one, a detail explaining it. A failed check is reported as unhealthy with
+// the error as its detail.
+func checkResource(
+	ctx context.Context, checker resourceChecker, res ResourceState,
+) (health, detail string) {
+	var checkErr error
+	if dc, ok := checker.(resourceDetailChecker); ok {
+		health, detail, checkErr = dc.CheckResourceDetail(ctx, res)
+	} else {
+		health, checkErr = checker.CheckResource(ctx, res)
+	}
+	if checkErr != nil {
+		return StatusUnhealthy, checkErr.Error()
+	}
+	return health, detail
 }
 
 // parseAdapterState deserializes the opaque prior_state JSON.
@@ -210,10 +218,5 @@ func parseAdapterState(raw string) (*AdapterState, error) {
 // isInDestroyOrder returns true if the resource type appears in the
 // standard destroy ordering.
 func isInDestroyOrder(rtype string) bool {
-	for _, t := range destroyOrder {
-		if t == rtype {
-			return true
-		}
-	}
-	return false
+	return slices.Contains(destroyOrder, rtype)
 }
@@ -0,0 +1,151 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// MethodStatusBatch is the JSON-RPC method that checks many deployments in
+// one call. It extends the standard adaptersdk method set.
+const MethodStatusBatch = "status_batch"
+
+// statusBatchConcurrency bounds how many deployments are checked at once.
+const statusBatchConcurrency = 8
+
+// StatusBatchEntry identifies one deployment to check.
+type StatusBatchEntry struct {
+	// ID is an optional caller-chosen label echoed back in the result.
+	ID           string `json:"id,omitempty"`
+	DeployConfig string `json:"deploy_config"`
+	PriorState   string `json:"prior_state"`
+}
+
+// StatusBatchRequest is the params object of a status_batch call.
+type StatusBatchRequest struct {
+	Deployments []StatusBatchEntry `json:"deployments"`
+}
+
+// StatusBatchResult is the health of one deployment. Error is set instead of
+// Resources when the deployment could not be checked.
+type StatusBatchResult struct {
+	ID        string                  `json:"id,omitempty"`
+	Index     int                     `json:"index"`
+	Status    string                  `json:"status"`
+	Resources []deploy.ResourceStatus `json:"resources,omitempty"`
+	State     string                  `json:"state,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+}
+
+// StatusBatchResponse holds per-deployment results in request order and a
+// count of deployments per status.
+type StatusBatchResponse struct {
+	Results []StatusBatchResult `json:"results"`
+	Summary map[string]int      `json:"summary"`
+}
+
+// batchItem is a parsed deployment waiting to be checked.
+type batchItem struct {
+	index int
+	cfg   *Config
+	state *AdapterState
+}
+
+// StatusBatch checks many deployments in one call. Deployments in the same
+// region with the same AWS credentials settings share one checker, so
+// credentials and clients are resolved once per region and account, and
+// checks run concurrently. A deployment that cannot be
+// checked is reported with status "error" without failing the batch.
+func (p *Provider) StatusBatch(ctx context.Context, req *StatusBatchRequest) *StatusBatchResponse {
+	results := make([]StatusBatchResult, len(req.Deployments))
+	byClient := make(map[string][]batchItem)
+	var keys []string
+
+	for i, entry := range req.Deployments {
+		results[i] = StatusBatchResult{ID: entry.ID, Index: i}
+		item, status, err := p.parseBatchEntry(i, entry)
+		if status != "" {
+			results[i].Status, results[i].Error = status, errString(err)
+			continue
+		}
+		key := item.cfg.awsClientKey()
+		if _, ok := byClient[key]; !ok {
+			keys = append(keys, key)
+		}
+		byClient[key] = append(byClient[key], item)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, statusBatchConcurrency)
+	for _, key := range keys {
+		items := byClient[key]
+		checker, ok := p.regionChecker(ctx, items[0].cfg.Region, items, results)
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				resp := checkDeployment(ctx, checker, item.state)
+				r := &results[item.index]
+				r.Status, r.Resources, r.State = resp.Status, resp.Resources, resp.State
+			}()
+		}
+	}
+	wg.Wait()
+
+	summary := make(map[string]int)
+	for _, r := range results {
+		summary[r.Status]++
+	}
+	return &StatusBatchResponse{Results: results, Summary: summary}
+}
+
+// regionChecker creates the checker shared by a region's deployments. If it
+// cannot be created, every deployment in the region is marked as an error.
+func (p *Provider) regionChecker(
+	ctx context.Context, region string, items []batchItem, results []StatusBatchResult,
+) (resourceChecker, bool) {
+	checker, err := p.checkerFunc(ctx, items[0].cfg)
+	if err == nil {
+		return checker, true
+	}
+	for _, item := range items {
+		results[item.index].Status = DeployStatusError
+		results[item.index].Error = fmt.Sprintf("failed to create checker for region %s: %v", region, err)
+	}
+	return nil, false
+}
+
+// parseBatchEntry parses one batch entry. It returns a non-empty status when
+// the entry is already resolved (not deployed, or invalid) and needs no check.
+func (p *Provider) parseBatchEntry(index int, entry StatusBatchEntry) (batchItem, string, error) {
+	state, err := parseAdapterState(entry.PriorState)
+	if err != nil {
+		return batchItem{}, DeployStatusError, fmt.Errorf("failed to parse prior state: %w", err)
+	}
+	if len(state.Resources) == 0 {
+		return batchItem{}, DeployStatusNotDeployed, nil
+	}
+	cfg, err := p.loadConfig(entry.DeployConfig, state.Variables)
+	if err != nil {
+		return batchItem{}, DeployStatusError, fmt.Errorf("failed to parse deploy config: %w", err)
+	}
+	if err := checkStateWorkspace(state, cfg); err != nil {
+		return batchItem{}, DeployStatusError, err
+	}
+	return batchItem{index: index, cfg: cfg, state: state}, "", nil
+}
+
+// errString returns err's message, or "" for a nil error.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
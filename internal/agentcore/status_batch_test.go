@@ -0,0 +1,106 @@
+package agentcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func regionConfig(region string) string {
+	return fmt.Sprintf(`{"region":%q,"runtime_role_arn":"arn:aws:iam::123456789012:role/test"}`, region)
+}
+
+func TestStatusBatch_MixedDeployments(t *testing.T) {
+	var mu sync.Mutex
+	factoryCalls := map[string]int{}
+	p := &Provider{
+		checkerFunc: func(_ context.Context, cfg *Config) (resourceChecker, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			factoryCalls[cfg.Region]++
+			if cfg.Region == "eu-west-1" {
+				return nil, fmt.Errorf("no credentials")
+			}
+			return &failingChecker{unhealthyTypes: map[string]bool{ResTypeEvaluator: true}}, nil
+		},
+	}
+	healthy := mustJSON(t, &AdapterState{Resources: []ResourceState{{Type: ResTypeAgentRuntime, Name: "rt"}}})
+	degraded := mustJSON(t, &AdapterState{Resources: []ResourceState{{Type: ResTypeEvaluator, Name: "ev"}}})
+
+	resp := p.StatusBatch(context.Background(), &StatusBatchRequest{Deployments: []StatusBatchEntry{
+		{ID: "a", DeployConfig: regionConfig("us-west-2"), PriorState: healthy},
+		{ID: "b", DeployConfig: regionConfig("us-west-2"), PriorState: degraded},
+		{ID: "c", DeployConfig: regionConfig("us-east-1"), PriorState: healthy},
+		{ID: "d", DeployConfig: regionConfig("us-west-2"), PriorState: ""},
+		{ID: "e", DeployConfig: regionConfig("us-west-2"), PriorState: "{bad"},
+		{ID: "f", DeployConfig: regionConfig("eu-west-1"), PriorState: healthy},
+	}})
+
+	want := []string{
+		DeployStatusDeployed, DeployStatusDegraded, DeployStatusDeployed,
+		DeployStatusNotDeployed, DeployStatusError, DeployStatusError,
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(resp.Results), len(want))
+	}
+	for i, r := range resp.Results {
+		if r.Index != i || r.ID != string(rune('a'+i)) {
+			t.Errorf("result %d: index=%d id=%q", i, r.Index, r.ID)
+		}
+		if r.Status != want[i] {
+			t.Errorf("result %d status = %q, want %q (error %q)", i, r.Status, want[i], r.Error)
+		}
+	}
+	if resp.Results[5].Error == "" || !strings.Contains(resp.Results[5].Error, "eu-west-1") {
+		t.Errorf("checker failure error = %q", resp.Results[5].Error)
+	}
+	if resp.Summary[DeployStatusDeployed] != 2 || resp.Summary[DeployStatusError] != 2 {
+		t.Errorf("summary = %v", resp.Summary)
+	}
+	for region, n := range factoryCalls {
+		if n != 1 {
+			t.Errorf("checker created %d times for %s, want once per region", n, region)
+		}
+	}
+}
+
+func TestServeIO_StatusBatch(t *testing.T) {
+	state := mustJSON(t, sampleState())
+	params := map[string]any{"deployments": []map[string]string{
+		{"id": "one", "deploy_config": validDestroyConfig(), "prior_state": state},
+	}}
+
+	var out bytes.Buffer
+	input := jsonRPCRequest(MethodStatusBatch, 4, params) + jsonRPCRequest(MethodStatusBatch, 5, "bad")
+	if err := ServeIO(newSimulatedProvider(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ServeIO error: %v", err)
+	}
+
+	dec := json.NewDecoder(&out)
+	var ok, bad jsonRPCResponse
+	if err := dec.Decode(&ok); err != nil {
+		t.Fatalf("decode first response: %v", err)
+	}
+	if err := dec.Decode(&bad); err != nil {
+		t.Fatalf("decode second response: %v", err)
+	}
+
+	if ok.Error != nil {
+		t.Fatalf("unexpected error: %s", ok.Error.Message)
+	}
+	var batch StatusBatchResponse
+	if err := json.Unmarshal(ok.Result, &batch); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if len(batch.Results) != 1 || batch.Results[0].ID != "one" || batch.Results[0].Status != DeployStatusDeployed {
+		t.Errorf("results = %+v", batch.Results)
+	}
+
+	if bad.Error == nil || !strings.Contains(bad.Error.Message, "invalid params") {
+		t.Errorf("expected invalid params error, got %+v", bad)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/AltairaLabs/PromptKit/runtime/deploy"
@@ -94,10 +95,10 @@ func TestDestroy_ValidState_ReverseOrder(t *testing.T) {
 		}
 	}
 
-	// Expected reverse dependency order: tool_gateway first (it depends on
-	// the policy engine so must be deleted before cedar_policy), then
-	// evaluator, a2a_endpoint, agent_runtime.
-	expected := []string{"tool_gateway", "evaluator", "a2a_endpoint", "agent_runtime"}
+	// Expected reverse dependency order: dependents before the types they
+	// depend on, so the A2A wiring goes before its runtime and the runtime
+	// before the gateway it calls.
+	expected := []string{"evaluator", "a2a_endpoint", "agent_runtime", "tool_gateway"}
 	if len(deletedTypes) != len(expected) {
 		t.Fatalf("deleted %d resources, want %d: %v", len(deletedTypes), len(expected), deletedTypes)
 	}
@@ -501,6 +502,72 @@ func TestStatus_CheckerReturnsError_MarksUnhealthy(t *testing.T) {
 	if len(resp.Resources) != 1 || resp.Resources[0].Status != "unhealthy" {
 		t.Errorf("expected unhealthy resource, got %+v", resp.Resources)
 	}
+	if !strings.Contains(resp.Resources[0].Detail, "simulated check error") {
+		t.Errorf("detail = %q, want the check error", resp.Resources[0].Detail)
+	}
+}
+
+func TestCheckGateway_Targets(t *testing.T) {
+	tests := []struct {
+		name       string
+		bodies     []string
+		wantStatus string
+		wantDetail string
+	}{
+		{
+			name:       "target ready",
+			bodies:     []string{`{"status":"READY"}`, `{"items":[{"name":"search","status":"READY"}]}`},
+			wantStatus: StatusHealthy,
+		},
+		{
+			name:       "target failed",
+			bodies:     []string{`{"status":"READY"}`, `{"items":[{"name":"search","status":"FAILED"}]}`},
+			wantStatus: StatusUnhealthy,
+			wantDetail: `target "search" is FAILED`,
+		},
+		{
+			name:       "target deleted",
+			bodies:     []string{`{"status":"READY"}`, `{"items":[{"name":"lookup","status":"READY"}]}`},
+			wantStatus: StatusMissing,
+			wantDetail: `target "search" not found on gateway`,
+		},
+		{
+			name:       "gateway not ready",
+			bodies:     []string{`{"status":"UPDATING"}`},
+			wantStatus: StatusUnhealthy,
+			wantDetail: "gateway gw-1 is UPDATING",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newStubbedRealClient(1, tt.bodies...)
+			res := ResourceState{
+				Type: ResTypeToolGateway, Name: "search",
+				ARN: "arn:aws:bedrock-agentcore:us-west-2:123456789012:gateway/gw-1",
+			}
+			status, detail, err := c.CheckResourceDetail(context.Background(), res)
+			if err != nil {
+				t.Fatalf("CheckResourceDetail: %v", err)
+			}
+			if status != tt.wantStatus || detail != tt.wantDetail {
+				t.Errorf("got %q/%q, want %q/%q", status, detail, tt.wantStatus, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestListGatewayTargets_Paginates(t *testing.T) {
+	c, _, stub := newStubbedRealClient(1,
+		`{"items":[{"name":"a","status":"READY"}],"nextToken":"p2"}`,
+		`{"items":[{"name":"b","status":"READY"}]}`)
+
+	targets, err := c.listGatewayTargets(context.Background(), "gw-1")
+	if err != nil {
+		t.Fatalf("listGatewayTargets: %v", err)
+	}
+	if len(targets) != 2 || stub.calls != 2 {
+		t.Errorf("got %d targets in %d calls, want 2 in 2", len(targets), stub.calls)
+	}
 }
 
 func TestStatus_InvalidConfig(t *testing.T) {
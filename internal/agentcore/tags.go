@@ -10,16 +10,20 @@ const (
 // buildResourceTags merges default pack metadata tags with user-defined tags
 // from the config. User-defined tags take precedence over defaults when keys
 // overlap. The agentName parameter is optional; when non-empty it sets the
-// promptpack:agent tag for multi-agent packs.
+// promptpack:agent tag for multi-agent packs. A non-empty workspace sets the
+// promptpack:workspace tag.
 func buildResourceTags(
-	packID, version, agentName string,
+	packID, version, workspace, agentName string,
 	userTags map[string]string,
 ) map[string]string {
-	tags := make(map[string]string, len(userTags)+3) //nolint:mnd // 3 default tag keys
+	tags := make(map[string]string, len(userTags)+4) //nolint:mnd // 4 default tag keys
 
 	// Default pack metadata tags.
 	tags[TagKeyPackID] = packID
 	tags[TagKeyVersion] = version
+	if workspace != "" {
+		tags[TagKeyWorkspace] = workspace
+	}
 	if agentName != "" {
 		tags[TagKeyAgent] = agentName
 	}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestBuildResourceTags_DefaultsOnly(t *testing.T) {
-	tags := buildResourceTags("mypack", "v1.0.0", "", nil)
+	tags := buildResourceTags("mypack", "v1.0.0", "", "", nil)
 
 	if tags[TagKeyPackID] != "mypack" {
 		t.Errorf("pack-id = %q, want mypack", tags[TagKeyPackID])
@@ -22,7 +22,7 @@ func TestBuildResourceTags_DefaultsOnly(t *testing.T) {
 }
 
 func TestBuildResourceTags_WithAgentName(t *testing.T) {
-	tags := buildResourceTags("mypack", "v1.0.0", "coordinator", nil)
+	tags := buildResourceTags("mypack", "v1.0.0", "", "coordinator", nil)
 
 	if tags[TagKeyAgent] != "coordinator" {
 		t.Errorf("agent = %q, want coordinator", tags[TagKeyAgent])
@@ -38,7 +38,7 @@ func TestBuildResourceTags_UserTagsMerged(t *testing.T) {
 		"team":    "platform",
 		"project": "chatbot",
 	}
-	tags := buildResourceTags("mypack", "v1.0.0", "", userTags)
+	tags := buildResourceTags("mypack", "v1.0.0", "", "", userTags)
 
 	if tags["env"] != "production" {
 		t.Errorf("env = %q, want production", tags["env"])
@@ -59,7 +59,7 @@ func TestBuildResourceTags_UserOverridesDefault(t *testing.T) {
 	userTags := map[string]string{
 		TagKeyPackID: "custom-id",
 	}
-	tags := buildResourceTags("mypack", "v1.0.0", "", userTags)
+	tags := buildResourceTags("mypack", "v1.0.0", "", "", userTags)
 
 	if tags[TagKeyPackID] != "custom-id" {
 		t.Errorf("pack-id = %q, want custom-id (user override)", tags[TagKeyPackID])
@@ -0,0 +1,173 @@
+package agentcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+// Environment variables controlling the runtime's tool-call audit trail.
+const (
+	EnvToolAudit          = "PROMPTPACK_TOOL_AUDIT"
+	EnvToolAuditMaxEvents = "PROMPTPACK_TOOL_AUDIT_MAX_EVENTS"
+)
+
+// Constants for the tool-call audit trail.
+const (
+	// ToolAuditActorID is the memory actor that owns audit events. Keeping
+	// them under their own actor leaves the conversation history written by
+	// StateStore (actor defaultActorID, or its namespace) untouched.
+	ToolAuditActorID = "promptkit-tool-audit"
+
+	// toolAuditPrefix marks the JSON text of an audit event payload.
+	toolAuditPrefix = "promptkit:tool_audit:"
+
+	// toolAuditKindKey and toolAuditKind tag audit events in their metadata.
+	toolAuditKindKey = "kind"
+	toolAuditKind    = "tool_call"
+
+	// DefaultToolAuditMaxEvents caps audit events per session.
+	DefaultToolAuditMaxEvents = 200
+
+	// maxToolAuditMaxEvents is the largest accepted per-session cap.
+	maxToolAuditMaxEvents = 10000
+
+	// maxToolAuditErrorLen truncates error text stored on a record.
+	maxToolAuditErrorLen = 256
+)
+
+// Tool call outcomes recorded on a ToolAuditRecord.
+const (
+	ToolOutcomeOK    = "ok"
+	ToolOutcomeError = "error"
+)
+
+// ToolAuditConfig enables the runtime's tool-call audit trail.
+type ToolAuditConfig struct {
+	Enabled             bool `json:"enabled,omitempty"`
+	MaxEventsPerSession int  `json:"max_events_per_session,omitempty"`
+}
+
+// ToolAuditRecord is one tool invocation as stored in memory. Arguments are
+// only stored as a hash and a size so that no tool input leaves the runtime.
+type ToolAuditRecord struct {
+	Tool        string `json:"tool"`
+	CallID      string `json:"call_id,omitempty"`
+	ArgsSHA256  string `json:"args_sha256"`
+	ArgsBytes   int    `json:"args_bytes"`
+	ResultBytes int    `json:"result_bytes"`
+	DurationMs  int64  `json:"duration_ms"`
+	Outcome     string `json:"outcome"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NewToolAuditRecord builds a record from a completed tool call, hashing the
+// raw arguments and truncating the error text.
+func NewToolAuditRecord(tool, callID string, args []byte, result, errText string, durationMs int64) ToolAuditRecord {
+	sum := sha256.Sum256(args)
+	rec := ToolAuditRecord{
+		Tool:        tool,
+		CallID:      callID,
+		ArgsSHA256:  hex.EncodeToString(sum[:]),
+		ArgsBytes:   len(args),
+		ResultBytes: len(result),
+		DurationMs:  durationMs,
+		Outcome:     ToolOutcomeOK,
+	}
+	if errText != "" {
+		rec.Outcome = ToolOutcomeError
+		if len(errText) > maxToolAuditErrorLen {
+			errText = errText[:maxToolAuditErrorLen]
+		}
+		rec.Error = errText
+	}
+	return rec
+}
+
+// ToolAuditor writes tool-call audit records as memory events, one event per
+// call, up to a per-session cap.
+type ToolAuditor struct {
+	memoryID  string
+	client    DataPlaneClient
+	maxEvents int
+	counts    map[string]int
+	mu        sync.Mutex
+}
+
+// NewToolAuditor creates a ToolAuditor. A maxEvents of 0 or less uses
+// DefaultToolAuditMaxEvents.
+func NewToolAuditor(memoryID string, client DataPlaneClient, maxEvents int) *ToolAuditor {
+	if maxEvents <= 0 {
+		maxEvents = DefaultToolAuditMaxEvents
+	}
+	return &ToolAuditor{
+		memoryID:  memoryID,
+		client:    client,
+		maxEvents: maxEvents,
+		counts:    make(map[string]int),
+	}
+}
+
+// Record writes rec as an audit event in sessionID. It returns false without
+// writing once the session has reached its cap.
+func (a *ToolAuditor) Record(ctx context.Context, sessionID string, rec ToolAuditRecord) (bool, error) {
+	a.mu.Lock()
+	if a.counts[sessionID] >= a.maxEvents {
+		a.mu.Unlock()
+		return false, nil
+	}
+	a.counts[sessionID]++
+	a.mu.Unlock()
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, fmt.Errorf("encode tool audit record: %w", err)
+	}
+	now := time.Now()
+	_, err = a.client.CreateEvent(ctx, &bedrockagentcore.CreateEventInput{
+		MemoryId:       aws.String(a.memoryID),
+		ActorId:        aws.String(ToolAuditActorID),
+		SessionId:      aws.String(sessionID),
+		EventTimestamp: &now,
+		Payload: []dpTypes.PayloadType{
+			&dpTypes.PayloadTypeMemberConversational{
+				Value: dpTypes.Conversational{
+					Role:    dpTypes.RoleTool,
+					Content: &dpTypes.ContentMemberText{Value: toolAuditPrefix + string(data)},
+				},
+			},
+		},
+		Metadata: map[string]dpTypes.MetadataValue{
+			toolAuditKindKey: &dpTypes.MetadataValueMemberStringValue{Value: toolAuditKind},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("CreateEvent tool audit for session %q: %w", sessionID, err)
+	}
+	return true, nil
+}
+
+// validateToolAudit checks the tools.audit settings. Auditing writes to the
+// deployment's memory, so it requires memory_store.
+func validateToolAudit(audit *ToolAuditConfig, hasMemory bool) []string {
+	if audit == nil {
+		return nil
+	}
+	var errs []string
+	if audit.Enabled && !hasMemory {
+		errs = append(errs, "tools.audit.enabled requires memory_store")
+	}
+	if audit.MaxEventsPerSession < 0 || audit.MaxEventsPerSession > maxToolAuditMaxEvents {
+		errs = append(errs, fmt.Sprintf("tools.audit.max_events_per_session %d must be between 0 and %d",
+			audit.MaxEventsPerSession, maxToolAuditMaxEvents))
+	}
+	return errs
+}
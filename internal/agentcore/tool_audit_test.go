@@ -0,0 +1,136 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	dpTypes "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore/types"
+)
+
+func TestNewToolAuditRecord(t *testing.T) {
+	rec := NewToolAuditRecord("lookup", "call-1", []byte(`{"id":1}`), "found", "", 42)
+	if rec.Outcome != ToolOutcomeOK || rec.Error != "" {
+		t.Errorf("outcome = %q error = %q", rec.Outcome, rec.Error)
+	}
+	if len(rec.ArgsSHA256) != 64 || rec.ArgsBytes != 8 || rec.ResultBytes != 5 || rec.DurationMs != 42 {
+		t.Errorf("record = %+v", rec)
+	}
+	if again := NewToolAuditRecord("lookup", "call-2", []byte(`{"id":1}`), "", "", 0); again.ArgsSHA256 != rec.ArgsSHA256 {
+		t.Error("same arguments should hash identically")
+	}
+
+	failed := NewToolAuditRecord("lookup", "", nil, "", strings.Repeat("x", 1000), 3)
+	if failed.Outcome != ToolOutcomeError || len(failed.Error) != maxToolAuditErrorLen {
+		t.Errorf("failed record outcome = %q, error length %d", failed.Outcome, len(failed.Error))
+	}
+}
+
+func TestToolAuditor_RecordWritesEvent(t *testing.T) {
+	mock := &mockDataPlaneClient{}
+	auditor := NewToolAuditor("mem-1", mock, 0)
+
+	rec := NewToolAuditRecord("lookup", "call-1", []byte(`{}`), "ok", "", 7)
+	written, err := auditor.Record(context.Background(), "sess-1", rec)
+	if err != nil || !written {
+		t.Fatalf("Record = %v, %v", written, err)
+	}
+	if len(mock.createCalls) != 1 {
+		t.Fatalf("CreateEvent calls = %d, want 1", len(mock.createCalls))
+	}
+	in := mock.createCalls[0]
+	if aws.ToString(in.ActorId) != ToolAuditActorID || aws.ToString(in.SessionId) != "sess-1" ||
+		aws.ToString(in.MemoryId) != "mem-1" {
+		t.Errorf("event ids = %s/%s/%s", aws.ToString(in.MemoryId), aws.ToString(in.ActorId), aws.ToString(in.SessionId))
+	}
+	kind, ok := in.Metadata[toolAuditKindKey].(*dpTypes.MetadataValueMemberStringValue)
+	if !ok || kind.Value != toolAuditKind {
+		t.Errorf("metadata = %v", in.Metadata)
+	}
+	conv := in.Payload[0].(*dpTypes.PayloadTypeMemberConversational).Value
+	text := conv.Content.(*dpTypes.ContentMemberText).Value
+	var got ToolAuditRecord
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(text, toolAuditPrefix)), &got); err != nil {
+		t.Fatalf("decode payload %q: %v", text, err)
+	}
+	if got != rec || conv.Role != dpTypes.RoleTool {
+		t.Errorf("payload = %+v role %s, want %+v", got, conv.Role, rec)
+	}
+}
+
+func TestToolAuditor_PerSessionCap(t *testing.T) {
+	mock := &mockDataPlaneClient{}
+	auditor := NewToolAuditor("mem-1", mock, 2)
+	rec := NewToolAuditRecord("t", "", nil, "", "", 0)
+
+	for i, want := range []bool{true, true, false} {
+		written, err := auditor.Record(context.Background(), "sess-1", rec)
+		if err != nil || written != want {
+			t.Errorf("call %d: written = %v, err = %v, want %v", i, written, err, want)
+		}
+	}
+	if written, _ := auditor.Record(context.Background(), "sess-2", rec); !written {
+		t.Error("cap should be per session")
+	}
+	if len(mock.createCalls) != 3 {
+		t.Errorf("CreateEvent calls = %d, want 3", len(mock.createCalls))
+	}
+}
+
+func TestToolAuditor_RecordError(t *testing.T) {
+	mock := &mockDataPlaneClient{
+		createEventFn: func(context.Context, *bedrockagentcore.CreateEventInput,
+			...func(*bedrockagentcore.Options)) (*bedrockagentcore.CreateEventOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+	_, err := NewToolAuditor("mem-1", mock, 0).Record(context.Background(), "sess-1", ToolAuditRecord{})
+	if err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestValidateToolAudit(t *testing.T) {
+	tests := []struct {
+		name      string
+		audit     *ToolAuditConfig
+		hasMemory bool
+		wantErr   string
+	}{
+		{name: "unset"},
+		{name: "enabled with memory", audit: &ToolAuditConfig{Enabled: true}, hasMemory: true},
+		{name: "enabled without memory", audit: &ToolAuditConfig{Enabled: true}, wantErr: "requires memory_store"},
+		{name: "cap too large", audit: &ToolAuditConfig{MaxEventsPerSession: 20000}, wantErr: "between 0 and 10000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateToolAudit(tt.audit, tt.hasMemory)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("errs = %v, want containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildRuntimeEnvVars_ToolAudit(t *testing.T) {
+	cfg := &Config{Tools: &ToolsConfig{Audit: &ToolAuditConfig{Enabled: true, MaxEventsPerSession: 50}}}
+	env := buildRuntimeEnvVars(cfg)
+	if env[EnvToolAudit] != "true" || env[EnvToolAuditMaxEvents] != "50" {
+		t.Errorf("env = %v", env)
+	}
+
+	cfg.Tools.Audit.Enabled = false
+	if _, ok := buildRuntimeEnvVars(cfg)[EnvToolAudit]; ok {
+		t.Errorf("%s set while audit is disabled", EnvToolAudit)
+	}
+}
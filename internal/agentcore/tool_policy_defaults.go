@@ -0,0 +1,117 @@
+package agentcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+// toolPolicyDefaults is a pack's top-level "tool_policy_defaults" block,
+// which PromptKit passes through untouched: tool policy settings every
+// prompt gets. A prompt's own max_rounds and max_tool_calls_per_turn win;
+// blocklists add up, so a prompt cannot unblock a tool the defaults block.
+type toolPolicyDefaults struct {
+	MaxRounds           int      `json:"max_rounds,omitempty"`
+	MaxToolCallsPerTurn int      `json:"max_tool_calls_per_turn,omitempty"`
+	Blocklist           []string `json:"blocklist,omitempty"`
+}
+
+// parsePack parses the pack JSON and merges its tool_policy_defaults into
+// every prompt's tool policy, so Cedar generation, lint, and the runtime
+// see the policies in effect.
+func parsePack(packJSON string) (*prompt.Pack, error) {
+	pack, err := adaptersdk.ParsePack([]byte(packJSON))
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := parseToolPolicyDefaults(packJSON)
+	if err != nil || defaults == nil {
+		return pack, err
+	}
+	for _, p := range pack.Prompts {
+		if p != nil {
+			p.ToolPolicy = defaults.merge(p.ToolPolicy)
+		}
+	}
+	return pack, nil
+}
+
+// parseToolPolicyDefaults reads and checks the tool_policy_defaults block
+// of the raw pack JSON. It returns nil when the pack has none.
+func parseToolPolicyDefaults(packJSON string) (*toolPolicyDefaults, error) {
+	var raw struct {
+		Defaults *toolPolicyDefaults `json:"tool_policy_defaults"`
+	}
+	if err := json.Unmarshal([]byte(packJSON), &raw); err != nil {
+		return nil, fmt.Errorf("invalid tool_policy_defaults: %w", err)
+	}
+	d := raw.Defaults
+	if d == nil {
+		return nil, nil
+	}
+	if d.MaxRounds < 0 || d.MaxToolCallsPerTurn < 0 {
+		return nil, fmt.Errorf("invalid tool_policy_defaults: max_rounds and max_tool_calls_per_turn " +
+			"must not be negative")
+	}
+	if slices.Contains(d.Blocklist, "") {
+		return nil, fmt.Errorf("invalid tool_policy_defaults: blocklist entries must not be empty")
+	}
+	return d, nil
+}
+
+// merge returns tp with the defaults filled in. tp may be nil.
+func (d *toolPolicyDefaults) merge(tp *prompt.ToolPolicyPack) *prompt.ToolPolicyPack {
+	merged := prompt.ToolPolicyPack{}
+	if tp != nil {
+		merged = *tp
+	}
+	if merged.MaxRounds == 0 {
+		merged.MaxRounds = d.MaxRounds
+	}
+	if merged.MaxToolCallsPerTurn == 0 {
+		merged.MaxToolCallsPerTurn = d.MaxToolCallsPerTurn
+	}
+	blocklist := slices.Clone(d.Blocklist)
+	for _, tool := range merged.Blocklist {
+		if !slices.Contains(blocklist, tool) {
+			blocklist = append(blocklist, tool)
+		}
+	}
+	merged.Blocklist = blocklist
+	return &merged
+}
+
+// runtimePackJSON returns the pack JSON the runtime loads: packJSON with
+// each prompt's tool_policy replaced by the merged one of pack, so the
+// runtime enforces the defaults' limits. Packs without
+// tool_policy_defaults are returned unchanged.
+func runtimePackJSON(packJSON string, pack *prompt.Pack) (string, error) {
+	defaults, err := parseToolPolicyDefaults(packJSON)
+	if err != nil || defaults == nil {
+		return packJSON, err
+	}
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal([]byte(packJSON), &raw); err != nil {
+		return "", err
+	}
+	var prompts map[string]map[string]json.RawMessage
+	if err = json.Unmarshal(raw["prompts"], &prompts); err != nil {
+		return "", fmt.Errorf("pack prompts: %w", err)
+	}
+	for name, p := range prompts {
+		if pack.Prompts[name] == nil || p == nil {
+			continue
+		}
+		if p["tool_policy"], err = json.Marshal(pack.Prompts[name].ToolPolicy); err != nil {
+			return "", err
+		}
+	}
+	if raw["prompts"], err = json.Marshal(prompts); err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(raw)
+	return string(out), err
+}
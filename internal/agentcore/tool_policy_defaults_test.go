@@ -0,0 +1,102 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// packWithToolPolicyDefaults returns singleAgentPackWithTools with a
+// second prompt setting its own policy, and pack-level defaults.
+func packWithToolPolicyDefaults() string {
+	var p map[string]any
+	_ = json.Unmarshal([]byte(singleAgentPackWithTools()), &p)
+	p["prompts"].(map[string]any)["strict"] = map[string]any{
+		"id": "strict", "name": "Strict", "system_template": "You check.", "version": "v1.0.0",
+		"tools":       []string{"search"},
+		"tool_policy": map[string]any{"max_rounds": 2, "blocklist": []string{"search"}},
+	}
+	p["tool_policy_defaults"] = map[string]any{
+		"max_rounds": 5, "max_tool_calls_per_turn": 3, "blocklist": []string{"calc"},
+	}
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+func TestParsePack_MergesToolPolicyDefaults(t *testing.T) {
+	pack, err := parsePack(packWithToolPolicyDefaults())
+	if err != nil {
+		t.Fatalf("parsePack: %v", err)
+	}
+	chat := pack.Prompts["chat"].ToolPolicy
+	if chat == nil || chat.MaxRounds != 5 || chat.MaxToolCallsPerTurn != 3 ||
+		!slices.Equal(chat.Blocklist, []string{"calc"}) {
+		t.Errorf("chat policy = %+v, want the defaults", chat)
+	}
+	strict := pack.Prompts["strict"].ToolPolicy
+	if strict.MaxRounds != 2 || strict.MaxToolCallsPerTurn != 3 ||
+		!slices.Equal(strict.Blocklist, []string{"calc", "search"}) {
+		t.Errorf("strict policy = %+v, want its max_rounds and both blocklists", strict)
+	}
+}
+
+func TestParsePack_InvalidToolPolicyDefaults(t *testing.T) {
+	for _, defaults := range []string{`{"max_rounds":-1}`, `{"blocklist":[""]}`, `"strict"`} {
+		pack := strings.TrimSuffix(singleAgentPack(), "}") + `,"tool_policy_defaults":` + defaults + `}`
+		if _, err := parsePack(pack); err == nil || !strings.Contains(err.Error(), "tool_policy_defaults") {
+			t.Errorf("defaults %s: error = %v", defaults, err)
+		}
+	}
+}
+
+func TestRuntimePackJSON(t *testing.T) {
+	if got, err := runtimePackJSON(singleAgentPack(), nil); err != nil || got != singleAgentPack() {
+		t.Errorf("pack without defaults changed: %v", err)
+	}
+
+	raw := packWithToolPolicyDefaults()
+	pack, err := parsePack(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := runtimePackJSON(raw, pack)
+	if err != nil {
+		t.Fatalf("runtimePackJSON: %v", err)
+	}
+	var shipped struct {
+		ID      string `json:"id"`
+		Prompts map[string]struct {
+			SystemTemplate string `json:"system_template"`
+			ToolPolicy     struct {
+				MaxRounds int      `json:"max_rounds"`
+				Blocklist []string `json:"blocklist"`
+			} `json:"tool_policy"`
+		} `json:"prompts"`
+	}
+	if err = json.Unmarshal([]byte(got), &shipped); err != nil {
+		t.Fatal(err)
+	}
+	chat := shipped.Prompts["chat"]
+	if shipped.ID != "toolpack" || chat.SystemTemplate != "You help." || chat.ToolPolicy.MaxRounds != 5 ||
+		!slices.Equal(chat.ToolPolicy.Blocklist, []string{"calc"}) {
+		t.Errorf("shipped pack = %s", got)
+	}
+}
+
+func TestPlan_ToolPolicyDefaultsAddCedarPolicies(t *testing.T) {
+	resp, err := newSimulatedProvider().Plan(context.Background(), &deploy.PlanRequest{
+		PackJSON: packWithToolPolicyDefaults(), DeployConfig: validConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	for _, name := range []string{"chat", "strict"} {
+		if findChange(resp.Changes, ResTypeCedarPolicy, name) == nil {
+			t.Errorf("no cedar_policy change for %s in %+v", name, resp.Changes)
+		}
+	}
+}
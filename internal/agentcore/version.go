@@ -1,5 +1,12 @@
 package agentcore
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
 // Build-time variables injected via ldflags.
 var (
 	// Version is the semantic version of this build.
@@ -9,3 +16,48 @@ var (
 	// Date is the build timestamp.
 	Date = "unknown"
 )
+
+// MethodVersion is the JSON-RPC method that returns a VersionResponse. It
+// extends the standard adaptersdk method set.
+const MethodVersion = "version"
+
+// VersionResponse identifies the adapter build.
+type VersionResponse struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+}
+
+// String describes the build for progress messages, e.g.
+// "agentcore adapter v0.9.0 (commit abc1234, built 2026-01-01T00:00:00Z)".
+func (v *VersionResponse) String() string {
+	return fmt.Sprintf("%s adapter %s (commit %s, built %s)", v.Name, v.Version, v.Commit, v.BuildDate)
+}
+
+// Version reports the adapter's version, commit, and build date.
+func (p *Provider) Version(_ context.Context) *VersionResponse {
+	return &VersionResponse{Name: providerName, Version: Version, Commit: Commit, BuildDate: Date}
+}
+
+// checkMinAdapterVersion fails when the arena config requires a newer
+// adapter than this build. Development builds, whose version is not
+// semver, are not checked.
+func checkMinAdapterVersion(minVersion string) error {
+	if minVersion == "" {
+		return nil
+	}
+	required, err := semver.NewVersion(minVersion)
+	if err != nil {
+		return fmt.Errorf("invalid min_adapter_version %q: %w", minVersion, err)
+	}
+	current, err := semver.NewVersion(Version)
+	if err != nil {
+		return nil //nolint:nilerr // development builds skip the check
+	}
+	if current.LessThan(required) {
+		return fmt.Errorf("arena_config requires %s adapter %s or newer, but this is %s; upgrade the adapter",
+			providerName, required.Original(), current.Original())
+	}
+	return nil
+}
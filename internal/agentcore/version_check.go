@@ -0,0 +1,162 @@
+package agentcore
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/Masterminds/semver/v3"
+)
+
+// promptKitRuntimeModule is the module whose version determines which pack
+// formats a runtime binary can parse.
+const promptKitRuntimeModule = "github.com/AltairaLabs/PromptKit/runtime"
+
+// supportedRuntimePromptKit is the range of PromptKit runtime versions a
+// runtime binary may embed for this adapter to deploy it. The lower bound is
+// the first release with the HTTP bridge contract the adapter relies on.
+const supportedRuntimePromptKit = ">= 1.5.0, < 2.0.0"
+
+// runtimeVersionFlagRE extracts the runtime's own version from the -X
+// ldflag recorded in its build settings.
+var runtimeVersionFlagRE = regexp.MustCompile(`main\.version=(\S+)`)
+
+// compiledWithVersionRE extracts a version from the pack's compiled_with
+// field (e.g. "packc v1.5.2").
+var compiledWithVersionRE = regexp.MustCompile(`v?(\d+(?:\.\d+){0,2})`)
+
+// runtimeVersionInfo describes the versions embedded in a runtime binary.
+type runtimeVersionInfo struct {
+	// Version is the runtime binary's own version, or "" if not stamped.
+	Version string
+	// PromptKit is the PromptKit runtime module version it was built with.
+	PromptKit string
+}
+
+// String describes the runtime for messages, e.g. "v0.4.0 (PromptKit v1.5.2)".
+func (v *runtimeVersionInfo) String() string {
+	if v.Version == "" {
+		return "PromptKit " + v.PromptKit
+	}
+	return fmt.Sprintf("%s (PromptKit %s)", v.Version, v.PromptKit)
+}
+
+// readRuntimeVersion reads the Go build info embedded in the runtime binary
+// at path. It works for any target OS/architecture since it only parses the
+// binary, never executes it.
+func readRuntimeVersion(path string) (*runtimeVersionInfo, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &runtimeVersionInfo{}
+	for _, s := range info.Settings {
+		if s.Key == "-ldflags" {
+			if m := runtimeVersionFlagRE.FindStringSubmatch(s.Value); m != nil {
+				v.Version = m[1]
+			}
+		}
+	}
+	if v.Version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		v.Version = info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != promptKitRuntimeModule {
+			continue
+		}
+		v.PromptKit = dep.Version
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			v.PromptKit = dep.Replace.Version
+		}
+	}
+	return v, nil
+}
+
+// checkRuntimeCompatibility compares the runtime binary's embedded versions
+// against the adapter's supported range and the pack's compiler version.
+// It returns an error for skew the runtime cannot survive (it would crash on
+// startup and loop in CREATE_FAILED) and warnings for skew it may tolerate.
+// Binaries without readable build info produce a warning, not an error.
+func checkRuntimeCompatibility(binaryPath string, pack *prompt.Pack) ([]string, error) {
+	rv, err := readRuntimeVersion(binaryPath)
+	if err != nil {
+		return []string{fmt.Sprintf(
+			"could not read version info from runtime binary %s (%v); skipping version check",
+			binaryPath, err)}, nil
+	}
+	if rv.PromptKit == "" {
+		return []string{fmt.Sprintf(
+			"runtime binary %s does not embed %s; is it the agentcore-runtime binary?",
+			binaryPath, promptKitRuntimeModule)}, nil
+	}
+
+	rtVer, err := semver.NewVersion(rv.PromptKit)
+	if err != nil {
+		return []string{fmt.Sprintf(
+			"runtime PromptKit version %q is not semver; skipping version check", rv.PromptKit)}, nil
+	}
+	supported, err := semver.NewConstraint(supportedRuntimePromptKit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid supported runtime range %q: %w", supportedRuntimePromptKit, err)
+	}
+	if !supported.Check(rtVer) {
+		return nil, fmt.Errorf(
+			"runtime binary %s is %s, outside the supported PromptKit range %q; "+
+				"rebuild the runtime with a matching PromptKit version",
+			binaryPath, rv, supportedRuntimePromptKit)
+	}
+
+	return checkPackCompiler(pack, rv, rtVer)
+}
+
+// checkPackCompiler compares the PromptKit version the pack was compiled
+// with against the runtime's. A newer major means the runtime cannot parse
+// the pack; a newer minor means newer pack fields may be silently ignored.
+func checkPackCompiler(pack *prompt.Pack, rv *runtimeVersionInfo, rtVer *semver.Version) ([]string, error) {
+	if pack == nil || pack.Compilation == nil {
+		return nil, nil
+	}
+	m := compiledWithVersionRE.FindStringSubmatch(pack.Compilation.CompiledWith)
+	if m == nil {
+		return nil, nil
+	}
+	packVer, err := semver.NewVersion(m[1])
+	if err != nil {
+		return nil, nil //nolint:nilerr // unparseable compiler versions skip the check
+	}
+
+	switch {
+	case packVer.Major() > rtVer.Major():
+		return nil, fmt.Errorf(
+			"pack was compiled with %q but runtime %s cannot parse it; "+
+				"rebuild the runtime or recompile the pack",
+			strings.TrimSpace(pack.Compilation.CompiledWith), rv)
+	case packVer.Major() == rtVer.Major() && packVer.Minor() > rtVer.Minor():
+		return []string{fmt.Sprintf(
+			"pack was compiled with %q, newer than runtime %s; newer pack fields may be ignored",
+			strings.TrimSpace(pack.Compilation.CompiledWith), rv)}, nil
+	}
+	return nil, nil
+}
+
+// reportRuntimeCompatibility runs checkRuntimeCompatibility before any AWS
+// resource is touched, emitting warnings as progress events.
+func reportRuntimeCompatibility(
+	reporter *adaptersdk.ProgressReporter, binaryPath string, pack *prompt.Pack,
+) error {
+	warnings, err := checkRuntimeCompatibility(binaryPath, pack)
+	if err != nil {
+		return fmt.Errorf("agentcore: runtime version check: %w", err)
+	}
+	for _, w := range warnings {
+		if cbErr := reporter.Progress("Warning: "+w, 0); cbErr != nil {
+			return cbErr
+		}
+	}
+	return nil
+}
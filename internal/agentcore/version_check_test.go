@@ -0,0 +1,141 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+	"github.com/Masterminds/semver/v3"
+)
+
+// goBinaryPath returns the running test binary, a real Go executable that
+// embeds the PromptKit runtime module in its build info.
+func goBinaryPath(t *testing.T) string {
+	t.Helper()
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	return path
+}
+
+func packCompiledWith(compiledWith string) *prompt.Pack {
+	return &prompt.Pack{ID: "p", Compilation: &prompt.CompilationInfo{CompiledWith: compiledWith}}
+}
+
+func TestReadRuntimeVersion_GoBinary(t *testing.T) {
+	rv, err := readRuntimeVersion(goBinaryPath(t))
+	if err != nil {
+		t.Fatalf("readRuntimeVersion: %v", err)
+	}
+	if !strings.HasPrefix(rv.PromptKit, "v1.") {
+		t.Errorf("PromptKit = %q, want v1.x", rv.PromptKit)
+	}
+}
+
+func TestReadRuntimeVersion_NotGoBinary(t *testing.T) {
+	if _, err := readRuntimeVersion(testBinaryPath(t)); err == nil {
+		t.Fatal("expected error for non-Go binary")
+	}
+}
+
+func TestCheckRuntimeCompatibility(t *testing.T) {
+	tests := []struct {
+		name         string
+		compiledWith string
+		wantWarn     bool
+		wantErr      bool
+	}{
+		{"no compiler info", "", false, false},
+		{"same version", "packc v1.5.0", false, false},
+		{"bare major", "packc v1", false, false},
+		{"newer minor", "packc v1.99.0", true, false},
+		{"newer major", "packc v2.0.0", false, true},
+		{"unversioned", "packc dev", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := checkRuntimeCompatibility(goBinaryPath(t), packCompiledWith(tt.compiledWith))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (len(warnings) > 0) != tt.wantWarn {
+				t.Errorf("warnings = %v, wantWarn %v", warnings, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestCheckRuntimeCompatibility_UnreadableBinaryWarns(t *testing.T) {
+	warnings, err := checkRuntimeCompatibility(testBinaryPath(t), packCompiledWith("packc v9.0.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipping version check") {
+		t.Errorf("warnings = %v, want skip warning", warnings)
+	}
+}
+
+func TestSupportedRuntimeRange(t *testing.T) {
+	c, err := semver.NewConstraint(supportedRuntimePromptKit)
+	if err != nil {
+		t.Fatalf("constraint: %v", err)
+	}
+	for v, want := range map[string]bool{"v1.4.9": false, "v1.5.2": true, "v1.9.0": true, "v2.0.0": false} {
+		if got := c.Check(semver.MustParse(v)); got != want {
+			t.Errorf("Check(%s) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestApply_VersionSkewFailsBeforeAWS(t *testing.T) {
+	var pack map[string]any
+	_ = json.Unmarshal([]byte(singleAgentPack()), &pack)
+	pack["compilation"] = map[string]any{"compiled_with": "packc v2.0.0"}
+	packJSON, _ := json.Marshal(pack)
+
+	clientCreated := false
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, cfg *Config) (awsClient, error) {
+		clientCreated = true
+		return newSimulatedAWSClient(cfg.Region), nil
+	}
+
+	_, err := provider.Apply(context.Background(), &deploy.PlanRequest{
+		PackJSON:    string(packJSON),
+		ArenaConfig: validArenaConfigJSON,
+		DeployConfig: fmt.Sprintf(
+			`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test","runtime_binary_path":%q}`,
+			goBinaryPath(t)),
+	}, func(*deploy.ApplyEvent) error { return nil })
+
+	if err == nil || !strings.Contains(err.Error(), "runtime version check") {
+		t.Fatalf("err = %v, want runtime version check error", err)
+	}
+	if clientCreated {
+		t.Error("AWS client should not be created when the version check fails")
+	}
+}
+
+func TestApply_UnreadableRuntimeEmitsWarning(t *testing.T) {
+	var events []*deploy.ApplyEvent
+	_, err := newSimulatedProvider().Apply(context.Background(), &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	}, func(e *deploy.ApplyEvent) error {
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(events) < 2 || !strings.Contains(events[1].Message, "skipping version check") {
+		t.Errorf("events = %+v, want the version check warning after the adapter version", events)
+	}
+}
@@ -0,0 +1,92 @@
+package agentcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// setVersion stamps the build version for the duration of a test.
+func setVersion(t *testing.T, v string) {
+	t.Helper()
+	prev := Version
+	Version = v
+	t.Cleanup(func() { Version = prev })
+}
+
+func TestCheckMinAdapterVersion(t *testing.T) {
+	setVersion(t, "v0.9.0")
+	tests := []struct {
+		min     string
+		wantErr string
+	}{
+		{"", ""},
+		{"0.8.1", ""},
+		{"v0.9.0", ""},
+		{"v0.10.0", "arena_config requires agentcore adapter v0.10.0 or newer, but this is v0.9.0; upgrade the adapter"},
+		{"latest", `invalid min_adapter_version "latest"`},
+	}
+	for _, tt := range tests {
+		err := checkMinAdapterVersion(tt.min)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("checkMinAdapterVersion(%q) = %v", tt.min, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("checkMinAdapterVersion(%q) = %v, want %q", tt.min, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckMinAdapterVersion_DevBuild(t *testing.T) {
+	setVersion(t, "dev")
+	if err := checkMinAdapterVersion("v99.0.0"); err != nil {
+		t.Errorf("dev build: %v", err)
+	}
+}
+
+func TestApply_MinAdapterVersion(t *testing.T) {
+	setVersion(t, "v0.9.0")
+	_, _, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: validConfig(t),
+		ArenaConfig: `{"min_adapter_version":"v1.0.0"}`,
+	})
+	if err == nil || !strings.Contains(err.Error(), "adapter v1.0.0 or newer, but this is v0.9.0") {
+		t.Errorf("Apply err = %v, want the version requirement", err)
+	}
+}
+
+func TestApply_FirstEventIsAdapterVersion(t *testing.T) {
+	setVersion(t, "v0.9.0")
+	events, _, err := collectEvents(t, newSimulatedProvider(), &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: validConfig(t), ArenaConfig: validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(events) == 0 || !strings.HasPrefix(events[0].Message, "agentcore adapter v0.9.0 (commit ") {
+		t.Errorf("events = %+v, want the adapter version first", events)
+	}
+}
+
+func TestServeIO_Version(t *testing.T) {
+	var out bytes.Buffer
+	err := ServeIO(newSimulatedProvider(), strings.NewReader(jsonRPCRequest(MethodVersion, 3, nil)), &out)
+	if err != nil {
+		t.Fatalf("ServeIO error: %v", err)
+	}
+	var resp jsonRPCResponse
+	if err = json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response %q: %v", out.String(), err)
+	}
+	var v VersionResponse
+	if err = json.Unmarshal(resp.Result, &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := newSimulatedProvider().Version(context.Background()); v != *want {
+		t.Errorf("version = %+v, want %+v", v, want)
+	}
+}
@@ -0,0 +1,67 @@
+package agentcore
+
+import (
+	"fmt"
+	"time"
+)
+
+// waitProgressEvery is how many poll attempts pass between progress events.
+// At the default poll interval this reports roughly every 30 seconds of
+// waiting.
+const waitProgressEvery = 6
+
+// progressNoPercent makes ProgressReporter omit the percentage, since wait
+// progress happens between the apply phase's own progress steps.
+const progressNoPercent = -1
+
+// waitProgressFunc receives status lines from inside waitFor* polling loops,
+// e.g. "runtime X still CREATING, 2m15s elapsed, attempt 27/60".
+type waitProgressFunc func(msg string)
+
+// waitProgressSetter is implemented by clients whose polling loops can report
+// progress. Apply and Destroy wire it to their event callbacks.
+type waitProgressSetter interface {
+	SetWaitProgress(fn waitProgressFunc)
+}
+
+// SetWaitProgress sets the function that receives polling progress.
+func (c *realAWSClient) SetWaitProgress(fn waitProgressFunc) {
+	c.waitProgress = fn
+}
+
+// pollTracker reports on a single waitFor* loop.
+type pollTracker struct {
+	kind     string
+	id       string
+	started  time.Time
+	now      func() time.Time
+	attempts int
+	report   waitProgressFunc
+}
+
+// newPollTracker starts tracking a wait for the resource of the given kind.
+func (c *realAWSClient) newPollTracker(kind, id string) *pollTracker {
+	return &pollTracker{
+		kind:     kind,
+		id:       id,
+		started:  c.poll.now(),
+		now:      c.poll.now,
+		attempts: c.poll.maxAttempts(),
+		report:   c.waitProgress,
+	}
+}
+
+// waiting records that attempt (zero-based) saw the resource still in
+// status. Every waitProgressEvery attempts it logs and reports a line.
+func (t *pollTracker) waiting(attempt int, status string) {
+	n := attempt + 1
+	if n%waitProgressEvery != 0 {
+		return
+	}
+	msg := fmt.Sprintf("%s %s still %s, %s elapsed, attempt %d/%d",
+		t.kind, t.id, status, t.now().Sub(t.started).Round(time.Second), n, t.attempts)
+	logf("agentcore: %s", msg)
+	if t.report != nil {
+		t.report(msg)
+	}
+}
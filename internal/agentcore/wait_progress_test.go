@@ -0,0 +1,88 @@
+package agentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+func TestPollTracker_ReportsEveryNAttempts(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	var got []string
+	tracker := &pollTracker{
+		kind:     "runtime",
+		id:       "chat-abc",
+		started:  start,
+		now:      func() time.Time { return now },
+		attempts: defaultMaxPollAttempts,
+		report:   func(msg string) { got = append(got, msg) },
+	}
+
+	for attempt := range 2 * waitProgressEvery {
+		now = start.Add(time.Duration(attempt+1) * defaultPollInterval)
+		tracker.waiting(attempt, "CREATING")
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d reports %v, want 2", len(got), got)
+	}
+	want := "runtime chat-abc still CREATING, 1m0s elapsed, attempt 12/60"
+	if got[1] != want {
+		t.Errorf("report = %q, want %q", got[1], want)
+	}
+}
+
+func TestPollTracker_NilReport(t *testing.T) {
+	tracker := (&realAWSClient{}).newPollTracker("memory", "mem-1")
+	tracker.waiting(waitProgressEvery-1, "CREATING")
+}
+
+// waitingAWSClient reports wait progress from CreateRuntime, as the real
+// client does while polling a runtime that is still being created.
+type waitingAWSClient struct {
+	simulatedAWSClient
+	progress waitProgressFunc
+}
+
+func (c *waitingAWSClient) SetWaitProgress(fn waitProgressFunc) {
+	c.progress = fn
+}
+
+func (c *waitingAWSClient) CreateRuntime(ctx context.Context, name string, cfg *Config) (string, error) {
+	c.progress("runtime " + name + " still CREATING, 30s elapsed, attempt 6/60")
+	return c.simulatedAWSClient.CreateRuntime(ctx, name, cfg)
+}
+
+func TestApply_EmitsWaitProgress(t *testing.T) {
+	sim := newSimulatedProvider()
+	provider := &Provider{
+		awsClientFunc: func(_ context.Context, cfg *Config) (awsClient, error) {
+			return &waitingAWSClient{simulatedAWSClient: *newSimulatedAWSClient(cfg.Region)}, nil
+		},
+		destroyerFunc: sim.destroyerFunc,
+		checkerFunc:   sim.checkerFunc,
+	}
+	req := &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfig(t),
+		ArenaConfig:  validArenaConfigJSON,
+	}
+
+	events, _, err := collectEvents(t, provider, req)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	for _, ev := range events {
+		if ev.Type == "progress" && strings.Contains(ev.Message, "still CREATING") {
+			if strings.Contains(ev.Message, "%") {
+				t.Errorf("wait progress %q should not carry a percentage", ev.Message)
+			}
+			return
+		}
+	}
+	t.Errorf("no wait progress event in %+v", events)
+}
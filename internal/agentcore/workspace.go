@@ -0,0 +1,57 @@
+package agentcore
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// TagKeyWorkspace tags every resource deployed into a workspace.
+const TagKeyWorkspace = "promptpack:workspace"
+
+// workspacePattern limits workspace names to characters AgentCore accepts
+// in resource names, and keeps them short enough to leave room for the
+// names they are appended to.
+const workspacePattern = `^[a-zA-Z0-9][a-zA-Z0-9_]{0,15}$`
+
+var workspaceRE = regexp.MustCompile(workspacePattern)
+
+// validateWorkspace checks the workspace name.
+func validateWorkspace(workspace string) []string {
+	if workspace == "" || workspaceRE.MatchString(workspace) {
+		return nil
+	}
+	return []string{fmt.Sprintf("workspace %q must match %s", workspace, workspacePattern)}
+}
+
+// awsName returns the name a resource is created under in AWS. A workspace
+// is appended so that deployments of the same pack into different
+// workspaces of one account never collide, and a renamed resource keeps
+// the name it was created under. State keeps the logical name.
+func (c *Config) awsName(name string) string {
+	if prior, ok := c.renamed[name]; ok {
+		name = prior
+	}
+	if c.Workspace == "" {
+		return name
+	}
+	return name + "_" + c.Workspace
+}
+
+// checkStateWorkspace rejects prior state written for another workspace,
+// so that Plan, Apply, Status, and Destroy never act on another
+// workspace's resources.
+func checkStateWorkspace(state *AdapterState, cfg *Config) error {
+	if state == nil || len(state.Resources) == 0 || state.Workspace == cfg.Workspace {
+		return nil
+	}
+	return fmt.Errorf("prior state belongs to %s, but the deploy config selects %s",
+		workspaceLabel(state.Workspace), workspaceLabel(cfg.Workspace))
+}
+
+// workspaceLabel names a workspace in messages.
+func workspaceLabel(workspace string) string {
+	if workspace == "" {
+		return "the default workspace"
+	}
+	return fmt.Sprintf("workspace %q", workspace)
+}
@@ -0,0 +1,140 @@
+package agentcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+	"github.com/AltairaLabs/PromptKit/runtime/prompt"
+)
+
+func validConfigWithWorkspace(t *testing.T, workspace string) string {
+	t.Helper()
+	return fmt.Sprintf(`{"region":"us-west-2","runtime_role_arn":"arn:aws:iam::123456789012:role/test",`+
+		`"runtime_binary_path":%q,"workspace":%q}`, testBinaryPath(t), workspace)
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	for _, ws := range []string{"", "dev", "pr_123", "Staging2"} {
+		if errs := validateWorkspace(ws); len(errs) != 0 {
+			t.Errorf("validateWorkspace(%q) = %v, want none", ws, errs)
+		}
+	}
+	for _, ws := range []string{"_dev", "dev-1", "prod.eu", "a_workspace_name_too_long"} {
+		if errs := validateWorkspace(ws); len(errs) != 1 {
+			t.Errorf("validateWorkspace(%q) = %v, want one error", ws, errs)
+		}
+	}
+}
+
+func TestAWSName(t *testing.T) {
+	if got := (&Config{}).awsName("mypack"); got != "mypack" {
+		t.Errorf("default workspace = %q, want the name unchanged", got)
+	}
+	if got := (&Config{Workspace: "dev"}).awsName("mypack_memory"); got != "mypack_memory_dev" {
+		t.Errorf("awsName = %q, want mypack_memory_dev", got)
+	}
+}
+
+func TestCheckStateWorkspace(t *testing.T) {
+	deployed := &AdapterState{Workspace: "prod", Resources: []ResourceState{{Type: ResTypeMemory, Name: "m"}}}
+	if err := checkStateWorkspace(deployed, &Config{Workspace: "prod"}); err != nil {
+		t.Errorf("matching workspace: %v", err)
+	}
+	if err := checkStateWorkspace(&AdapterState{Workspace: "prod"}, &Config{}); err != nil {
+		t.Errorf("empty state: %v", err)
+	}
+	err := checkStateWorkspace(deployed, &Config{})
+	want := `prior state belongs to workspace "prod", but the deploy config selects the default workspace`
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+}
+
+func TestMemoryIDHasName(t *testing.T) {
+	if !memoryIDHasName("mypack_memory-AbCdEfGh", "mypack_memory") {
+		t.Error("own memory not matched")
+	}
+	if memoryIDHasName("mypack_memory_dev-AbCdEfGh", "mypack_memory") {
+		t.Error("another workspace's memory matched")
+	}
+}
+
+func TestApply_RecordsWorkspace(t *testing.T) {
+	client := &tagCapturingClient{simulatedAWSClient: *newSimulatedAWSClient("us-west-2")}
+	provider := newSimulatedProvider()
+	provider.awsClientFunc = func(_ context.Context, _ *Config) (awsClient, error) { return client, nil }
+
+	_, raw, err := collectEvents(t, provider, &deploy.PlanRequest{
+		PackJSON:     singleAgentPack(),
+		DeployConfig: validConfigWithWorkspace(t, "staging"),
+		ArenaConfig:  validArenaConfigJSON,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !strings.Contains(raw, `"workspace":"staging"`) {
+		t.Errorf("state does not record the workspace: %s", raw)
+	}
+	for call, tags := range client.capturedTags {
+		if tags[TagKeyWorkspace] != "staging" {
+			t.Errorf("call %d tags = %v, want %s=staging", call, tags, TagKeyWorkspace)
+		}
+	}
+}
+
+func TestWorkspaceMismatch_Rejected(t *testing.T) {
+	p := newSimulatedProvider()
+	prior := mustJSON(t, &AdapterState{PackID: "mypack", Workspace: "prod", Resources: []ResourceState{
+		{Type: ResTypeAgentRuntime, Name: "mypack", ARN: "arn:rt", Status: ResStatusCreated},
+	}})
+	cfg := validConfigWithWorkspace(t, "staging")
+	planReq := &deploy.PlanRequest{
+		PackJSON: singleAgentPack(), DeployConfig: cfg, ArenaConfig: validArenaConfigJSON, PriorState: prior,
+	}
+	const want = `prior state belongs to workspace "prod"`
+
+	if _, err := p.Plan(context.Background(), planReq); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Plan err = %v", err)
+	}
+	if _, _, err := collectEvents(t, p, planReq); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Apply err = %v", err)
+	}
+	_, err := p.Status(context.Background(), &deploy.StatusRequest{DeployConfig: cfg, PriorState: prior})
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Status err = %v", err)
+	}
+	err = p.Destroy(context.Background(), &deploy.DestroyRequest{DeployConfig: cfg, PriorState: prior},
+		func(*deploy.DestroyEvent) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Destroy err = %v", err)
+	}
+}
+
+func TestValidateResourceNames_IncludesWorkspace(t *testing.T) {
+	id := strings.Repeat("a", 45)
+	pack := &prompt.Pack{ID: id}
+
+	if errs := validateResourceNames(pack, &Config{}); len(errs) != 0 {
+		t.Fatalf("default workspace errs = %v", errs)
+	}
+	errs := validateResourceNames(pack, &Config{Workspace: "staging"})
+	if len(errs) != 1 || !strings.Contains(errs[0], id+"_staging") {
+		t.Errorf("errs = %v, want the suffixed runtime name rejected", errs)
+	}
+}
+
+func TestCreatePolicyEngine_UsesWorkspaceName(t *testing.T) {
+	c, rec := newRecordingRealClient(
+		`{"policyEngineId":"pe-1","policyEngineArn":"arn:pe-1","status":"CREATING"}`, `{"status":"ACTIVE"}`)
+
+	if _, _, err := c.CreatePolicyEngine(context.Background(), "chat_policy_engine",
+		&Config{Workspace: "dev"}); err != nil {
+		t.Fatalf("CreatePolicyEngine: %v", err)
+	}
+	if len(rec.requests) == 0 || !strings.Contains(rec.requests[0], `"name":"chat_policy_engine_dev"`) {
+		t.Errorf("requests = %v, want the workspace-suffixed name", rec.requests)
+	}
+}
@@ -3,17 +3,35 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
-
-	"github.com/AltairaLabs/PromptKit/runtime/deploy/adaptersdk"
+	"os/signal"
+	"syscall"
 
 	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
 )
 
 func main() {
+	serveHTTP := flag.String("serve-http", "",
+		"serve Plan/Apply/Status/Destroy as an HTTP API on this address (e.g. :8080) instead of JSON-RPC on stdio; "+
+			"requires "+agentcore.EnvServeToken)
+	region := flag.String("region", "",
+		"AWS region for every request, overriding the deploy config's region and AWS_REGION")
+	flag.Parse()
+
 	provider := agentcore.NewProvider()
-	if err := adaptersdk.Serve(provider); err != nil {
+	provider.SetRegionOverride(*region)
+	var err error
+	if *serveHTTP != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+		err = agentcore.ServeHTTP(ctx, provider, *serveHTTP, os.Getenv(agentcore.EnvServeToken))
+		stop()
+	} else {
+		err = agentcore.Serve(provider)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "agentcore: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,255 @@
+// Package agentcore is the public Go API of the AWS Bedrock AgentCore
+// deploy adapter. It lets other Go services embed the adapter: build a
+// typed Config, encode it as the deploy config of a PromptKit deploy
+// request, and call the Provider's Plan, Apply, Status, and Destroy
+// directly instead of running the adapter as a JSON-RPC subprocess.
+//
+// The types here are aliases of the adapter's implementation, so values
+// pass between this package and the adapter unchanged. The names, JSON
+// keys, and constant values exported here are kept stable across minor
+// releases.
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// Provider implements the PromptKit deploy.Provider interface for AWS
+// Bedrock AgentCore, plus the adapter's extension methods such as Lint,
+// Graph, and Promote.
+type Provider = agentcore.Provider
+
+// NewProvider returns a Provider that calls AWS, resolving credentials
+// through the standard aws-sdk-go-v2 chain or the config's AWS settings.
+func NewProvider() *Provider {
+	return agentcore.NewProvider()
+}
+
+// Serve runs p as a JSON-RPC 2.0 server on stdin and stdout, as
+// promptarena deploy expects of an adapter subprocess.
+func Serve(p *Provider) error {
+	return agentcore.Serve(p)
+}
+
+// NewHTTPHandler returns the handler of the adapter's HTTP API, which
+// requires token as a bearer token on every endpoint but /healthz.
+func NewHTTPHandler(p *Provider, token string) http.Handler {
+	return agentcore.NewHTTPHandler(p, token)
+}
+
+// ServeHTTP serves the adapter's HTTP API on addr until ctx is done.
+func ServeHTTP(ctx context.Context, p *Provider, addr, token string) error {
+	return agentcore.ServeHTTP(ctx, p, addr, token)
+}
+
+// Config is the adapter's deploy config: the JSON object a deploy request
+// carries in its deploy_config field.
+type Config = agentcore.Config
+
+// Deploy config blocks.
+type (
+	MemoryConfig            = agentcore.MemoryConfig
+	ToolsConfig             = agentcore.ToolsConfig
+	ToolAuditConfig         = agentcore.ToolAuditConfig
+	ObservabilityConfig     = agentcore.ObservabilityConfig
+	A2AAuthConfig           = agentcore.A2AAuthConfig
+	AWSCredentialsEnv       = agentcore.AWSCredentialsEnv
+	ConflictPolicy          = agentcore.ConflictPolicy
+	AgentCardConfig         = agentcore.AgentCardConfig
+	InferenceProfilesConfig = agentcore.InferenceProfilesConfig
+	InferenceProfileConfig  = agentcore.InferenceProfileConfig
+	ApprovalConfig          = agentcore.ApprovalConfig
+	ChangeManifestConfig    = agentcore.ChangeManifestConfig
+	IdentityProviderConfig  = agentcore.IdentityProviderConfig
+	GatewayConfig           = agentcore.GatewayConfig
+	GatewayInterceptor      = agentcore.GatewayInterceptor
+	NetworkConfig           = agentcore.NetworkConfig
+	SessionsConfig          = agentcore.SessionsConfig
+	MemoryNamespacesConfig  = agentcore.MemoryNamespacesConfig
+	LogsConfig              = agentcore.LogsConfig
+	EvalAlertConfig         = agentcore.EvalAlertConfig
+	EvalDefaultsConfig      = agentcore.EvalDefaultsConfig
+	LifecycleConfig         = agentcore.LifecycleConfig
+	RolloutConfig           = agentcore.RolloutConfig
+	MetricsConfig           = agentcore.MetricsConfig
+	DashboardConfig         = agentcore.DashboardConfig
+	PhasesConfig            = agentcore.PhasesConfig
+	PostDeployTestsConfig   = agentcore.PostDeployTestsConfig
+	PostDeployTest          = agentcore.PostDeployTest
+	AgentsFilterConfig      = agentcore.AgentsFilterConfig
+	ArenaToolSpec           = agentcore.ArenaToolSpec
+)
+
+// Requests and responses of the Provider's extension methods.
+type (
+	ApproveRequest            = agentcore.ApproveRequest
+	ApproveResponse           = agentcore.ApproveResponse
+	PendingApprovalsRequest   = agentcore.PendingApprovalsRequest
+	PendingApprovalsResponse  = agentcore.PendingApprovalsResponse
+	DescribeResponse          = agentcore.DescribeResponse
+	EvalPreviewRequest        = agentcore.EvalPreviewRequest
+	EvalPreviewResponse       = agentcore.EvalPreviewResponse
+	EvalResultsRequest        = agentcore.EvalResultsRequest
+	EvalResultsResponse       = agentcore.EvalResultsResponse
+	ListEvalTemplatesRequest  = agentcore.ListEvalTemplatesRequest
+	ListEvalTemplatesResponse = agentcore.ListEvalTemplatesResponse
+	GraphRequest              = agentcore.GraphRequest
+	GraphResponse             = agentcore.GraphResponse
+	DestroyPlanRequest        = agentcore.DestroyPlanRequest
+	DestroyPlanResponse       = agentcore.DestroyPlanResponse
+	DestroyPlanStep           = agentcore.DestroyPlanStep
+	DestroyPlanResource       = agentcore.DestroyPlanResource
+	LintRequest               = agentcore.LintRequest
+	LintResponse              = agentcore.LintResponse
+	MemoryListRequest         = agentcore.MemoryListRequest
+	MemoryListResponse        = agentcore.MemoryListResponse
+	MemoryPurgeRequest        = agentcore.MemoryPurgeRequest
+	MemoryPurgeResponse       = agentcore.MemoryPurgeResponse
+	PromoteRequest            = agentcore.PromoteRequest
+	PromoteResponse           = agentcore.PromoteResponse
+	StatusBatchRequest        = agentcore.StatusBatchRequest
+	StatusBatchResponse       = agentcore.StatusBatchResponse
+	VersionResponse           = agentcore.VersionResponse
+)
+
+// AdapterState is the decoded form of the opaque state Apply returns, and
+// ResourceState is one resource in it.
+type (
+	AdapterState  = agentcore.AdapterState
+	ResourceState = agentcore.ResourceState
+)
+
+// DeployError is the structured error Apply and Destroy report for a
+// failed resource.
+type DeployError = agentcore.DeployError
+
+// Event is an Apply or Destroy event with its stable code, as the JSON-RPC
+// and HTTP servers send it.
+type Event = agentcore.Event
+
+// EventCode returns the stable code of an Apply or Destroy event, for
+// callers of Apply and Destroy that receive PromptKit's uncoded events.
+func EventCode(eventType, message string, res *deploy.ResourceResult) string {
+	return agentcore.EventCode(eventType, message, res)
+}
+
+// Codes of events that concern no single resource.
+const (
+	EventCodeProgress        = agentcore.EventCodeProgress
+	EventCodeWarning         = agentcore.EventCodeWarning
+	EventCodeError           = agentcore.EventCodeError
+	EventCodeDestroyComplete = agentcore.EventCodeDestroyComplete
+)
+
+// Resource types, as they appear in plans, events, and adapter state.
+const (
+	ResTypeMemory           = agentcore.ResTypeMemory
+	ResTypeAgentRuntime     = agentcore.ResTypeAgentRuntime
+	ResTypeRuntimeEndpoint  = agentcore.ResTypeRuntimeEndpoint
+	ResTypeToolGateway      = agentcore.ResTypeToolGateway
+	ResTypeA2AEndpoint      = agentcore.ResTypeA2AEndpoint
+	ResTypeEvaluator        = agentcore.ResTypeEvaluator
+	ResTypeOnlineEvalConfig = agentcore.ResTypeOnlineEvalConfig
+	ResTypeCedarPolicy      = agentcore.ResTypeCedarPolicy
+	ResTypeInferenceProfile = agentcore.ResTypeInferenceProfile
+	ResTypeLogGroup         = agentcore.ResTypeLogGroup
+	ResTypeIdentityProvider = agentcore.ResTypeIdentityProvider
+	ResTypeEvalAlert        = agentcore.ResTypeEvalAlert
+	ResTypeLogsQuery        = agentcore.ResTypeLogsQuery
+)
+
+// Resource statuses in ResourceState.Status.
+const (
+	ResStatusCreated         = agentcore.ResStatusCreated
+	ResStatusUpdated         = agentcore.ResStatusUpdated
+	ResStatusReplaced        = agentcore.ResStatusReplaced
+	ResStatusFailed          = agentcore.ResStatusFailed
+	ResStatusPlanned         = agentcore.ResStatusPlanned
+	ResStatusDeleted         = agentcore.ResStatusDeleted
+	ResStatusSkipped         = agentcore.ResStatusSkipped
+	ResStatusPendingApproval = agentcore.ResStatusPendingApproval
+)
+
+// Runtime protocols for Config.Protocol.
+const (
+	ProtocolHTTP = agentcore.ProtocolHTTP
+	ProtocolA2A  = agentcore.ProtocolA2A
+	ProtocolBoth = agentcore.ProtocolBoth
+)
+
+// Memory strategies for MemoryConfig.Strategies.
+const (
+	StrategyEpisodic       = agentcore.StrategyEpisodic
+	StrategySemantic       = agentcore.StrategySemantic
+	StrategySummary        = agentcore.StrategySummary
+	StrategyUserPreference = agentcore.StrategyUserPreference
+)
+
+// A2A auth modes for A2AAuthConfig.Mode.
+const (
+	A2AAuthModeIAM = agentcore.A2AAuthModeIAM
+	A2AAuthModeJWT = agentcore.A2AAuthModeJWT
+)
+
+// Gateway partitioning modes for Config.GatewayPartitioning.
+const (
+	GatewayPartitioningShared   = agentcore.GatewayPartitioningShared
+	GatewayPartitioningPerAgent = agentcore.GatewayPartitioningPerAgent
+)
+
+// AWS partitions, decided by Config.Region.
+const (
+	PartitionAWS      = agentcore.PartitionAWS
+	PartitionGovCloud = agentcore.PartitionGovCloud
+	PartitionChina    = agentcore.PartitionChina
+)
+
+// Services whose endpoints Config.Endpoints overrides.
+const (
+	EndpointBedrockAgentCoreControl = agentcore.EndpointBedrockAgentCoreControl
+	EndpointBedrockAgentCore        = agentcore.EndpointBedrockAgentCore
+	EndpointBedrock                 = agentcore.EndpointBedrock
+	EndpointBedrockRuntime          = agentcore.EndpointBedrockRuntime
+	EndpointSTS                     = agentcore.EndpointSTS
+	EndpointLogs                    = agentcore.EndpointLogs
+	EndpointS3                      = agentcore.EndpointS3
+	EndpointLambda                  = agentcore.EndpointLambda
+	EndpointIAM                     = agentcore.EndpointIAM
+)
+
+// Memory sharing modes for MemoryNamespacesConfig.Sharing.
+const (
+	MemorySharingShared   = agentcore.MemorySharingShared
+	MemorySharingIsolated = agentcore.MemorySharingIsolated
+)
+
+// Post-deploy test failure policies for PostDeployTestsConfig.OnFailure.
+const (
+	PostDeployOnFailureWarn     = agentcore.PostDeployOnFailureWarn
+	PostDeployOnFailureFail     = agentcore.PostDeployOnFailureFail
+	PostDeployOnFailureRollback = agentcore.PostDeployOnFailureRollback
+)
+
+// On-conflict policies for ConflictPolicy values.
+const (
+	ConflictAdopt   = agentcore.ConflictAdopt
+	ConflictFail    = agentcore.ConflictFail
+	ConflictReplace = agentcore.ConflictReplace
+)
+
+// EncodeConfig returns cfg as the JSON deploy config of a deploy request,
+// such as deploy.PlanRequest.DeployConfig.
+func EncodeConfig(cfg *Config) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encode deploy config: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,142 @@
+package agentcore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+)
+
+// The public API is a compatibility promise: these tests fail when an
+// exported name, constant value, or deploy config key changes.
+
+var _ deploy.Provider = (*Provider)(nil)
+
+func TestResourceTypeValues(t *testing.T) {
+	want := map[string]string{
+		ResTypeMemory:           "memory",
+		ResTypeAgentRuntime:     "agent_runtime",
+		ResTypeRuntimeEndpoint:  "runtime_endpoint",
+		ResTypeToolGateway:      "tool_gateway",
+		ResTypeA2AEndpoint:      "a2a_endpoint",
+		ResTypeEvaluator:        "evaluator",
+		ResTypeOnlineEvalConfig: "online_eval_config",
+		ResTypeCedarPolicy:      "cedar_policy",
+		ResTypeInferenceProfile: "inference_profile",
+		ResTypeLogGroup:         "log_group",
+		ResTypeIdentityProvider: "identity_provider",
+		ResTypeEvalAlert:        "eval_alert",
+		ResTypeLogsQuery:        "logs_query",
+	}
+	for got, value := range want {
+		if got != value {
+			t.Errorf("resource type = %q, want %q", got, value)
+		}
+	}
+	if len(want) != 13 {
+		t.Errorf("got %d distinct resource types, want 13", len(want))
+	}
+}
+
+func TestConstantValues(t *testing.T) {
+	tests := []struct{ got, want string }{
+		{ResStatusCreated, "created"},
+		{ResStatusUpdated, "updated"},
+		{ResStatusReplaced, "replaced"},
+		{ResStatusFailed, "failed"},
+		{ResStatusPlanned, "planned"},
+		{ResStatusDeleted, "deleted"},
+		{ResStatusSkipped, "skipped"},
+		{ResStatusPendingApproval, "pending_approval"},
+		{ProtocolHTTP, "http"},
+		{ProtocolA2A, "a2a"},
+		{ProtocolBoth, "both"},
+		{StrategyEpisodic, "episodic"},
+		{StrategySemantic, "semantic"},
+		{StrategySummary, "summary"},
+		{StrategyUserPreference, "user_preference"},
+		{A2AAuthModeIAM, "iam"},
+		{A2AAuthModeJWT, "jwt"},
+		{GatewayPartitioningShared, "shared"},
+		{GatewayPartitioningPerAgent, "per_agent"},
+		{PartitionAWS, "aws"},
+		{PartitionGovCloud, "aws-us-gov"},
+		{PartitionChina, "aws-cn"},
+		{EndpointBedrockAgentCoreControl, "bedrock_agentcore_control"},
+		{MemorySharingShared, "shared"},
+		{MemorySharingIsolated, "isolated"},
+		{PostDeployOnFailureWarn, "warn"},
+		{PostDeployOnFailureFail, "fail"},
+		{PostDeployOnFailureRollback, "rollback"},
+		{ConflictAdopt, "adopt"},
+		{ConflictFail, "fail"},
+		{ConflictReplace, "replace"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("constant = %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestEncodeConfig_Keys(t *testing.T) {
+	cfg, err := NewConfigBuilder("us-west-2", "arn:aws:iam::123456789012:role/test", "/bin/runtime").
+		WithProtocol(ProtocolBoth).
+		WithMemory(StrategyEpisodic).
+		WithTag("team", "search").
+		WithWorkspace("dev").
+		WithObservability(&ObservabilityConfig{TracingEnabled: true}).
+		WithResponseModeration(true).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	raw, err := EncodeConfig(cfg)
+	if err != nil {
+		t.Fatalf("EncodeConfig: %v", err)
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	for _, key := range []string{
+		"region", "runtime_role_arn", "runtime_binary_path", "protocol", "memory_store",
+		"tags", "workspace", "observability", "response_moderation",
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("encoded config %s has no %q key", raw, key)
+		}
+	}
+}
+
+func TestEncodeConfig_ValidatesInProvider(t *testing.T) {
+	cfg, err := NewConfigBuilder("us-west-2", "arn:aws:iam::123456789012:role/test", "/bin/runtime").
+		WithMemory(StrategySemantic, StrategySummary).
+		WithOnConflict(ConflictAdopt).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	raw, err := EncodeConfig(cfg)
+	if err != nil {
+		t.Fatalf("EncodeConfig: %v", err)
+	}
+
+	resp, err := NewProvider().ValidateConfig(context.Background(), &deploy.ValidateRequest{Config: raw})
+	if err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+	if !resp.Valid {
+		t.Errorf("ValidateConfig(%s) = invalid: %v", raw, resp.Errors)
+	}
+
+	var decoded Config
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	if len(decoded.Memory.Strategies) != 2 || decoded.OnConflict["default"] != ConflictAdopt {
+		t.Errorf("decoded config = %+v, want the built memory and on_conflict", decoded)
+	}
+}
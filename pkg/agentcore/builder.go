@@ -0,0 +1,174 @@
+package agentcore
+
+import (
+	"maps"
+	"slices"
+)
+
+// ConfigBuilder builds a Config field by field. Blocks without a setter
+// of their own can be set on the Config that Build returns.
+type ConfigBuilder struct {
+	cfg Config
+}
+
+// NewConfigBuilder starts a Config with its required fields: the AWS
+// region, the IAM role the runtimes assume, and the path of the runtime
+// binary to deploy.
+func NewConfigBuilder(region, runtimeRoleARN, runtimeBinaryPath string) *ConfigBuilder {
+	return &ConfigBuilder{cfg: Config{
+		Region:            region,
+		RuntimeRoleARN:    runtimeRoleARN,
+		RuntimeBinaryPath: runtimeBinaryPath,
+	}}
+}
+
+// WithProtocol sets the protocol the runtimes serve: ProtocolHTTP,
+// ProtocolA2A, or ProtocolBoth.
+func (b *ConfigBuilder) WithProtocol(protocol string) *ConfigBuilder {
+	b.cfg.Protocol = protocol
+	return b
+}
+
+// WithMemory provisions AgentCore memory with the given strategies, such
+// as StrategyEpisodic.
+func (b *ConfigBuilder) WithMemory(strategies ...string) *ConfigBuilder {
+	b.cfg.Memory.Strategies = slices.Clone(strategies)
+	return b
+}
+
+// WithTag adds a tag to every AWS resource the adapter creates.
+func (b *ConfigBuilder) WithTag(key, value string) *ConfigBuilder {
+	if b.cfg.Tags == nil {
+		b.cfg.Tags = make(map[string]string)
+	}
+	b.cfg.Tags[key] = value
+	return b
+}
+
+// WithWorkspace separates this deployment from others of the same pack in
+// the account, such as dev and prod.
+func (b *ConfigBuilder) WithWorkspace(workspace string) *ConfigBuilder {
+	b.cfg.Workspace = workspace
+	return b
+}
+
+// WithDryRun makes Apply simulate its AWS calls.
+func (b *ConfigBuilder) WithDryRun(dryRun bool) *ConfigBuilder {
+	b.cfg.DryRun = dryRun
+	return b
+}
+
+// WithAWSProfile loads AWS credentials from a shared config profile.
+func (b *ConfigBuilder) WithAWSProfile(profile string) *ConfigBuilder {
+	b.cfg.AWSProfile = profile
+	return b
+}
+
+// WithEndpoint overrides the endpoint URL of one AWS service, such as
+// EndpointBedrockAgentCoreControl.
+func (b *ConfigBuilder) WithEndpoint(service, url string) *ConfigBuilder {
+	if b.cfg.Endpoints == nil {
+		b.cfg.Endpoints = make(map[string]string)
+	}
+	b.cfg.Endpoints[service] = url
+	return b
+}
+
+// WithOnConflict sets the policy for resources that already exist in AWS,
+// for every resource type: ConflictAdopt, ConflictFail, or ConflictReplace.
+func (b *ConfigBuilder) WithOnConflict(policy string) *ConfigBuilder {
+	b.cfg.OnConflict = ConflictPolicy{"default": policy}
+	return b
+}
+
+// WithTools sets the runtime tool settings.
+func (b *ConfigBuilder) WithTools(tools *ToolsConfig) *ConfigBuilder {
+	b.cfg.Tools = tools
+	return b
+}
+
+// WithObservability sets the CloudWatch log group and tracing.
+func (b *ConfigBuilder) WithObservability(obs *ObservabilityConfig) *ConfigBuilder {
+	b.cfg.Observability = obs
+	return b
+}
+
+// WithA2AAuth sets how the A2A endpoints authenticate callers.
+func (b *ConfigBuilder) WithA2AAuth(auth *A2AAuthConfig) *ConfigBuilder {
+	b.cfg.A2AAuth = auth
+	return b
+}
+
+// WithGateway configures the shared MCP tool gateway.
+func (b *ConfigBuilder) WithGateway(gateway *GatewayConfig) *ConfigBuilder {
+	b.cfg.Gateway = gateway
+	return b
+}
+
+// WithGatewayPartitioning sets how tools are split across gateways:
+// GatewayPartitioningShared or GatewayPartitioningPerAgent.
+func (b *ConfigBuilder) WithGatewayPartitioning(mode string) *ConfigBuilder {
+	b.cfg.GatewayPartitioning = mode
+	return b
+}
+
+// WithInferenceProfiles routes the runtime and evaluators through Bedrock
+// inference profiles.
+func (b *ConfigBuilder) WithInferenceProfiles(profiles *InferenceProfilesConfig) *ConfigBuilder {
+	b.cfg.InferenceProfiles = profiles
+	return b
+}
+
+// WithApproval makes Apply wait for its plan to be approved.
+func (b *ConfigBuilder) WithApproval(approval *ApprovalConfig) *ConfigBuilder {
+	b.cfg.Approval = approval
+	return b
+}
+
+// WithPhases limits Apply to some of its phases.
+func (b *ConfigBuilder) WithPhases(phases *PhasesConfig) *ConfigBuilder {
+	b.cfg.Phases = phases
+	return b
+}
+
+// WithResponseModeration has the runtime bridges enforce each prompt's
+// banned_words and regex validators on responses.
+func (b *ConfigBuilder) WithResponseModeration(enabled bool) *ConfigBuilder {
+	b.cfg.ResponseModeration = enabled
+	return b
+}
+
+// WithPrewarm has Apply send n health prompts to each runtime it created
+// or updated, reporting cold and warm timings.
+func (b *ConfigBuilder) WithPrewarm(n int) *ConfigBuilder {
+	b.cfg.Prewarm = n
+	return b
+}
+
+// WithPostDeployTests has Apply run synthetic conversations against the
+// entry runtime once it is deployed.
+func (b *ConfigBuilder) WithPostDeployTests(tests *PostDeployTestsConfig) *ConfigBuilder {
+	b.cfg.PostDeployTests = tests
+	return b
+}
+
+// WithMemoryNamespaces sets the memory namespace of each runtime, and
+// whether members share memories. It requires WithMemory.
+func (b *ConfigBuilder) WithMemoryNamespaces(namespaces *MemoryNamespacesConfig) *ConfigBuilder {
+	b.cfg.MemoryNamespaces = namespaces
+	return b
+}
+
+// Build validates the config and returns a copy of it, so the builder can
+// be reused. The error lists every validation failure.
+func (b *ConfigBuilder) Build() (*Config, error) {
+	cfg := b.cfg
+	cfg.Memory.Strategies = slices.Clone(b.cfg.Memory.Strategies)
+	cfg.Tags = maps.Clone(b.cfg.Tags)
+	cfg.OnConflict = maps.Clone(b.cfg.OnConflict)
+	cfg.Endpoints = maps.Clone(b.cfg.Endpoints)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
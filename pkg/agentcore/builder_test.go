@@ -0,0 +1,79 @@
+package agentcore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigBuilder_Build(t *testing.T) {
+	cfg, err := NewConfigBuilder("eu-west-1", "arn:aws:iam::123456789012:role/agent", "/bin/runtime").
+		WithProtocol(ProtocolA2A).
+		WithDryRun(true).
+		WithPrewarm(2).
+		WithGatewayPartitioning(GatewayPartitioningPerAgent).
+		WithPhases(&PhasesConfig{Include: []string{ResTypeEvaluator}}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if cfg.Region != "eu-west-1" || cfg.Protocol != ProtocolA2A || !cfg.DryRun {
+		t.Errorf("cfg = %+v, want region eu-west-1, protocol a2a, dry run", cfg)
+	}
+	if cfg.Prewarm != 2 || cfg.GatewayPartitioning != GatewayPartitioningPerAgent {
+		t.Errorf("prewarm = %d, gateway partitioning = %q, want 2 and per_agent", cfg.Prewarm, cfg.GatewayPartitioning)
+	}
+	if cfg.Phases == nil || cfg.Phases.Include[0] != ResTypeEvaluator {
+		t.Errorf("phases = %+v, want include evaluator", cfg.Phases)
+	}
+}
+
+func TestConfigBuilder_BuildInvalid(t *testing.T) {
+	_, err := NewConfigBuilder("nowhere", "", "").
+		WithProtocol("grpc").
+		WithPostDeployTests(&PostDeployTestsConfig{OnFailure: PostDeployOnFailureRollback}).
+		WithMemoryNamespaces(&MemoryNamespacesConfig{Sharing: MemorySharingIsolated}).
+		Build()
+	if err == nil {
+		t.Fatal("Build() error = nil, want validation errors")
+	}
+	for _, want := range []string{
+		"region", "runtime_role_arn is required", "runtime_binary_path is required", "protocol", "post_deploy_tests",
+		"memory_namespaces requires memory_store",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Build() error = %q, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestConfigBuilder_BuildReturnsCopy(t *testing.T) {
+	b := NewConfigBuilder("us-west-2", "arn:aws:iam::123456789012:role/test", "/bin/runtime").
+		WithTag("env", "dev")
+	first, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	second, err := b.WithTag("env", "prod").WithWorkspace("prod").Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if first.Tags["env"] != "dev" || first.Workspace != "" {
+		t.Errorf("first config changed after reuse: tags %v, workspace %q", first.Tags, first.Workspace)
+	}
+	if second.Tags["env"] != "prod" || second.Workspace != "prod" {
+		t.Errorf("second config = tags %v, workspace %q, want prod", second.Tags, second.Workspace)
+	}
+}
+
+func TestConfigBuilder_GovCloudEndpoint(t *testing.T) {
+	controlURL := "https://bedrock-agentcore-control.us-gov-west-1.amazonaws.com"
+	cfg, err := NewConfigBuilder("us-gov-west-1", "arn:aws-us-gov:iam::123456789012:role/agent", "/bin/runtime").
+		WithEndpoint(EndpointBedrockAgentCoreControl, controlURL).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if cfg.Endpoints[EndpointBedrockAgentCoreControl] != controlURL {
+		t.Errorf("endpoints = %v, want the control-plane override", cfg.Endpoints)
+	}
+}
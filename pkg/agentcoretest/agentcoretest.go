@@ -0,0 +1,40 @@
+// Package agentcoretest runs the AWS Bedrock AgentCore deploy adapter
+// against an in-memory model of AgentCore, so integration suites of
+// PromptKit and other adapters can exercise full Plan, Apply, Status, and
+// Destroy flows without AWS credentials or AWS resources.
+//
+// A Cloud holds the resources of one simulated AWS account. Apply creates
+// resources in it with the tags the adapter gives them in AWS, and
+// Destroy deletes them. Seed a resource to test what Apply does when one
+// of the same name already exists: on_conflict decides as it does against
+// AWS, including the promptpack tag check before adopting.
+//
+// The runtime binary named by runtime_binary_path must still exist, since
+// Apply packages it; its contents are never run.
+package agentcoretest
+
+import (
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/internal/agentcore"
+)
+
+// Cloud is an in-memory model of the AgentCore resources of one AWS
+// account. It is safe for concurrent use.
+type Cloud = agentcore.SimulatedCloud
+
+// Resource is a resource in a Cloud.
+type Resource = agentcore.SimulatedResource
+
+// AccountID is the AWS account of every ARN a Cloud hands out.
+const AccountID = agentcore.SimulatedAccountID
+
+// NewCloud returns an empty Cloud.
+func NewCloud() *Cloud {
+	return agentcore.NewSimulatedCloud()
+}
+
+// NewProvider returns a Provider, the same type as the Provider of
+// package agentcore, whose AWS calls act on cloud. Providers sharing a
+// cloud see each other's resources.
+func NewProvider(cloud *Cloud) *agentcore.Provider {
+	return agentcore.NewSimulatedProvider(cloud)
+}
@@ -0,0 +1,85 @@
+package agentcoretest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AltairaLabs/PromptKit/runtime/deploy"
+
+	"github.com/AltairaLabs/promptarena-deploy-agentcore/pkg/agentcore"
+)
+
+const testPackJSON = `{
+	"id": "mypack", "version": "v1.0.0", "name": "My Pack",
+	"prompts": {"chat": {"id": "chat", "name": "Chat", "system_template": "Help.", "version": "v1.0.0"}},
+	"template_engine": {"version": "1.0", "syntax": "{{variable}}"}
+}`
+
+func testDeployConfig(t *testing.T, onConflict string) string {
+	t.Helper()
+	binary := filepath.Join(t.TempDir(), "promptkit-runtime")
+	if err := os.WriteFile(binary, []byte("fake-binary"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := agentcore.NewConfigBuilder("us-west-2", "arn:aws:iam::"+AccountID+":role/test", binary).
+		WithOnConflict(onConflict).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	raw, err := agentcore.EncodeConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func apply(t *testing.T, p *agentcore.Provider, deployConfig string) (string, error) {
+	t.Helper()
+	return p.Apply(context.Background(), &deploy.PlanRequest{
+		PackJSON: testPackJSON, DeployConfig: deployConfig, ArenaConfig: `{"tool_specs":{}}`,
+	}, func(*deploy.ApplyEvent) error { return nil })
+}
+
+func TestProvider_ApplyDestroy(t *testing.T) {
+	cloud := NewCloud()
+	p := NewProvider(cloud)
+	deployConfig := testDeployConfig(t, agentcore.ConflictAdopt)
+	state, err := apply(t, p, deployConfig)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	runtime, ok := cloud.Resource(agentcore.ResTypeAgentRuntime, "mypack")
+	if !ok || !strings.Contains(runtime.ARN, ":"+AccountID+":") {
+		t.Fatalf("runtime = %+v, %v", runtime, ok)
+	}
+
+	status, err := p.Status(context.Background(), &deploy.StatusRequest{DeployConfig: deployConfig, PriorState: state})
+	if err != nil || status.Status != "deployed" {
+		t.Errorf("Status = %+v, %v, want deployed", status, err)
+	}
+
+	err = p.Destroy(context.Background(), &deploy.DestroyRequest{DeployConfig: deployConfig, PriorState: state},
+		func(*deploy.DestroyEvent) error { return nil })
+	if err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if left := cloud.Resources(); len(left) != 0 {
+		t.Errorf("resources left after Destroy: %+v", left)
+	}
+}
+
+func TestProvider_Conflict(t *testing.T) {
+	cloud := NewCloud()
+	cloud.Seed("us-west-2", agentcore.ResTypeAgentRuntime, "mypack", map[string]string{"promptpack:pack-id": "other"})
+	if _, err := apply(t, NewProvider(cloud), testDeployConfig(t, agentcore.ConflictAdopt)); err == nil ||
+		!strings.Contains(err.Error(), "refusing to adopt") {
+		t.Errorf("err = %v, want a refused adoption", err)
+	}
+	if _, err := apply(t, NewProvider(cloud), testDeployConfig(t, agentcore.ConflictFail)); err == nil {
+		t.Error("on_conflict fail: err = nil, want the conflict")
+	}
+}